@@ -14,8 +14,13 @@ import (
 	audienceModel "oceanengine-backend/internal/app/audience/model"
 	campaignModel "oceanengine-backend/internal/app/campaign/model"
 	creativeModel "oceanengine-backend/internal/app/creative/model"
+	dmpModel "oceanengine-backend/internal/app/dmp/model"
 	mediaModel "oceanengine-backend/internal/app/media/model"
 	reportModel "oceanengine-backend/internal/app/report/model"
+	spiModel "oceanengine-backend/internal/app/spi/model"
+	trackingModel "oceanengine-backend/internal/app/tracking/model"
+	transferModel "oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/pkg/crypto"
 	"oceanengine-backend/pkg/database"
 	"oceanengine-backend/pkg/logger"
 )
@@ -23,7 +28,7 @@ import (
 func main() {
 	// 命令行参数
 	configPath := flag.String("config", "config/settings.yml", "配置文件路径")
-	action := flag.String("action", "migrate", "执行操作: migrate, fresh, seed")
+	action := flag.String("action", "migrate", "执行操作: migrate, fresh, seed, encrypt-tokens, rotate-key")
 	flag.Parse()
 
 	// 加载配置
@@ -54,6 +59,10 @@ func main() {
 		runFresh(log, db)
 	case "seed":
 		runSeed(log, db)
+	case "encrypt-tokens":
+		runEncryptTokens(log, db, &cfg.Crypto, false)
+	case "rotate-key":
+		runEncryptTokens(log, db, &cfg.Crypto, true)
 	default:
 		fmt.Printf("未知操作: %s\n", *action)
 		os.Exit(1)
@@ -71,11 +80,15 @@ func runMigrate(log *zap.Logger, db *gorm.DB) {
 		&adminModel.Role{},
 		&adminModel.Menu{},
 		&adminModel.RoleMenu{},
+		&adminModel.UserAdvertiser{},
+		&adminModel.RoleAdvertiser{},
 		&adminModel.OperationLog{},
 		&adminModel.UserSetting{},
 		&adminModel.Notification{},
 		&adminModel.DictType{},
 		&adminModel.DictData{},
+		&adminModel.Job{},
+		&adminModel.JobRun{},
 		// 广告主模块
 		&advertiserModel.Advertiser{},
 		&advertiserModel.AdvertiserFund{},
@@ -89,15 +102,36 @@ func runMigrate(log *zap.Logger, db *gorm.DB) {
 		&reportModel.AdvertiserReport{},
 		&reportModel.CampaignReport{},
 		&reportModel.AdReport{},
+		&reportModel.CreativeReport{},
+		&reportModel.ProjectReport{},
+		&reportModel.PromotionReport{},
+		&reportModel.MaterialReport{},
 		&reportModel.ExportTask{},
 		// 素材模块
 		&mediaModel.MaterialImage{},
 		&mediaModel.MaterialVideo{},
+		&mediaModel.LibraryFolder{},
+		&mediaModel.LibraryMaterial{},
+		&mediaModel.LibraryTag{},
+		&mediaModel.LibraryBinding{},
 		// 人群定向模块
 		&audienceModel.AudiencePackage{},
 		&audienceModel.CustomAudience{},
+		&dmpModel.AudienceBuildTask{},
+		// 订阅推送模块
+		&spiModel.Message{},
+		// 转化追踪模块
+		&trackingModel.Click{},
+		&trackingModel.Conversion{},
+		&trackingModel.UserAttribution{},
+		&transferModel.Transfer{},
+		&transferModel.Approval{},
+		&transferModel.Ledger{},
 	}
 
+	dropLegacyIndexes(log, db)
+	dedupReports(log, db)
+
 	for _, model := range models {
 		if err := db.AutoMigrate(model); err != nil {
 			log.Error(fmt.Sprintf("迁移失败: %v", err))
@@ -105,9 +139,112 @@ func runMigrate(log *zap.Logger, db *gorm.DB) {
 		}
 	}
 
+	backfillMaterialStatus(log, db)
+
 	log.Info("数据库迁移完成")
 }
 
+// dropLegacyIndexes 删除已被替换的索引，AutoMigrate 不会删除模型中已移除的索引
+func dropLegacyIndexes(log *zap.Logger, db *gorm.DB) {
+	indexes := []struct {
+		model interface{}
+		name  string
+	}{
+		// 巨量素材ID改为按广告主唯一（素材推送后同主体广告主共享素材ID）
+		{&mediaModel.MaterialImage{}, "idx_ad_material_image_image_id"},
+		{&mediaModel.MaterialVideo{}, "idx_ad_material_video_video_id"},
+	}
+	for _, idx := range indexes {
+		if !db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+			log.Error(fmt.Sprintf("删除索引 %s 失败: %v", idx.name, err))
+			os.Exit(1)
+		}
+		log.Info(fmt.Sprintf("删除索引 %s 成功", idx.name))
+	}
+}
+
+// dedupReports 删除报表表中同一维度同一日期的重复记录，每组保留最新写入的一条
+// 历史版本的报表同步直接插入，重复同步会产生重复行，需在 AutoMigrate 创建唯一索引前清理
+func dedupReports(log *zap.Logger, db *gorm.DB) {
+	tables := []struct {
+		model   interface{}
+		index   string
+		columns string
+	}{
+		{&reportModel.AdvertiserReport{}, "uk_rpt_advertiser_date", "advertiser_id, stat_date"},
+		{&reportModel.CampaignReport{}, "uk_rpt_campaign_date", "campaign_id, stat_date"},
+		{&reportModel.AdReport{}, "uk_rpt_ad_date", "ad_id, stat_date"},
+	}
+	for _, t := range tables {
+		if !db.Migrator().HasTable(t.model) || db.Migrator().HasIndex(t.model, t.index) {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(t.model); err != nil {
+			log.Error(fmt.Sprintf("解析报表模型失败: %v", err))
+			os.Exit(1)
+		}
+		table := stmt.Schema.Table
+		// 子查询包一层派生表，MySQL 不允许在 DELETE 的子查询中直接引用目标表
+		result := db.Exec(fmt.Sprintf(
+			"DELETE FROM %s WHERE id NOT IN (SELECT id FROM (SELECT MAX(id) AS id FROM %s GROUP BY %s) AS latest)",
+			table, table, t.columns))
+		if result.Error != nil {
+			log.Error(fmt.Sprintf("清理报表 %s 重复记录失败: %v", table, result.Error))
+			os.Exit(1)
+		}
+		if result.RowsAffected > 0 {
+			log.Info(fmt.Sprintf("清理报表 %s 重复记录 %d 条", table, result.RowsAffected))
+		}
+	}
+}
+
+// backfillMaterialStatus 为新增同步状态前的素材记录补充状态
+// 历史版本在推送巨量失败时生成 img_/vid_ 开头的本地ID，这些记录标记为 FAILED，需重新上传；其余记录已有巨量素材ID，标记为 SYNCED
+func backfillMaterialStatus(log *zap.Logger, db *gorm.DB) {
+	legacy := "status = ? AND (storage_key IS NULL OR storage_key = '')"
+	tables := []struct {
+		model  interface{}
+		column string
+		prefix string
+	}{
+		{&mediaModel.MaterialImage{}, "image_id", "img_"},
+		{&mediaModel.MaterialVideo{}, "video_id", "vid_"},
+	}
+	for _, t := range tables {
+		// 本地生成的ID为前缀加 8 位 UUID
+		fabricated := db.Model(t.model).
+			Where(legacy, mediaModel.MaterialStatusPending).
+			Where(fmt.Sprintf("%s LIKE ? AND LENGTH(%s) = ?", t.column, t.column), t.prefix+"%", len(t.prefix)+8).
+			Updates(map[string]interface{}{
+				"status":    mediaModel.MaterialStatusFailed,
+				"error_msg": "素材未上传到巨量引擎（历史记录的素材ID为本地生成），请重新上传",
+			})
+		if fabricated.Error != nil {
+			log.Error(fmt.Sprintf("更新素材同步状态失败: %v", fabricated.Error))
+			os.Exit(1)
+		}
+
+		synced := db.Model(t.model).
+			Where(legacy, mediaModel.MaterialStatusPending).
+			Where(fmt.Sprintf("%s IS NOT NULL AND %s <> ''", t.column, t.column)).
+			Updates(map[string]interface{}{
+				"status":    mediaModel.MaterialStatusSynced,
+				"synced_at": gorm.Expr("created_at"),
+			})
+		if synced.Error != nil {
+			log.Error(fmt.Sprintf("更新素材同步状态失败: %v", synced.Error))
+			os.Exit(1)
+		}
+		if fabricated.RowsAffected+synced.RowsAffected > 0 {
+			log.Info(fmt.Sprintf("素材同步状态补充完成: %s 待重新上传 %d 条，已同步 %d 条", t.column, fabricated.RowsAffected, synced.RowsAffected))
+		}
+	}
+}
+
 // runFresh 清空并重建表
 func runFresh(log *zap.Logger, db *gorm.DB) {
 	log.Info("开始清空数据库...")
@@ -116,11 +253,17 @@ func runFresh(log *zap.Logger, db *gorm.DB) {
 	tables := []string{
 		"sys_user", "sys_role", "sys_menu", "sys_role_menu", "sys_operation_log",
 		"sys_user_setting", "sys_notification", "sys_dict_type", "sys_dict_data",
+		"sys_user_advertiser", "sys_role_advertiser", "sys_job", "sys_job_run",
 		"ad_advertiser", "ad_advertiser_fund",
 		"ad_campaign", "ad_ad", "ad_creative",
-		"rpt_advertiser_daily", "rpt_campaign_daily", "rpt_ad_daily",
-		"ad_material_image", "ad_material_video",
+		"rpt_advertiser", "rpt_campaign", "rpt_ad", "rpt_creative",
+		"rpt_project", "rpt_promotion", "rpt_material",
+		"ad_material_image", "ad_material_video", "ad_material_folder",
+		"ad_material_library", "ad_material_library_tag", "ad_material_library_binding",
 		"ad_audience_package", "ad_custom_audience",
+		"spi_message",
+		"track_click", "track_conversion", "track_user_attribution",
+		"fund_transfer", "fund_transfer_approval", "fund_transfer_ledger",
 	}
 
 	// 禁用外键检查
@@ -192,3 +335,94 @@ func runSeed(log *zap.Logger, db *gorm.DB) {
 
 	log.Info("初始数据填充完成")
 }
+
+// tokenBatchSize 每批处理的广告主数量
+const tokenBatchSize = 200
+
+// tokenRow 广告主 Token 原始存储值（不经过 encrypted 序列化器）
+type tokenRow struct {
+	ID           uint64
+	AccessToken  string
+	RefreshToken string
+}
+
+// runEncryptTokens 加密广告主 Token
+// rotate=false 时仅加密历史明文；rotate=true 时将明文及旧版本密文统一用当前密钥重新加密，
+// 轮换前需在 crypto.keys 中同时保留新旧密钥
+func runEncryptTokens(log *zap.Logger, db *gorm.DB, cfg *config.CryptoConfig, rotate bool) {
+	keyring, err := crypto.NewKeyringFromConfig(cfg)
+	if err != nil {
+		log.Error(fmt.Sprintf("初始化加密密钥失败: %v", err))
+		os.Exit(1)
+	}
+
+	log.Info(fmt.Sprintf("开始加密广告主 Token，当前密钥版本: %s，轮换: %v", keyring.ActiveVersion(), rotate))
+
+	var updated, failed int
+	var rows []tokenRow
+	result := db.Table("ad_advertiser").
+		Select("id, access_token, refresh_token").
+		Where("access_token != '' OR refresh_token != ''").
+		FindInBatches(&rows, tokenBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				changes := make(map[string]interface{}, 2)
+				for column, value := range map[string]string{
+					"access_token":  row.AccessToken,
+					"refresh_token": row.RefreshToken,
+				} {
+					encrypted, changed, err := reencryptToken(keyring, value, rotate)
+					if err != nil {
+						log.Warn(fmt.Sprintf("广告主记录 %d 字段 %s 加密失败: %v", row.ID, column, err))
+						failed++
+						continue
+					}
+					if changed {
+						changes[column] = encrypted
+					}
+				}
+				if len(changes) == 0 {
+					continue
+				}
+				if err := db.Table("ad_advertiser").Where("id = ?", row.ID).UpdateColumns(changes).Error; err != nil {
+					log.Warn(fmt.Sprintf("广告主记录 %d 更新失败: %v", row.ID, err))
+					failed++
+					continue
+				}
+				updated++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		log.Error(fmt.Sprintf("加密广告主 Token 失败: %v", result.Error))
+		os.Exit(1)
+	}
+
+	log.Info(fmt.Sprintf("广告主 Token 加密完成，更新: %d, 失败: %d", updated, failed))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// reencryptToken 计算字段的新存储值，返回是否需要更新
+func reencryptToken(keyring *crypto.Keyring, value string, rotate bool) (string, bool, error) {
+	if value == "" {
+		return "", false, nil
+	}
+	if rotate {
+		if !keyring.NeedsRotation(value) {
+			return "", false, nil
+		}
+	} else if crypto.IsEncrypted(value) {
+		return "", false, nil
+	}
+
+	plain, err := keyring.Decrypt(value)
+	if err != nil {
+		return "", false, err
+	}
+	encrypted, err := keyring.Encrypt(plain)
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted, true, nil
+}
@@ -13,10 +13,14 @@ import (
 	"time"
 
 	"oceanengine-backend/config"
+	reportService "oceanengine-backend/internal/app/report/service"
+	"oceanengine-backend/internal/fanout"
 	"oceanengine-backend/internal/router"
 	"oceanengine-backend/pkg/auth"
+	"oceanengine-backend/pkg/crypto"
 	"oceanengine-backend/pkg/database"
 	"oceanengine-backend/pkg/logger"
+	"oceanengine-backend/pkg/storage"
 )
 
 func main() {
@@ -44,6 +48,14 @@ func main() {
 	}
 	log.Info("数据库连接成功")
 
+	// 初始化 Token 加密密钥环，生产环境必须配置
+	if _, err := crypto.Init(&cfg.Crypto); err != nil {
+		if cfg.Server.Mode == "release" {
+			log.Fatal(fmt.Sprintf("初始化加密密钥失败: %v", err))
+		}
+		log.Warn(fmt.Sprintf("未配置加密密钥，广告主授权将无法保存: %v", err))
+	}
+
 	// 初始化 Redis (可选)
 	if cfg.Redis.Addr != "" {
 		_, err := database.InitRedis(&cfg.Redis, log)
@@ -58,7 +70,22 @@ func main() {
 	jwtManager := auth.NewJWTManager(&cfg.JWT)
 
 	// 设置路由
-	r := router.NewRouter(db, log, jwtManager, &cfg.Ocean)
+	r := router.NewRouter(db, log, jwtManager, &cfg.Ocean).SetFanout(fanout.New(&cfg.Fanout)).SetSPI(&cfg.SPI).SetTracking(&cfg.Tracking).SetTransfer(&cfg.Transfer)
+
+	// 报表导出文件存储（与定时任务服务共享）
+	if files, err := reportService.NewExportFiles(&cfg.Storage, &cfg.Export); err != nil {
+		log.Warn(fmt.Sprintf("初始化导出文件存储失败，导出文件将无法下载: %v", err))
+	} else {
+		r.SetExportFiles(files)
+	}
+
+	// 素材原始文件存储（与定时任务服务共享，推送失败的素材由定时任务重试）
+	if st, err := storage.New(&cfg.Storage); err != nil {
+		log.Warn(fmt.Sprintf("初始化素材存储失败，素材将无法上传: %v", err))
+	} else {
+		r.SetMaterials(st, &cfg.Material)
+	}
+
 	engine := r.Setup(cfg.Server.Mode)
 
 	// 创建 HTTP 服务器
@@ -92,5 +119,6 @@ func main() {
 		log.Fatal(fmt.Sprintf("服务器关闭失败: %v", err))
 	}
 
+	r.Close()
 	log.Info("服务器已关闭")
 }
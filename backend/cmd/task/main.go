@@ -12,24 +12,54 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	advService "oceanengine-backend/internal/app/advertiser/service"
+	dmpService "oceanengine-backend/internal/app/dmp/service"
+	mediaService "oceanengine-backend/internal/app/media/service"
+	"oceanengine-backend/internal/app/report/model"
+	reportService "oceanengine-backend/internal/app/report/service"
+	spiService "oceanengine-backend/internal/app/spi/service"
+	trackingService "oceanengine-backend/internal/app/tracking/service"
+	transferService "oceanengine-backend/internal/app/transfer/service"
+	"oceanengine-backend/internal/fanout"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/scheduler"
+	"oceanengine-backend/pkg/cache"
+	"oceanengine-backend/pkg/crypto"
 	"oceanengine-backend/pkg/database"
 	"oceanengine-backend/pkg/logger"
 	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/oceansdk"
+	"oceanengine-backend/pkg/storage"
 )
 
+// tokenRefreshWindow Token 提前刷新窗口
+const tokenRefreshWindow = 1 * time.Hour
+
 // TaskRunner 任务运行器
 type TaskRunner struct {
-	cfg    *config.Config
-	log    *zap.Logger
-	db     *gorm.DB
-	client *oceanengine.Client
-	ctx    context.Context
-	cancel context.CancelFunc
+	cfg       *config.Config
+	log       *zap.Logger
+	db        *gorm.DB
+	client    *oceanengine.Client
+	tokens    *advService.TokenService
+	export    *reportService.ExportWorker
+	fanout    *fanout.Executor
+	spi       *spiService.Dispatcher
+	media     *mediaService.MediaService
+	builds    *dmpService.BuildService
+	postback  *trackingService.Postback
+	conv      *trackingService.ConversionService
+	transfers *transferService.Executor
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 func main() {
 	// 命令行参数
 	configPath := flag.String("config", "config/settings.yml", "配置文件路径")
+	backfillStart := flag.String("backfill-start", "", "回补报表开始日期 (YYYY-MM-DD)，指定后执行一次回补并退出")
+	backfillEnd := flag.String("backfill-end", "", "回补报表结束日期 (YYYY-MM-DD)，默认与开始日期相同")
+	backfillLevels := flag.String("backfill-levels", "", "回补报表维度，逗号分隔，默认全部维度")
 	flag.Parse()
 
 	// 加载配置
@@ -55,8 +85,24 @@ func main() {
 		log.Fatal(fmt.Sprintf("初始化数据库失败: %v", err))
 	}
 
+	// 初始化 Token 加密密钥环
+	if _, err := crypto.Init(&cfg.Crypto); err != nil {
+		log.Fatal(fmt.Sprintf("初始化加密密钥失败: %v", err))
+	}
+
+	// 初始化 Redis (可选，用于 Token 刷新及定时任务的分布式锁)
+	var c cache.Cache
+	if cfg.Redis.Addr != "" {
+		rdb, err := database.InitRedis(&cfg.Redis, log)
+		if err != nil {
+			log.Warn(fmt.Sprintf("初始化 Redis 失败: %v", err))
+		} else {
+			c = cache.NewRedisCache(rdb, "oceanengine")
+		}
+	}
+
 	// 初始化 Ocean Engine 客户端
-	client := oceanengine.NewClient(cfg.Ocean.AppID, cfg.Ocean.Secret)
+	client := oceanclient.New(&cfg.Ocean)
 
 	// 创建任务运行器
 	ctx, cancel := context.WithCancel(context.Background())
@@ -65,12 +111,68 @@ func main() {
 		log:    log,
 		db:     db,
 		client: client,
+		tokens: advService.NewTokenService(db, &cfg.Ocean, c),
+		fanout: fanout.New(&cfg.Fanout),
 		ctx:    ctx,
 		cancel: cancel,
 	}
 
+	// 订阅推送消息重试
+	runner.spi = spiService.NewDispatcher(db, &cfg.SPI, log)
+	spiService.RegisterDefaultHandlers(runner.spi, db, reportService.NewReportSyncer(db, client), runner.tokens, log)
+
+	// 报表导出 worker（需与 API 服务共享存储目录与签名密钥）
+	if files, err := reportService.NewExportFiles(&cfg.Storage, &cfg.Export); err != nil {
+		log.Warn(fmt.Sprintf("初始化导出文件存储失败，报表导出任务不会执行: %v", err))
+	} else {
+		runner.export = reportService.NewExportWorker(db, client, runner.tokens, files, &cfg.Export, log)
+	}
+
+	// 素材同步与人群包构建（需与 API 服务共享存储目录）
+	if st, err := storage.New(&cfg.Storage); err != nil {
+		log.Warn(fmt.Sprintf("初始化素材存储失败，素材同步与人群包构建任务不会执行: %v", err))
+	} else {
+		runner.media = mediaService.NewMediaService(db, &cfg.Ocean, st, runner.tokens, &cfg.Material)
+		dmpSDK := oceanclient.NewSDK(&cfg.Ocean, oceansdk.WithTokenResolver(runner.tokens), oceansdk.WithLogger(log.Named("oceanengine")))
+		runner.builds = dmpService.NewBuildService(db, st, dmpSDK, log)
+	}
+
+	// 未归因转化重新归因与转化回传重试
+	runner.conv = trackingService.NewConversionService(db, &cfg.Tracking)
+	sdk := oceanclient.NewSDK(&cfg.Ocean, oceansdk.WithLogger(log.Named("oceanengine")))
+	if runner.postback, err = trackingService.NewPostback(db, &cfg.Tracking, sdk, log); err != nil {
+		log.Warn(fmt.Sprintf("初始化转化回传失败，转化回传重试任务不会执行: %v", err))
+	}
+
+	// 转账执行与对账
+	transferSDK := transferService.NewExecutorSDK(&cfg.Ocean, runner.tokens, log)
+	runner.transfers = transferService.NewExecutor(db, &cfg.Transfer, transferSDK, log)
+
+	// 回补报表
+	if *backfillStart != "" {
+		code := runner.backfill(*backfillStart, *backfillEnd, *backfillLevels)
+		log.Sync()
+		os.Exit(code)
+	}
+
 	// 启动定时任务
-	go runner.startScheduler()
+	sched := scheduler.New(db, c, log, &cfg.Scheduler)
+	if err := runner.registerJobs(sched); err != nil {
+		log.Fatal(fmt.Sprintf("注册定时任务失败: %v", err))
+	}
+	if err := sched.Start(ctx); err != nil {
+		log.Fatal(fmt.Sprintf("启动定时任务失败: %v", err))
+	}
+
+	// 处理报表导出任务
+	var exportDone chan struct{}
+	if runner.export != nil {
+		exportDone = make(chan struct{})
+		go func() {
+			defer close(exportDone)
+			runner.runExportWorker()
+		}()
+	}
 
 	// 优雅关闭
 	quit := make(chan os.Signal, 1)
@@ -79,273 +181,421 @@ func main() {
 
 	log.Info("正在关闭定时任务服务...")
 	cancel()
-	time.Sleep(2 * time.Second)
+	sched.Wait()
+	if exportDone != nil {
+		<-exportDone
+	}
 	log.Info("定时任务服务已关闭")
 }
 
-// startScheduler 启动调度器
-func (r *TaskRunner) startScheduler() {
-	// 每小时同步广告主余额
-	go r.runPeriodically("广告主余额同步", 1*time.Hour, r.syncAdvertiserBalance)
-
-	// 每天凌晨2点同步昨日报表
-	go r.runDailyAt("日报表同步", 2, 0, r.syncDailyReport)
-
-	// 每5分钟检查并刷新即将过期的 Token
-	go r.runPeriodically("Token刷新检查", 5*time.Minute, r.refreshExpiredTokens)
-
-	// 每天清理30天前的操作日志
-	go r.runDailyAt("操作日志清理", 3, 0, r.cleanOperationLogs)
-}
-
-// runPeriodically 周期性运行任务
-func (r *TaskRunner) runPeriodically(name string, interval time.Duration, task func() error) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	r.log.Info(fmt.Sprintf("[%s] 任务已启动，间隔: %v", name, interval))
-
-	// 立即执行一次
-	if err := task(); err != nil {
-		r.log.Error(fmt.Sprintf("[%s] 执行失败: %v", name, err))
+// registerJobs 注册定时任务，调度表达式可在配置 scheduler.jobs 中覆盖
+func (r *TaskRunner) registerJobs(s *scheduler.Scheduler) error {
+	jobs := []scheduler.Job{
+		{
+			Name:        "advertiser_balance",
+			Description: "广告主余额同步",
+			Spec:        "0 * * * *",
+			Timeout:     30 * time.Minute,
+			CatchUp:     true,
+			Run:         r.syncAdvertiserBalance,
+		},
+		{
+			Name:        "daily_report",
+			Description: "日报表同步",
+			Spec:        "0 2 * * *",
+			Timeout:     2 * time.Hour,
+			CatchUp:     true,
+			Run:         r.syncDailyReport,
+		},
+		{
+			// 刷新窗口远大于执行间隔，错过的执行无需补跑
+			Name:        "token_refresh",
+			Description: "Token刷新检查",
+			Spec:        "@every 5m",
+			Timeout:     5 * time.Minute,
+			Run:         r.refreshExpiredTokens,
+		},
+		{
+			Name:        "operation_log_cleanup",
+			Description: "操作日志清理",
+			Spec:        "0 3 * * *",
+			Timeout:     30 * time.Minute,
+			CatchUp:     true,
+			Run:         r.cleanOperationLogs,
+		},
+		{
+			Name:        "spi_message_retry",
+			Description: "订阅推送消息重试",
+			Spec:        "@every 5m",
+			Timeout:     10 * time.Minute,
+			Run:         r.retrySPIMessages,
+		},
 	}
-
-	for {
-		select {
-		case <-r.ctx.Done():
-			r.log.Info(fmt.Sprintf("[%s] 任务已停止", name))
-			return
-		case <-ticker.C:
-			if err := task(); err != nil {
-				r.log.Error(fmt.Sprintf("[%s] 执行失败: %v", name, err))
-			} else {
-				r.log.Info(fmt.Sprintf("[%s] 执行成功", name))
-			}
+	if r.media != nil {
+		jobs = append(jobs, scheduler.Job{
+			Name:        "material_sync",
+			Description: "素材同步到巨量",
+			Spec:        "@every 5m",
+			Timeout:     30 * time.Minute,
+			Run:         r.syncMaterials,
+		})
+	}
+	if r.builds != nil {
+		jobs = append(jobs, scheduler.Job{
+			Name:        "dmp_audience_build",
+			Description: "人群包构建",
+			Spec:        "@every 1m",
+			Timeout:     audienceBuildTimeout,
+			Run:         r.buildAudiences,
+		})
+	}
+	if r.postback != nil {
+		jobs = append(jobs, scheduler.Job{
+			Name:        "conversion_retry",
+			Description: "未归因转化重新归因与转化回传重试",
+			Spec:        "@every 5m",
+			Timeout:     10 * time.Minute,
+			Run:         r.retryConversions,
+		})
+	}
+	if r.transfers != nil {
+		jobs = append(jobs, scheduler.Job{
+			Name:        "transfer_reconcile",
+			Description: "转账执行与对账",
+			Spec:        "@every 2m",
+			Timeout:     10 * time.Minute,
+			Run:         r.reconcileTransfers,
+		})
+	}
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
-// runDailyAt 每天指定时间运行任务
-func (r *TaskRunner) runDailyAt(name string, hour, minute int, task func() error) {
-	r.log.Info(fmt.Sprintf("[%s] 任务已启动，每天 %02d:%02d 执行", name, hour, minute))
+// advertiserRow 待同步的广告主
+// Token 加密存储，仅查询 ID，由 TokenService 解密并按需刷新
+type advertiserRow struct {
+	ID           uint64
+	AdvertiserID uint64
+}
 
-	for {
-		now := time.Now()
-		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
-		if next.Before(now) {
-			next = next.Add(24 * time.Hour)
-		}
+// advertiserIDs 返回巨量广告主 ID 列表
+func advertiserIDs(rows []advertiserRow) []uint64 {
+	ids := make([]uint64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.AdvertiserID
+	}
+	return ids
+}
 
-		timer := time.NewTimer(next.Sub(now))
-		select {
-		case <-r.ctx.Done():
-			timer.Stop()
-			r.log.Info(fmt.Sprintf("[%s] 任务已停止", name))
-			return
-		case <-timer.C:
-			if err := task(); err != nil {
-				r.log.Error(fmt.Sprintf("[%s] 执行失败: %v", name, err))
-			} else {
-				r.log.Info(fmt.Sprintf("[%s] 执行成功", name))
-			}
-		}
+// summarize 输出并发执行汇总并转换为任务执行结果
+func (r *TaskRunner) summarize(name string, summary *fanout.Summary) scheduler.Result {
+	for _, f := range summary.Failures {
+		r.log.Warn(fmt.Sprintf("[%s] 广告主 %d 失败: %v", name, f.AdvertiserID, f.Err))
+	}
+	if omitted := summary.Failed - len(summary.Failures); omitted > 0 {
+		r.log.Warn(fmt.Sprintf("[%s] 另有 %d 个广告主失败未列出", name, omitted))
 	}
+	r.log.Info(fmt.Sprintf("[%s] 完成，共: %d, 成功: %d, 失败: %d, 耗时: %v",
+		name, summary.Total, summary.Success, summary.Failed, summary.Duration))
+	return scheduler.Result{Success: summary.Success, Failed: summary.Failed}
 }
 
 // syncAdvertiserBalance 同步广告主余额
-func (r *TaskRunner) syncAdvertiserBalance() error {
+func (r *TaskRunner) syncAdvertiserBalance(ctx context.Context) (scheduler.Result, error) {
 	r.log.Info("开始同步广告主余额...")
 
 	// 1. 查询所有有效Token的广告主
-	var advertisers []struct {
-		ID           uint64
-		AdvertiserID uint64
-		AccessToken  string
-	}
-	if err := r.db.Table("ad_advertiser").
-		Select("id, advertiser_id, access_token").
+	var advertisers []advertiserRow
+	if err := r.db.WithContext(ctx).Table("ad_advertiser").
+		Select("id, advertiser_id").
 		Where("access_token != '' AND deleted_at IS NULL").
 		Find(&advertisers).Error; err != nil {
-		return fmt.Errorf("查询广告主失败: %w", err)
+		return scheduler.Result{}, fmt.Errorf("查询广告主失败: %w", err)
 	}
 
 	if len(advertisers) == 0 {
 		r.log.Info("没有需要同步的广告主")
-		return nil
+		return scheduler.Result{}, nil
 	}
 
-	successCount := 0
-	failCount := 0
-
-	// 2. 逐个同步余额
+	rowIDs := make(map[uint64]uint64, len(advertisers))
 	for _, adv := range advertisers {
-		balance, err := r.client.Qianchuan().GetBalance(r.ctx, adv.AccessToken, adv.AdvertiserID)
+		rowIDs[adv.AdvertiserID] = adv.ID
+	}
+
+	// 2. 并发同步余额
+	summary := r.fanout.Run(ctx, advertiserIDs(advertisers), func(ctx context.Context, advertiserID uint64) error {
+		accessToken, err := r.tokens.GetAccessToken(ctx, advertiserID)
 		if err != nil {
-			r.log.Warn(fmt.Sprintf("获取广告主 %d 余额失败: %v", adv.AdvertiserID, err))
-			failCount++
-			continue
+			return fmt.Errorf("获取 Token 失败: %w", err)
+		}
+
+		balance, err := r.client.Qianchuan().GetBalance(ctx, accessToken, advertiserID)
+		if err != nil {
+			return fmt.Errorf("获取余额失败: %w", err)
 		}
 
 		// 3. 更新数据库
-		if err := r.db.Table("ad_advertiser").
-			Where("id = ?", adv.ID).
+		if err := r.db.WithContext(ctx).Table("ad_advertiser").
+			Where("id = ?", rowIDs[advertiserID]).
 			Updates(map[string]interface{}{
 				"balance":      float64(balance) / 100, // 分转元
 				"last_sync_at": time.Now(),
 			}).Error; err != nil {
-			r.log.Warn(fmt.Sprintf("更新广告主 %d 余额失败: %v", adv.AdvertiserID, err))
-			failCount++
-			continue
+			return fmt.Errorf("更新余额失败: %w", err)
 		}
+		return nil
+	})
 
-		successCount++
-	}
-
-	r.log.Info(fmt.Sprintf("广告主余额同步完成，成功: %d, 失败: %d", successCount, failCount))
-	return nil
+	return r.summarize("广告主余额同步", summary), nil
 }
 
 // syncDailyReport 同步日报表
-func (r *TaskRunner) syncDailyReport() error {
-	r.log.Info("开始同步日报表...")
-
-	// 1. 获取昨天的日期
+// 同步昨日全部维度报表，重复执行只会覆盖当日指标
+func (r *TaskRunner) syncDailyReport(ctx context.Context) (scheduler.Result, error) {
 	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	return r.syncReports(ctx, yesterday, yesterday, model.ReportLevels)
+}
 
-	// 2. 查询所有有效Token的广告主
-	var advertisers []struct {
-		ID           uint64
-		AdvertiserID uint64
-		AccessToken  string
-	}
-	if err := r.db.Table("ad_advertiser").
-		Select("id, advertiser_id, access_token").
+// syncReports 并发同步所有广告主指定日期范围与维度的报表
+func (r *TaskRunner) syncReports(ctx context.Context, startDate, endDate string, levels []string) (scheduler.Result, error) {
+	r.log.Info(fmt.Sprintf("开始同步报表 %s~%s %v...", startDate, endDate, levels))
+
+	// 1. 查询所有有效Token的广告主
+	var advertisers []advertiserRow
+	if err := r.db.WithContext(ctx).Table("ad_advertiser").
+		Select("id, advertiser_id").
 		Where("access_token != '' AND deleted_at IS NULL").
 		Find(&advertisers).Error; err != nil {
-		return fmt.Errorf("查询广告主失败: %w", err)
+		return scheduler.Result{}, fmt.Errorf("查询广告主失败: %w", err)
 	}
 
 	if len(advertisers) == 0 {
 		r.log.Info("没有需要同步的广告主")
-		return nil
+		return scheduler.Result{}, nil
 	}
 
-	successCount := 0
-	failCount := 0
-
-	// 3. 逐个同步日报表
+	// 报表按 ad_advertiser.id 存储
+	localIDs := make(map[uint64]uint64, len(advertisers))
 	for _, adv := range advertisers {
-		report, err := r.client.Qianchuan().GetAdvertiserReport(r.ctx, adv.AccessToken, adv.AdvertiserID, yesterday, yesterday)
+		localIDs[adv.AdvertiserID] = adv.ID
+	}
+
+	// 2. 并发同步报表
+	syncer := reportService.NewReportSyncer(r.db, r.client)
+	summary := r.fanout.Run(ctx, advertiserIDs(advertisers), func(ctx context.Context, advertiserID uint64) error {
+		accessToken, err := r.tokens.GetAccessToken(ctx, advertiserID)
 		if err != nil {
-			r.log.Warn(fmt.Sprintf("获取广告主 %d 日报表失败: %v", adv.AdvertiserID, err))
-			failCount++
-			continue
+			return fmt.Errorf("获取 Token 失败: %w", err)
 		}
 
-		// 4. 保存报表数据
-		for _, data := range report {
-			reportRecord := map[string]interface{}{
-				"advertiser_id": adv.AdvertiserID,
-				"stat_date":     yesterday,
-				"cost":          data.Cost,
-				"show_cnt":      data.ShowCnt,
-				"click_cnt":     data.ClickCnt,
-				"convert_cnt":   data.ConvertCnt,
-				"pay_order_cnt": data.PayOrderCnt,
-				"pay_order_amt": data.PayOrderAmt,
-				"created_at":    time.Now(),
-			}
-
-			// 使用upsert逻辑
-			if err := r.db.Table("ad_report_daily").Where(
-				"advertiser_id = ? AND stat_date = ?",
-				adv.AdvertiserID, yesterday,
-			).Assign(reportRecord).FirstOrCreate(&map[string]interface{}{}).Error; err != nil {
-				r.log.Warn(fmt.Sprintf("保存广告主 %d 报表失败: %v", adv.AdvertiserID, err))
-			}
+		target := reportService.SyncTarget{ID: localIDs[advertiserID], AdvertiserID: advertiserID, AccessToken: accessToken}
+		if _, err := syncer.Sync(ctx, target, startDate, endDate, levels); err != nil {
+			return fmt.Errorf("同步报表失败: %w", err)
 		}
+		return nil
+	})
+
+	return r.summarize(fmt.Sprintf("报表同步 %s~%s", startDate, endDate), summary), nil
+}
 
-		successCount++
+// backfill 回补指定日期范围的报表，返回进程退出码
+func (r *TaskRunner) backfill(startDate, endDate, levelSpec string) int {
+	if endDate == "" {
+		endDate = startDate
+	}
+	start, err1 := time.Parse("2006-01-02", startDate)
+	end, err2 := time.Parse("2006-01-02", endDate)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		r.log.Error(fmt.Sprintf("回补日期范围无效: %s~%s", startDate, endDate))
+		return 2
+	}
+	levels, err := reportService.ParseReportLevels(levelSpec)
+	if err != nil {
+		r.log.Error(fmt.Sprintf("回补参数错误: %v", err))
+		return 2
 	}
 
-	r.log.Info(fmt.Sprintf("日报表同步完成(%s)，成功: %d, 失败: %d", yesterday, successCount, failCount))
-	return nil
+	ctx, stop := signal.NotifyContext(r.ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	result, err := r.syncReports(ctx, startDate, endDate, levels)
+	if err != nil {
+		r.log.Error(fmt.Sprintf("回补报表失败: %v", err))
+		return 1
+	}
+	if result.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// spiRetryBatchSize 每次重试的推送消息数
+const spiRetryBatchSize = 100
+
+// retrySPIMessages 重新处理失败或处理中断的订阅推送消息
+func (r *TaskRunner) retrySPIMessages(ctx context.Context) (scheduler.Result, error) {
+	success, failed, err := r.spi.Retry(ctx, spiRetryBatchSize)
+	if err != nil {
+		return scheduler.Result{}, fmt.Errorf("查询待重试消息失败: %w", err)
+	}
+	if success+failed > 0 {
+		r.log.Info(fmt.Sprintf("订阅推送消息重试完成，成功: %d, 失败: %d", success, failed))
+	}
+	return scheduler.Result{Success: success, Failed: failed}, nil
+}
+
+// materialSyncBatchSize 每次推送的图片、视频素材数
+const materialSyncBatchSize = 50
+
+// syncMaterials 将待同步及推送失败的素材上传到巨量
+func (r *TaskRunner) syncMaterials(ctx context.Context) (scheduler.Result, error) {
+	success, failed, err := r.media.Sync(ctx, materialSyncBatchSize)
+	if err != nil {
+		return scheduler.Result{Success: success, Failed: failed}, fmt.Errorf("查询待同步素材失败: %w", err)
+	}
+	if success+failed > 0 {
+		r.log.Info(fmt.Sprintf("素材同步完成，成功: %d, 失败: %d", success, failed))
+	}
+	return scheduler.Result{Success: success, Failed: failed}, nil
+}
+
+// audienceBuildTimeout 人群包构建任务单次执行超时，同时作为单个构建任务的处理租约
+// 等待数据源就绪可能需要较长时间，超时的构建任务标记为失败
+const audienceBuildTimeout = 2 * time.Hour
+
+// buildAudiences 执行待处理的人群包构建任务
+func (r *TaskRunner) buildAudiences(ctx context.Context) (scheduler.Result, error) {
+	success, failed, err := r.builds.Run(ctx, audienceBuildTimeout)
+	if err != nil {
+		return scheduler.Result{Success: success, Failed: failed}, fmt.Errorf("领取人群包构建任务失败: %w", err)
+	}
+	if success+failed > 0 {
+		r.log.Info(fmt.Sprintf("人群包构建完成，成功: %d, 失败: %d", success, failed))
+	}
+	return scheduler.Result{Success: success, Failed: failed}, nil
+}
+
+// conversionRetryBatchSize 每次重试回传的转化数
+const conversionRetryBatchSize = 100
+
+// retryConversions 重新归因早于点击入库的转化，并重新回传失败或回传中断的转化
+// 重新归因的转化在下一轮回传
+func (r *TaskRunner) retryConversions(ctx context.Context) (scheduler.Result, error) {
+	attributed, err := r.conv.Reattribute(ctx, conversionRetryBatchSize)
+	if err != nil {
+		return scheduler.Result{}, fmt.Errorf("重新归因转化失败: %w", err)
+	}
+	if attributed > 0 {
+		r.log.Info(fmt.Sprintf("未归因转化重新归因: %d", attributed))
+	}
+
+	success, failed, err := r.postback.Retry(ctx, conversionRetryBatchSize)
+	if err != nil {
+		return scheduler.Result{}, fmt.Errorf("查询待回传转化失败: %w", err)
+	}
+	if success+failed > 0 {
+		r.log.Info(fmt.Sprintf("转化回传重试完成，成功: %d, 失败: %d", success, failed))
+	}
+	return scheduler.Result{Success: success, Failed: failed}, nil
+}
+
+// transferReconcileBatchSize 每次继续执行的转账数
+const transferReconcileBatchSize = 50
+
+// reconcileTransfers 继续执行中断的转账并与巨量转账单对账
+func (r *TaskRunner) reconcileTransfers(ctx context.Context) (scheduler.Result, error) {
+	success, failed, err := r.transfers.Resume(ctx, transferReconcileBatchSize)
+	if err != nil {
+		return scheduler.Result{}, fmt.Errorf("查询未完成转账失败: %w", err)
+	}
+	if success+failed > 0 {
+		r.log.Info(fmt.Sprintf("转账对账完成，成功: %d, 失败: %d", success, failed))
+	}
+	return scheduler.Result{Success: success, Failed: failed}, nil
 }
 
 // refreshExpiredTokens 刷新即将过期的 Token
-func (r *TaskRunner) refreshExpiredTokens() error {
+// 与 API 服务共用 TokenService 的刷新锁，避免同一广告主被并发刷新
+func (r *TaskRunner) refreshExpiredTokens(ctx context.Context) (scheduler.Result, error) {
 	r.log.Debug("检查即将过期的 Token...")
 
-	// 1. 查询即将过期的Token (未杨1小时内过期)
-	expireTime := time.Now().Add(1 * time.Hour)
+	// 1. 查询即将过期的Token (未来1小时内过期)
+	expireTime := time.Now().Add(tokenRefreshWindow)
 
-	var advertisers []struct {
-		ID            uint64
-		AdvertiserID  uint64
-		RefreshToken  string
-		TokenExpireAt *time.Time
-	}
-	if err := r.db.Table("ad_advertiser").
-		Select("id, advertiser_id, refresh_token, token_expire_at").
+	var advertisers []advertiserRow
+	if err := r.db.WithContext(ctx).Table("ad_advertiser").
+		Select("id, advertiser_id").
 		Where("refresh_token != '' AND token_expire_at IS NOT NULL AND token_expire_at < ? AND deleted_at IS NULL", expireTime).
 		Find(&advertisers).Error; err != nil {
-		return fmt.Errorf("查询广告主失败: %w", err)
+		return scheduler.Result{}, fmt.Errorf("查询广告主失败: %w", err)
 	}
 
 	if len(advertisers) == 0 {
-		return nil
+		return scheduler.Result{}, nil
 	}
 
 	r.log.Info(fmt.Sprintf("发现 %d 个即将过期的Token", len(advertisers)))
 
-	successCount := 0
-	failCount := 0
-
-	// 2. 逐个刷新Token
-	for _, adv := range advertisers {
-		tokenData, err := r.client.OAuth().RefreshAccessToken(r.ctx, adv.RefreshToken)
-		if err != nil {
-			r.log.Warn(fmt.Sprintf("刷新广告主 %d Token失败: %v", adv.AdvertiserID, err))
-			failCount++
-			continue
+	// 2. 并发刷新Token
+	summary := r.fanout.Run(ctx, advertiserIDs(advertisers), func(ctx context.Context, advertiserID uint64) error {
+		if _, err := r.tokens.Refresh(ctx, advertiserID, tokenRefreshWindow); err != nil {
+			return fmt.Errorf("刷新 Token 失败: %w", err)
 		}
+		return nil
+	})
 
-		// 3. 更新数据库
-		newExpireAt := time.Now().Add(time.Duration(tokenData.ExpiresIn) * time.Second)
-		if err := r.db.Table("ad_advertiser").
-			Where("id = ?", adv.ID).
-			Updates(map[string]interface{}{
-				"access_token":    tokenData.AccessToken,
-				"refresh_token":   tokenData.RefreshToken,
-				"token_expire_at": newExpireAt,
-				"updated_at":      time.Now(),
-			}).Error; err != nil {
-			r.log.Warn(fmt.Sprintf("更新广告主 %d Token失败: %v", adv.AdvertiserID, err))
-			failCount++
-			continue
-		}
+	return r.summarize("Token刷新", summary), nil
+}
 
-		successCount++
-		r.log.Info(fmt.Sprintf("刷新广告主 %d Token成功", adv.AdvertiserID))
-	}
+// runExportWorker 轮询处理报表导出任务
+// 扫描间隔较短，仅在处理了任务或出错时输出日志
+func (r *TaskRunner) runExportWorker() {
+	ticker := time.NewTicker(r.cfg.Export.PollInterval)
+	defer ticker.Stop()
+
+	r.log.Info(fmt.Sprintf("[报表导出] 任务已启动，间隔: %v", r.cfg.Export.PollInterval))
+
+	for {
+		// 每轮处理满一批时继续领取，直到没有待处理任务
+		for r.ctx.Err() == nil {
+			n, err := r.export.RunOnce(r.ctx)
+			if err != nil {
+				r.log.Error(fmt.Sprintf("[报表导出] 执行失败: %v", err))
+				break
+			}
+			if n > 0 {
+				r.log.Info(fmt.Sprintf("[报表导出] 处理 %d 个任务", n))
+			}
+			if n < r.cfg.Export.BatchSize {
+				break
+			}
+		}
 
-	if successCount > 0 || failCount > 0 {
-		r.log.Info(fmt.Sprintf("Token刷新完成，成功: %d, 失败: %d", successCount, failCount))
+		select {
+		case <-r.ctx.Done():
+			r.log.Info("[报表导出] 任务已停止")
+			return
+		case <-ticker.C:
+		}
 	}
-	return nil
 }
 
 // cleanOperationLogs 清理操作日志
-func (r *TaskRunner) cleanOperationLogs() error {
+func (r *TaskRunner) cleanOperationLogs(ctx context.Context) (scheduler.Result, error) {
 	r.log.Info("开始清理操作日志...")
 
 	// 删除30天前的操作日志
 	cutoffDate := time.Now().AddDate(0, 0, -30)
-	result := r.db.Table("sys_operation_log").Where("created_at < ?", cutoffDate).Delete(&struct{}{})
+	result := r.db.WithContext(ctx).Table("sys_operation_log").Where("created_at < ?", cutoffDate).Delete(&struct{}{})
 	if result.Error != nil {
-		return result.Error
+		return scheduler.Result{}, result.Error
 	}
 
 	r.log.Info(fmt.Sprintf("清理操作日志完成，删除 %d 条记录", result.RowsAffected))
-	return nil
+	return scheduler.Result{Success: int(result.RowsAffected)}, nil
 }
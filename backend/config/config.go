@@ -19,6 +19,15 @@ type Config struct {
 	Logger    LoggerConfig    `mapstructure:"logger"`
 	Ocean     OceanConfig     `mapstructure:"ocean"`
 	Qianchuan QianchuanConfig `mapstructure:"qianchuan"`
+	Crypto    CryptoConfig    `mapstructure:"crypto"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Export    ExportConfig    `mapstructure:"export"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+	Fanout    FanoutConfig    `mapstructure:"fanout"`
+	SPI       SPIConfig       `mapstructure:"spi"`
+	Material  MaterialConfig  `mapstructure:"material"`
+	Tracking  TrackingConfig  `mapstructure:"tracking"`
+	Transfer  TransferConfig  `mapstructure:"transfer"`
 }
 
 // ServerConfig 服务器配置
@@ -90,14 +99,29 @@ type LoggerConfig struct {
 
 // OceanConfig Ocean Engine配置 (巨量广告 - 代理商)
 type OceanConfig struct {
-	AppID        string        `mapstructure:"app_id"`
-	Secret       string        `mapstructure:"secret"`
-	RedirectURI  string        `mapstructure:"redirect_uri"`
-	BaseURL      string        `mapstructure:"base_url"`
-	AuthURL      string        `mapstructure:"auth_url"`
-	Timeout      time.Duration `mapstructure:"timeout"`
-	RetryCount   int           `mapstructure:"retry_count"`
-	MaterialAuth bool          `mapstructure:"material_auth"` // 是否启用素材授权
+	AppID        string          `mapstructure:"app_id"`
+	Secret       string          `mapstructure:"secret"`
+	RedirectURI  string          `mapstructure:"redirect_uri"`
+	BaseURL      string          `mapstructure:"base_url"`
+	AuthURL      string          `mapstructure:"auth_url"`
+	Timeout      time.Duration   `mapstructure:"timeout"`
+	RetryCount   int             `mapstructure:"retry_count"`
+	MaterialAuth bool            `mapstructure:"material_auth"` // 是否启用素材授权
+	Sandbox      bool            `mapstructure:"sandbox"`       // 是否启用沙箱模式 (X-Debug-Mode)
+	RateLimit    RateLimitConfig `mapstructure:"rate_limit"`
+}
+
+// RateLimitConfig 开放平台接口限流配置（应用级，按接口路径分别计数）
+type RateLimitConfig struct {
+	QPS       float64                      `mapstructure:"qps"` // 单接口默认 QPS，小于 0 表示不限流
+	Burst     int                          `mapstructure:"burst"`
+	Endpoints map[string]EndpointRateLimit `mapstructure:"endpoints"` // 按接口路径单独配置，如 /2/report/advertiser/get/
+}
+
+// EndpointRateLimit 单接口限流配置
+type EndpointRateLimit struct {
+	QPS   float64 `mapstructure:"qps"`
+	Burst int     `mapstructure:"burst"`
 }
 
 // QianchuanConfig 巨量千川配置
@@ -110,6 +134,91 @@ type QianchuanConfig struct {
 	Timeout      time.Duration `mapstructure:"timeout"`
 	RetryCount   int           `mapstructure:"retry_count"`
 	MaterialAuth bool          `mapstructure:"material_auth"` // 是否启用素材授权
+	Sandbox      bool          `mapstructure:"sandbox"`       // 是否启用沙箱模式 (X-Debug-Mode)
+}
+
+// CryptoConfig 敏感字段加密配置 (广告主 Token 落库加密)
+type CryptoConfig struct {
+	ActiveKey string            `mapstructure:"active_key"` // 当前用于加密的密钥版本
+	Keys      map[string]string `mapstructure:"keys"`       // 密钥版本 => base64 编码的 32 字节 AES-256 密钥
+}
+
+// StorageConfig 文件存储配置
+type StorageConfig struct {
+	Driver     string `mapstructure:"driver"`      // 存储后端，目前支持 local
+	LocalPath  string `mapstructure:"local_path"`  // 本地存储根目录
+	SignSecret string `mapstructure:"sign_secret"` // 下载链接签名密钥，必须单独配置，不与 JWT 密钥共用
+}
+
+// ExportConfig 报表导出任务配置
+type ExportConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // 扫描待处理任务的间隔
+	BatchSize      int           `mapstructure:"batch_size"`      // 每次领取的任务数
+	LeaseTimeout   time.Duration `mapstructure:"lease_timeout"`   // 任务租约，超时未完成可被重新领取
+	RemoteTimeout  time.Duration `mapstructure:"remote_timeout"`  // 等待巨量异步报表生成的超时
+	RemoteInterval time.Duration `mapstructure:"remote_interval"` // 轮询巨量异步报表状态的间隔
+	URLExpire      time.Duration `mapstructure:"url_expire"`      // 下载链接有效期
+}
+
+// SchedulerConfig 定时任务调度配置
+type SchedulerConfig struct {
+	Tick time.Duration        `mapstructure:"tick"` // 调度检查间隔（同时决定手动触发的响应延迟）
+	Jobs map[string]JobConfig `mapstructure:"jobs"` // 任务名 => 配置，未配置的任务使用默认值
+}
+
+// JobConfig 单个定时任务配置
+type JobConfig struct {
+	Cron     string        `mapstructure:"cron"`     // cron 表达式或 @every 5m
+	Timeout  time.Duration `mapstructure:"timeout"`  // 单次执行超时
+	CatchUp  *bool         `mapstructure:"catch_up"` // 是否补跑停机期间错过的执行
+	Disabled bool          `mapstructure:"disabled"` // 禁用后不注册该任务
+}
+
+// FanoutConfig 按广告主并发执行配置
+type FanoutConfig struct {
+	Workers int           `mapstructure:"workers"` // 并发数
+	Timeout time.Duration `mapstructure:"timeout"` // 单个广告主的执行超时
+}
+
+// SPIConfig 巨量引擎订阅推送 (SPI) 配置
+type SPIConfig struct {
+	Secret          string        `mapstructure:"secret"`           // 推送签名密钥，默认使用 ocean.secret
+	ReplayWindow    time.Duration `mapstructure:"replay_window"`    // 推送时间戳允许的偏差，超出视为重放
+	DispatchTimeout time.Duration `mapstructure:"dispatch_timeout"` // 单条消息的处理超时
+	MaxAttempts     int           `mapstructure:"max_attempts"`     // 处理失败的最大尝试次数
+}
+
+// MaterialConfig 素材同步配置
+type MaterialConfig struct {
+	UploadTimeout time.Duration `mapstructure:"upload_timeout"` // 单个素材推送到巨量的超时，超时仍未完成的素材可被重新领取
+	MaxAttempts   int           `mapstructure:"max_attempts"`   // 推送失败的最大尝试次数
+}
+
+// TrackingConfig 监测链接点击接收与转化回传配置
+type TrackingConfig struct {
+	AttributionWindow time.Duration     `mapstructure:"attribution_window"` // 归因窗口，转化发生前该时长内的点击参与匹配
+	APIKeys           map[string]string `mapstructure:"api_keys"`           // 转化上报接口的应用密钥 (应用名: 密钥)，未配置时不注册转化上报接口
+	Source            string            `mapstructure:"source"`             // 回传数据来源标识
+	PrivateKeyFile    string            `mapstructure:"private_key_file"`   // 回传 RS256 签名私钥 (PEM)，未配置时使用 ocean.secret 签名
+	Credential        string            `mapstructure:"credential"`         // 私钥对应的密钥对：primary 或 backup
+	BatchSize         int               `mapstructure:"batch_size"`         // 点击批量写入条数
+	FlushInterval     time.Duration     `mapstructure:"flush_interval"`     // 点击批量写入间隔
+	QueueSize         int               `mapstructure:"queue_size"`         // 点击写入队列长度，队列满时同步写入
+	DispatchTimeout   time.Duration     `mapstructure:"dispatch_timeout"`   // 单条转化的回传超时
+	MaxAttempts       int               `mapstructure:"max_attempts"`       // 回传失败的最大尝试次数
+}
+
+// TransferConfig 资金转账配置
+type TransferConfig struct {
+	ApprovalRules  []ApprovalRule `mapstructure:"approval_rules"`  // 审批链，按转账金额匹配 min_amount 不超过金额的最高一档
+	ExecuteTimeout time.Duration  `mapstructure:"execute_timeout"` // 单笔转账执行超时，超时仍未完成的转账由定时任务继续执行
+	MaxAttempts    int            `mapstructure:"max_attempts"`    // 执行与对账的最大尝试次数
+}
+
+// ApprovalRule 转账审批规则，金额达到 min_amount (元) 时依次由 roles 中的角色审批
+type ApprovalRule struct {
+	MinAmount int64    `mapstructure:"min_amount"`
+	Roles     []string `mapstructure:"roles"` // 角色标识 (sys_role.key)，为空时无需审批
 }
 
 var cfg *Config
@@ -163,6 +272,11 @@ func Load(configPath string) (*Config, error) {
 		cfg.JWT.SecretKey = sk
 	}
 
+	// 下载链接签名
+	if secret := os.Getenv("STORAGE_SIGN_SECRET"); secret != "" {
+		cfg.Storage.SignSecret = secret
+	}
+
 	// Ocean Engine
 	if appID := os.Getenv("OCEAN_APP_ID"); appID != "" {
 		cfg.Ocean.AppID = appID
@@ -170,6 +284,26 @@ func Load(configPath string) (*Config, error) {
 	if secret := os.Getenv("OCEAN_SECRET"); secret != "" {
 		cfg.Ocean.Secret = secret
 	}
+	if baseURL := os.Getenv("OCEAN_BASE_URL"); baseURL != "" {
+		cfg.Ocean.BaseURL = baseURL
+	}
+	if sandbox := os.Getenv("OCEAN_SANDBOX"); sandbox != "" {
+		cfg.Ocean.Sandbox, _ = strconv.ParseBool(sandbox)
+	}
+
+	// 加密密钥
+	if key := os.Getenv("CRYPTO_KEY"); key != "" {
+		if active := os.Getenv("CRYPTO_ACTIVE_KEY"); active != "" {
+			cfg.Crypto.ActiveKey = active
+		}
+		if cfg.Crypto.ActiveKey == "" {
+			cfg.Crypto.ActiveKey = "v1"
+		}
+		if cfg.Crypto.Keys == nil {
+			cfg.Crypto.Keys = make(map[string]string)
+		}
+		cfg.Crypto.Keys[cfg.Crypto.ActiveKey] = key
+	}
 
 	// 设置默认值
 	setDefaults(cfg)
@@ -244,6 +378,9 @@ func setDefaults(c *Config) {
 	if c.Ocean.RetryCount == 0 {
 		c.Ocean.RetryCount = 3
 	}
+	if c.Ocean.RateLimit.QPS == 0 {
+		c.Ocean.RateLimit.QPS = 10
+	}
 	// 千川默认值
 	if c.Qianchuan.BaseURL == "" {
 		c.Qianchuan.BaseURL = "https://ad.oceanengine.com/open_api"
@@ -257,4 +394,93 @@ func setDefaults(c *Config) {
 	if c.Qianchuan.RetryCount == 0 {
 		c.Qianchuan.RetryCount = 3
 	}
+	// 文件存储默认值
+	if c.Storage.Driver == "" {
+		c.Storage.Driver = "local"
+	}
+	if c.Storage.LocalPath == "" {
+		c.Storage.LocalPath = "storage"
+	}
+	// 报表导出默认值
+	if c.Export.PollInterval == 0 {
+		c.Export.PollInterval = 10 * time.Second
+	}
+	if c.Export.BatchSize == 0 {
+		c.Export.BatchSize = 5
+	}
+	if c.Export.LeaseTimeout == 0 {
+		c.Export.LeaseTimeout = 30 * time.Minute
+	}
+	if c.Export.RemoteTimeout == 0 {
+		c.Export.RemoteTimeout = 20 * time.Minute
+	}
+	if c.Export.RemoteInterval == 0 {
+		c.Export.RemoteInterval = 10 * time.Second
+	}
+	if c.Export.URLExpire == 0 {
+		c.Export.URLExpire = time.Hour
+	}
+	// 定时任务默认值
+	if c.Scheduler.Tick == 0 {
+		c.Scheduler.Tick = 10 * time.Second
+	}
+	// 并发执行默认值
+	if c.Fanout.Workers == 0 {
+		c.Fanout.Workers = 8
+	}
+	if c.Fanout.Timeout == 0 {
+		c.Fanout.Timeout = 2 * time.Minute
+	}
+	// 订阅推送默认值
+	if c.SPI.Secret == "" {
+		c.SPI.Secret = c.Ocean.Secret
+	}
+	if c.SPI.ReplayWindow == 0 {
+		c.SPI.ReplayWindow = 5 * time.Minute
+	}
+	if c.SPI.DispatchTimeout == 0 {
+		c.SPI.DispatchTimeout = 2 * time.Minute
+	}
+	if c.SPI.MaxAttempts == 0 {
+		c.SPI.MaxAttempts = 5
+	}
+	// 素材同步默认值
+	if c.Material.UploadTimeout == 0 {
+		c.Material.UploadTimeout = 10 * time.Minute
+	}
+	if c.Material.MaxAttempts == 0 {
+		c.Material.MaxAttempts = 5
+	}
+	// 转化回传默认值
+	if c.Tracking.AttributionWindow == 0 {
+		c.Tracking.AttributionWindow = 7 * 24 * time.Hour
+	}
+	if c.Tracking.Credential == "" {
+		c.Tracking.Credential = "primary"
+	}
+	if c.Tracking.BatchSize == 0 {
+		c.Tracking.BatchSize = 200
+	}
+	if c.Tracking.FlushInterval == 0 {
+		c.Tracking.FlushInterval = time.Second
+	}
+	if c.Tracking.QueueSize == 0 {
+		c.Tracking.QueueSize = 10000
+	}
+	if c.Tracking.DispatchTimeout == 0 {
+		c.Tracking.DispatchTimeout = 30 * time.Second
+	}
+	if c.Tracking.MaxAttempts == 0 {
+		c.Tracking.MaxAttempts = 5
+	}
+	// 资金转账默认值
+	if len(c.Transfer.ApprovalRules) == 0 {
+		c.Transfer.ApprovalRules = []ApprovalRule{{MinAmount: 0, Roles: []string{"admin"}}}
+	}
+	if c.Transfer.ExecuteTimeout == 0 {
+		c.Transfer.ExecuteTimeout = 2 * time.Minute
+	}
+	if c.Transfer.MaxAttempts == 0 {
+		c.Transfer.MaxAttempts = 10
+	}
 }
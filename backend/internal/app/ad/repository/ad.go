@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/internal/app/ad/dto"
 	"oceanengine-backend/internal/app/ad/model"
+	"oceanengine-backend/internal/datascope"
 )
 
 // AdRepository 广告组仓储接口
@@ -35,7 +36,7 @@ func (r *adRepository) GetList(ctx context.Context, req *dto.AdListReq) ([]*mode
 	var list []*model.Ad
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Ad{})
+	query := r.db.WithContext(ctx).Model(&model.Ad{}).Scopes(datascope.ByAdvertiser(ctx))
 
 	if req.AdvertiserID > 0 {
 		query = query.Where("advertiser_id = ?", req.AdvertiserID)
@@ -68,6 +69,9 @@ func (r *adRepository) GetByID(ctx context.Context, id uint64) (*model.Ad, error
 	if err := r.db.WithContext(ctx).First(&ad, id).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, ad.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &ad, nil
 }
 
@@ -77,13 +81,16 @@ func (r *adRepository) GetByAdID(ctx context.Context, adID uint64) (*model.Ad, e
 	if err := r.db.WithContext(ctx).Where("ad_id = ?", adID).First(&ad).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, ad.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &ad, nil
 }
 
 // GetByCampaignID 根据广告系列ID获取广告组列表
 func (r *adRepository) GetByCampaignID(ctx context.Context, campaignID uint64) ([]*model.Ad, error) {
 	var list []*model.Ad
-	if err := r.db.WithContext(ctx).Where("campaign_id = ?", campaignID).Find(&list).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(datascope.ByAdvertiser(ctx)).Where("campaign_id = ?", campaignID).Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -99,9 +106,13 @@ func (r *adRepository) Update(ctx context.Context, ad *model.Ad) error {
 	return r.db.WithContext(ctx).Save(ad).Error
 }
 
-// UpdateStatus 批量更新状态
+// UpdateStatus 批量更新状态，存在数据权限外的记录时返回 datascope.ErrOutOfScope
 func (r *adRepository) UpdateStatus(ctx context.Context, ids []uint64, status string) error {
+	if err := datascope.CheckIDs(ctx, r.db, &model.Ad{}, ids); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Model(&model.Ad{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("id IN ?", ids).
 		Update("opt_status", status).Error
 }
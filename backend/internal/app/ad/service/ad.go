@@ -11,6 +11,8 @@ import (
 	"oceanengine-backend/internal/app/ad/model"
 	"oceanengine-backend/internal/app/ad/repository"
 	advRepo "oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 )
@@ -61,6 +63,9 @@ func (s *AdService) GetList(ctx context.Context, req *dto.AdListReq) ([]*dto.AdL
 func (s *AdService) GetByID(ctx context.Context, id uint64) (*dto.AdDetailResp, error) {
 	ad, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdNotFound)
 		}
@@ -74,6 +79,9 @@ func (s *AdService) GetByID(ctx context.Context, id uint64) (*dto.AdDetailResp,
 func (s *AdService) Update(ctx context.Context, id uint64, req *dto.AdUpdateReq) error {
 	ad, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrAdNotFound)
 		}
@@ -106,6 +114,9 @@ func (s *AdService) Update(ctx context.Context, id uint64, req *dto.AdUpdateReq)
 // UpdateStatus 批量更新状态
 func (s *AdService) UpdateStatus(ctx context.Context, req *dto.AdStatusUpdateReq) error {
 	if err := s.repo.UpdateStatus(ctx, req.IDs, req.OptStatus); err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 	return nil
@@ -116,6 +127,9 @@ func (s *AdService) Create(ctx context.Context, req *dto.AdCreateReq) (map[strin
 	// 验证广告主是否存在并获取 access_token
 	adv, err := s.advRepo.GetByID(ctx, req.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 	}
 
@@ -123,7 +137,7 @@ func (s *AdService) Create(ctx context.Context, req *dto.AdCreateReq) (map[strin
 
 	// 如果有 access_token，调用 OE API 创建广告
 	if adv.AccessToken != "" {
-		client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+		client := oceanclient.New(s.oceanCfg)
 		client.SetAccessToken(adv.AccessToken)
 		adSvc := oceanengine.NewAdService(client)
 
@@ -179,6 +193,9 @@ func (s *AdService) Create(ctx context.Context, req *dto.AdCreateReq) (map[strin
 func (s *AdService) Delete(ctx context.Context, id uint64) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrAdNotFound)
 		}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oceanengine-backend/internal/app/admin/dto"
+	"oceanengine-backend/internal/app/admin/service"
+	"oceanengine-backend/internal/middleware"
+	"oceanengine-backend/pkg/response"
+)
+
+// JobAPI 定时任务 API
+type JobAPI struct {
+	jobService *service.JobService
+}
+
+// NewJobAPI 创建定时任务 API
+func NewJobAPI(jobService *service.JobService) *JobAPI {
+	return &JobAPI{jobService: jobService}
+}
+
+// GetList godoc
+// @Summary 获取定时任务列表
+// @Tags 系统管理-定时任务
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.JobResp}
+// @Router /api/v1/system/jobs [get]
+func (a *JobAPI) GetList(c *gin.Context) {
+	list, err := a.jobService.GetList(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// GetRuns godoc
+// @Summary 获取定时任务执行记录
+// @Tags 系统管理-定时任务
+// @Produce json
+// @Param job_name query string false "任务名称"
+// @Param status query string false "执行状态"
+// @Param trigger query string false "触发方式"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} response.Response{data=[]dto.JobRunResp}
+// @Router /api/v1/system/jobs/runs [get]
+func (a *JobAPI) GetRuns(c *gin.Context) {
+	var req dto.JobRunListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	list, total, err := a.jobService.GetRuns(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, list, total, req.GetPage(), req.GetPageSize())
+}
+
+// Trigger godoc
+// @Summary 手动触发定时任务
+// @Tags 系统管理-定时任务
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{data=dto.JobRunResp}
+// @Router /api/v1/system/jobs/{id}/trigger [post]
+func (a *JobAPI) Trigger(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	run, err := a.jobService.Trigger(c.Request.Context(), id, uint64(middleware.GetUserID(c)))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, run)
+}
+
+// Pause godoc
+// @Summary 暂停定时任务
+// @Tags 系统管理-定时任务
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/system/jobs/{id}/pause [put]
+func (a *JobAPI) Pause(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := a.jobService.Pause(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Resume godoc
+// @Summary 恢复定时任务
+// @Tags 系统管理-定时任务
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/system/jobs/{id}/resume [put]
+func (a *JobAPI) Resume(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := a.jobService.Resume(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -220,3 +220,58 @@ func (a *RoleAPI) UpdateRoleMenus(c *gin.Context) {
 
 	response.OK(c)
 }
+
+// GetRoleAdvertisers godoc
+// @Summary 获取角色分配的广告主
+// @Tags 系统管理-角色
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} response.Response{data=[]uint64}
+// @Router /api/v1/system/roles/{id}/advertisers [get]
+func (a *RoleAPI) GetRoleAdvertisers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	advertiserIDs, err := a.roleService.GetRoleAdvertisers(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, advertiserIDs)
+}
+
+// UpdateRoleAdvertisers godoc
+// @Summary 更新角色分配的广告主
+// @Tags 系统管理-角色
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param data body dto.AdvertiserAssignReq true "广告主ID列表"
+// @Success 200 {object} response.Response
+// @Router /api/v1/system/roles/{id}/advertisers [put]
+func (a *RoleAPI) UpdateRoleAdvertisers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req dto.AdvertiserAssignReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := a.roleService.UpdateRoleAdvertisers(c.Request.Context(), id, req.AdvertiserIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c)
+}
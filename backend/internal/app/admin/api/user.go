@@ -207,3 +207,58 @@ func (a *UserAPI) ChangePassword(c *gin.Context) {
 
 	response.OK(c)
 }
+
+// GetUserAdvertisers godoc
+// @Summary 获取用户分配的广告主
+// @Tags 系统管理-用户
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response{data=[]uint64}
+// @Router /api/v1/system/users/{id}/advertisers [get]
+func (a *UserAPI) GetUserAdvertisers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	advertiserIDs, err := a.userService.GetUserAdvertisers(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, advertiserIDs)
+}
+
+// UpdateUserAdvertisers godoc
+// @Summary 更新用户分配的广告主
+// @Tags 系统管理-用户
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param data body dto.AdvertiserAssignReq true "广告主ID列表"
+// @Success 200 {object} response.Response
+// @Router /api/v1/system/users/{id}/advertisers [put]
+func (a *UserAPI) UpdateUserAdvertisers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req dto.AdvertiserAssignReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := a.userService.UpdateUserAdvertisers(c.Request.Context(), id, req.AdvertiserIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c)
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "oceanengine-backend/pkg/utils"
+
+// JobResp 定时任务响应
+type JobResp struct {
+	ID              uint64 `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Cron            string `json:"cron"`
+	CatchUp         bool   `json:"catch_up"`
+	Paused          bool   `json:"paused"`
+	LastScheduledAt string `json:"last_scheduled_at"`
+	LastRunAt       string `json:"last_run_at"`
+	LastStatus      string `json:"last_status"`
+	NextRunAt       string `json:"next_run_at"`
+}
+
+// JobRunListReq 执行记录列表请求
+type JobRunListReq struct {
+	utils.Pagination
+	JobName string `form:"job_name"`
+	Status  string `form:"status"`
+	Trigger string `form:"trigger"`
+}
+
+// JobRunResp 执行记录响应
+type JobRunResp struct {
+	ID           uint64 `json:"id"`
+	JobName      string `json:"job_name"`
+	Trigger      string `json:"trigger"`
+	Status       string `json:"status"`
+	ScheduledAt  string `json:"scheduled_at"`
+	StartedAt    string `json:"started_at"`
+	FinishedAt   string `json:"finished_at"`
+	Duration     int64  `json:"duration"` // 毫秒
+	SuccessCount int    `json:"success_count"`
+	FailCount    int    `json:"fail_count"`
+	ErrorMsg     string `json:"error_msg"`
+	Instance     string `json:"instance"`
+	TriggeredBy  uint64 `json:"triggered_by"`
+	CreatedAt    string `json:"created_at"`
+}
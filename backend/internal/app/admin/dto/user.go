@@ -104,21 +104,23 @@ type RoleListReq struct {
 
 // RoleCreateReq 创建角色请求
 type RoleCreateReq struct {
-	Name   string `json:"name" binding:"required,max=64"`
-	Code   string `json:"code" binding:"required,max=64"`
-	Sort   int    `json:"sort"`
-	Status int8   `json:"status"`
-	Remark string `json:"remark" binding:"max=500"`
+	Name      string `json:"name" binding:"required,max=64"`
+	Code      string `json:"code" binding:"required,max=64"`
+	Sort      int    `json:"sort"`
+	Status    int8   `json:"status"`
+	DataScope int8   `json:"data_scope" binding:"omitempty,oneof=1 2 3 4 5"` // 1-全部,2-自定义,3-本部门,4-本部门及以下,5-仅本人
+	Remark    string `json:"remark" binding:"max=500"`
 }
 
 // RoleUpdateReq 更新角色请求
 type RoleUpdateReq struct {
-	ID     uint64 `json:"id"`
-	Name   string `json:"name" binding:"max=64"`
-	Code   string `json:"code" binding:"max=64"`
-	Sort   int    `json:"sort"`
-	Status int8   `json:"status"`
-	Remark string `json:"remark" binding:"max=500"`
+	ID        uint64 `json:"id"`
+	Name      string `json:"name" binding:"max=64"`
+	Code      string `json:"code" binding:"max=64"`
+	Sort      int    `json:"sort"`
+	Status    int8   `json:"status"`
+	DataScope int8   `json:"data_scope" binding:"omitempty,oneof=1 2 3 4 5"`
+	Remark    string `json:"remark" binding:"max=500"`
 }
 
 // RoleMenuUpdateReq 更新角色菜单请求
@@ -126,6 +128,11 @@ type RoleMenuUpdateReq struct {
 	MenuIDs []uint64 `json:"menu_ids"`
 }
 
+// AdvertiserAssignReq 分配广告主数据权限请求
+type AdvertiserAssignReq struct {
+	AdvertiserIDs []uint64 `json:"advertiser_ids"` // ad_advertiser.id
+}
+
 // MenuCreateReq 创建菜单请求
 type MenuCreateReq struct {
 	ParentID   uint64 `json:"parent_id"`
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// Job 定时任务表
+// 由定时任务服务启动时按注册的任务同步，Cron 以配置文件为准；Paused 由后台管理维护
+type Job struct {
+	ID              uint64     `gorm:"primaryKey" json:"id"`
+	Name            string     `gorm:"size:64;uniqueIndex" json:"name"`
+	Description     string     `gorm:"size:255" json:"description"`
+	Cron            string     `gorm:"size:64" json:"cron"`
+	CatchUp         bool       `json:"catch_up"` // 是否补跑停机期间错过的执行
+	Paused          bool       `gorm:"default:false" json:"paused"`
+	LastScheduledAt *time.Time `json:"last_scheduled_at"` // 最近一次已领取的计划执行时间
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastStatus      string     `gorm:"size:16" json:"last_status"`
+	NextRunAt       *time.Time `json:"next_run_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (Job) TableName() string {
+	return "sys_job"
+}
+
+// JobRun 定时任务执行记录表
+type JobRun struct {
+	ID           uint64     `gorm:"primaryKey" json:"id"`
+	JobName      string     `gorm:"size:64;index" json:"job_name"`
+	Trigger      string     `gorm:"size:16" json:"trigger"` // SCHEDULE, CATCHUP, MANUAL
+	Status       string     `gorm:"size:16;index" json:"status"`
+	ScheduledAt  *time.Time `json:"scheduled_at"`
+	StartedAt    *time.Time `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+	Duration     int64      `gorm:"default:0" json:"duration"` // 毫秒
+	SuccessCount int        `gorm:"default:0" json:"success_count"`
+	FailCount    int        `gorm:"default:0" json:"fail_count"`
+	ErrorMsg     string     `gorm:"type:text" json:"error_msg"`
+	Instance     string     `gorm:"size:128" json:"instance"` // 执行实例 hostname:pid
+	TriggeredBy  uint64     `gorm:"default:0" json:"triggered_by"`
+	CreatedAt    time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (JobRun) TableName() string {
+	return "sys_job_run"
+}
+
+// 任务触发方式
+const (
+	JobTriggerSchedule = "SCHEDULE"
+	JobTriggerCatchUp  = "CATCHUP"
+	JobTriggerManual   = "MANUAL"
+)
+
+// 任务执行状态
+const (
+	JobRunPending = "PENDING" // 手动触发，等待定时任务服务领取
+	JobRunRunning = "RUNNING"
+	JobRunSuccess = "SUCCESS"
+	JobRunFailed  = "FAILED"
+	JobRunSkipped = "SKIPPED" // 其他实例正在执行
+)
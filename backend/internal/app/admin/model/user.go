@@ -101,6 +101,30 @@ func (RoleMenu) TableName() string {
 	return "sys_role_menu"
 }
 
+// UserAdvertiser 用户广告主分配表（数据权限）
+type UserAdvertiser struct {
+	ID           uint64 `gorm:"primaryKey"`
+	UserID       uint64 `gorm:"uniqueIndex:uk_user_advertiser;not null"`
+	AdvertiserID uint64 `gorm:"uniqueIndex:uk_user_advertiser;index;not null"` // ad_advertiser.id
+}
+
+// TableName 表名
+func (UserAdvertiser) TableName() string {
+	return "sys_user_advertiser"
+}
+
+// RoleAdvertiser 角色广告主关联表（自定义数据权限）
+type RoleAdvertiser struct {
+	ID           uint64 `gorm:"primaryKey"`
+	RoleID       uint64 `gorm:"uniqueIndex:uk_role_advertiser;not null"`
+	AdvertiserID uint64 `gorm:"uniqueIndex:uk_role_advertiser;index;not null"` // ad_advertiser.id
+}
+
+// TableName 表名
+func (RoleAdvertiser) TableName() string {
+	return "sys_role_advertiser"
+}
+
 // OperationLog 操作日志表
 type OperationLog struct {
 	ID        uint64    `gorm:"primaryKey" json:"id"`
@@ -133,6 +157,15 @@ const (
 	UserStatusLocked   = 2
 )
 
+// 数据权限常量 (Role.DataScope)
+const (
+	DataScopeAll          = 1 // 全部
+	DataScopeCustom       = 2 // 自定义（角色分配的广告主）
+	DataScopeDept         = 3 // 本部门
+	DataScopeDeptAndChild = 4 // 本部门及以下
+	DataScopeSelf         = 5 // 仅本人
+)
+
 // 菜单类型常量
 const (
 	MenuTypeDir    = 1 // 目录
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"oceanengine-backend/internal/app/admin/dto"
+	"oceanengine-backend/internal/app/admin/model"
+	"oceanengine-backend/pkg/errcode"
+)
+
+// JobService 定时任务管理服务
+// 任务由定时任务服务 (cmd/task) 执行，此处仅读写 sys_job / sys_job_run
+type JobService struct {
+	db *gorm.DB
+}
+
+// NewJobService 创建定时任务管理服务
+func NewJobService(db *gorm.DB) *JobService {
+	return &JobService{db: db}
+}
+
+// GetList 获取定时任务列表
+func (s *JobService) GetList(ctx context.Context) ([]*dto.JobResp, error) {
+	var jobs []*model.Job
+	if err := s.db.WithContext(ctx).Order("id ASC").Find(&jobs).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	result := make([]*dto.JobResp, len(jobs))
+	for i, j := range jobs {
+		result[i] = &dto.JobResp{
+			ID:              j.ID,
+			Name:            j.Name,
+			Description:     j.Description,
+			Cron:            j.Cron,
+			CatchUp:         j.CatchUp,
+			Paused:          j.Paused,
+			LastScheduledAt: formatTimePtr(j.LastScheduledAt),
+			LastRunAt:       formatTimePtr(j.LastRunAt),
+			LastStatus:      j.LastStatus,
+			NextRunAt:       formatTimePtr(j.NextRunAt),
+		}
+	}
+	return result, nil
+}
+
+// GetRuns 获取执行记录
+func (s *JobService) GetRuns(ctx context.Context, req *dto.JobRunListReq) ([]*dto.JobRunResp, int64, error) {
+	var runs []*model.JobRun
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&model.JobRun{})
+	if req.JobName != "" {
+		query = query.Where("job_name = ?", req.JobName)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Trigger != "" {
+		query = query.Where("`trigger` = ?", req.Trigger)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	offset := (req.GetPage() - 1) * req.GetPageSize()
+	if err := query.Order("id DESC").Offset(offset).Limit(req.GetPageSize()).Find(&runs).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	result := make([]*dto.JobRunResp, len(runs))
+	for i, r := range runs {
+		result[i] = &dto.JobRunResp{
+			ID:           r.ID,
+			JobName:      r.JobName,
+			Trigger:      r.Trigger,
+			Status:       r.Status,
+			ScheduledAt:  formatTimePtr(r.ScheduledAt),
+			StartedAt:    formatTimePtr(r.StartedAt),
+			FinishedAt:   formatTimePtr(r.FinishedAt),
+			Duration:     r.Duration,
+			SuccessCount: r.SuccessCount,
+			FailCount:    r.FailCount,
+			ErrorMsg:     r.ErrorMsg,
+			Instance:     r.Instance,
+			TriggeredBy:  r.TriggeredBy,
+			CreatedAt:    r.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return result, total, nil
+}
+
+// Trigger 手动触发任务
+// 写入等待执行的记录，由定时任务服务在下一个调度间隔领取；暂停中的任务也可手动触发
+func (s *JobService) Trigger(ctx context.Context, id uint64, operatorID uint64) (*dto.JobRunResp, error) {
+	job, err := s.getJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var active int64
+	if err := s.db.WithContext(ctx).Model(&model.JobRun{}).
+		Where("job_name = ? AND status IN ?", job.Name, []string{model.JobRunPending, model.JobRunRunning}).
+		Count(&active).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if active > 0 {
+		return nil, errcode.New(errcode.ErrJobPending)
+	}
+
+	run := &model.JobRun{
+		JobName:     job.Name,
+		Trigger:     model.JobTriggerManual,
+		Status:      model.JobRunPending,
+		TriggeredBy: operatorID,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	return &dto.JobRunResp{
+		ID:          run.ID,
+		JobName:     run.JobName,
+		Trigger:     run.Trigger,
+		Status:      run.Status,
+		TriggeredBy: run.TriggeredBy,
+		CreatedAt:   run.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// Pause 暂停任务的计划执行
+func (s *JobService) Pause(ctx context.Context, id uint64) error {
+	if _, err := s.getJob(ctx, id); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", id).
+		Update("paused", true).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// Resume 恢复任务的计划执行
+// 从当前时间重新计算计划，暂停期间错过的执行不补跑
+func (s *JobService) Resume(ctx context.Context, id uint64) error {
+	job, err := s.getJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !job.Paused {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"paused":            false,
+			"last_scheduled_at": time.Now(),
+		}).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return nil
+}
+
+func (s *JobService) getJob(ctx context.Context, id uint64) (*model.Job, error) {
+	var job model.Job
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrJobNotFound)
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &job, nil
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -86,6 +86,7 @@ func (s *RoleService) Create(ctx context.Context, req *dto.RoleCreateReq, operat
 		Key:       req.Code,
 		Status:    req.Status,
 		Sort:      req.Sort,
+		DataScope: req.DataScope,
 		Remark:    req.Remark,
 		CreatedBy: operatorID,
 		UpdatedBy: operatorID,
@@ -130,6 +131,9 @@ func (s *RoleService) Update(ctx context.Context, req *dto.RoleUpdateReq, operat
 	}
 	updates["status"] = req.Status
 	updates["sort"] = req.Sort
+	if req.DataScope != 0 {
+		updates["data_scope"] = req.DataScope
+	}
 	if req.Remark != "" {
 		updates["remark"] = req.Remark
 	}
@@ -157,6 +161,11 @@ func (s *RoleService) Delete(ctx context.Context, id uint64) error {
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
+	// 删除角色广告主关联
+	if err := s.db.WithContext(ctx).Where("role_id = ?", id).Delete(&model.RoleAdvertiser{}).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
 	// 删除角色
 	result := s.db.WithContext(ctx).Delete(&model.Role{}, id)
 	if result.Error != nil {
@@ -217,3 +226,42 @@ func (s *RoleService) UpdateRoleMenus(ctx context.Context, roleID uint64, menuID
 		return nil
 	})
 }
+
+// GetRoleAdvertisers 获取角色分配的广告主ID列表（自定义数据权限）
+func (s *RoleService) GetRoleAdvertisers(ctx context.Context, roleID uint64) ([]uint64, error) {
+	advertiserIDs := make([]uint64, 0)
+	if err := s.db.WithContext(ctx).Model(&model.RoleAdvertiser{}).
+		Where("role_id = ?", roleID).
+		Pluck("advertiser_id", &advertiserIDs).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return advertiserIDs, nil
+}
+
+// UpdateRoleAdvertisers 更新角色分配的广告主
+func (s *RoleService) UpdateRoleAdvertisers(ctx context.Context, roleID uint64, advertiserIDs []uint64) error {
+	if _, err := s.GetByID(ctx, roleID); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RoleAdvertiser{}).Error; err != nil {
+			return errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+
+		if len(advertiserIDs) > 0 {
+			assigns := make([]*model.RoleAdvertiser, len(advertiserIDs))
+			for i, advertiserID := range advertiserIDs {
+				assigns[i] = &model.RoleAdvertiser{
+					RoleID:       roleID,
+					AdvertiserID: advertiserID,
+				}
+			}
+			if err := tx.Create(&assigns).Error; err != nil {
+				return errcode.Wrap(errcode.ErrInternalServer, err)
+			}
+		}
+
+		return nil
+	})
+}
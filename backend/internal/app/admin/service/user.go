@@ -180,9 +180,57 @@ func (s *UserService) Delete(ctx context.Context, id uint64) error {
 	if result.RowsAffected == 0 {
 		return errcode.New(errcode.ErrUserNotFound)
 	}
+
+	// 删除用户广告主分配
+	if err := s.db.WithContext(ctx).Where("user_id = ?", id).Delete(&model.UserAdvertiser{}).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
 	return nil
 }
 
+// GetUserAdvertisers 获取用户分配的广告主ID列表
+func (s *UserService) GetUserAdvertisers(ctx context.Context, userID uint64) ([]uint64, error) {
+	advertiserIDs := make([]uint64, 0)
+	if err := s.db.WithContext(ctx).Model(&model.UserAdvertiser{}).
+		Where("user_id = ?", userID).
+		Pluck("advertiser_id", &advertiserIDs).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return advertiserIDs, nil
+}
+
+// UpdateUserAdvertisers 更新用户分配的广告主
+func (s *UserService) UpdateUserAdvertisers(ctx context.Context, userID uint64, advertiserIDs []uint64) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if count == 0 {
+		return errcode.New(errcode.ErrUserNotFound)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserAdvertiser{}).Error; err != nil {
+			return errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+
+		if len(advertiserIDs) > 0 {
+			assigns := make([]*model.UserAdvertiser, len(advertiserIDs))
+			for i, advertiserID := range advertiserIDs {
+				assigns[i] = &model.UserAdvertiser{
+					UserID:       userID,
+					AdvertiserID: advertiserID,
+				}
+			}
+			if err := tx.Create(&assigns).Error; err != nil {
+				return errcode.Wrap(errcode.ErrInternalServer, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // ResetPassword 重置密码
 func (s *UserService) ResetPassword(ctx context.Context, req *dto.UserResetPasswordReq) error {
 	var user model.User
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	_ "oceanengine-backend/pkg/crypto" // 注册 encrypted 序列化器
 )
 
 // Advertiser 广告主表
@@ -24,8 +25,8 @@ type Advertiser struct {
 	ContactPhone  string         `gorm:"size:20" json:"contact_phone"`
 	ContactEmail  string         `gorm:"size:128" json:"contact_email"`
 	Address       string         `gorm:"size:500" json:"address"`
-	AccessToken   string         `gorm:"size:500" json:"-"`
-	RefreshToken  string         `gorm:"size:500" json:"-"`
+	AccessToken   string         `gorm:"size:500;serializer:encrypted" json:"-"` // AES-GCM 加密存储
+	RefreshToken  string         `gorm:"size:500;serializer:encrypted" json:"-"` // AES-GCM 加密存储
 	TokenExpireAt *time.Time     `json:"-"`
 	LastSyncAt    *time.Time     `json:"last_sync_at"`
 	Remark        string         `gorm:"size:500" json:"remark"`
@@ -68,7 +69,9 @@ const (
 
 // 交易类型常量
 const (
-	TransactionTypeRecharge = "recharge" // 充值
-	TransactionTypeConsume  = "consume"  // 消费
-	TransactionTypeRefund   = "refund"   // 退款
+	TransactionTypeRecharge    = "recharge"     // 充值
+	TransactionTypeConsume     = "consume"      // 消费
+	TransactionTypeRefund      = "refund"       // 退款
+	TransactionTypeTransferIn  = "transfer_in"  // 转入
+	TransactionTypeTransferOut = "transfer_out" // 转出
 )
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 	"oceanengine-backend/internal/app/advertiser/dto"
 	"oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/datascope"
 )
 
 // AdvertiserRepository 广告主仓库接口
@@ -17,6 +19,7 @@ type AdvertiserRepository interface {
 	Update(ctx context.Context, advertiser *model.Advertiser) error
 	Delete(ctx context.Context, id uint64) error
 	ExistsByAdvertiserID(ctx context.Context, advertiserID uint64) (bool, error)
+	UpdateToken(ctx context.Context, id uint64, accessToken, refreshToken string, expireAt time.Time) error
 }
 
 // advertiserRepository 广告主仓库实现
@@ -34,7 +37,7 @@ func (r *advertiserRepository) GetList(ctx context.Context, req *dto.AdvertiserL
 	var list []*model.Advertiser
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Advertiser{})
+	query := r.db.WithContext(ctx).Model(&model.Advertiser{}).Scopes(datascope.Scope(ctx, "id"))
 
 	// 关键词搜索
 	if req.Keyword != "" {
@@ -67,6 +70,9 @@ func (r *advertiserRepository) GetByID(ctx context.Context, id uint64) (*model.A
 	if err := r.db.WithContext(ctx).First(&advertiser, id).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, advertiser.ID); err != nil {
+		return nil, err
+	}
 	return &advertiser, nil
 }
 
@@ -76,6 +82,9 @@ func (r *advertiserRepository) GetByAdvertiserID(ctx context.Context, advertiser
 	if err := r.db.WithContext(ctx).Where("advertiser_id = ?", advertiserID).First(&advertiser).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, advertiser.ID); err != nil {
+		return nil, err
+	}
 	return &advertiser, nil
 }
 
@@ -101,6 +110,18 @@ func (r *advertiserRepository) ExistsByAdvertiserID(ctx context.Context, adverti
 	return count > 0, err
 }
 
+// UpdateToken 更新广告主 Token
+func (r *advertiserRepository) UpdateToken(ctx context.Context, id uint64, accessToken, refreshToken string, expireAt time.Time) error {
+	// 使用结构体更新以经过 encrypted 序列化器，map 更新会绕过加密
+	return r.db.WithContext(ctx).Model(&model.Advertiser{}).Where("id = ?", id).
+		Select("access_token", "refresh_token", "token_expire_at").
+		Updates(&model.Advertiser{
+			AccessToken:   accessToken,
+			RefreshToken:  refreshToken,
+			TokenExpireAt: &expireAt,
+		}).Error
+}
+
 // FundRepository 资金流水仓库接口
 type FundRepository interface {
 	GetList(ctx context.Context, req *dto.FundListReq) ([]*model.AdvertiserFund, int64, error)
@@ -123,7 +144,8 @@ func (r *fundRepository) GetList(ctx context.Context, req *dto.FundListReq) ([]*
 	var list []*model.AdvertiserFund
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.AdvertiserFund{}).Where("advertiser_id = ?", req.AdvertiserID)
+	query := r.db.WithContext(ctx).Model(&model.AdvertiserFund{}).Scopes(datascope.ByAdvertiser(ctx)).
+		Where("advertiser_id = ?", req.AdvertiserID)
 
 	// 日期筛选
 	if req.StartDate != "" {
@@ -10,6 +10,8 @@ import (
 	"oceanengine-backend/internal/app/advertiser/dto"
 	"oceanengine-backend/internal/app/advertiser/model"
 	"oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 )
@@ -58,6 +60,9 @@ func (s *AdvertiserService) GetList(ctx context.Context, req *dto.AdvertiserList
 func (s *AdvertiserService) GetByID(ctx context.Context, id uint64) (*dto.AdvertiserDetailResp, error) {
 	adv, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -71,6 +76,9 @@ func (s *AdvertiserService) GetByID(ctx context.Context, id uint64) (*dto.Advert
 func (s *AdvertiserService) Update(ctx context.Context, id uint64, req *dto.AdvertiserUpdateReq) error {
 	adv, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -105,6 +113,9 @@ func (s *AdvertiserService) Update(ctx context.Context, id uint64, req *dto.Adve
 func (s *AdvertiserService) Delete(ctx context.Context, id uint64) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -122,6 +133,9 @@ func (s *AdvertiserService) Delete(ctx context.Context, id uint64) error {
 func (s *AdvertiserService) Sync(ctx context.Context, id uint64) (*dto.AdvertiserSyncResp, error) {
 	adv, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -134,7 +148,7 @@ func (s *AdvertiserService) Sync(ctx context.Context, id uint64) (*dto.Advertise
 	}
 
 	// 创建 SDK 客户端
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	client.SetAccessToken(adv.AccessToken)
 
 	// 获取广告主信息
@@ -207,6 +221,9 @@ func (s *AdvertiserService) Sync(ctx context.Context, id uint64) (*dto.Advertise
 func (s *AdvertiserService) GetBalance(ctx context.Context, id uint64) (*dto.AdvertiserBalanceResp, error) {
 	adv, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -219,7 +236,7 @@ func (s *AdvertiserService) GetBalance(ctx context.Context, id uint64) (*dto.Adv
 	}
 
 	// 创建 SDK 客户端
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	client.SetAccessToken(adv.AccessToken)
 
 	// 获取资金信息
@@ -251,6 +268,10 @@ func (s *AdvertiserService) GetBalance(ctx context.Context, id uint64) (*dto.Adv
 
 // GetFundList 获取资金流水列表
 func (s *AdvertiserService) GetFundList(ctx context.Context, req *dto.FundListReq) ([]*dto.FundListResp, int64, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, 0, err
+	}
+
 	list, total, err := s.fundRepo.GetList(ctx, req)
 	if err != nil {
 		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
@@ -279,7 +300,7 @@ func (s *AdvertiserService) GetFundList(ctx context.Context, req *dto.FundListRe
 
 // GetOAuthAuthorizeURL 获取 OAuth 授权 URL
 func (s *AdvertiserService) GetOAuthAuthorizeURL(state string) string {
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	oauthService := oceanengine.NewOAuthService(client)
 	// 使用带scope和material_auth的URL
 	return oauthService.GetAuthURLWithScope(state, s.oceanCfg.RedirectURI, nil, s.oceanCfg.MaterialAuth)
@@ -287,7 +308,7 @@ func (s *AdvertiserService) GetOAuthAuthorizeURL(state string) string {
 
 // HandleOAuthCallback 处理 OAuth 回调
 func (s *AdvertiserService) HandleOAuthCallback(ctx context.Context, authCode string) error {
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	oauthService := oceanengine.NewOAuthService(client)
 
 	// 获取 Access Token
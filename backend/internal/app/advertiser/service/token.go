@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/pkg/cache"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+const (
+	// TokenRefreshBefore Token 距离过期不足该时长时自动刷新
+	TokenRefreshBefore = 10 * time.Minute
+
+	tokenRefreshLockTTL   = 30 * time.Second
+	tokenRefreshWait      = 5 * time.Second
+	tokenRefreshPollEvery = 200 * time.Millisecond
+)
+
+// TokenService 广告主 Token 托管服务
+// 根据 advertiser_id 从 ad_advertiser 读取 Ocean Engine Access Token，临近过期时自动刷新，
+// 前端不再持有任何 Ocean Engine 凭证
+type TokenService struct {
+	repo          repository.AdvertiserRepository
+	client        *oceanengine.Client
+	cache         cache.Cache
+	refreshBefore time.Duration
+
+	// refreshing 合并同一广告主的并发刷新
+	refreshing singleflight.Group
+}
+
+// NewTokenService 创建 Token 服务
+// cache 为空时仅在进程内合并并发刷新
+func NewTokenService(db *gorm.DB, oceanCfg *config.OceanConfig, c cache.Cache) *TokenService {
+	return &TokenService{
+		repo:          repository.NewAdvertiserRepository(db),
+		client:        oceanclient.New(oceanCfg),
+		cache:         c,
+		refreshBefore: TokenRefreshBefore,
+	}
+}
+
+// GetAccessToken 获取广告主当前可用的 Access Token
+func (s *TokenService) GetAccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	return s.Refresh(ctx, advertiserID, s.refreshBefore)
+}
+
+// Refresh 确保广告主 Token 在 within 时长内不会过期，必要时调用 OAuth 刷新
+func (s *TokenService) Refresh(ctx context.Context, advertiserID uint64, within time.Duration) (string, error) {
+	adv, err := s.load(ctx, advertiserID)
+	if err != nil {
+		return "", err
+	}
+	if !needsRefresh(adv, within) {
+		return adv.AccessToken, nil
+	}
+
+	// 进程内合并同一广告主的并发刷新
+	token, err, _ := s.refreshing.Do(strconv.FormatUint(advertiserID, 10), func() (any, error) {
+		return s.refresh(ctx, advertiserID, within)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
+}
+
+// refresh 获取分布式锁后刷新 Token
+func (s *TokenService) refresh(ctx context.Context, advertiserID uint64, within time.Duration) (string, error) {
+	// 分布式锁，Redis 不可用时仅在进程内合并
+	if s.cache != nil {
+		key := fmt.Sprintf("oe_token_refresh:%d", advertiserID)
+		locked, err := s.cache.Lock(ctx, key, tokenRefreshLockTTL)
+		if err == nil && !locked {
+			return s.waitForRefresh(ctx, advertiserID, within)
+		}
+		if err == nil {
+			defer s.cache.Unlock(context.Background(), key)
+		}
+	}
+
+	// 获取锁后再次检查，可能已被其他请求刷新
+	adv, err := s.load(ctx, advertiserID)
+	if err != nil {
+		return "", err
+	}
+	if !needsRefresh(adv, within) {
+		return adv.AccessToken, nil
+	}
+
+	return s.doRefresh(ctx, adv)
+}
+
+// doRefresh 调用 OAuth 刷新 Token 并落库
+func (s *TokenService) doRefresh(ctx context.Context, adv *model.Advertiser) (string, error) {
+	if adv.RefreshToken == "" {
+		if !isExpired(adv) {
+			return adv.AccessToken, nil
+		}
+		return "", errcode.New(errcode.ErrOETokenExpired)
+	}
+
+	tokenResp, err := s.client.OAuth().RefreshAccessToken(ctx, adv.RefreshToken)
+	if err != nil {
+		// 刷新失败但旧 Token 仍有效时继续使用
+		if !isExpired(adv) {
+			return adv.AccessToken, nil
+		}
+		return "", errcode.WrapWithMessage(errcode.ErrOETokenExpired, "广告主授权已过期，刷新失败", err)
+	}
+
+	expireAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	if err := s.repo.UpdateToken(ctx, adv.ID, tokenResp.AccessToken, tokenResp.RefreshToken, expireAt); err != nil {
+		return "", errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+// waitForRefresh 等待其他进程完成刷新
+func (s *TokenService) waitForRefresh(ctx context.Context, advertiserID uint64, within time.Duration) (string, error) {
+	ticker := time.NewTicker(tokenRefreshPollEvery)
+	defer ticker.Stop()
+	deadline := time.After(tokenRefreshWait)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-deadline:
+			adv, err := s.load(ctx, advertiserID)
+			if err != nil {
+				return "", err
+			}
+			if isExpired(adv) {
+				return "", errcode.New(errcode.ErrOETokenExpired)
+			}
+			return adv.AccessToken, nil
+		case <-ticker.C:
+			adv, err := s.load(ctx, advertiserID)
+			if err != nil {
+				return "", err
+			}
+			if !needsRefresh(adv, within) {
+				return adv.AccessToken, nil
+			}
+		}
+	}
+}
+
+// load 读取广告主 Token 信息
+func (s *TokenService) load(ctx context.Context, advertiserID uint64) (*model.Advertiser, error) {
+	adv, err := s.repo.GetByAdvertiserID(ctx, advertiserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
+		}
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if adv.AccessToken == "" {
+		return nil, errcode.New(errcode.ErrOETokenInvalid)
+	}
+	return adv, nil
+}
+
+// needsRefresh Token 是否将在 within 时长内过期
+func needsRefresh(adv *model.Advertiser, within time.Duration) bool {
+	return adv.TokenExpireAt != nil && time.Until(*adv.TokenExpireAt) < within
+}
+
+// isExpired Token 是否已过期
+func isExpired(adv *model.Advertiser) bool {
+	return adv.TokenExpireAt != nil && !adv.TokenExpireAt.After(time.Now())
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/pkg/crypto"
+	"oceanengine-backend/pkg/errcode"
+)
+
+func newTokenTestDB(t *testing.T) *gorm.DB {
+	k, err := crypto.NewKeyring("v1", map[string]string{
+		"v1": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))),
+	})
+	require.NoError(t, err)
+	crypto.SetDefault(k)
+	t.Cleanup(func() { crypto.SetDefault(nil) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Advertiser{}))
+	return db
+}
+
+func newRefreshServer(t *testing.T, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/open_api/oauth2/refresh_token/", r.URL.Path)
+		atomic.AddInt32(calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "OK",
+			"data": map[string]interface{}{
+				"access_token":  "new-access-token",
+				"refresh_token": "new-refresh-token",
+				"expires_in":    86400,
+			},
+		})
+	}))
+}
+
+func TestTokenService_GetAccessToken(t *testing.T) {
+	var calls int32
+	server := newRefreshServer(t, &calls)
+	defer server.Close()
+
+	db := newTokenTestDB(t)
+	valid := time.Now().Add(2 * time.Hour)
+	expiring := time.Now().Add(time.Minute)
+	require.NoError(t, db.Create(&model.Advertiser{AdvertiserID: 1, Name: "valid", AccessToken: "valid-token", RefreshToken: "r1", TokenExpireAt: &valid}).Error)
+	require.NoError(t, db.Create(&model.Advertiser{AdvertiserID: 2, Name: "expiring", AccessToken: "old-token", RefreshToken: "r2", TokenExpireAt: &expiring}).Error)
+	require.NoError(t, db.Create(&model.Advertiser{AdvertiserID: 3, Name: "unauthorized"}).Error)
+
+	svc := NewTokenService(db, &config.OceanConfig{BaseURL: server.URL + "/open_api"}, nil)
+	ctx := context.Background()
+
+	token, err := svc.GetAccessToken(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	token, err = svc.GetAccessToken(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", token)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	var adv model.Advertiser
+	require.NoError(t, db.Where("advertiser_id = ?", 2).First(&adv).Error)
+	assert.Equal(t, "new-refresh-token", adv.RefreshToken)
+	assert.True(t, adv.TokenExpireAt.After(time.Now().Add(time.Hour)))
+
+	var stored string
+	require.NoError(t, db.Table("ad_advertiser").Select("access_token").Where("advertiser_id = ?", 2).Scan(&stored).Error)
+	assert.True(t, crypto.IsEncrypted(stored), "Token 应加密存储")
+
+	_, err = svc.GetAccessToken(ctx, 3)
+	appErr, ok := err.(*errcode.AppError)
+	require.True(t, ok)
+	assert.Equal(t, errcode.ErrOETokenInvalid, appErr.Code)
+
+	_, err = svc.GetAccessToken(ctx, 4)
+	appErr, ok = err.(*errcode.AppError)
+	require.True(t, ok)
+	assert.Equal(t, errcode.ErrAdvertiserNotFound, appErr.Code)
+}
+
+func TestTokenService_ConcurrentRefresh(t *testing.T) {
+	var calls int32
+	server := newRefreshServer(t, &calls)
+	defer server.Close()
+
+	db := newTokenTestDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	expiring := time.Now().Add(time.Minute)
+	require.NoError(t, db.Create(&model.Advertiser{AdvertiserID: 1, Name: "expiring", AccessToken: "old-token", RefreshToken: "r1", TokenExpireAt: &expiring}).Error)
+
+	svc := NewTokenService(db, &config.OceanConfig{BaseURL: server.URL + "/open_api"}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := svc.GetAccessToken(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, "new-access-token", token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "并发请求只应刷新一次")
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,17 +24,13 @@ func NewAdvToolsHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *AdvToolsHand
 	return &AdvToolsHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *AdvToolsHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -68,7 +66,7 @@ func (h *AdvToolsHandler) GetRtaInfo(c *gin.Context) {
 
 	result, err := advToolsSvc.GetRtaInfo(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -92,7 +90,7 @@ func (h *AdvToolsHandler) GetAvailableRta(c *gin.Context) {
 
 	list, err := advToolsSvc.GetAvailableRta(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -117,7 +115,7 @@ func (h *AdvToolsHandler) UpdateRtaStatus(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	err := advToolsSvc.UpdateRtaStatus(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -142,7 +140,7 @@ func (h *AdvToolsHandler) SetRtaScope(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	err := advToolsSvc.SetRtaScope(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -166,7 +164,7 @@ func (h *AdvToolsHandler) GetRtaScope(c *gin.Context) {
 
 	list, err := advToolsSvc.GetRtaScope(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -193,7 +191,7 @@ func (h *AdvToolsHandler) SetAdRaise(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	status, err := advToolsSvc.SetAdRaise(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -221,7 +219,7 @@ func (h *AdvToolsHandler) GetAdRaiseEstimate(c *gin.Context) {
 
 	estimate, err := advToolsSvc.GetAdRaiseEstimate(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -254,7 +252,7 @@ func (h *AdvToolsHandler) GetAdRaiseStatus(c *gin.Context) {
 
 	result, err := advToolsSvc.GetAdRaiseStatus(c.Request.Context(), statusReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -287,7 +285,7 @@ func (h *AdvToolsHandler) GetAdRaiseResult(c *gin.Context) {
 
 	list, err := advToolsSvc.GetAdRaiseResult(c.Request.Context(), resultReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -320,7 +318,7 @@ func (h *AdvToolsHandler) GetSuggestBudget(c *gin.Context) {
 
 	list, err := advToolsSvc.GetSuggestBudget(c.Request.Context(), budgetReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -352,7 +350,7 @@ func (h *AdvToolsHandler) GetAudiencePackage(c *gin.Context) {
 
 	result, err := advToolsSvc.GetAudiencePackage(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -377,7 +375,7 @@ func (h *AdvToolsHandler) CreateAudiencePackage(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	id, err := advToolsSvc.CreateAudiencePackage(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -402,7 +400,7 @@ func (h *AdvToolsHandler) UpdateAudiencePackage(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	id, err := advToolsSvc.UpdateAudiencePackage(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -427,7 +425,7 @@ func (h *AdvToolsHandler) DeleteAudiencePackage(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	id, err := advToolsSvc.DeleteAudiencePackage(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -452,7 +450,7 @@ func (h *AdvToolsHandler) BindAudiencePackage(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	id, err := advToolsSvc.BindAudiencePackage(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -477,7 +475,7 @@ func (h *AdvToolsHandler) UnbindAudiencePackage(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	id, err := advToolsSvc.UnbindAudiencePackage(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -509,7 +507,7 @@ func (h *AdvToolsHandler) GetNativeAnchor(c *gin.Context) {
 
 	result, err := advToolsSvc.GetNativeAnchor(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -535,7 +533,7 @@ func (h *AdvToolsHandler) GetNativeAnchorDetail(c *gin.Context) {
 
 	result, err := advToolsSvc.GetNativeAnchorDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -560,7 +558,7 @@ func (h *AdvToolsHandler) CreateNativeAnchor(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	result, err := advToolsSvc.CreateNativeAnchor(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -585,7 +583,7 @@ func (h *AdvToolsHandler) UpdateNativeAnchor(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	result, err := advToolsSvc.UpdateNativeAnchor(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -610,7 +608,7 @@ func (h *AdvToolsHandler) DeleteNativeAnchor(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	err := advToolsSvc.DeleteNativeAnchor(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -638,7 +636,7 @@ func (h *AdvToolsHandler) GetDiagnosisSuggestion(c *gin.Context) {
 
 	result, err := advToolsSvc.GetDiagnosisSuggestion(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -663,7 +661,7 @@ func (h *AdvToolsHandler) AcceptDiagnosisSuggestion(c *gin.Context) {
 	advToolsSvc := oceanengine.NewAdvToolsService(h.client)
 	result, err := advToolsSvc.AcceptDiagnosisSuggestion(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -689,7 +687,7 @@ func (h *AdvToolsHandler) GetQuota(c *gin.Context) {
 
 	result, err := advToolsSvc.GetQuota(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -722,7 +720,7 @@ func (h *AdvToolsHandler) GetAdQuality(c *gin.Context) {
 
 	list, err := advToolsSvc.GetAdQuality(c.Request.Context(), qualityReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -755,7 +753,7 @@ func (h *AdvToolsHandler) GetAdStatExtraInfo(c *gin.Context) {
 
 	list, err := advToolsSvc.GetAdStatExtraInfo(c.Request.Context(), extraReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/internal/app/audience/dto"
 	"oceanengine-backend/internal/app/audience/model"
+	"oceanengine-backend/internal/datascope"
 	"oceanengine-backend/pkg/errcode"
 )
 
@@ -23,6 +24,10 @@ func NewAudienceService(db *gorm.DB) *AudienceService {
 
 // GetPackageList 获取定向包列表
 func (s *AudienceService) GetPackageList(ctx context.Context, req *dto.AudiencePackageListReq) ([]*dto.AudiencePackageListResp, int64, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, 0, err
+	}
+
 	var packages []*model.AudiencePackage
 	var total int64
 
@@ -71,11 +76,18 @@ func (s *AudienceService) GetPackageByID(ctx context.Context, id uint64) (*model
 		}
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	if err := datascope.Check(ctx, pkg.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &pkg, nil
 }
 
 // CreatePackage 创建定向包
 func (s *AudienceService) CreatePackage(ctx context.Context, req *dto.AudiencePackageCreateReq) error {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return err
+	}
+
 	audienceJSON, _ := json.Marshal(req.Audience)
 
 	pkg := &model.AudiencePackage{
@@ -97,12 +109,9 @@ func (s *AudienceService) CreatePackage(ctx context.Context, req *dto.AudiencePa
 
 // UpdatePackage 更新定向包
 func (s *AudienceService) UpdatePackage(ctx context.Context, req *dto.AudiencePackageUpdateReq) error {
-	var pkg model.AudiencePackage
-	if err := s.db.WithContext(ctx).First(&pkg, req.ID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errcode.New(errcode.ErrNotFound)
-		}
-		return errcode.Wrap(errcode.ErrInternalServer, err)
+	pkg, err := s.GetPackageByID(ctx, req.ID)
+	if err != nil {
+		return err
 	}
 
 	updates := map[string]interface{}{}
@@ -120,7 +129,7 @@ func (s *AudienceService) UpdatePackage(ctx context.Context, req *dto.AudiencePa
 		updates["audience"] = string(audienceJSON)
 	}
 
-	if err := s.db.WithContext(ctx).Model(&pkg).Updates(updates).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(pkg).Updates(updates).Error; err != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
@@ -129,6 +138,10 @@ func (s *AudienceService) UpdatePackage(ctx context.Context, req *dto.AudiencePa
 
 // DeletePackage 删除定向包
 func (s *AudienceService) DeletePackage(ctx context.Context, id uint64) error {
+	if _, err := s.GetPackageByID(ctx, id); err != nil {
+		return err
+	}
+
 	result := s.db.WithContext(ctx).Delete(&model.AudiencePackage{}, id)
 	if result.Error != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, result.Error)
@@ -141,6 +154,10 @@ func (s *AudienceService) DeletePackage(ctx context.Context, id uint64) error {
 
 // GetCustomAudienceList 获取自定义人群列表
 func (s *AudienceService) GetCustomAudienceList(ctx context.Context, req *dto.CustomAudienceListReq) ([]*dto.CustomAudienceListResp, int64, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, 0, err
+	}
+
 	var audiences []*model.CustomAudience
 	var total int64
 
@@ -193,11 +210,18 @@ func (s *AudienceService) GetCustomAudienceByID(ctx context.Context, id uint64)
 		}
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	if err := datascope.Check(ctx, audience.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &audience, nil
 }
 
 // DeleteCustomAudience 删除自定义人群
 func (s *AudienceService) DeleteCustomAudience(ctx context.Context, id uint64) error {
+	if _, err := s.GetCustomAudienceByID(ctx, id); err != nil {
+		return err
+	}
+
 	result := s.db.WithContext(ctx).Delete(&model.CustomAudience{}, id)
 	if result.Error != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, result.Error)
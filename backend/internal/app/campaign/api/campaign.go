@@ -8,6 +8,7 @@ import (
 	"oceanengine-backend/config"
 	"oceanengine-backend/internal/app/campaign/dto"
 	"oceanengine-backend/internal/app/campaign/service"
+	"oceanengine-backend/internal/fanout"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/response"
 )
@@ -18,9 +19,9 @@ type CampaignHandler struct {
 }
 
 // NewCampaignHandler 创建广告系列处理器
-func NewCampaignHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *CampaignHandler {
+func NewCampaignHandler(db *gorm.DB, oceanCfg *config.OceanConfig, executor *fanout.Executor) *CampaignHandler {
 	return &CampaignHandler{
-		service: service.NewCampaignService(db, oceanCfg),
+		service: service.NewCampaignService(db, oceanCfg, executor),
 	}
 }
 
@@ -210,3 +211,21 @@ func (h *CampaignHandler) Sync(c *gin.Context) {
 
 	response.OKWithData(c, data)
 }
+
+// SyncBatch 批量同步广告系列
+// @Summary 批量同步广告系列
+// @Tags 广告系列管理
+// @Accept json
+// @Produce json
+// @Param data body dto.CampaignBatchSyncReq true "广告主ID列表"
+// @Success 200 {object} response.Response{data=dto.CampaignBatchSyncResp}
+// @Router /api/v1/campaigns/sync [post]
+func (h *CampaignHandler) SyncBatch(c *gin.Context) {
+	var req dto.CampaignBatchSyncReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, errcode.New(errcode.ErrInvalidParams))
+		return
+	}
+
+	response.OKWithData(c, h.service.SyncBatch(c.Request.Context(), req.AdvertiserIDs))
+}
@@ -74,3 +74,25 @@ type CampaignSyncResp struct {
 	SyncCount int    `json:"sync_count"`
 	SyncAt    string `json:"sync_at"`
 }
+
+// CampaignBatchSyncReq 批量同步广告系列请求
+type CampaignBatchSyncReq struct {
+	AdvertiserIDs []uint64 `json:"advertiser_ids" binding:"required,min=1,max=200"`
+}
+
+// CampaignBatchSyncResp 批量同步广告系列响应
+type CampaignBatchSyncResp struct {
+	Total     int                   `json:"total"`
+	Success   int                   `json:"success"`
+	Failed    int                   `json:"failed"`
+	SyncCount int                   `json:"sync_count"`
+	Failures  []CampaignSyncFailure `json:"failures"`
+	SyncAt    string                `json:"sync_at"`
+}
+
+// CampaignSyncFailure 单个广告主同步失败明细
+type CampaignSyncFailure struct {
+	AdvertiserID uint64 `json:"advertiser_id"`
+	Code         int    `json:"code"`
+	Message      string `json:"message"`
+}
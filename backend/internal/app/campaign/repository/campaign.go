@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/internal/app/campaign/dto"
 	"oceanengine-backend/internal/app/campaign/model"
+	"oceanengine-backend/internal/datascope"
 )
 
 // CampaignRepository 广告系列仓储接口
@@ -36,7 +37,7 @@ func (r *campaignRepository) GetList(ctx context.Context, req *dto.CampaignListR
 	var list []*model.Campaign
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Campaign{})
+	query := r.db.WithContext(ctx).Model(&model.Campaign{}).Scopes(datascope.ByAdvertiser(ctx))
 
 	if req.AdvertiserID > 0 {
 		query = query.Where("advertiser_id = ?", req.AdvertiserID)
@@ -69,6 +70,9 @@ func (r *campaignRepository) GetByID(ctx context.Context, id uint64) (*model.Cam
 	if err := r.db.WithContext(ctx).First(&campaign, id).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, campaign.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &campaign, nil
 }
 
@@ -78,13 +82,16 @@ func (r *campaignRepository) GetByCampaignID(ctx context.Context, campaignID uin
 	if err := r.db.WithContext(ctx).Where("campaign_id = ?", campaignID).First(&campaign).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, campaign.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &campaign, nil
 }
 
 // GetByAdvertiserID 根据广告主ID获取广告系列列表
 func (r *campaignRepository) GetByAdvertiserID(ctx context.Context, advertiserID uint64) ([]*model.Campaign, error) {
 	var list []*model.Campaign
-	if err := r.db.WithContext(ctx).Where("advertiser_id = ?", advertiserID).Find(&list).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(datascope.ByAdvertiser(ctx)).Where("advertiser_id = ?", advertiserID).Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -100,9 +107,13 @@ func (r *campaignRepository) Update(ctx context.Context, campaign *model.Campaig
 	return r.db.WithContext(ctx).Save(campaign).Error
 }
 
-// UpdateStatus 批量更新状态
+// UpdateStatus 批量更新状态，存在数据权限外的记录时返回 datascope.ErrOutOfScope
 func (r *campaignRepository) UpdateStatus(ctx context.Context, ids []uint64, status string) error {
+	if err := datascope.CheckIDs(ctx, r.db, &model.Campaign{}, ids); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Model(&model.Campaign{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("id IN ?", ids).
 		Update("status", status).Error
 }
@@ -112,9 +123,12 @@ func (r *campaignRepository) Delete(ctx context.Context, id uint64) error {
 	return r.db.WithContext(ctx).Delete(&model.Campaign{}, id).Error
 }
 
-// BatchDelete 批量删除广告系列
+// BatchDelete 批量删除广告系列，存在数据权限外的记录时返回 datascope.ErrOutOfScope
 func (r *campaignRepository) BatchDelete(ctx context.Context, ids []uint64) error {
-	return r.db.WithContext(ctx).Delete(&model.Campaign{}, ids).Error
+	if err := datascope.CheckIDs(ctx, r.db, &model.Campaign{}, ids); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Scopes(datascope.ByAdvertiser(ctx)).Delete(&model.Campaign{}, ids).Error
 }
 
 // ExistsByCampaignID 检查广告系列ID是否存在
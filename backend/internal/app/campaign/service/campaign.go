@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,6 +13,9 @@ import (
 	"oceanengine-backend/internal/app/campaign/dto"
 	"oceanengine-backend/internal/app/campaign/model"
 	"oceanengine-backend/internal/app/campaign/repository"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/fanout"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 )
@@ -21,14 +25,16 @@ type CampaignService struct {
 	repo     repository.CampaignRepository
 	advRepo  advRepo.AdvertiserRepository
 	oceanCfg *config.OceanConfig
+	fanout   *fanout.Executor
 }
 
 // NewCampaignService 创建广告系列服务
-func NewCampaignService(db *gorm.DB, oceanCfg *config.OceanConfig) *CampaignService {
+func NewCampaignService(db *gorm.DB, oceanCfg *config.OceanConfig, executor *fanout.Executor) *CampaignService {
 	return &CampaignService{
 		repo:     repository.NewCampaignRepository(db),
 		advRepo:  advRepo.NewAdvertiserRepository(db),
 		oceanCfg: oceanCfg,
+		fanout:   executor,
 	}
 }
 
@@ -61,6 +67,9 @@ func (s *CampaignService) GetList(ctx context.Context, req *dto.CampaignListReq)
 func (s *CampaignService) GetByID(ctx context.Context, id uint64) (*dto.CampaignDetailResp, error) {
 	c, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrCampaignNotFound)
 		}
@@ -75,6 +84,9 @@ func (s *CampaignService) Create(ctx context.Context, req *dto.CampaignCreateReq
 	// 验证广告主
 	adv, err := s.advRepo.GetByID(ctx, req.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -82,7 +94,7 @@ func (s *CampaignService) Create(ctx context.Context, req *dto.CampaignCreateReq
 	}
 
 	// 调用巨量引擎API创建广告系列
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	client.SetAccessToken(adv.AccessToken)
 
 	campaignService := oceanengine.NewCampaignService(client)
@@ -122,6 +134,9 @@ func (s *CampaignService) Create(ctx context.Context, req *dto.CampaignCreateReq
 func (s *CampaignService) Update(ctx context.Context, id uint64, req *dto.CampaignUpdateReq) error {
 	campaign, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrCampaignNotFound)
 		}
@@ -131,11 +146,14 @@ func (s *CampaignService) Update(ctx context.Context, id uint64, req *dto.Campai
 	// 获取广告主信息
 	adv, err := s.advRepo.GetByID(ctx, campaign.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
 	// 调用巨量引擎API更新
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	client.SetAccessToken(adv.AccessToken)
 
 	campaignService := oceanengine.NewCampaignService(client)
@@ -177,6 +195,9 @@ func (s *CampaignService) Update(ctx context.Context, id uint64, req *dto.Campai
 func (s *CampaignService) UpdateStatus(ctx context.Context, req *dto.CampaignStatusUpdateReq) error {
 	// 这里可以批量调用API更新状态
 	if err := s.repo.UpdateStatus(ctx, req.IDs, req.Status); err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 	return nil
@@ -186,6 +207,9 @@ func (s *CampaignService) UpdateStatus(ctx context.Context, req *dto.CampaignSta
 func (s *CampaignService) Delete(ctx context.Context, id uint64) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrCampaignNotFound)
 		}
@@ -207,6 +231,9 @@ func (s *CampaignService) Sync(ctx context.Context, advertiserID uint64) (*dto.C
 
 	adv, err = s.advRepo.GetByID(ctx, advertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 		}
@@ -218,7 +245,7 @@ func (s *CampaignService) Sync(ctx context.Context, advertiserID uint64) (*dto.C
 	}
 
 	// 创建SDK客户端
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
+	client := oceanclient.New(s.oceanCfg)
 	client.SetAccessToken(adv.AccessToken)
 
 	// 获取广告系列列表
@@ -271,6 +298,38 @@ func (s *CampaignService) Sync(ctx context.Context, advertiserID uint64) (*dto.C
 	}, nil
 }
 
+// SyncBatch 并发同步多个广告主的广告系列
+// 单个广告主失败不影响其他广告主，数据权限沿用请求上下文逐个校验
+func (s *CampaignService) SyncBatch(ctx context.Context, advertiserIDs []uint64) *dto.CampaignBatchSyncResp {
+	var syncCount int64
+	summary := s.fanout.Run(ctx, advertiserIDs, func(ctx context.Context, advertiserID uint64) error {
+		resp, err := s.Sync(ctx, advertiserID)
+		if err != nil {
+			return err
+		}
+		atomic.AddInt64(&syncCount, int64(resp.SyncCount))
+		return nil
+	})
+
+	failures := make([]dto.CampaignSyncFailure, len(summary.Failures))
+	for i, f := range summary.Failures {
+		failures[i] = dto.CampaignSyncFailure{AdvertiserID: f.AdvertiserID, Code: errcode.GetCode(f.Err), Message: errcode.Message(errcode.ErrUnknown)}
+		var appErr *errcode.AppError
+		if errors.As(f.Err, &appErr) {
+			failures[i].Message = appErr.Message
+		}
+	}
+
+	return &dto.CampaignBatchSyncResp{
+		Total:     summary.Total,
+		Success:   summary.Success,
+		Failed:    summary.Failed,
+		SyncCount: int(syncCount),
+		Failures:  failures,
+		SyncAt:    time.Now().Format("2006-01-02 15:04:05"),
+	}
+}
+
 // toDetailResp 转换为详情响应
 func (s *CampaignService) toDetailResp(c *model.Campaign) *dto.CampaignDetailResp {
 	var startTime, endTime, lastSyncAt string
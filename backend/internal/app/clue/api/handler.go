@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,17 +24,13 @@ func NewClueHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *ClueHandler {
 	return &ClueHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *ClueHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -72,7 +70,7 @@ func (h *ClueHandler) GetClueList(c *gin.Context) {
 
 	result, err := clueService.GetClueList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -97,7 +95,7 @@ func (h *ClueHandler) ClueCallback(c *gin.Context) {
 	clueService := oceanengine.NewClueService(h.client)
 	err := clueService.ClueCallback(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -122,7 +120,7 @@ func (h *ClueHandler) BatchClueCallback(c *gin.Context) {
 	clueService := oceanengine.NewClueService(h.client)
 	result, err := clueService.BatchClueCallback(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -149,7 +147,7 @@ func (h *ClueHandler) GetKeyAction(c *gin.Context) {
 
 	result, err := clueService.GetKeyAction(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -177,7 +175,7 @@ func (h *ClueHandler) GetSmartPhone(c *gin.Context) {
 
 	result, err := clueService.GetSmartPhone(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -207,7 +205,7 @@ func (h *ClueHandler) GetFormList(c *gin.Context) {
 
 	result, err := clueService.GetFormList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -234,7 +232,7 @@ func (h *ClueHandler) GetFormDetail(c *gin.Context) {
 
 	result, err := clueService.GetFormDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -264,7 +262,7 @@ func (h *ClueHandler) GetClueStoreList(c *gin.Context) {
 
 	result, err := clueService.GetClueStoreList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -296,7 +294,7 @@ func (h *ClueHandler) GetQingniaoFormList(c *gin.Context) {
 
 	result, err := qingniaoService.GetFormList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -321,7 +319,7 @@ func (h *ClueHandler) CreateQingniaoForm(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	formID, err := qingniaoService.CreateForm(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -346,7 +344,7 @@ func (h *ClueHandler) UpdateQingniaoForm(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	formID, err := qingniaoService.UpdateForm(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -373,7 +371,7 @@ func (h *ClueHandler) DeleteQingniaoForm(c *gin.Context) {
 
 	_, err := qingniaoService.DeleteForm(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -403,7 +401,7 @@ func (h *ClueHandler) GetCouponList(c *gin.Context) {
 
 	result, err := qingniaoService.GetCouponList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -428,7 +426,7 @@ func (h *ClueHandler) CreateCoupon(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	couponID, err := qingniaoService.CreateCoupon(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -455,7 +453,7 @@ func (h *ClueHandler) GetCouponDetail(c *gin.Context) {
 
 	result, err := qingniaoService.GetCouponDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -480,7 +478,7 @@ func (h *ClueHandler) UpdateCoupon(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	err := qingniaoService.UpdateCoupon(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -505,7 +503,7 @@ func (h *ClueHandler) UploadCouponCode(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	result, err := qingniaoService.UploadCouponCode(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -530,7 +528,7 @@ func (h *ClueHandler) ConsumeCouponCode(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	err := qingniaoService.ConsumeCouponCode(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -560,7 +558,7 @@ func (h *ClueHandler) GetQingniaoSmartPhoneList(c *gin.Context) {
 
 	result, err := qingniaoService.GetSmartPhoneList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -585,7 +583,7 @@ func (h *ClueHandler) CreateSmartPhone(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	result, err := qingniaoService.CreateSmartPhone(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -612,7 +610,7 @@ func (h *ClueHandler) DeleteSmartPhone(c *gin.Context) {
 
 	err := qingniaoService.DeleteSmartPhone(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -647,7 +645,7 @@ func (h *ClueHandler) GetSmartPhoneRecords(c *gin.Context) {
 
 	result, err := qingniaoService.GetSmartPhoneRecords(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -677,7 +675,7 @@ func (h *ClueHandler) GetWechatPoolList(c *gin.Context) {
 
 	result, err := qingniaoService.GetWechatPoolList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -705,7 +703,7 @@ func (h *ClueHandler) GetWechatInstanceList(c *gin.Context) {
 
 	result, err := qingniaoService.GetWechatInstanceList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -732,7 +730,7 @@ func (h *ClueHandler) GetWechatInstanceDetail(c *gin.Context) {
 
 	result, err := qingniaoService.GetWechatInstanceDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -757,7 +755,7 @@ func (h *ClueHandler) UpdateWechatInstance(c *gin.Context) {
 	qingniaoService := oceanengine.NewQingniaoService(h.client)
 	result, err := qingniaoService.UpdateWechatInstance(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
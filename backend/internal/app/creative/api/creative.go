@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"oceanengine-backend/config"
 	"oceanengine-backend/internal/app/creative/dto"
 	"oceanengine-backend/internal/app/creative/service"
 	"oceanengine-backend/pkg/errcode"
@@ -17,9 +18,9 @@ type CreativeHandler struct {
 }
 
 // NewCreativeHandler 创建创意处理器
-func NewCreativeHandler(db *gorm.DB) *CreativeHandler {
+func NewCreativeHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *CreativeHandler {
 	return &CreativeHandler{
-		service: service.NewCreativeService(db),
+		service: service.NewCreativeService(db, oceanCfg),
 	}
 }
 
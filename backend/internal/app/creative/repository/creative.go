@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/internal/app/creative/dto"
 	"oceanengine-backend/internal/app/creative/model"
+	"oceanengine-backend/internal/datascope"
 )
 
 // CreativeRepository 创意仓储接口
@@ -35,7 +36,7 @@ func (r *creativeRepository) GetList(ctx context.Context, req *dto.CreativeListR
 	var list []*model.Creative
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.Creative{})
+	query := r.db.WithContext(ctx).Model(&model.Creative{}).Scopes(datascope.ByAdvertiser(ctx))
 
 	if req.AdvertiserID > 0 {
 		query = query.Where("advertiser_id = ?", req.AdvertiserID)
@@ -68,6 +69,9 @@ func (r *creativeRepository) GetByID(ctx context.Context, id uint64) (*model.Cre
 	if err := r.db.WithContext(ctx).First(&creative, id).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, creative.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &creative, nil
 }
 
@@ -77,13 +81,16 @@ func (r *creativeRepository) GetByCreativeID(ctx context.Context, creativeID uin
 	if err := r.db.WithContext(ctx).Where("creative_id = ?", creativeID).First(&creative).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, creative.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &creative, nil
 }
 
 // GetByAdID 根据广告组ID获取创意列表
 func (r *creativeRepository) GetByAdID(ctx context.Context, adID uint64) ([]*model.Creative, error) {
 	var list []*model.Creative
-	if err := r.db.WithContext(ctx).Where("ad_id = ?", adID).Find(&list).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(datascope.ByAdvertiser(ctx)).Where("ad_id = ?", adID).Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -99,9 +106,13 @@ func (r *creativeRepository) Update(ctx context.Context, creative *model.Creativ
 	return r.db.WithContext(ctx).Save(creative).Error
 }
 
-// UpdateStatus 批量更新状态
+// UpdateStatus 批量更新状态，存在数据权限外的记录时返回 datascope.ErrOutOfScope
 func (r *creativeRepository) UpdateStatus(ctx context.Context, ids []uint64, status string) error {
+	if err := datascope.CheckIDs(ctx, r.db, &model.Creative{}, ids); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Model(&model.Creative{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("id IN ?", ids).
 		Update("opt_status", status).Error
 }
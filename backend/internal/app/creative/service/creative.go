@@ -3,34 +3,40 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"oceanengine-backend/config"
 	adRepo "oceanengine-backend/internal/app/ad/repository"
 	advRepo "oceanengine-backend/internal/app/advertiser/repository"
 	"oceanengine-backend/internal/app/creative/dto"
 	"oceanengine-backend/internal/app/creative/model"
 	"oceanengine-backend/internal/app/creative/repository"
 	mediaModel "oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 )
 
 // CreativeService 创意服务
 type CreativeService struct {
-	db      *gorm.DB
-	repo    repository.CreativeRepository
-	adRepo  adRepo.AdRepository
-	advRepo advRepo.AdvertiserRepository
+	db       *gorm.DB
+	repo     repository.CreativeRepository
+	adRepo   adRepo.AdRepository
+	advRepo  advRepo.AdvertiserRepository
+	oceanCfg *config.OceanConfig
 }
 
 // NewCreativeService 创建创意服务
-func NewCreativeService(db *gorm.DB) *CreativeService {
+func NewCreativeService(db *gorm.DB, oceanCfg *config.OceanConfig) *CreativeService {
 	return &CreativeService{
-		db:      db,
-		repo:    repository.NewCreativeRepository(db),
-		adRepo:  adRepo.NewAdRepository(db),
-		advRepo: advRepo.NewAdvertiserRepository(db),
+		db:       db,
+		repo:     repository.NewCreativeRepository(db),
+		adRepo:   adRepo.NewAdRepository(db),
+		advRepo:  advRepo.NewAdvertiserRepository(db),
+		oceanCfg: oceanCfg,
 	}
 }
 
@@ -65,6 +71,9 @@ func (s *CreativeService) Create(ctx context.Context, req *dto.CreativeCreateReq
 	// 校验广告组是否存在
 	ad, err := s.adRepo.GetByAdID(ctx, req.AdID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrAdNotFound)
 		}
@@ -77,14 +86,23 @@ func (s *CreativeService) Create(ctx context.Context, req *dto.CreativeCreateReq
 	// 获取广告主信息，用于调用 OE API
 	adv, err := s.advRepo.GetByID(ctx, req.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 	}
 
+	// 素材须已上传到巨量
+	images, video, err := s.syncedMaterials(ctx, req.AdvertiserID, req.ImageIDs, req.VideoID)
+	if err != nil {
+		return nil, err
+	}
+
 	var creativeID uint64
 
 	// 如果有 access_token，调用 OE API 创建创意
 	if adv.AccessToken != "" {
-		client := oceanengine.NewClient("", "")
+		client := oceanclient.New(s.oceanCfg)
 		client.SetAccessToken(adv.AccessToken)
 		creativeSvc := oceanengine.NewCreativeService(client)
 
@@ -127,22 +145,12 @@ func (s *CreativeService) Create(ctx context.Context, req *dto.CreativeCreateReq
 	}
 
 	// 如果有图片/视频素材，取首个设置URL（简化处理）
-	if len(req.ImageIDs) > 0 {
-		var img mediaModel.MaterialImage
-		if err := s.db.WithContext(ctx).
-			Where("advertiser_id = ? AND image_id = ?", req.AdvertiserID, req.ImageIDs[0]).
-			First(&img).Error; err == nil {
-			creative.ImageURL = img.URL
-		}
+	if len(images) > 0 {
+		creative.ImageURL = images[0].URL
 	}
-	if req.VideoID != "" {
-		var vid mediaModel.MaterialVideo
-		if err := s.db.WithContext(ctx).
-			Where("advertiser_id = ? AND video_id = ?", req.AdvertiserID, req.VideoID).
-			First(&vid).Error; err == nil {
-			creative.VideoURL = vid.URL
-			creative.ThumbURL = vid.PosterURL
-		}
+	if video != nil {
+		creative.VideoURL = video.URL
+		creative.ThumbURL = video.PosterURL
 	}
 
 	if err := s.repo.Create(ctx, creative); err != nil {
@@ -155,10 +163,57 @@ func (s *CreativeService) Create(ctx context.Context, req *dto.CreativeCreateReq
 	}, nil
 }
 
+// syncedMaterials 查询创意使用的素材，素材不在素材库或未同步到巨量 (非 SYNCED) 时返回错误
+func (s *CreativeService) syncedMaterials(ctx context.Context, advertiserID uint64, imageIDs []string, videoID string) ([]*mediaModel.MaterialImage, *mediaModel.MaterialVideo, error) {
+	var images []*mediaModel.MaterialImage
+	if len(imageIDs) > 0 {
+		var list []*mediaModel.MaterialImage
+		if err := s.db.WithContext(ctx).
+			Where("advertiser_id = ? AND image_id IN ?", advertiserID, imageIDs).
+			Find(&list).Error; err != nil {
+			return nil, nil, errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+		byID := make(map[string]*mediaModel.MaterialImage, len(list))
+		for _, img := range list {
+			byID[*img.ImageID] = img
+		}
+		for _, id := range imageIDs {
+			img, ok := byID[id]
+			if !ok {
+				return nil, nil, errcode.NewWithMessage(errcode.ErrMaterialNotFound, fmt.Sprintf("图片素材 %s 不存在", id))
+			}
+			if img.Status != mediaModel.MaterialStatusSynced {
+				return nil, nil, errcode.NewWithMessage(errcode.ErrMaterialNotSynced, fmt.Sprintf("图片素材 %s 未同步到巨量引擎，当前状态: %s", id, img.Status))
+			}
+			images = append(images, img)
+		}
+	}
+
+	if videoID == "" {
+		return images, nil, nil
+	}
+	var video mediaModel.MaterialVideo
+	if err := s.db.WithContext(ctx).
+		Where("advertiser_id = ? AND video_id = ?", advertiserID, videoID).
+		First(&video).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errcode.NewWithMessage(errcode.ErrMaterialNotFound, fmt.Sprintf("视频素材 %s 不存在", videoID))
+		}
+		return nil, nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if video.Status != mediaModel.MaterialStatusSynced {
+		return nil, nil, errcode.NewWithMessage(errcode.ErrMaterialNotSynced, fmt.Sprintf("视频素材 %s 未同步到巨量引擎，当前状态: %s", videoID, video.Status))
+	}
+	return images, &video, nil
+}
+
 // GetByID 获取创意详情
 func (s *CreativeService) GetByID(ctx context.Context, id uint64) (*dto.CreativeDetailResp, error) {
 	c, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.New(errcode.ErrCreativeNotFound)
 		}
@@ -172,6 +227,9 @@ func (s *CreativeService) GetByID(ctx context.Context, id uint64) (*dto.Creative
 func (s *CreativeService) Update(ctx context.Context, id uint64, req *dto.CreativeUpdateReq) error {
 	c, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrCreativeNotFound)
 		}
@@ -198,6 +256,9 @@ func (s *CreativeService) Update(ctx context.Context, id uint64, req *dto.Creati
 // UpdateStatus 批量更新状态
 func (s *CreativeService) UpdateStatus(ctx context.Context, req *dto.CreativeStatusUpdateReq) error {
 	if err := s.repo.UpdateStatus(ctx, req.IDs, req.OptStatus); err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 	return nil
@@ -207,6 +268,9 @@ func (s *CreativeService) UpdateStatus(ctx context.Context, req *dto.CreativeSta
 func (s *CreativeService) Delete(ctx context.Context, id uint64) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return err
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errcode.New(errcode.ErrCreativeNotFound)
 		}
@@ -1,11 +1,20 @@
 package api
 
 import (
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
+	"github.com/bububa/oceanengine/marketing-api/api/dmp/audience"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/dmp/model"
+	"oceanengine-backend/internal/app/dmp/service"
+	"oceanengine-backend/internal/middleware"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -15,27 +24,22 @@ type DMPHandler struct {
 	db       *gorm.DB
 	oceanCfg *config.OceanConfig
 	client   *oceanengine.Client
+	builds   *service.BuildService
 }
 
 // NewDMPHandler 创建DMP处理器
-func NewDMPHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *DMPHandler {
+func NewDMPHandler(db *gorm.DB, oceanCfg *config.OceanConfig, builds *service.BuildService) *DMPHandler {
 	return &DMPHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
+		builds:   builds,
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *DMPHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -68,16 +72,15 @@ func (h *DMPHandler) UploadDataSourceFile(c *gin.Context) {
 	defer file.Close()
 
 	// 读取文件内容
-	fileBytes := make([]byte, header.Size)
-	_, err = file.Read(fileBytes)
+	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		response.InternalError(c, "读取文件失败: "+err.Error())
 		return
 	}
 
-	result, err := h.client.DMP().UploadDataSourceFile(c.Request.Context(), advertiserID, header.Filename, fileBytes)
+	result, err := h.client.WithAccessToken(accessToken).DMP().UploadDataSourceFile(c.Request.Context(), advertiserID, header.Filename, fileBytes)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -99,10 +102,9 @@ func (h *DMPHandler) CreateDataSource(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	result, err := h.client.DMP().CreateDataSource(c.Request.Context(), &req)
+	result, err := h.client.WithAccessToken(accessToken).DMP().CreateDataSource(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -124,10 +126,9 @@ func (h *DMPHandler) UpdateDataSource(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	err := h.client.DMP().UpdateDataSource(c.Request.Context(), &req)
+	err := h.client.WithAccessToken(accessToken).DMP().UpdateDataSource(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -145,15 +146,14 @@ func (h *DMPHandler) GetDataSourceDetail(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
 	req := &oceanengine.DataSourceReadRequest{
 		AdvertiserID:  advertiserID,
 		DataSourceIDs: dataSourceIDs,
 	}
 
-	list, err := h.client.DMP().GetDataSourceDetail(c.Request.Context(), req)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetDataSourceDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -174,16 +174,15 @@ func (h *DMPHandler) GetCustomAudienceList(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
 	req := &oceanengine.CustomAudienceListRequest{
 		AdvertiserID: advertiserID,
 		Page:         page,
 		PageSize:     pageSize,
 	}
 
-	result, err := h.client.DMP().GetCustomAudienceList(c.Request.Context(), req)
+	result, err := h.client.WithAccessToken(accessToken).DMP().GetCustomAudienceList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -206,15 +205,14 @@ func (h *DMPHandler) GetCustomAudienceDetail(c *gin.Context) {
 		audienceIDs[i], _ = strconv.ParseInt(s, 10, 64)
 	}
 
-	h.client.SetAccessToken(accessToken)
 	req := &oceanengine.CustomAudienceReadRequest{
 		AdvertiserID:      advertiserID,
 		CustomAudienceIDs: audienceIDs,
 	}
 
-	list, err := h.client.DMP().GetCustomAudienceDetail(c.Request.Context(), req)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetCustomAudienceDetail(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -236,10 +234,9 @@ func (h *DMPHandler) CreateCustomAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	result, err := h.client.DMP().CreateCustomAudience(c.Request.Context(), &req)
+	result, err := h.client.WithAccessToken(accessToken).DMP().CreateCustomAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -261,10 +258,9 @@ func (h *DMPHandler) PublishCustomAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	err := h.client.DMP().PublishCustomAudience(c.Request.Context(), &req)
+	err := h.client.WithAccessToken(accessToken).DMP().PublishCustomAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -286,16 +282,101 @@ func (h *DMPHandler) PushCustomAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	err := h.client.DMP().PushCustomAudience(c.Request.Context(), &req)
+	err := h.client.WithAccessToken(accessToken).DMP().PushCustomAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	response.OK(c)
 }
 
+// BuildAudience 根据原始ID文件创建人群包构建任务，由定时任务服务异步构建，通过 GetBuildTask 查询结果
+// multipart 表单: file 可多个, CSV 或每行一个ID的文本; id_type 为 PHONE/IMEI/IDFA/OAID; column 为ID所在列;
+// hashed=true 表示文件中的ID已按类型哈希 (手机号 SHA256，其余 MD5)，否则一律按原始ID哈希;
+// 不传 data_source_id 时以 data_source_name 新建数据源; wait/publish/push_advertiser_ids 决定是否等待数据源就绪并发布、推送
+func (h *DMPHandler) BuildAudience(c *gin.Context) {
+	advertiserID := h.getAdvertiserID(c)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		response.BadRequest(c, "文件上传失败: "+err.Error())
+		return
+	}
+	files := form.File["file"]
+	idType := audience.IDType(strings.ToUpper(c.PostForm("id_type")))
+	if advertiserID == 0 || len(files) == 0 || idType == "" {
+		response.BadRequest(c, "缺少必要参数")
+		return
+	}
+	if _, err := idType.DataType(); err != nil {
+		response.BadRequest(c, "不支持的ID类型")
+		return
+	}
+
+	column, _ := strconv.Atoi(c.PostForm("column"))
+	operationType, _ := strconv.Atoi(c.PostForm("operation_type"))
+	task := &model.AudienceBuildTask{
+		IDType:         string(idType),
+		Column:         column,
+		Hashed:         c.PostForm("hashed") == "true",
+		DataSourceID:   c.PostForm("data_source_id"),
+		DataSourceName: c.PostForm("data_source_name"),
+		Description:    c.PostForm("description"),
+		DataSourceType: c.PostForm("data_source_type"),
+		OperationType:  operationType,
+		Dedupe:         c.PostForm("dedupe") == "true",
+		Wait:           c.PostForm("wait") == "true",
+		Publish:        c.PostForm("publish") == "true",
+		CreatedBy:      uint64(middleware.GetUserID(c)),
+	}
+	if task.DataSourceID == "" && task.DataSourceName == "" {
+		response.BadRequest(c, "缺少数据源名称")
+		return
+	}
+	var pushIDs []string
+	for _, idStr := range strings.Split(c.PostForm("push_advertiser_ids"), ",") {
+		if idStr = strings.TrimSpace(idStr); idStr == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(idStr, 10, 64); err != nil {
+			response.BadRequest(c, "推送广告主ID格式错误")
+			return
+		}
+		pushIDs = append(pushIDs, idStr)
+	}
+	if len(pushIDs) > audience.MaxPushAdvertisers {
+		response.BadRequest(c, fmt.Sprintf("一次最多推送 %d 个广告主", audience.MaxPushAdvertisers))
+		return
+	}
+	task.PushAdvertiserIDs = strings.Join(pushIDs, ",")
+
+	task, err = h.builds.Create(c.Request.Context(), uint64(advertiserID), task, files)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OKWithData(c, task)
+}
+
+// GetBuildTask 查询人群包构建任务状态与结果
+func (h *DMPHandler) GetBuildTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "任务ID格式错误")
+		return
+	}
+
+	task, err := h.builds.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OKWithData(c, task)
+}
+
 // DeleteCustomAudience 删除人群包
 func (h *DMPHandler) DeleteCustomAudience(c *gin.Context) {
 	accessToken := h.getAccessToken(c)
@@ -311,10 +392,9 @@ func (h *DMPHandler) DeleteCustomAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	err := h.client.DMP().DeleteCustomAudience(c.Request.Context(), &req)
+	err := h.client.WithAccessToken(accessToken).DMP().DeleteCustomAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -333,10 +413,9 @@ func (h *DMPHandler) GetBrandList(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().GetBrandList(c.Request.Context(), advertiserID)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetBrandList(c.Request.Context(), advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -358,10 +437,9 @@ func (h *DMPHandler) CopyAudienceToBrand(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	err := h.client.DMP().CopyCustomAudienceToBrand(c.Request.Context(), &req)
+	err := h.client.WithAccessToken(accessToken).DMP().CopyCustomAudienceToBrand(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -385,10 +463,9 @@ func (h *DMPHandler) CreateLookalikeAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	result, err := h.client.DMP().CreateLookalikeAudience(c.Request.Context(), &req)
+	result, err := h.client.WithAccessToken(accessToken).DMP().CreateLookalikeAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -407,10 +484,9 @@ func (h *DMPHandler) GetInterestCategories(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().GetInterestCategories(c.Request.Context(), advertiserID)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetInterestCategories(c.Request.Context(), advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -429,10 +505,9 @@ func (h *DMPHandler) GetActionCategories(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().GetActionCategories(c.Request.Context(), advertiserID, actionScene, actionDays)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetActionCategories(c.Request.Context(), advertiserID, actionScene, actionDays)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -450,10 +525,9 @@ func (h *DMPHandler) SearchInterestKeywords(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().SearchInterestKeywords(c.Request.Context(), advertiserID, query)
+	list, err := h.client.WithAccessToken(accessToken).DMP().SearchInterestKeywords(c.Request.Context(), advertiserID, query)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -473,10 +547,9 @@ func (h *DMPHandler) SearchAwemeAuthors(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().SearchAwemeAuthors(c.Request.Context(), advertiserID, query)
+	list, err := h.client.WithAccessToken(accessToken).DMP().SearchAwemeAuthors(c.Request.Context(), advertiserID, query)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -493,10 +566,9 @@ func (h *DMPHandler) GetAwemeAuthorCategories(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	list, err := h.client.DMP().GetAwemeAuthorCategories(c.Request.Context(), advertiserID)
+	list, err := h.client.WithAccessToken(accessToken).DMP().GetAwemeAuthorCategories(c.Request.Context(), advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -520,10 +592,9 @@ func (h *DMPHandler) EstimateAudience(c *gin.Context) {
 		return
 	}
 
-	h.client.SetAccessToken(accessToken)
-	result, err := h.client.DMP().EstimateAudience(c.Request.Context(), &req)
+	result, err := h.client.WithAccessToken(accessToken).DMP().EstimateAudience(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
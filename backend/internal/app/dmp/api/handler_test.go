@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/dmp/model"
+	"oceanengine-backend/internal/app/dmp/service"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/pkg/oceansdk"
+	"oceanengine-backend/pkg/storage"
+)
+
+type staticTokens string
+
+func (s staticTokens) GetAccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	return string(s), nil
+}
+
+// buildForm 构造人群包构建表单
+func buildForm(t *testing.T, fields map[string]string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "oaid.txt")
+	require.NoError(t, err)
+	part.Write([]byte("0CC175B9C0F1B6A831C399E269772661\nnot-hashed\n"))
+	for k, v := range fields {
+		form.WriteField(k, v)
+	}
+	require.NoError(t, form.Close())
+	return &body, form.FormDataContentType()
+}
+
+// TestBuildAudience 创建构建任务后由定时任务构建，已哈希的ID仅校验格式，未等待时不查询数据源状态
+func TestBuildAudience(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("Access-Token"))
+		paths = append(paths, r.URL.Path)
+		var data interface{}
+		switch r.URL.Path {
+		case "/open_api/2/dmp/data_source/file/upload/":
+			data = map[string]interface{}{"file_path": "path1"}
+		case "/open_api/2/dmp/data_source/create/":
+			data = map[string]interface{}{"data_source_id": "ds1"}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": data})
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}, &model.AudienceBuildTask{}))
+	adv := &advModel.Advertiser{AdvertiserID: 1001, Name: "dmp"}
+	other := &advModel.Advertiser{AdvertiserID: 1002, Name: "other"}
+	require.NoError(t, db.Create([]*advModel.Advertiser{adv, other}).Error)
+
+	dir := t.TempDir()
+	st, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	oceanCfg := &config.OceanConfig{AppID: "1", Secret: "secret", BaseURL: server.URL + "/open_api"}
+	sdk := oceanclient.NewSDK(oceanCfg, oceansdk.WithTokenResolver(staticTokens("token")))
+	builds := service.NewBuildService(db, st, sdk, zap.NewNop())
+
+	gin.SetMode(gin.TestMode)
+	h := NewDMPHandler(db, oceanCfg, builds)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		filter := datascope.NewFilter([]uint64{adv.ID})
+		c.Request = c.Request.WithContext(datascope.WithFilter(c.Request.Context(), filter))
+	})
+	router.POST("/dmp/audience/build", h.BuildAudience)
+	router.GET("/dmp/audience/build/:id", h.GetBuildTask)
+
+	// 推送到数据权限外的广告主
+	body, contentType := buildForm(t, map[string]string{
+		"id_type": "oaid", "hashed": "true", "data_source_name": "老客", "publish": "true", "push_advertiser_ids": "1002",
+	})
+	req, _ := http.NewRequest("POST", "/dmp/audience/build?advertiser_id=1001", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	body, contentType = buildForm(t, map[string]string{"id_type": "oaid", "hashed": "true", "data_source_name": "老客"})
+	req, _ = http.NewRequest("POST", "/dmp/audience/build?advertiser_id=1001", body)
+	req.Header.Set("Content-Type", contentType)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var created struct {
+		Data model.AudienceBuildTask `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, model.BuildStatusPending, created.Data.Status)
+	assert.Equal(t, adv.ID, created.Data.AdvertiserID)
+	assert.Empty(t, paths)
+
+	// 服务异常退出时处理中的任务标记为失败
+	expired := time.Now().Add(-time.Minute)
+	stale := &model.AudienceBuildTask{AdvertiserID: adv.ID, IDType: "OAID", Status: model.BuildStatusProcessing, LeaseUntil: &expired}
+	require.NoError(t, db.Create(stale).Error)
+
+	success, failed, err := builds.Run(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, []string{"/open_api/2/dmp/data_source/file/upload/", "/open_api/2/dmp/data_source/create/"}, paths)
+
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/dmp/audience/build/%d", created.Data.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.AudienceBuildTask `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.BuildStatusCompleted, resp.Data.Status)
+	assert.Equal(t, "ds1", resp.Data.DataSourceID)
+	assert.Equal(t, 1, resp.Data.IDs)
+	assert.Equal(t, 1, resp.Data.Invalid)
+	assert.False(t, resp.Data.Ready)
+	assert.NotNil(t, resp.Data.FinishedAt)
+
+	var task model.AudienceBuildTask
+	require.NoError(t, db.First(&task, stale.ID).Error)
+	assert.Equal(t, model.BuildStatusFailed, task.Status)
+	assert.NotEmpty(t, task.ErrorMsg)
+
+	// 原始ID文件在任务结束后删除
+	files, _ := filepath.Glob(filepath.Join(dir, "dmp", "*", "*"))
+	assert.Empty(t, files)
+
+	// 其他广告主的任务
+	hidden := &model.AudienceBuildTask{AdvertiserID: other.ID, IDType: "OAID", Status: model.BuildStatusPending}
+	require.NoError(t, db.Create(hidden).Error)
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/dmp/audience/build/%d", hidden.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+)
+
+// AudienceBuildTask 人群包构建任务
+// API 服务保存上传的原始ID文件并创建任务，定时任务服务领取后构建人群包。
+// 构建过程会上传文件并创建数据源，中断的任务不会重新执行，避免重复创建数据源
+type AudienceBuildTask struct {
+	ID                uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdvertiserID      uint64     `gorm:"index;not null" json:"advertiser_id"` // ad_advertiser.id
+	Status            string     `gorm:"size:32;default:'PENDING';index" json:"status"`
+	IDType            string     `gorm:"size:16;not null" json:"id_type"`
+	Column            int        `gorm:"default:0" json:"column"`
+	Hashed            bool       `gorm:"default:false" json:"hashed"`
+	SourceKeys        string     `gorm:"type:text" json:"-"` // 原始ID文件存储路径，JSON 数组，任务结束后删除
+	DataSourceID      string     `gorm:"size:64" json:"data_source_id"`
+	DataSourceName    string     `gorm:"size:64" json:"data_source_name"`
+	Description       string     `gorm:"size:256" json:"description"`
+	DataSourceType    string     `gorm:"size:16" json:"data_source_type"`
+	OperationType     int        `gorm:"default:0" json:"operation_type"`
+	Dedupe            bool       `gorm:"default:false" json:"dedupe"`
+	Wait              bool       `gorm:"default:false" json:"wait"`
+	Publish           bool       `gorm:"default:false" json:"publish"`
+	PushAdvertiserIDs string     `gorm:"type:text" json:"push_advertiser_ids"` // 逗号分隔的巨量广告主ID
+	CustomAudienceID  uint64     `gorm:"default:0" json:"custom_audience_id"`
+	IDs               int        `gorm:"default:0" json:"ids"`
+	Invalid           int        `gorm:"default:0" json:"invalid"`
+	Duplicated        int        `gorm:"default:0" json:"duplicated"`
+	Ready             bool       `gorm:"default:false" json:"ready"` // 数据源是否已就绪
+	ErrorMsg          string     `gorm:"size:500" json:"error_msg"`
+	LeaseUntil        *time.Time `gorm:"index" json:"-"` // 处理租约到期时间
+	FinishedAt        *time.Time `json:"finished_at"`
+	CreatedBy         uint64     `gorm:"default:0" json:"created_by"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (AudienceBuildTask) TableName() string {
+	return "ad_audience_build_task"
+}
+
+// 人群包构建任务状态
+const (
+	BuildStatusPending    = "PENDING"
+	BuildStatusProcessing = "PROCESSING"
+	BuildStatusCompleted  = "COMPLETED"
+	BuildStatusFailed     = "FAILED"
+)
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/dmp/audience"
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	advRepo "oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/app/dmp/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceansdk"
+	"oceanengine-backend/pkg/storage"
+	"oceanengine-backend/pkg/utils"
+)
+
+// BuildService 人群包构建任务
+// API 服务通过 Create 保存原始ID文件并创建任务，定时任务服务通过 Run 领取任务并调用 SDK 构建人群包，
+// 两者需使用相同的存储
+type BuildService struct {
+	db      *gorm.DB
+	advRepo advRepo.AdvertiserRepository
+	storage storage.Storage
+	sdk     *oceansdk.Client
+	log     *zap.Logger
+}
+
+// NewBuildService 创建人群包构建任务服务，sdk 需配置按广告主解析 access_token
+func NewBuildService(db *gorm.DB, st storage.Storage, sdk *oceansdk.Client, log *zap.Logger) *BuildService {
+	return &BuildService{
+		db:      db,
+		advRepo: advRepo.NewAdvertiserRepository(db),
+		storage: st,
+		sdk:     sdk,
+		log:     log,
+	}
+}
+
+// Create 保存原始ID文件并创建构建任务
+// advertiserID 与推送的广告主均为巨量广告主ID，需在当前用户的数据权限内
+func (s *BuildService) Create(ctx context.Context, advertiserID uint64, task *model.AudienceBuildTask, files []*multipart.FileHeader) (*model.AudienceBuildTask, error) {
+	if s.storage == nil {
+		return nil, errcode.NewWithMessage(errcode.ErrServiceUnavail, "文件存储未配置")
+	}
+	adv, err := s.advertiser(ctx, advertiserID)
+	if err != nil {
+		return nil, err
+	}
+	if task.PushAdvertiserIDs != "" {
+		for _, idStr := range strings.Split(task.PushAdvertiserIDs, ",") {
+			id, _ := strconv.ParseUint(idStr, 10, 64)
+			if _, err := s.advertiser(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(files))
+	prefix := fmt.Sprintf("dmp/%s/%s", time.Now().Format("20060102"), uuid.NewString())
+	for i, header := range files {
+		key := fmt.Sprintf("%s_%d.txt", prefix, i)
+		if err := s.save(ctx, key, header); err != nil {
+			s.remove(context.WithoutCancel(ctx), keys)
+			return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+		keys = append(keys, key)
+	}
+	data, _ := json.Marshal(keys)
+
+	task.AdvertiserID = adv.ID
+	task.Status = model.BuildStatusPending
+	task.SourceKeys = string(data)
+	if err := s.db.WithContext(ctx).Create(task).Error; err != nil {
+		s.remove(context.WithoutCancel(ctx), keys)
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return task, nil
+}
+
+// Get 获取构建任务
+func (s *BuildService) Get(ctx context.Context, id uint64) (*model.AudienceBuildTask, error) {
+	var task model.AudienceBuildTask
+	if err := s.db.WithContext(ctx).First(&task, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrNotFound)
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if err := datascope.Check(ctx, task.AdvertiserID); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Run 依次领取并执行待处理的构建任务，直到没有待处理任务或 ctx 结束
+// lease 为单个任务的处理租约，租约过期仍处于处理中的任务（服务异常退出）标记为失败
+func (s *BuildService) Run(ctx context.Context, lease time.Duration) (success, failed int, err error) {
+	if err := s.expire(ctx); err != nil {
+		return 0, 0, err
+	}
+	for ctx.Err() == nil {
+		task, err := s.claim(ctx, lease)
+		if err != nil {
+			return success, failed, err
+		}
+		if task == nil {
+			break
+		}
+		if s.process(ctx, task) {
+			success++
+		} else {
+			failed++
+		}
+	}
+	return success, failed, nil
+}
+
+// expire 将租约过期的处理中任务标记为失败
+func (s *BuildService) expire(ctx context.Context) error {
+	var list []*model.AudienceBuildTask
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND lease_until < ?", model.BuildStatusProcessing, time.Now()).
+		Find(&list).Error; err != nil {
+		return err
+	}
+	for _, task := range list {
+		task.Status = model.BuildStatusFailed
+		task.ErrorMsg = "构建中断，请确认数据源状态后重新提交"
+		if err := s.finish(ctx, task, model.BuildStatusProcessing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claim 领取一个待处理任务，通过带条件的 UPDATE 抢占，没有待处理任务时返回 nil
+func (s *BuildService) claim(ctx context.Context, lease time.Duration) (*model.AudienceBuildTask, error) {
+	for {
+		var task model.AudienceBuildTask
+		err := s.db.WithContext(ctx).Where("status = ?", model.BuildStatusPending).Order("id ASC").First(&task).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		leaseUntil := time.Now().Add(lease)
+		result := s.db.WithContext(ctx).Model(&model.AudienceBuildTask{}).
+			Where("id = ? AND status = ?", task.ID, model.BuildStatusPending).
+			Updates(map[string]interface{}{"status": model.BuildStatusProcessing, "lease_until": leaseUntil})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			task.Status = model.BuildStatusProcessing
+			task.LeaseUntil = &leaseUntil
+			return &task, nil
+		}
+	}
+}
+
+// process 执行构建任务并记录结果，原始ID文件在任务结束后删除
+func (s *BuildService) process(ctx context.Context, task *model.AudienceBuildTask) bool {
+	var keys []string
+	_ = json.Unmarshal([]byte(task.SourceKeys), &keys)
+
+	result, err := s.build(ctx, task, keys)
+	if err != nil {
+		task.Status = model.BuildStatusFailed
+		task.ErrorMsg = utils.TruncateRunes(err.Error(), 500)
+		s.log.Warn(fmt.Sprintf("人群包构建任务 %d 失败: %v", task.ID, err))
+	} else {
+		task.Status = model.BuildStatusCompleted
+		task.DataSourceID = result.DataSourceID
+		task.CustomAudienceID = result.CustomAudienceID
+		task.IDs = result.IDs
+		task.Invalid = result.Invalid
+		task.Duplicated = result.Duplicated
+		task.Ready = result.DataSource != nil
+	}
+	// 服务关闭时同样记录结果，中断的任务不会重新执行
+	ctx = context.WithoutCancel(ctx)
+	if err := s.finish(ctx, task, model.BuildStatusProcessing); err != nil {
+		s.log.Error(fmt.Sprintf("更新人群包构建任务 %d 状态失败: %v", task.ID, err))
+	}
+	s.remove(ctx, keys)
+	return err == nil
+}
+
+// build 读取原始ID文件并调用 SDK 构建人群包
+func (s *BuildService) build(ctx context.Context, task *model.AudienceBuildTask, keys []string) (*audience.BuildResult, error) {
+	adv, err := s.advRepo.GetByID(ctx, task.AdvertiserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取广告主失败: %w", err)
+	}
+
+	req := &audience.BuildRequest{
+		AdvertiserID:   adv.AdvertiserID,
+		DataSourceID:   task.DataSourceID,
+		DataSourceName: task.DataSourceName,
+		Description:    task.Description,
+		DataSourceType: task.DataSourceType,
+		OperationType:  enum.DmpDatasourceOperationType(task.OperationType),
+		Dedupe:         task.Dedupe,
+		NoWait:         !task.Wait,
+		Publish:        task.Publish,
+	}
+	if task.PushAdvertiserIDs != "" {
+		for _, idStr := range strings.Split(task.PushAdvertiserIDs, ",") {
+			id, _ := strconv.ParseUint(idStr, 10, 64)
+			req.PushAdvertiserIDs = append(req.PushAdvertiserIDs, id)
+		}
+	}
+	for _, key := range keys {
+		file, err := s.storage.Open(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("读取原始ID文件失败: %w", err)
+		}
+		defer file.Close()
+		req.Sources = append(req.Sources, audience.Source{
+			Type:   audience.IDType(task.IDType),
+			Reader: file,
+			Column: task.Column,
+			Hashed: task.Hashed,
+		})
+	}
+
+	return oceansdk.Call(ctx, s.sdk, req.AdvertiserID, audience.Build, req)
+}
+
+// finish 按当前状态条件更新任务结果
+func (s *BuildService) finish(ctx context.Context, task *model.AudienceBuildTask, from string) error {
+	now := time.Now()
+	return s.db.WithContext(ctx).Model(&model.AudienceBuildTask{}).
+		Where("id = ? AND status = ?", task.ID, from).
+		Updates(map[string]interface{}{
+			"status":             task.Status,
+			"data_source_id":     task.DataSourceID,
+			"custom_audience_id": task.CustomAudienceID,
+			"ids":                task.IDs,
+			"invalid":            task.Invalid,
+			"duplicated":         task.Duplicated,
+			"ready":              task.Ready,
+			"error_msg":          task.ErrorMsg,
+			"lease_until":        nil,
+			"finished_at":        now,
+		}).Error
+}
+
+// advertiser 按巨量广告主ID获取广告主并校验数据权限
+func (s *BuildService) advertiser(ctx context.Context, advertiserID uint64) (*advModel.Advertiser, error) {
+	adv, err := s.advRepo.GetByAdvertiserID(ctx, advertiserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NewWithMessage(errcode.ErrAdvertiserNotFound, fmt.Sprintf("广告主 %d 不存在", advertiserID))
+		}
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return adv, nil
+}
+
+// save 保存上传的原始ID文件
+func (s *BuildService) save(ctx context.Context, key string, header *multipart.FileHeader) error {
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = s.storage.Put(ctx, key, file)
+	return err
+}
+
+// remove 删除原始ID文件
+func (s *BuildService) remove(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.log.Warn(fmt.Sprintf("删除原始ID文件 %s 失败: %v", key, err))
+		}
+	}
+}
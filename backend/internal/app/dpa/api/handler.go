@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,20 +24,13 @@ func NewDPAHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *DPAHandler {
 	return &DPAHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *DPAHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -64,7 +59,7 @@ func (h *DPAHandler) GetProductLibraryList(c *gin.Context) {
 
 	list, total, err := h.client.DPA().GetProductLibraryList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -91,7 +86,7 @@ func (h *DPAHandler) CreateProductLibrary(c *gin.Context) {
 
 	libraryID, err := h.client.DPA().CreateProductLibrary(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryName)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -120,7 +115,7 @@ func (h *DPAHandler) UpdateProductLibrary(c *gin.Context) {
 
 	err := h.client.DPA().UpdateProductLibrary(c.Request.Context(), accessToken, req.AdvertiserID, libraryID, req.LibraryName)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -141,7 +136,7 @@ func (h *DPAHandler) DeleteProductLibrary(c *gin.Context) {
 
 	err := h.client.DPA().DeleteProductLibrary(c.Request.Context(), accessToken, advertiserID, libraryID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -166,7 +161,7 @@ func (h *DPAHandler) GetProductList(c *gin.Context) {
 
 	list, total, err := h.client.DPA().GetProductList(c.Request.Context(), accessToken, advertiserID, libraryID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -190,7 +185,7 @@ func (h *DPAHandler) CreateProduct(c *gin.Context) {
 
 	productID, err := h.client.DPA().CreateProduct(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -217,7 +212,7 @@ func (h *DPAHandler) UpdateProduct(c *gin.Context) {
 
 	err := h.client.DPA().UpdateProduct(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -240,7 +235,7 @@ func (h *DPAHandler) DeleteProduct(c *gin.Context) {
 
 	err := h.client.DPA().DeleteProduct(c.Request.Context(), accessToken, advertiserID, libraryID, productID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -268,7 +263,7 @@ func (h *DPAHandler) BatchDeleteProducts(c *gin.Context) {
 
 	err := h.client.DPA().BatchDeleteProducts(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryID, req.ProductIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -291,7 +286,7 @@ func (h *DPAHandler) GetProductCategoryList(c *gin.Context) {
 
 	list, err := h.client.DPA().GetProductCategoryList(c.Request.Context(), accessToken, advertiserID, libraryID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -320,7 +315,7 @@ func (h *DPAHandler) CreateProductCategory(c *gin.Context) {
 
 	categoryID, err := h.client.DPA().CreateProductCategory(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryID, req.CategoryName, req.ParentID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -350,7 +345,7 @@ func (h *DPAHandler) UpdateProductCategory(c *gin.Context) {
 
 	err := h.client.DPA().UpdateProductCategory(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryID, categoryID, req.CategoryName)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -373,7 +368,7 @@ func (h *DPAHandler) DeleteProductCategory(c *gin.Context) {
 
 	err := h.client.DPA().DeleteProductCategory(c.Request.Context(), accessToken, advertiserID, libraryID, categoryID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -398,7 +393,7 @@ func (h *DPAHandler) GetProductSetList(c *gin.Context) {
 
 	list, total, err := h.client.DPA().GetProductSetList(c.Request.Context(), accessToken, advertiserID, libraryID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -427,7 +422,7 @@ func (h *DPAHandler) CreateProductSet(c *gin.Context) {
 
 	setID, err := h.client.DPA().CreateProductSet(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryID, req.SetName, req.Filters)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -458,7 +453,7 @@ func (h *DPAHandler) UpdateProductSet(c *gin.Context) {
 
 	err := h.client.DPA().UpdateProductSet(c.Request.Context(), accessToken, req.AdvertiserID, req.LibraryID, setID, req.SetName, req.Filters)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -481,7 +476,7 @@ func (h *DPAHandler) DeleteProductSet(c *gin.Context) {
 
 	err := h.client.DPA().DeleteProductSet(c.Request.Context(), accessToken, advertiserID, libraryID, setID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -505,7 +500,7 @@ func (h *DPAHandler) GetTemplateList(c *gin.Context) {
 
 	list, total, err := h.client.DPA().GetTemplateList(c.Request.Context(), accessToken, advertiserID, templateType, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -530,7 +525,7 @@ func (h *DPAHandler) GetDPACreativeList(c *gin.Context) {
 
 	list, total, err := h.client.DPA().GetDPACreativeList(c.Request.Context(), accessToken, advertiserID, adID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -554,7 +549,7 @@ func (h *DPAHandler) CreateDPACreative(c *gin.Context) {
 
 	creativeID, err := h.client.DPA().CreateDPACreative(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
 	enterpriseModel "oceanengine-backend/internal/app/enterprise/model"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -23,20 +25,13 @@ func NewEnterpriseHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *Enterprise
 	return &EnterpriseHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 获取access token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *EnterpriseHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAccountID 获取account_id (兼容open_id)
@@ -66,7 +61,7 @@ func (h *EnterpriseHandler) GetInfo(c *gin.Context) {
 
 	info, err := h.client.Enterprise().GetInfo(c.Request.Context(), accessToken, accountID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, info)
@@ -83,7 +78,7 @@ func (h *EnterpriseHandler) GetBindList(c *gin.Context) {
 
 	list, err := h.client.Enterprise().GetBindList(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(len(list)), 1, 100)
@@ -114,7 +109,7 @@ func (h *EnterpriseHandler) GetItemList(c *gin.Context) {
 
 	list, nextCursor, hasMore, err := h.client.Enterprise().GetVideoList(c.Request.Context(), accessToken, accountID, cursor, count)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{
@@ -138,7 +133,7 @@ func (h *EnterpriseHandler) GetItemDetail(c *gin.Context) {
 	// 获取视频分析数据
 	analytics, err := h.client.Enterprise().GetVideoAnalytics(c.Request.Context(), accessToken, accountID, itemID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, analytics)
@@ -171,7 +166,7 @@ func (h *EnterpriseHandler) SetTopItem(c *gin.Context) {
 
 	err := h.client.Enterprise().SetVideoTop(c.Request.Context(), accessToken, accountID, itemID, req.IsTop)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -192,7 +187,7 @@ func (h *EnterpriseHandler) DeleteItem(c *gin.Context) {
 
 	err := h.client.Enterprise().DeleteVideo(c.Request.Context(), accessToken, accountID, itemID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -224,7 +219,7 @@ func (h *EnterpriseHandler) GetCommentList(c *gin.Context) {
 
 	list, nextCursor, hasMore, err := h.client.Enterprise().GetCommentList(c.Request.Context(), accessToken, accountID, itemID, cursor, count)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{
@@ -271,7 +266,7 @@ func (h *EnterpriseHandler) ReplyComment(c *gin.Context) {
 
 	replyID, err := h.client.Enterprise().ReplyComment(c.Request.Context(), accessToken, accountID, itemID, req.CommentID, req.Content)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"reply_id": replyID})
@@ -341,7 +336,7 @@ func (h *EnterpriseHandler) UpdateReply(c *gin.Context) {
 
 	err := h.client.Enterprise().UpdateCommentReply(c.Request.Context(), accessToken, accountID, commentID, req.Content)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -360,7 +355,7 @@ func (h *EnterpriseHandler) HideComment(c *gin.Context) {
 
 	err := h.client.Enterprise().HideComment(c.Request.Context(), accessToken, accountID, commentID, true)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -379,7 +374,7 @@ func (h *EnterpriseHandler) DeleteComment(c *gin.Context) {
 
 	err := h.client.Enterprise().DeleteComment(c.Request.Context(), accessToken, accountID, commentID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -404,7 +399,7 @@ func (h *EnterpriseHandler) GetReplyTemplates(c *gin.Context) {
 		query = query.Where("account_id = ?", accountID)
 	}
 	if err := query.Order("sort_order ASC, id DESC").Find(&templates).Error; err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -450,7 +445,7 @@ func (h *EnterpriseHandler) CreateReplyTemplate(c *gin.Context) {
 	}
 
 	if err := h.db.Create(&template).Error; err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -498,7 +493,7 @@ func (h *EnterpriseHandler) GetOverviewData(c *gin.Context) {
 	if startDate != "" && endDate != "" {
 		data, err := h.client.Enterprise().GetOverviewDataByDateRange(c.Request.Context(), accessToken, accountID, startDate, endDate)
 		if err != nil {
-			response.InternalError(c, err.Error())
+			response.UpstreamError(c, err)
 			return
 		}
 		response.OKWithData(c, data)
@@ -507,7 +502,7 @@ func (h *EnterpriseHandler) GetOverviewData(c *gin.Context) {
 
 	data, err := h.client.Enterprise().GetOverviewData(c.Request.Context(), accessToken, accountID, dateType)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, data)
@@ -570,7 +565,7 @@ func (h *EnterpriseHandler) GetTrafficSource(c *gin.Context) {
 
 	data, err := h.client.Enterprise().GetFlowCategoryData(c.Request.Context(), accessToken, accountID, dateType)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, data)
@@ -592,7 +587,7 @@ func (h *EnterpriseHandler) GetOperationLogs(c *gin.Context) {
 
 	list, total, err := h.client.Enterprise().GetOperationLog(c.Request.Context(), accessToken, advertiserID, startDate, endDate, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,17 +24,13 @@ func NewEventManagerHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *EventMan
 	return &EventManagerHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *EventManagerHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -72,7 +70,7 @@ func (h *EventManagerHandler) GetAssets(c *gin.Context) {
 
 	result, err := eventService.GetAssets(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -104,7 +102,7 @@ func (h *EventManagerHandler) GetAllAssetsList(c *gin.Context) {
 
 	result, err := eventService.GetAllAssetsList(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -129,7 +127,7 @@ func (h *EventManagerHandler) CreateAsset(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	assetID, err := eventService.CreateAsset(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -158,7 +156,7 @@ func (h *EventManagerHandler) GetAvailableEvents(c *gin.Context) {
 
 	result, err := eventService.GetAvailableEvents(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -185,7 +183,7 @@ func (h *EventManagerHandler) GetEventConfigs(c *gin.Context) {
 
 	result, err := eventService.GetEventConfigs(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -210,7 +208,7 @@ func (h *EventManagerHandler) CreateEvents(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	err := eventService.CreateEvents(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -239,7 +237,7 @@ func (h *EventManagerHandler) GetTrackURL(c *gin.Context) {
 
 	result, err := eventService.GetTrackURL(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -264,7 +262,7 @@ func (h *EventManagerHandler) CreateTrackURL(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	err := eventService.CreateTrackURL(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -289,7 +287,7 @@ func (h *EventManagerHandler) UpdateTrackURL(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	err := eventService.UpdateTrackURL(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -318,7 +316,7 @@ func (h *EventManagerHandler) GetShare(c *gin.Context) {
 
 	result, err := eventService.GetShare(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -343,7 +341,7 @@ func (h *EventManagerHandler) Share(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	failList, err := eventService.Share(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -368,7 +366,7 @@ func (h *EventManagerHandler) ShareCancel(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	failList, err := eventService.ShareCancel(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -399,7 +397,7 @@ func (h *EventManagerHandler) GetOptimizedGoal(c *gin.Context) {
 
 	result, err := eventService.GetEventConvertOptimizedGoal(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -424,7 +422,7 @@ func (h *EventManagerHandler) Conversion(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	_, err := eventService.Conversion(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -451,7 +449,7 @@ func (h *EventManagerHandler) AddPublicKey(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	result, err := eventService.AddPublicKey(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -475,7 +473,7 @@ func (h *EventManagerHandler) GetAllPublicKeys(c *gin.Context) {
 
 	result, err := eventService.GetAllPublicKeys(c.Request.Context(), req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -495,7 +493,7 @@ func (h *EventManagerHandler) EnableAuth(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	err := eventService.EnableAuth(c.Request.Context(), advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -515,7 +513,7 @@ func (h *EventManagerHandler) DisableAuth(c *gin.Context) {
 	eventService := oceanengine.NewEventManagerService(h.client)
 	err := eventService.DisableAuth(c.Request.Context(), advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
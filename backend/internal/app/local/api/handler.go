@@ -1,20 +1,43 @@
 package api
 
 import (
+	"context"
 	"strconv"
-
+	"strings"
+
+	localFile "github.com/bububa/oceanengine/marketing-api/api/local/file"
+	localProject "github.com/bububa/oceanengine/marketing-api/api/local/project"
+	localPromotion "github.com/bububa/oceanengine/marketing-api/api/local/promotion"
+	localReport "github.com/bububa/oceanengine/marketing-api/api/local/report"
+	"github.com/bububa/oceanengine/marketing-api/model"
+	"github.com/bububa/oceanengine/marketing-api/model/local/file"
+	"github.com/bububa/oceanengine/marketing-api/model/local/project"
+	"github.com/bububa/oceanengine/marketing-api/model/local/promotion"
+	"github.com/bububa/oceanengine/marketing-api/model/local/report"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
+	"oceanengine-backend/pkg/logger"
 	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/oceansdk"
 	"oceanengine-backend/pkg/response"
 )
 
+// defaultReportMetrics 报表默认指标
+var defaultReportMetrics = []string{
+	"stat_cost", "show_cnt", "click_cnt", "ctr", "cpc_platform", "cpm_platform",
+	"convert_cnt", "conversion_rate", "conversion_cost",
+}
+
 // LocalHandler 本地推处理器
+// 项目、广告、报表与素材接口通过 sdk/marketing-api 调用，线索与门店接口仍使用 oceanengine.LocalClient
 type LocalHandler struct {
 	db       *gorm.DB
 	oceanCfg *config.OceanConfig
 	client   *oceanengine.Client
+	sdk      *oceansdk.Client
 }
 
 // NewLocalHandler 创建本地推处理器
@@ -22,20 +45,35 @@ func NewLocalHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *LocalHandler {
 	return &LocalHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
+		sdk:      oceanclient.NewSDK(oceanCfg, oceansdk.WithLogger(logger.Named("oceanengine"))),
 	}
 }
 
-// getAccessToken 获取access token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *LocalHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
+	return utils.GetAccessToken(c)
+}
+
+// sdkContext 返回携带 access_token 的请求上下文
+func (h *LocalHandler) sdkContext(c *gin.Context) context.Context {
+	return oceansdk.WithAccessToken(c.Request.Context(), h.getAccessToken(c))
+}
+
+// reportMetrics 报表指标，query 参数 metrics 以逗号分隔，未指定时使用默认指标
+func reportMetrics(c *gin.Context) []string {
+	if metrics := c.Query("metrics"); metrics != "" {
+		return strings.Split(metrics, ",")
 	}
-	if token == "" {
-		token = c.Query("access_token")
+	return defaultReportMetrics
+}
+
+// totalNumber 分页信息中的总数
+func totalNumber(pageInfo *model.PageInfo) int64 {
+	if pageInfo == nil {
+		return 0
 	}
-	return token
+	return int64(pageInfo.TotalNumber)
 }
 
 // getAdvertiserID 获取广告主ID
@@ -60,18 +98,18 @@ func (h *LocalHandler) GetProjectList(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	req := &oceanengine.ProjectListRequest{
-		AdvertiserID: advertiserID,
-		Page:         page,
-		PageSize:     pageSize,
+	req := &project.ListRequest{
+		LocalAccountID: advertiserID,
+		Page:           page,
+		PageSize:       pageSize,
 	}
 
-	list, total, err := h.client.Local().GetProjectList(c.Request.Context(), accessToken, req)
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localProject.List, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(total), page, pageSize)
+	response.OKWithList(c, result.ProjectList, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // GetProjectDetail 获取项目详情
@@ -84,9 +122,13 @@ func (h *LocalHandler) GetProjectDetail(c *gin.Context) {
 		return
 	}
 
-	detail, err := h.client.Local().GetProjectDetail(c.Request.Context(), accessToken, advertiserID, projectID)
+	req := &project.DetailRequest{
+		LocalAccountID: advertiserID,
+		ProjectID:      projectID,
+	}
+	detail, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localProject.Detail, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -101,15 +143,16 @@ func (h *LocalHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	var projectData map[string]interface{}
-	if err := c.ShouldBindJSON(&projectData); err != nil {
+	var req project.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
+	req.LocalAccountID = advertiserID
 
-	projectID, err := h.client.Local().CreateProject(c.Request.Context(), accessToken, advertiserID, projectData)
+	projectID, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localProject.Create, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"project_id": projectID})
@@ -125,16 +168,16 @@ func (h *LocalHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	var updateData map[string]interface{}
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	var req project.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
-	updateData["project_id"] = projectID
+	req.LocalAccountID = advertiserID
+	req.ProjectID = projectID
 
-	err := h.client.Local().UpdateProject(c.Request.Context(), accessToken, advertiserID, projectID, updateData)
-	if err != nil {
-		response.InternalError(c, err.Error())
+	if err := oceansdk.Exec(h.sdkContext(c), h.sdk, advertiserID, localProject.Update, &req); err != nil {
+		response.Error(c, err)
 		return
 	}
 	response.OK(c)
@@ -162,12 +205,23 @@ func (h *LocalHandler) UpdateProjectStatus(c *gin.Context) {
 		return
 	}
 
-	succIDs, failIDs, err := h.client.Local().UpdateProjectStatus(c.Request.Context(), accessToken, advertiserID, req.ProjectIDs, req.OptStatus)
+	sdkReq := &project.StatusUpdateRequest{
+		LocalAccountID: advertiserID,
+		Data:           make([]project.StatusUpdateItem, 0, len(req.ProjectIDs)),
+	}
+	for _, id := range req.ProjectIDs {
+		sdkReq.Data = append(sdkReq.Data, project.StatusUpdateItem{ProjectID: id, OptStatus: req.OptStatus})
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localProject.StatusUpdate, sdkReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithData(c, gin.H{"success_ids": succIDs, "fail_ids": failIDs})
+	failIDs := make([]uint64, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		failIDs = append(failIDs, e.ProjectID)
+	}
+	response.OKWithData(c, gin.H{"success_ids": result.ProjectIDs, "fail_ids": failIDs, "errors": result.Errors})
 }
 
 // DeleteProject 删除项目
@@ -182,7 +236,7 @@ func (h *LocalHandler) DeleteProject(c *gin.Context) {
 
 	err := h.client.Local().DeleteProject(c.Request.Context(), accessToken, advertiserID, []uint64{projectID})
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -200,18 +254,18 @@ func (h *LocalHandler) GetPromotionList(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	req := &oceanengine.PromotionListRequest{
-		AdvertiserID: advertiserID,
-		Page:         page,
-		PageSize:     pageSize,
+	req := &promotion.ListRequest{
+		LocalAccountID: advertiserID,
+		Page:           page,
+		PageSize:       pageSize,
 	}
 
-	list, total, err := h.client.Local().GetPromotionList(c.Request.Context(), accessToken, req)
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localPromotion.List, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(total), page, pageSize)
+	response.OKWithList(c, result.Promotion, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // GetPromotionDetail 获取广告详情
@@ -224,9 +278,13 @@ func (h *LocalHandler) GetPromotionDetail(c *gin.Context) {
 		return
 	}
 
-	detail, err := h.client.Local().GetPromotionDetail(c.Request.Context(), accessToken, advertiserID, promotionID)
+	req := &promotion.DetailRequest{
+		LocalAccountID: advertiserID,
+		PromotionID:    promotionID,
+	}
+	detail, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localPromotion.Detail, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -241,15 +299,16 @@ func (h *LocalHandler) CreatePromotion(c *gin.Context) {
 		return
 	}
 
-	var promotionData map[string]interface{}
-	if err := c.ShouldBindJSON(&promotionData); err != nil {
+	var req promotion.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
+	req.LocalAccountID = advertiserID
 
-	promotionID, err := h.client.Local().CreatePromotion(c.Request.Context(), accessToken, advertiserID, promotionData)
+	promotionID, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localPromotion.Create, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"promotion_id": promotionID})
@@ -265,16 +324,16 @@ func (h *LocalHandler) UpdatePromotion(c *gin.Context) {
 		return
 	}
 
-	var updateData map[string]interface{}
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	var req promotion.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
-	updateData["promotion_id"] = promotionID
+	req.LocalAccountID = advertiserID
+	req.PromotionID = promotionID
 
-	err := h.client.Local().UpdatePromotion(c.Request.Context(), accessToken, advertiserID, promotionID, updateData)
-	if err != nil {
-		response.InternalError(c, err.Error())
+	if err := oceansdk.Exec(h.sdkContext(c), h.sdk, advertiserID, localPromotion.Update, &req); err != nil {
+		response.Error(c, err)
 		return
 	}
 	response.OK(c)
@@ -302,12 +361,19 @@ func (h *LocalHandler) UpdatePromotionStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.client.Local().UpdatePromotionStatus(c.Request.Context(), accessToken, advertiserID, req.PromotionIDs, req.OptStatus)
+	sdkReq := &promotion.StatusUpdateRequest{
+		LocalAccountID: advertiserID,
+		Data:           make([]promotion.StatusUpdateItem, 0, len(req.PromotionIDs)),
+	}
+	for _, id := range req.PromotionIDs {
+		sdkReq.Data = append(sdkReq.Data, promotion.StatusUpdateItem{PromotionID: id, OptStatus: req.OptStatus})
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localPromotion.StatusUpdate, sdkReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OK(c)
+	response.OKWithData(c, result)
 }
 
 // DeletePromotion 删除广告
@@ -322,7 +388,7 @@ func (h *LocalHandler) DeletePromotion(c *gin.Context) {
 
 	err := h.client.Local().DeletePromotion(c.Request.Context(), accessToken, advertiserID, []uint64{promotionID})
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -344,7 +410,7 @@ func (h *LocalHandler) GetClueList(c *gin.Context) {
 
 	list, total, err := h.client.Local().GetClueList(c.Request.Context(), accessToken, advertiserID, startDate, endDate, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -363,7 +429,7 @@ func (h *LocalHandler) GetClueDetail(c *gin.Context) {
 
 	detail, err := h.client.Local().GetClueDetail(c.Request.Context(), accessToken, advertiserID, clueID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -391,7 +457,7 @@ func (h *LocalHandler) UpdateClueStatus(c *gin.Context) {
 
 	err := h.client.Local().UpdateClueFollowStatus(c.Request.Context(), accessToken, advertiserID, clueID, req.FollowStatus, req.Remark)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -421,7 +487,7 @@ func (h *LocalHandler) ExportClues(c *gin.Context) {
 	// 获取线索报表数据作为导出数据源
 	list, total, err := h.client.Local().GetClueReport(c.Request.Context(), accessToken, advertiserID, req.StartDate, req.EndDate, 1, 1000)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -443,15 +509,21 @@ func (h *LocalHandler) GetProjectReport(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
 
-	list, err := h.client.Local().GetProjectReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
+	req := &report.ProjectGetRequest{
+		LocalAccountID: advertiserID,
+		StartDate:      c.Query("start_date"),
+		EndDate:        c.Query("end_date"),
+		Metrics:        reportMetrics(c),
+		Page:           page,
+		PageSize:       pageSize,
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localReport.ProjectGet, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(len(list)), page, pageSize)
+	response.OKWithList(c, result.ProjectList, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // GetPromotionReport 获取广告报表
@@ -465,15 +537,21 @@ func (h *LocalHandler) GetPromotionReport(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
 
-	list, err := h.client.Local().GetPromotionReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
+	req := &report.PromotionGetRequest{
+		LocalAccountID: advertiserID,
+		StartDate:      c.Query("start_date"),
+		EndDate:        c.Query("end_date"),
+		Metrics:        reportMetrics(c),
+		Page:           page,
+		PageSize:       pageSize,
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localReport.PromotionGet, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(len(list)), page, pageSize)
+	response.OKWithList(c, result.PromotionList, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // GetMaterialReport 获取素材报表
@@ -487,15 +565,21 @@ func (h *LocalHandler) GetMaterialReport(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
 
-	list, err := h.client.Local().GetMaterialReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
+	req := &report.MaterialGetRequest{
+		LocalAccountID: advertiserID,
+		StartDate:      c.Query("start_date"),
+		EndDate:        c.Query("end_date"),
+		Metrics:        reportMetrics(c),
+		Page:           page,
+		PageSize:       pageSize,
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localReport.MaterialGet, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(len(list)), page, pageSize)
+	response.OKWithList(c, result.MaterialList, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // GetMaterialList 获取素材列表
@@ -510,17 +594,23 @@ func (h *LocalHandler) GetMaterialList(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	list, total, err := h.client.Local().GetVideoList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
+	req := &file.VideoGetRequest{
+		LocalAccountID: advertiserID,
+		Page:           page,
+		PageSize:       pageSize,
+	}
+	result, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localFile.VideoGet, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
-	response.OKWithList(c, list, int64(total), page, pageSize)
+	response.OKWithList(c, result.VideoList, totalNumber(result.PageInfo), page, pageSize)
 }
 
 // UploadVideoRequest 上传视频请求
 type UploadVideoRequest struct {
 	VideoURL string `json:"video_url" binding:"required"`
+	Filename string `json:"filename"`
 }
 
 // UploadVideo 上传视频 (异步任务)
@@ -538,9 +628,14 @@ func (h *LocalHandler) UploadVideo(c *gin.Context) {
 		return
 	}
 
-	taskID, err := h.client.Local().CreateVideoUploadTask(c.Request.Context(), accessToken, advertiserID, req.VideoURL)
+	sdkReq := &file.UploadTaskCreateRequest{
+		LocalAccountID: advertiserID,
+		Filename:       req.Filename,
+		VideoURL:       req.VideoURL,
+	}
+	taskID, err := oceansdk.Call(h.sdkContext(c), h.sdk, advertiserID, localFile.UploadTaskCreate, sdkReq)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"task_id": taskID, "message": "视频上传任务已创建，请查询任务状态获取结果"})
@@ -576,7 +671,7 @@ func (h *LocalHandler) GetStoreList(c *gin.Context) {
 
 	list, total, err := h.client.Local().GetStoreList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
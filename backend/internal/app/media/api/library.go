@@ -0,0 +1,285 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oceanengine-backend/internal/app/media/dto"
+	"oceanengine-backend/internal/app/media/service"
+	"oceanengine-backend/internal/middleware"
+	"oceanengine-backend/pkg/response"
+)
+
+// LibraryAPI 素材库API
+type LibraryAPI struct {
+	libraryService *service.LibraryService
+}
+
+// NewLibraryAPI 创建素材库API
+func NewLibraryAPI(libraryService *service.LibraryService) *LibraryAPI {
+	return &LibraryAPI{libraryService: libraryService}
+}
+
+// List godoc
+// @Summary 获取素材库列表
+// @Tags 素材库
+// @Produce json
+// @Param material_type query string false "素材类型 image/video"
+// @Param folder_id query int false "文件夹ID，0 为根目录"
+// @Param owner_id query int false "上传人ID"
+// @Param tag query string false "标签"
+// @Param keyword query string false "关键词"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} response.Response{data=[]dto.LibraryResp}
+// @Router /api/v1/media/library [get]
+func (a *LibraryAPI) List(c *gin.Context) {
+	var req dto.LibraryListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	list, total, err := a.libraryService.List(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, list, total, req.GetPage(), req.GetPageSize())
+}
+
+// Upload godoc
+// @Summary 上传素材到素材库
+// @Tags 素材库
+// @Accept multipart/form-data
+// @Produce json
+// @Param material_type formData string true "素材类型 image/video"
+// @Param name formData string false "素材名称"
+// @Param folder_id formData int false "文件夹ID"
+// @Param tags formData []string false "标签"
+// @Param file formData file true "素材文件"
+// @Success 200 {object} response.Response{data=dto.LibraryResp}
+// @Router /api/v1/media/library [post]
+func (a *LibraryAPI) Upload(c *gin.Context) {
+	var req dto.LibraryUploadReq
+	if err := c.ShouldBind(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := a.libraryService.Upload(c.Request.Context(), uint64(middleware.GetUserID(c)), &req, header.Filename, file)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Get godoc
+// @Summary 获取素材库素材详情
+// @Tags 素材库
+// @Produce json
+// @Param id path int true "素材ID"
+// @Success 200 {object} response.Response{data=dto.LibraryDetailResp}
+// @Router /api/v1/media/library/{id} [get]
+func (a *LibraryAPI) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	result, err := a.libraryService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Update godoc
+// @Summary 更新素材库素材
+// @Tags 素材库
+// @Accept json
+// @Produce json
+// @Param id path int true "素材ID"
+// @Param request body dto.LibraryUpdateReq true "更新信息"
+// @Success 200 {object} response.Response{data=dto.LibraryResp}
+// @Router /api/v1/media/library/{id} [put]
+func (a *LibraryAPI) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req dto.LibraryUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := a.libraryService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Delete godoc
+// @Summary 删除素材库素材
+// @Tags 素材库
+// @Produce json
+// @Param id path int true "素材ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/media/library/{id} [delete]
+func (a *LibraryAPI) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := a.libraryService.Delete(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// Push godoc
+// @Summary 推送素材到广告主
+// @Tags 素材库
+// @Accept json
+// @Produce json
+// @Param id path int true "素材ID"
+// @Param request body dto.LibraryPushReq true "广告主ID列表"
+// @Success 200 {object} response.Response{data=dto.LibraryPushResp}
+// @Router /api/v1/media/library/{id}/push [post]
+func (a *LibraryAPI) Push(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req dto.LibraryPushReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := a.libraryService.Push(c.Request.Context(), id, req.AdvertiserIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ListFolders godoc
+// @Summary 获取素材库文件夹
+// @Tags 素材库
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.LibraryFolder}
+// @Router /api/v1/media/folders [get]
+func (a *LibraryAPI) ListFolders(c *gin.Context) {
+	folders, err := a.libraryService.ListFolders(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, folders)
+}
+
+// CreateFolder godoc
+// @Summary 创建素材库文件夹
+// @Tags 素材库
+// @Accept json
+// @Produce json
+// @Param request body dto.FolderCreateReq true "文件夹信息"
+// @Success 200 {object} response.Response{data=model.LibraryFolder}
+// @Router /api/v1/media/folders [post]
+func (a *LibraryAPI) CreateFolder(c *gin.Context) {
+	var req dto.FolderCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	folder, err := a.libraryService.CreateFolder(c.Request.Context(), uint64(middleware.GetUserID(c)), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, folder)
+}
+
+// UpdateFolder godoc
+// @Summary 更新素材库文件夹
+// @Tags 素材库
+// @Accept json
+// @Produce json
+// @Param id path int true "文件夹ID"
+// @Param request body dto.FolderUpdateReq true "文件夹信息"
+// @Success 200 {object} response.Response{data=model.LibraryFolder}
+// @Router /api/v1/media/folders/{id} [put]
+func (a *LibraryAPI) UpdateFolder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	var req dto.FolderUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	folder, err := a.libraryService.UpdateFolder(c.Request.Context(), id, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, folder)
+}
+
+// DeleteFolder godoc
+// @Summary 删除素材库文件夹
+// @Tags 素材库
+// @Produce json
+// @Param id path int true "文件夹ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/media/folders/{id} [delete]
+func (a *LibraryAPI) DeleteFolder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	if err := a.libraryService.DeleteFolder(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"oceanengine-backend/internal/app/media/dto"
 	"oceanengine-backend/internal/app/media/service"
+	"oceanengine-backend/pkg/mediaprobe"
 	"oceanengine-backend/pkg/response"
 )
 
@@ -230,3 +231,39 @@ func (a *MediaAPI) UploadVideo(c *gin.Context) {
 
 	response.Success(c, result)
 }
+
+// Probe godoc
+// @Summary 检查素材规格
+// @Description 本地解析素材的宽高、时长、编码与码率，按投放平台规格返回不符合项，不上传到巨量
+// @Tags 素材管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param platform formData string false "投放平台 oceanengine/qianchuan，默认 oceanengine"
+// @Param file formData file true "素材文件"
+// @Success 200 {object} response.Response{data=dto.ProbeResp}
+// @Router /api/v1/media/probe [post]
+func (a *MediaAPI) Probe(c *gin.Context) {
+	var req dto.ProbeReq
+	if err := c.ShouldBind(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if req.Platform == "" {
+		req.Platform = mediaprobe.PlatformOceanEngine
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := a.mediaService.Probe(req.Platform, file, header.Size)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,94 @@
+package dto
+
+import "oceanengine-backend/pkg/utils"
+
+// LibraryUploadReq 素材库上传请求 (multipart/form-data，文件字段为 file)
+type LibraryUploadReq struct {
+	MaterialType string   `form:"material_type" binding:"required,oneof=image video"`
+	Name         string   `form:"name" binding:"max=255"` // 为空时使用文件名
+	FolderID     uint64   `form:"folder_id"`
+	Tags         []string `form:"tags" binding:"max=20,dive,max=50"`
+}
+
+// LibraryListReq 素材库列表请求
+type LibraryListReq struct {
+	utils.Pagination
+	MaterialType string  `form:"material_type" binding:"omitempty,oneof=image video"`
+	FolderID     *uint64 `form:"folder_id"` // 为空时不按文件夹过滤，0 为根目录
+	OwnerID      uint64  `form:"owner_id"`
+	Tag          string  `form:"tag"`
+	Keyword      string  `form:"keyword"`
+}
+
+// LibraryUpdateReq 更新素材库素材请求，字段为空时不修改
+type LibraryUpdateReq struct {
+	Name     string   `json:"name" binding:"max=255"`
+	FolderID *uint64  `json:"folder_id"`
+	Tags     []string `json:"tags" binding:"max=20,dive,max=50"` // 传空数组清空标签
+}
+
+// LibraryResp 素材库素材
+type LibraryResp struct {
+	ID           uint64   `json:"id"`
+	MaterialType string   `json:"material_type"`
+	Name         string   `json:"name"`
+	FolderID     uint64   `json:"folder_id"`
+	OwnerID      uint64   `json:"owner_id"`
+	Filename     string   `json:"filename"`
+	Size         int64    `json:"size"`
+	Format       string   `json:"format"`
+	Signature    string   `json:"signature"`
+	Tags         []string `json:"tags"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// LibraryDetailResp 素材库素材详情，包含推送到各广告主的映射
+type LibraryDetailResp struct {
+	LibraryResp
+	Bindings []*LibraryBindingResp `json:"bindings"`
+}
+
+// LibraryBindingResp 素材在广告主下的巨量素材ID与同步状态
+type LibraryBindingResp struct {
+	AdvertiserID uint64 `json:"advertiser_id"`
+	MediaID      uint64 `json:"media_id"`    // 广告主图片/视频素材记录ID
+	MaterialID   string `json:"material_id"` // 巨量图片ID/视频ID
+	Method       string `json:"method"`      // REUSE, BIND, UPLOAD
+	Status       string `json:"status"`
+	ErrorMsg     string `json:"error_msg"`
+	SyncedAt     string `json:"synced_at"`
+}
+
+// LibraryPushReq 推送素材到广告主请求
+type LibraryPushReq struct {
+	AdvertiserIDs []uint64 `json:"advertiser_ids" binding:"required,min=1,max=200"`
+}
+
+// LibraryPushResp 推送素材到广告主响应
+// 推送失败的广告主在 failures 中返回原因，未同步的素材由定时任务继续推送
+type LibraryPushResp struct {
+	Total    int                   `json:"total"`
+	Success  int                   `json:"success"`
+	Failed   int                   `json:"failed"`
+	Bindings []*LibraryBindingResp `json:"bindings"`
+	Failures []LibraryPushFailure  `json:"failures"`
+}
+
+// LibraryPushFailure 单个广告主推送失败明细
+type LibraryPushFailure struct {
+	AdvertiserID uint64 `json:"advertiser_id"`
+	Code         int    `json:"code"`
+	Message      string `json:"message"`
+}
+
+// FolderCreateReq 创建文件夹请求
+type FolderCreateReq struct {
+	ParentID uint64 `json:"parent_id"`
+	Name     string `json:"name" binding:"required,max=100"`
+}
+
+// FolderUpdateReq 更新文件夹请求
+type FolderUpdateReq struct {
+	ParentID *uint64 `json:"parent_id"`
+	Name     string  `json:"name" binding:"max=100"`
+}
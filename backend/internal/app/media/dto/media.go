@@ -1,6 +1,9 @@
 package dto
 
-import "oceanengine-backend/pkg/utils"
+import (
+	"oceanengine-backend/pkg/mediaprobe"
+	"oceanengine-backend/pkg/utils"
+)
 
 // ImageListReq 图片列表请求
 type ImageListReq struct {
@@ -27,6 +30,8 @@ type ImageListResp struct {
 	Height       int    `json:"height"`
 	Format       string `json:"format"`
 	URL          string `json:"url"`
+	Status       string `json:"status"`
+	ErrorMsg     string `json:"error_msg"`
 	CreatedAt    string `json:"created_at"`
 }
 
@@ -55,6 +60,8 @@ type VideoListResp struct {
 	Format       string  `json:"format"`
 	URL          string  `json:"url"`
 	PosterURL    string  `json:"poster_url"`
+	Status       string  `json:"status"`
+	ErrorMsg     string  `json:"error_msg"`
 	CreatedAt    string  `json:"created_at"`
 }
 
@@ -66,23 +73,45 @@ type MaterialDeleteReq struct {
 }
 
 // ImageUploadResp 图片上传响应
+// status 非 SYNCED 时 image_id 为空，素材由定时任务继续推送，可通过详情接口查询同步结果
 type ImageUploadResp struct {
-	ImageID string `json:"image_id"`
-	URL     string `json:"url"`
-	Width   int    `json:"width"`
-	Height  int    `json:"height"`
-	Size    int64  `json:"size"`
-	Format  string `json:"format"`
+	ID       uint64 `json:"id"`
+	ImageID  string `json:"image_id"`
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Size     int64  `json:"size"`
+	Format   string `json:"format"`
+	Status   string `json:"status"`
+	ErrorMsg string `json:"error_msg"`
 }
 
 // VideoUploadResp 视频上传响应
+// status 非 SYNCED 时 video_id 为空，同 ImageUploadResp
 type VideoUploadResp struct {
+	ID        uint64  `json:"id"`
 	VideoID   string  `json:"video_id"`
 	URL       string  `json:"url"`
 	Width     int     `json:"width"`
 	Height    int     `json:"height"`
 	Duration  float64 `json:"duration"`
+	BitRate   int     `json:"bit_rate"`
 	Size      int64   `json:"size"`
 	Format    string  `json:"format"`
 	PosterURL string  `json:"poster_url"`
+	Status    string  `json:"status"`
+	ErrorMsg  string  `json:"error_msg"`
+}
+
+// ProbeReq 素材规格检查请求 (multipart/form-data，文件字段为 file)
+type ProbeReq struct {
+	Platform string `form:"platform" binding:"omitempty,oneof=oceanengine qianchuan"` // 默认 oceanengine
+}
+
+// ProbeResp 素材规格检查响应
+// violations 为空表示符合规格
+type ProbeResp struct {
+	Info       *mediaprobe.Info       `json:"info"`
+	Spec       string                 `json:"spec"` // 适用的规格，如 巨量广告竖版视频
+	Violations []mediaprobe.Violation `json:"violations"`
 }
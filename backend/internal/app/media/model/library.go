@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+)
+
+// LibraryFolder 素材库文件夹
+type LibraryFolder struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	ParentID  uint64    `gorm:"index;default:0" json:"parent_id"` // 上级文件夹，0 为根目录
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	OwnerID   uint64    `gorm:"index;default:0" json:"owner_id"` // 创建人
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (LibraryFolder) TableName() string {
+	return "ad_material_folder"
+}
+
+// LibraryMaterial 素材库素材
+// 组织级素材，不属于单个广告主；按类型 + MD5 签名去重，推送到广告主后的巨量素材ID记录在 LibraryBinding
+type LibraryMaterial struct {
+	ID           uint64    `gorm:"primaryKey" json:"id"`
+	MaterialType string    `gorm:"size:16;uniqueIndex:uk_library_signature;not null" json:"material_type"` // image, video
+	Signature    string    `gorm:"size:100;uniqueIndex:uk_library_signature;not null" json:"signature"`    // MD5签名
+	Name         string    `gorm:"size:255" json:"name"`
+	FolderID     uint64    `gorm:"index;default:0" json:"folder_id"` // 所在文件夹，0 为根目录
+	OwnerID      uint64    `gorm:"index;default:0" json:"owner_id"`  // 上传人
+	Filename     string    `gorm:"size:255" json:"filename"`         // 原始文件名
+	Size         int64     `gorm:"default:0" json:"size"`            // 文件大小（字节）
+	Format       string    `gorm:"size:20" json:"format"`            // 格式
+	StorageKey   string    `gorm:"size:255" json:"-"`                // 原始文件存储路径
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (LibraryMaterial) TableName() string {
+	return "ad_material_library"
+}
+
+// LibraryTag 素材库素材标签
+type LibraryTag struct {
+	ID        uint64 `gorm:"primaryKey" json:"id"`
+	LibraryID uint64 `gorm:"uniqueIndex:uk_library_tag;not null" json:"library_id"`
+	Tag       string `gorm:"size:50;uniqueIndex:uk_library_tag;index;not null" json:"tag"`
+}
+
+// TableName 表名
+func (LibraryTag) TableName() string {
+	return "ad_material_library_tag"
+}
+
+// LibraryBinding 素材库素材与广告主巨量素材的映射
+// 每个广告主对应一条图片/视频素材记录 (MediaID)，同步状态随该记录推送结果更新
+type LibraryBinding struct {
+	ID           uint64     `gorm:"primaryKey" json:"id"`
+	LibraryID    uint64     `gorm:"uniqueIndex:uk_library_binding;not null" json:"library_id"`
+	AdvertiserID uint64     `gorm:"uniqueIndex:uk_library_binding;not null" json:"advertiser_id"` // 广告主ID
+	MaterialType string     `gorm:"size:16;index:idx_library_binding_media" json:"material_type"`
+	MediaID      uint64     `gorm:"index:idx_library_binding_media" json:"media_id"` // ad_material_image/ad_material_video ID
+	MaterialID   string     `gorm:"size:100" json:"material_id"`                     // 巨量图片ID/视频ID，同步成功前为空
+	Method       string     `gorm:"size:16" json:"method"`                           // 推送方式
+	Status       string     `gorm:"size:16;index;default:'PENDING'" json:"status"`   // 同步状态，取值同 MaterialStatus*
+	ErrorMsg     string     `gorm:"size:500" json:"error_msg"`
+	SyncedAt     *time.Time `json:"synced_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (LibraryBinding) TableName() string {
+	return "ad_material_library_binding"
+}
+
+// 素材推送方式
+// REUSE 广告主已有相同签名的素材；BIND 通过素材推送接口从同主体广告主推送；UPLOAD 上传原始文件
+const (
+	BindMethodReuse  = "REUSE"
+	BindMethodBind   = "BIND"
+	BindMethodUpload = "UPLOAD"
+)
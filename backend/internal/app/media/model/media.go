@@ -7,18 +7,25 @@ import (
 )
 
 // MaterialImage 图片素材表
+// 原始文件保存在存储中，推送到巨量成功 (SYNCED) 后才有 image_id，推送失败的素材由定时任务重试
+// 巨量素材ID可通过素材推送在同主体广告主间共享，按广告主唯一
 type MaterialImage struct {
 	ID           uint64         `gorm:"primaryKey" json:"id"`
-	ImageID      string         `gorm:"size:100;uniqueIndex" json:"image_id"` // Ocean Engine 图片ID
-	AdvertiserID uint64         `gorm:"index" json:"advertiser_id"`           // 广告主ID
-	Filename     string         `gorm:"size:255" json:"filename"`             // 文件名
-	Size         int64          `gorm:"default:0" json:"size"`                // 文件大小（字节）
-	Width        int            `gorm:"default:0" json:"width"`               // 宽度
-	Height       int            `gorm:"default:0" json:"height"`              // 高度
-	Format       string         `gorm:"size:20" json:"format"`                // 格式
-	URL          string         `gorm:"size:500" json:"url"`                  // 图片URL
-	MaterialID   string         `gorm:"size:100" json:"material_id"`          // 素材ID
-	Signature    string         `gorm:"size:100" json:"signature"`            // MD5签名
+	ImageID      *string        `gorm:"size:100;uniqueIndex:uk_material_image_adv,priority:2" json:"image_id"`   // Ocean Engine 图片ID，同步成功前为空
+	AdvertiserID uint64         `gorm:"index;uniqueIndex:uk_material_image_adv,priority:1" json:"advertiser_id"` // 广告主ID
+	Filename     string         `gorm:"size:255" json:"filename"`                                                // 文件名
+	Size         int64          `gorm:"default:0" json:"size"`                                                   // 文件大小（字节）
+	Width        int            `gorm:"default:0" json:"width"`                                                  // 宽度
+	Height       int            `gorm:"default:0" json:"height"`                                                 // 高度
+	Format       string         `gorm:"size:20" json:"format"`                                                   // 格式
+	URL          string         `gorm:"size:500" json:"url"`                                                     // 图片URL
+	MaterialID   string         `gorm:"size:100" json:"material_id"`                                             // 素材ID
+	Signature    string         `gorm:"size:100" json:"signature"`                                               // MD5签名
+	StorageKey   string         `gorm:"size:255" json:"-"`                                                       // 原始文件存储路径
+	Status       string         `gorm:"size:16;index;default:'PENDING'" json:"status"`                           // 同步状态
+	Attempts     int            `gorm:"default:0" json:"attempts"`                                               // 推送次数
+	ErrorMsg     string         `gorm:"size:500" json:"error_msg"`                                               // 最近一次推送失败原因
+	SyncedAt     *time.Time     `json:"synced_at"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -30,21 +37,27 @@ func (MaterialImage) TableName() string {
 }
 
 // MaterialVideo 视频素材表
+// 同步状态与 MaterialImage 一致
 type MaterialVideo struct {
 	ID           uint64         `gorm:"primaryKey" json:"id"`
-	VideoID      string         `gorm:"size:100;uniqueIndex" json:"video_id"` // Ocean Engine 视频ID
-	AdvertiserID uint64         `gorm:"index" json:"advertiser_id"`           // 广告主ID
-	Filename     string         `gorm:"size:255" json:"filename"`             // 文件名
-	Size         int64          `gorm:"default:0" json:"size"`                // 文件大小（字节）
-	Width        int            `gorm:"default:0" json:"width"`               // 宽度
-	Height       int            `gorm:"default:0" json:"height"`              // 高度
-	Duration     float64        `gorm:"default:0" json:"duration"`            // 时长（秒）
-	Format       string         `gorm:"size:20" json:"format"`                // 格式
-	URL          string         `gorm:"size:500" json:"url"`                  // 视频URL
-	PosterURL    string         `gorm:"size:500" json:"poster_url"`           // 封面URL
-	MaterialID   string         `gorm:"size:100" json:"material_id"`          // 素材ID
-	Signature    string         `gorm:"size:100" json:"signature"`            // MD5签名
-	BitRate      int            `gorm:"default:0" json:"bit_rate"`            // 比特率
+	VideoID      *string        `gorm:"size:100;uniqueIndex:uk_material_video_adv,priority:2" json:"video_id"`   // Ocean Engine 视频ID，同步成功前为空
+	AdvertiserID uint64         `gorm:"index;uniqueIndex:uk_material_video_adv,priority:1" json:"advertiser_id"` // 广告主ID
+	Filename     string         `gorm:"size:255" json:"filename"`                                                // 文件名
+	Size         int64          `gorm:"default:0" json:"size"`                                                   // 文件大小（字节）
+	Width        int            `gorm:"default:0" json:"width"`                                                  // 宽度
+	Height       int            `gorm:"default:0" json:"height"`                                                 // 高度
+	Duration     float64        `gorm:"default:0" json:"duration"`                                               // 时长（秒）
+	Format       string         `gorm:"size:20" json:"format"`                                                   // 格式
+	URL          string         `gorm:"size:500" json:"url"`                                                     // 视频URL
+	PosterURL    string         `gorm:"size:500" json:"poster_url"`                                              // 封面URL
+	MaterialID   string         `gorm:"size:100" json:"material_id"`                                             // 素材ID
+	Signature    string         `gorm:"size:100" json:"signature"`                                               // MD5签名
+	BitRate      int            `gorm:"default:0" json:"bit_rate"`                                               // 比特率
+	StorageKey   string         `gorm:"size:255" json:"-"`                                                       // 原始文件存储路径
+	Status       string         `gorm:"size:16;index;default:'PENDING'" json:"status"`                           // 同步状态
+	Attempts     int            `gorm:"default:0" json:"attempts"`                                               // 推送次数
+	ErrorMsg     string         `gorm:"size:500" json:"error_msg"`                                               // 最近一次推送失败原因
+	SyncedAt     *time.Time     `json:"synced_at"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -55,6 +68,15 @@ func (MaterialVideo) TableName() string {
 	return "ad_material_video"
 }
 
+// 素材同步状态
+// PENDING 已保存原始文件，等待推送；UPLOADING 推送中；SYNCED 已上传到巨量；FAILED 推送失败，未超过最大次数时由定时任务重试
+const (
+	MaterialStatusPending   = "PENDING"
+	MaterialStatusUploading = "UPLOADING"
+	MaterialStatusSynced    = "SYNCED"
+	MaterialStatusFailed    = "FAILED"
+)
+
 // 素材类型常量
 const (
 	MaterialTypeImage = "image"
@@ -0,0 +1,732 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/file"
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	fileModel "github.com/bububa/oceanengine/marketing-api/model/file"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/media/dto"
+	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/fanout"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+// LibraryService 素材库服务
+// 素材库为组织级素材，推送到广告主时依次尝试：复用广告主已有的相同签名素材、从同主体广告主推送 (file.MaterialBind)、上传原始文件
+type LibraryService struct {
+	db     *gorm.DB
+	media  *MediaService
+	sdk    *oceansdk.Client
+	fanout *fanout.Executor
+}
+
+// NewLibraryService 创建素材库服务
+// sdk 为空时不使用素材推送接口，全部上传原始文件
+func NewLibraryService(db *gorm.DB, media *MediaService, sdk *oceansdk.Client, executor *fanout.Executor) *LibraryService {
+	if executor == nil {
+		executor = fanout.New(nil)
+	}
+	return &LibraryService{
+		db:     db,
+		media:  media,
+		sdk:    sdk,
+		fanout: executor,
+	}
+}
+
+// Upload 上传素材到素材库
+// 相同类型与 MD5 签名的素材已存在时返回已有素材，不重复保存
+func (s *LibraryService) Upload(ctx context.Context, ownerID uint64, req *dto.LibraryUploadReq, filename string, file io.Reader) (*dto.LibraryResp, error) {
+	if s.media.storage == nil {
+		return nil, errcode.NewWithMessage(errcode.ErrMaterialUploadFail, "素材存储未配置")
+	}
+	if err := s.checkFolder(ctx, req.FolderID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	// 素材库不区分投放平台，只校验文件类型，推送到广告主时再按平台规格上传
+	info, err := probe(req.MaterialType, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	signature := fmt.Sprintf("%x", md5.Sum(data))
+
+	var item model.LibraryMaterial
+	err = s.db.WithContext(ctx).Where("material_type = ? AND signature = ?", req.MaterialType, signature).First(&item).Error
+	if err == nil {
+		return s.toResp(ctx, &item)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	key := fmt.Sprintf("materials/library/%s/%s%s", req.MaterialType, signature, ext)
+	if _, err := s.media.storage.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, errcode.WrapWithMessage(errcode.ErrMaterialUploadFail, "保存素材原始文件失败", err)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = filename
+	}
+	item = model.LibraryMaterial{
+		MaterialType: req.MaterialType,
+		Signature:    signature,
+		Name:         name,
+		FolderID:     req.FolderID,
+		OwnerID:      ownerID,
+		Filename:     filename,
+		Size:         int64(len(data)),
+		Format:       info.Format,
+		StorageKey:   key,
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		return replaceTags(tx, item.ID, req.Tags)
+	}); err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return s.toResp(ctx, &item)
+}
+
+// List 获取素材库列表
+func (s *LibraryService) List(ctx context.Context, req *dto.LibraryListReq) ([]*dto.LibraryResp, int64, error) {
+	var items []*model.LibraryMaterial
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&model.LibraryMaterial{})
+	if req.MaterialType != "" {
+		query = query.Where("material_type = ?", req.MaterialType)
+	}
+	if req.FolderID != nil {
+		query = query.Where("folder_id = ?", *req.FolderID)
+	}
+	if req.OwnerID > 0 {
+		query = query.Where("owner_id = ?", req.OwnerID)
+	}
+	if req.Tag != "" {
+		query = query.Where("id IN (?)", s.db.Model(&model.LibraryTag{}).Select("library_id").Where("tag = ?", req.Tag))
+	}
+	if req.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+req.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	offset := (req.GetPage() - 1) * req.GetPageSize()
+	if err := query.Offset(offset).Limit(req.GetPageSize()).Order("id DESC").Find(&items).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	ids := make([]uint64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	tags, err := s.tags(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*dto.LibraryResp, len(items))
+	for i, item := range items {
+		result[i] = libraryResp(item, tags[item.ID])
+	}
+	return result, total, nil
+}
+
+// Get 获取素材库素材详情，包含当前用户有权限的广告主映射
+func (s *LibraryService) Get(ctx context.Context, id uint64) (*dto.LibraryDetailResp, error) {
+	item, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.toResp(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	var bindings []*model.LibraryBinding
+	if err := s.db.WithContext(ctx).Scopes(datascope.Scope(ctx, "advertiser_id")).
+		Where("library_id = ?", id).Order("id ASC").Find(&bindings).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &dto.LibraryDetailResp{LibraryResp: *resp, Bindings: bindingResps(bindings)}, nil
+}
+
+// Update 更新素材库素材名称、文件夹与标签
+func (s *LibraryService) Update(ctx context.Context, id uint64, req *dto.LibraryUpdateReq) (*dto.LibraryResp, error) {
+	item, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.FolderID != nil {
+		if err := s.checkFolder(ctx, *req.FolderID); err != nil {
+			return nil, err
+		}
+		updates["folder_id"] = *req.FolderID
+	}
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(item).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			return replaceTags(tx, id, req.Tags)
+		}
+		return nil
+	}); err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	item, err = s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResp(ctx, item)
+}
+
+// Delete 删除素材库素材及其标签与广告主映射
+// 已推送到广告主的素材记录保留；原始文件没有被广告主素材引用时一并删除
+func (s *LibraryService) Delete(ctx context.Context, id uint64) error {
+	item, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("library_id = ?", id).Delete(&model.LibraryTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("library_id = ?", id).Delete(&model.LibraryBinding{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(item).Error
+	}); err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	var table interface{} = &model.MaterialImage{}
+	if item.MaterialType == model.MaterialTypeVideo {
+		table = &model.MaterialVideo{}
+	}
+	var refs int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(table).Where("storage_key = ?", item.StorageKey).Count(&refs).Error; err == nil && refs == 0 {
+		_ = s.media.storage.Delete(ctx, item.StorageKey)
+	}
+	return nil
+}
+
+// ListFolders 获取全部文件夹，由前端按 parent_id 组装目录树
+func (s *LibraryService) ListFolders(ctx context.Context) ([]*model.LibraryFolder, error) {
+	var folders []*model.LibraryFolder
+	if err := s.db.WithContext(ctx).Order("parent_id ASC, name ASC").Find(&folders).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return folders, nil
+}
+
+// CreateFolder 创建文件夹
+func (s *LibraryService) CreateFolder(ctx context.Context, ownerID uint64, req *dto.FolderCreateReq) (*model.LibraryFolder, error) {
+	if err := s.checkFolder(ctx, req.ParentID); err != nil {
+		return nil, err
+	}
+	folder := &model.LibraryFolder{ParentID: req.ParentID, Name: req.Name, OwnerID: ownerID}
+	if err := s.db.WithContext(ctx).Create(folder).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return folder, nil
+}
+
+// UpdateFolder 重命名或移动文件夹，不能移动到自身或其子文件夹下
+func (s *LibraryService) UpdateFolder(ctx context.Context, id uint64, req *dto.FolderUpdateReq) (*model.LibraryFolder, error) {
+	folder, err := s.getFolder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.ParentID != nil {
+		// 从新的上级文件夹向上查找，经过自身说明形成了环
+		for parentID := *req.ParentID; parentID != 0; {
+			if parentID == id {
+				return nil, errcode.NewWithMessage(errcode.ErrInvalidParams, "不能移动到自身或子文件夹下")
+			}
+			parent, err := s.getFolder(ctx, parentID)
+			if err != nil {
+				return nil, err
+			}
+			parentID = parent.ParentID
+		}
+		updates["parent_id"] = *req.ParentID
+	}
+	if len(updates) == 0 {
+		return folder, nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(folder).Updates(updates).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return s.getFolder(ctx, id)
+}
+
+// DeleteFolder 删除空文件夹
+func (s *LibraryService) DeleteFolder(ctx context.Context, id uint64) error {
+	folder, err := s.getFolder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var children, items int64
+	if err := s.db.WithContext(ctx).Model(&model.LibraryFolder{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if err := s.db.WithContext(ctx).Model(&model.LibraryMaterial{}).Where("folder_id = ?", id).Count(&items).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if children > 0 || items > 0 {
+		return errcode.NewWithMessage(errcode.ErrInvalidParams, "文件夹不为空")
+	}
+
+	if err := s.db.WithContext(ctx).Delete(folder).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// Push 并发推送素材到多个广告主
+// 单个广告主失败不影响其他广告主，数据权限沿用请求上下文逐个校验；上传未完成的素材由定时任务继续推送
+func (s *LibraryService) Push(ctx context.Context, id uint64, advertiserIDs []uint64) (*dto.LibraryPushResp, error) {
+	item, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := s.fanout.Run(ctx, advertiserIDs, func(ctx context.Context, advertiserID uint64) error {
+		return s.push(ctx, item, advertiserID)
+	})
+
+	failures := make([]dto.LibraryPushFailure, len(summary.Failures))
+	for i, f := range summary.Failures {
+		failures[i] = dto.LibraryPushFailure{AdvertiserID: f.AdvertiserID, Code: errcode.GetCode(f.Err), Message: errcode.Message(errcode.ErrUnknown)}
+		var appErr *errcode.AppError
+		if errors.As(f.Err, &appErr) {
+			failures[i].Message = appErr.Message
+		}
+	}
+
+	var bindings []*model.LibraryBinding
+	if err := s.db.WithContext(ctx).Scopes(datascope.Scope(ctx, "advertiser_id")).
+		Where("library_id = ? AND advertiser_id IN ?", id, advertiserIDs).Order("id ASC").Find(&bindings).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	return &dto.LibraryPushResp{
+		Total:    summary.Total,
+		Success:  summary.Success,
+		Failed:   summary.Failed,
+		Bindings: bindingResps(bindings),
+		Failures: failures,
+	}, nil
+}
+
+// push 推送素材到单个广告主
+func (s *LibraryService) push(ctx context.Context, item *model.LibraryMaterial, advertiserID uint64) error {
+	adv, err := s.media.advRepo.GetByID(ctx, advertiserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errcode.New(errcode.ErrAdvertiserNotFound)
+		}
+		return err
+	}
+	if item.MaterialType == model.MaterialTypeVideo {
+		return s.pushVideo(ctx, item, adv)
+	}
+	return s.pushImage(ctx, item, adv)
+}
+
+// pushImage 推送图片到广告主
+func (s *LibraryService) pushImage(ctx context.Context, item *model.LibraryMaterial, adv *advModel.Advertiser) error {
+	var image model.MaterialImage
+	err := s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", adv.ID, item.Signature).First(&image).Error
+	method := model.BindMethodReuse
+	switch {
+	case err == nil:
+		if image.Status != model.MaterialStatusSynced {
+			method = model.BindMethodUpload
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if bound := s.bindImage(ctx, item, adv); bound != nil {
+			image, method = *bound, model.BindMethodBind
+		} else {
+			image = model.MaterialImage{
+				AdvertiserID: adv.ID,
+				Filename:     item.Filename,
+				Size:         item.Size,
+				Format:       item.Format,
+				Signature:    item.Signature,
+				StorageKey:   item.StorageKey,
+				Status:       model.MaterialStatusPending,
+			}
+			method = model.BindMethodUpload
+		}
+		if err := s.db.WithContext(ctx).Create(&image).Error; err != nil {
+			return errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+	default:
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	if err := s.saveBinding(ctx, &model.LibraryBinding{
+		LibraryID:    item.ID,
+		AdvertiserID: adv.ID,
+		MaterialType: model.MaterialTypeImage,
+		MediaID:      image.ID,
+		MaterialID:   stringValue(image.ImageID),
+		Method:       method,
+		Status:       image.Status,
+		ErrorMsg:     image.ErrorMsg,
+		SyncedAt:     image.SyncedAt,
+	}); err != nil {
+		return err
+	}
+	if image.Status == model.MaterialStatusSynced {
+		return nil
+	}
+
+	if image.Status == model.MaterialStatusFailed {
+		key := image.StorageKey
+		if key == "" {
+			key = item.StorageKey
+		}
+		if err := s.media.reset(ctx, &model.MaterialImage{}, model.MaterialTypeImage, image.ID, key); err != nil {
+			return err
+		}
+		image.StorageKey, image.Status, image.Attempts = key, model.MaterialStatusPending, 0
+	}
+	return s.media.syncImage(ctx, &image)
+}
+
+// pushVideo 推送视频到广告主，处理流程同 pushImage
+func (s *LibraryService) pushVideo(ctx context.Context, item *model.LibraryMaterial, adv *advModel.Advertiser) error {
+	var video model.MaterialVideo
+	err := s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", adv.ID, item.Signature).First(&video).Error
+	method := model.BindMethodReuse
+	switch {
+	case err == nil:
+		if video.Status != model.MaterialStatusSynced {
+			method = model.BindMethodUpload
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if bound := s.bindVideo(ctx, item, adv); bound != nil {
+			video, method = *bound, model.BindMethodBind
+		} else {
+			video = model.MaterialVideo{
+				AdvertiserID: adv.ID,
+				Filename:     item.Filename,
+				Size:         item.Size,
+				Format:       item.Format,
+				Signature:    item.Signature,
+				StorageKey:   item.StorageKey,
+				Status:       model.MaterialStatusPending,
+			}
+			method = model.BindMethodUpload
+		}
+		if err := s.db.WithContext(ctx).Create(&video).Error; err != nil {
+			return errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+	default:
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	if err := s.saveBinding(ctx, &model.LibraryBinding{
+		LibraryID:    item.ID,
+		AdvertiserID: adv.ID,
+		MaterialType: model.MaterialTypeVideo,
+		MediaID:      video.ID,
+		MaterialID:   stringValue(video.VideoID),
+		Method:       method,
+		Status:       video.Status,
+		ErrorMsg:     video.ErrorMsg,
+		SyncedAt:     video.SyncedAt,
+	}); err != nil {
+		return err
+	}
+	if video.Status == model.MaterialStatusSynced {
+		return nil
+	}
+
+	if video.Status == model.MaterialStatusFailed {
+		key := video.StorageKey
+		if key == "" {
+			key = item.StorageKey
+		}
+		if err := s.media.reset(ctx, &model.MaterialVideo{}, model.MaterialTypeVideo, video.ID, key); err != nil {
+			return err
+		}
+		video.StorageKey, video.Status, video.Attempts = key, model.MaterialStatusPending, 0
+	}
+	return s.media.syncVideo(ctx, &video)
+}
+
+// bindImage 从同主体且已同步该图片的广告主推送到目标广告主，返回待创建的已同步记录
+// 没有可用的来源广告主或推送失败时返回 nil，由调用方改为上传
+func (s *LibraryService) bindImage(ctx context.Context, item *model.LibraryMaterial, target *advModel.Advertiser) *model.MaterialImage {
+	var sources []*model.MaterialImage
+	if err := s.db.WithContext(ctx).
+		Where("signature = ? AND status = ? AND advertiser_id <> ?", item.Signature, model.MaterialStatusSynced, target.ID).
+		Find(&sources).Error; err != nil {
+		return nil
+	}
+	for _, src := range sources {
+		source := s.bindSource(ctx, target, src.AdvertiserID)
+		if source == nil || src.ImageID == nil {
+			continue
+		}
+		if !s.materialBind(ctx, source, target, &fileModel.MaterialBindRequest{ImageIDs: []string{*src.ImageID}}) {
+			return nil
+		}
+		now := time.Now()
+		return &model.MaterialImage{
+			ImageID:      src.ImageID,
+			AdvertiserID: target.ID,
+			Filename:     item.Filename,
+			Size:         item.Size,
+			Width:        src.Width,
+			Height:       src.Height,
+			Format:       item.Format,
+			URL:          src.URL,
+			Signature:    item.Signature,
+			StorageKey:   item.StorageKey,
+			Status:       model.MaterialStatusSynced,
+			SyncedAt:     &now,
+		}
+	}
+	return nil
+}
+
+// bindVideo 从同主体且已同步该视频的广告主推送到目标广告主，同 bindImage
+func (s *LibraryService) bindVideo(ctx context.Context, item *model.LibraryMaterial, target *advModel.Advertiser) *model.MaterialVideo {
+	var sources []*model.MaterialVideo
+	if err := s.db.WithContext(ctx).
+		Where("signature = ? AND status = ? AND advertiser_id <> ?", item.Signature, model.MaterialStatusSynced, target.ID).
+		Find(&sources).Error; err != nil {
+		return nil
+	}
+	for _, src := range sources {
+		source := s.bindSource(ctx, target, src.AdvertiserID)
+		if source == nil || src.VideoID == nil {
+			continue
+		}
+		if !s.materialBind(ctx, source, target, &fileModel.MaterialBindRequest{VideoIDs: []string{*src.VideoID}}) {
+			return nil
+		}
+		now := time.Now()
+		return &model.MaterialVideo{
+			VideoID:      src.VideoID,
+			AdvertiserID: target.ID,
+			Filename:     item.Filename,
+			Size:         item.Size,
+			Width:        src.Width,
+			Height:       src.Height,
+			Duration:     src.Duration,
+			Format:       item.Format,
+			URL:          src.URL,
+			PosterURL:    src.PosterURL,
+			Signature:    item.Signature,
+			StorageKey:   item.StorageKey,
+			Status:       model.MaterialStatusSynced,
+			SyncedAt:     &now,
+		}
+	}
+	return nil
+}
+
+// bindSource 素材推送只能在同主体广告主间进行，来源广告主与目标主体不同时返回 nil
+func (s *LibraryService) bindSource(ctx context.Context, target *advModel.Advertiser, sourceID uint64) *advModel.Advertiser {
+	if s.sdk == nil || target.Company == "" {
+		return nil
+	}
+	var source advModel.Advertiser
+	if err := s.db.WithContext(ctx).Where("id = ? AND company = ?", sourceID, target.Company).First(&source).Error; err != nil {
+		return nil
+	}
+	return &source
+}
+
+// materialBind 调用素材推送接口，素材已存在于目标广告主时视为成功
+func (s *LibraryService) materialBind(ctx context.Context, source, target *advModel.Advertiser, req *fileModel.MaterialBindRequest) bool {
+	req.AdvertiserID = source.AdvertiserID
+	req.TargetAdvertiserIDs = []uint64{target.AdvertiserID}
+	fails, err := oceansdk.Call(ctx, s.sdk, source.AdvertiserID, file.MaterialBind, req)
+	if err != nil {
+		return false
+	}
+	for _, f := range fails {
+		if f.FailReason != enum.IMAGE_BINDING_EXISTED && f.FailReason != enum.VIDEO_BINDING_EXISTED {
+			return false
+		}
+	}
+	return true
+}
+
+// saveBinding 创建或更新素材在广告主下的映射
+func (s *LibraryService) saveBinding(ctx context.Context, binding *model.LibraryBinding) error {
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "library_id"}, {Name: "advertiser_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"material_type", "media_id", "material_id", "method", "status", "error_msg", "synced_at", "updated_at"}),
+	}).Create(binding).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// get 获取素材库素材
+func (s *LibraryService) get(ctx context.Context, id uint64) (*model.LibraryMaterial, error) {
+	var item model.LibraryMaterial
+	if err := s.db.WithContext(ctx).First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrMaterialNotFound)
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &item, nil
+}
+
+// getFolder 获取文件夹
+func (s *LibraryService) getFolder(ctx context.Context, id uint64) (*model.LibraryFolder, error) {
+	var folder model.LibraryFolder
+	if err := s.db.WithContext(ctx).First(&folder, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NewWithMessage(errcode.ErrNotFound, "文件夹不存在")
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &folder, nil
+}
+
+// checkFolder 校验文件夹存在，0 为根目录
+func (s *LibraryService) checkFolder(ctx context.Context, id uint64) error {
+	if id == 0 {
+		return nil
+	}
+	_, err := s.getFolder(ctx, id)
+	return err
+}
+
+// tags 批量获取素材标签
+func (s *LibraryService) tags(ctx context.Context, ids []uint64) (map[uint64][]string, error) {
+	result := make(map[uint64][]string, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var tags []*model.LibraryTag
+	if err := s.db.WithContext(ctx).Where("library_id IN ?", ids).Order("id ASC").Find(&tags).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	for _, t := range tags {
+		result[t.LibraryID] = append(result[t.LibraryID], t.Tag)
+	}
+	return result, nil
+}
+
+// toResp 转换为响应
+func (s *LibraryService) toResp(ctx context.Context, item *model.LibraryMaterial) (*dto.LibraryResp, error) {
+	tags, err := s.tags(ctx, []uint64{item.ID})
+	if err != nil {
+		return nil, err
+	}
+	return libraryResp(item, tags[item.ID]), nil
+}
+
+// replaceTags 替换素材标签，去除空白与重复标签
+func replaceTags(tx *gorm.DB, libraryID uint64, tags []string) error {
+	if err := tx.Where("library_id = ?", libraryID).Delete(&model.LibraryTag{}).Error; err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(tags))
+	rows := make([]*model.LibraryTag, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		rows = append(rows, &model.LibraryTag{LibraryID: libraryID, Tag: tag})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Create(&rows).Error
+}
+
+func libraryResp(item *model.LibraryMaterial, tags []string) *dto.LibraryResp {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &dto.LibraryResp{
+		ID:           item.ID,
+		MaterialType: item.MaterialType,
+		Name:         item.Name,
+		FolderID:     item.FolderID,
+		OwnerID:      item.OwnerID,
+		Filename:     item.Filename,
+		Size:         item.Size,
+		Format:       item.Format,
+		Signature:    item.Signature,
+		Tags:         tags,
+		CreatedAt:    item.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func bindingResps(bindings []*model.LibraryBinding) []*dto.LibraryBindingResp {
+	result := make([]*dto.LibraryBindingResp, len(bindings))
+	for i, b := range bindings {
+		var syncedAt string
+		if b.SyncedAt != nil {
+			syncedAt = b.SyncedAt.Format("2006-01-02 15:04:05")
+		}
+		result[i] = &dto.LibraryBindingResp{
+			AdvertiserID: b.AdvertiserID,
+			MediaID:      b.MediaID,
+			MaterialID:   b.MaterialID,
+			Method:       b.Method,
+			Status:       b.Status,
+			ErrorMsg:     b.ErrorMsg,
+			SyncedAt:     syncedAt,
+		}
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/media/dto"
+	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/internal/fanout"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/pkg/oceansdk"
+	"oceanengine-backend/pkg/storage"
+)
+
+// libraryTestEnv 素材库测试环境，记录巨量上传与素材推送接口的调用次数
+type libraryTestEnv struct {
+	svc     *LibraryService
+	db      *gorm.DB
+	uploads atomic.Int32
+	binds   atomic.Int32
+}
+
+func newLibraryTestEnv(t *testing.T) *libraryTestEnv {
+	env := &libraryTestEnv{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		switch r.URL.Path {
+		case "/open_api/2/file/image/ad/":
+			n := env.uploads.Add(1)
+			data = map[string]interface{}{"id": fmt.Sprintf("web.business.image/%d", n), "url": "https://p3.example.com/1.jpg", "width": 640, "height": 480}
+		case "/open_api/2/file/material/bind/":
+			env.binds.Add(1)
+			var req map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, float64(2001), req["advertiser_id"])
+			assert.Equal(t, []interface{}{"web.business.image/1"}, req["image_ids"])
+			data = map[string]interface{}{"fail_list": []interface{}{
+				map[string]interface{}{"image_id": "web.business.image/1", "target_advertiser_id": 2002, "fail_reason": "IMAGE_BINDING_EXISTED"},
+			}}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}, &model.MaterialImage{}, &model.MaterialVideo{}, &model.LibraryMaterial{}, &model.LibraryTag{}, &model.LibraryFolder{}, &model.LibraryBinding{}))
+
+	st, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	oceanCfg := &config.OceanConfig{BaseURL: server.URL + "/open_api"}
+	media := NewMediaService(db, oceanCfg, st, staticTokens("media-token"), &config.MaterialConfig{UploadTimeout: time.Minute, MaxAttempts: 2})
+	sdk := oceanclient.NewSDK(oceanCfg, oceansdk.WithTokenResolver(staticTokens("media-token")))
+	env.svc = NewLibraryService(db, media, sdk, fanout.New(nil))
+	env.db = db
+	return env
+}
+
+func TestLibrary_UploadDedupAndTags(t *testing.T) {
+	env := newLibraryTestEnv(t)
+	ctx := context.Background()
+
+	folder, err := env.svc.CreateFolder(ctx, 1, &dto.FolderCreateReq{Name: "618"})
+	require.NoError(t, err)
+
+	png := testImage(t, "png", 1280, 720)
+	item, err := env.svc.Upload(ctx, 1, &dto.LibraryUploadReq{MaterialType: model.MaterialTypeImage, FolderID: folder.ID, Tags: []string{"大促", " 大促", "主图"}},
+		"banner.png", bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Equal(t, "banner.png", item.Name)
+	assert.Equal(t, []string{"大促", "主图"}, item.Tags)
+
+	// 相同文件返回已有素材
+	again, err := env.svc.Upload(ctx, 2, &dto.LibraryUploadReq{MaterialType: model.MaterialTypeImage}, "copy.png", bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Equal(t, item.ID, again.ID)
+
+	list, total, err := env.svc.List(ctx, &dto.LibraryListReq{Tag: "主图", FolderID: &folder.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, item.ID, list[0].ID)
+
+	// 非空文件夹不能删除
+	assert.Error(t, env.svc.DeleteFolder(ctx, folder.ID))
+	_, err = env.svc.UpdateFolder(ctx, folder.ID, &dto.FolderUpdateReq{ParentID: &folder.ID})
+	assert.Error(t, err)
+}
+
+func TestLibrary_Push(t *testing.T) {
+	env := newLibraryTestEnv(t)
+	ctx := context.Background()
+
+	source := &advModel.Advertiser{AdvertiserID: 2001, Name: "source", Company: "示例公司"}
+	sibling := &advModel.Advertiser{AdvertiserID: 2002, Name: "sibling", Company: "示例公司"}
+	other := &advModel.Advertiser{AdvertiserID: 2003, Name: "other", Company: "其他公司"}
+	existing := &advModel.Advertiser{AdvertiserID: 2004, Name: "existing"}
+	for _, adv := range []*advModel.Advertiser{source, sibling, other, existing} {
+		require.NoError(t, env.db.Create(adv).Error)
+	}
+
+	item, err := env.svc.Upload(ctx, 1, &dto.LibraryUploadReq{MaterialType: model.MaterialTypeImage}, "banner.png", bytes.NewReader(testImage(t, "png", 1280, 720)))
+	require.NoError(t, err)
+
+	// 广告主已有相同签名的已同步素材
+	imageID := "web.business.image/legacy"
+	require.NoError(t, env.db.Create(&model.MaterialImage{AdvertiserID: existing.ID, ImageID: &imageID, Signature: item.Signature, Status: model.MaterialStatusSynced}).Error)
+
+	// 首个广告主没有可推送的来源，上传原始文件
+	resp, err := env.svc.Push(ctx, item.ID, []uint64{source.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Success)
+	require.Len(t, resp.Bindings, 1)
+	assert.Equal(t, model.BindMethodUpload, resp.Bindings[0].Method)
+	assert.Equal(t, model.MaterialStatusSynced, resp.Bindings[0].Status)
+	assert.Equal(t, "web.business.image/1", resp.Bindings[0].MaterialID)
+
+	// 同主体广告主通过素材推送复用巨量图片ID，其他主体上传，已有素材直接复用
+	resp, err = env.svc.Push(ctx, item.ID, []uint64{sibling.ID, other.ID, existing.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Success)
+	assert.Empty(t, resp.Failures)
+	methods := map[uint64]string{}
+	for _, b := range resp.Bindings {
+		assert.Equal(t, model.MaterialStatusSynced, b.Status)
+		methods[b.AdvertiserID] = b.Method
+	}
+	assert.Equal(t, map[uint64]string{sibling.ID: model.BindMethodBind, other.ID: model.BindMethodUpload, existing.ID: model.BindMethodReuse}, methods)
+	assert.Equal(t, int32(1), env.binds.Load())
+	assert.Equal(t, int32(2), env.uploads.Load())
+
+	var bound model.MaterialImage
+	require.NoError(t, env.db.Where("advertiser_id = ?", sibling.ID).First(&bound).Error)
+	assert.Equal(t, "web.business.image/1", *bound.ImageID)
+	assert.Equal(t, 640, bound.Width)
+
+	// 重复推送不再调用巨量接口
+	resp, err = env.svc.Push(ctx, item.ID, []uint64{source.ID, sibling.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Success)
+	assert.Equal(t, int32(1), env.binds.Load())
+	assert.Equal(t, int32(2), env.uploads.Load())
+
+	detail, err := env.svc.Get(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Len(t, detail.Bindings, 4)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"errors"
@@ -10,31 +11,54 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"oceanengine-backend/config"
 	advRepo "oceanengine-backend/internal/app/advertiser/repository"
 	"oceanengine-backend/internal/app/media/dto"
 	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/internal/datascope"
 	"oceanengine-backend/pkg/errcode"
-	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/mediaprobe"
+	"oceanengine-backend/pkg/storage"
 )
 
+// TokenResolver 按巨量广告主ID解析 access_token，advertiser/service.TokenService 实现了该接口
+type TokenResolver interface {
+	GetAccessToken(ctx context.Context, advertiserID uint64) (string, error)
+}
+
 // MediaService 素材服务
 type MediaService struct {
-	db      *gorm.DB
-	advRepo advRepo.AdvertiserRepository
+	db       *gorm.DB
+	advRepo  advRepo.AdvertiserRepository
+	oceanCfg *config.OceanConfig
+	storage  storage.Storage
+	tokens   TokenResolver
+	cfg      *config.MaterialConfig
 }
 
 // NewMediaService 创建素材服务
-func NewMediaService(db *gorm.DB) *MediaService {
+// st 保存素材原始文件，为空时不支持上传；cfg 为空时使用默认同步配置
+func NewMediaService(db *gorm.DB, oceanCfg *config.OceanConfig, st storage.Storage, tokens TokenResolver, cfg *config.MaterialConfig) *MediaService {
+	if cfg == nil {
+		cfg = &config.MaterialConfig{UploadTimeout: 10 * time.Minute, MaxAttempts: 5}
+	}
 	return &MediaService{
-		db:      db,
-		advRepo: advRepo.NewAdvertiserRepository(db),
+		db:       db,
+		advRepo:  advRepo.NewAdvertiserRepository(db),
+		oceanCfg: oceanCfg,
+		storage:  st,
+		tokens:   tokens,
+		cfg:      cfg,
 	}
 }
 
 // GetImageList 获取图片列表
 func (s *MediaService) GetImageList(ctx context.Context, req *dto.ImageListReq) ([]*dto.ImageListResp, int64, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, 0, err
+	}
+
 	var images []*model.MaterialImage
 	var total int64
 
@@ -57,7 +81,7 @@ func (s *MediaService) GetImageList(ctx context.Context, req *dto.ImageListReq)
 	for i, img := range images {
 		result[i] = &dto.ImageListResp{
 			ID:           img.ID,
-			ImageID:      img.ImageID,
+			ImageID:      stringValue(img.ImageID),
 			AdvertiserID: img.AdvertiserID,
 			Filename:     img.Filename,
 			Size:         img.Size,
@@ -65,6 +89,8 @@ func (s *MediaService) GetImageList(ctx context.Context, req *dto.ImageListReq)
 			Height:       img.Height,
 			Format:       img.Format,
 			URL:          img.URL,
+			Status:       img.Status,
+			ErrorMsg:     img.ErrorMsg,
 			CreatedAt:    img.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 	}
@@ -81,11 +107,17 @@ func (s *MediaService) GetImageByID(ctx context.Context, id uint64) (*model.Mate
 		}
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	if err := datascope.Check(ctx, image.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &image, nil
 }
 
 // CreateImage 创建图片记录
 func (s *MediaService) CreateImage(ctx context.Context, image *model.MaterialImage) error {
+	if err := datascope.Check(ctx, image.AdvertiserID); err != nil {
+		return err
+	}
 	if err := s.db.WithContext(ctx).Create(image).Error; err != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
@@ -94,6 +126,10 @@ func (s *MediaService) CreateImage(ctx context.Context, image *model.MaterialIma
 
 // DeleteImage 删除图片
 func (s *MediaService) DeleteImage(ctx context.Context, id uint64) error {
+	if _, err := s.GetImageByID(ctx, id); err != nil {
+		return err
+	}
+
 	result := s.db.WithContext(ctx).Delete(&model.MaterialImage{}, id)
 	if result.Error != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, result.Error)
@@ -106,6 +142,10 @@ func (s *MediaService) DeleteImage(ctx context.Context, id uint64) error {
 
 // GetVideoList 获取视频列表
 func (s *MediaService) GetVideoList(ctx context.Context, req *dto.VideoListReq) ([]*dto.VideoListResp, int64, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, 0, err
+	}
+
 	var videos []*model.MaterialVideo
 	var total int64
 
@@ -128,7 +168,7 @@ func (s *MediaService) GetVideoList(ctx context.Context, req *dto.VideoListReq)
 	for i, vid := range videos {
 		result[i] = &dto.VideoListResp{
 			ID:           vid.ID,
-			VideoID:      vid.VideoID,
+			VideoID:      stringValue(vid.VideoID),
 			AdvertiserID: vid.AdvertiserID,
 			Filename:     vid.Filename,
 			Size:         vid.Size,
@@ -138,6 +178,8 @@ func (s *MediaService) GetVideoList(ctx context.Context, req *dto.VideoListReq)
 			Format:       vid.Format,
 			URL:          vid.URL,
 			PosterURL:    vid.PosterURL,
+			Status:       vid.Status,
+			ErrorMsg:     vid.ErrorMsg,
 			CreatedAt:    vid.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 	}
@@ -154,11 +196,17 @@ func (s *MediaService) GetVideoByID(ctx context.Context, id uint64) (*model.Mate
 		}
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	if err := datascope.Check(ctx, video.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &video, nil
 }
 
 // CreateVideo 创建视频记录
 func (s *MediaService) CreateVideo(ctx context.Context, video *model.MaterialVideo) error {
+	if err := datascope.Check(ctx, video.AdvertiserID); err != nil {
+		return err
+	}
 	if err := s.db.WithContext(ctx).Create(video).Error; err != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
@@ -167,6 +215,10 @@ func (s *MediaService) CreateVideo(ctx context.Context, video *model.MaterialVid
 
 // DeleteVideo 删除视频
 func (s *MediaService) DeleteVideo(ctx context.Context, id uint64) error {
+	if _, err := s.GetVideoByID(ctx, id); err != nil {
+		return err
+	}
+
 	result := s.db.WithContext(ctx).Delete(&model.MaterialVideo{}, id)
 	if result.Error != nil {
 		return errcode.Wrap(errcode.ErrInternalServer, result.Error)
@@ -178,179 +230,226 @@ func (s *MediaService) DeleteVideo(ctx context.Context, id uint64) error {
 }
 
 // UploadImage 上传图片
-// 如果广告主有 access_token，则调用 OE API 上传
+// 原始文件写入存储并创建 PENDING 记录后立即推送到巨量；推送失败时记录原因并返回当前状态，由定时任务继续重试
+// 同一广告主重复上传相同文件时返回已有记录，推送失败 (FAILED) 的记录重新推送
 func (s *MediaService) UploadImage(ctx context.Context, advertiserID uint64, filename string, file io.Reader, size int64) (*dto.ImageUploadResp, error) {
+	if err := datascope.Check(ctx, advertiserID); err != nil {
+		return nil, err
+	}
+	if s.storage == nil {
+		return nil, errcode.NewWithMessage(errcode.ErrMaterialUploadFail, "素材存储未配置")
+	}
+
 	// 读取文件内容计算签名
 	data, err := io.ReadAll(file)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
+	// 本地解析并校验投放规格，不符合规格的文件不保存也不推送
+	info, err := CheckSpec(mediaprobe.PlatformOceanEngine, model.MaterialTypeImage, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
 	// 计算MD5签名
 	hash := md5.Sum(data)
 	signature := fmt.Sprintf("%x", hash)
 
-	// 检查是否已存在
-	var existImage model.MaterialImage
-	if err := s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", advertiserID, signature).First(&existImage).Error; err == nil {
-		return &dto.ImageUploadResp{
-			ImageID: existImage.ImageID,
-			URL:     existImage.URL,
-			Width:   existImage.Width,
-			Height:  existImage.Height,
-			Size:    existImage.Size,
-			Format:  existImage.Format,
-		}, nil
-	}
-
-	// 获取文件格式
+	// 扩展名用于原始文件存储路径，格式以文件内容为准
 	ext := strings.ToLower(filepath.Ext(filename))
-	format := strings.TrimPrefix(ext, ".")
-	if format == "jpeg" {
-		format = "jpg"
-	}
-
-	var imageID, imageURL string
-	var width, height int
-
-	// 获取广告主信息，尝试调用 OE API
-	adv, _ := s.advRepo.GetByID(ctx, advertiserID)
-	if adv != nil && adv.AccessToken != "" {
-		client := oceanengine.NewClient("", "")
-		client.SetAccessToken(adv.AccessToken)
-		fileSvc := oceanengine.NewFileService(client)
-
-		oeResp, err := fileSvc.UploadImageByBytes(ctx, int64(adv.AdvertiserID), filename, data)
-		if err == nil {
-			imageID = oeResp.ImageID
-			imageURL = oeResp.URL
-			width = oeResp.Width
-			height = oeResp.Height
-		}
-	}
-
-	// 如果 OE API 调用失败或无 token，回退到本地生成
-	if imageID == "" {
-		imageID = fmt.Sprintf("img_%s", uuid.New().String()[:8])
-		imageURL = fmt.Sprintf("https://cdn.oceanengine.com/material/%d/%s%s", advertiserID, imageID, ext)
-	}
 
-	// 保存数据库记录
-	image := &model.MaterialImage{
-		ImageID:      imageID,
-		AdvertiserID: advertiserID,
-		Filename:     filename,
-		Size:         size,
-		Width:        width,
-		Height:       height,
-		Format:       format,
-		URL:          imageURL,
-		Signature:    signature,
+	// 检查是否已存在
+	var image model.MaterialImage
+	err = s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", advertiserID, signature).First(&image).Error
+	switch {
+	case err == nil:
+		if image.Status != model.MaterialStatusFailed {
+			return imageUploadResp(&image), nil
+		}
+		key, err := s.saveOriginal(ctx, model.MaterialTypeImage, advertiserID, signature, ext, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.reset(ctx, &model.MaterialImage{}, model.MaterialTypeImage, image.ID, key); err != nil {
+			return nil, err
+		}
+		image.StorageKey, image.Status, image.Attempts = key, model.MaterialStatusPending, 0
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		key, err := s.saveOriginal(ctx, model.MaterialTypeImage, advertiserID, signature, ext, data)
+		if err != nil {
+			return nil, err
+		}
+		image = model.MaterialImage{
+			AdvertiserID: advertiserID,
+			Filename:     filename,
+			Size:         int64(len(data)),
+			Width:        info.Width,
+			Height:       info.Height,
+			Format:       info.Format,
+			Signature:    signature,
+			StorageKey:   key,
+			Status:       model.MaterialStatusPending,
+		}
+		if err := s.db.WithContext(ctx).Create(&image).Error; err != nil {
+			return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+	default:
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
-	if err := s.db.WithContext(ctx).Create(image).Error; err != nil {
+	// 推送失败的原因已写入素材记录
+	_ = s.syncImage(ctx, &image)
+	if err := s.db.WithContext(ctx).First(&image, image.ID).Error; err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
-
-	return &dto.ImageUploadResp{
-		ImageID: imageID,
-		URL:     imageURL,
-		Width:   width,
-		Height:  height,
-		Size:    size,
-		Format:  format,
-	}, nil
+	return imageUploadResp(&image), nil
 }
 
 // UploadVideo 上传视频
-// 如果广告主有 access_token，则调用 OE API 上传
+// 处理流程同 UploadImage
 func (s *MediaService) UploadVideo(ctx context.Context, advertiserID uint64, filename string, file io.Reader, size int64) (*dto.VideoUploadResp, error) {
+	if err := datascope.Check(ctx, advertiserID); err != nil {
+		return nil, err
+	}
+	if s.storage == nil {
+		return nil, errcode.NewWithMessage(errcode.ErrMaterialUploadFail, "素材存储未配置")
+	}
+
 	// 读取文件内容计算签名
 	data, err := io.ReadAll(file)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
+	// 本地解析并校验投放规格，不符合规格的文件不保存也不推送
+	info, err := CheckSpec(mediaprobe.PlatformOceanEngine, model.MaterialTypeVideo, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
 	// 计算MD5签名
 	hash := md5.Sum(data)
 	signature := fmt.Sprintf("%x", hash)
 
-	// 检查是否已存在
-	var existVideo model.MaterialVideo
-	if err := s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", advertiserID, signature).First(&existVideo).Error; err == nil {
-		return &dto.VideoUploadResp{
-			VideoID:   existVideo.VideoID,
-			URL:       existVideo.URL,
-			Width:     existVideo.Width,
-			Height:    existVideo.Height,
-			Duration:  existVideo.Duration,
-			Size:      existVideo.Size,
-			Format:    existVideo.Format,
-			PosterURL: existVideo.PosterURL,
-		}, nil
-	}
-
-	// 获取文件格式
+	// 扩展名用于原始文件存储路径，格式以文件内容为准
 	ext := strings.ToLower(filepath.Ext(filename))
-	format := strings.TrimPrefix(ext, ".")
-
-	var videoID, videoURL, posterURL string
-	var width, height int
-	var duration float64
-
-	// 获取广告主信息，尝试调用 OE API
-	adv, _ := s.advRepo.GetByID(ctx, advertiserID)
-	if adv != nil && adv.AccessToken != "" {
-		client := oceanengine.NewClient("", "")
-		client.SetAccessToken(adv.AccessToken)
-		fileSvc := oceanengine.NewFileService(client)
-
-		oeResp, err := fileSvc.UploadVideoByBytes(ctx, int64(adv.AdvertiserID), filename, data)
-		if err == nil {
-			videoID = oeResp.VideoID
-			videoURL = oeResp.URL
-			posterURL = oeResp.PosterURL
-			width = oeResp.Width
-			height = oeResp.Height
-			duration = oeResp.Duration
+
+	// 检查是否已存在
+	var video model.MaterialVideo
+	err = s.db.WithContext(ctx).Where("advertiser_id = ? AND signature = ?", advertiserID, signature).First(&video).Error
+	switch {
+	case err == nil:
+		if video.Status != model.MaterialStatusFailed {
+			return videoUploadResp(&video), nil
+		}
+		key, err := s.saveOriginal(ctx, model.MaterialTypeVideo, advertiserID, signature, ext, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.reset(ctx, &model.MaterialVideo{}, model.MaterialTypeVideo, video.ID, key); err != nil {
+			return nil, err
 		}
+		video.StorageKey, video.Status, video.Attempts = key, model.MaterialStatusPending, 0
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		key, err := s.saveOriginal(ctx, model.MaterialTypeVideo, advertiserID, signature, ext, data)
+		if err != nil {
+			return nil, err
+		}
+		video = model.MaterialVideo{
+			AdvertiserID: advertiserID,
+			Filename:     filename,
+			Size:         int64(len(data)),
+			Width:        info.Width,
+			Height:       info.Height,
+			Duration:     info.Duration,
+			BitRate:      info.BitRate,
+			Format:       info.Format,
+			Signature:    signature,
+			StorageKey:   key,
+			Status:       model.MaterialStatusPending,
+		}
+		if err := s.db.WithContext(ctx).Create(&video).Error; err != nil {
+			return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+		}
+	default:
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
-	// 如果 OE API 调用失败或无 token，回退到本地生成
-	if videoID == "" {
-		videoID = fmt.Sprintf("vid_%s", uuid.New().String()[:8])
-		videoURL = fmt.Sprintf("https://cdn.oceanengine.com/material/%d/%s%s", advertiserID, videoID, ext)
-		posterURL = fmt.Sprintf("https://cdn.oceanengine.com/material/%d/%s_poster.jpg", advertiserID, videoID)
+	// 推送失败的原因已写入素材记录
+	_ = s.syncVideo(ctx, &video)
+	if err := s.db.WithContext(ctx).First(&video, video.ID).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	return videoUploadResp(&video), nil
+}
 
-	// 保存数据库记录
-	video := &model.MaterialVideo{
-		VideoID:      videoID,
-		AdvertiserID: advertiserID,
-		Filename:     filename,
-		Size:         size,
-		Width:        width,
-		Height:       height,
-		Duration:     duration,
-		Format:       format,
-		URL:          videoURL,
-		PosterURL:    posterURL,
-		Signature:    signature,
-		CreatedAt:    time.Now(),
+// saveOriginal 保存素材原始文件，路径为 materials/{类型}/{广告主ID}/{MD5}{扩展名}
+func (s *MediaService) saveOriginal(ctx context.Context, materialType string, advertiserID uint64, signature, ext string, data []byte) (string, error) {
+	key := fmt.Sprintf("materials/%s/%d/%s%s", materialType, advertiserID, signature, ext)
+	if _, err := s.storage.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return "", errcode.WrapWithMessage(errcode.ErrMaterialUploadFail, "保存素材原始文件失败", err)
 	}
+	return key, nil
+}
 
-	if err := s.db.WithContext(ctx).Create(video).Error; err != nil {
-		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+// reset 将推送失败的素材重置为 PENDING 并清零推送次数
+func (s *MediaService) reset(ctx context.Context, table interface{}, materialType string, id uint64, storageKey string) error {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(table).
+			Where("id = ? AND status = ?", id, model.MaterialStatusFailed).
+			Updates(map[string]interface{}{
+				"status":      model.MaterialStatusPending,
+				"attempts":    0,
+				"error_msg":   "",
+				"storage_key": storageKey,
+			}).Error; err != nil {
+			return err
+		}
+		return updateBindings(tx, materialType, id, map[string]interface{}{
+			"status":    model.MaterialStatusPending,
+			"error_msg": "",
+		})
+	}); err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
 	}
+	return nil
+}
 
+func imageUploadResp(image *model.MaterialImage) *dto.ImageUploadResp {
+	return &dto.ImageUploadResp{
+		ID:       image.ID,
+		ImageID:  stringValue(image.ImageID),
+		URL:      image.URL,
+		Width:    image.Width,
+		Height:   image.Height,
+		Size:     image.Size,
+		Format:   image.Format,
+		Status:   image.Status,
+		ErrorMsg: image.ErrorMsg,
+	}
+}
+
+func videoUploadResp(video *model.MaterialVideo) *dto.VideoUploadResp {
 	return &dto.VideoUploadResp{
-		VideoID:   videoID,
-		URL:       videoURL,
-		Width:     width,
-		Height:    height,
-		Duration:  duration,
-		Size:      size,
-		Format:    format,
-		PosterURL: posterURL,
-	}, nil
+		ID:        video.ID,
+		VideoID:   stringValue(video.VideoID),
+		URL:       video.URL,
+		Width:     video.Width,
+		Height:    video.Height,
+		Duration:  video.Duration,
+		BitRate:   video.BitRate,
+		Size:      video.Size,
+		Format:    video.Format,
+		PosterURL: video.PosterURL,
+		Status:    video.Status,
+		ErrorMsg:  video.ErrorMsg,
+	}
+}
+
+func stringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
 }
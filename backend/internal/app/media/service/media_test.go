@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/mediaprobe"
+	"oceanengine-backend/pkg/storage"
+)
+
+// testImage 生成指定尺寸的图片
+func testImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	var buf bytes.Buffer
+	if format == "png" {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+func mp4Box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	return append(append(binary.BigEndian.AppendUint32(nil, uint32(8+len(body))), typ...), body...)
+}
+
+// testVideo 生成只包含一条 H.264 视频轨的 MP4，mdat 按 1Mbps 码率填充
+func testVideo(width, height int, seconds uint32) []byte {
+	mvhd := binary.BigEndian.AppendUint32(make([]byte, 12), 1)
+	mvhd = binary.BigEndian.AppendUint32(mvhd, seconds)
+	tkhd := make([]byte, 40)
+	for _, v := range []uint32{0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000, uint32(width) << 16, uint32(height) << 16} {
+		tkhd = binary.BigEndian.AppendUint32(tkhd, v)
+	}
+	hdlr := append(make([]byte, 8), "vide"...)
+	stsd := append(binary.BigEndian.AppendUint32(make([]byte, 4), 1), mp4Box("avc1", make([]byte, 70))...)
+	trak := mp4Box("trak", mp4Box("tkhd", tkhd), mp4Box("mdia", mp4Box("hdlr", hdlr), mp4Box("minf", mp4Box("stbl", mp4Box("stsd", stsd)))))
+	return bytes.Join([][]byte{
+		mp4Box("ftyp", []byte("isom"), make([]byte, 4)),
+		mp4Box("moov", mp4Box("mvhd", mvhd), trak),
+		mp4Box("mdat", make([]byte, seconds*125000)),
+	}, nil)
+}
+
+type staticTokens string
+
+func (s staticTokens) GetAccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	return string(s), nil
+}
+
+// newMediaTestEnv 创建测试环境，fail 为 true 时模拟巨量上传接口返回错误
+func newMediaTestEnv(t *testing.T, fail *atomic.Bool) (*MediaService, *gorm.DB, storage.Storage, *advModel.Advertiser) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "media-token", r.Header.Get("Access-Token"))
+		if fail.Load() {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 40001, "message": "文件格式错误"})
+			return
+		}
+		var data interface{}
+		switch r.URL.Path {
+		case "/open_api/2/file/image/ad/":
+			data = map[string]interface{}{"id": "web.business.image/1", "url": "https://p3.example.com/1.jpg", "width": 1280, "height": 720}
+		case "/open_api/2/file/video/ad/":
+			data = map[string]interface{}{"video_id": "v0201", "video_url": "https://v.example.com/1.mp4", "poster_url": "https://p3.example.com/1_poster.jpg", "duration": 15.5}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}, &model.MaterialImage{}, &model.MaterialVideo{}, &model.LibraryMaterial{}, &model.LibraryTag{}, &model.LibraryFolder{}, &model.LibraryBinding{}))
+	adv := &advModel.Advertiser{AdvertiserID: 1001, Name: "media"}
+	require.NoError(t, db.Create(adv).Error)
+
+	st, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	svc := NewMediaService(db, &config.OceanConfig{BaseURL: server.URL + "/open_api"}, st, staticTokens("media-token"),
+		&config.MaterialConfig{UploadTimeout: time.Minute, MaxAttempts: 2})
+	return svc, db, st, adv
+}
+
+func TestUploadImage_Synced(t *testing.T) {
+	var fail atomic.Bool
+	svc, _, st, adv := newMediaTestEnv(t, &fail)
+
+	jpg := testImage(t, "jpg", 1280, 720)
+	resp, err := svc.UploadImage(context.Background(), adv.ID, "banner.JPEG", bytes.NewReader(jpg), int64(len(jpg)))
+	require.NoError(t, err)
+	assert.Equal(t, model.MaterialStatusSynced, resp.Status)
+	assert.Equal(t, "web.business.image/1", resp.ImageID)
+	assert.Equal(t, "https://p3.example.com/1.jpg", resp.URL)
+	assert.Equal(t, "jpg", resp.Format)
+	assert.Equal(t, 1280, resp.Width)
+
+	image, err := svc.GetImageByID(context.Background(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, image.Attempts)
+	assert.NotNil(t, image.SyncedAt)
+	rc, err := st.Open(context.Background(), image.StorageKey)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, jpg, data)
+
+	// 重复上传返回已有记录
+	again, err := svc.UploadImage(context.Background(), adv.ID, "banner.jpg", bytes.NewReader(jpg), int64(len(jpg)))
+	require.NoError(t, err)
+	assert.Equal(t, resp.ID, again.ID)
+}
+
+func TestUploadVideo_FailedThenSync(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	svc, db, _, adv := newMediaTestEnv(t, &fail)
+
+	// 推送失败不生成素材ID，记录失败原因
+	mp4 := testVideo(1280, 720, 15)
+	resp, err := svc.UploadVideo(context.Background(), adv.ID, "clip.mp4", bytes.NewReader(mp4), int64(len(mp4)))
+	require.NoError(t, err)
+	assert.Equal(t, model.MaterialStatusFailed, resp.Status)
+	assert.Empty(t, resp.VideoID)
+	assert.Empty(t, resp.URL)
+	assert.Contains(t, resp.ErrorMsg, "文件格式错误")
+	// 本地解析的规格在推送前写入
+	assert.Equal(t, 1280, resp.Width)
+	assert.Equal(t, 15.0, resp.Duration)
+	assert.Greater(t, resp.BitRate, 516000)
+
+	// 定时任务重新推送
+	fail.Store(false)
+	success, failed, err := svc.Sync(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Equal(t, 0, failed)
+
+	var video model.MaterialVideo
+	require.NoError(t, db.First(&video, resp.ID).Error)
+	assert.Equal(t, model.MaterialStatusSynced, video.Status)
+	require.NotNil(t, video.VideoID)
+	assert.Equal(t, "v0201", *video.VideoID)
+	assert.Equal(t, 15.5, video.Duration)
+	assert.Equal(t, 2, video.Attempts)
+	assert.Empty(t, video.ErrorMsg)
+
+	// 已同步的素材不再推送
+	success, failed, err = svc.Sync(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, success+failed)
+}
+
+func TestSync_MaxAttemptsAndReupload(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	svc, db, _, adv := newMediaTestEnv(t, &fail)
+
+	png := testImage(t, "png", 1280, 720)
+	resp, err := svc.UploadImage(context.Background(), adv.ID, "a.png", bytes.NewReader(png), int64(len(png)))
+	require.NoError(t, err)
+	require.Equal(t, model.MaterialStatusFailed, resp.Status)
+
+	// 达到最大推送次数后不再重试
+	_, failed, err := svc.Sync(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	_, failed, err = svc.Sync(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failed)
+
+	// 中断的推送超时后可重新领取
+	stale := &model.MaterialImage{AdvertiserID: adv.ID, Filename: "b.png", Signature: "b", StorageKey: "materials/image/1/b.png", Status: model.MaterialStatusUploading}
+	require.NoError(t, db.Create(stale).Error)
+	require.NoError(t, db.Model(stale).UpdateColumn("updated_at", time.Now().Add(-2*time.Minute)).Error)
+	_, failed, err = svc.Sync(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	require.NoError(t, db.First(stale, stale.ID).Error)
+	assert.Equal(t, model.MaterialStatusFailed, stale.Status)
+
+	// 重新上传相同文件时重置推送次数并立即推送
+	fail.Store(false)
+	again, err := svc.UploadImage(context.Background(), adv.ID, "a.png", bytes.NewReader(png), int64(len(png)))
+	require.NoError(t, err)
+	assert.Equal(t, resp.ID, again.ID)
+	assert.Equal(t, model.MaterialStatusSynced, again.Status)
+	assert.Equal(t, "web.business.image/1", again.ImageID)
+}
+
+func TestUpload_SpecViolation(t *testing.T) {
+	var fail atomic.Bool
+	svc, db, _, adv := newMediaTestEnv(t, &fail)
+
+	// 分辨率不足与宽高比不符的文件在推送前拦截，不创建素材记录
+	small := testImage(t, "png", 640, 360)
+	_, err := svc.UploadImage(context.Background(), adv.ID, "small.png", bytes.NewReader(small), int64(len(small)))
+	var appErr *errcode.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errcode.ErrMaterialSpecInvalid, appErr.Code)
+	violations, ok := appErr.Details.([]mediaprobe.Violation)
+	require.True(t, ok)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "width", violations[0].Field)
+	assert.Equal(t, "640", violations[0].Actual)
+
+	short := testVideo(720, 1280, 2)
+	_, err = svc.UploadVideo(context.Background(), adv.ID, "short.mp4", bytes.NewReader(short), int64(len(short)))
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errcode.ErrMaterialSpecInvalid, appErr.Code)
+	assert.Contains(t, appErr.Message, "不能短于 4 秒")
+
+	// 文件类型与接口不符
+	_, err = svc.UploadVideo(context.Background(), adv.ID, "clip.mp4", bytes.NewReader(small), int64(len(small)))
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errcode.ErrMaterialTypeInvalid, appErr.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&model.MaterialImage{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	resp, err := svc.Probe(mediaprobe.PlatformQianchuan, bytes.NewReader(short), int64(len(short)))
+	require.NoError(t, err)
+	assert.Equal(t, "千川竖版视频", resp.Spec)
+	assert.Equal(t, 720, resp.Info.Width)
+	require.Len(t, resp.Violations, 1)
+	assert.Equal(t, "duration", resp.Violations[0].Field)
+}
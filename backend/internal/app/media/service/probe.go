@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"oceanengine-backend/internal/app/media/dto"
+	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/mediaprobe"
+)
+
+// Probe 解析素材文件并按平台规格校验，返回素材信息与全部不符合项，供上传前检查
+func (s *MediaService) Probe(platform string, r io.ReaderAt, size int64) (*dto.ProbeResp, error) {
+	info, err := probe("", r, size)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.ProbeResp{Info: info, Violations: []mediaprobe.Violation{}}
+	if spec := mediaprobe.SpecFor(platform, info); spec != nil {
+		resp.Spec = spec.Name
+		if violations := spec.Validate(info); len(violations) > 0 {
+			resp.Violations = violations
+		}
+	}
+	return resp, nil
+}
+
+// CheckSpec 本地解析素材并按平台投放规格校验，在调用巨量接口前拦截不符合规格的文件
+// 文件不是 materialType 类型或无法解析时返回 ErrMaterialTypeInvalid；不符合规格时返回 ErrMaterialSpecInvalid，Details 为不符合项列表
+func CheckSpec(platform, materialType string, r io.ReaderAt, size int64) (*mediaprobe.Info, error) {
+	info, err := probe(materialType, r, size)
+	if err != nil {
+		return nil, err
+	}
+	spec := mediaprobe.SpecFor(platform, info)
+	if spec == nil {
+		return info, nil
+	}
+	violations := spec.Validate(info)
+	if len(violations) == 0 {
+		return info, nil
+	}
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Message
+	}
+	return nil, errcode.NewWithMessage(errcode.ErrMaterialSpecInvalid, strings.Join(msgs, "；")).WithDetails(violations)
+}
+
+// probe 解析素材文件，materialType 不为空时校验文件类型
+func probe(materialType string, r io.ReaderAt, size int64) (*mediaprobe.Info, error) {
+	info, err := mediaprobe.Probe(r, size)
+	if err != nil {
+		if errors.Is(err, mediaprobe.ErrUnsupported) {
+			return nil, errcode.NewWithMessage(errcode.ErrMaterialTypeInvalid, "不支持的素材格式，图片支持 JPG/PNG/GIF/WebP，视频支持 MP4/MOV")
+		}
+		return nil, errcode.WrapWithMessage(errcode.ErrMaterialTypeInvalid, "素材文件无法解析", err)
+	}
+	switch {
+	case materialType == model.MaterialTypeImage && info.IsVideo():
+		return nil, errcode.NewWithMessage(errcode.ErrMaterialTypeInvalid, "请上传图片文件")
+	case materialType == model.MaterialTypeVideo && !info.IsVideo():
+		return nil, errcode.NewWithMessage(errcode.ErrMaterialTypeInvalid, "请上传视频文件")
+	}
+	return info, nil
+}
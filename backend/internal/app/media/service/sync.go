@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+	"oceanengine-backend/internal/app/media/model"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/outbox"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+// errNoOriginal 素材没有保存原始文件（历史记录），无法重新推送
+var errNoOriginal = errors.New("素材原始文件不存在，请重新上传")
+
+// Sync 推送待同步及推送失败的素材，返回成功与失败数
+// 推送中 (UPLOADING) 超过上传超时仍未完成的素材视为中断，重新领取；推送次数达到上限的素材不再处理
+func (s *MediaService) Sync(ctx context.Context, limit int) (success, failed int, err error) {
+	if s.storage == nil {
+		return 0, 0, errors.New("素材存储未配置")
+	}
+
+	var images []*model.MaterialImage
+	if err := s.db.WithContext(ctx).Scopes(s.syncable()).Order("id ASC").Limit(limit).Find(&images).Error; err != nil {
+		return 0, 0, err
+	}
+	for _, image := range images {
+		if ctx.Err() != nil {
+			return success, failed, nil
+		}
+		if err := s.syncImage(ctx, image); err != nil {
+			failed++
+			continue
+		}
+		success++
+	}
+
+	var videos []*model.MaterialVideo
+	if err := s.db.WithContext(ctx).Scopes(s.syncable()).Order("id ASC").Limit(limit).Find(&videos).Error; err != nil {
+		return success, failed, err
+	}
+	for _, video := range videos {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.syncVideo(ctx, video); err != nil {
+			failed++
+			continue
+		}
+		success++
+	}
+	return success, failed, nil
+}
+
+// syncable 可领取推送的素材
+func (s *MediaService) syncable() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(status IN ? OR (status = ? AND updated_at < ?))",
+			[]string{model.MaterialStatusPending, model.MaterialStatusFailed},
+			model.MaterialStatusUploading, time.Now().Add(-s.cfg.UploadTimeout)).
+			Where("attempts < ?", s.cfg.MaxAttempts)
+	}
+}
+
+// claim 通过 attempts 条件更新领取素材并标记为 UPLOADING，素材正被其他请求或实例推送时返回 false
+func (s *MediaService) claim(ctx context.Context, table interface{}, id uint64, attempts int) (bool, error) {
+	return outbox.Claim(s.db.WithContext(ctx).Model(table).Scopes(s.syncable()), id, attempts,
+		map[string]interface{}{"status": model.MaterialStatusUploading})
+}
+
+// syncImage 领取并推送图片素材，推送结果写回素材记录
+func (s *MediaService) syncImage(ctx context.Context, image *model.MaterialImage) error {
+	claimed, err := s.claim(ctx, &model.MaterialImage{}, image.ID, image.Attempts)
+	if err != nil || !claimed {
+		return err
+	}
+	image.Attempts++
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.UploadTimeout)
+	defer cancel()
+
+	info, pushErr := s.pushImage(ctx, image)
+	if pushErr != nil {
+		return s.markFailed(&model.MaterialImage{}, model.MaterialTypeImage, image.ID, pushErr)
+	}
+	fields := map[string]interface{}{
+		"image_id": info.ImageID,
+		"url":      info.URL,
+	}
+	// 巨量未返回宽高时保留本地解析结果
+	if info.Width > 0 && info.Height > 0 {
+		fields["width"], fields["height"] = info.Width, info.Height
+	}
+	if err := s.markSynced(&model.MaterialImage{}, model.MaterialTypeImage, image.ID, info.ImageID, fields); err != nil {
+		return s.markFailed(&model.MaterialImage{}, model.MaterialTypeImage, image.ID, err)
+	}
+	return nil
+}
+
+// syncVideo 领取并推送视频素材，推送结果写回素材记录
+func (s *MediaService) syncVideo(ctx context.Context, video *model.MaterialVideo) error {
+	claimed, err := s.claim(ctx, &model.MaterialVideo{}, video.ID, video.Attempts)
+	if err != nil || !claimed {
+		return err
+	}
+	video.Attempts++
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.UploadTimeout)
+	defer cancel()
+
+	info, pushErr := s.pushVideo(ctx, video)
+	if pushErr != nil {
+		return s.markFailed(&model.MaterialVideo{}, model.MaterialTypeVideo, video.ID, pushErr)
+	}
+	fields := map[string]interface{}{
+		"video_id":   info.VideoID,
+		"url":        info.URL,
+		"poster_url": info.PosterURL,
+	}
+	// 巨量未返回宽高、时长时保留本地解析结果
+	if info.Width > 0 && info.Height > 0 {
+		fields["width"], fields["height"] = info.Width, info.Height
+	}
+	if info.Duration > 0 {
+		fields["duration"] = info.Duration
+	}
+	if err := s.markSynced(&model.MaterialVideo{}, model.MaterialTypeVideo, video.ID, info.VideoID, fields); err != nil {
+		return s.markFailed(&model.MaterialVideo{}, model.MaterialTypeVideo, video.ID, err)
+	}
+	return nil
+}
+
+// markSynced 记录推送结果，同时更新素材库映射
+// 使用独立 context，推送超时后仍需记录状态
+func (s *MediaService) markSynced(table interface{}, materialType string, id uint64, materialID string, fields map[string]interface{}) error {
+	now := time.Now()
+	fields["status"] = model.MaterialStatusSynced
+	fields["error_msg"] = ""
+	fields["synced_at"] = now
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(table).Where("id = ?", id).Updates(fields).Error; err != nil {
+			return err
+		}
+		return updateBindings(tx, materialType, id, map[string]interface{}{
+			"material_id": materialID,
+			"status":      model.MaterialStatusSynced,
+			"error_msg":   "",
+			"synced_at":   now,
+		})
+	})
+}
+
+// markFailed 记录推送失败原因，返回原始错误
+func (s *MediaService) markFailed(table interface{}, materialType string, id uint64, cause error) error {
+	updates := map[string]interface{}{
+		"status":    model.MaterialStatusFailed,
+		"error_msg": errorMessage(cause),
+	}
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(table).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		return updateBindings(tx, materialType, id, updates)
+	}); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// updateBindings 更新引用该素材记录的素材库映射
+func updateBindings(tx *gorm.DB, materialType string, mediaID uint64, updates map[string]interface{}) error {
+	return tx.Model(&model.LibraryBinding{}).
+		Where("material_type = ? AND media_id = ?", materialType, mediaID).
+		Updates(updates).Error
+}
+
+// pushImage 从存储读取原始文件上传到巨量
+func (s *MediaService) pushImage(ctx context.Context, image *model.MaterialImage) (*oceanengine.ImageInfo, error) {
+	if image.StorageKey == "" {
+		return nil, errNoOriginal
+	}
+	fileSvc, advertiserID, err := s.fileService(ctx, image.AdvertiserID)
+	if err != nil {
+		return nil, err
+	}
+	return fileSvc.UploadImageFromSource(ctx, advertiserID, image.Filename, s.source(ctx, image.StorageKey), image.Size)
+}
+
+// pushVideo 从存储读取原始文件上传到巨量
+func (s *MediaService) pushVideo(ctx context.Context, video *model.MaterialVideo) (*oceanengine.VideoInfo, error) {
+	if video.StorageKey == "" {
+		return nil, errNoOriginal
+	}
+	fileSvc, advertiserID, err := s.fileService(ctx, video.AdvertiserID)
+	if err != nil {
+		return nil, err
+	}
+	return fileSvc.UploadVideoFromSource(ctx, advertiserID, video.Filename, s.source(ctx, video.StorageKey), video.Size)
+}
+
+// fileService 使用广告主 Token 创建巨量文件服务，返回巨量广告主ID
+func (s *MediaService) fileService(ctx context.Context, id uint64) (*oceanengine.FileService, int64, error) {
+	if s.tokens == nil {
+		return nil, 0, errcode.New(errcode.ErrOETokenInvalid)
+	}
+	adv, err := s.advRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, errcode.New(errcode.ErrAdvertiserNotFound)
+		}
+		return nil, 0, err
+	}
+	token, err := s.tokens.GetAccessToken(ctx, adv.AdvertiserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	client := oceanclient.New(s.oceanCfg)
+	client.SetAccessToken(token)
+	return oceanengine.NewFileService(client), int64(adv.AdvertiserID), nil
+}
+
+// source 存储中的原始文件，上传重试时重新打开
+func (s *MediaService) source(ctx context.Context, key string) oceanengine.UploadSource {
+	return func() (io.ReadCloser, error) {
+		return s.storage.Open(ctx, key)
+	}
+}
+
+// errorMessage 推送失败原因，超出 error_msg 长度时截断
+func errorMessage(err error) string {
+	msg := err.Error()
+	var appErr *errcode.AppError
+	if errors.As(err, &appErr) {
+		msg = appErr.Message
+		if cause := appErr.Cause(); cause != nil {
+			msg += ": " + cause.Error()
+		}
+	}
+	return outbox.ErrorMessage(msg)
+}
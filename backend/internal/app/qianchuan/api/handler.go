@@ -3,10 +3,19 @@ package api
 import (
 	"strconv"
 
+	qianchuanAd "github.com/bububa/oceanengine/marketing-api/api/qianchuan/ad"
+	"github.com/bububa/oceanengine/marketing-api/model/qianchuan/ad"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	mediaModel "oceanengine-backend/internal/app/media/model"
+	mediaService "oceanengine-backend/internal/app/media/service"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
+	"oceanengine-backend/pkg/logger"
+	"oceanengine-backend/pkg/mediaprobe"
 	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/oceansdk"
 	"oceanengine-backend/pkg/response"
 )
 
@@ -15,6 +24,7 @@ type QianchuanHandler struct {
 	db       *gorm.DB
 	oceanCfg *config.OceanConfig
 	client   *oceanengine.Client
+	sdk      *oceansdk.Client
 }
 
 // NewQianchuanHandler 创建千川处理器
@@ -22,17 +32,14 @@ func NewQianchuanHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *QianchuanHa
 	return &QianchuanHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
+		sdk:      oceanclient.NewSDK(oceanCfg, oceansdk.WithLogger(logger.Named("oceanengine"))),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *QianchuanHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -57,7 +64,7 @@ func (h *QianchuanHandler) GetAccountInfo(c *gin.Context) {
 
 	info, err := h.client.Qianchuan().GetAccountInfo(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -76,7 +83,7 @@ func (h *QianchuanHandler) GetShopList(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetShopList(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -97,7 +104,7 @@ func (h *QianchuanHandler) GetAwemeAuthList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetAuthorizedAwemeList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -116,7 +123,7 @@ func (h *QianchuanHandler) GetBalance(c *gin.Context) {
 
 	balance, err := h.client.Qianchuan().GetBalance(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -137,7 +144,7 @@ func (h *QianchuanHandler) GetCampaignList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetCampaignList(c.Request.Context(), accessToken, advertiserID, page, pageSize, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -156,13 +163,13 @@ func (h *QianchuanHandler) CreateCampaign(c *gin.Context) {
 		MarketingGoal string  `json:"marketing_goal"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	campaignID, err := h.client.Qianchuan().CreateCampaign(c.Request.Context(), accessToken, req.AdvertiserID, req.CampaignName, req.Budget, req.BudgetMode, req.MarketingGoal)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -188,7 +195,7 @@ func (h *QianchuanHandler) GetAdList(c *gin.Context) {
 	}
 	list, total, err := h.client.Qianchuan().GetAdList(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -208,7 +215,7 @@ func (h *QianchuanHandler) GetAdDetail(c *gin.Context) {
 
 	detail, err := h.client.Qianchuan().GetAdDetail(c.Request.Context(), accessToken, advertiserID, adID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -219,20 +226,24 @@ func (h *QianchuanHandler) GetAdDetail(c *gin.Context) {
 func (h *QianchuanHandler) CreateAd(c *gin.Context) {
 	accessToken := h.getAccessToken(c)
 
-	var req map[string]interface{}
+	var req ad.CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if accessToken == "" || req.AdvertiserID == 0 {
+		response.BadRequest(c, "缺少必要参数")
 		return
 	}
 
-	advertiserID := uint64(req["advertiser_id"].(float64))
-	adID, err := h.client.Qianchuan().CreateAd(c.Request.Context(), accessToken, advertiserID, req)
+	ctx := oceansdk.WithAccessToken(c.Request.Context(), accessToken)
+	result, err := oceansdk.Call(ctx, h.sdk, req.AdvertiserID, qianchuanAd.Create, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
-	response.OKWithData(c, gin.H{"ad_id": adID})
+	response.OKWithData(c, gin.H{"ad_id": result.AdID})
 }
 
 // UpdateAdStatus 更新广告状态
@@ -245,13 +256,13 @@ func (h *QianchuanHandler) UpdateAdStatus(c *gin.Context) {
 		OptStatus    string   `json:"opt_status"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	err := h.client.Qianchuan().UpdateAdStatus(c.Request.Context(), accessToken, req.AdvertiserID, req.AdIDs, req.OptStatus)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -272,7 +283,7 @@ func (h *QianchuanHandler) GetCreativeList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetCreativeList(c.Request.Context(), accessToken, advertiserID, page, pageSize, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -293,7 +304,7 @@ func (h *QianchuanHandler) GetAwemeOrderList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetAwemeOrderList(c.Request.Context(), accessToken, advertiserID, page, pageSize, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -313,7 +324,7 @@ func (h *QianchuanHandler) GetAwemeOrderDetail(c *gin.Context) {
 
 	detail, err := h.client.Qianchuan().GetAwemeOrderDetail(c.Request.Context(), accessToken, advertiserID, orderID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -326,14 +337,14 @@ func (h *QianchuanHandler) CreateAwemeOrder(c *gin.Context) {
 
 	var req map[string]interface{}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	advertiserID := uint64(req["advertiser_id"].(float64))
 	order, err := h.client.Qianchuan().CreateAwemeOrder(c.Request.Context(), accessToken, advertiserID, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -354,7 +365,7 @@ func (h *QianchuanHandler) GetAdvertiserReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetAdReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -375,7 +386,7 @@ func (h *QianchuanHandler) GetAdReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetAdReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -396,7 +407,7 @@ func (h *QianchuanHandler) GetMaterialList(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetMaterialReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -421,9 +432,15 @@ func (h *QianchuanHandler) UploadImage(c *gin.Context) {
 	}
 	defer file.Close()
 
+	// 上传前按千川素材规格校验
+	if _, err := mediaService.CheckSpec(mediaprobe.PlatformQianchuan, mediaModel.MaterialTypeImage, file, header.Size); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	result, err := h.client.Qianchuan().UploadImageFromReader(c.Request.Context(), accessToken, advertiserID, header.Filename, file)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -448,9 +465,15 @@ func (h *QianchuanHandler) UploadVideo(c *gin.Context) {
 	}
 	defer file.Close()
 
+	// 上传前按千川素材规格校验
+	if _, err := mediaService.CheckSpec(mediaprobe.PlatformQianchuan, mediaModel.MaterialTypeVideo, file, header.Size); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	result, err := h.client.Qianchuan().UploadVideoFromReader(c.Request.Context(), accessToken, advertiserID, header.Filename, file)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -468,7 +491,7 @@ func (h *QianchuanHandler) GetIndustryList(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetIndustryList(c.Request.Context(), accessToken)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -489,7 +512,7 @@ func (h *QianchuanHandler) GetDmpList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetAudienceList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -511,7 +534,7 @@ func (h *QianchuanHandler) GetProductList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetProductList(c.Request.Context(), accessToken, advertiserID, awemeID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -530,7 +553,7 @@ func (h *QianchuanHandler) GetBudget(c *gin.Context) {
 
 	budget, err := h.client.Qianchuan().GetAccountBudget(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -546,13 +569,13 @@ func (h *QianchuanHandler) UpdateBudget(c *gin.Context) {
 		Budget       float64 `json:"budget"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	err := h.client.Qianchuan().UpdateAccountBudget(c.Request.Context(), accessToken, req.AdvertiserID, req.Budget)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -575,7 +598,7 @@ func (h *QianchuanHandler) GetFinanceDetail(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetFinanceDetail(c.Request.Context(), accessToken, advertiserID, startDate, endDate, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -598,7 +621,7 @@ func (h *QianchuanHandler) GetUniList(c *gin.Context) {
 
 	list, total, err := h.client.Qianchuan().GetUniPromotionList(c.Request.Context(), accessToken, advertiserID, page, pageSize, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -611,14 +634,14 @@ func (h *QianchuanHandler) CreateUni(c *gin.Context) {
 
 	var req map[string]interface{}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
 	advertiserID := uint64(req["advertiser_id"].(float64))
 	adID, err := h.client.Qianchuan().CreateUniPromotion(c.Request.Context(), accessToken, advertiserID, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -638,7 +661,7 @@ func (h *QianchuanHandler) GetUniDetail(c *gin.Context) {
 
 	detail, err := h.client.Qianchuan().GetUniPromotionDetail(c.Request.Context(), accessToken, advertiserID, adID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -661,7 +684,7 @@ func (h *QianchuanHandler) GetCreativeReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetCreativeReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -682,7 +705,7 @@ func (h *QianchuanHandler) GetMaterialReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetMaterialReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -707,7 +730,7 @@ func (h *QianchuanHandler) GetKeywordReport(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetKeywords(c.Request.Context(), accessToken, advertiserID, adID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -728,7 +751,7 @@ func (h *QianchuanHandler) GetLiveReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetLiveReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -750,7 +773,7 @@ func (h *QianchuanHandler) GetRoomReport(c *gin.Context) {
 	// 使用通用的直播间报表接口
 	list, err := h.client.Qianchuan().GetLiveReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -775,7 +798,7 @@ func (h *QianchuanHandler) GetUniReport(c *gin.Context) {
 
 	list, err := h.client.Qianchuan().GetUniPromotionReport(c.Request.Context(), accessToken, advertiserID, startDate, endDate, nil)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -799,7 +822,7 @@ func (h *QianchuanHandler) GetKeywordRecommend(c *gin.Context) {
 		// 如果没有ad_id，返回词包列表
 		packages, err := h.client.Qianchuan().GetKeywordPackages(c.Request.Context(), accessToken, advertiserID)
 		if err != nil {
-			response.InternalError(c, err.Error())
+			response.UpstreamError(c, err)
 			return
 		}
 		response.OKWithData(c, packages)
@@ -808,7 +831,7 @@ func (h *QianchuanHandler) GetKeywordRecommend(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetRecommendKeywords(c.Request.Context(), accessToken, advertiserID, adID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -830,7 +853,7 @@ func (h *QianchuanHandler) GetKeywordList(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetKeywords(c.Request.Context(), accessToken, advertiserID, adID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -861,7 +884,7 @@ func (h *QianchuanHandler) UpdateKeywords(c *gin.Context) {
 
 	err := h.client.Qianchuan().UpdateKeywords(c.Request.Context(), accessToken, advertiserID, req.AdID, req.Keywords)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -885,7 +908,7 @@ func (h *QianchuanHandler) GetActionKeywords(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetActionKeywords(c.Request.Context(), accessToken, advertiserID, queryWord, actionScene, actionDays)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -905,7 +928,7 @@ func (h *QianchuanHandler) GetInterestKeywords(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetInterestKeywords(c.Request.Context(), accessToken, advertiserID, queryWord)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -932,7 +955,7 @@ func (h *QianchuanHandler) GetKeywordSuggest(c *gin.Context) {
 
 	keywords, err := h.client.Qianchuan().GetKeywordSuggest(c.Request.Context(), accessToken, advertiserID, req.Keywords)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
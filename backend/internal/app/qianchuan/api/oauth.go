@@ -9,6 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"oceanengine-backend/config"
+	advRepository "oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/database"
 	"oceanengine-backend/pkg/oauth"
 	"oceanengine-backend/pkg/oceanengine"
@@ -27,7 +29,7 @@ func NewQianchuanOAuthHandler(cfg *config.QianchuanConfig, redisClient *redis.Cl
 	return &QianchuanOAuthHandler{
 		cfg:          cfg,
 		stateManager: oauth.NewStateManager(redisClient),
-		client:       oceanengine.NewClient(cfg.AppID, cfg.Secret),
+		client:       oceanclient.NewQianchuan(cfg),
 	}
 }
 
@@ -221,22 +223,16 @@ func (h *QianchuanOAuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	refreshToken := req.RefreshToken
+	advRepo := advRepository.NewAdvertiserRepository(database.GetDB())
+	adv, err := advRepo.GetByAdvertiserID(c.Request.Context(), uint64(req.AdvertiserID))
+	if err != nil {
+		response.NotFound(c, "未找到该广告主")
+		return
+	}
 
-	// 如果没有提供refresh_token，从数据库获取
+	// 如果没有提供refresh_token，使用数据库中保存的
+	refreshToken := req.RefreshToken
 	if refreshToken == "" {
-		// 从数据库查询
-		var adv struct {
-			RefreshToken string
-		}
-		if err := database.GetDB().Table("ad_advertiser").
-			Select("refresh_token").
-			Where("advertiser_id = ? AND deleted_at IS NULL", req.AdvertiserID).
-			First(&adv).Error; err != nil {
-			response.NotFound(c, "未找到该广告主")
-			return
-		}
-
 		if adv.RefreshToken == "" {
 			response.BadRequest(c, "该广告主没有可用的刷新令牌，请重新授权")
 			return
@@ -252,16 +248,9 @@ func (h *QianchuanOAuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 更新数据库中的Token
+	// 更新数据库中的Token（经仓库层加密存储）
 	newExpireAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-	if err := database.GetDB().Table("ad_advertiser").
-		Where("advertiser_id = ?", req.AdvertiserID).
-		Updates(map[string]interface{}{
-			"access_token":     tokenResp.AccessToken,
-			"refresh_token":    tokenResp.RefreshToken,
-			"token_expires_at": newExpireAt,
-			"updated_at":       time.Now(),
-		}).Error; err != nil {
+	if err := advRepo.UpdateToken(c.Request.Context(), adv.ID, tokenResp.AccessToken, tokenResp.RefreshToken, newExpireAt); err != nil {
 		response.InternalError(c, "更新令牌失败: "+err.Error())
 		return
 	}
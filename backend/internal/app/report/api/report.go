@@ -1,13 +1,18 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
 	"oceanengine-backend/internal/app/report/dto"
+	"oceanengine-backend/internal/app/report/model"
 	"oceanengine-backend/internal/app/report/service"
+	"oceanengine-backend/internal/middleware"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/response"
 )
@@ -18,9 +23,9 @@ type ReportHandler struct {
 }
 
 // NewReportHandler 创建报告处理器
-func NewReportHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *ReportHandler {
+func NewReportHandler(db *gorm.DB, oceanCfg *config.OceanConfig, files *service.ExportFiles) *ReportHandler {
 	return &ReportHandler{
-		service: service.NewReportService(db, oceanCfg),
+		service: service.NewReportService(db, oceanCfg, files),
 	}
 }
 
@@ -204,7 +209,8 @@ func (h *ReportHandler) CreateExportTask(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.CreateExportTask(c.Request.Context(), &req)
+	operatorID := uint64(middleware.GetUserID(c))
+	data, err := h.service.CreateExportTask(c.Request.Context(), &req, operatorID)
 	if err != nil {
 		response.Fail(c, err)
 		return
@@ -238,6 +244,45 @@ func (h *ReportHandler) GetExportTask(c *gin.Context) {
 	response.OKWithData(c, data)
 }
 
+// DownloadExport 下载导出文件
+// @Summary 下载导出文件
+// @Description 使用导出任务详情中的签名链接下载，无需登录
+// @Tags 数据报告
+// @Produce octet-stream
+// @Param id path int true "任务ID"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Success 200 {file} file
+// @Router /api/v1/reports/exports/{id}/download [get]
+func (h *ReportHandler) DownloadExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, errcode.New(errcode.ErrInvalidParams))
+		return
+	}
+
+	var req dto.ExportDownloadReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, errcode.New(errcode.ErrInvalidParams))
+		return
+	}
+
+	task, file, err := h.service.OpenExportFile(c.Request.Context(), id, &req)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+	defer file.Close()
+
+	contentType := "text/csv; charset=utf-8"
+	if task.Format == model.ExportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.DataFromReader(http.StatusOK, task.FileSize, contentType, file, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q; filename*=UTF-8''%s", task.FileName, url.PathEscape(task.FileName)),
+	})
+}
+
 // GetCreativeReport 获取创意报告
 // @Summary 获取创意报告
 // @Tags 数据报告
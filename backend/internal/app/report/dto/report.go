@@ -74,13 +74,14 @@ type ReportSyncReq struct {
 	AdvertiserID uint64 `json:"advertiser_id" binding:"required"`
 	StartDate    string `json:"start_date" binding:"required"`
 	EndDate      string `json:"end_date" binding:"required"`
-	Dimension    string `json:"dimension"` // ADVERTISER, CAMPAIGN, AD
+	Dimension    string `json:"dimension"` // 逗号分隔：ADVERTISER, CAMPAIGN, AD, CREATIVE, PROJECT, PROMOTION, MATERIAL，为空同步全部
 }
 
 // ReportSyncResp 报告同步响应
 type ReportSyncResp struct {
 	SyncCount int    `json:"sync_count"`
 	SyncAt    string `json:"sync_at"`
+	Error     string `json:"error,omitempty"` // 部分维度同步失败时的错误信息
 }
 
 // ExportTaskListReq 导出任务列表请求
@@ -96,12 +97,17 @@ type ExportTaskResp struct {
 	ID           uint64 `json:"id"`
 	AdvertiserID uint64 `json:"advertiser_id"`
 	TaskType     string `json:"task_type"`
+	Format       string `json:"format"`
+	Source       string `json:"source"`
 	Status       string `json:"status"`
 	FileName     string `json:"file_name"`
 	FileSize     int64  `json:"file_size"`
 	StartDate    string `json:"start_date"`
 	EndDate      string `json:"end_date"`
 	ErrorMsg     string `json:"error_msg"`
+	DownloadURL  string `json:"download_url,omitempty"` // 已完成任务的签名下载链接
+	URLExpireAt  string `json:"url_expire_at,omitempty"`
+	FinishedAt   string `json:"finished_at,omitempty"`
 	CreatedAt    string `json:"created_at"`
 }
 
@@ -109,10 +115,18 @@ type ExportTaskResp struct {
 type ExportCreateReq struct {
 	AdvertiserID uint64 `json:"advertiser_id" binding:"required"`
 	TaskType     string `json:"task_type" binding:"required,oneof=ADVERTISER CAMPAIGN AD"`
+	Format       string `json:"format" binding:"omitempty,oneof=CSV XLSX"`     // 默认 CSV
+	Source       string `json:"source" binding:"omitempty,oneof=LOCAL REMOTE"` // 默认 LOCAL
 	StartDate    string `json:"start_date" binding:"required"`
 	EndDate      string `json:"end_date" binding:"required"`
 }
 
+// ExportDownloadReq 导出文件下载请求（签名链接参数）
+type ExportDownloadReq struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
 // CreativeReportResp 创意报告响应
 type CreativeReportResp struct {
 	CreativeID  uint64  `json:"creative_id"`
@@ -7,10 +7,11 @@ import (
 )
 
 // AdvertiserReport 广告主报告
+// 各维度报表以 (维度ID, stat_date) 唯一，同步时按该键幂等写入
 type AdvertiserReport struct {
 	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
-	StatDate     string    `gorm:"size:10;index;not null" json:"stat_date"` // YYYY-MM-DD
+	AdvertiserID uint64    `gorm:"index;uniqueIndex:uk_rpt_advertiser_date;not null" json:"advertiser_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_advertiser_date;not null" json:"stat_date"` // YYYY-MM-DD
 	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
 	Show         int64     `gorm:"default:0" json:"show"`
 	Click        int64     `gorm:"default:0" json:"click"`
@@ -33,8 +34,8 @@ func (AdvertiserReport) TableName() string {
 type CampaignReport struct {
 	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
 	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
-	CampaignID   uint64    `gorm:"index;not null" json:"campaign_id"`
-	StatDate     string    `gorm:"size:10;index;not null" json:"stat_date"`
+	CampaignID   uint64    `gorm:"index;uniqueIndex:uk_rpt_campaign_date;not null" json:"campaign_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_campaign_date;not null" json:"stat_date"`
 	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
 	Show         int64     `gorm:"default:0" json:"show"`
 	Click        int64     `gorm:"default:0" json:"click"`
@@ -58,8 +59,8 @@ type AdReport struct {
 	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
 	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
 	CampaignID   uint64    `gorm:"index;not null" json:"campaign_id"`
-	AdID         uint64    `gorm:"index;not null" json:"ad_id"`
-	StatDate     string    `gorm:"size:10;index;not null" json:"stat_date"`
+	AdID         uint64    `gorm:"index;uniqueIndex:uk_rpt_ad_date;not null" json:"ad_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_ad_date;not null" json:"stat_date"`
 	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
 	Show         int64     `gorm:"default:0" json:"show"`
 	Click        int64     `gorm:"default:0" json:"click"`
@@ -78,18 +79,142 @@ func (AdReport) TableName() string {
 	return "rpt_ad"
 }
 
+// CreativeReport 创意报告
+type CreativeReport struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
+	CampaignID   uint64    `gorm:"index;not null" json:"campaign_id"`
+	AdID         uint64    `gorm:"index;not null" json:"ad_id"`
+	CreativeID   uint64    `gorm:"uniqueIndex:uk_rpt_creative_date;not null" json:"creative_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_creative_date;not null" json:"stat_date"`
+	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
+	Show         int64     `gorm:"default:0" json:"show"`
+	Click        int64     `gorm:"default:0" json:"click"`
+	Convert      int64     `gorm:"default:0" json:"convert"`
+	CTR          float64   `gorm:"type:decimal(10,4);default:0" json:"ctr"`
+	CVR          float64   `gorm:"type:decimal(10,4);default:0" json:"cvr"`
+	CPM          float64   `gorm:"type:decimal(10,2);default:0" json:"cpm"`
+	CPC          float64   `gorm:"type:decimal(10,2);default:0" json:"cpc"`
+	ConvertCost  float64   `gorm:"type:decimal(10,2);default:0" json:"convert_cost"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (CreativeReport) TableName() string {
+	return "rpt_creative"
+}
+
+// ProjectReport 项目报告（体验版）
+type ProjectReport struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
+	ProjectID    uint64    `gorm:"uniqueIndex:uk_rpt_project_date;not null" json:"project_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_project_date;not null" json:"stat_date"`
+	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
+	Show         int64     `gorm:"default:0" json:"show"`
+	Click        int64     `gorm:"default:0" json:"click"`
+	Convert      int64     `gorm:"default:0" json:"convert"`
+	CTR          float64   `gorm:"type:decimal(10,4);default:0" json:"ctr"`
+	CVR          float64   `gorm:"type:decimal(10,4);default:0" json:"cvr"`
+	CPM          float64   `gorm:"type:decimal(10,2);default:0" json:"cpm"`
+	CPC          float64   `gorm:"type:decimal(10,2);default:0" json:"cpc"`
+	ConvertCost  float64   `gorm:"type:decimal(10,2);default:0" json:"convert_cost"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (ProjectReport) TableName() string {
+	return "rpt_project"
+}
+
+// PromotionReport 广告报告（体验版）
+type PromotionReport struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdvertiserID uint64    `gorm:"index;not null" json:"advertiser_id"`
+	ProjectID    uint64    `gorm:"index;not null" json:"project_id"`
+	PromotionID  uint64    `gorm:"uniqueIndex:uk_rpt_promotion_date;not null" json:"promotion_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_promotion_date;not null" json:"stat_date"`
+	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
+	Show         int64     `gorm:"default:0" json:"show"`
+	Click        int64     `gorm:"default:0" json:"click"`
+	Convert      int64     `gorm:"default:0" json:"convert"`
+	CTR          float64   `gorm:"type:decimal(10,4);default:0" json:"ctr"`
+	CVR          float64   `gorm:"type:decimal(10,4);default:0" json:"cvr"`
+	CPM          float64   `gorm:"type:decimal(10,2);default:0" json:"cpm"`
+	CPC          float64   `gorm:"type:decimal(10,2);default:0" json:"cpc"`
+	ConvertCost  float64   `gorm:"type:decimal(10,2);default:0" json:"convert_cost"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (PromotionReport) TableName() string {
+	return "rpt_promotion"
+}
+
+// MaterialReport 素材报告（体验版）
+// 同一素材可被多个广告主使用，唯一键包含广告主
+type MaterialReport struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdvertiserID uint64    `gorm:"index;uniqueIndex:uk_rpt_material_date;not null" json:"advertiser_id"`
+	MaterialID   uint64    `gorm:"uniqueIndex:uk_rpt_material_date;not null" json:"material_id"`
+	StatDate     string    `gorm:"size:10;index;uniqueIndex:uk_rpt_material_date;not null" json:"stat_date"`
+	Cost         float64   `gorm:"type:decimal(14,2);default:0" json:"cost"`
+	Show         int64     `gorm:"default:0" json:"show"`
+	Click        int64     `gorm:"default:0" json:"click"`
+	Convert      int64     `gorm:"default:0" json:"convert"`
+	CTR          float64   `gorm:"type:decimal(10,4);default:0" json:"ctr"`
+	CVR          float64   `gorm:"type:decimal(10,4);default:0" json:"cvr"`
+	CPM          float64   `gorm:"type:decimal(10,2);default:0" json:"cpm"`
+	CPC          float64   `gorm:"type:decimal(10,2);default:0" json:"cpc"`
+	ConvertCost  float64   `gorm:"type:decimal(10,2);default:0" json:"convert_cost"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (MaterialReport) TableName() string {
+	return "rpt_material"
+}
+
+// 报表同步维度
+const (
+	ReportLevelAdvertiser = "ADVERTISER"
+	ReportLevelCampaign   = "CAMPAIGN"
+	ReportLevelAd         = "AD"
+	ReportLevelCreative   = "CREATIVE"
+	ReportLevelProject    = "PROJECT"
+	ReportLevelPromotion  = "PROMOTION"
+	ReportLevelMaterial   = "MATERIAL"
+)
+
+// ReportLevels 全部报表同步维度
+var ReportLevels = []string{
+	ReportLevelAdvertiser, ReportLevelCampaign, ReportLevelAd, ReportLevelCreative,
+	ReportLevelProject, ReportLevelPromotion, ReportLevelMaterial,
+}
+
 // ExportTask 导出任务
 type ExportTask struct {
 	ID           uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
 	AdvertiserID uint64         `gorm:"index;not null" json:"advertiser_id"`
 	TaskType     string         `gorm:"size:32;not null" json:"task_type"`
-	Status       string         `gorm:"size:32;default:'PENDING'" json:"status"`
+	Format       string         `gorm:"size:8;default:'CSV'" json:"format"`
+	Source       string         `gorm:"size:16;default:'LOCAL'" json:"source"`
+	Status       string         `gorm:"size:32;default:'PENDING';index" json:"status"`
 	FileName     string         `gorm:"size:255" json:"file_name"`
 	FilePath     string         `gorm:"size:512" json:"file_path"`
 	FileSize     int64          `gorm:"default:0" json:"file_size"`
 	StartDate    string         `gorm:"size:10" json:"start_date"`
 	EndDate      string         `gorm:"size:10" json:"end_date"`
 	ErrorMsg     string         `gorm:"size:500" json:"error_msg"`
+	RemoteTaskID string         `gorm:"size:64" json:"remote_task_id"` // 巨量异步报表任务ID
+	Attempts     int            `gorm:"default:0" json:"attempts"`     // 领取次数
+	LeaseUntil   *time.Time     `gorm:"index" json:"-"`                // 处理租约到期时间
+	FinishedAt   *time.Time     `json:"finished_at"`
+	CreatedBy    uint64         `gorm:"default:0" json:"created_by"`
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -114,3 +239,15 @@ const (
 	ExportTypeCampaign   = "CAMPAIGN"
 	ExportTypeAd         = "AD"
 )
+
+// 导出文件格式
+const (
+	ExportFormatCSV  = "CSV"
+	ExportFormatXLSX = "XLSX"
+)
+
+// 导出数据来源
+const (
+	ExportSourceLocal  = "LOCAL"  // 本地已同步的报表
+	ExportSourceRemote = "REMOTE" // 巨量引擎异步报表
+)
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"oceanengine-backend/internal/app/report/dto"
 	"oceanengine-backend/internal/app/report/model"
+	"oceanengine-backend/internal/datascope"
 )
 
 // ReportRepository 报告仓储接口
@@ -23,11 +27,18 @@ type ReportRepository interface {
 	GetAdReport(ctx context.Context, req *dto.ReportQueryReq) ([]*model.AdReport, error)
 	SaveAdReport(ctx context.Context, report *model.AdReport) error
 
+	// 创意报告
+	GetCreativeReport(ctx context.Context, req *dto.ReportQueryReq) ([]*model.CreativeReport, error)
+
+	// UpsertReports 按唯一键批量写入报表，已存在的行覆盖指标
+	UpsertReports(ctx context.Context, rows interface{}, conflictColumns ...string) error
+
 	// 导出任务
 	GetExportTaskList(ctx context.Context, req *dto.ExportTaskListReq) ([]*model.ExportTask, int64, error)
 	GetExportTaskByID(ctx context.Context, id uint64) (*model.ExportTask, error)
 	CreateExportTask(ctx context.Context, task *model.ExportTask) error
 	UpdateExportTask(ctx context.Context, task *model.ExportTask) error
+	ClaimExportTasks(ctx context.Context, limit int, lease time.Duration) ([]*model.ExportTask, error)
 }
 
 type reportRepository struct {
@@ -44,6 +55,7 @@ func (r *reportRepository) GetAdvertiserReport(ctx context.Context, req *dto.Rep
 	var list []*model.AdvertiserReport
 
 	query := r.db.WithContext(ctx).Model(&model.AdvertiserReport{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("advertiser_id = ?", req.AdvertiserID).
 		Where("stat_date >= ?", req.StartDate).
 		Where("stat_date <= ?", req.EndDate)
@@ -60,6 +72,7 @@ func (r *reportRepository) GetAdvertiserSummary(ctx context.Context, req *dto.Re
 	var result dto.ReportSummaryResp
 
 	err := r.db.WithContext(ctx).Model(&model.AdvertiserReport{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Select("COALESCE(SUM(cost), 0) as cost, COALESCE(SUM(show), 0) as show, COALESCE(SUM(click), 0) as click, COALESCE(SUM(convert), 0) as convert").
 		Where("advertiser_id = ?", req.AdvertiserID).
 		Where("stat_date >= ?", req.StartDate).
@@ -99,6 +112,7 @@ func (r *reportRepository) GetCampaignReport(ctx context.Context, req *dto.Repor
 	var list []*model.CampaignReport
 
 	query := r.db.WithContext(ctx).Model(&model.CampaignReport{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("advertiser_id = ?", req.AdvertiserID).
 		Where("stat_date >= ?", req.StartDate).
 		Where("stat_date <= ?", req.EndDate)
@@ -127,6 +141,7 @@ func (r *reportRepository) GetAdReport(ctx context.Context, req *dto.ReportQuery
 	var list []*model.AdReport
 
 	query := r.db.WithContext(ctx).Model(&model.AdReport{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
 		Where("advertiser_id = ?", req.AdvertiserID).
 		Where("stat_date >= ?", req.StartDate).
 		Where("stat_date <= ?", req.EndDate)
@@ -153,12 +168,56 @@ func (r *reportRepository) SaveAdReport(ctx context.Context, report *model.AdRep
 		FirstOrCreate(report).Error
 }
 
+// GetCreativeReport 获取创意报告
+func (r *reportRepository) GetCreativeReport(ctx context.Context, req *dto.ReportQueryReq) ([]*model.CreativeReport, error) {
+	var list []*model.CreativeReport
+
+	query := r.db.WithContext(ctx).Model(&model.CreativeReport{}).
+		Scopes(datascope.ByAdvertiser(ctx)).
+		Where("advertiser_id = ?", req.AdvertiserID).
+		Where("stat_date >= ?", req.StartDate).
+		Where("stat_date <= ?", req.EndDate)
+
+	if req.CampaignID > 0 {
+		query = query.Where("campaign_id = ?", req.CampaignID)
+	}
+	if req.AdID > 0 {
+		query = query.Where("ad_id = ?", req.AdID)
+	}
+
+	if err := query.Order("stat_date ASC, creative_id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// upsertBatchSize 批量写入报表的每批行数
+const upsertBatchSize = 200
+
+// UpsertReports 按唯一键批量写入报表
+// rows 为报表模型切片，conflictColumns 与模型上的唯一索引一致，冲突时覆盖除主键和创建时间外的全部字段
+func (r *reportRepository) UpsertReports(ctx context.Context, rows interface{}, conflictColumns ...string) error {
+	if v := reflect.ValueOf(rows); v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil
+	}
+
+	columns := make([]clause.Column, 0, len(conflictColumns))
+	for _, name := range conflictColumns {
+		columns = append(columns, clause.Column{Name: name})
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: columns, UpdateAll: true}).
+		CreateInBatches(rows, upsertBatchSize).Error
+}
+
 // GetExportTaskList 获取导出任务列表
 func (r *reportRepository) GetExportTaskList(ctx context.Context, req *dto.ExportTaskListReq) ([]*model.ExportTask, int64, error) {
 	var list []*model.ExportTask
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.ExportTask{})
+	query := r.db.WithContext(ctx).Model(&model.ExportTask{}).Scopes(datascope.ByAdvertiser(ctx))
 
 	if req.AdvertiserID > 0 {
 		query = query.Where("advertiser_id = ?", req.AdvertiserID)
@@ -194,6 +253,9 @@ func (r *reportRepository) GetExportTaskByID(ctx context.Context, id uint64) (*m
 	if err := r.db.WithContext(ctx).First(&task, id).Error; err != nil {
 		return nil, err
 	}
+	if err := datascope.Check(ctx, task.AdvertiserID); err != nil {
+		return nil, err
+	}
 	return &task, nil
 }
 
@@ -206,3 +268,50 @@ func (r *reportRepository) CreateExportTask(ctx context.Context, task *model.Exp
 func (r *reportRepository) UpdateExportTask(ctx context.Context, task *model.ExportTask) error {
 	return r.db.WithContext(ctx).Save(task).Error
 }
+
+// ClaimExportTasks 领取待处理的导出任务
+// 待处理任务及租约已过期的处理中任务（worker 异常退出）均可被领取；
+// 通过带条件的 UPDATE 抢占，多个 worker 并发时每个任务只会被一个 worker 领取
+func (r *reportRepository) ClaimExportTasks(ctx context.Context, limit int, lease time.Duration) ([]*model.ExportTask, error) {
+	now := time.Now()
+	claimable := func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? OR (status = ? AND lease_until < ?)",
+			model.ExportStatusPending, model.ExportStatusProcessing, now)
+	}
+
+	var ids []uint64
+	if err := r.db.WithContext(ctx).Model(&model.ExportTask{}).
+		Scopes(claimable).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease)
+	claimed := make([]*model.ExportTask, 0, len(ids))
+	for _, id := range ids {
+		result := r.db.WithContext(ctx).Model(&model.ExportTask{}).
+			Where("id = ?", id).
+			Scopes(claimable).
+			Updates(map[string]interface{}{
+				"status":      model.ExportStatusProcessing,
+				"lease_until": leaseUntil,
+				"attempts":    gorm.Expr("attempts + 1"),
+			})
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		var task model.ExportTask
+		if err := r.db.WithContext(ctx).First(&task, id).Error; err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, &task)
+	}
+
+	return claimed, nil
+}
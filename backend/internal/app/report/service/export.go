@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advRepo "oceanengine-backend/internal/app/advertiser/repository"
+	"oceanengine-backend/internal/app/report/dto"
+	"oceanengine-backend/internal/app/report/model"
+	"oceanengine-backend/internal/app/report/repository"
+	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/storage"
+	"oceanengine-backend/pkg/utils"
+)
+
+// remoteDownloadPageSize 下载巨量异步报表结果的分页大小
+const remoteDownloadPageSize = 1000
+
+// ExportFiles 导出文件存储与下载链接签名
+// API 服务用于生成/校验下载链接，定时任务服务用于写入文件，两者需使用相同的存储与签名密钥
+type ExportFiles struct {
+	Storage   storage.Storage
+	Signer    *storage.Signer
+	URLExpire time.Duration
+}
+
+// NewExportFiles 根据配置创建导出文件存储
+func NewExportFiles(storageCfg *config.StorageConfig, exportCfg *config.ExportConfig) (*ExportFiles, error) {
+	if storageCfg.SignSecret == "" {
+		return nil, errors.New("storage.sign_secret 未配置")
+	}
+	st, err := storage.New(storageCfg)
+	if err != nil {
+		return nil, err
+	}
+	expire := exportCfg.URLExpire
+	if expire <= 0 {
+		expire = time.Hour
+	}
+	return &ExportFiles{
+		Storage:   st,
+		Signer:    storage.NewSigner(storageCfg.SignSecret),
+		URLExpire: expire,
+	}, nil
+}
+
+// DownloadURL 生成导出文件的签名下载链接
+func (f *ExportFiles) DownloadURL(task *model.ExportTask) (string, time.Time) {
+	expireAt := time.Now().Add(f.URLExpire)
+	expires, signature := f.Signer.Sign(exportResource(task), expireAt)
+	url := fmt.Sprintf("/api/v1/reports/exports/%d/download?expires=%d&signature=%s", task.ID, expires, signature)
+	return url, time.Unix(expires, 0)
+}
+
+// Verify 校验下载链接签名
+func (f *ExportFiles) Verify(task *model.ExportTask, expires int64, signature string) error {
+	return f.Signer.Verify(exportResource(task), expires, signature)
+}
+
+// exportResource 签名资源标识，包含文件路径，文件重新生成后旧链接失效
+func exportResource(task *model.ExportTask) string {
+	return fmt.Sprintf("export:%d:%s", task.ID, task.FilePath)
+}
+
+// AccessTokenProvider 广告主 Access Token 提供者
+type AccessTokenProvider interface {
+	GetAccessToken(ctx context.Context, advertiserID uint64) (string, error)
+}
+
+// ExportWorker 报表导出任务执行器
+// 领取待处理任务，从本地报表或巨量异步报表拉取数据，生成 CSV/XLSX 写入存储
+type ExportWorker struct {
+	repo    repository.ReportRepository
+	advRepo advRepo.AdvertiserRepository
+	tokens  AccessTokenProvider
+	client  *oceanengine.Client
+	files   *ExportFiles
+	cfg     *config.ExportConfig
+	log     *zap.Logger
+}
+
+// NewExportWorker 创建报表导出任务执行器
+func NewExportWorker(db *gorm.DB, client *oceanengine.Client, tokens AccessTokenProvider, files *ExportFiles, cfg *config.ExportConfig, log *zap.Logger) *ExportWorker {
+	return &ExportWorker{
+		repo:    repository.NewReportRepository(db),
+		advRepo: advRepo.NewAdvertiserRepository(db),
+		tokens:  tokens,
+		client:  client,
+		files:   files,
+		cfg:     cfg,
+		log:     log,
+	}
+}
+
+// RunOnce 领取并处理一批导出任务，返回处理的任务数
+func (w *ExportWorker) RunOnce(ctx context.Context) (int, error) {
+	tasks, err := w.repo.ClaimExportTasks(ctx, w.cfg.BatchSize, w.cfg.LeaseTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("领取导出任务失败: %w", err)
+	}
+
+	for _, task := range tasks {
+		w.process(ctx, task)
+	}
+	return len(tasks), nil
+}
+
+// process 处理单个导出任务
+func (w *ExportWorker) process(ctx context.Context, task *model.ExportTask) {
+	err := w.export(ctx, task)
+	if err != nil && ctx.Err() != nil {
+		// 服务关闭，保持处理中状态，租约到期后由其他 worker 重新领取
+		w.log.Info(fmt.Sprintf("导出任务 %d 被中断，等待重新领取", task.ID))
+		return
+	}
+
+	now := time.Now()
+	task.FinishedAt = &now
+	task.LeaseUntil = nil
+	if err != nil {
+		task.Status = model.ExportStatusFailed
+		task.ErrorMsg = utils.TruncateRunes(err.Error(), 500)
+		w.log.Warn(fmt.Sprintf("导出任务 %d 失败: %v", task.ID, err))
+	} else {
+		task.Status = model.ExportStatusCompleted
+		task.ErrorMsg = ""
+		w.log.Info(fmt.Sprintf("导出任务 %d 完成: %s (%d bytes)", task.ID, task.FileName, task.FileSize))
+	}
+
+	if err := w.repo.UpdateExportTask(context.WithoutCancel(ctx), task); err != nil {
+		w.log.Error(fmt.Sprintf("更新导出任务 %d 状态失败: %v", task.ID, err))
+	}
+}
+
+// export 拉取数据并写入存储
+func (w *ExportWorker) export(ctx context.Context, task *model.ExportTask) error {
+	var (
+		table *exportTable
+		err   error
+	)
+	if task.Source == model.ExportSourceRemote {
+		table, err = w.loadRemote(ctx, task)
+	} else {
+		table, err = w.loadLocal(ctx, task)
+	}
+	if err != nil {
+		return err
+	}
+
+	format := task.Format
+	if format == "" {
+		format = model.ExportFormatCSV
+	}
+	write := writeCSV
+	ext := "csv"
+	if format == model.ExportFormatXLSX {
+		write = writeXLSX
+		ext = "xlsx"
+	}
+
+	key := fmt.Sprintf("exports/%s/%d_%d.%s", task.CreatedAt.Format("20060102"), task.ID, time.Now().Unix(), ext)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(write(pw, table))
+	}()
+	size, err := w.files.Storage.Put(ctx, key, pr)
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+
+	// 清理重新领取前生成的旧文件
+	if task.FilePath != "" && task.FilePath != key {
+		_ = w.files.Storage.Delete(ctx, task.FilePath)
+	}
+
+	task.Format = format
+	task.FilePath = key
+	task.FileSize = size
+	task.FileName = fmt.Sprintf("report_%s_%s_%s_%d.%s", task.TaskType, task.StartDate, task.EndDate, task.ID, ext)
+	return nil
+}
+
+// loadLocal 从本地已同步的报表读取数据
+func (w *ExportWorker) loadLocal(ctx context.Context, task *model.ExportTask) (*exportTable, error) {
+	req := &dto.ReportQueryReq{
+		AdvertiserID: task.AdvertiserID,
+		StartDate:    task.StartDate,
+		EndDate:      task.EndDate,
+	}
+	metricHeader := []string{"消耗", "展示数", "点击数", "转化数", "点击率", "转化率", "平均千次展示费用", "平均点击单价", "转化成本"}
+
+	switch task.TaskType {
+	case model.ExportTypeAdvertiser:
+		list, err := w.repo.GetAdvertiserReport(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		t := &exportTable{Header: append([]string{"日期"}, metricHeader...)}
+		for _, r := range list {
+			t.Rows = append(t.Rows, append([]string{r.StatDate},
+				metricCells(r.Cost, r.Show, r.Click, r.Convert, r.CTR, r.CVR, r.CPM, r.CPC, r.ConvertCost)...))
+		}
+		return t, nil
+
+	case model.ExportTypeCampaign:
+		list, err := w.repo.GetCampaignReport(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		t := &exportTable{Header: append([]string{"日期", "广告系列ID"}, metricHeader...)}
+		for _, r := range list {
+			t.Rows = append(t.Rows, append([]string{r.StatDate, strconv.FormatUint(r.CampaignID, 10)},
+				metricCells(r.Cost, r.Show, r.Click, r.Convert, r.CTR, r.CVR, r.CPM, r.CPC, r.ConvertCost)...))
+		}
+		return t, nil
+
+	case model.ExportTypeAd:
+		list, err := w.repo.GetAdReport(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		t := &exportTable{Header: append([]string{"日期", "广告系列ID", "广告组ID"}, metricHeader...)}
+		for _, r := range list {
+			t.Rows = append(t.Rows, append([]string{r.StatDate, strconv.FormatUint(r.CampaignID, 10), strconv.FormatUint(r.AdID, 10)},
+				metricCells(r.Cost, r.Show, r.Click, r.Convert, r.CTR, r.CVR, r.CPM, r.CPC, r.ConvertCost)...))
+		}
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("不支持的导出类型: %s", task.TaskType)
+}
+
+// loadRemote 通过巨量异步报表拉取数据：创建任务 → 轮询状态 → 分页下载
+// 远端任务ID会先落库，任务被重新领取时复用，避免重复创建
+func (w *ExportWorker) loadRemote(ctx context.Context, task *model.ExportTask) (*exportTable, error) {
+	dataLevel, ok := map[string]string{
+		model.ExportTypeAdvertiser: "ACCOUNT",
+		model.ExportTypeCampaign:   "CAMPAIGN",
+		model.ExportTypeAd:         "AD",
+	}[task.TaskType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的导出类型: %s", task.TaskType)
+	}
+
+	adv, err := w.advRepo.GetByID(ctx, task.AdvertiserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取广告主失败: %w", err)
+	}
+	token, err := w.tokens.GetAccessToken(ctx, adv.AdvertiserID)
+	if err != nil {
+		return nil, err
+	}
+	svc := oceanengine.NewAsyncReportService(w.client.WithAccessToken(token))
+	oeAdvertiserID := int64(adv.AdvertiserID)
+
+	if task.RemoteTaskID == "" {
+		req := &oceanengine.AsyncReportTaskCreateRequest{
+			AdvertiserID: oeAdvertiserID,
+			TaskName:     fmt.Sprintf("export_%d", task.ID),
+			TaskType:     "REPORT",
+		}
+		req.TaskParams.DataLevel = dataLevel
+		req.TaskParams.TimeGranularity = "DAILY"
+		req.TaskParams.StartDate = task.StartDate
+		req.TaskParams.EndDate = task.EndDate
+
+		resp, err := svc.CreateTask(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("创建异步报表任务失败: %w", err)
+		}
+		task.RemoteTaskID = resp.TaskID
+		if err := w.repo.UpdateExportTask(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.waitRemote(ctx, svc, oeAdvertiserID, task.RemoteTaskID); err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	for page := 1; ; page++ {
+		resp, err := svc.Download(ctx, &oceanengine.AsyncReportTaskDownloadRequest{
+			AdvertiserID: oeAdvertiserID,
+			TaskID:       task.RemoteTaskID,
+			Page:         page,
+			PageSize:     remoteDownloadPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("下载异步报表失败: %w", err)
+		}
+		rows = append(rows, resp.List...)
+		if len(resp.List) == 0 || page >= resp.PageInfo.TotalPage {
+			break
+		}
+	}
+
+	return mapsToTable(rows), nil
+}
+
+// waitRemote 轮询巨量异步报表任务直到完成
+func (w *ExportWorker) waitRemote(ctx context.Context, svc *oceanengine.AsyncReportService, advertiserID int64, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.RemoteTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(w.cfg.RemoteInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := svc.GetTasks(ctx, &oceanengine.AsyncReportTaskGetRequest{
+			AdvertiserID: advertiserID,
+			TaskIDs:      []string{taskID},
+		})
+		if err != nil {
+			return fmt.Errorf("查询异步报表任务失败: %w", err)
+		}
+		if len(resp.List) > 0 {
+			switch t := resp.List[0]; t.TaskStatus {
+			case "SUCCESS":
+				return nil
+			case "FAILED":
+				return fmt.Errorf("异步报表任务失败: %s", t.ErrorMessage)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("等待异步报表任务 %s 超时", taskID)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// mapsToTable 将异步报表结果转换为表格，列按字段名排序
+func mapsToTable(rows []map[string]interface{}) *exportTable {
+	seen := make(map[string]struct{})
+	var header []string
+	for _, row := range rows {
+		for k := range row {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				header = append(header, k)
+			}
+		}
+	}
+	sort.Strings(header)
+
+	t := &exportTable{Header: header, Rows: make([][]string, 0, len(rows))}
+	for _, row := range rows {
+		cells := make([]string, len(header))
+		for i, k := range header {
+			if v, ok := row[k]; ok && v != nil {
+				cells[i] = formatCell(v)
+			}
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+	return t
+}
+
+func formatCell(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func metricCells(cost float64, show, click, convert int64, ctr, cvr, cpm, cpc, convertCost float64) []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []string{
+		f(cost),
+		strconv.FormatInt(show, 10),
+		strconv.FormatInt(click, 10),
+		strconv.FormatInt(convert, 10),
+		f(ctr), f(cvr), f(cpm), f(cpc), f(convertCost),
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/report/model"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+type staticTokens string
+
+func (s staticTokens) GetAccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	return string(s), nil
+}
+
+func newExportTestEnv(t *testing.T) (*gorm.DB, *ExportFiles) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}, &model.AdvertiserReport{}, &model.CampaignReport{}, &model.ExportTask{}))
+
+	files, err := NewExportFiles(
+		&config.StorageConfig{LocalPath: t.TempDir(), SignSecret: "secret"},
+		&config.ExportConfig{URLExpire: time.Minute},
+	)
+	require.NoError(t, err)
+	return db, files
+}
+
+func readExportFile(t *testing.T, files *ExportFiles, task *model.ExportTask) []byte {
+	rc, err := files.Storage.Open(context.Background(), task.FilePath)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return data
+}
+
+func TestExportWorker_Local(t *testing.T) {
+	db, files := newExportTestEnv(t)
+	require.NoError(t, db.Create(&model.CampaignReport{AdvertiserID: 1, CampaignID: 12345678901234567, StatDate: "2024-01-01", Cost: 1.5, Show: 10}).Error)
+	csvTask := &model.ExportTask{AdvertiserID: 1, TaskType: model.ExportTypeCampaign, Format: model.ExportFormatCSV, Status: model.ExportStatusPending, StartDate: "2024-01-01", EndDate: "2024-01-31"}
+	xlsxTask := &model.ExportTask{AdvertiserID: 1, TaskType: model.ExportTypeCampaign, Format: model.ExportFormatXLSX, Status: model.ExportStatusPending, StartDate: "2024-01-01", EndDate: "2024-01-31"}
+	badTask := &model.ExportTask{AdvertiserID: 1, TaskType: "UNKNOWN", Status: model.ExportStatusPending}
+	require.NoError(t, db.Create(csvTask).Error)
+	require.NoError(t, db.Create(xlsxTask).Error)
+	require.NoError(t, db.Create(badTask).Error)
+
+	worker := NewExportWorker(db, nil, nil, files, &config.ExportConfig{BatchSize: 10, LeaseTimeout: time.Minute}, zap.NewNop())
+	n, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	require.NoError(t, db.First(csvTask, csvTask.ID).Error)
+	assert.Equal(t, model.ExportStatusCompleted, csvTask.Status)
+	assert.NotNil(t, csvTask.FinishedAt)
+	assert.Nil(t, csvTask.LeaseUntil)
+	data := readExportFile(t, files, csvTask)
+	assert.Equal(t, int64(len(data)), csvTask.FileSize)
+	assert.True(t, bytes.HasPrefix(data, []byte("\xEF\xBB\xBF日期,广告系列ID,消耗")))
+	assert.Contains(t, string(data), "2024-01-01,12345678901234567,1.5,10,")
+
+	require.NoError(t, db.First(xlsxTask, xlsxTask.ID).Error)
+	assert.Equal(t, model.ExportStatusCompleted, xlsxTask.Status)
+	assert.True(t, strings.HasSuffix(xlsxTask.FileName, ".xlsx"))
+	data = readExportFile(t, files, xlsxTask)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(b)
+		}
+	}
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t>12345678901234567</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2"><v>1.5</v></c>`)
+
+	require.NoError(t, db.First(badTask, badTask.ID).Error)
+	assert.Equal(t, model.ExportStatusFailed, badTask.Status)
+	assert.Contains(t, badTask.ErrorMsg, "UNKNOWN")
+
+	// 已处理的任务不会被再次领取
+	n, err = worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestExportWorker_ReclaimExpiredLease(t *testing.T) {
+	db, files := newExportTestEnv(t)
+	expired := time.Now().Add(-time.Minute)
+	active := time.Now().Add(time.Hour)
+	stale := &model.ExportTask{AdvertiserID: 1, TaskType: model.ExportTypeAdvertiser, Status: model.ExportStatusProcessing, LeaseUntil: &expired}
+	running := &model.ExportTask{AdvertiserID: 1, TaskType: model.ExportTypeAdvertiser, Status: model.ExportStatusProcessing, LeaseUntil: &active}
+	require.NoError(t, db.Create(stale).Error)
+	require.NoError(t, db.Create(running).Error)
+
+	worker := NewExportWorker(db, nil, nil, files, &config.ExportConfig{BatchSize: 10, LeaseTimeout: time.Minute}, zap.NewNop())
+	n, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.NoError(t, db.First(stale, stale.ID).Error)
+	assert.Equal(t, model.ExportStatusCompleted, stale.Status)
+	assert.Equal(t, 1, stale.Attempts)
+	require.NoError(t, db.First(running, running.ID).Error)
+	assert.Equal(t, model.ExportStatusProcessing, running.Status)
+}
+
+func TestExportWorker_Remote(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "remote-token", r.Header.Get("Access-Token"))
+		var data interface{}
+		switch r.URL.Path {
+		case "/open_api/2/async_task/create/":
+			data = map[string]interface{}{"task_id": "T1"}
+		case "/open_api/2/async_task/get/":
+			status := "PROCESSING"
+			if atomic.AddInt32(&polls, 1) > 1 {
+				status = "SUCCESS"
+			}
+			data = map[string]interface{}{"list": []map[string]interface{}{{"task_id": "T1", "task_status": status}}}
+		case "/open_api/2/async_task/download/":
+			var req map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			row := map[string]interface{}{"stat_datetime": "2024-01-01", "cost": 1.25, "ad_id": int64(1790000000000000123)}
+			if req["page"] == float64(2) {
+				row = map[string]interface{}{"stat_datetime": "2024-01-02", "show_cnt": 7}
+			}
+			data = map[string]interface{}{
+				"list":      []map[string]interface{}{row},
+				"page_info": map[string]interface{}{"page": req["page"], "total_page": 2},
+			}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": data})
+	}))
+	defer server.Close()
+
+	db, files := newExportTestEnv(t)
+	adv := &advModel.Advertiser{AdvertiserID: 1001, Name: "remote"}
+	require.NoError(t, db.Create(adv).Error)
+	task := &model.ExportTask{AdvertiserID: adv.ID, TaskType: model.ExportTypeAdvertiser, Source: model.ExportSourceRemote, Status: model.ExportStatusPending, StartDate: "2024-01-01", EndDate: "2024-01-02"}
+	require.NoError(t, db.Create(task).Error)
+
+	client := oceanengine.NewClient("app", "secret", oceanengine.WithBaseURL(server.URL+"/open_api"), oceanengine.WithRetryCount(0))
+	cfg := &config.ExportConfig{BatchSize: 1, LeaseTimeout: time.Minute, RemoteTimeout: 5 * time.Second, RemoteInterval: 10 * time.Millisecond}
+	worker := NewExportWorker(db, client, staticTokens("remote-token"), files, cfg, zap.NewNop())
+
+	n, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.NoError(t, db.First(task, task.ID).Error)
+	require.Equal(t, model.ExportStatusCompleted, task.Status, task.ErrorMsg)
+	assert.Equal(t, "T1", task.RemoteTaskID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&polls))
+	data := string(readExportFile(t, files, task))
+	assert.Contains(t, data, "ad_id,cost,show_cnt,stat_datetime\n1790000000000000123,1.25,,2024-01-01\n,,7,2024-01-02\n")
+}
+
+func TestExportFiles_DownloadURL(t *testing.T) {
+	_, files := newExportTestEnv(t)
+	task := &model.ExportTask{ID: 7, FilePath: "exports/20240101/7_1.csv"}
+
+	link, _ := files.DownloadURL(task)
+	require.True(t, strings.HasPrefix(link, "/api/v1/reports/exports/7/download?expires="))
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := u.Query().Get("signature")
+	assert.NoError(t, files.Verify(task, expires, signature))
+
+	// 文件重新生成后旧链接失效
+	task.FilePath = "exports/20240101/7_2.csv"
+	assert.Error(t, files.Verify(task, expires, signature))
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,28 +12,39 @@ import (
 	"oceanengine-backend/internal/app/report/dto"
 	"oceanengine-backend/internal/app/report/model"
 	"oceanengine-backend/internal/app/report/repository"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
-	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/storage"
 )
 
 // ReportService 报告服务
 type ReportService struct {
+	db       *gorm.DB
 	repo     repository.ReportRepository
 	advRepo  advRepo.AdvertiserRepository
 	oceanCfg *config.OceanConfig
+	files    *ExportFiles
 }
 
 // NewReportService 创建报告服务
-func NewReportService(db *gorm.DB, oceanCfg *config.OceanConfig) *ReportService {
+// files 为导出文件存储，为 nil 时导出任务仍可创建，但无法下载
+func NewReportService(db *gorm.DB, oceanCfg *config.OceanConfig, files *ExportFiles) *ReportService {
 	return &ReportService{
+		db:       db,
 		repo:     repository.NewReportRepository(db),
 		advRepo:  advRepo.NewAdvertiserRepository(db),
 		oceanCfg: oceanCfg,
+		files:    files,
 	}
 }
 
 // GetAdvertiserReport 获取广告主报告
 func (s *ReportService) GetAdvertiserReport(ctx context.Context, req *dto.ReportQueryReq) ([]*dto.ReportDetailResp, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+
 	list, err := s.repo.GetAdvertiserReport(ctx, req)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
@@ -58,6 +71,10 @@ func (s *ReportService) GetAdvertiserReport(ctx context.Context, req *dto.Report
 
 // GetAdvertiserSummary 获取广告主汇总报告
 func (s *ReportService) GetAdvertiserSummary(ctx context.Context, req *dto.ReportQueryReq) (*dto.ReportSummaryResp, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+
 	result, err := s.repo.GetAdvertiserSummary(ctx, req)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
@@ -68,6 +85,10 @@ func (s *ReportService) GetAdvertiserSummary(ctx context.Context, req *dto.Repor
 
 // GetCampaignReport 获取广告系列报告
 func (s *ReportService) GetCampaignReport(ctx context.Context, req *dto.ReportQueryReq) ([]*dto.CampaignReportResp, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+
 	list, err := s.repo.GetCampaignReport(ctx, req)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
@@ -95,6 +116,10 @@ func (s *ReportService) GetCampaignReport(ctx context.Context, req *dto.ReportQu
 
 // GetAdReport 获取广告组报告
 func (s *ReportService) GetAdReport(ctx context.Context, req *dto.ReportQueryReq) ([]*dto.AdReportResp, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+
 	list, err := s.repo.GetAdReport(ctx, req)
 	if err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
@@ -121,10 +146,24 @@ func (s *ReportService) GetAdReport(ctx context.Context, req *dto.ReportQueryReq
 }
 
 // SyncReport 同步报告数据
+// Dimension 为逗号分隔的维度列表，为空时同步全部维度；单次同步的日期跨度不超过 MaxSyncRangeDays
 func (s *ReportService) SyncReport(ctx context.Context, req *dto.ReportSyncReq) (*dto.ReportSyncResp, error) {
+	start, err1 := time.Parse("2006-01-02", req.StartDate)
+	end, err2 := time.Parse("2006-01-02", req.EndDate)
+	if err1 != nil || err2 != nil || end.Before(start) || end.Sub(start) >= MaxSyncRangeDays*24*time.Hour {
+		return nil, errcode.New(errcode.ErrReportDateRange)
+	}
+	levels, err := ParseReportLevels(req.Dimension)
+	if err != nil {
+		return nil, errcode.New(errcode.ErrInvalidParams)
+	}
+
 	// 获取广告主信息
 	adv, err := s.advRepo.GetByID(ctx, req.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 	}
 
@@ -132,71 +171,21 @@ func (s *ReportService) SyncReport(ctx context.Context, req *dto.ReportSyncReq)
 		return nil, errcode.New(errcode.ErrOETokenInvalid)
 	}
 
-	// 创建SDK客户端
-	client := oceanengine.NewClient(s.oceanCfg.AppID, s.oceanCfg.Secret)
-	client.SetAccessToken(adv.AccessToken)
-
-	reportService := oceanengine.NewReportService(client)
-	syncCount := 0
-
-	// 同步广告主维度报告
-	reportReq := &oceanengine.ReportRequest{
-		AdvertiserID: int64(adv.AdvertiserID),
-		StartDate:    req.StartDate,
-		EndDate:      req.EndDate,
-	}
-	reports, _, err := reportService.GetAdvertiserReport(ctx, reportReq)
-	if err == nil {
-		for _, r := range reports {
-			report := &model.AdvertiserReport{
-				AdvertiserID: req.AdvertiserID,
-				StatDate:     r.StatDatetime,
-				Cost:         r.Cost,
-				Show:         r.ShowCnt,
-				Click:        r.ClickCnt,
-				Convert:      r.ConvertCnt,
-				CTR:          r.CTR,
-				CVR:          r.ConvertRate,
-				CPM:          r.CPM,
-				CPC:          r.CPC,
-				ConvertCost:  r.ConvertCost,
-			}
-			if err := s.repo.SaveAdvertiserReport(ctx, report); err == nil {
-				syncCount++
-			}
-		}
-	}
-
-	// 同步广告系列维度报告
-	if req.Dimension == "" || req.Dimension == "CAMPAIGN" {
-		campaignReports, _, err := reportService.GetCampaignReport(ctx, reportReq)
-		if err == nil {
-			for _, r := range campaignReports {
-				report := &model.CampaignReport{
-					AdvertiserID: req.AdvertiserID,
-					CampaignID:   uint64(r.CampaignID),
-					StatDate:     r.StatDatetime,
-					Cost:         r.Cost,
-					Show:         r.ShowCnt,
-					Click:        r.ClickCnt,
-					Convert:      r.ConvertCnt,
-					CTR:          r.CTR,
-					CVR:          r.ConvertRate,
-					CPM:          r.CPM,
-					CPC:          r.CPC,
-					ConvertCost:  r.ConvertCost,
-				}
-				if err := s.repo.SaveCampaignReport(ctx, report); err == nil {
-					syncCount++
-				}
-			}
-		}
+	syncer := NewReportSyncer(s.db, oceanclient.New(s.oceanCfg))
+	target := SyncTarget{ID: adv.ID, AdvertiserID: adv.AdvertiserID, AccessToken: adv.AccessToken}
+	syncCount, err := syncer.Sync(ctx, target, req.StartDate, req.EndDate, levels)
+	if err != nil && syncCount == 0 {
+		return nil, errcode.Wrap(errcode.ErrOEAPIFailed, err)
 	}
 
-	return &dto.ReportSyncResp{
+	resp := &dto.ReportSyncResp{
 		SyncCount: syncCount,
 		SyncAt:    time.Now().Format("2006-01-02 15:04:05"),
-	}, nil
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
 }
 
 // GetExportTaskList 获取导出任务列表
@@ -208,80 +197,153 @@ func (s *ReportService) GetExportTaskList(ctx context.Context, req *dto.ExportTa
 
 	result := make([]*dto.ExportTaskResp, len(list))
 	for i, t := range list {
-		result[i] = &dto.ExportTaskResp{
-			ID:           t.ID,
-			AdvertiserID: t.AdvertiserID,
-			TaskType:     t.TaskType,
-			Status:       t.Status,
-			FileName:     t.FileName,
-			FileSize:     t.FileSize,
-			StartDate:    t.StartDate,
-			EndDate:      t.EndDate,
-			ErrorMsg:     t.ErrorMsg,
-			CreatedAt:    t.CreatedAt.Format("2006-01-02 15:04:05"),
-		}
+		result[i] = s.toExportTaskResp(t)
 	}
 
 	return result, total, nil
 }
 
 // CreateExportTask 创建导出任务
-func (s *ReportService) CreateExportTask(ctx context.Context, req *dto.ExportCreateReq) (*dto.ExportTaskResp, error) {
+// 任务创建后由定时任务服务中的 ExportWorker 异步处理
+func (s *ReportService) CreateExportTask(ctx context.Context, req *dto.ExportCreateReq, operatorID uint64) (*dto.ExportTaskResp, error) {
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+	if req.StartDate > req.EndDate {
+		return nil, errcode.New(errcode.ErrReportDateRange)
+	}
+
 	task := &model.ExportTask{
 		AdvertiserID: req.AdvertiserID,
 		TaskType:     req.TaskType,
+		Format:       req.Format,
+		Source:       req.Source,
 		Status:       model.ExportStatusPending,
 		StartDate:    req.StartDate,
 		EndDate:      req.EndDate,
+		CreatedBy:    operatorID,
+	}
+	if task.Format == "" {
+		task.Format = model.ExportFormatCSV
+	}
+	if task.Source == "" {
+		task.Source = model.ExportSourceLocal
 	}
 
 	if err := s.repo.CreateExportTask(ctx, task); err != nil {
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
-	// TODO: 启动异步导出任务
-
-	return &dto.ExportTaskResp{
-		ID:           task.ID,
-		AdvertiserID: task.AdvertiserID,
-		TaskType:     task.TaskType,
-		Status:       task.Status,
-		StartDate:    task.StartDate,
-		EndDate:      task.EndDate,
-		CreatedAt:    task.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	return s.toExportTaskResp(task), nil
 }
 
 // GetExportTask 获取导出任务详情
 func (s *ReportService) GetExportTask(ctx context.Context, id uint64) (*dto.ExportTaskResp, error) {
 	task, err := s.repo.GetExportTaskByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrNotFound)
+		}
 		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
 	}
 
-	if task == nil {
-		return nil, errcode.New(errcode.ErrNotFound)
+	return s.toExportTaskResp(task), nil
+}
+
+// OpenExportFile 校验签名下载链接并打开导出文件，调用方负责关闭
+// 签名链接本身即授权凭证，不校验登录态与数据权限
+func (s *ReportService) OpenExportFile(ctx context.Context, id uint64, req *dto.ExportDownloadReq) (*model.ExportTask, io.ReadCloser, error) {
+	if s.files == nil {
+		return nil, nil, errcode.NewWithMessage(errcode.ErrServiceUnavail, "导出文件存储未配置")
 	}
 
-	return &dto.ExportTaskResp{
-		ID:           task.ID,
-		AdvertiserID: task.AdvertiserID,
-		TaskType:     task.TaskType,
-		Status:       task.Status,
-		FileName:     task.FileName,
-		FileSize:     task.FileSize,
-		StartDate:    task.StartDate,
-		EndDate:      task.EndDate,
-		ErrorMsg:     task.ErrorMsg,
-		CreatedAt:    task.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	task, err := s.repo.GetExportTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errcode.New(errcode.ErrNotFound)
+		}
+		return nil, nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if task.Status != model.ExportStatusCompleted || task.FilePath == "" {
+		return nil, nil, errcode.NewWithMessage(errcode.ErrReportExportFail, "导出文件尚未生成")
+	}
+
+	if err := s.files.Verify(task, req.Expires, req.Signature); err != nil {
+		if errors.Is(err, storage.ErrSignatureExpired) {
+			return nil, nil, errcode.NewWithMessage(errcode.ErrPermissionDeny, "下载链接已过期")
+		}
+		return nil, nil, errcode.NewWithMessage(errcode.ErrPermissionDeny, "下载链接无效")
+	}
+
+	rc, err := s.files.Storage.Open(ctx, task.FilePath)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, errcode.NewWithMessage(errcode.ErrNotFound, "导出文件不存在")
+		}
+		return nil, nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return task, rc, nil
+}
+
+// toExportTaskResp 转换导出任务响应，已完成的任务附带签名下载链接
+func (s *ReportService) toExportTaskResp(t *model.ExportTask) *dto.ExportTaskResp {
+	resp := &dto.ExportTaskResp{
+		ID:           t.ID,
+		AdvertiserID: t.AdvertiserID,
+		TaskType:     t.TaskType,
+		Format:       t.Format,
+		Source:       t.Source,
+		Status:       t.Status,
+		FileName:     t.FileName,
+		FileSize:     t.FileSize,
+		StartDate:    t.StartDate,
+		EndDate:      t.EndDate,
+		ErrorMsg:     t.ErrorMsg,
+		CreatedAt:    t.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if t.FinishedAt != nil {
+		resp.FinishedAt = t.FinishedAt.Format("2006-01-02 15:04:05")
+	}
+	if s.files != nil && t.Status == model.ExportStatusCompleted && t.FilePath != "" {
+		url, expireAt := s.files.DownloadURL(t)
+		resp.DownloadURL = url
+		resp.URLExpireAt = expireAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
 }
 
 // GetCreativeReport 获取创意报告
 func (s *ReportService) GetCreativeReport(ctx context.Context, req *dto.ReportQueryReq) ([]*dto.CreativeReportResp, error) {
-	// 这里简化处理，实际应从创意报表中查询
-	// TODO: 创建创意报表并实现查询逻辑
-	return []*dto.CreativeReportResp{}, nil
+	if err := datascope.Check(ctx, req.AdvertiserID); err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.GetCreativeReport(ctx, req)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	result := make([]*dto.CreativeReportResp, len(list))
+	for i, r := range list {
+		result[i] = &dto.CreativeReportResp{
+			CreativeID:  r.CreativeID,
+			StatDate:    r.StatDate,
+			Cost:        r.Cost,
+			Show:        r.Show,
+			Click:       r.Click,
+			Convert:     r.Convert,
+			CTR:         r.CTR,
+			CVR:         r.CVR,
+			CPM:         r.CPM,
+			CPC:         r.CPC,
+			ConvertCost: r.ConvertCost,
+		}
+	}
+
+	return result, nil
 }
 
 // GetRealtimeReport 获取实时报告
@@ -289,6 +351,9 @@ func (s *ReportService) GetRealtimeReport(ctx context.Context, req *dto.Realtime
 	// 获取广告主信息
 	adv, err := s.advRepo.GetByID(ctx, req.AdvertiserID)
 	if err != nil {
+		if errors.Is(err, datascope.ErrOutOfScope) {
+			return nil, err
+		}
 		return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 	}
 
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"oceanengine-backend/internal/app/report/model"
+	"oceanengine-backend/internal/app/report/repository"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+const (
+	// syncPageSize 分页拉取报表的每页数量
+	syncPageSize = 500
+	// syncMaxPages 单次请求的最大页数，防止分页信息异常时死循环
+	syncMaxPages = 1000
+	// syncWindowDays 单次请求的日期跨度，长区间按窗口拆分
+	syncWindowDays = 30
+
+	// 体验版报表分组与时间粒度
+	v3GroupByID            = "STAT_GROUP_BY_FIELD_ID"
+	v3GroupByStatTime      = "STAT_GROUP_BY_FIELD_STAT_TIME"
+	v3TimeGranularityDaily = "STAT_TIME_GRANULARITY_DAILY"
+	// MaxSyncRangeDays 接口同步允许的最大日期跨度，更长区间使用定时任务服务的回补命令
+	MaxSyncRangeDays = 93
+)
+
+// SyncTarget 报表同步的广告主
+type SyncTarget struct {
+	ID           uint64 // ad_advertiser.id，即报表表中的 advertiser_id
+	AdvertiserID uint64 // 巨量广告主ID
+	AccessToken  string
+}
+
+// ReportSyncer 报表同步器
+// 按维度分页拉取巨量报表，并按 (维度ID, stat_date) 幂等写入本地报表表，重复同步同一日期只会覆盖指标
+type ReportSyncer struct {
+	repo   repository.ReportRepository
+	client *oceanengine.Client
+}
+
+// NewReportSyncer 创建报表同步器
+func NewReportSyncer(db *gorm.DB, client *oceanengine.Client) *ReportSyncer {
+	return &ReportSyncer{
+		repo:   repository.NewReportRepository(db),
+		client: client,
+	}
+}
+
+// ParseReportLevels 解析同步维度，多个维度以逗号分隔，为空时返回全部维度
+func ParseReportLevels(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return model.ReportLevels, nil
+	}
+
+	var levels []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		level := strings.ToUpper(strings.TrimSpace(part))
+		if level == "" || seen[level] {
+			continue
+		}
+		if !isReportLevel(level) {
+			return nil, fmt.Errorf("unknown report level %q", part)
+		}
+		seen[level] = true
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func isReportLevel(level string) bool {
+	for _, l := range model.ReportLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync 同步日期范围内的报表，返回写入的行数
+// 单个维度失败不影响其他维度，所有失败合并返回
+func (s *ReportSyncer) Sync(ctx context.Context, target SyncTarget, startDate, endDate string, levels []string) (int, error) {
+	windows, err := splitDateRange(startDate, endDate, syncWindowDays)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var errs []error
+	for _, level := range levels {
+		for _, w := range windows {
+			n, err := s.syncLevel(ctx, target, level, w[0], w[1])
+			total += n
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s~%s: %w", level, w[0], w[1], err))
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+func (s *ReportSyncer) syncLevel(ctx context.Context, target SyncTarget, level, startDate, endDate string) (int, error) {
+	switch level {
+	case model.ReportLevelAdvertiser:
+		return s.syncAdvertiser(ctx, target, startDate, endDate)
+	case model.ReportLevelCampaign:
+		return s.syncCampaign(ctx, target, startDate, endDate)
+	case model.ReportLevelAd:
+		return s.syncAd(ctx, target, startDate, endDate)
+	case model.ReportLevelCreative:
+		return s.syncCreative(ctx, target, startDate, endDate)
+	case model.ReportLevelProject, model.ReportLevelPromotion, model.ReportLevelMaterial:
+		return s.syncV3(ctx, target, level, startDate, endDate)
+	default:
+		return 0, fmt.Errorf("unknown report level %q", level)
+	}
+}
+
+// reportRequest 构造按天汇总的报表请求
+func reportRequest(target SyncTarget, startDate, endDate string, page int) *oceanengine.ReportRequest {
+	return &oceanengine.ReportRequest{
+		AdvertiserID: int64(target.AdvertiserID),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		TimeGranular: "STAT_TIME_GRANULARITY_DAILY",
+		Page:         page,
+		PageSize:     syncPageSize,
+	}
+}
+
+// paginate 逐页拉取，fetch 返回总页数与本页行数
+func paginate(ctx context.Context, fetch func(page int) (totalPage, n int, err error)) error {
+	for page := 1; page <= syncMaxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		totalPage, n, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		if n == 0 || page >= totalPage {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *ReportSyncer) syncAdvertiser(ctx context.Context, target SyncTarget, startDate, endDate string) (int, error) {
+	reports := oceanengine.NewReportService(s.client.WithAccessToken(target.AccessToken))
+	var rows []*model.AdvertiserReport
+	err := paginate(ctx, func(page int) (int, int, error) {
+		list, resp, err := reports.GetAdvertiserReport(ctx, reportRequest(target, startDate, endDate, page))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, r := range list {
+			m := toReportMetrics(r.ReportMetrics)
+			rows = append(rows, &model.AdvertiserReport{
+				AdvertiserID: target.ID, StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return resp.PageInfo.TotalPage, len(list), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), s.repo.UpsertReports(ctx, rows, "advertiser_id", "stat_date")
+}
+
+func (s *ReportSyncer) syncCampaign(ctx context.Context, target SyncTarget, startDate, endDate string) (int, error) {
+	reports := oceanengine.NewReportService(s.client.WithAccessToken(target.AccessToken))
+	var rows []*model.CampaignReport
+	err := paginate(ctx, func(page int) (int, int, error) {
+		list, resp, err := reports.GetCampaignReport(ctx, reportRequest(target, startDate, endDate, page))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, r := range list {
+			m := toReportMetrics(r.ReportMetrics)
+			rows = append(rows, &model.CampaignReport{
+				AdvertiserID: target.ID, CampaignID: uint64(r.CampaignID), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return resp.PageInfo.TotalPage, len(list), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), s.repo.UpsertReports(ctx, rows, "campaign_id", "stat_date")
+}
+
+func (s *ReportSyncer) syncAd(ctx context.Context, target SyncTarget, startDate, endDate string) (int, error) {
+	reports := oceanengine.NewReportService(s.client.WithAccessToken(target.AccessToken))
+	var rows []*model.AdReport
+	err := paginate(ctx, func(page int) (int, int, error) {
+		list, resp, err := reports.GetAdReport(ctx, reportRequest(target, startDate, endDate, page))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, r := range list {
+			m := toReportMetrics(r.ReportMetrics)
+			rows = append(rows, &model.AdReport{
+				AdvertiserID: target.ID, CampaignID: uint64(r.CampaignID), AdID: uint64(r.AdID), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return resp.PageInfo.TotalPage, len(list), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), s.repo.UpsertReports(ctx, rows, "ad_id", "stat_date")
+}
+
+func (s *ReportSyncer) syncCreative(ctx context.Context, target SyncTarget, startDate, endDate string) (int, error) {
+	reports := oceanengine.NewReportService(s.client.WithAccessToken(target.AccessToken))
+	var rows []*model.CreativeReport
+	err := paginate(ctx, func(page int) (int, int, error) {
+		list, resp, err := reports.GetCreativeReport(ctx, reportRequest(target, startDate, endDate, page))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, r := range list {
+			m := toReportMetrics(r.ReportMetrics)
+			rows = append(rows, &model.CreativeReport{
+				AdvertiserID: target.ID, CampaignID: uint64(r.CampaignID), AdID: uint64(r.AdID), CreativeID: uint64(r.CreativeID), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return resp.PageInfo.TotalPage, len(list), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), s.repo.UpsertReports(ctx, rows, "creative_id", "stat_date")
+}
+
+// syncV3 同步体验版项目、广告、素材报表
+func (s *ReportSyncer) syncV3(ctx context.Context, target SyncTarget, level, startDate, endDate string) (int, error) {
+	v3 := s.client.V3()
+	fetch := v3.GetProjectReport
+	switch level {
+	case model.ReportLevelPromotion:
+		fetch = v3.GetPromotionReport
+	case model.ReportLevelMaterial:
+		fetch = v3.GetMaterialReport
+	}
+
+	var list []v3Row
+	err := paginate(ctx, func(page int) (int, int, error) {
+		// 按 ID 和天分组，每行对应一个对象一天的数据
+		data, err := fetch(ctx, target.AccessToken, &oceanengine.V3ReportRequest{
+			AdvertiserID:    target.AdvertiserID,
+			StartDate:       startDate,
+			EndDate:         endDate,
+			GroupBy:         []string{v3GroupByID, v3GroupByStatTime},
+			TimeGranularity: v3TimeGranularityDaily,
+			Page:            page,
+			PageSize:        syncPageSize,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, r := range data.List {
+			row := v3Row(r)
+			// 缺少日期的行无法按 stat_date 落库，跳过以免覆盖其他日期的数据
+			if row.statDate() == "" {
+				continue
+			}
+			list = append(list, row)
+		}
+		return data.PageInfo.TotalPage, len(data.List), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	switch level {
+	case model.ReportLevelProject:
+		rows := make([]*model.ProjectReport, 0, len(list))
+		for _, r := range list {
+			m := r.metrics()
+			rows = append(rows, &model.ProjectReport{
+				AdvertiserID: target.ID, ProjectID: r.uint("project_id", "cdp_project_id"), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return len(rows), s.repo.UpsertReports(ctx, rows, "project_id", "stat_date")
+	case model.ReportLevelPromotion:
+		rows := make([]*model.PromotionReport, 0, len(list))
+		for _, r := range list {
+			m := r.metrics()
+			rows = append(rows, &model.PromotionReport{
+				AdvertiserID: target.ID, ProjectID: r.uint("project_id", "cdp_project_id"),
+				PromotionID: r.uint("promotion_id", "cdp_promotion_id"), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return len(rows), s.repo.UpsertReports(ctx, rows, "promotion_id", "stat_date")
+	default:
+		rows := make([]*model.MaterialReport, 0, len(list))
+		for _, r := range list {
+			m := r.metrics()
+			rows = append(rows, &model.MaterialReport{
+				AdvertiserID: target.ID, MaterialID: r.uint("material_id"), StatDate: m.StatDate,
+				Cost: m.Cost, Show: m.Show, Click: m.Click, Convert: m.Convert,
+				CTR: m.CTR, CVR: m.CVR, CPM: m.CPM, CPC: m.CPC, ConvertCost: m.ConvertCost,
+			})
+		}
+		return len(rows), s.repo.UpsertReports(ctx, rows, "advertiser_id", "material_id", "stat_date")
+	}
+}
+
+// reportMetrics 统一后的报表指标
+type reportMetrics struct {
+	StatDate    string
+	Cost        float64
+	Show        int64
+	Click       int64
+	Convert     int64
+	CTR         float64
+	CVR         float64
+	CPM         float64
+	CPC         float64
+	ConvertCost float64
+}
+
+func toReportMetrics(r oceanengine.ReportMetrics) reportMetrics {
+	return reportMetrics{
+		StatDate:    statDate(r.StatDatetime),
+		Cost:        r.Cost,
+		Show:        r.ShowCnt,
+		Click:       r.ClickCnt,
+		Convert:     r.ConvertCnt,
+		CTR:         r.CTR,
+		CVR:         r.ConvertRate,
+		CPM:         r.CPM,
+		CPC:         r.CPC,
+		ConvertCost: r.ConvertCost,
+	}
+}
+
+// statDate 截取日期部分，巨量返回的 stat_datetime 形如 2024-01-01 00:00:00
+func statDate(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+// v3Row 体验版报表行，字段名随接口版本存在差异，按候选字段依次读取
+type v3Row map[string]interface{}
+
+func (r v3Row) metrics() reportMetrics {
+	return reportMetrics{
+		StatDate:    r.statDate(),
+		Cost:        r.float("stat_cost", "cost"),
+		Show:        int64(r.float("show_cnt", "show")),
+		Click:       int64(r.float("click_cnt", "click")),
+		Convert:     int64(r.float("convert_cnt", "convert")),
+		CTR:         r.float("ctr"),
+		CVR:         r.float("conversion_rate", "convert_rate"),
+		CPM:         r.float("cpm_platform", "cpm"),
+		CPC:         r.float("cpc_platform", "cpc"),
+		ConvertCost: r.float("conversion_cost", "convert_cost"),
+	}
+}
+
+// statDate 返回行的统计日期，未按天分组时为空
+func (r v3Row) statDate() string {
+	return statDate(r.string("stat_time_day", "stat_datetime", "stat_date"))
+}
+
+func (r v3Row) lookup(keys ...string) (interface{}, bool) {
+	for _, key := range keys {
+		if v, ok := r[key]; ok && v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (r v3Row) string(keys ...string) string {
+	v, ok := r.lookup(keys...)
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func (r v3Row) float(keys ...string) float64 {
+	v, ok := r.lookup(keys...)
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
+
+// uint 读取 ID，兼容数值与字符串形式
+func (r v3Row) uint(keys ...string) uint64 {
+	v, ok := r.lookup(keys...)
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case json.Number:
+		id, _ := strconv.ParseUint(n.String(), 10, 64)
+		return id
+	case float64:
+		return uint64(n)
+	case string:
+		id, _ := strconv.ParseUint(n, 10, 64)
+		return id
+	}
+	return 0
+}
+
+// splitDateRange 按窗口拆分日期范围，返回 [开始, 结束] 列表
+func splitDateRange(startDate, endDate string, days int) ([][2]string, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date %q", startDate)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date %q", endDate)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end date %s before start date %s", endDate, startDate)
+	}
+
+	var windows [][2]string
+	for from := start; !from.After(end); from = from.AddDate(0, 0, days) {
+		to := from.AddDate(0, 0, days-1)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, [2]string{from.Format("2006-01-02"), to.Format("2006-01-02")})
+	}
+	return windows, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"oceanengine-backend/internal/app/report/model"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+func TestReportSyncer_Sync(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "sync-token", r.Header.Get("Access-Token"))
+
+		page := 1
+		if r.Method == http.MethodPost {
+			var req map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			page = int(req["page"].(float64))
+		} else if r.URL.Query().Get("page") == "2" {
+			page = 2
+		}
+
+		var list []map[string]interface{}
+		switch r.URL.Path {
+		case "/open_api/2/report/campaign/get/":
+			list = []map[string]interface{}{
+				{"campaign_id": 100 + page, "stat_datetime": "2024-01-01 00:00:00", "cost": 1.5 * float64(page), "show": 10},
+			}
+		case "/open_api/2/report/creative/get/":
+			list = []map[string]interface{}{
+				{"creative_id": 300, "ad_id": 200, "campaign_id": 100, "stat_datetime": "2024-01-01 00:00:00", "click": 3},
+			}
+		case "/open_api/v3.0/report/project/get/":
+			row := map[string]interface{}{"project_id": "7300000000000000001", "stat_cost": "2.5", "show_cnt": 20}
+			// 未按天分组时接口返回整个区间的汇总，不含日期
+			query := r.URL.Query()
+			if strings.Contains(query.Get("group_by"), "STAT_GROUP_BY_FIELD_STAT_TIME") && query.Get("time_granularity") == "STAT_TIME_GRANULARITY_DAILY" {
+				row["stat_time_day"] = "2024-01-01"
+			}
+			list = []map[string]interface{}{row, {"project_id": "7300000000000000002", "stat_cost": "9.9"}}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		data := map[string]interface{}{
+			"list":      list,
+			"page_info": map[string]interface{}{"page": page, "total_page": 1},
+		}
+		if r.URL.Path == "/open_api/2/report/campaign/get/" {
+			data["page_info"] = map[string]interface{}{"page": page, "total_page": 2}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": data})
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.CampaignReport{}, &model.CreativeReport{}, &model.ProjectReport{}))
+
+	client := oceanengine.NewClient("app", "secret", oceanengine.WithBaseURL(server.URL+"/open_api"), oceanengine.WithRetryCount(0))
+	syncer := NewReportSyncer(db, client)
+	target := SyncTarget{ID: 1, AdvertiserID: 1001, AccessToken: "sync-token"}
+	levels := []string{model.ReportLevelCampaign, model.ReportLevelCreative, model.ReportLevelProject}
+
+	n, err := syncer.Sync(context.Background(), target, "2024-01-01", "2024-01-01", levels)
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	// 重复同步只覆盖指标，不产生重复行
+	_, err = syncer.Sync(context.Background(), target, "2024-01-01", "2024-01-01", levels)
+	require.NoError(t, err)
+
+	var campaigns []model.CampaignReport
+	require.NoError(t, db.Order("campaign_id").Find(&campaigns).Error)
+	require.Len(t, campaigns, 2)
+	assert.Equal(t, uint64(101), campaigns[0].CampaignID)
+	assert.Equal(t, "2024-01-01", campaigns[0].StatDate)
+	assert.Equal(t, 3.0, campaigns[1].Cost)
+
+	var creative model.CreativeReport
+	require.NoError(t, db.First(&creative).Error)
+	assert.Equal(t, uint64(300), creative.CreativeID)
+	assert.Equal(t, uint64(1), creative.AdvertiserID)
+	assert.Equal(t, int64(3), creative.Click)
+
+	var projects []model.ProjectReport
+	require.NoError(t, db.Find(&projects).Error)
+	require.Len(t, projects, 1)
+	assert.Equal(t, uint64(7300000000000000001), projects[0].ProjectID)
+	assert.Equal(t, 2.5, projects[0].Cost)
+	assert.Equal(t, int64(20), projects[0].Show)
+}
+
+func TestParseReportLevels(t *testing.T) {
+	levels, err := ParseReportLevels("")
+	require.NoError(t, err)
+	assert.Equal(t, model.ReportLevels, levels)
+
+	levels, err = ParseReportLevels(" campaign, AD,campaign ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.ReportLevelCampaign, model.ReportLevelAd}, levels)
+
+	_, err = ParseReportLevels("CAMPAIGN,UNKNOWN")
+	assert.Error(t, err)
+}
+
+func TestSplitDateRange(t *testing.T) {
+	windows, err := splitDateRange("2024-01-01", "2024-03-01", 30)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"2024-01-01", "2024-01-30"},
+		{"2024-01-31", "2024-02-29"},
+		{"2024-03-01", "2024-03-01"},
+	}, windows)
+
+	_, err = splitDateRange("2024-01-02", "2024-01-01", 30)
+	assert.Error(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
 	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
@@ -24,7 +26,7 @@ func NewServeMarketHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *ServeMark
 	return &ServeMarketHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
@@ -42,6 +44,9 @@ func (h *ServeMarketHandler) getAdvertiser(c *gin.Context) (*advModel.Advertiser
 	if err := h.db.Where("advertiser_id = ?", advID).First(&adv).Error; err != nil {
 		return nil, errcode.New(errcode.ErrAdvertiserNotFound)
 	}
+	if err := datascope.Check(c.Request.Context(), adv.ID); err != nil {
+		return nil, err
+	}
 	if adv.AccessToken == "" {
 		return nil, errcode.New(errcode.ErrOETokenInvalid)
 	}
@@ -235,6 +240,10 @@ func (h *ServeMarketHandler) CreateSubscription(c *gin.Context) {
 		response.Error(c, errcode.New(errcode.ErrAdvertiserNotFound))
 		return
 	}
+	if err := datascope.Check(c.Request.Context(), adv.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	subscriptionID, err := h.client.ServeMarket().CreateRdsSubscription(
 		c.Request.Context(),
@@ -276,6 +285,10 @@ func (h *ServeMarketHandler) UpdateSubscription(c *gin.Context) {
 		response.Error(c, errcode.New(errcode.ErrAdvertiserNotFound))
 		return
 	}
+	if err := datascope.Check(c.Request.Context(), adv.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	err := h.client.ServeMarket().UpdateRdsSubscription(
 		c.Request.Context(),
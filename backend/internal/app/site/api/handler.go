@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,20 +24,13 @@ func NewSiteHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *SiteHandler {
 	return &SiteHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 获取access token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *SiteHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 获取广告主ID
@@ -64,7 +59,7 @@ func (h *SiteHandler) GetOrangeSiteList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetOrangeSiteList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -83,7 +78,7 @@ func (h *SiteHandler) GetOrangeSiteDetail(c *gin.Context) {
 
 	detail, err := h.client.Site().GetOrangeSiteDetail(c.Request.Context(), accessToken, advertiserID, siteID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -106,7 +101,7 @@ func (h *SiteHandler) CreateOrangeSite(c *gin.Context) {
 
 	siteID, err := h.client.Site().CreateOrangeSite(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"site_id": siteID})
@@ -134,7 +129,7 @@ func (h *SiteHandler) UpdateOrangeSite(c *gin.Context) {
 
 	err := h.client.Site().UpdateOrangeSite(c.Request.Context(), accessToken, req.AdvertiserID, siteID, req.SiteName, req.Components)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -153,7 +148,7 @@ func (h *SiteHandler) PublishOrangeSite(c *gin.Context) {
 
 	siteURL, err := h.client.Site().PublishOrangeSite(c.Request.Context(), accessToken, advertiserID, siteID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"site_url": siteURL})
@@ -172,7 +167,7 @@ func (h *SiteHandler) DeleteOrangeSite(c *gin.Context) {
 
 	err := h.client.Site().DeleteOrangeSite(c.Request.Context(), accessToken, advertiserID, siteID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -199,7 +194,7 @@ func (h *SiteHandler) CopyOrangeSite(c *gin.Context) {
 
 	newSiteID, err := h.client.Site().CopyOrangeSite(c.Request.Context(), accessToken, req.AdvertiserID, siteID, req.SiteName)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"site_id": newSiteID})
@@ -221,7 +216,7 @@ func (h *SiteHandler) GetThirdPartySiteList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetThirdPartySiteList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -248,7 +243,7 @@ func (h *SiteHandler) CreateThirdPartySite(c *gin.Context) {
 
 	siteID, err := h.client.Site().CreateThirdPartySite(c.Request.Context(), accessToken, req.AdvertiserID, req.SiteName, req.SiteURL)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"site_id": siteID})
@@ -276,7 +271,7 @@ func (h *SiteHandler) UpdateThirdPartySite(c *gin.Context) {
 
 	err := h.client.Site().UpdateThirdPartySite(c.Request.Context(), accessToken, req.AdvertiserID, siteID, req.SiteName, req.SiteURL)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -295,7 +290,7 @@ func (h *SiteHandler) DeleteThirdPartySite(c *gin.Context) {
 
 	err := h.client.Site().DeleteThirdPartySite(c.Request.Context(), accessToken, advertiserID, siteID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -318,7 +313,7 @@ func (h *SiteHandler) GetSiteTemplateList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetSiteTemplateList(c.Request.Context(), accessToken, advertiserID, templateType, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -345,7 +340,7 @@ func (h *SiteHandler) CreateSiteFromTemplate(c *gin.Context) {
 
 	siteID, err := h.client.Site().CreateSiteFromTemplate(c.Request.Context(), accessToken, req.AdvertiserID, req.TemplateID, req.SiteName)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"site_id": siteID})
@@ -366,7 +361,7 @@ func (h *SiteHandler) GetSiteComponentList(c *gin.Context) {
 
 	list, err := h.client.Site().GetSiteComponentList(c.Request.Context(), accessToken, advertiserID, componentType)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, list)
@@ -389,7 +384,7 @@ func (h *SiteHandler) GetSiteAnalysis(c *gin.Context) {
 
 	analysis, err := h.client.Site().GetSiteAnalysis(c.Request.Context(), accessToken, advertiserID, siteID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, analysis)
@@ -410,7 +405,7 @@ func (h *SiteHandler) GetSiteHeatmap(c *gin.Context) {
 
 	heatmap, err := h.client.Site().GetSiteHeatmap(c.Request.Context(), accessToken, advertiserID, siteID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, heatmap)
@@ -432,7 +427,7 @@ func (h *SiteHandler) GetMiniPageList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetMiniPageList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -460,7 +455,7 @@ func (h *SiteHandler) CreateMiniPage(c *gin.Context) {
 
 	pageID, err := h.client.Site().CreateMiniPage(c.Request.Context(), accessToken, req.AdvertiserID, req.PageName, req.PagePath, req.AppID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"page_id": pageID})
@@ -479,7 +474,7 @@ func (h *SiteHandler) DeleteMiniPage(c *gin.Context) {
 
 	err := h.client.Site().DeleteMiniPage(c.Request.Context(), accessToken, advertiserID, pageID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -502,7 +497,7 @@ func (h *SiteHandler) GetSiteFormList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetSiteFormList(c.Request.Context(), accessToken, advertiserID, siteID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -525,7 +520,7 @@ func (h *SiteHandler) GetFormSubmissionList(c *gin.Context) {
 
 	list, total, err := h.client.Site().GetFormSubmissionList(c.Request.Context(), accessToken, advertiserID, formID, startDate, endDate, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -553,7 +548,7 @@ func (h *SiteHandler) ExportFormSubmissions(c *gin.Context) {
 
 	downloadURL, err := h.client.Site().ExportFormSubmissions(c.Request.Context(), accessToken, req.AdvertiserID, req.FormID, req.StartDate, req.EndDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, gin.H{"download_url": downloadURL})
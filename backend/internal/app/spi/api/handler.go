@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	sdkspi "github.com/bububa/oceanengine/marketing-api/model/spi"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"oceanengine-backend/internal/app/spi/service"
+)
+
+// maxBodySize 推送消息体上限
+const maxBodySize = 1 << 20
+
+// SPIHandler 订阅推送回调处理器
+// 应答使用开放平台约定的 BaseResp 结构：200 成功，4xx 不重试，5xx 重试
+type SPIHandler struct {
+	receiver   *service.Receiver
+	dispatcher *service.Dispatcher
+	log        *zap.Logger
+}
+
+// NewSPIHandler 创建订阅推送回调处理器
+func NewSPIHandler(receiver *service.Receiver, dispatcher *service.Dispatcher, log *zap.Logger) *SPIHandler {
+	return &SPIHandler{receiver: receiver, dispatcher: dispatcher, log: log}
+}
+
+// Callback 接收订阅推送
+// @Summary 订阅推送回调
+// @Description 应答回调地址校验 (verify_webhook)，校验签名后保存推送消息并异步分发
+// @Tags 订阅推送
+// @Accept json
+// @Produce json
+// @Param X-Open-Signature header string false "HMAC-SHA256 签名"
+// @Success 200 {object} sdkspi.BaseResponse
+// @Router /api/v1/spi/callback [post]
+func (h *SPIHandler) Callback(c *gin.Context) {
+	if c.Request.Method == http.MethodGet {
+		var req sdkspi.ChallengeRequest
+		if err := c.ShouldBindQuery(&req); err != nil || req.Event != service.VerifyEvent {
+			h.reply(c, http.StatusBadRequest, "invalid challenge")
+			return
+		}
+		h.challenge(c, req.Challenge)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodySize+1))
+	if err != nil {
+		h.reply(c, http.StatusBadRequest, "read body failed")
+		return
+	}
+	// 截断后的消息体签名必然不匹配，超出上限时明确拒绝
+	if len(body) > maxBodySize {
+		h.reply(c, http.StatusRequestEntityTooLarge, "body too large")
+		return
+	}
+
+	var req sdkspi.ChallengeRequest
+	if json.Unmarshal(body, &req) == nil && req.Event == service.VerifyEvent {
+		h.challenge(c, req.Challenge)
+		return
+	}
+
+	msg, duplicate, err := h.receiver.Receive(c.Request.Context(), body, c.GetHeader(service.SignatureHeader))
+	switch {
+	case errors.Is(err, service.ErrInvalidSignature):
+		h.reply(c, http.StatusUnauthorized, "invalid signature")
+		return
+	case errors.Is(err, service.ErrInvalidMessage), errors.Is(err, service.ErrReplayed):
+		h.reply(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		h.log.Error(fmt.Sprintf("[SPI] 保存推送消息失败: %v", err))
+		h.reply(c, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if !duplicate {
+		// 处理可能较慢，先应答再分发；失败的消息由定时任务重试
+		go func() {
+			if err := h.dispatcher.Dispatch(context.Background(), msg); err != nil {
+				h.log.Warn(fmt.Sprintf("[SPI] 分发消息 %s 失败: %v", msg.MessageID, err))
+			}
+		}()
+	}
+	h.reply(c, http.StatusOK, "success")
+}
+
+// challenge 应答回调地址校验
+func (h *SPIHandler) challenge(c *gin.Context, challenge int64) {
+	c.JSON(http.StatusOK, sdkspi.ChallengeResponse{
+		BaseResp:  sdkspi.BaseResponse{StatusCode: http.StatusOK, StatusMessage: "success"},
+		Challenge: challenge,
+	})
+}
+
+// reply 返回推送应答
+func (h *SPIHandler) reply(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"BaseResp": sdkspi.BaseResponse{StatusCode: status, StatusMessage: message},
+	})
+}
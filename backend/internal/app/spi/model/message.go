@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Message 订阅推送消息表
+// 以 message_id 去重，同一 nonce + timestamp 只接收一次；处理失败的消息由定时任务重试
+type Message struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	MessageID       string     `gorm:"size:64;uniqueIndex;not null" json:"message_id"`
+	ServiceLabel    string     `gorm:"size:128;index" json:"service_label"`
+	AdvertiserIDs   string     `gorm:"size:2048" json:"advertiser_ids"` // 巨量广告主ID，逗号分隔
+	SubscribeTaskID uint64     `gorm:"default:0" json:"subscribe_task_id"`
+	Data            string     `gorm:"type:text" json:"data"`
+	Nonce           int64      `gorm:"uniqueIndex:uk_spi_nonce" json:"nonce"`
+	Timestamp       int64      `gorm:"uniqueIndex:uk_spi_nonce" json:"timestamp"` // 推送时间，毫秒
+	PublishTime     int64      `gorm:"default:0" json:"publish_time"`
+	Status          string     `gorm:"size:16;index;default:'PENDING'" json:"status"`
+	Attempts        int        `gorm:"default:0" json:"attempts"`
+	ErrorMsg        string     `gorm:"size:500" json:"error_msg"`
+	ProcessedAt     *time.Time `json:"processed_at"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (Message) TableName() string {
+	return "spi_message"
+}
+
+// 消息处理状态
+const (
+	MessageStatusPending   = "PENDING"
+	MessageStatusProcessed = "PROCESSED"
+	MessageStatusFailed    = "FAILED"
+	MessageStatusIgnored   = "IGNORED" // 没有对应的处理器
+)
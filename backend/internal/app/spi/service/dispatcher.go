@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	sdkspi "github.com/bububa/oceanengine/marketing-api/model/spi"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/spi/model"
+	"oceanengine-backend/internal/outbox"
+)
+
+// Event 分发给处理器的推送事件
+type Event struct {
+	Message       *model.Message
+	AdvertiserIDs []uint64            // 巨量广告主ID
+	Data          *sdkspi.MessageData // data 无法解析时为空结构
+}
+
+// Handler 推送事件处理器
+type Handler func(ctx context.Context, event *Event) error
+
+type route struct {
+	pattern string
+	handler Handler
+}
+
+// Dispatcher 推送消息分发器
+// 按 service_label 匹配已注册的处理器，处理器需保证幂等：同一消息在失败重试时会被再次处理
+type Dispatcher struct {
+	db     *gorm.DB
+	cfg    *config.SPIConfig
+	log    *zap.Logger
+	mu     sync.RWMutex
+	routes []route
+}
+
+// NewDispatcher 创建推送消息分发器
+func NewDispatcher(db *gorm.DB, cfg *config.SPIConfig, log *zap.Logger) *Dispatcher {
+	return &Dispatcher{db: db, cfg: cfg, log: log}
+}
+
+// Register 注册处理器
+// pattern 为 path.Match 语法，如 report.*.activeprogram；一条消息可匹配多个处理器
+func (d *Dispatcher) Register(pattern string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.routes = append(d.routes, route{pattern: pattern, handler: handler})
+}
+
+// handlersFor 返回匹配 service_label 的处理器
+func (d *Dispatcher) handlersFor(label string) []Handler {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var handlers []Handler
+	for _, r := range d.routes {
+		if ok, _ := path.Match(r.pattern, label); ok {
+			handlers = append(handlers, r.handler)
+		}
+	}
+	return handlers
+}
+
+// Dispatch 处理单条消息并更新处理状态
+// 通过 attempts 条件更新领取消息，消息正被其他实例处理时直接返回
+func (d *Dispatcher) Dispatch(ctx context.Context, msg *model.Message) error {
+	handlers := d.handlersFor(msg.ServiceLabel)
+	if len(handlers) == 0 {
+		return d.db.WithContext(ctx).Model(msg).Updates(map[string]interface{}{
+			"status":       model.MessageStatusIgnored,
+			"processed_at": time.Now(),
+		}).Error
+	}
+
+	claimed, err := outbox.Claim(d.db.WithContext(ctx).Model(&model.Message{}).
+		Where("status IN ?", []string{model.MessageStatusPending, model.MessageStatusFailed}),
+		msg.ID, msg.Attempts, nil)
+	if err != nil || !claimed {
+		return err
+	}
+	msg.Attempts++
+
+	event := &Event{Message: msg, AdvertiserIDs: splitIDs(msg.AdvertiserIDs), Data: &sdkspi.MessageData{}}
+	if msg.Data != "" {
+		if err := json.Unmarshal([]byte(msg.Data), event.Data); err != nil {
+			d.log.Warn(fmt.Sprintf("[SPI] 消息 %s 数据解析失败: %v", msg.MessageID, err))
+		}
+	}
+	if len(event.AdvertiserIDs) == 0 && event.Data.AdvertiserID > 0 {
+		event.AdvertiserIDs = []uint64{event.Data.AdvertiserID}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.DispatchTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := d.runHandler(ctx, h, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	handleErr := errors.Join(errs...)
+
+	updates := map[string]interface{}{
+		"status":       model.MessageStatusProcessed,
+		"error_msg":    "",
+		"processed_at": time.Now(),
+	}
+	if handleErr != nil {
+		updates = map[string]interface{}{
+			"status":    model.MessageStatusFailed,
+			"error_msg": outbox.ErrorMessage(handleErr.Error()),
+		}
+		d.log.Warn(fmt.Sprintf("[SPI] 消息 %s (%s) 第 %d 次处理失败: %v", msg.MessageID, msg.ServiceLabel, msg.Attempts, handleErr))
+	}
+	if err := outbox.Record(d.db, &model.Message{}, msg.ID, updates); err != nil {
+		return err
+	}
+	return handleErr
+}
+
+// runHandler 执行处理器并捕获 panic
+func (d *Dispatcher) runHandler(ctx context.Context, h Handler, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, event)
+}
+
+// Retry 重新处理未完成的消息，返回成功与失败数
+// 仅处理超过处理超时仍未完成的消息，避免与正在处理的实例冲突
+func (d *Dispatcher) Retry(ctx context.Context, limit int) (success, failed int, err error) {
+	query := d.db.Model(&model.Message{}).Where("status IN ?", []string{model.MessageStatusPending, model.MessageStatusFailed})
+	return outbox.Retry(ctx, query, d.cfg.MaxAttempts, d.cfg.DispatchTimeout, limit, d.Dispatch)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	reportModel "oceanengine-backend/internal/app/report/model"
+	reportService "oceanengine-backend/internal/app/report/service"
+)
+
+// 订阅服务类型
+const (
+	LabelReportActive    = "report.*.activeprogram"      // 聚合周期内指标变更
+	LabelReportBeforeDay = "report.advertiser.beforeday" // 前一日数据产出
+	LabelProjectStatus   = "project.*"                   // 项目状态变更
+	LabelPromotionStatus = "promotion.*"                 // 广告状态变更
+	LabelSiteEvent       = "site.*"                      // 橙子建站站点事件
+)
+
+// reportLevelOf 根据 report.<level>.activeprogram 取报表维度
+func reportLevelOf(label string) string {
+	parts := strings.Split(label, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	level := strings.ToUpper(parts[1])
+	for _, l := range reportModel.ReportLevels {
+		if l == level {
+			return level
+		}
+	}
+	return ""
+}
+
+// DefaultHandlers 内置推送处理器：收到指标或状态变更后重新同步对应维度的本地报表
+type DefaultHandlers struct {
+	db     *gorm.DB
+	syncer *reportService.ReportSyncer
+	tokens reportService.AccessTokenProvider
+	log    *zap.Logger
+}
+
+// RegisterDefaultHandlers 注册内置推送处理器
+func RegisterDefaultHandlers(d *Dispatcher, db *gorm.DB, syncer *reportService.ReportSyncer, tokens reportService.AccessTokenProvider, log *zap.Logger) {
+	h := &DefaultHandlers{db: db, syncer: syncer, tokens: tokens, log: log}
+	d.Register(LabelReportActive, h.reportActive)
+	d.Register(LabelReportBeforeDay, h.reportBeforeDay)
+	d.Register(LabelProjectStatus, h.resyncToday(reportModel.ReportLevelProject))
+	d.Register(LabelPromotionStatus, h.resyncToday(reportModel.ReportLevelPromotion))
+	d.Register(LabelSiteEvent, h.siteEvent)
+}
+
+// reportActive 指标变更，重新同步当日对应维度报表
+func (h *DefaultHandlers) reportActive(ctx context.Context, event *Event) error {
+	level := reportLevelOf(event.Message.ServiceLabel)
+	if level == "" {
+		return nil
+	}
+	today := time.Now().Format("2006-01-02")
+	return h.resync(ctx, event.AdvertiserIDs, today, []string{level})
+}
+
+// reportBeforeDay 前一日数据产出，同步前一日全部维度报表
+func (h *DefaultHandlers) reportBeforeDay(ctx context.Context, event *Event) error {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	return h.resync(ctx, event.AdvertiserIDs, yesterday, reportModel.ReportLevels)
+}
+
+// resyncToday 状态变更，重新同步当日指定维度报表
+func (h *DefaultHandlers) resyncToday(level string) Handler {
+	return func(ctx context.Context, event *Event) error {
+		today := time.Now().Format("2006-01-02")
+		return h.resync(ctx, event.AdvertiserIDs, today, []string{level})
+	}
+}
+
+// siteEvent 站点事件，本地不保存站点数据，仅记录日志
+func (h *DefaultHandlers) siteEvent(ctx context.Context, event *Event) error {
+	if c := event.Data.Content; c != nil {
+		h.log.Info(fmt.Sprintf("[SPI] 站点 %d 事件 %d (广告主 %d)", c.SiteID, c.EventType, c.AdvID))
+	}
+	return nil
+}
+
+// resync 同步指定广告主的报表，未授权到本系统的广告主忽略
+func (h *DefaultHandlers) resync(ctx context.Context, advertiserIDs []uint64, date string, levels []string) error {
+	if len(advertiserIDs) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		ID           uint64
+		AdvertiserID uint64
+	}
+	if err := h.db.WithContext(ctx).Table("ad_advertiser").
+		Select("id, advertiser_id").
+		Where("advertiser_id IN ? AND deleted_at IS NULL", advertiserIDs).
+		Find(&rows).Error; err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, row := range rows {
+		token, err := h.tokens.GetAccessToken(ctx, row.AdvertiserID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("advertiser %d: %w", row.AdvertiserID, err))
+			continue
+		}
+		target := reportService.SyncTarget{ID: row.ID, AdvertiserID: row.AdvertiserID, AccessToken: token}
+		if _, err := h.syncer.Sync(ctx, target, date, date, levels); err != nil {
+			errs = append(errs, fmt.Errorf("advertiser %d: %w", row.AdvertiserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkspi "github.com/bububa/oceanengine/marketing-api/model/spi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/spi/model"
+)
+
+const (
+	// SignatureHeader 推送签名请求头，值为 HMAC-SHA256(secret, body) 的十六进制
+	SignatureHeader = "X-Open-Signature"
+	// VerifyEvent 回调地址校验事件
+	VerifyEvent = "verify_webhook"
+)
+
+var (
+	// ErrInvalidSignature 签名校验失败
+	ErrInvalidSignature = errors.New("spi: invalid signature")
+	// ErrInvalidMessage 消息格式错误
+	ErrInvalidMessage = errors.New("spi: invalid message")
+	// ErrReplayed 推送时间超出允许范围
+	ErrReplayed = errors.New("spi: timestamp outside replay window")
+)
+
+// Receiver 推送消息接收器
+// 校验签名与推送时间后落库，重复推送（相同 message_id 或 nonce + timestamp）不会重复入库
+type Receiver struct {
+	db     *gorm.DB
+	cfg    *config.SPIConfig
+	secret []byte
+	now    func() time.Time
+}
+
+// NewReceiver 创建推送消息接收器
+func NewReceiver(db *gorm.DB, cfg *config.SPIConfig) *Receiver {
+	return &Receiver{
+		db:     db,
+		cfg:    cfg,
+		secret: []byte(cfg.Secret),
+		now:    time.Now,
+	}
+}
+
+// Receive 接收推送消息
+// 返回落库的消息；duplicate 为 true 时表示消息已接收过，调用方直接应答成功即可
+func (r *Receiver) Receive(ctx context.Context, body []byte, signature string) (msg *model.Message, duplicate bool, err error) {
+	if len(r.secret) == 0 || !sdkspi.VerifyTokenHeader(r.secret, body, signature) {
+		return nil, false, ErrInvalidSignature
+	}
+
+	var m sdkspi.Message
+	if err := json.Unmarshal(body, &m); err != nil || m.MessageID == "" {
+		return nil, false, ErrInvalidMessage
+	}
+
+	if window := r.cfg.ReplayWindow; window > 0 {
+		sent := time.UnixMilli(m.Timestamp)
+		if d := r.now().Sub(sent); d > window || d < -window {
+			return nil, false, ErrReplayed
+		}
+	}
+
+	msg = &model.Message{
+		MessageID:       m.MessageID,
+		ServiceLabel:    m.ServiceLabel,
+		AdvertiserIDs:   joinIDs(m.AdvertiserIDs),
+		SubscribeTaskID: m.SubscribeTaskID,
+		Data:            m.Data,
+		Nonce:           m.Nonce,
+		Timestamp:       m.Timestamp,
+		PublishTime:     m.PublishTime,
+		Status:          model.MessageStatusPending,
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(msg)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	return msg, result.RowsAffected == 0, nil
+}
+
+// joinIDs 广告主ID列表转为逗号分隔字符串
+func joinIDs(ids []uint64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitIDs 解析逗号分隔的广告主ID
+func splitIDs(value string) []uint64 {
+	var ids []uint64
+	for _, part := range strings.Split(value, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	sdkspi "github.com/bububa/oceanengine/marketing-api/model/spi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/spi/model"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Message{}))
+	return db
+}
+
+func testConfig() *config.SPIConfig {
+	return &config.SPIConfig{Secret: "secret", ReplayWindow: time.Minute, DispatchTimeout: time.Second, MaxAttempts: 2}
+}
+
+func signedBody(t *testing.T, msg sdkspi.Message) ([]byte, string) {
+	body, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return body, sdkspi.Sign([]byte("secret"), body)
+}
+
+func TestReceiver_Receive(t *testing.T) {
+	db := newTestDB(t)
+	r := NewReceiver(db, testConfig())
+	now := time.Now()
+
+	msg := sdkspi.Message{
+		MessageID:     "m1",
+		ServiceLabel:  "report.ad.activeprogram",
+		AdvertiserIDs: []uint64{1001, 1002},
+		Data:          `{"ad_ids":[1]}`,
+		Timestamp:     now.UnixMilli(),
+		Nonce:         42,
+	}
+	body, sig := signedBody(t, msg)
+
+	_, _, err := r.Receive(context.Background(), body, "bad")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	saved, duplicate, err := r.Receive(context.Background(), body, sig)
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, "1001,1002", saved.AdvertiserIDs)
+	assert.Equal(t, model.MessageStatusPending, saved.Status)
+
+	// 相同消息重复推送
+	_, duplicate, err = r.Receive(context.Background(), body, sig)
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+
+	// 不同 message_id 但 nonce + timestamp 相同视为重放
+	msg.MessageID = "m2"
+	body, sig = signedBody(t, msg)
+	_, duplicate, err = r.Receive(context.Background(), body, sig)
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+
+	var count int64
+	db.Model(&model.Message{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// 超出时间窗口
+	msg.MessageID = "m3"
+	msg.Timestamp = now.Add(-2 * time.Minute).UnixMilli()
+	body, sig = signedBody(t, msg)
+	_, _, err = r.Receive(context.Background(), body, sig)
+	assert.ErrorIs(t, err, ErrReplayed)
+
+	body, sig = []byte(`{"service_label":"x"}`), sdkspi.Sign([]byte("secret"), []byte(`{"service_label":"x"}`))
+	_, _, err = r.Receive(context.Background(), body, sig)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	db := newTestDB(t)
+	d := NewDispatcher(db, testConfig(), zap.NewNop())
+
+	var got []*Event
+	d.Register(LabelReportActive, func(ctx context.Context, event *Event) error {
+		got = append(got, event)
+		return nil
+	})
+	failing := true
+	d.Register("project.*", func(ctx context.Context, event *Event) error {
+		if failing {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	report := &model.Message{MessageID: "a", ServiceLabel: "report.creative.activeprogram", Data: `{"advertiser_id":7,"creative_ids":[9]}`, Nonce: 1}
+	project := &model.Message{MessageID: "b", ServiceLabel: "project.status.change", AdvertiserIDs: "8", Nonce: 2}
+	unknown := &model.Message{MessageID: "c", ServiceLabel: "unknown", Nonce: 3}
+	for _, m := range []*model.Message{report, project, unknown} {
+		m.Status = model.MessageStatusPending
+		require.NoError(t, db.Create(m).Error)
+	}
+
+	require.NoError(t, d.Dispatch(context.Background(), report))
+	require.Len(t, got, 1)
+	assert.Equal(t, []uint64{7}, got[0].AdvertiserIDs)
+	assert.Equal(t, []uint64{9}, got[0].Data.CreativeIDs)
+	assert.Equal(t, "CREATIVE", reportLevelOf(report.ServiceLabel))
+
+	assert.Error(t, d.Dispatch(context.Background(), project))
+	require.NoError(t, d.Dispatch(context.Background(), unknown))
+
+	statusOf := func(m *model.Message) *model.Message {
+		var saved model.Message
+		require.NoError(t, db.First(&saved, m.ID).Error)
+		return &saved
+	}
+	assert.Equal(t, model.MessageStatusProcessed, statusOf(report).Status)
+	assert.Equal(t, model.MessageStatusIgnored, statusOf(unknown).Status)
+	failed := statusOf(project)
+	assert.Equal(t, model.MessageStatusFailed, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "boom", failed.ErrorMsg)
+
+	// 已被领取的旧副本不会重复处理
+	require.NoError(t, d.Dispatch(context.Background(), report))
+	assert.Len(t, got, 1)
+
+	// 超过处理超时的失败消息由 Retry 重新处理
+	require.NoError(t, db.Model(&model.Message{}).Where("id = ?", project.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Minute)).Error)
+	failing = false
+	success, failedCount, err := d.Retry(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Equal(t, 0, failedCount)
+	assert.Equal(t, model.MessageStatusProcessed, statusOf(project).Status)
+	assert.Equal(t, 2, statusOf(project).Attempts)
+}
@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
+	"oceanengine-backend/pkg/errcode"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,20 +28,13 @@ func NewStarHandler(db *gorm.DB, oceanCfg *config.OceanConfig) *StarHandler {
 	return &StarHandler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 获取access token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *StarHandler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 获取广告主ID
@@ -48,6 +47,29 @@ func (h *StarHandler) getAdvertiserID(c *gin.Context) uint64 {
 	return id
 }
 
+// checkScope 校验巨量广告主ID均在当前用户的数据权限内，未授权接入的广告主同样视为无权访问
+func (h *StarHandler) checkScope(ctx context.Context, advertiserIDs []uint64) error {
+	f, ok := datascope.FromContext(ctx)
+	if !ok || f.All {
+		return nil
+	}
+	ids := make(map[uint64]struct{}, len(advertiserIDs))
+	for _, id := range advertiserIDs {
+		ids[id] = struct{}{}
+	}
+	var count int64
+	if err := h.db.WithContext(ctx).Model(&advModel.Advertiser{}).
+		Scopes(datascope.Scope(ctx, "id")).
+		Where("advertiser_id IN ?", advertiserIDs).
+		Count(&count).Error; err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if int(count) != len(ids) {
+		return datascope.ErrOutOfScope
+	}
+	return nil
+}
+
 // GetAccountInfo 获取星图账户信息
 func (h *StarHandler) GetAccountInfo(c *gin.Context) {
 	accessToken := h.getAccessToken(c)
@@ -59,7 +81,7 @@ func (h *StarHandler) GetAccountInfo(c *gin.Context) {
 
 	info, err := h.client.Star().GetAccountInfo(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, info)
@@ -88,10 +110,18 @@ func (h *StarHandler) GetBatchBalance(c *gin.Context) {
 		response.BadRequest(c, err.Error())
 		return
 	}
+	if len(req.AdvertiserIDs) == 0 {
+		response.BadRequest(c, "缺少必要参数")
+		return
+	}
+	if err := h.checkScope(c.Request.Context(), req.AdvertiserIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	list, err := h.client.Star().GetFundBalance(c.Request.Context(), accessToken, req.AdvertiserIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, list)
@@ -110,7 +140,7 @@ func (h *StarHandler) GetFundDaily(c *gin.Context) {
 
 	list, err := h.client.Star().GetFundDaily(c.Request.Context(), accessToken, advertiserID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, list)
@@ -132,7 +162,7 @@ func (h *StarHandler) GetFundTransactions(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetFundTransaction(c.Request.Context(), accessToken, advertiserID, startDate, endDate, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -152,7 +182,7 @@ func (h *StarHandler) GetTaskList(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetTaskList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -170,7 +200,7 @@ func (h *StarHandler) GetTaskDetail(c *gin.Context) {
 
 	detail, err := h.client.Star().GetTaskDetail(c.Request.Context(), accessToken, advertiserID, taskID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -191,7 +221,7 @@ func (h *StarHandler) GetTaskItems(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetTaskItemList(c.Request.Context(), accessToken, advertiserID, taskID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -221,7 +251,7 @@ func (h *StarHandler) UpdateTaskStatus(c *gin.Context) {
 
 	err := h.client.Star().UpdateTaskStatus(c.Request.Context(), accessToken, advertiserID, taskID, req.Status)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -241,7 +271,7 @@ func (h *StarHandler) GetDemandList(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetDemandList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -259,7 +289,7 @@ func (h *StarHandler) GetDemandDetail(c *gin.Context) {
 
 	detail, err := h.client.Star().GetDemandDetail(c.Request.Context(), accessToken, advertiserID, demandID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, detail)
@@ -281,7 +311,7 @@ func (h *StarHandler) GetDemandOrders(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetDemandOrders(c.Request.Context(), accessToken, advertiserID, demandID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -300,7 +330,7 @@ func (h *StarHandler) GetReportOverview(c *gin.Context) {
 
 	report, err := h.client.Star().GetReportOverview(c.Request.Context(), accessToken, advertiserID, taskID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, report)
@@ -319,7 +349,7 @@ func (h *StarHandler) GetReportAudience(c *gin.Context) {
 
 	audience, err := h.client.Star().GetReportAudience(c.Request.Context(), accessToken, advertiserID, taskID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, audience)
@@ -340,7 +370,7 @@ func (h *StarHandler) GetReportDaily(c *gin.Context) {
 
 	list, err := h.client.Star().GetReportDaily(c.Request.Context(), accessToken, advertiserID, taskID, startDate, endDate)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithData(c, list)
@@ -362,7 +392,7 @@ func (h *StarHandler) GetClueList(c *gin.Context) {
 
 	list, total, err := h.client.Star().GetClueList(c.Request.Context(), accessToken, advertiserID, taskID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OKWithList(c, list, int64(total), page, pageSize)
@@ -392,7 +422,7 @@ func (h *StarHandler) UpdateClueStatus(c *gin.Context) {
 
 	err := h.client.Star().UpdateClueStatus(c.Request.Context(), accessToken, clueID, req.Status, req.Remark)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 	response.OK(c)
@@ -429,7 +459,7 @@ func (h *StarHandler) ExportClues(c *gin.Context) {
 	for {
 		list, total, err := h.client.Star().GetClueList(c.Request.Context(), accessToken, advertiserID, req.TaskID, page, pageSize)
 		if err != nil {
-			response.InternalError(c, err.Error())
+			response.UpstreamError(c, err)
 			return
 		}
 
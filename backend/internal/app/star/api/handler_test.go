@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/utils"
+)
+
+// TestGetBatchBalance_Scope 批量查询余额的每个广告主都需在数据权限内
+func TestGetBatchBalance_Scope(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "OK", "data": map[string]interface{}{"list": []interface{}{}}})
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}))
+	advs := []*advModel.Advertiser{{AdvertiserID: 1001, Name: "a"}, {AdvertiserID: 1002, Name: "b"}}
+	require.NoError(t, db.Create(advs).Error)
+
+	gin.SetMode(gin.TestMode)
+	h := NewStarHandler(db, &config.OceanConfig{AppID: "1", Secret: "secret", BaseURL: server.URL + "/open_api"})
+	router := gin.New()
+	router.POST("/star/balance", func(c *gin.Context) {
+		filter := datascope.NewFilter([]uint64{advs[0].ID})
+		c.Request = c.Request.WithContext(datascope.WithFilter(c.Request.Context(), filter))
+		utils.SetAccessToken(c, "token")
+		h.GetBatchBalance(c)
+	})
+
+	post := func(body string) int {
+		req, _ := http.NewRequest("POST", "/star/balance?advertiser_id=1001", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, post(`{"advertiser_ids":[1001,1002]}`))
+	assert.Equal(t, http.StatusForbidden, post(`{"advertiser_ids":[1001,9999]}`))
+	assert.Zero(t, calls)
+
+	assert.Equal(t, http.StatusOK, post(`{"advertiser_ids":[1001,1001]}`))
+	assert.Equal(t, 1, calls)
+}
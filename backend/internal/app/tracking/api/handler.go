@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"oceanengine-backend/internal/app/tracking/dto"
+	"oceanengine-backend/internal/app/tracking/model"
+	"oceanengine-backend/internal/app/tracking/service"
+	"oceanengine-backend/pkg/response"
+)
+
+// AppKeyHeader 转化上报接口的应用密钥请求头
+const AppKeyHeader = "X-App-Key"
+
+// TrackingHandler 监测链接与转化上报处理器
+type TrackingHandler struct {
+	clicks      *service.ClickRecorder
+	conversions *service.ConversionService
+	postback    *service.Postback
+	apiKeys     map[string]string
+	log         *zap.Logger
+}
+
+// NewTrackingHandler 创建监测链接与转化上报处理器，apiKeys 为应用名到密钥的映射
+func NewTrackingHandler(clicks *service.ClickRecorder, conversions *service.ConversionService, postback *service.Postback, apiKeys map[string]string, log *zap.Logger) *TrackingHandler {
+	return &TrackingHandler{clicks: clicks, conversions: conversions, postback: postback, apiKeys: apiKeys, log: log}
+}
+
+// Click 接收监测链接点击
+// @Summary 监测链接点击
+// @Description 巨量在广告被点击时请求该地址，宏参数替换为实际值；点击异步批量写入
+// @Tags 转化追踪
+// @Produce json
+// @Param callback query string false "__CALLBACK_PARAM__"
+// @Param advertiser_id query string false "__ADVERTISER_ID__"
+// @Param promotion_id query string false "__PROMOTION_ID__"
+// @Param project_id query string false "__PROJECT_ID__"
+// @Param aid query string false "__AID__"
+// @Param campaign_id query string false "__CAMPAIGN_ID__"
+// @Param cid query string false "__CID__"
+// @Param oaid query string false "__OAID__"
+// @Param ip query string false "__IP__"
+// @Param ua query string false "__UA__"
+// @Param ts query string false "__TS__"
+// @Success 200 {object} response.Response
+// @Router /api/v1/track/click [get]
+func (h *TrackingHandler) Click(c *gin.Context) {
+	var req dto.ClickReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	click := service.NewClick(&req, time.Now())
+	if err := h.clicks.Record(c.Request.Context(), click); err != nil {
+		h.log.Error(fmt.Sprintf("[Tracking] 保存点击失败: %v", err))
+		response.InternalError(c, "save click failed")
+		return
+	}
+	response.OK(c)
+}
+
+// Conversion 应用上报转化
+// @Summary 上报转化
+// @Description 应用服务端按自己的用户ID/订单号上报转化，归因到点击后异步回传巨量；同一事件重复上报返回已有记录
+// @Tags 转化追踪
+// @Accept json
+// @Produce json
+// @Param X-App-Key header string true "应用密钥"
+// @Param body body dto.ConversionReq true "转化信息"
+// @Success 200 {object} response.Response{data=dto.ConversionResp}
+// @Router /api/v1/track/conversions [post]
+func (h *TrackingHandler) Conversion(c *gin.Context) {
+	app := h.authenticate(c.GetHeader(AppKeyHeader))
+	if app == "" {
+		response.Unauthorized(c, "invalid app key")
+		return
+	}
+
+	var req dto.ConversionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	conv, duplicate, err := h.conversions.Report(c.Request.Context(), app, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if !duplicate && conv.Status == model.ConversionStatusPending {
+		// 回传可能较慢，先应答再回传；失败的转化由定时任务重试
+		go func() {
+			if err := h.postback.Dispatch(context.Background(), conv); err != nil {
+				h.log.Warn(fmt.Sprintf("[Tracking] 回传转化 %d 失败: %v", conv.ID, err))
+			}
+		}()
+	}
+
+	response.Success(c, &dto.ConversionResp{
+		ID:           conv.ID,
+		Status:       conv.Status,
+		MatchType:    conv.MatchType,
+		ClickID:      conv.ClickID,
+		AdvertiserID: conv.AdvertiserID,
+		PromotionID:  conv.PromotionID,
+		Duplicate:    duplicate,
+	})
+}
+
+// authenticate 按应用密钥返回应用名，密钥无效时返回空
+func (h *TrackingHandler) authenticate(key string) string {
+	if key == "" {
+		return ""
+	}
+	for app, secret := range h.apiKeys {
+		if secret != "" && subtle.ConstantTimeCompare([]byte(key), []byte(secret)) == 1 {
+			return app
+		}
+	}
+	return ""
+}
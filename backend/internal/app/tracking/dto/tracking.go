@@ -0,0 +1,50 @@
+package dto
+
+// ClickReq 监测链接点击参数，参数名与 track.DEFAULT_CLICK_FIELDS 一致，未被替换的宏视为空
+type ClickReq struct {
+	Callback     string `form:"callback"`
+	AdvertiserID string `form:"advertiser_id"`
+	AID          string `form:"aid"`
+	CID          string `form:"cid"`
+	CampaignID   string `form:"campaign_id"`
+	PromotionID  string `form:"promotion_id"`
+	ProjectID    string `form:"project_id"`
+	RequestID    string `form:"request_id"`
+	OS           string `form:"os"`
+	Imei         string `form:"imei"`
+	Oaid         string `form:"oaid"`
+	Idfa         string `form:"idfa"`
+	AndroidID    string `form:"android_id"`
+	IP           string `form:"ip"`
+	UA           string `form:"ua"`
+	TS           string `form:"ts"`
+}
+
+// ConversionReq 应用上报转化
+// order_id 与 user_id 至少填写一个，用于去重与归因；callback 为落地页 clickid 或应用已知的 __CALLBACK_PARAM__
+type ConversionReq struct {
+	EventType   string  `json:"event_type" binding:"required,max=64"`
+	UserID      string  `json:"user_id" binding:"required_without=OrderID,max=128"`
+	OrderID     string  `json:"order_id" binding:"required_without=UserID,max=128"`
+	EventWeight float64 `json:"event_weight" binding:"gte=0"`
+	PayAmount   int64   `json:"pay_amount" binding:"gte=0"` // 支付金额，单位分
+	Timestamp   int64   `json:"timestamp"`                  // 事件发生时间，毫秒，为空时为接收时间
+	Callback    string  `json:"callback" binding:"max=1024"`
+	Imei        string  `json:"imei"` // MD5
+	Oaid        string  `json:"oaid"`
+	Idfa        string  `json:"idfa"`
+	AndroidID   string  `json:"android_id"` // MD5
+	IP          string  `json:"ip"`
+	UA          string  `json:"ua"`
+}
+
+// ConversionResp 转化上报结果
+type ConversionResp struct {
+	ID           uint64 `json:"id"`
+	Status       string `json:"status"`
+	MatchType    string `json:"match_type"`
+	ClickID      uint64 `json:"click_id"`
+	AdvertiserID uint64 `json:"advertiser_id"`
+	PromotionID  uint64 `json:"promotion_id"`
+	Duplicate    bool   `json:"duplicate"` // 同一事件已上报过，返回已有记录
+}
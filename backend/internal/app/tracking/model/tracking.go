@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+// Click 监测链接点击记录
+// 巨量在广告被点击时请求监测链接，宏参数替换为实际值；转化归因按设备号或 IP+UA 匹配归因窗口内最近的点击
+type Click struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Callback     string    `gorm:"size:1024" json:"callback"`                               // __CALLBACK_PARAM__
+	AdvertiserID uint64    `gorm:"default:0" json:"advertiser_id"`                          // __ADVERTISER_ID__
+	PromotionID  uint64    `gorm:"default:0" json:"promotion_id"`                           // __PROMOTION_ID__ (体验版) 或 __AID__
+	ProjectID    uint64    `gorm:"default:0" json:"project_id"`                             // __PROJECT_ID__ (体验版) 或 __CAMPAIGN_ID__
+	CreativeID   uint64    `gorm:"default:0" json:"creative_id"`                            // __CID__，体验版广告无创意ID
+	RequestID    string    `gorm:"size:64" json:"request_id"`                               // __REQUEST_ID__
+	OS           string    `gorm:"size:8" json:"os"`                                        // __OS__：0 Android，1 iOS
+	Imei         string    `gorm:"size:64;index:idx_track_click_imei" json:"imei"`          // __IMEI__，MD5
+	Oaid         string    `gorm:"size:128;index:idx_track_click_oaid" json:"oaid"`         // __OAID__
+	Idfa         string    `gorm:"size:64;index:idx_track_click_idfa" json:"idfa"`          // __IDFA__
+	AndroidID    string    `gorm:"size:64;index:idx_track_click_android" json:"android_id"` // __ANDROIDID__，MD5
+	IP           string    `gorm:"size:64;index:idx_track_click_ip" json:"ip"`              // __IP__
+	UA           string    `gorm:"size:512" json:"ua"`                                      // __UA__
+	ClickTime    time.Time `gorm:"index" json:"click_time"`                                 // __TS__，未替换时为接收时间
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (Click) TableName() string {
+	return "track_click"
+}
+
+// Conversion 转化回传记录（发件箱）
+// 同一应用的同一事件按 dedup_key (订单号，无订单号时为用户ID) 去重；回传失败的记录由定时任务重试。
+// 设备号与 IP+UA 用于归因，未归因的转化在归因窗口内由定时任务重新归因
+type Conversion struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	App          string     `gorm:"size:64;uniqueIndex:uk_track_conversion;not null" json:"app"`
+	EventType    string     `gorm:"size:64;uniqueIndex:uk_track_conversion;not null" json:"event_type"`
+	DedupKey     string     `gorm:"size:191;uniqueIndex:uk_track_conversion;not null" json:"dedup_key"`
+	UserID       string     `gorm:"size:128;index" json:"user_id"`
+	OrderID      string     `gorm:"size:128" json:"order_id"`
+	EventWeight  float64    `gorm:"default:0" json:"event_weight"`
+	PayAmount    int64      `gorm:"default:0" json:"pay_amount"` // 支付金额，单位分
+	EventTime    time.Time  `json:"event_time"`
+	Imei         string     `gorm:"size:64" json:"imei"`
+	Oaid         string     `gorm:"size:128" json:"oaid"`
+	Idfa         string     `gorm:"size:64" json:"idfa"`
+	AndroidID    string     `gorm:"size:64" json:"android_id"`
+	IP           string     `gorm:"size:64" json:"ip"`
+	UA           string     `gorm:"size:512" json:"ua"`
+	ClickID      uint64     `gorm:"default:0;index" json:"click_id"`
+	MatchType    string     `gorm:"size:16" json:"match_type"` // 归因方式，见 MatchBy* 常量
+	Callback     string     `gorm:"size:1024" json:"callback"`
+	AdvertiserID uint64     `gorm:"default:0;index" json:"advertiser_id"`
+	PromotionID  uint64     `gorm:"default:0" json:"promotion_id"`
+	ProjectID    uint64     `gorm:"default:0" json:"project_id"`
+	Status       string     `gorm:"size:16;index;default:'PENDING'" json:"status"`
+	Attempts     int        `gorm:"default:0" json:"attempts"`
+	ErrorMsg     string     `gorm:"size:500" json:"error_msg"`
+	SentAt       *time.Time `json:"sent_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (Conversion) TableName() string {
+	return "track_conversion"
+}
+
+// 转化回传状态
+const (
+	ConversionStatusPending      = "PENDING"
+	ConversionStatusSent         = "SENT"
+	ConversionStatusFailed       = "FAILED"
+	ConversionStatusUnattributed = "UNATTRIBUTED" // 归因窗口内没有匹配的点击，不回传
+)
+
+// 归因方式
+const (
+	MatchByCallback  = "callback" // 应用直接上报 callback (落地页 clickid)
+	MatchByUser      = "user"     // 同一用户此前已归因的点击
+	MatchByOaid      = "oaid"
+	MatchByIdfa      = "idfa"
+	MatchByImei      = "imei"
+	MatchByAndroidID = "android_id"
+	MatchByIPUA      = "ip_ua"
+)
+
+// UserAttribution 应用用户与点击的归因关系
+// 用户首次归因成功后记录，之后该用户的付费等事件直接回传到同一点击
+type UserAttribution struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	App       string    `gorm:"size:64;uniqueIndex:uk_track_user;not null" json:"app"`
+	UserID    string    `gorm:"size:128;uniqueIndex:uk_track_user;not null" json:"user_id"`
+	ClickID   uint64    `gorm:"not null" json:"click_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (UserAttribution) TableName() string {
+	return "track_user_attribution"
+}
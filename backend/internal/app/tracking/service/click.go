@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/tracking/dto"
+	"oceanengine-backend/internal/app/tracking/model"
+	"oceanengine-backend/pkg/utils"
+)
+
+// ClickRecorder 点击记录器
+// 点击先写入内存队列，由后台协程按 batch_size / flush_interval 批量写入；队列满或已关闭时同步写入
+type ClickRecorder struct {
+	db     *gorm.DB
+	cfg    *config.TrackingConfig
+	log    *zap.Logger
+	queue  chan *model.Click
+	done   chan struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewClickRecorder 创建点击记录器并启动批量写入协程，停止服务前需调用 Close
+func NewClickRecorder(db *gorm.DB, cfg *config.TrackingConfig, log *zap.Logger) *ClickRecorder {
+	r := &ClickRecorder{
+		db:    db,
+		cfg:   cfg,
+		log:   log,
+		queue: make(chan *model.Click, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record 保存点击
+func (r *ClickRecorder) Record(ctx context.Context, click *model.Click) error {
+	r.mu.RLock()
+	if !r.closed {
+		select {
+		case r.queue <- click:
+			r.mu.RUnlock()
+			return nil
+		default:
+		}
+	}
+	r.mu.RUnlock()
+	return r.db.WithContext(ctx).Create(click).Error
+}
+
+// Close 停止接收队列并写入剩余的点击
+func (r *ClickRecorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+	<-r.done
+}
+
+// run 批量写入队列中的点击
+func (r *ClickRecorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*model.Click, 0, r.cfg.BatchSize)
+	for {
+		select {
+		case click, ok := <-r.queue:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+			batch = append(batch, click)
+			if len(batch) >= r.cfg.BatchSize {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush 批量写入点击，失败时逐条写入，避免一条异常数据导致整批丢失
+func (r *ClickRecorder) flush(batch []*model.Click) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := r.db.CreateInBatches(batch, len(batch)).Error; err == nil {
+		return
+	}
+	for _, click := range batch {
+		click.ID = 0
+		if err := r.db.Create(click).Error; err != nil {
+			r.log.Error(fmt.Sprintf("[Tracking] 保存点击失败 (request_id=%s): %v", click.RequestID, err))
+		}
+	}
+}
+
+// NewClick 将监测链接参数转换为点击记录
+// 体验版广告使用 promotion_id/project_id，旧版广告使用 aid/campaign_id，cid 为旧版广告的创意ID；__TS__ 未替换或晚于接收时间时使用接收时间
+func NewClick(req *dto.ClickReq, now time.Time) *model.Click {
+	click := &model.Click{
+		Callback:     utils.TruncateRunes(param(req.Callback), 1024),
+		AdvertiserID: paramID(req.AdvertiserID),
+		PromotionID:  paramID(req.PromotionID),
+		ProjectID:    paramID(req.ProjectID),
+		CreativeID:   paramID(req.CID),
+		RequestID:    utils.TruncateRunes(param(req.RequestID), 64),
+		OS:           utils.TruncateRunes(param(req.OS), 8),
+		Imei:         utils.TruncateRunes(device(req.Imei), 64),
+		Oaid:         utils.TruncateRunes(device(req.Oaid), 128),
+		Idfa:         utils.TruncateRunes(device(req.Idfa), 64),
+		AndroidID:    utils.TruncateRunes(device(req.AndroidID), 64),
+		IP:           utils.TruncateRunes(param(req.IP), 64),
+		UA:           utils.TruncateRunes(param(req.UA), 512),
+		ClickTime:    now,
+	}
+	if click.PromotionID == 0 {
+		click.PromotionID = paramID(req.AID)
+	}
+	if click.ProjectID == 0 {
+		click.ProjectID = paramID(req.CampaignID)
+	}
+	if ts, _ := strconv.ParseInt(param(req.TS), 10, 64); ts > 0 && ts <= now.UnixMilli() {
+		click.ClickTime = time.UnixMilli(ts)
+	}
+	return click
+}
+
+// param 去除首尾空白，未被替换的宏 (如 __IMEI__) 视为空
+func param(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) >= 4 && strings.HasPrefix(v, "__") && strings.HasSuffix(v, "__") {
+		return ""
+	}
+	return v
+}
+
+// paramID 解析ID参数，无法解析时为 0
+func paramID(v string) uint64 {
+	id, _ := strconv.ParseUint(param(v), 10, 64)
+	return id
+}
+
+// device 规范化设备号：统一为小写，全零的无效设备号 (如受限的 IDFA) 视为空
+func device(v string) string {
+	v = strings.ToLower(param(v))
+	if strings.Trim(v, "0-") == "" {
+		return ""
+	}
+	return v
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/tracking/dto"
+	"oceanengine-backend/internal/app/tracking/model"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/utils"
+)
+
+// ConversionService 转化上报服务
+// 保存应用上报的转化并归因到归因窗口内的点击，归因成功的转化进入发件箱等待回传。
+// 点击异步批量写入，转化可能早于对应点击入库，未归因的转化由 Reattribute 重新归因
+type ConversionService struct {
+	db  *gorm.DB
+	cfg *config.TrackingConfig
+}
+
+// NewConversionService 创建转化上报服务
+func NewConversionService(db *gorm.DB, cfg *config.TrackingConfig) *ConversionService {
+	return &ConversionService{db: db, cfg: cfg}
+}
+
+// Report 保存转化，返回转化记录及是否为重复上报
+// 同一应用的同一事件按订单号 (无订单号时按用户ID) 去重，重复上报返回已有记录
+func (s *ConversionService) Report(ctx context.Context, app string, req *dto.ConversionReq) (*model.Conversion, bool, error) {
+	now := time.Now()
+	conv := &model.Conversion{
+		App:         app,
+		EventType:   req.EventType,
+		DedupKey:    "user:" + req.UserID,
+		UserID:      req.UserID,
+		OrderID:     req.OrderID,
+		EventWeight: req.EventWeight,
+		PayAmount:   req.PayAmount,
+		EventTime:   now,
+		Callback:    utils.TruncateRunes(param(req.Callback), 1024),
+		Imei:        utils.TruncateRunes(device(req.Imei), 64),
+		Oaid:        utils.TruncateRunes(device(req.Oaid), 128),
+		Idfa:        utils.TruncateRunes(device(req.Idfa), 64),
+		AndroidID:   utils.TruncateRunes(device(req.AndroidID), 64),
+		IP:          utils.TruncateRunes(param(req.IP), 64),
+		UA:          utils.TruncateRunes(strings.TrimSpace(req.UA), 512),
+		Status:      model.ConversionStatusUnattributed,
+	}
+	if req.OrderID != "" {
+		conv.DedupKey = "order:" + req.OrderID
+	}
+	if req.Timestamp > 0 && req.Timestamp <= now.UnixMilli() {
+		conv.EventTime = time.UnixMilli(req.Timestamp)
+	}
+
+	if existing, err := s.find(ctx, conv); err != nil || existing != nil {
+		return existing, existing != nil, err
+	}
+
+	click, matchType, err := s.attribute(ctx, conv)
+	if err != nil {
+		return nil, false, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if click != nil {
+		conv.Status = model.ConversionStatusPending
+		conv.MatchType = matchType
+		conv.ClickID = click.ID
+		conv.Callback = click.Callback
+		conv.AdvertiserID = click.AdvertiserID
+		conv.PromotionID = click.PromotionID
+		conv.ProjectID = click.ProjectID
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(conv)
+	if result.Error != nil {
+		return nil, false, errcode.Wrap(errcode.ErrInternalServer, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// 并发上报同一事件
+		existing, err := s.find(ctx, conv)
+		return existing, true, err
+	}
+
+	if err := s.bind(ctx, conv); err != nil {
+		return nil, false, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return conv, false, nil
+}
+
+// Reattribute 重新归因归因窗口内未归因的转化，返回归因成功数
+// 按上次检查时间依次处理，归因成功的转化进入发件箱，由回传重试任务回传
+func (s *ConversionService) Reattribute(ctx context.Context, limit int) (int, error) {
+	var list []*model.Conversion
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND event_time >= ?", model.ConversionStatusUnattributed, time.Now().Add(-s.cfg.AttributionWindow)).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return 0, err
+	}
+
+	attributed := 0
+	for _, conv := range list {
+		if ctx.Err() != nil {
+			break
+		}
+		click, matchType, err := s.attribute(ctx, conv)
+		if err != nil {
+			return attributed, err
+		}
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if click != nil {
+			updates = map[string]interface{}{
+				"status":        model.ConversionStatusPending,
+				"match_type":    matchType,
+				"click_id":      click.ID,
+				"callback":      click.Callback,
+				"advertiser_id": click.AdvertiserID,
+				"promotion_id":  click.PromotionID,
+				"project_id":    click.ProjectID,
+				"updated_at":    time.Now(),
+			}
+		}
+		result := s.db.WithContext(ctx).Model(&model.Conversion{}).
+			Where("id = ? AND status = ?", conv.ID, model.ConversionStatusUnattributed).
+			Updates(updates)
+		if result.Error != nil {
+			return attributed, result.Error
+		}
+		if click == nil || result.RowsAffected == 0 {
+			continue
+		}
+		conv.MatchType, conv.ClickID = matchType, click.ID
+		if err := s.bind(ctx, conv); err != nil {
+			return attributed, err
+		}
+		attributed++
+	}
+	return attributed, nil
+}
+
+// bind 记录用户首次归因的点击，之后该用户的事件直接回传到同一点击
+func (s *ConversionService) bind(ctx context.Context, conv *model.Conversion) error {
+	if conv.ClickID == 0 || conv.UserID == "" || conv.MatchType == model.MatchByUser {
+		return nil
+	}
+	binding := &model.UserAttribution{App: conv.App, UserID: conv.UserID, ClickID: conv.ClickID}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(binding).Error
+}
+
+// find 按去重键查询已上报的转化
+func (s *ConversionService) find(ctx context.Context, conv *model.Conversion) (*model.Conversion, error) {
+	var existing model.Conversion
+	err := s.db.WithContext(ctx).
+		Where("app = ? AND event_type = ? AND dedup_key = ?", conv.App, conv.EventType, conv.DedupKey).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &existing, nil
+}
+
+// deviceMatch 按设备号归因的匹配顺序
+var deviceMatch = []struct {
+	matchType string
+	column    string
+	value     func(conv *model.Conversion) string
+}{
+	{model.MatchByOaid, "oaid", func(conv *model.Conversion) string { return conv.Oaid }},
+	{model.MatchByIdfa, "idfa", func(conv *model.Conversion) string { return conv.Idfa }},
+	{model.MatchByImei, "imei", func(conv *model.Conversion) string { return conv.Imei }},
+	{model.MatchByAndroidID, "android_id", func(conv *model.Conversion) string { return conv.AndroidID }},
+}
+
+// attribute 归因到点击，依次按 callback、用户已归因的点击、设备号、IP+UA 匹配
+// 应用上报 callback 时直接回传，不要求本地存在对应点击；其他方式只匹配 [转化时间 - 归因窗口, 转化时间] 内带 callback 的点击
+func (s *ConversionService) attribute(ctx context.Context, conv *model.Conversion) (*model.Click, string, error) {
+	if conv.Callback != "" {
+		return &model.Click{Callback: conv.Callback}, model.MatchByCallback, nil
+	}
+
+	eventTime := conv.EventTime
+	since := eventTime.Add(-s.cfg.AttributionWindow)
+	if conv.UserID != "" {
+		var binding model.UserAttribution
+		err := s.db.WithContext(ctx).Where("app = ? AND user_id = ?", conv.App, conv.UserID).First(&binding).Error
+		switch {
+		case err == nil:
+			click, err := s.latestClick(ctx, since, eventTime, "id = ?", binding.ClickID)
+			if err != nil || click != nil {
+				return click, model.MatchByUser, err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, "", err
+		}
+	}
+
+	for _, m := range deviceMatch {
+		value := m.value(conv)
+		if value == "" {
+			continue
+		}
+		click, err := s.latestClick(ctx, since, eventTime, m.column+" = ?", value)
+		if err != nil || click != nil {
+			return click, m.matchType, err
+		}
+	}
+
+	if conv.IP != "" && conv.UA != "" {
+		click, err := s.latestClick(ctx, since, eventTime, "ip = ? AND ua = ?", conv.IP, conv.UA)
+		if err != nil || click != nil {
+			return click, model.MatchByIPUA, err
+		}
+	}
+	return nil, "", nil
+}
+
+// latestClick 查询时间范围内最近一次带 callback 的点击，没有时返回 nil
+func (s *ConversionService) latestClick(ctx context.Context, since, until time.Time, query string, args ...interface{}) (*model.Click, error) {
+	var click model.Click
+	err := s.db.WithContext(ctx).
+		Where(query, args...).
+		Where("callback <> '' AND click_time BETWEEN ? AND ?", since, until).
+		Order("click_time DESC, id DESC").
+		First(&click).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &click, nil
+}
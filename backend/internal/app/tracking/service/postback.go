@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/conversion"
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	conversionModel "github.com/bububa/oceanengine/marketing-api/model/conversion"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/tracking/model"
+	"oceanengine-backend/internal/outbox"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+// Postback 转化回传发件箱
+// 通过转化回传 API 将归因成功的转化回传到巨量，失败的记录由定时任务重试
+type Postback struct {
+	db         *gorm.DB
+	cfg        *config.TrackingConfig
+	sdk        *oceansdk.Client
+	log        *zap.Logger
+	privateKey *rsa.PrivateKey
+}
+
+// NewPostback 创建转化回传发件箱，配置了 private_key_file 时使用 RS256 签名，否则使用应用 secret 签名
+func NewPostback(db *gorm.DB, cfg *config.TrackingConfig, sdk *oceansdk.Client, log *zap.Logger) (*Postback, error) {
+	p := &Postback{db: db, cfg: cfg, sdk: sdk, log: log}
+	if cfg.PrivateKeyFile != "" {
+		key, err := loadPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载转化回传私钥失败: %w", err)
+		}
+		p.privateKey = key
+	}
+	return p, nil
+}
+
+// loadPrivateKey 读取 PEM 格式的 RSA 私钥，支持 PKCS#1 与 PKCS#8
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("不是 PEM 格式")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("不是 RSA 私钥")
+	}
+	return key, nil
+}
+
+// Dispatch 回传单条转化并更新回传状态
+// 通过 attempts 条件更新领取记录，记录正被其他实例回传时直接返回
+func (p *Postback) Dispatch(ctx context.Context, conv *model.Conversion) error {
+	claimed, err := outbox.Claim(p.db.WithContext(ctx).Model(&model.Conversion{}).
+		Where("status IN ?", []string{model.ConversionStatusPending, model.ConversionStatusFailed}),
+		conv.ID, conv.Attempts, nil)
+	if err != nil || !claimed {
+		return err
+	}
+	conv.Attempts++
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.DispatchTimeout)
+	defer cancel()
+	_, sendErr := conversion.Conversion(ctx, p.sdk.SDK(), p.request(conv))
+	sendErr = oceansdk.MapError(sendErr)
+
+	updates := map[string]interface{}{
+		"status":    model.ConversionStatusSent,
+		"error_msg": "",
+		"sent_at":   time.Now(),
+	}
+	if sendErr != nil {
+		updates = map[string]interface{}{
+			"status":    model.ConversionStatusFailed,
+			"error_msg": outbox.ErrorMessage(sendErr.Error()),
+		}
+		p.log.Warn(fmt.Sprintf("[Tracking] 转化 %d (%s) 第 %d 次回传失败: %v", conv.ID, conv.EventType, conv.Attempts, sendErr))
+	}
+	if err := outbox.Record(p.db, &model.Conversion{}, conv.ID, updates); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// request 构造转化回传请求
+func (p *Postback) request(conv *model.Conversion) *conversionModel.Request {
+	req := &conversionModel.Request{
+		EventType:   conv.EventType,
+		EventWeight: conv.EventWeight,
+		Context: &conversionModel.Context{
+			Ad: &conversionModel.ContextAd{Callback: conv.Callback},
+		},
+		Timestamp: conv.EventTime.UnixMilli(),
+		Source:    p.cfg.Source,
+	}
+	if conv.PayAmount > 0 || conv.OrderID != "" {
+		req.Properties = &conversionModel.Properties{PayAmount: float64(conv.PayAmount), OrderID: conv.OrderID}
+	}
+	if p.privateKey != nil {
+		req.PrivateKey = p.privateKey
+		req.Credential = enum.Credential(p.cfg.Credential)
+	}
+	return req
+}
+
+// Retry 重新回传未完成的转化，返回成功与失败数
+// 仅处理超过回传超时仍未完成的记录，避免与正在回传的实例冲突
+func (p *Postback) Retry(ctx context.Context, limit int) (success, failed int, err error) {
+	query := p.db.Model(&model.Conversion{}).Where("status IN ?", []string{model.ConversionStatusPending, model.ConversionStatusFailed})
+	return outbox.Retry(ctx, query, p.cfg.MaxAttempts, p.cfg.DispatchTimeout, limit, p.Dispatch)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/tracking/dto"
+	"oceanengine-backend/internal/app/tracking/model"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Click{}, &model.Conversion{}, &model.UserAttribution{}))
+	return db
+}
+
+func testConfig() *config.TrackingConfig {
+	return &config.TrackingConfig{
+		AttributionWindow: 24 * time.Hour,
+		Source:            "test",
+		Credential:        "primary",
+		BatchSize:         2,
+		FlushInterval:     time.Hour,
+		QueueSize:         10,
+		DispatchTimeout:   time.Second,
+		MaxAttempts:       2,
+	}
+}
+
+func TestNewClick(t *testing.T) {
+	now := time.Now()
+	ts := now.Add(-time.Minute).UnixMilli()
+	click := NewClick(&dto.ClickReq{
+		Callback:     "cb1",
+		AdvertiserID: "1001",
+		AID:          "2001",
+		CID:          "4001",
+		CampaignID:   "3001",
+		ProjectID:    "__PROJECT_ID__",
+		Imei:         "__IMEI__",
+		Oaid:         " OAID-1 ",
+		Idfa:         "00000000-0000-0000-0000-000000000000",
+		IP:           "1.2.3.4",
+		UA:           "__UA__",
+		TS:           strconv.FormatInt(ts, 10),
+	}, now)
+	assert.Equal(t, "cb1", click.Callback)
+	assert.Equal(t, uint64(1001), click.AdvertiserID)
+	assert.Equal(t, uint64(2001), click.PromotionID)
+	assert.Equal(t, uint64(3001), click.ProjectID)
+	assert.Equal(t, uint64(4001), click.CreativeID)
+	assert.Empty(t, click.Imei)
+	assert.Equal(t, "oaid-1", click.Oaid)
+	assert.Empty(t, click.Idfa)
+	assert.Empty(t, click.UA)
+	assert.Equal(t, ts, click.ClickTime.UnixMilli())
+
+	click = NewClick(&dto.ClickReq{TS: "__TS__"}, now)
+	assert.Equal(t, now, click.ClickTime)
+
+	// 晚于接收时间的 __TS__ 不可信
+	click = NewClick(&dto.ClickReq{TS: "99999999999999"}, now)
+	assert.Equal(t, now, click.ClickTime)
+
+	// 超长字段按字符截断，不截断多字节字符
+	click = NewClick(&dto.ClickReq{UA: strings.Repeat("浏", 600)}, now)
+	assert.Equal(t, 512, utf8.RuneCountInString(click.UA))
+	assert.True(t, utf8.ValidString(click.UA))
+}
+
+func TestClickRecorder(t *testing.T) {
+	db := newTestDB(t)
+	r := NewClickRecorder(db, testConfig(), zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, r.Record(context.Background(), &model.Click{Callback: "cb", ClickTime: time.Now()}))
+	}
+	r.Close()
+	r.Close()
+
+	var count int64
+	db.Model(&model.Click{}).Count(&count)
+	assert.Equal(t, int64(3), count)
+
+	// 关闭后同步写入
+	require.NoError(t, r.Record(context.Background(), &model.Click{Callback: "cb", ClickTime: time.Now()}))
+	db.Model(&model.Click{}).Count(&count)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestConversionService_Report(t *testing.T) {
+	db := newTestDB(t)
+	s := NewConversionService(db, testConfig())
+	ctx := context.Background()
+	now := time.Now()
+
+	clicks := []*model.Click{
+		{Callback: "old", AdvertiserID: 1001, Oaid: "oaid-1", ClickTime: now.Add(-48 * time.Hour)},
+		{Callback: "cb-oaid", AdvertiserID: 1001, PromotionID: 2001, Oaid: "oaid-1", ClickTime: now.Add(-2 * time.Hour)},
+		{Callback: "", Oaid: "oaid-1", ClickTime: now.Add(-time.Hour)},
+		{Callback: "cb-ip", AdvertiserID: 1002, IP: "1.2.3.4", UA: "Mozilla/5.0", ClickTime: now.Add(-time.Hour)},
+		{Callback: "expired", AdvertiserID: 1001, Oaid: "oaid-2", ClickTime: now.Add(-30 * time.Hour)},
+	}
+	require.NoError(t, db.Create(clicks).Error)
+
+	// 按设备号匹配归因窗口内最近一次带 callback 的点击，并记录用户归因
+	conv, duplicate, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active", UserID: "u1", Oaid: "OAID-1"})
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, model.ConversionStatusPending, conv.Status)
+	assert.Equal(t, model.MatchByOaid, conv.MatchType)
+	assert.Equal(t, clicks[1].ID, conv.ClickID)
+	assert.Equal(t, "cb-oaid", conv.Callback)
+	assert.Equal(t, uint64(2001), conv.PromotionID)
+
+	// 同一用户的付费事件回传到同一点击
+	pay, _, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active_pay", UserID: "u1", OrderID: "o1", PayAmount: 600})
+	require.NoError(t, err)
+	assert.Equal(t, model.MatchByUser, pay.MatchType)
+	assert.Equal(t, clicks[1].ID, pay.ClickID)
+	assert.Equal(t, "order:o1", pay.DedupKey)
+
+	// 重复上报返回已有记录
+	again, duplicate, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active_pay", UserID: "u1", OrderID: "o1", PayAmount: 600})
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	assert.Equal(t, pay.ID, again.ID)
+
+	// 其他应用的同一订单不去重
+	_, duplicate, err = s.Report(ctx, "other", &dto.ConversionReq{EventType: "active_pay", OrderID: "o1"})
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	// IP + UA 匹配
+	conv, _, err = s.Report(ctx, "app", &dto.ConversionReq{EventType: "active", UserID: "u2", IP: "1.2.3.4", UA: "Mozilla/5.0"})
+	require.NoError(t, err)
+	assert.Equal(t, model.MatchByIPUA, conv.MatchType)
+	assert.Equal(t, "cb-ip", conv.Callback)
+
+	// 应用直接上报 callback
+	conv, _, err = s.Report(ctx, "app", &dto.ConversionReq{EventType: "form", UserID: "u3", Callback: "landing-clickid"})
+	require.NoError(t, err)
+	assert.Equal(t, model.MatchByCallback, conv.MatchType)
+	assert.Equal(t, "landing-clickid", conv.Callback)
+	assert.Zero(t, conv.ClickID)
+
+	// 点击超出归因窗口
+	conv, _, err = s.Report(ctx, "app", &dto.ConversionReq{
+		EventType: "active", UserID: "u4", Oaid: "oaid-2", Timestamp: now.Add(-time.Minute).UnixMilli(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.ConversionStatusUnattributed, conv.Status)
+	assert.Empty(t, conv.Callback)
+
+	var bindings int64
+	db.Model(&model.UserAttribution{}).Count(&bindings)
+	assert.Equal(t, int64(2), bindings)
+}
+
+func TestConversionService_Reattribute(t *testing.T) {
+	db := newTestDB(t)
+	s := NewConversionService(db, testConfig())
+	ctx := context.Background()
+	now := time.Now()
+
+	// 点击尚未入库时上报的转化未归因
+	active, _, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active", UserID: "u1", Oaid: "OAID-1"})
+	require.NoError(t, err)
+	assert.Equal(t, model.ConversionStatusUnattributed, active.Status)
+	ipua, _, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active", UserID: "u2", IP: "1.2.3.4", UA: "Mozilla/5.0"})
+	require.NoError(t, err)
+	assert.Equal(t, model.ConversionStatusUnattributed, ipua.Status)
+	expired, _, err := s.Report(ctx, "app", &dto.ConversionReq{
+		EventType: "active", UserID: "u3", Oaid: "oaid-1", Timestamp: now.Add(-25 * time.Hour).UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	clicks := []*model.Click{
+		{Callback: "cb-oaid", AdvertiserID: 1001, PromotionID: 2001, Oaid: "oaid-1", ClickTime: now.Add(-time.Minute)},
+		{Callback: "cb-old", AdvertiserID: 1001, Oaid: "oaid-1", ClickTime: now.Add(-26 * time.Hour)},
+	}
+	require.NoError(t, db.Create(clicks).Error)
+
+	attributed, err := s.Reattribute(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attributed)
+
+	conv := &model.Conversion{}
+	require.NoError(t, db.First(conv, active.ID).Error)
+	assert.Equal(t, model.ConversionStatusPending, conv.Status)
+	assert.Equal(t, model.MatchByOaid, conv.MatchType)
+	assert.Equal(t, clicks[0].ID, conv.ClickID)
+	assert.Equal(t, "cb-oaid", conv.Callback)
+	assert.Equal(t, uint64(2001), conv.PromotionID)
+
+	// 未匹配到点击的保持未归因，超出归因窗口的不再重新归因
+	conv = &model.Conversion{}
+	require.NoError(t, db.First(conv, ipua.ID).Error)
+	assert.Equal(t, model.ConversionStatusUnattributed, conv.Status)
+	conv = &model.Conversion{}
+	require.NoError(t, db.First(conv, expired.ID).Error)
+	assert.Equal(t, model.ConversionStatusUnattributed, conv.Status)
+	assert.Empty(t, conv.Callback)
+
+	// 重新归因后记录用户归因，后续事件回传到同一点击
+	pay, _, err := s.Report(ctx, "app", &dto.ConversionReq{EventType: "active_pay", UserID: "u1", OrderID: "o1"})
+	require.NoError(t, err)
+	assert.Equal(t, model.MatchByUser, pay.MatchType)
+	assert.Equal(t, clicks[0].ID, pay.ClickID)
+
+	require.NoError(t, db.Create(&model.Click{Callback: "cb-ip", IP: "1.2.3.4", UA: "Mozilla/5.0", ClickTime: now}).Error)
+	attributed, err = s.Reattribute(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attributed)
+	conv = &model.Conversion{}
+	require.NoError(t, db.First(conv, ipua.ID).Error)
+	assert.Equal(t, model.MatchByIPUA, conv.MatchType)
+	assert.Equal(t, "cb-ip", conv.Callback)
+}
+
+// analyticsServer 模拟转化回传 API，code 为返回码
+type analyticsServer struct {
+	mu       sync.Mutex
+	code     int
+	requests []map[string]interface{}
+	headers  []http.Header
+}
+
+func (s *analyticsServer) start(t *testing.T) *oceansdk.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]interface{}
+		_ = json.Unmarshal(body, &req)
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.headers = append(s.headers, r.Header.Clone())
+		code := s.code
+		s.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "message": "msg"})
+	}))
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	transport := roundTripper(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host, req.Host = target.Scheme, target.Host, target.Host
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	return oceansdk.NewClient(1, "secret", oceansdk.WithHTTPClient(&http.Client{Transport: transport}), oceansdk.WithRetryCount(0))
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestPostback_Dispatch(t *testing.T) {
+	db := newTestDB(t)
+	api := &analyticsServer{}
+	cfg := testConfig()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	cfg.PrivateKeyFile = filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(cfg.PrivateKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	p, err := NewPostback(db, cfg, api.start(t), zap.NewNop())
+	require.NoError(t, err)
+
+	eventTime := time.Now().Add(-time.Minute)
+	conv := &model.Conversion{
+		App: "app", EventType: "active_pay", DedupKey: "order:o1", OrderID: "o1", PayAmount: 600,
+		EventTime: eventTime, Callback: "cb1", Status: model.ConversionStatusPending,
+	}
+	require.NoError(t, db.Create(conv).Error)
+
+	// 回传失败
+	api.code = 40001
+	require.Error(t, p.Dispatch(context.Background(), conv))
+	var saved model.Conversion
+	require.NoError(t, db.First(&saved, conv.ID).Error)
+	assert.Equal(t, model.ConversionStatusFailed, saved.Status)
+	assert.Equal(t, 1, saved.Attempts)
+	assert.NotEmpty(t, saved.ErrorMsg)
+
+	// 未超过回传超时不重试
+	success, failed, err := p.Retry(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, success+failed)
+
+	// 定时任务重试成功
+	api.code = 0
+	db.Model(&model.Conversion{}).Where("id = ?", conv.ID).Update("updated_at", time.Now().Add(-time.Minute))
+	success, failed, err = p.Retry(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Zero(t, failed)
+	require.NoError(t, db.First(&saved, conv.ID).Error)
+	assert.Equal(t, model.ConversionStatusSent, saved.Status)
+	assert.Equal(t, 2, saved.Attempts)
+	assert.NotNil(t, saved.SentAt)
+
+	// 已回传的记录不会被再次领取
+	require.NoError(t, p.Dispatch(context.Background(), &saved))
+	require.Len(t, api.requests, 2)
+
+	req := api.requests[1]
+	assert.Equal(t, "active_pay", req["event_type"])
+	assert.Equal(t, "test", req["source"])
+	assert.Equal(t, float64(eventTime.UnixMilli()), req["timestamp"])
+	assert.Equal(t, "cb1", req["context"].(map[string]interface{})["ad"].(map[string]interface{})["callback"])
+	assert.Equal(t, "o1", req["properties"].(map[string]interface{})["order_id"])
+	assert.Contains(t, api.headers[1].Get("x-rs256-token"), "credential=primary")
+	assert.Empty(t, api.headers[1].Get("x-signature"))
+
+	// 私钥文件无效
+	cfg.PrivateKeyFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = NewPostback(db, cfg, api.start(t), zap.NewNop())
+	assert.Error(t, err)
+}
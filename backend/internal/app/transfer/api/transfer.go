@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oceanengine-backend/internal/app/transfer/dto"
+	"oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/internal/app/transfer/service"
+	"oceanengine-backend/internal/middleware"
+	"oceanengine-backend/pkg/response"
+)
+
+// TransferAPI 资金转账API
+type TransferAPI struct {
+	transferService *service.TransferService
+	executor        *service.Executor
+}
+
+// NewTransferAPI 创建资金转账API
+func NewTransferAPI(transferService *service.TransferService, executor *service.Executor) *TransferAPI {
+	return &TransferAPI{transferService: transferService, executor: executor}
+}
+
+// List godoc
+// @Summary 获取转账单列表
+// @Tags 资金转账
+// @Produce json
+// @Param status query string false "状态"
+// @Param channel query string false "渠道 AGENT/CUSTOMER_CENTER/SHARED_WALLET"
+// @Param advertiser_id query int false "授权账户本地ID"
+// @Param pending_mine query bool false "仅返回当前角色待审批的转账单"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} response.Response{data=[]model.Transfer}
+// @Router /api/v1/transfers [get]
+func (a *TransferAPI) List(c *gin.Context) {
+	var req dto.TransferListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	list, total, err := a.transferService.List(c.Request.Context(), middleware.GetRoleKey(c), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, list, total, req.GetPage(), req.GetPageSize())
+}
+
+// Create godoc
+// @Summary 发起转账
+// @Description 按金额匹配审批链，审批通过后自动执行；相同幂等键重复提交返回已有转账单
+// @Tags 资金转账
+// @Accept json
+// @Produce json
+// @Param request body dto.TransferCreateReq true "转账信息"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers [post]
+func (a *TransferAPI) Create(c *gin.Context) {
+	var req dto.TransferCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := a.transferService.Create(c.Request.Context(), uint64(middleware.GetUserID(c)), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !result.Duplicate {
+		a.execute(result.Transfer)
+	}
+
+	response.Success(c, result)
+}
+
+// Get godoc
+// @Summary 获取转账单详情
+// @Tags 资金转账
+// @Produce json
+// @Param id path int true "转账单ID"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers/{id} [get]
+func (a *TransferAPI) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	result, err := a.transferService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Approve godoc
+// @Summary 审批通过转账单
+// @Description 当前用户角色须为待审批的角色，最后一步审批通过后自动执行
+// @Tags 资金转账
+// @Accept json
+// @Produce json
+// @Param id path int true "转账单ID"
+// @Param request body dto.TransferApproveReq false "审批意见"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers/{id}/approve [post]
+func (a *TransferAPI) Approve(c *gin.Context) {
+	a.review(c, a.transferService.Approve)
+}
+
+// Reject godoc
+// @Summary 驳回转账单
+// @Tags 资金转账
+// @Accept json
+// @Produce json
+// @Param id path int true "转账单ID"
+// @Param request body dto.TransferApproveReq false "驳回原因"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers/{id}/reject [post]
+func (a *TransferAPI) Reject(c *gin.Context) {
+	a.review(c, a.transferService.Reject)
+}
+
+func (a *TransferAPI) review(c *gin.Context, fn func(ctx context.Context, id, userID uint64, roleKey, comment string) (*dto.TransferResp, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+	var req dto.TransferApproveReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	result, err := fn(c.Request.Context(), id, uint64(middleware.GetUserID(c)), middleware.GetRoleKey(c), req.Comment)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	a.execute(result.Transfer)
+
+	response.Success(c, result)
+}
+
+// Cancel godoc
+// @Summary 撤销转账单
+// @Description 发起人撤销待审批的转账单
+// @Tags 资金转账
+// @Produce json
+// @Param id path int true "转账单ID"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers/{id}/cancel [post]
+func (a *TransferAPI) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	result, err := a.transferService.Cancel(c.Request.Context(), id, uint64(middleware.GetUserID(c)))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Reconcile godoc
+// @Summary 继续执行并对账
+// @Description 手动继续执行未完成的转账并查询巨量转账单状态，不检查最大尝试次数；本次执行同样计入尝试次数
+// @Tags 资金转账
+// @Produce json
+// @Param id path int true "转账单ID"
+// @Success 200 {object} response.Response{data=dto.TransferResp}
+// @Router /api/v1/transfers/{id}/reconcile [post]
+func (a *TransferAPI) Reconcile(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid id")
+		return
+	}
+
+	current, err := a.transferService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := a.executor.Reconcile(c.Request.Context(), current.Transfer); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := a.transferService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// execute 审批通过的转账单异步执行，未完成的由定时任务继续执行
+func (a *TransferAPI) execute(t *model.Transfer) {
+	if t.Status != model.TransferStatusApproved {
+		return
+	}
+	transfer := *t
+	// 执行失败已由执行器记录到转账单
+	go func() { _ = a.executor.Execute(context.Background(), &transfer) }()
+}
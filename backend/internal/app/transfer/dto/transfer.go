@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/pkg/utils"
+)
+
+// TransferCreateReq 发起转账请求
+// 代理商转账：account_id 与 source_id 为代理商ID，target_id 为广告主ID，transfer_type 如 CASH_DEFAULT、PREPAY_GENERAL；
+// 客户中心转账：account_id 为客户中心或转出广告主ID，source_id 为转出账户，target_id 为转入广告主ID，transfer_type 如 PREPAY_UNIVERSAL；
+// 共享钱包转账：account_id 为大钱包所属广告主ID，source_id 为大钱包ID，target_id 为小钱包ID，transfer_type 为资金类型如 PREPAY_GENERAL
+type TransferCreateReq struct {
+	IdempotencyKey string `json:"idempotency_key" binding:"required,max=64"`
+	Channel        string `json:"channel" binding:"required,oneof=AGENT CUSTOMER_CENTER SHARED_WALLET"`
+	AccountID      uint64 `json:"account_id" binding:"required"`
+	SourceID       uint64 `json:"source_id" binding:"required"`
+	TargetID       uint64 `json:"target_id" binding:"required"`
+	Direction      string `json:"direction" binding:"required_if=Channel SHARED_WALLET,omitempty,oneof=TRANSFER_IN TRANSFER_OUT"`
+	TransferType   string `json:"transfer_type" binding:"required,max=32"`
+	Amount         int64  `json:"amount" binding:"required,gt=0"` // 单位分
+	Remark         string `json:"remark" binding:"max=500"`
+}
+
+// TransferListReq 转账单列表请求
+type TransferListReq struct {
+	utils.Pagination
+	Status       string `form:"status"`
+	Channel      string `form:"channel"`
+	AdvertiserID uint64 `form:"advertiser_id"` // 本地广告主ID
+	// PendingMine 仅返回当前用户角色待审批的转账单
+	PendingMine bool `form:"pending_mine"`
+}
+
+// TransferApproveReq 审批请求
+type TransferApproveReq struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// TransferResp 转账单详情
+type TransferResp struct {
+	*model.Transfer
+	ApprovalChain []string          `json:"approval_chain"`
+	Approvals     []*model.Approval `json:"approvals,omitempty"`
+	Ledgers       []*model.Ledger   `json:"ledgers,omitempty"`
+	Duplicate     bool              `json:"duplicate,omitempty"` // 相同幂等键的转账单已存在
+}
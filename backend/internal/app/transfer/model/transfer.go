@@ -0,0 +1,107 @@
+package model
+
+import "time"
+
+// Transfer 转账单
+// 审批通过后执行：代理商、客户中心转账先创建交易号再提交，交易号保存后重试只会提交同一交易号；共享钱包转账一步发起
+type Transfer struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	IdempotencyKey string     `gorm:"size:64;uniqueIndex;not null" json:"idempotency_key"` // 发起方提供的幂等键，同一键只创建一笔转账
+	Channel        string     `gorm:"size:32;index;not null" json:"channel"`               // 转账渠道，见 Channel* 常量
+	AccountID      uint64     `gorm:"not null" json:"account_id"`                          // 发起转账的授权账户 (巨量账户ID)，使用其 Token 调用接口
+	SourceID       uint64     `gorm:"not null" json:"source_id"`                           // 转出方：代理商ID、转出广告主ID或大钱包ID
+	TargetID       uint64     `gorm:"not null;index" json:"target_id"`                     // 转入方：广告主ID或小钱包ID
+	AdvertiserID   uint64     `gorm:"not null;index" json:"advertiser_id"`                 // 授权账户的本地ID (ad_advertiser.id)，用于数据权限
+	Direction      string     `gorm:"size:16" json:"direction"`                            // 共享钱包转账方向 (以小钱包视角)：TRANSFER_IN、TRANSFER_OUT
+	TransferType   string     `gorm:"size:32;not null" json:"transfer_type"`               // 资金类型
+	Amount         int64      `gorm:"not null" json:"amount"`                              // 转账金额，单位分
+	Remark         string     `gorm:"size:500" json:"remark"`
+	Status         string     `gorm:"size:32;index;not null" json:"status"`
+	ApprovalRoles  string     `gorm:"size:255" json:"approval_roles"` // 审批链角色，逗号分隔，创建时按金额确定
+	ApprovalStep   int        `gorm:"default:0" json:"approval_step"` // 已完成的审批步骤数
+	NextRole       string     `gorm:"size:64;index" json:"next_role"` // 待审批的角色，非待审批状态为空
+	TransactionSeq string     `gorm:"size:64" json:"transaction_seq"` // 交易号；共享钱包转账为发起转账的幂等ID (biz_request_no)
+	TransferSerial string     `gorm:"size:64" json:"transfer_serial"` // 巨量转账单号
+	RemoteStatus   string     `gorm:"size:32" json:"remote_status"`   // 对账时巨量转账单状态
+	RemoteAmount   int64      `gorm:"default:0" json:"remote_amount"` // 对账时巨量实际转账金额，单位分
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	ErrorMsg       string     `gorm:"size:500" json:"error_msg"`
+	RequestedBy    uint64     `gorm:"not null;index" json:"requested_by"`
+	ExecutedAt     *time.Time `json:"executed_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (Transfer) TableName() string {
+	return "fund_transfer"
+}
+
+// 转账渠道
+const (
+	ChannelAgent          = "AGENT"           // 代理商转账到广告主
+	ChannelCustomerCenter = "CUSTOMER_CENTER" // 客户中心或广告主之间转账
+	ChannelSharedWallet   = "SHARED_WALLET"   // 共享钱包大小钱包互转
+)
+
+// 转账状态
+const (
+	TransferStatusPending   = "PENDING_APPROVAL"
+	TransferStatusApproved  = "APPROVED"  // 审批通过，等待执行
+	TransferStatusExecuting = "EXECUTING" // 已创建交易号，尚未确认提交
+	TransferStatusSubmitted = "SUBMITTED" // 已提交，等待对账确认
+	TransferStatusSucceeded = "SUCCEEDED"
+	TransferStatusPartial   = "PARTIAL" // 部分成功，按对账金额记账
+	TransferStatusFailed    = "FAILED"
+	TransferStatusRejected  = "REJECTED"
+	TransferStatusCancelled = "CANCELLED"
+)
+
+// Approval 转账审批记录
+type Approval struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransferID uint64    `gorm:"uniqueIndex:uk_fund_transfer_approval;not null" json:"transfer_id"`
+	Step       int       `gorm:"uniqueIndex:uk_fund_transfer_approval;not null" json:"step"`
+	Role       string    `gorm:"size:64;not null" json:"role"`
+	ApproverID uint64    `gorm:"not null" json:"approver_id"`
+	Action     string    `gorm:"size:16;not null" json:"action"`
+	Comment    string    `gorm:"size:500" json:"comment"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (Approval) TableName() string {
+	return "fund_transfer_approval"
+}
+
+// 审批操作
+const (
+	ApprovalActionApprove = "APPROVE"
+	ApprovalActionReject  = "REJECT"
+)
+
+// Ledger 转账台账
+// 转账成功或部分成功后按对账金额记账，涉及本地广告主的分录同时写入资金流水 (ad_advertiser_fund)
+type Ledger struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransferID     uint64    `gorm:"uniqueIndex:uk_fund_ledger;not null" json:"transfer_id"`
+	Direction      string    `gorm:"size:8;uniqueIndex:uk_fund_ledger;not null" json:"direction"` // IN、OUT
+	AccountID      uint64    `gorm:"not null;index" json:"account_id"`                            // 巨量账户ID或钱包ID
+	AdvertiserID   uint64    `gorm:"default:0;index" json:"advertiser_id"`                        // 本地广告主ID，非本地广告主为 0
+	FundID         uint64    `gorm:"default:0" json:"fund_id"`                                    // 对应的资金流水ID
+	Amount         int64     `gorm:"not null" json:"amount"`                                      // 单位分
+	TransactionSeq string    `gorm:"size:64" json:"transaction_seq"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 表名
+func (Ledger) TableName() string {
+	return "fund_transfer_ledger"
+}
+
+// 台账方向
+const (
+	LedgerDirectionIn  = "IN"
+	LedgerDirectionOut = "OUT"
+)
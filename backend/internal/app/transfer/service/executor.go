@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/agent"
+	"github.com/bububa/oceanengine/marketing-api/api/customercenter"
+	"github.com/bububa/oceanengine/marketing-api/api/sharedwallet"
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	agentModel "github.com/bububa/oceanengine/marketing-api/model/agent"
+	customercenterModel "github.com/bububa/oceanengine/marketing-api/model/customercenter"
+	sharedwalletModel "github.com/bububa/oceanengine/marketing-api/model/sharedwallet"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/outbox"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+// sharedWalletAccountType 共享钱包接口的鉴权账户类型
+const sharedWalletAccountType = "AD"
+
+// Executor 转账执行器
+// 代理商、客户中心转账分两步：创建交易号并保存，再提交该交易号；中断后重试只会提交已保存的交易号，不会重复转账。
+// 共享钱包转账先保存幂等ID再发起，结果未知时按幂等ID查询转账单，未查到时以同一幂等ID重新发起。
+// 提交后按巨量转账单状态对账，成功或部分成功时按实际金额写入台账与资金流水
+type Executor struct {
+	db  *gorm.DB
+	cfg *config.TransferConfig
+	sdk *oceansdk.Client
+	log *zap.Logger
+}
+
+// NewExecutorSDK 创建转账执行使用的 SDK 客户端
+// 转账请求失败时由执行器按交易号对账后决定是否重新提交，客户端不自动重试，避免重复发起转账
+func NewExecutorSDK(cfg *config.OceanConfig, tokens oceansdk.TokenResolver, log *zap.Logger) *oceansdk.Client {
+	return oceanclient.NewSDK(cfg, oceansdk.WithRetryCount(0), oceansdk.WithTokenResolver(tokens), oceansdk.WithLogger(log.Named("oceanengine")))
+}
+
+// NewExecutor 创建转账执行器，sdk 使用 NewExecutorSDK 创建
+func NewExecutor(db *gorm.DB, cfg *config.TransferConfig, sdk *oceansdk.Client, log *zap.Logger) *Executor {
+	return &Executor{db: db, cfg: cfg, sdk: sdk, log: log}
+}
+
+// executable 可执行的转账状态
+var executable = []string{model.TransferStatusApproved, model.TransferStatusExecuting, model.TransferStatusSubmitted}
+
+// Execute 执行或继续执行转账
+// 通过 attempts 条件更新领取转账单，转账单正被其他实例执行时直接返回
+func (e *Executor) Execute(ctx context.Context, t *model.Transfer) error {
+	claimed, err := outbox.Claim(e.db.WithContext(ctx).Model(&model.Transfer{}).Where("status IN ?", executable), t.ID, t.Attempts, nil)
+	if err != nil || !claimed {
+		return err
+	}
+	t.Attempts++
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.ExecuteTimeout)
+	defer cancel()
+
+	switch {
+	case t.Status == model.TransferStatusSubmitted:
+		err = e.reconcile(ctx, t)
+	case t.Channel == model.ChannelSharedWallet && t.Status == model.TransferStatusExecuting && t.TransactionSeq == "":
+		// 未保存幂等ID的转账单无法查询发起结果
+		err = errcode.NewWithMessage(errcode.ErrTransferStatus, "共享钱包转账结果未知，请在巨量后台核实")
+	case t.Status == model.TransferStatusExecuting:
+		err = e.resume(ctx, t)
+	default:
+		err = e.start(ctx, t)
+	}
+	if err != nil {
+		e.log.Warn(fmt.Sprintf("[Transfer] 转账单 %d 第 %d 次执行失败: %v", t.ID, t.Attempts, err))
+		e.recordError(t, err)
+	}
+	return err
+}
+
+// start 执行已审批的转账
+func (e *Executor) start(ctx context.Context, t *model.Transfer) error {
+	if t.Channel == model.ChannelSharedWallet {
+		// 先保存幂等ID并标记为执行中，发起结果未知时按幂等ID查询或重新发起，不会重复转账
+		if err := e.update(t, map[string]interface{}{
+			"status":          model.TransferStatusExecuting,
+			"transaction_seq": uuid.New().String(),
+			"executed_at":     time.Now(),
+		}); err != nil {
+			return err
+		}
+		return e.submitWallet(ctx, t, false)
+	}
+
+	seq, err := e.createSeq(ctx, t)
+	if err != nil {
+		// 交易号未创建，资金未变动
+		if !ambiguous(err) {
+			return e.fail(t, err)
+		}
+		return err
+	}
+	if err := e.update(t, map[string]interface{}{
+		"status":          model.TransferStatusExecuting,
+		"transaction_seq": seq,
+		"executed_at":     time.Now(),
+	}); err != nil {
+		return err
+	}
+	return e.commit(ctx, t, false)
+}
+
+// resume 继续执行已创建交易号的转账：巨量已有转账单时直接对账，否则重新提交同一交易号或幂等ID
+func (e *Executor) resume(ctx context.Context, t *model.Transfer) error {
+	if detail, err := e.detail(ctx, t); err == nil && detail.status != enum.TransferStatus_NO_TRANSFER {
+		updates := map[string]interface{}{"status": model.TransferStatusSubmitted}
+		if t.TransferSerial == "" && detail.serial != "" {
+			updates["transfer_serial"] = detail.serial
+		}
+		if err := e.update(t, updates); err != nil {
+			return err
+		}
+		return e.apply(t, detail)
+	}
+	if t.Channel == model.ChannelSharedWallet {
+		return e.submitWallet(ctx, t, true)
+	}
+	return e.commit(ctx, t, true)
+}
+
+// submitWallet 以保存的幂等ID发起共享钱包转账并对账
+// 重新发起时转账可能已受理，巨量拒绝也不能确定转账未发生，保持执行中等待对账或人工核实
+func (e *Executor) submitWallet(ctx context.Context, t *model.Transfer, retry bool) error {
+	serial, err := oceansdk.Call(ctx, e.sdk, t.AccountID, sharedwallet.TransferCreate, e.walletRequest(t))
+	if err != nil {
+		if !retry && !ambiguous(err) {
+			return e.fail(t, err)
+		}
+		return err
+	}
+	if err := e.update(t, map[string]interface{}{"status": model.TransferStatusSubmitted, "transfer_serial": serial}); err != nil {
+		return err
+	}
+	return e.reconcile(ctx, t)
+}
+
+// commit 提交交易号并对账
+// 重新提交时交易号可能已被提交过，巨量拒绝也不能确定转账未发生，保持执行中等待对账或人工核实
+func (e *Executor) commit(ctx context.Context, t *model.Transfer, retry bool) error {
+	serial, err := e.commitSeq(ctx, t)
+	if err != nil {
+		if !retry && !ambiguous(err) {
+			return e.fail(t, err)
+		}
+		return err
+	}
+	if serial == "" {
+		serial = t.TransactionSeq
+	}
+	if err := e.update(t, map[string]interface{}{"status": model.TransferStatusSubmitted, "transfer_serial": serial}); err != nil {
+		return err
+	}
+	return e.reconcile(ctx, t)
+}
+
+// createSeq 创建转账交易号
+func (e *Executor) createSeq(ctx context.Context, t *model.Transfer) (string, error) {
+	if t.Channel == model.ChannelAgent {
+		return oceansdk.Call(ctx, e.sdk, t.AccountID, agent.FundTransferSeqCreate, &agentModel.FundTransferSeqCreateRequest{
+			AgentID:      t.SourceID,
+			AccountID:    t.TargetID,
+			TransferType: agentModel.FundTransferType(t.TransferType),
+			Amount:       yuan(t.Amount),
+		})
+	}
+	seq, err := oceansdk.Call(ctx, e.sdk, t.AccountID, customercenter.FundTransferSeqCreate, &customercenterModel.FundTransferSeqCreateRequest{
+		AdvertiserID:       t.SourceID,
+		TargetAdvertiserID: t.TargetID,
+		Amount:             yuan(t.Amount),
+		TransferType:       customercenterModel.TransferType(t.TransferType),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(seq, 10), nil
+}
+
+// commitSeq 提交转账交易号，返回巨量转账单号
+func (e *Executor) commitSeq(ctx context.Context, t *model.Transfer) (string, error) {
+	if t.Channel == model.ChannelAgent {
+		return oceansdk.Call(ctx, e.sdk, t.AccountID, agent.FundTransferSeqCommit, &agentModel.FundTransferSeqCommitRequest{
+			AgentID:     t.SourceID,
+			TransferSeq: t.TransactionSeq,
+		})
+	}
+	seq, err := strconv.ParseUint(t.TransactionSeq, 10, 64)
+	if err != nil {
+		return "", errcode.WrapWithMessage(errcode.ErrInvalidParams, "交易号无效", err)
+	}
+	serial, err := oceansdk.Call(ctx, e.sdk, t.AccountID, customercenter.FundTransferSeqCommit, &customercenterModel.FundTransferSeqCommitRequest{
+		AdvertiserID:       t.SourceID,
+		TargetAdvertiserID: t.TargetID,
+		TransactionSeq:     seq,
+	})
+	if err != nil {
+		return "", err
+	}
+	if serial == 0 {
+		return "", nil
+	}
+	return strconv.FormatUint(serial, 10), nil
+}
+
+// walletRequest 构造共享钱包转账请求，金额单位为分
+func (e *Executor) walletRequest(t *model.Transfer) *sharedwalletModel.TransferCreateRequest {
+	return &sharedwalletModel.TransferCreateRequest{
+		AccountID:         t.AccountID,
+		AccountType:       sharedWalletAccountType,
+		BizRequestNo:      t.TransactionSeq,
+		MainWalletID:      t.SourceID,
+		TransferDirection: enum.TransferDirection(t.Direction),
+		TargetWalletDetailList: []sharedwalletModel.TargetWalletDetail{{
+			SubWalletID: t.TargetID,
+			TransferCapitalDetailList: []sharedwalletModel.CapitalDetail{{
+				Platform:        "AD",
+				CapitalType:     enum.CapitalType(t.TransferType),
+				TransferBalance: t.Amount,
+			}},
+		}},
+	}
+}
+
+// remoteDetail 巨量转账单对账结果
+type remoteDetail struct {
+	serial string
+	status enum.TransferStatus
+	amount int64 // 实际转账金额，单位分
+}
+
+// detail 查询巨量转账单，提交未收到应答时按交易号或幂等ID查询
+func (e *Executor) detail(ctx context.Context, t *model.Transfer) (*remoteDetail, error) {
+	bizRequestNo := uuid.New().String()
+	serial := t.TransferSerial
+	if serial == "" {
+		serial = t.TransactionSeq
+	}
+	switch t.Channel {
+	case model.ChannelAgent:
+		d, err := oceansdk.Call(ctx, e.sdk, t.AccountID, agent.QueryTransferDetail, &agentModel.QueryTransferDetailRequest{
+			BizRequestNo:   bizRequestNo,
+			AgentID:        t.SourceID,
+			TransferSerial: serial,
+		})
+		if err != nil || d == nil {
+			return nil, detailError(err)
+		}
+		return &remoteDetail{serial: d.TransferSerial, status: d.TransferStatus, amount: d.TransferAmount}, nil
+	case model.ChannelSharedWallet:
+		req := &sharedwalletModel.TransferDetailRequest{
+			AccountID:      t.AccountID,
+			AccountType:    sharedWalletAccountType,
+			BizRequestNo:   bizRequestNo,
+			TransferSerial: t.TransferSerial,
+		}
+		if req.TransferSerial == "" {
+			req.TransferBizRequestNo = t.TransactionSeq
+		}
+		d, err := oceansdk.Call(ctx, e.sdk, t.AccountID, sharedwallet.TransferDetail, req)
+		if err != nil || d == nil {
+			return nil, detailError(err)
+		}
+		return &remoteDetail{serial: d.TransferSerial, status: d.TransferStatus, amount: d.TransferAmount}, nil
+	default:
+		d, err := oceansdk.Call(ctx, e.sdk, t.AccountID, customercenter.TransferDetailGet, &customercenterModel.TransferDetailGetRequest{
+			OrganizationID: t.AccountID,
+			BizRequestNo:   bizRequestNo,
+			TransferSerial: serial,
+			Platform:       "AD",
+		})
+		if err != nil || d == nil {
+			return nil, detailError(err)
+		}
+		return &remoteDetail{serial: d.TransferSerial, status: d.TransferStatus, amount: d.TransferAmount}, nil
+	}
+}
+
+func detailError(err error) error {
+	if err != nil {
+		return err
+	}
+	return errcode.NewWithMessage(errcode.ErrOEAPIFailed, "转账单详情为空")
+}
+
+// reconcile 查询巨量转账单并更新状态
+func (e *Executor) reconcile(ctx context.Context, t *model.Transfer) error {
+	detail, err := e.detail(ctx, t)
+	if err != nil {
+		return err
+	}
+	return e.apply(t, detail)
+}
+
+// apply 按巨量转账单状态更新转账单：成功或部分成功时记账，失败时结束，处理中保持已提交状态等待下次对账
+func (e *Executor) apply(t *model.Transfer, detail *remoteDetail) error {
+	switch detail.status {
+	case enum.TransferStatus_TRANSFER_SUCCESS, enum.TransferStatus_TRANSFER_PART:
+		return e.book(t, detail)
+	case enum.TransferStatus_TRANSFER_FAILED:
+		return e.update(t, map[string]interface{}{
+			"status":        model.TransferStatusFailed,
+			"remote_status": string(detail.status),
+			"finished_at":   time.Now(),
+		})
+	default:
+		return e.update(t, map[string]interface{}{"remote_status": string(detail.status), "error_msg": ""})
+	}
+}
+
+// ledgerEntry 台账分录
+type ledgerEntry struct {
+	direction string
+	accountID uint64
+}
+
+// entries 转出、转入分录，共享钱包转账方向以小钱包视角确定
+func entries(t *model.Transfer) []ledgerEntry {
+	out, in := t.SourceID, t.TargetID
+	if t.Channel == model.ChannelSharedWallet && t.Direction == string(enum.TRANSFER_OUT) {
+		out, in = t.TargetID, t.SourceID
+	}
+	return []ledgerEntry{
+		{direction: model.LedgerDirectionOut, accountID: out},
+		{direction: model.LedgerDirectionIn, accountID: in},
+	}
+}
+
+// book 在同一事务中更新转账单状态并写入台账，涉及本地广告主的分录同时写入资金流水并调整余额
+// 以状态条件更新保证同一转账单只记账一次
+func (e *Executor) book(t *model.Transfer, detail *remoteDetail) error {
+	status := model.TransferStatusSucceeded
+	if detail.status == enum.TransferStatus_TRANSFER_PART {
+		status = model.TransferStatusPartial
+	}
+	amount := t.Amount
+	if detail.amount > 0 || status == model.TransferStatusPartial {
+		amount = detail.amount
+	}
+	errMsg := ""
+	if amount != t.Amount {
+		errMsg = fmt.Sprintf("对账金额 %.2f 与申请金额 %.2f 不一致", yuan(amount), yuan(t.Amount))
+	}
+	now := time.Now()
+
+	// 使用独立 context，执行超时后仍需完成记账
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Transfer{}).
+			Where("id = ? AND status IN ?", t.ID, []string{model.TransferStatusExecuting, model.TransferStatusSubmitted}).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"remote_status": string(detail.status),
+				"remote_amount": amount,
+				"error_msg":     errMsg,
+				"finished_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, entry := range entries(t) {
+			ledger := &model.Ledger{
+				TransferID:     t.ID,
+				Direction:      entry.direction,
+				AccountID:      entry.accountID,
+				Amount:         amount,
+				TransactionSeq: t.TransactionSeq,
+			}
+			if t.Channel != model.ChannelSharedWallet {
+				fund, err := e.fund(tx, t, entry, amount, now)
+				if err != nil {
+					return err
+				}
+				if fund != nil {
+					ledger.AdvertiserID = fund.AdvertiserID
+					ledger.FundID = fund.ID
+				}
+			}
+			if err := tx.Create(ledger).Error; err != nil {
+				return err
+			}
+		}
+		t.Status = status
+		return nil
+	})
+}
+
+// fund 分录账户为本地广告主时写入资金流水并调整余额，非本地广告主返回 nil
+// 资金流水与广告主余额单位为元，amount (分) 在此转换
+func (e *Executor) fund(tx *gorm.DB, t *model.Transfer, entry ledgerEntry, amount int64, now time.Time) (*advModel.AdvertiserFund, error) {
+	var adv advModel.Advertiser
+	if err := tx.Select("id", "balance").Where("advertiser_id = ?", entry.accountID).First(&adv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	transactionType, delta := advModel.TransactionTypeTransferIn, amount
+	if entry.direction == model.LedgerDirectionOut {
+		transactionType, delta = advModel.TransactionTypeTransferOut, -amount
+	}
+	balance := int64(math.Round(adv.Balance * 100))
+	fund := &advModel.AdvertiserFund{
+		AdvertiserID:    adv.ID,
+		TransactionType: transactionType,
+		Amount:          yuan(amount),
+		BalanceBefore:   yuan(balance),
+		BalanceAfter:    yuan(balance + delta),
+		TransactionSeq:  t.TransactionSeq,
+		TransactionTime: &now,
+		Remark:          fmt.Sprintf("转账单 %d", t.ID),
+	}
+	if err := tx.Create(fund).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&advModel.Advertiser{}).Where("id = ?", adv.ID).
+		Update("balance", gorm.Expr("balance + ?", yuan(delta))).Error; err != nil {
+		return nil, err
+	}
+	return fund, nil
+}
+
+// fail 巨量明确拒绝时结束转账单
+func (e *Executor) fail(t *model.Transfer, cause error) error {
+	if err := e.update(t, map[string]interface{}{
+		"status":      model.TransferStatusFailed,
+		"finished_at": time.Now(),
+	}); err != nil {
+		return err
+	}
+	return cause
+}
+
+// update 更新转账单并同步到 t
+func (e *Executor) update(t *model.Transfer, updates map[string]interface{}) error {
+	if err := outbox.Record(e.db, &model.Transfer{}, t.ID, updates); err != nil {
+		return err
+	}
+	if status, ok := updates["status"].(string); ok {
+		t.Status = status
+	}
+	if seq, ok := updates["transaction_seq"].(string); ok {
+		t.TransactionSeq = seq
+	}
+	if serial, ok := updates["transfer_serial"].(string); ok {
+		t.TransferSerial = serial
+	}
+	return nil
+}
+
+// recordError 记录最近一次执行错误
+func (e *Executor) recordError(t *model.Transfer, cause error) {
+	if err := outbox.Record(e.db, &model.Transfer{}, t.ID, map[string]interface{}{"error_msg": outbox.ErrorMessage(cause.Error())}); err != nil {
+		e.log.Error(fmt.Sprintf("[Transfer] 记录转账单 %d 执行错误失败: %v", t.ID, err))
+	}
+}
+
+// yuan 分转换为元，用于以元为单位的巨量接口、资金流水与展示
+func yuan(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// ambiguous 请求结果未知或可重试：网络错误、超时、HTTP 5xx、巨量系统错误与限流；
+// 巨量返回业务错误码 (4xxxx) 时视为明确失败
+func ambiguous(err error) bool {
+	var appErr *errcode.AppError
+	if !errors.As(err, &appErr) {
+		return true
+	}
+	if details, ok := appErr.Details.(errcode.OceanEngineDetails); ok {
+		return details.Code < 40000 || details.Code >= 50000 || appErr.Code == errcode.ErrOERateLimit
+	}
+	return appErr.Code == errcode.ErrOEAPIFailed || appErr.Code == errcode.ErrTimeout
+}
+
+// Reconcile 手动继续执行或对账，不检查最大尝试次数
+// 与定时任务同样通过 attempts 领取转账单并计入尝试次数，次数已达上限的转账单此后仍只能手动处理
+func (e *Executor) Reconcile(ctx context.Context, t *model.Transfer) error {
+	if t.Status != model.TransferStatusApproved && t.Status != model.TransferStatusExecuting && t.Status != model.TransferStatusSubmitted {
+		return errcode.New(errcode.ErrTransferStatus)
+	}
+	return e.Execute(ctx, t)
+}
+
+// Resume 继续执行超时仍未完成的转账，返回成功与失败数
+// 未保存幂等ID的共享钱包转账发起结果未知，需人工核实，不自动重试
+func (e *Executor) Resume(ctx context.Context, limit int) (success, failed int, err error) {
+	query := e.db.Model(&model.Transfer{}).
+		Where("status IN ?", executable).
+		Where("NOT (channel = ? AND status = ? AND transaction_seq = ?)", model.ChannelSharedWallet, model.TransferStatusExecuting, "")
+	return outbox.Retry(ctx, query, e.cfg.MaxAttempts, e.cfg.ExecuteTimeout, limit, e.Execute)
+}
@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/transfer/dto"
+	"oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+type stubTokens struct{}
+
+func (stubTokens) GetAccessToken(context.Context, uint64) (string, error) {
+	return "token", nil
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&advModel.Advertiser{}, &advModel.AdvertiserFund{}, &model.Transfer{}, &model.Approval{}, &model.Ledger{}))
+	// 本地广告主：1001 (客户中心授权账户)、1002 (转入方)
+	require.NoError(t, db.Create(&[]advModel.Advertiser{
+		{ID: 1, AdvertiserID: 1001, Name: "源账户", Balance: 1000},
+		{ID: 2, AdvertiserID: 1002, Name: "目标账户", Balance: 0},
+	}).Error)
+	return db
+}
+
+func testConfig() *config.TransferConfig {
+	return &config.TransferConfig{
+		ApprovalRules: []config.ApprovalRule{
+			{MinAmount: 0, Roles: []string{"admin"}},
+			{MinAmount: 50000, Roles: []string{"finance", "admin"}},
+			{MinAmount: 1000000, Roles: []string{"finance", "admin", "ceo"}},
+		},
+		ExecuteTimeout: time.Second,
+		MaxAttempts:    3,
+	}
+}
+
+func createReq(key string, amount int64) *dto.TransferCreateReq {
+	return &dto.TransferCreateReq{
+		IdempotencyKey: key,
+		Channel:        model.ChannelCustomerCenter,
+		AccountID:      1001,
+		SourceID:       1001,
+		TargetID:       1002,
+		TransferType:   "PREPAY_UNIVERSAL",
+		Amount:         amount,
+	}
+}
+
+func errCode(err error) int {
+	var appErr *errcode.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return 0
+}
+
+func TestTransferService_Create(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewTransferService(db, testConfig())
+	ctx := context.Background()
+
+	small, err := svc.Create(ctx, 1, createReq("k1", 10000))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, small.ApprovalChain)
+	assert.Equal(t, "admin", small.NextRole)
+	assert.Equal(t, uint64(1), small.AdvertiserID)
+
+	large, err := svc.Create(ctx, 1, createReq("k2", 5000000))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"finance", "admin"}, large.ApprovalChain)
+	assert.Equal(t, model.TransferStatusPending, large.Status)
+
+	// 相同幂等键返回已有转账单，参数不一致时拒绝
+	dup, err := svc.Create(ctx, 1, createReq("k1", 10000))
+	require.NoError(t, err)
+	assert.True(t, dup.Duplicate)
+	assert.Equal(t, small.ID, dup.ID)
+	_, err = svc.Create(ctx, 1, createReq("k1", 20000))
+	assert.Equal(t, errcode.ErrInvalidParams, errCode(err))
+
+	// 授权账户不在数据权限内
+	scoped := datascope.WithFilter(ctx, datascope.NewFilter([]uint64{2}))
+	_, err = svc.Create(scoped, 1, createReq("k4", 10000))
+	assert.Equal(t, errcode.ErrPermissionDeny, errCode(err))
+
+	// 未配置审批角色时直接进入待执行状态
+	cfg := testConfig()
+	cfg.ApprovalRules = []config.ApprovalRule{{MinAmount: 0}}
+	auto, err := NewTransferService(db, cfg).Create(ctx, 1, createReq("k5", 10000))
+	require.NoError(t, err)
+	assert.Equal(t, model.TransferStatusApproved, auto.Status)
+	assert.Empty(t, auto.ApprovalChain)
+}
+
+func TestTransferService_Approve(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewTransferService(db, testConfig())
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, 1, createReq("k1", 6000000))
+	require.NoError(t, err)
+
+	// 非待审批角色、发起人不能审批
+	_, err = svc.Approve(ctx, created.ID, 2, "admin", "")
+	assert.Equal(t, errcode.ErrTransferApprover, errCode(err))
+	_, err = svc.Approve(ctx, created.ID, 1, "finance", "")
+	assert.Equal(t, errcode.ErrTransferApprover, errCode(err))
+
+	step1, err := svc.Approve(ctx, created.ID, 2, "finance", "ok")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransferStatusPending, step1.Status)
+	assert.Equal(t, "admin", step1.NextRole)
+	assert.Len(t, step1.Approvals, 1)
+
+	// 同一用户不能审批多个步骤
+	_, err = svc.Approve(ctx, created.ID, 2, "admin", "")
+	assert.Equal(t, errcode.ErrTransferApprover, errCode(err))
+
+	list, total, err := svc.List(ctx, "admin", &dto.TransferListReq{PendingMine: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, created.ID, list[0].ID)
+
+	step2, err := svc.Approve(ctx, created.ID, 3, "admin", "")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransferStatusApproved, step2.Status)
+	assert.Empty(t, step2.NextRole)
+	assert.Len(t, step2.Approvals, 2)
+
+	_, err = svc.Approve(ctx, created.ID, 4, "admin", "")
+	assert.Equal(t, errcode.ErrTransferStatus, errCode(err))
+	_, err = svc.Cancel(ctx, created.ID, 1)
+	assert.Equal(t, errcode.ErrTransferStatus, errCode(err))
+}
+
+func TestTransferService_RejectAndCancel(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewTransferService(db, testConfig())
+	ctx := context.Background()
+
+	rejected, err := svc.Create(ctx, 1, createReq("k1", 10000))
+	require.NoError(t, err)
+	result, err := svc.Reject(ctx, rejected.ID, 2, "admin", "金额有误")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransferStatusRejected, result.Status)
+	assert.Equal(t, model.ApprovalActionReject, result.Approvals[0].Action)
+
+	cancelled, err := svc.Create(ctx, 1, createReq("k2", 10000))
+	require.NoError(t, err)
+	_, err = svc.Cancel(ctx, cancelled.ID, 2)
+	assert.Equal(t, errcode.ErrPermissionDeny, errCode(err))
+	result, err = svc.Cancel(ctx, cancelled.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransferStatusCancelled, result.Status)
+}
+
+// oceanServer 模拟巨量转账接口，记录各接口调用次数
+type oceanServer struct {
+	mu sync.Mutex
+	// handle 按路径返回 data 与错误码，code 为 -1 时返回 HTTP 500，为 -2 时不应答直接断开连接
+	handle  func(path string, calls int) (data interface{}, code int)
+	calls   map[string]int
+	bodies  map[string]string // 各接口最近一次请求体
+	queries map[string]string // 各接口最近一次查询参数
+	url     string
+}
+
+func (s *oceanServer) start(t *testing.T) *oceansdk.Client {
+	s.calls = make(map[string]int)
+	s.bodies = make(map[string]string)
+	s.queries = make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/open_api/")
+		s.mu.Lock()
+		body, _ := io.ReadAll(r.Body)
+		s.calls[path]++
+		s.bodies[path] = string(body)
+		s.queries[path] = r.URL.RawQuery
+		n := s.calls[path]
+		s.mu.Unlock()
+		data, code := s.handle(path, n)
+		switch code {
+		case -1:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case -2:
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "message": "msg", "data": data})
+	}))
+	t.Cleanup(srv.Close)
+	s.url = srv.URL
+	return oceansdk.NewClient(1, "secret", oceansdk.WithBaseURL(srv.URL+"/open_api"), oceansdk.WithRetryCount(0), oceansdk.WithTokenResolver(stubTokens{}))
+}
+
+func (s *oceanServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+func (s *oceanServer) body(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[path]
+}
+
+func (s *oceanServer) query(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[path]
+}
+
+const (
+	pathSeqCreate   = "2/customer_center/fund/transfer_seq/create/"
+	pathSeqCommit   = "2/customer_center/fund/transfer_seq/commit/"
+	pathDetail      = "v3.0/cg_transfer/transfer_detail/get/"
+	pathWalletTrans = "v3.0/cg_transfer/wallet/transfer/create/"
+	pathWalletInfo  = "v3.0/cg_transfer/wallet/transfer/detail/"
+)
+
+func approvedTransfer(t *testing.T, db *gorm.DB, channel string, amount int64) *model.Transfer {
+	tr := &model.Transfer{
+		IdempotencyKey: channel,
+		Channel:        channel,
+		AccountID:      1001,
+		SourceID:       1001,
+		TargetID:       1002,
+		AdvertiserID:   1,
+		TransferType:   "PREPAY_UNIVERSAL",
+		Amount:         amount,
+		Status:         model.TransferStatusApproved,
+		RequestedBy:    1,
+	}
+	require.NoError(t, db.Create(tr).Error)
+	return tr
+}
+
+func reload(t *testing.T, db *gorm.DB, id uint64) *model.Transfer {
+	var tr model.Transfer
+	require.NoError(t, db.First(&tr, id).Error)
+	return &tr
+}
+
+func TestExecutor_CustomerCenter(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, _ int) (interface{}, int) {
+		switch path {
+		case pathSeqCreate, pathSeqCommit:
+			return map[string]interface{}{"transaction_seq": 123}, 0
+		case pathDetail:
+			return map[string]interface{}{"transfer_serial": "123", "transfer_amount": 10000, "transfer_status": "TRANSFER_SUCCESS"}, 0
+		}
+		return nil, 40002
+	}}
+	executor := NewExecutor(db, testConfig(), server.start(t), zap.NewNop())
+
+	tr := approvedTransfer(t, db, model.ChannelCustomerCenter, 10000)
+	require.NoError(t, executor.Execute(context.Background(), tr))
+
+	tr = reload(t, db, tr.ID)
+	assert.Equal(t, model.TransferStatusSucceeded, tr.Status)
+	assert.Equal(t, "123", tr.TransactionSeq)
+	assert.Contains(t, server.body(pathSeqCreate), `"amount":100,`) // 客户中心接口金额单位为元
+	assert.Equal(t, int64(10000), tr.RemoteAmount)
+	assert.NotNil(t, tr.FinishedAt)
+
+	var ledgers []*model.Ledger
+	require.NoError(t, db.Order("id").Find(&ledgers).Error)
+	require.Len(t, ledgers, 2)
+	assert.Equal(t, model.LedgerDirectionOut, ledgers[0].Direction)
+	assert.Equal(t, uint64(1), ledgers[0].AdvertiserID)
+	assert.Equal(t, model.LedgerDirectionIn, ledgers[1].Direction)
+	assert.Equal(t, uint64(2), ledgers[1].AdvertiserID)
+
+	var fund advModel.AdvertiserFund
+	require.NoError(t, db.First(&fund, ledgers[1].FundID).Error)
+	assert.Equal(t, advModel.TransactionTypeTransferIn, fund.TransactionType)
+	assert.Equal(t, 100.0, fund.Amount)
+	assert.Equal(t, 100.0, fund.BalanceAfter)
+	var source advModel.Advertiser
+	require.NoError(t, db.First(&source, 1).Error)
+	assert.Equal(t, 900.0, source.Balance)
+
+	// 已完成的转账单不会重复执行或记账
+	require.NoError(t, executor.Execute(context.Background(), tr))
+	var count int64
+	db.Model(&model.Ledger{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 1, server.count(pathSeqCommit))
+}
+
+func TestExecutor_ResumeAfterCommitFailure(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, calls int) (interface{}, int) {
+		switch path {
+		case pathSeqCreate:
+			return map[string]interface{}{"transaction_seq": 123}, 0
+		case pathSeqCommit:
+			if calls == 1 {
+				return nil, -1 // 提交未收到应答
+			}
+			return map[string]interface{}{"transaction_seq": 123}, 0
+		case pathDetail:
+			if calls == 1 {
+				return map[string]interface{}{"transfer_status": "NO_TRANSFER"}, 0
+			}
+			return map[string]interface{}{"transfer_serial": "123", "transfer_amount": 6000, "transfer_status": "TRANSFER_PART"}, 0
+		}
+		return nil, 40002
+	}}
+	executor := NewExecutor(db, testConfig(), server.start(t), zap.NewNop())
+
+	tr := approvedTransfer(t, db, model.ChannelCustomerCenter, 10000)
+	require.Error(t, executor.Execute(context.Background(), tr))
+	tr = reload(t, db, tr.ID)
+	assert.Equal(t, model.TransferStatusExecuting, tr.Status)
+	assert.Equal(t, "123", tr.TransactionSeq)
+	assert.NotEmpty(t, tr.ErrorMsg)
+
+	// 未超时的转账单不会被定时任务处理
+	success, failed, err := executor.Resume(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, success+failed)
+
+	require.NoError(t, db.Model(&model.Transfer{}).Where("id = ?", tr.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Minute)).Error)
+	success, failed, err = executor.Resume(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Zero(t, failed)
+
+	// 重新提交同一交易号，不会重复创建
+	assert.Equal(t, 1, server.count(pathSeqCreate))
+	assert.Equal(t, 2, server.count(pathSeqCommit))
+	tr = reload(t, db, tr.ID)
+	assert.Equal(t, model.TransferStatusPartial, tr.Status)
+	assert.Equal(t, int64(6000), tr.RemoteAmount)
+	assert.Contains(t, tr.ErrorMsg, "不一致")
+
+	var ledger model.Ledger
+	require.NoError(t, db.Where("transfer_id = ? AND direction = ?", tr.ID, model.LedgerDirectionIn).First(&ledger).Error)
+	assert.Equal(t, int64(6000), ledger.Amount)
+}
+
+func TestExecutor_Reconcile(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, _ int) (interface{}, int) {
+		if path == pathDetail {
+			return map[string]interface{}{"transfer_serial": "123", "transfer_amount": 10000, "transfer_status": "TRANSFER_SUCCESS"}, 0
+		}
+		return nil, 40002
+	}}
+	executor := NewExecutor(db, testConfig(), server.start(t), zap.NewNop())
+
+	// 尝试次数已达上限的转账单定时任务不再处理，手动对账仍会执行并计入尝试次数
+	tr := approvedTransfer(t, db, model.ChannelCustomerCenter, 10000)
+	require.NoError(t, db.Model(tr).Updates(map[string]interface{}{
+		"status": model.TransferStatusSubmitted, "transaction_seq": "123", "attempts": testConfig().MaxAttempts,
+	}).Error)
+	tr = reload(t, db, tr.ID)
+	require.NoError(t, executor.Reconcile(context.Background(), tr))
+
+	tr = reload(t, db, tr.ID)
+	assert.Equal(t, model.TransferStatusSucceeded, tr.Status)
+	assert.Equal(t, testConfig().MaxAttempts+1, tr.Attempts)
+
+	// 已完成的转账单不能再对账
+	assert.Equal(t, errcode.ErrTransferStatus, errCode(executor.Reconcile(context.Background(), tr)))
+}
+
+func TestExecutor_SharedWallet(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, calls int) (interface{}, int) {
+		switch path {
+		case pathWalletTrans:
+			if calls == 1 {
+				return nil, 40002 // 巨量明确拒绝
+			}
+			return nil, -1 // 发起未收到应答
+		case pathWalletInfo:
+			return map[string]interface{}{"transfer_serial": "w1", "transfer_amount": 10000, "transfer_status": "TRANSFER_SUCCESS"}, 0
+		}
+		return nil, 40002
+	}}
+	executor := NewExecutor(db, testConfig(), server.start(t), zap.NewNop())
+
+	rejected := approvedTransfer(t, db, model.ChannelSharedWallet, 10000)
+	require.Error(t, executor.Execute(context.Background(), rejected))
+	assert.Equal(t, model.TransferStatusFailed, reload(t, db, rejected.ID).Status)
+
+	// 发起结果未知时保持执行中，定时任务按幂等ID查询到转账单后对账，不重新发起
+	unknown := &model.Transfer{
+		IdempotencyKey: "wallet-2", Channel: model.ChannelSharedWallet, AccountID: 1001, SourceID: 9001, TargetID: 9002,
+		AdvertiserID: 1, Direction: "TRANSFER_IN", TransferType: "PREPAY_GENERAL", Amount: 10000,
+		Status: model.TransferStatusApproved, RequestedBy: 1,
+	}
+	require.NoError(t, db.Create(unknown).Error)
+	require.Error(t, executor.Execute(context.Background(), unknown))
+	unknown = reload(t, db, unknown.ID)
+	assert.Equal(t, model.TransferStatusExecuting, unknown.Status)
+	require.NotEmpty(t, unknown.TransactionSeq)
+	assert.Contains(t, server.body(pathWalletTrans), `"biz_request_no":"`+unknown.TransactionSeq+`"`)
+
+	require.NoError(t, db.Model(&model.Transfer{}).Where("id = ?", unknown.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Minute)).Error)
+	success, failed, err := executor.Resume(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Zero(t, failed)
+	assert.Equal(t, 2, server.count(pathWalletTrans))
+	assert.Contains(t, server.query(pathWalletInfo), "transfer_biz_request_no="+unknown.TransactionSeq)
+
+	unknown = reload(t, db, unknown.ID)
+	assert.Equal(t, model.TransferStatusSucceeded, unknown.Status)
+	assert.Equal(t, "w1", unknown.TransferSerial)
+	var count int64
+	db.Model(&model.Ledger{}).Where("transfer_id = ?", unknown.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestExecutor_SharedWalletResubmit(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, calls int) (interface{}, int) {
+		switch path {
+		case pathWalletTrans:
+			if calls == 1 {
+				return nil, -1
+			}
+			return map[string]interface{}{"transfer_serial": "w1"}, 0
+		case pathWalletInfo:
+			if calls == 1 {
+				return map[string]interface{}{"transfer_status": "NO_TRANSFER"}, 0
+			}
+			return map[string]interface{}{"transfer_serial": "w1", "transfer_amount": 10000, "transfer_status": "TRANSFER_SUCCESS"}, 0
+		}
+		return nil, 40002
+	}}
+	executor := NewExecutor(db, testConfig(), server.start(t), zap.NewNop())
+
+	tr := approvedTransfer(t, db, model.ChannelSharedWallet, 10000)
+	tr.Direction = "TRANSFER_IN"
+	require.Error(t, executor.Execute(context.Background(), tr))
+	tr = reload(t, db, tr.ID)
+	seq := tr.TransactionSeq
+
+	// 巨量未受理时以同一幂等ID重新发起
+	require.NoError(t, executor.Reconcile(context.Background(), tr))
+	assert.Equal(t, 2, server.count(pathWalletTrans))
+	assert.Contains(t, server.body(pathWalletTrans), `"biz_request_no":"`+seq+`"`)
+	assert.Equal(t, model.TransferStatusSucceeded, reload(t, db, tr.ID).Status)
+}
+
+func TestExecutor_NoRetry(t *testing.T) {
+	db := newTestDB(t)
+	server := &oceanServer{handle: func(path string, calls int) (interface{}, int) {
+		if path == pathWalletTrans {
+			switch calls {
+			case 1:
+				return nil, -2 // 转账已受理但应答丢失
+			case 2:
+				return nil, 51010 // 系统繁忙
+			}
+		}
+		return nil, 40002
+	}}
+	server.start(t)
+	sdk := NewExecutorSDK(&config.OceanConfig{AppID: "1", Secret: "secret", BaseURL: server.url + "/open_api", RetryCount: 3}, stubTokens{}, zap.NewNop())
+	executor := NewExecutor(db, testConfig(), sdk, zap.NewNop())
+
+	dropped := approvedTransfer(t, db, model.ChannelSharedWallet, 10000)
+	require.Error(t, executor.Execute(context.Background(), dropped))
+	assert.Equal(t, model.TransferStatusExecuting, reload(t, db, dropped.ID).Status)
+	assert.Equal(t, 1, server.count(pathWalletTrans))
+
+	busy := &model.Transfer{
+		IdempotencyKey: "wallet-busy", Channel: model.ChannelSharedWallet, AccountID: 1001, SourceID: 9001, TargetID: 9002,
+		AdvertiserID: 1, Direction: "TRANSFER_IN", TransferType: "PREPAY_GENERAL", Amount: 10000,
+		Status: model.TransferStatusApproved, RequestedBy: 1,
+	}
+	require.NoError(t, db.Create(busy).Error)
+	require.Error(t, executor.Execute(context.Background(), busy))
+	assert.Equal(t, model.TransferStatusExecuting, reload(t, db, busy.ID).Status)
+	assert.Equal(t, 2, server.count(pathWalletTrans))
+}
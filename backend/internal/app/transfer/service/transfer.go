@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/transfer/dto"
+	"oceanengine-backend/internal/app/transfer/model"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/pkg/errcode"
+)
+
+// TransferService 转账单服务
+// 转账单按金额匹配审批链，审批链中的角色依次审批通过后由 Executor 执行
+type TransferService struct {
+	db  *gorm.DB
+	cfg *config.TransferConfig
+}
+
+// NewTransferService 创建转账单服务
+func NewTransferService(db *gorm.DB, cfg *config.TransferConfig) *TransferService {
+	return &TransferService{db: db, cfg: cfg}
+}
+
+// Create 发起转账
+// 授权账户须为数据权限内的广告主；相同幂等键的转账单已存在时返回已有转账单，审批链为空时直接进入待执行状态
+func (s *TransferService) Create(ctx context.Context, userID uint64, req *dto.TransferCreateReq) (*dto.TransferResp, error) {
+	var existing model.Transfer
+	err := s.db.WithContext(ctx).Where("idempotency_key = ?", req.IdempotencyKey).First(&existing).Error
+	if err == nil {
+		return s.duplicate(ctx, &existing, req)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	advertiserID, err := s.checkAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Channel != model.ChannelSharedWallet {
+		// 转入方为本地广告主时同样需要数据权限
+		if err := s.checkTarget(ctx, req.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	chain := s.approvalChain(req.Amount)
+	t := &model.Transfer{
+		IdempotencyKey: req.IdempotencyKey,
+		Channel:        req.Channel,
+		AccountID:      req.AccountID,
+		SourceID:       req.SourceID,
+		TargetID:       req.TargetID,
+		AdvertiserID:   advertiserID,
+		TransferType:   req.TransferType,
+		Amount:         req.Amount,
+		Remark:         req.Remark,
+		Status:         model.TransferStatusPending,
+		ApprovalRoles:  strings.Join(chain, ","),
+		RequestedBy:    userID,
+	}
+	if req.Channel == model.ChannelSharedWallet {
+		t.Direction = req.Direction
+	}
+	if len(chain) == 0 {
+		t.Status = model.TransferStatusApproved
+	} else {
+		t.NextRole = chain[0]
+	}
+
+	if err := s.db.WithContext(ctx).Create(t).Error; err != nil {
+		// 并发提交相同幂等键时以先保存的转账单为准
+		if s.db.WithContext(ctx).Where("idempotency_key = ?", req.IdempotencyKey).First(&existing).Error == nil {
+			return s.duplicate(ctx, &existing, req)
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return s.toResp(ctx, t, false)
+}
+
+// duplicate 返回相同幂等键的已有转账单，转账参数不一致时视为幂等键冲突
+func (s *TransferService) duplicate(ctx context.Context, t *model.Transfer, req *dto.TransferCreateReq) (*dto.TransferResp, error) {
+	if t.Channel != req.Channel || t.AccountID != req.AccountID || t.SourceID != req.SourceID ||
+		t.TargetID != req.TargetID || t.Amount != req.Amount {
+		return nil, errcode.NewWithMessage(errcode.ErrInvalidParams, "幂等键已用于其他转账")
+	}
+	if err := datascope.Check(ctx, t.AdvertiserID); err != nil {
+		return nil, err
+	}
+	return s.toResp(ctx, t, true)
+}
+
+// checkAccount 校验授权账户已授权且在数据权限内，返回其本地ID
+func (s *TransferService) checkAccount(ctx context.Context, accountID uint64) (uint64, error) {
+	var adv advModel.Advertiser
+	if err := s.db.WithContext(ctx).Select("id").Where("advertiser_id = ?", accountID).First(&adv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errcode.NewWithMessage(errcode.ErrAdvertiserNotFound, "授权账户不存在")
+		}
+		return 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if err := datascope.Check(ctx, adv.ID); err != nil {
+		return 0, err
+	}
+	return adv.ID, nil
+}
+
+// checkTarget 转入方为本地广告主时校验数据权限，非本地广告主不校验
+func (s *TransferService) checkTarget(ctx context.Context, targetID uint64) error {
+	var adv advModel.Advertiser
+	err := s.db.WithContext(ctx).Select("id").Where("advertiser_id = ?", targetID).First(&adv).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return datascope.Check(ctx, adv.ID)
+}
+
+// approvalChain 返回 min_amount 不超过转账金额的最高一档审批规则的角色，amount 单位为分
+func (s *TransferService) approvalChain(amount int64) []string {
+	var (
+		matched *config.ApprovalRule
+		chain   []string
+	)
+	for i := range s.cfg.ApprovalRules {
+		rule := &s.cfg.ApprovalRules[i]
+		if rule.MinAmount*100 <= amount && (matched == nil || rule.MinAmount > matched.MinAmount) {
+			matched = rule
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+	for _, role := range matched.Roles {
+		if role = strings.TrimSpace(role); role != "" {
+			chain = append(chain, role)
+		}
+	}
+	return chain
+}
+
+// Approve 审批通过当前步骤
+// 当前用户角色须为待审批的角色，发起人与已审批过的用户不能审批；最后一步通过后转账单进入待执行状态
+func (s *TransferService) Approve(ctx context.Context, id, userID uint64, roleKey, comment string) (*dto.TransferResp, error) {
+	return s.review(ctx, id, userID, roleKey, comment, model.ApprovalActionApprove)
+}
+
+// Reject 驳回转账单
+func (s *TransferService) Reject(ctx context.Context, id, userID uint64, roleKey, comment string) (*dto.TransferResp, error) {
+	return s.review(ctx, id, userID, roleKey, comment, model.ApprovalActionReject)
+}
+
+// review 记录审批操作，按 status 与 approval_step 条件更新，避免同一步骤被重复审批
+func (s *TransferService) review(ctx context.Context, id, userID uint64, roleKey, comment, action string) (*dto.TransferResp, error) {
+	t, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != model.TransferStatusPending {
+		return nil, errcode.New(errcode.ErrTransferStatus)
+	}
+	if roleKey == "" || roleKey != t.NextRole {
+		return nil, errcode.New(errcode.ErrTransferApprover)
+	}
+	if userID == t.RequestedBy {
+		return nil, errcode.NewWithMessage(errcode.ErrTransferApprover, "不能审批自己发起的转账")
+	}
+	var reviewed int64
+	if err := s.db.WithContext(ctx).Model(&model.Approval{}).
+		Where("transfer_id = ? AND approver_id = ?", t.ID, userID).Count(&reviewed).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if reviewed > 0 {
+		return nil, errcode.NewWithMessage(errcode.ErrTransferApprover, "同一用户不能审批多个步骤")
+	}
+
+	chain := splitRoles(t.ApprovalRoles)
+	step := t.ApprovalStep
+	updates := map[string]interface{}{"approval_step": step + 1}
+	switch {
+	case action == model.ApprovalActionReject:
+		updates["status"] = model.TransferStatusRejected
+		updates["next_role"] = ""
+	case step+1 >= len(chain):
+		updates["status"] = model.TransferStatusApproved
+		updates["next_role"] = ""
+	default:
+		updates["next_role"] = chain[step+1]
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Transfer{}).
+			Where("id = ? AND status = ? AND approval_step = ?", t.ID, model.TransferStatusPending, step).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errcode.New(errcode.ErrTransferStatus)
+		}
+		return tx.Create(&model.Approval{
+			TransferID: t.ID,
+			Step:       step,
+			Role:       roleKey,
+			ApproverID: userID,
+			Action:     action,
+			Comment:    comment,
+		}).Error
+	})
+	if err != nil {
+		var appErr *errcode.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+
+	if t, err = s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.toResp(ctx, t, false)
+}
+
+// Cancel 发起人撤销待审批的转账单
+func (s *TransferService) Cancel(ctx context.Context, id, userID uint64) (*dto.TransferResp, error) {
+	t, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.RequestedBy != userID {
+		return nil, errcode.NewWithMessage(errcode.ErrPermissionDeny, "只能撤销自己发起的转账")
+	}
+	result := s.db.WithContext(ctx).Model(&model.Transfer{}).
+		Where("id = ? AND status = ?", t.ID, model.TransferStatusPending).
+		Updates(map[string]interface{}{"status": model.TransferStatusCancelled, "next_role": ""})
+	if result.Error != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errcode.New(errcode.ErrTransferStatus)
+	}
+	if t, err = s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.toResp(ctx, t, false)
+}
+
+// List 获取转账单列表
+func (s *TransferService) List(ctx context.Context, roleKey string, req *dto.TransferListReq) ([]*model.Transfer, int64, error) {
+	query := s.db.WithContext(ctx).Model(&model.Transfer{}).Scopes(datascope.ByAdvertiser(ctx))
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Channel != "" {
+		query = query.Where("channel = ?", req.Channel)
+	}
+	if req.AdvertiserID > 0 {
+		query = query.Where("advertiser_id = ?", req.AdvertiserID)
+	}
+	if req.PendingMine {
+		query = query.Where("status = ? AND next_role = ?", model.TransferStatusPending, roleKey)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	var list []*model.Transfer
+	if err := query.Order("id DESC").Offset(req.GetOffset()).Limit(req.GetLimit()).Find(&list).Error; err != nil {
+		return nil, 0, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return list, total, nil
+}
+
+// Get 获取转账单详情，包含审批记录与台账
+func (s *TransferService) Get(ctx context.Context, id uint64) (*dto.TransferResp, error) {
+	t, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResp(ctx, t, false)
+}
+
+// find 按数据权限获取转账单
+func (s *TransferService) find(ctx context.Context, id uint64) (*model.Transfer, error) {
+	var t model.Transfer
+	if err := s.db.WithContext(ctx).Scopes(datascope.ByAdvertiser(ctx)).First(&t, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrTransferNotFound)
+		}
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return &t, nil
+}
+
+func (s *TransferService) toResp(ctx context.Context, t *model.Transfer, duplicate bool) (*dto.TransferResp, error) {
+	resp := &dto.TransferResp{Transfer: t, ApprovalChain: splitRoles(t.ApprovalRoles), Duplicate: duplicate}
+	if err := s.db.WithContext(ctx).Where("transfer_id = ?", t.ID).Order("step ASC").Find(&resp.Approvals).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	if err := s.db.WithContext(ctx).Where("transfer_id = ?", t.ID).Order("id ASC").Find(&resp.Ledgers).Error; err != nil {
+		return nil, errcode.Wrap(errcode.ErrInternalServer, err)
+	}
+	return resp, nil
+}
+
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return []string{}
+	}
+	return strings.Split(roles, ",")
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"oceanengine-backend/config"
+	"oceanengine-backend/internal/oceanclient"
+	"oceanengine-backend/internal/utils"
 	"oceanengine-backend/pkg/oceanengine"
 	"oceanengine-backend/pkg/response"
 )
@@ -22,20 +24,13 @@ func NewV3Handler(db *gorm.DB, oceanCfg *config.OceanConfig) *V3Handler {
 	return &V3Handler{
 		db:       db,
 		oceanCfg: oceanCfg,
-		client:   oceanengine.NewClient(oceanCfg.AppID, oceanCfg.Secret),
+		client:   oceanclient.New(oceanCfg),
 	}
 }
 
-// getAccessToken 从请求中获取 access_token
+// getAccessToken 获取服务端解析的 access_token（由 OceanAccessToken 中间件按 advertiser_id 注入）
 func (h *V3Handler) getAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
-	}
-	if token == "" {
-		token = c.Query("access_token")
-	}
-	return token
+	return utils.GetAccessToken(c)
 }
 
 // getAdvertiserID 从请求中获取 advertiser_id
@@ -72,7 +67,7 @@ func (h *V3Handler) GetProjectList(c *gin.Context) {
 
 	list, total, err := h.client.V3().GetProjectList(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -100,7 +95,7 @@ func (h *V3Handler) GetProjectDetail(c *gin.Context) {
 
 	list, _, err := h.client.V3().GetProjectList(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -129,7 +124,7 @@ func (h *V3Handler) CreateProject(c *gin.Context) {
 
 	projectID, err := h.client.V3().CreateProject(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -156,7 +151,7 @@ func (h *V3Handler) UpdateProject(c *gin.Context) {
 
 	result, err := h.client.V3().UpdateProject(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -184,7 +179,7 @@ func (h *V3Handler) UpdateProjectStatus(c *gin.Context) {
 
 	result, err := h.client.V3().UpdateProjectStatus(c.Request.Context(), accessToken, req.AdvertiserID, req.ProjectIDs, req.OptStatus)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -211,7 +206,7 @@ func (h *V3Handler) DeleteProject(c *gin.Context) {
 
 	result, err := h.client.V3().DeleteProjects(c.Request.Context(), accessToken, req.AdvertiserID, req.ProjectIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -252,7 +247,7 @@ func (h *V3Handler) UpdateProjectBudget(c *gin.Context) {
 
 	result, err := h.client.V3().UpdateProjectBudget(c.Request.Context(), accessToken, req.AdvertiserID, data)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -286,7 +281,7 @@ func (h *V3Handler) GetPromotionList(c *gin.Context) {
 
 	list, total, err := h.client.V3().GetPromotionList(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -314,7 +309,7 @@ func (h *V3Handler) GetPromotionDetail(c *gin.Context) {
 
 	list, _, err := h.client.V3().GetPromotionList(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -343,7 +338,7 @@ func (h *V3Handler) CreatePromotion(c *gin.Context) {
 
 	promotionID, err := h.client.V3().CreatePromotion(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -370,7 +365,7 @@ func (h *V3Handler) UpdatePromotion(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotion(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -398,7 +393,7 @@ func (h *V3Handler) UpdatePromotionStatus(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionStatus(c.Request.Context(), accessToken, req.AdvertiserID, req.PromotionIDs, req.OptStatus)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -425,7 +420,7 @@ func (h *V3Handler) DeletePromotion(c *gin.Context) {
 
 	result, err := h.client.V3().DeletePromotions(c.Request.Context(), accessToken, req.AdvertiserID, req.PromotionIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -465,7 +460,7 @@ func (h *V3Handler) UpdatePromotionBudget(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionBudget(c.Request.Context(), accessToken, req.AdvertiserID, data)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -505,7 +500,7 @@ func (h *V3Handler) UpdatePromotionBid(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionBid(c.Request.Context(), accessToken, req.AdvertiserID, data)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -552,7 +547,7 @@ func (h *V3Handler) UpdatePromotionDeepBid(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionDeepBid(c.Request.Context(), accessToken, req.AdvertiserID, data)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -580,7 +575,7 @@ func (h *V3Handler) UpdatePromotionScheduleTime(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionScheduleTime(c.Request.Context(), accessToken, req.AdvertiserID, req.PromotionIDs, req.ScheduleTime)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -621,7 +616,7 @@ func (h *V3Handler) UpdatePromotionMaterialStatus(c *gin.Context) {
 
 	result, err := h.client.V3().UpdatePromotionMaterialStatus(c.Request.Context(), accessToken, req.AdvertiserID, data)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -646,7 +641,7 @@ func (h *V3Handler) GetPromotionRejectReason(c *gin.Context) {
 
 	list, err := h.client.V3().GetPromotionRejectReason(c.Request.Context(), accessToken, advertiserID, promotionIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -671,7 +666,7 @@ func (h *V3Handler) GetPromotionCostProtectStatus(c *gin.Context) {
 
 	list, err := h.client.V3().GetPromotionCostProtectStatus(c.Request.Context(), accessToken, advertiserID, promotionIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -694,7 +689,7 @@ func (h *V3Handler) GetBudgetGroupList(c *gin.Context) {
 
 	list, total, err := h.client.V3().GetBudgetGroupList(c.Request.Context(), accessToken, advertiserID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -724,7 +719,7 @@ func (h *V3Handler) CreateBudgetGroup(c *gin.Context) {
 
 	budgetGroupID, err := h.client.V3().CreateBudgetGroup(c.Request.Context(), accessToken, req.AdvertiserID, req.BudgetGroupName, req.Budget, req.BudgetMode, req.ProjectIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -755,7 +750,7 @@ func (h *V3Handler) UpdateBudgetGroup(c *gin.Context) {
 
 	resultID, err := h.client.V3().UpdateBudgetGroup(c.Request.Context(), accessToken, req.AdvertiserID, budgetGroupID, req.BudgetGroupName, req.Budget, req.ProjectIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -782,7 +777,7 @@ func (h *V3Handler) DeleteBudgetGroup(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().DeleteBudgetGroups(c.Request.Context(), accessToken, req.AdvertiserID, req.BudgetGroupIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -818,7 +813,7 @@ func (h *V3Handler) GetProjectReport(c *gin.Context) {
 
 	data, err := h.client.V3().GetProjectReport(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -849,7 +844,7 @@ func (h *V3Handler) GetPromotionReport(c *gin.Context) {
 
 	data, err := h.client.V3().GetPromotionReport(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -880,7 +875,7 @@ func (h *V3Handler) GetMaterialReport(c *gin.Context) {
 
 	data, err := h.client.V3().GetMaterialReport(c.Request.Context(), accessToken, req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -904,7 +899,7 @@ func (h *V3Handler) GetCustomReport(c *gin.Context) {
 
 	data, err := h.client.V3().GetCustomReport(c.Request.Context(), accessToken, &req)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -923,7 +918,7 @@ func (h *V3Handler) GetCustomReportConfig(c *gin.Context) {
 
 	list, err := h.client.V3().GetCustomConfigFields(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -953,7 +948,7 @@ func (h *V3Handler) SaveAutoGenerateConfig(c *gin.Context) {
 
 	configID, err := h.client.V3().CreateAutoGenerateConfig(c.Request.Context(), accessToken, req.AdvertiserID, req.PromotionID, req.Config)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -974,7 +969,7 @@ func (h *V3Handler) GetAutoGenerateConfig(c *gin.Context) {
 
 	config, err := h.client.V3().GetAutoGenerateConfig(c.Request.Context(), accessToken, advertiserID, promotionID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -995,7 +990,7 @@ func (h *V3Handler) GetBlueFlowPackages(c *gin.Context) {
 
 	list, err := h.client.V3().GetBlueFlowPackages(c.Request.Context(), accessToken, advertiserID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1016,7 +1011,7 @@ func (h *V3Handler) GetBlueFlowKeywords(c *gin.Context) {
 
 	list, err := h.client.V3().GetBlueFlowKeywords(c.Request.Context(), accessToken, advertiserID, promotionID)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1036,7 +1031,7 @@ func (h *V3Handler) GetSuggestKeywords(c *gin.Context) {
 
 	list, err := h.client.V3().GetSuggestKeywords(c.Request.Context(), accessToken, advertiserID, queryWord)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1061,7 +1056,7 @@ func (h *V3Handler) GetV3Keywords(c *gin.Context) {
 
 	list, total, err := h.client.V3().GetV3Keywords(c.Request.Context(), accessToken, advertiserID, promotionID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1089,7 +1084,7 @@ func (h *V3Handler) CreateV3Keywords(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().CreateV3Keywords(c.Request.Context(), accessToken, req.AdvertiserID, req.PromotionID, req.Keywords)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1119,7 +1114,7 @@ func (h *V3Handler) UpdateV3Keywords(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().UpdateV3Keywords(c.Request.Context(), accessToken, req.AdvertiserID, req.Keywords)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1149,7 +1144,7 @@ func (h *V3Handler) DeleteV3Keywords(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().DeleteV3Keywords(c.Request.Context(), accessToken, req.AdvertiserID, req.KeywordIDs)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1177,7 +1172,7 @@ func (h *V3Handler) GetV3PrivativeWords(c *gin.Context) {
 
 	list, total, err := h.client.V3().GetV3PrivativeWords(c.Request.Context(), accessToken, advertiserID, projectID, page, pageSize)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1205,7 +1200,7 @@ func (h *V3Handler) AddV3PrivativeWords(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().AddV3PrivativeWords(c.Request.Context(), accessToken, req.AdvertiserID, req.ProjectID, req.Words)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -1236,7 +1231,7 @@ func (h *V3Handler) UpdateV3PrivativeWords(c *gin.Context) {
 
 	successIDs, failIDs, err := h.client.V3().UpdateV3PrivativeWords(c.Request.Context(), accessToken, req.AdvertiserID, req.ProjectID, req.Words)
 	if err != nil {
-		response.InternalError(c, err.Error())
+		response.UpstreamError(c, err)
 		return
 	}
 
@@ -0,0 +1,115 @@
+package datascope
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"oceanengine-backend/pkg/errcode"
+)
+
+// ErrOutOfScope 访问了数据权限范围外的广告主数据
+var ErrOutOfScope = errcode.NewWithMessage(errcode.ErrPermissionDeny, "无权访问该广告主数据")
+
+// Filter 当前用户的广告主数据权限
+// AdvertiserIDs 为可访问的 ad_advertiser.id，业务表的 advertiser_id 字段同样存储该 ID
+type Filter struct {
+	All           bool
+	AdvertiserIDs []uint64
+
+	allowed map[uint64]struct{}
+}
+
+// Unrestricted 不限制数据权限
+func Unrestricted() *Filter {
+	return &Filter{All: true}
+}
+
+// NewFilter 创建仅允许指定广告主的数据权限
+func NewFilter(advertiserIDs []uint64) *Filter {
+	f := &Filter{
+		AdvertiserIDs: make([]uint64, 0, len(advertiserIDs)),
+		allowed:       make(map[uint64]struct{}, len(advertiserIDs)),
+	}
+	for _, id := range advertiserIDs {
+		if _, ok := f.allowed[id]; ok {
+			continue
+		}
+		f.allowed[id] = struct{}{}
+		f.AdvertiserIDs = append(f.AdvertiserIDs, id)
+	}
+	return f
+}
+
+// Allows 是否允许访问指定广告主
+func (f *Filter) Allows(advertiserID uint64) bool {
+	if f == nil || f.All {
+		return true
+	}
+	_, ok := f.allowed[advertiserID]
+	return ok
+}
+
+type ctxKey struct{}
+
+// WithFilter 将数据权限写入 context
+func WithFilter(ctx context.Context, f *Filter) context.Context {
+	return context.WithValue(ctx, ctxKey{}, f)
+}
+
+// FromContext 读取 context 中的数据权限
+// 未设置时（定时任务、OAuth 回调等非用户请求）返回 false，调用方按不限制处理
+func FromContext(ctx context.Context) (*Filter, bool) {
+	f, ok := ctx.Value(ctxKey{}).(*Filter)
+	return f, ok && f != nil
+}
+
+// Check 校验是否可访问指定广告主（ad_advertiser.id）
+func Check(ctx context.Context, advertiserID uint64) error {
+	if f, ok := FromContext(ctx); ok && !f.Allows(advertiserID) {
+		return ErrOutOfScope
+	}
+	return nil
+}
+
+// Scope 按数据权限过滤的 GORM Scope，column 为存储 ad_advertiser.id 的字段
+func Scope(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		f, ok := FromContext(ctx)
+		if !ok || f.All {
+			return db
+		}
+		if len(f.AdvertiserIDs) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where(column+" IN ?", f.AdvertiserIDs)
+	}
+}
+
+// ByAdvertiser 按业务表 advertiser_id 字段过滤
+func ByAdvertiser(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return Scope(ctx, "advertiser_id")
+}
+
+// CheckIDs 校验批量操作的记录均在数据权限内，ids 中存在权限外或不存在的记录时返回 ErrOutOfScope
+// model 为业务表模型，业务表需包含存储 ad_advertiser.id 的 advertiser_id 字段
+func CheckIDs(ctx context.Context, db *gorm.DB, model interface{}, ids []uint64) error {
+	f, ok := FromContext(ctx)
+	if !ok || f.All {
+		return nil
+	}
+	unique := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	var count int64
+	if err := db.WithContext(ctx).Model(model).
+		Scopes(ByAdvertiser(ctx)).
+		Where("id IN ?", ids).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return ErrOutOfScope
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package datascope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	adminModel "oceanengine-backend/internal/app/admin/model"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/pkg/auth"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&adminModel.User{},
+		&adminModel.UserAdvertiser{},
+		&adminModel.RoleAdvertiser{},
+		&advModel.Advertiser{},
+	))
+	return db
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, Check(ctx, 1), "未设置数据权限时不限制")
+
+	assert.NoError(t, Check(WithFilter(ctx, Unrestricted()), 1))
+
+	scoped := WithFilter(ctx, NewFilter([]uint64{1, 2, 2}))
+	assert.NoError(t, Check(scoped, 2))
+	assert.ErrorIs(t, Check(scoped, 3), ErrOutOfScope)
+}
+
+func TestScope(t *testing.T) {
+	db := newTestDB(t)
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, db.Create(&advModel.Advertiser{AdvertiserID: 100 + i, Name: "adv"}).Error)
+	}
+
+	count := func(ctx context.Context) int64 {
+		var n int64
+		require.NoError(t, db.Model(&advModel.Advertiser{}).Scopes(Scope(ctx, "id")).Count(&n).Error)
+		return n
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, int64(3), count(ctx))
+	assert.Equal(t, int64(3), count(WithFilter(ctx, Unrestricted())))
+	assert.Equal(t, int64(2), count(WithFilter(ctx, NewFilter([]uint64{1, 3}))))
+	assert.Equal(t, int64(0), count(WithFilter(ctx, NewFilter(nil))))
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	db := newTestDB(t)
+	users := []adminModel.User{
+		{Username: "u1", Password: "x", DeptID: 10},
+		{Username: "u2", Password: "x", DeptID: 10},
+		{Username: "u3", Password: "x"},
+	}
+	require.NoError(t, db.Create(&users).Error)
+	require.NoError(t, db.Create(&[]adminModel.UserAdvertiser{
+		{UserID: users[0].ID, AdvertiserID: 1},
+		{UserID: users[1].ID, AdvertiserID: 2},
+		{UserID: users[2].ID, AdvertiserID: 3},
+	}).Error)
+	require.NoError(t, db.Create(&adminModel.RoleAdvertiser{RoleID: 7, AdvertiserID: 5}).Error)
+	require.NoError(t, db.Create(&advModel.Advertiser{AdvertiserID: 900, Name: "own", CreatedBy: users[2].ID}).Error)
+
+	r := NewResolver(db)
+	ctx := context.Background()
+	resolve := func(userID uint64, roleID int64, scope string) *Filter {
+		f, err := r.Resolve(ctx, &auth.Claims{UserID: int64(userID), RoleID: roleID, DataScope: scope})
+		require.NoError(t, err)
+		return f
+	}
+
+	assert.True(t, resolve(users[0].ID, 1, "1").All)
+	assert.ElementsMatch(t, []uint64{5}, resolve(users[0].ID, 7, "2").AdvertiserIDs)
+	assert.ElementsMatch(t, []uint64{1, 2}, resolve(users[0].ID, 1, "3").AdvertiserIDs)
+	assert.ElementsMatch(t, []uint64{1}, resolve(users[0].ID, 1, "5").AdvertiserIDs)
+
+	// 未分配部门的用户按仅本人处理，包含本人创建的广告主
+	self := resolve(users[2].ID, 1, "4")
+	assert.False(t, self.All)
+	assert.True(t, self.Allows(3))
+	assert.Len(t, self.AdvertiserIDs, 2)
+}
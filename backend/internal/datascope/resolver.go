@@ -0,0 +1,78 @@
+package datascope
+
+import (
+	"context"
+	"strconv"
+
+	"gorm.io/gorm"
+	adminModel "oceanengine-backend/internal/app/admin/model"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/pkg/auth"
+)
+
+// Resolver 根据登录用户的角色数据权限计算可访问的广告主
+type Resolver struct {
+	db *gorm.DB
+}
+
+// NewResolver 创建数据权限解析器
+func NewResolver(db *gorm.DB) *Resolver {
+	return &Resolver{db: db}
+}
+
+// Resolve 解析用户数据权限
+//
+//	1-全部：不限制
+//	2-自定义：角色分配的广告主 (sys_role_advertiser)
+//	3-本部门 / 4-本部门及以下：同部门用户分配的广告主（暂无部门树，4 按本部门处理）
+//	5-仅本人：分配给本人及本人创建的广告主
+//
+// 未配置或无法识别的数据权限按仅本人处理
+func (r *Resolver) Resolve(ctx context.Context, claims *auth.Claims) (*Filter, error) {
+	scope, _ := strconv.Atoi(claims.DataScope)
+	userID := uint64(claims.UserID)
+	db := r.db.WithContext(ctx)
+
+	var ids []uint64
+	switch scope {
+	case adminModel.DataScopeAll:
+		return Unrestricted(), nil
+
+	case adminModel.DataScopeCustom:
+		if err := db.Model(&adminModel.RoleAdvertiser{}).
+			Where("role_id = ?", claims.RoleID).
+			Pluck("advertiser_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		return NewFilter(ids), nil
+
+	case adminModel.DataScopeDept, adminModel.DataScopeDeptAndChild:
+		var user adminModel.User
+		if err := db.Select("id", "dept_id").First(&user, userID).Error; err != nil {
+			return nil, err
+		}
+		if user.DeptID > 0 {
+			deptUsers := db.Model(&adminModel.User{}).Select("id").Where("dept_id = ?", user.DeptID)
+			if err := db.Model(&adminModel.UserAdvertiser{}).
+				Where("user_id IN (?)", deptUsers).
+				Pluck("advertiser_id", &ids).Error; err != nil {
+				return nil, err
+			}
+			return NewFilter(ids), nil
+		}
+	}
+
+	// 仅本人
+	if err := db.Model(&adminModel.UserAdvertiser{}).
+		Where("user_id = ?", userID).
+		Pluck("advertiser_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	var created []uint64
+	if err := db.Model(&advModel.Advertiser{}).
+		Where("created_by = ?", userID).
+		Pluck("id", &created).Error; err != nil {
+		return nil, err
+	}
+	return NewFilter(append(ids, created...)), nil
+}
@@ -0,0 +1,126 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"oceanengine-backend/config"
+)
+
+const (
+	// DefaultWorkers 默认并发数
+	DefaultWorkers = 8
+	// DefaultTimeout 默认单个广告主执行超时
+	DefaultTimeout = 2 * time.Minute
+	// maxFailures 汇总中保留的失败明细上限
+	maxFailures = 50
+)
+
+// Func 针对单个广告主的执行函数
+type Func func(ctx context.Context, advertiserID uint64) error
+
+// Failure 单个广告主的失败明细
+type Failure struct {
+	AdvertiserID uint64
+	Err          error
+}
+
+// Summary 执行汇总
+type Summary struct {
+	Total    int
+	Success  int
+	Failed   int
+	Failures []Failure // 最多保留 maxFailures 条
+	Duration time.Duration
+}
+
+// Executor 按广告主并发执行器
+//
+// 以固定数量的 worker 并发处理广告主列表，每个广告主独立超时，单个广告主失败或 panic 不影响其他广告主。
+// 开放平台的 QPS 由客户端的接口限流器控制（见 oceanengine.WithRateLimiter），并发数只决定同时在途的请求数。
+type Executor struct {
+	workers int
+	timeout time.Duration
+}
+
+// New 创建执行器，cfg 为 nil 或字段为零值时使用默认值
+func New(cfg *config.FanoutConfig) *Executor {
+	e := &Executor{workers: DefaultWorkers, timeout: DefaultTimeout}
+	if cfg != nil {
+		if cfg.Workers > 0 {
+			e.workers = cfg.Workers
+		}
+		if cfg.Timeout > 0 {
+			e.timeout = cfg.Timeout
+		}
+	}
+	return e
+}
+
+// Run 对每个广告主执行 fn 并汇总结果
+// ctx 取消后未开始的广告主记为失败
+func (e *Executor) Run(ctx context.Context, advertiserIDs []uint64, fn Func) *Summary {
+	start := time.Now()
+	summary := &Summary{Total: len(advertiserIDs)}
+	if len(advertiserIDs) == 0 {
+		return summary
+	}
+
+	workers := e.workers
+	if workers > len(advertiserIDs) {
+		workers = len(advertiserIDs)
+	}
+
+	var mu sync.Mutex
+	record := func(advertiserID uint64, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			summary.Success++
+			return
+		}
+		summary.Failed++
+		if len(summary.Failures) < maxFailures {
+			summary.Failures = append(summary.Failures, Failure{AdvertiserID: advertiserID, Err: err})
+		}
+	}
+
+	ids := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if err := ctx.Err(); err != nil {
+					record(id, err)
+					continue
+				}
+				record(id, e.runOne(ctx, id, fn))
+			}
+		}()
+	}
+	for _, id := range advertiserIDs {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+
+	summary.Duration = time.Since(start)
+	return summary
+}
+
+// runOne 带超时执行单个广告主并捕获 panic
+func (e *Executor) runOne(ctx context.Context, advertiserID uint64, fn Func) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(ctx, advertiserID)
+}
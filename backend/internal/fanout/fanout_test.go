@@ -0,0 +1,87 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oceanengine-backend/config"
+)
+
+func TestExecutor_Run(t *testing.T) {
+	e := New(&config.FanoutConfig{Workers: 4, Timeout: 50 * time.Millisecond})
+
+	var inflight, peak int32
+	ids := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	summary := e.Run(context.Background(), ids, func(ctx context.Context, id uint64) error {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		switch id {
+		case 3:
+			return errors.New("api error")
+		case 5:
+			panic("boom")
+		case 7:
+			// 超时由执行器控制
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	assert.Equal(t, 10, summary.Total)
+	assert.Equal(t, 7, summary.Success)
+	assert.Equal(t, 3, summary.Failed)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(4))
+
+	failed := map[uint64]error{}
+	for _, f := range summary.Failures {
+		failed[f.AdvertiserID] = f.Err
+	}
+	require.Len(t, failed, 3)
+	assert.EqualError(t, failed[3], "api error")
+	assert.Contains(t, failed[5].Error(), "panic: boom")
+	assert.ErrorIs(t, failed[7], context.DeadlineExceeded)
+}
+
+func TestExecutor_Canceled(t *testing.T) {
+	e := New(&config.FanoutConfig{Workers: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int32
+	summary := e.Run(ctx, []uint64{1, 2, 3}, func(ctx context.Context, id uint64) error {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		return nil
+	})
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, summary.Success)
+	assert.Equal(t, 2, summary.Failed)
+	assert.ErrorIs(t, summary.Failures[0].Err, context.Canceled)
+}
+
+func TestExecutor_FailuresCapped(t *testing.T) {
+	ids := make([]uint64, maxFailures+10)
+	for i := range ids {
+		ids[i] = uint64(i + 1)
+	}
+	summary := New(nil).Run(context.Background(), ids, func(ctx context.Context, id uint64) error {
+		return errors.New("failed")
+	})
+
+	assert.Equal(t, len(ids), summary.Failed)
+	assert.Len(t, summary.Failures, maxFailures)
+}
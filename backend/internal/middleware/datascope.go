@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/pkg/auth"
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/response"
+)
+
+// DataScopeResolver 数据权限解析器
+type DataScopeResolver interface {
+	Resolve(ctx context.Context, claims *auth.Claims) (*datascope.Filter, error)
+}
+
+// DataScope 数据权限中间件（需在 JWTAuth 之后）
+// 解析当前用户可访问的广告主并写入 request context，仓储层据此过滤查询
+func DataScope(resolver DataScopeResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := GetClaims(c)
+		if claims == nil {
+			c.Next()
+			return
+		}
+
+		filter, err := resolver.Resolve(c.Request.Context(), claims)
+		if err != nil {
+			response.Error(c, errcode.Wrap(errcode.ErrInternalServer, err))
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(datascope.WithFilter(c.Request.Context(), filter))
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"oceanengine-backend/internal/utils"
+	"oceanengine-backend/pkg/response"
+)
+
+// AccessTokenResolver 广告主 Access Token 解析器
+type AccessTokenResolver interface {
+	GetAccessToken(ctx context.Context, advertiserID uint64) (string, error)
+}
+
+// OceanAccessToken Ocean Engine Access Token 注入中间件
+// 根据请求中的 advertiser_id 在服务端解析 Token 并写入上下文，处理器通过 utils.GetAccessToken 读取；
+// 请求未携带 advertiser_id 时直接放行，由处理器返回参数错误；query 与请求体中的 advertiser_id 不一致时返回 400，
+// 避免使用一个广告主的 Token 操作另一个广告主
+func OceanAccessToken(resolver AccessTokenResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		advertiserID, err := utils.LookupAdvertiserID(c)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			c.Abort()
+			return
+		}
+		if advertiserID == 0 {
+			c.Next()
+			return
+		}
+
+		token, err := resolver.GetAccessToken(c.Request.Context(), advertiserID)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		utils.SetAccessToken(c, token)
+		c.Next()
+	}
+}
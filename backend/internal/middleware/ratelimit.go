@@ -10,8 +10,9 @@ import (
 
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
-	Rate  rate.Limit // 每秒请求数
-	Burst int        // 突发请求数
+	Rate      rate.Limit // 每秒请求数
+	Burst     int        // 突发请求数
+	SkipPaths []string   // 不限流的路由，如巨量服务端请求的监测链接
 }
 
 // IPRateLimiter IP限流器
@@ -53,7 +54,15 @@ func (l *IPRateLimiter) getLimiter(ip string) *rate.Limiter {
 
 // RateLimit 限流中间件
 func RateLimit(limiter *IPRateLimiter) gin.HandlerFunc {
+	skip := make(map[string]bool, len(limiter.config.SkipPaths))
+	for _, path := range limiter.config.SkipPaths {
+		skip[path] = true
+	}
 	return func(c *gin.Context) {
+		if skip[c.FullPath()] {
+			c.Next()
+			return
+		}
 		ip := c.ClientIP()
 		l := limiter.getLimiter(ip)
 
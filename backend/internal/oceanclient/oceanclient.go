@@ -0,0 +1,72 @@
+package oceanclient
+
+import (
+	"net/http"
+	"strconv"
+
+	"oceanengine-backend/config"
+	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+// New 根据巨量广告配置创建 oceanengine 客户端，opts 覆盖配置项
+func New(cfg *config.OceanConfig, opts ...oceanengine.Option) *oceanengine.Client {
+	base := []oceanengine.Option{
+		oceanengine.WithBaseURL(cfg.BaseURL),
+		oceanengine.WithAuthURL(cfg.AuthURL),
+		oceanengine.WithTimeout(cfg.Timeout),
+		oceanengine.WithSandbox(cfg.Sandbox),
+	}
+	if cfg.RetryCount > 0 {
+		base = append(base, oceanengine.WithRetryCount(cfg.RetryCount))
+	}
+	if limiter := rateLimiter(cfg); limiter != nil {
+		base = append(base, oceanengine.WithRateLimiter(limiter))
+	}
+	return oceanengine.NewClient(cfg.AppID, cfg.Secret, append(base, opts...)...)
+}
+
+// NewQianchuan 根据千川配置创建 oceanengine 客户端
+func NewQianchuan(cfg *config.QianchuanConfig, opts ...oceanengine.Option) *oceanengine.Client {
+	base := []oceanengine.Option{
+		oceanengine.WithBaseURL(cfg.BaseURL),
+		oceanengine.WithAuthURL(cfg.AuthURL),
+		oceanengine.WithTimeout(cfg.Timeout),
+		oceanengine.WithSandbox(cfg.Sandbox),
+	}
+	if cfg.RetryCount > 0 {
+		base = append(base, oceanengine.WithRetryCount(cfg.RetryCount))
+	}
+	return oceanengine.NewClient(cfg.AppID, cfg.Secret, append(base, opts...)...)
+}
+
+// NewSDK 根据巨量广告配置创建 SDK 适配客户端，与 New 创建的客户端共用应用限流器
+func NewSDK(cfg *config.OceanConfig, opts ...oceansdk.Option) *oceansdk.Client {
+	appID, _ := strconv.ParseUint(cfg.AppID, 10, 64)
+	base := []oceansdk.Option{
+		oceansdk.WithBaseURL(cfg.BaseURL),
+		oceansdk.WithSandbox(cfg.Sandbox),
+	}
+	if cfg.Timeout > 0 {
+		base = append(base, oceansdk.WithHTTPClient(&http.Client{Timeout: cfg.Timeout}))
+	}
+	if cfg.RetryCount > 0 {
+		base = append(base, oceansdk.WithRetryCount(cfg.RetryCount))
+	}
+	if limiter := rateLimiter(cfg); limiter != nil {
+		base = append(base, oceansdk.WithRateLimiter(limiter))
+	}
+	return oceansdk.NewClient(appID, cfg.Secret, append(base, opts...)...)
+}
+
+// rateLimiter 返回应用共享的限流器，未配置 QPS 时不限流
+func rateLimiter(cfg *config.OceanConfig) *oceanengine.RateLimiter {
+	if cfg.RateLimit.QPS <= 0 {
+		return nil
+	}
+	endpoints := make(map[string]oceanengine.EndpointLimit, len(cfg.RateLimit.Endpoints))
+	for path, limit := range cfg.RateLimit.Endpoints {
+		endpoints[path] = oceanengine.EndpointLimit{QPS: limit.QPS, Burst: limit.Burst}
+	}
+	return oceanengine.SharedRateLimiter(cfg.AppID, oceanengine.EndpointLimit{QPS: cfg.RateLimit.QPS, Burst: cfg.RateLimit.Burst}, endpoints)
+}
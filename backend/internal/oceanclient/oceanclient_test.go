@@ -0,0 +1,46 @@
+package oceanclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oceanengine-backend/config"
+)
+
+func TestNew(t *testing.T) {
+	var gotPath, gotToken, gotDebug string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotToken = r.Header.Get("Access-Token")
+		gotDebug = r.Header.Get("X-Debug-Mode")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"message":"OK"}`))
+	}))
+	defer server.Close()
+
+	client := New(&config.OceanConfig{
+		AppID:   "app",
+		Secret:  "secret",
+		BaseURL: server.URL + "/open_api",
+		Timeout: 3 * time.Second,
+		Sandbox: true,
+	})
+
+	err := client.GetWithToken(context.Background(), "token", "/2/advertiser/info/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/open_api/2/advertiser/info/", gotPath)
+	assert.Equal(t, "token", gotToken)
+	assert.Equal(t, "1", gotDebug)
+}
+
+func TestRateLimiter(t *testing.T) {
+	assert.Nil(t, rateLimiter(&config.OceanConfig{AppID: "no-limit"}))
+
+	cfg := &config.OceanConfig{AppID: "limited", RateLimit: config.RateLimitConfig{QPS: 5, Burst: 5}}
+	assert.Same(t, rateLimiter(cfg), rateLimiter(cfg))
+}
@@ -0,0 +1,65 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxErrorLength 错误信息最大长度（与各表 error_msg 字段一致）
+const MaxErrorLength = 500
+
+// Claim 通过 attempts 条件更新领取记录：attempts 与读取时一致且满足 query 的条件时加一，同时写入 updates
+// query 为已指定 Model 及领取条件 (如状态) 的查询；记录正被其他实例处理时返回 false
+func Claim(query *gorm.DB, id uint64, attempts int, updates map[string]interface{}) (bool, error) {
+	fields := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"updated_at": time.Now(),
+	}
+	for k, v := range updates {
+		fields[k] = v
+	}
+	result := query.Where("id = ? AND attempts = ?", id, attempts).Updates(fields)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Record 写入处理结果，使用独立 context，处理超时后仍需记录状态
+func Record(db *gorm.DB, table interface{}, id uint64, updates map[string]interface{}) error {
+	return db.WithContext(context.Background()).Model(table).Where("id = ?", id).Updates(updates).Error
+}
+
+// Retry 重新处理超过处理超时仍未完成、尝试次数未达上限的记录，返回成功与失败数
+// query 为已指定可重试条件 (如状态) 的查询，记录按 ID 顺序交给 process 处理；ctx 取消后不再处理剩余记录
+func Retry[T any](ctx context.Context, query *gorm.DB, maxAttempts int, timeout time.Duration, limit int, process func(context.Context, *T) error) (success, failed int, err error) {
+	var list []*T
+	if err := query.WithContext(ctx).
+		Where("attempts < ?", maxAttempts).
+		Where("updated_at < ?", time.Now().Add(-timeout)).
+		Order("id ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return 0, 0, err
+	}
+
+	for _, item := range list {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := process(ctx, item); err != nil {
+			failed++
+			continue
+		}
+		success++
+	}
+	return success, failed, nil
+}
+
+// ErrorMessage 错误信息，超出 MaxErrorLength 时截断
+func ErrorMessage(msg string) string {
+	if len(msg) > MaxErrorLength {
+		msg = strings.ToValidUTF8(msg[:MaxErrorLength], "")
+	}
+	return msg
+}
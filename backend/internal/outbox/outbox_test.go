@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type job struct {
+	ID        uint64 `gorm:"primaryKey"`
+	Status    string
+	Attempts  int
+	ErrorMsg  string
+	UpdatedAt time.Time
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&job{}))
+	return db
+}
+
+func TestClaim(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&job{ID: 1, Status: "PENDING"}).Error)
+	query := func() *gorm.DB {
+		return db.WithContext(context.Background()).Model(&job{}).Where("status IN ?", []string{"PENDING", "FAILED"})
+	}
+
+	claimed, err := Claim(query(), 1, 0, map[string]interface{}{"status": "RUNNING"})
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	// attempts 已变化或状态不满足条件时不能重复领取
+	claimed, err = Claim(query(), 1, 0, nil)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	claimed, err = Claim(query(), 1, 1, nil)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	var got job
+	require.NoError(t, db.First(&got, 1).Error)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "RUNNING", got.Status)
+}
+
+func TestRecord(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&job{ID: 1, Status: "RUNNING"}).Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, Record(db.WithContext(ctx), &job{}, 1, map[string]interface{}{"status": "FAILED"}))
+
+	var got job
+	require.NoError(t, db.First(&got, 1).Error)
+	assert.Equal(t, "FAILED", got.Status)
+}
+
+func TestRetry(t *testing.T) {
+	db := newTestDB(t)
+	stale := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Create(&[]job{
+		{ID: 1, Status: "FAILED", UpdatedAt: stale},
+		{ID: 2, Status: "FAILED", Attempts: 3, UpdatedAt: stale},
+		{ID: 3, Status: "DONE", UpdatedAt: stale},
+		{ID: 4, Status: "PENDING", UpdatedAt: stale},
+	}).Error)
+	require.NoError(t, db.Create(&job{ID: 5, Status: "PENDING"}).Error)
+
+	var processed []uint64
+	success, failed, err := Retry(context.Background(), db.Model(&job{}).Where("status IN ?", []string{"PENDING", "FAILED"}),
+		3, time.Minute, 10, func(_ context.Context, j *job) error {
+			processed = append(processed, j.ID)
+			if j.ID == 4 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1, success)
+	assert.Equal(t, 1, failed)
+	// 达到最大尝试次数、状态不满足或未超时的记录不处理
+	assert.Equal(t, []uint64{1, 4}, processed)
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.Equal(t, "failed", ErrorMessage("failed"))
+
+	msg := ErrorMessage("a" + strings.Repeat("错", MaxErrorLength))
+	assert.LessOrEqual(t, len(msg), MaxErrorLength)
+	assert.True(t, utf8.ValidString(msg))
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,7 @@ import (
 	adminApi "oceanengine-backend/internal/app/admin/api"
 	"oceanengine-backend/internal/app/admin/service"
 	advApi "oceanengine-backend/internal/app/advertiser/api"
+	advService "oceanengine-backend/internal/app/advertiser/service"
 	advtoolsApi "oceanengine-backend/internal/app/advtools/api"
 	audienceApi "oceanengine-backend/internal/app/audience/api"
 	audienceService "oceanengine-backend/internal/app/audience/service"
@@ -18,6 +20,7 @@ import (
 	clueApi "oceanengine-backend/internal/app/clue/api"
 	creativeApi "oceanengine-backend/internal/app/creative/api"
 	dmpApi "oceanengine-backend/internal/app/dmp/api"
+	dmpService "oceanengine-backend/internal/app/dmp/service"
 	dpaApi "oceanengine-backend/internal/app/dpa/api"
 	enterpriseApi "oceanengine-backend/internal/app/enterprise/api"
 	eventmanagerApi "oceanengine-backend/internal/app/eventmanager/api"
@@ -26,12 +29,26 @@ import (
 	mediaService "oceanengine-backend/internal/app/media/service"
 	qianchuanApi "oceanengine-backend/internal/app/qianchuan/api"
 	reportApi "oceanengine-backend/internal/app/report/api"
+	reportService "oceanengine-backend/internal/app/report/service"
 	serveMarketApi "oceanengine-backend/internal/app/servemarket/api"
 	siteApi "oceanengine-backend/internal/app/site/api"
+	spiApi "oceanengine-backend/internal/app/spi/api"
+	spiService "oceanengine-backend/internal/app/spi/service"
 	starApi "oceanengine-backend/internal/app/star/api"
+	trackingApi "oceanengine-backend/internal/app/tracking/api"
+	trackingService "oceanengine-backend/internal/app/tracking/service"
+	transferApi "oceanengine-backend/internal/app/transfer/api"
+	transferService "oceanengine-backend/internal/app/transfer/service"
 	v3Api "oceanengine-backend/internal/app/v3/api"
+	"oceanengine-backend/internal/datascope"
+	"oceanengine-backend/internal/fanout"
 	"oceanengine-backend/internal/middleware"
+	"oceanengine-backend/internal/oceanclient"
 	"oceanengine-backend/pkg/auth"
+	"oceanengine-backend/pkg/cache"
+	"oceanengine-backend/pkg/database"
+	"oceanengine-backend/pkg/oceansdk"
+	"oceanengine-backend/pkg/storage"
 )
 
 // Router 路由管理器
@@ -42,6 +59,15 @@ type Router struct {
 	jwtManager   *auth.JWTManager
 	oceanCfg     *config.OceanConfig
 	qianchuanCfg *config.QianchuanConfig
+	tokenService *advService.TokenService
+	exportFiles  *reportService.ExportFiles
+	fanout       *fanout.Executor
+	spiCfg       *config.SPIConfig
+	materials    storage.Storage
+	materialCfg  *config.MaterialConfig
+	trackingCfg  *config.TrackingConfig
+	clicks       *trackingService.ClickRecorder
+	transferCfg  *config.TransferConfig
 }
 
 // NewRouter 创建路由
@@ -65,6 +91,50 @@ func NewRouterWithQianchuan(db *gorm.DB, logger *zap.Logger, jwtManager *auth.JW
 	}
 }
 
+// SetFanout 设置按广告主并发执行器（用于批量同步接口），未设置时使用默认配置
+func (r *Router) SetFanout(executor *fanout.Executor) *Router {
+	r.fanout = executor
+	return r
+}
+
+// SetSPI 设置订阅推送配置，未设置时不注册推送回调路由
+func (r *Router) SetSPI(cfg *config.SPIConfig) *Router {
+	r.spiCfg = cfg
+	return r
+}
+
+// SetExportFiles 设置报表导出文件存储（用于生成与校验下载链接）
+func (r *Router) SetExportFiles(files *reportService.ExportFiles) *Router {
+	r.exportFiles = files
+	return r
+}
+
+// SetMaterials 设置素材原始文件存储与同步配置（需与定时任务服务共享存储目录），未设置时不支持上传素材
+func (r *Router) SetMaterials(st storage.Storage, cfg *config.MaterialConfig) *Router {
+	r.materials = st
+	r.materialCfg = cfg
+	return r
+}
+
+// SetTracking 设置监测链接与转化回传配置，未设置时不注册点击接收与转化上报路由
+func (r *Router) SetTracking(cfg *config.TrackingConfig) *Router {
+	r.trackingCfg = cfg
+	return r
+}
+
+// SetTransfer 设置资金转账配置，未设置时不注册转账路由
+func (r *Router) SetTransfer(cfg *config.TransferConfig) *Router {
+	r.transferCfg = cfg
+	return r
+}
+
+// Close 停止路由使用的后台任务，写入队列中尚未保存的点击
+func (r *Router) Close() {
+	if r.clicks != nil {
+		r.clicks.Close()
+	}
+}
+
 // Setup 设置路由
 func (r *Router) Setup(mode string) *gin.Engine {
 	// 设置 Gin 模式
@@ -82,21 +152,31 @@ func (r *Router) Setup(mode string) *gin.Engine {
 	r.engine.Use(middleware.CORS(middleware.DefaultCORSConfig()))
 
 	// 限流中间件
-	rateLimiter := middleware.NewIPRateLimiter(middleware.DefaultRateLimitConfig())
+	rateLimitCfg := middleware.DefaultRateLimitConfig()
+	if r.trackingCfg != nil {
+		// 点击由巨量服务端集中请求，不按 IP 限流
+		rateLimitCfg.SkipPaths = append(rateLimitCfg.SkipPaths, "/api/v1/track/click")
+	}
+	rateLimiter := middleware.NewIPRateLimiter(rateLimitCfg)
 	r.engine.Use(middleware.RateLimit(rateLimiter))
 
 	// 健康检查
 	r.engine.GET("/health", r.healthCheck)
 
+	// 广告主 Token 托管服务（服务端解析 Ocean Engine 凭证）
+	r.tokenService = advService.NewTokenService(r.db, r.oceanCfg, r.newCache())
+
 	// API 路由组
 	apiV1 := r.engine.Group("/api/v1")
 	{
 		// 公开路由
 		r.registerPublicRoutes(apiV1)
 
-	// 需要认证的路由
+		// 需要认证的路由
 		protected := apiV1.Group("")
 		protected.Use(middleware.JWTAuth(r.jwtManager))
+		// 数据权限中间件（按角色数据范围限制可访问的广告主）
+		protected.Use(middleware.DataScope(datascope.NewResolver(r.db)))
 		// 操作日志中间件（在 JWT 认证之后，可获取用户信息）
 		protected.Use(middleware.OperationLog(r.db, nil))
 		r.registerProtectedRoutes(protected)
@@ -105,6 +185,15 @@ func (r *Router) Setup(mode string) *gin.Engine {
 	return r.engine
 }
 
+// newCache 创建缓存（Redis 未初始化时返回 nil）
+func (r *Router) newCache() cache.Cache {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil
+	}
+	return cache.NewRedisCache(rdb, "oceanengine")
+}
+
 // healthCheck 健康检查
 func (r *Router) healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -137,6 +226,10 @@ func (r *Router) registerPublicRoutes(rg *gin.RouterGroup) {
 		oauthGroup.GET("/callback", advHandler.OAuthCallback)
 	}
 
+	// 报表导出文件下载（签名链接鉴权）
+	reportHandler := reportApi.NewReportHandler(r.db, r.oceanCfg, r.exportFiles)
+	rg.GET("/reports/exports/:id/download", reportHandler.DownloadExport)
+
 	// 千川 OAuth 路由（公开）
 	if r.qianchuanCfg != nil {
 		qcOAuthHandler := qianchuanApi.NewQianchuanOAuthHandlerDefault(r.qianchuanCfg)
@@ -147,6 +240,43 @@ func (r *Router) registerPublicRoutes(rg *gin.RouterGroup) {
 			qcOAuthGroup.POST("/refresh", qcOAuthHandler.RefreshToken)
 		}
 	}
+
+	// 订阅推送回调（签名鉴权）
+	if r.spiCfg != nil {
+		r.registerSPIRoutes(rg)
+	}
+
+	// 监测链接点击与转化上报（应用密钥鉴权）
+	if r.trackingCfg != nil {
+		r.registerTrackingRoutes(rg)
+	}
+}
+
+// registerSPIRoutes 注册订阅推送回调路由
+func (r *Router) registerSPIRoutes(rg *gin.RouterGroup) {
+	dispatcher := spiService.NewDispatcher(r.db, r.spiCfg, r.logger)
+	syncer := reportService.NewReportSyncer(r.db, oceanclient.New(r.oceanCfg))
+	spiService.RegisterDefaultHandlers(dispatcher, r.db, syncer, r.tokenService, r.logger)
+	handler := spiApi.NewSPIHandler(spiService.NewReceiver(r.db, r.spiCfg), dispatcher, r.logger)
+
+	rg.GET("/spi/callback", handler.Callback)
+	rg.POST("/spi/callback", handler.Callback)
+}
+
+// registerTrackingRoutes 注册监测链接点击与转化上报路由，配置了应用密钥时才注册转化上报接口
+func (r *Router) registerTrackingRoutes(rg *gin.RouterGroup) {
+	r.clicks = trackingService.NewClickRecorder(r.db, r.trackingCfg, r.logger)
+	sdk := oceanclient.NewSDK(r.oceanCfg, oceansdk.WithLogger(r.logger.Named("oceanengine")))
+	postback, err := trackingService.NewPostback(r.db, r.trackingCfg, sdk, r.logger)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("初始化转化回传失败，转化上报接口不可用: %v", err))
+	}
+	handler := trackingApi.NewTrackingHandler(r.clicks, trackingService.NewConversionService(r.db, r.trackingCfg), postback, r.trackingCfg.APIKeys, r.logger)
+
+	rg.GET("/track/click", handler.Click)
+	if postback != nil && len(r.trackingCfg.APIKeys) > 0 {
+		rg.POST("/track/conversions", handler.Conversion)
+	}
 }
 
 // registerProtectedRoutes 注册需要认证的路由
@@ -221,6 +351,29 @@ func (r *Router) registerProtectedRoutes(rg *gin.RouterGroup) {
 
 	// DPA商品广告模块
 	r.registerDPARoutes(rg)
+
+	// 资金转账模块
+	if r.transferCfg != nil {
+		r.registerTransferRoutes(rg)
+	}
+}
+
+// registerTransferRoutes 注册资金转账路由
+func (r *Router) registerTransferRoutes(rg *gin.RouterGroup) {
+	sdk := transferService.NewExecutorSDK(r.oceanCfg, r.tokenService, r.logger)
+	executor := transferService.NewExecutor(r.db, r.transferCfg, sdk, r.logger)
+	handler := transferApi.NewTransferAPI(transferService.NewTransferService(r.db, r.transferCfg), executor)
+
+	transfers := rg.Group("/transfers")
+	{
+		transfers.GET("", handler.List)
+		transfers.POST("", handler.Create)
+		transfers.GET("/:id", handler.Get)
+		transfers.POST("/:id/approve", handler.Approve)
+		transfers.POST("/:id/reject", handler.Reject)
+		transfers.POST("/:id/cancel", handler.Cancel)
+		transfers.POST("/:id/reconcile", handler.Reconcile)
+	}
 }
 
 // registerSystemRoutes 注册系统管理路由
@@ -233,6 +386,7 @@ func (r *Router) registerSystemRoutes(rg *gin.RouterGroup) {
 	settingService := service.NewSettingService(r.db)
 	notificationService := service.NewNotificationService(r.db)
 	dictService := service.NewDictService(r.db)
+	jobService := service.NewJobService(r.db)
 
 	// 初始化 API
 	userAPI := adminApi.NewUserAPI(userService)
@@ -242,6 +396,7 @@ func (r *Router) registerSystemRoutes(rg *gin.RouterGroup) {
 	settingAPI := adminApi.NewSettingAPI(settingService)
 	notificationAPI := adminApi.NewNotificationAPI(notificationService)
 	dictAPI := adminApi.NewDictAPI(dictService)
+	jobAPI := adminApi.NewJobAPI(jobService)
 
 	system := rg.Group("/system")
 	{
@@ -255,6 +410,8 @@ func (r *Router) registerSystemRoutes(rg *gin.RouterGroup) {
 			users.DELETE("/:id", userAPI.Delete)
 			users.POST("/:id/reset-password", userAPI.ResetPassword)
 			users.POST("/change-password", userAPI.ChangePassword)
+			users.GET("/:id/advertisers", userAPI.GetUserAdvertisers)
+			users.PUT("/:id/advertisers", userAPI.UpdateUserAdvertisers)
 		}
 
 		// 角色管理
@@ -268,6 +425,8 @@ func (r *Router) registerSystemRoutes(rg *gin.RouterGroup) {
 			roles.DELETE("/:id", roleAPI.Delete)
 			roles.GET("/:id/menus", roleAPI.GetRoleMenus)
 			roles.PUT("/:id/menus", roleAPI.UpdateRoleMenus)
+			roles.GET("/:id/advertisers", roleAPI.GetRoleAdvertisers)
+			roles.PUT("/:id/advertisers", roleAPI.UpdateRoleAdvertisers)
 		}
 
 		// 菜单管理
@@ -290,6 +449,16 @@ func (r *Router) registerSystemRoutes(rg *gin.RouterGroup) {
 			logs.DELETE("/operation", logAPI.Delete)
 		}
 
+		// 定时任务
+		jobs := system.Group("/jobs")
+		{
+			jobs.GET("", jobAPI.GetList)
+			jobs.GET("/runs", jobAPI.GetRuns)
+			jobs.POST("/:id/trigger", jobAPI.Trigger)
+			jobs.PUT("/:id/pause", jobAPI.Pause)
+			jobs.PUT("/:id/resume", jobAPI.Resume)
+		}
+
 		// 用户设置
 		settings := system.Group("/settings")
 		{
@@ -356,7 +525,11 @@ func (r *Router) registerAdvertiserRoutes(rg *gin.RouterGroup) {
 
 // registerCampaignRoutes 注册广告系列路由
 func (r *Router) registerCampaignRoutes(rg *gin.RouterGroup) {
-	campaignHandler := campaignApi.NewCampaignHandler(r.db, r.oceanCfg)
+	executor := r.fanout
+	if executor == nil {
+		executor = fanout.New(nil)
+	}
+	campaignHandler := campaignApi.NewCampaignHandler(r.db, r.oceanCfg, executor)
 
 	campaigns := rg.Group("/campaigns")
 	{
@@ -366,6 +539,7 @@ func (r *Router) registerCampaignRoutes(rg *gin.RouterGroup) {
 		campaigns.PUT("/:id", campaignHandler.Update)
 		campaigns.DELETE("/:id", campaignHandler.Delete)
 		campaigns.PUT("/status", campaignHandler.UpdateStatus)
+		campaigns.POST("/sync", campaignHandler.SyncBatch)
 		campaigns.POST("/sync/:advertiser_id", campaignHandler.Sync)
 	}
 }
@@ -387,7 +561,7 @@ func (r *Router) registerAdRoutes(rg *gin.RouterGroup) {
 
 // registerCreativeRoutes 注册创意路由
 func (r *Router) registerCreativeRoutes(rg *gin.RouterGroup) {
-	creativeHandler := creativeApi.NewCreativeHandler(r.db)
+	creativeHandler := creativeApi.NewCreativeHandler(r.db, r.oceanCfg)
 
 	creatives := rg.Group("/creatives")
 	{
@@ -402,7 +576,7 @@ func (r *Router) registerCreativeRoutes(rg *gin.RouterGroup) {
 
 // registerReportRoutes 注册报表路由
 func (r *Router) registerReportRoutes(rg *gin.RouterGroup) {
-	reportHandler := reportApi.NewReportHandler(r.db, r.oceanCfg)
+	reportHandler := reportApi.NewReportHandler(r.db, r.oceanCfg, r.exportFiles)
 
 	reports := rg.Group("/reports")
 	{
@@ -410,23 +584,34 @@ func (r *Router) registerReportRoutes(rg *gin.RouterGroup) {
 		reports.GET("/advertiser/summary", reportHandler.GetAdvertiserSummary)
 		reports.GET("/campaign", reportHandler.GetCampaignReport)
 		reports.GET("/ad", reportHandler.GetAdReport)
+		reports.GET("/creative", reportHandler.GetCreativeReport)
 		reports.POST("/sync", reportHandler.SyncReport)
 		reports.GET("/exports", reportHandler.GetExportTaskList)
 		reports.POST("/exports", reportHandler.CreateExportTask)
+		reports.GET("/exports/:id", reportHandler.GetExportTask)
 	}
 }
 
 // registerMediaRoutes 注册素材管理路由
 func (r *Router) registerMediaRoutes(rg *gin.RouterGroup) {
-	mediaSvc := mediaService.NewMediaService(r.db)
+	mediaSvc := mediaService.NewMediaService(r.db, r.oceanCfg, r.materials, r.tokenService, r.materialCfg)
 	mediaHandler := mediaApi.NewMediaAPI(mediaSvc)
+	executor := r.fanout
+	if executor == nil {
+		executor = fanout.New(nil)
+	}
+	sdk := oceanclient.NewSDK(r.oceanCfg, oceansdk.WithTokenResolver(r.tokenService), oceansdk.WithLogger(r.logger.Named("oceanengine")))
+	libraryHandler := mediaApi.NewLibraryAPI(mediaService.NewLibraryService(r.db, mediaSvc, sdk, executor))
 
 	media := rg.Group("/media")
 	{
+		media.POST("/probe", mediaHandler.Probe)
+
 		// 图片素材
 		images := media.Group("/images")
 		{
 			images.GET("", mediaHandler.GetImageList)
+			images.POST("/upload", mediaHandler.UploadImage)
 			images.GET("/:id", mediaHandler.GetImageByID)
 			images.DELETE("/:id", mediaHandler.DeleteImage)
 		}
@@ -435,9 +620,30 @@ func (r *Router) registerMediaRoutes(rg *gin.RouterGroup) {
 		videos := media.Group("/videos")
 		{
 			videos.GET("", mediaHandler.GetVideoList)
+			videos.POST("/upload", mediaHandler.UploadVideo)
 			videos.GET("/:id", mediaHandler.GetVideoByID)
 			videos.DELETE("/:id", mediaHandler.DeleteVideo)
 		}
+
+		// 素材库
+		library := media.Group("/library")
+		{
+			library.GET("", libraryHandler.List)
+			library.POST("", libraryHandler.Upload)
+			library.GET("/:id", libraryHandler.Get)
+			library.PUT("/:id", libraryHandler.Update)
+			library.DELETE("/:id", libraryHandler.Delete)
+			library.POST("/:id/push", libraryHandler.Push)
+		}
+
+		// 素材库文件夹
+		folders := media.Group("/folders")
+		{
+			folders.GET("", libraryHandler.ListFolders)
+			folders.POST("", libraryHandler.CreateFolder)
+			folders.PUT("/:id", libraryHandler.UpdateFolder)
+			folders.DELETE("/:id", libraryHandler.DeleteFolder)
+		}
 	}
 }
 
@@ -472,7 +678,7 @@ func (r *Router) registerAudienceRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerQianchuanRoutes(rg *gin.RouterGroup) {
 	handler := qianchuanApi.NewQianchuanHandler(r.db, r.oceanCfg)
 
-	qianchuan := rg.Group("/qianchuan")
+	qianchuan := rg.Group("/qianchuan", middleware.OceanAccessToken(r.tokenService))
 	{
 		qianchuan.GET("/account", handler.GetAccountInfo)
 		qianchuan.GET("/shops", handler.GetShopList)
@@ -525,7 +731,7 @@ func (r *Router) registerQianchuanRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerEnterpriseRoutes(rg *gin.RouterGroup) {
 	handler := enterpriseApi.NewEnterpriseHandler(r.db, r.oceanCfg)
 
-	enterprise := rg.Group("/enterprise")
+	enterprise := rg.Group("/enterprise", middleware.OceanAccessToken(r.tokenService))
 	{
 		enterprise.GET("/info", handler.GetInfo)
 		enterprise.GET("/binds", handler.GetBindList)
@@ -555,7 +761,7 @@ func (r *Router) registerEnterpriseRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerLocalRoutes(rg *gin.RouterGroup) {
 	handler := localApi.NewLocalHandler(r.db, r.oceanCfg)
 
-	local := rg.Group("/local")
+	local := rg.Group("/local", middleware.OceanAccessToken(r.tokenService))
 	{
 		local.GET("/projects", handler.GetProjectList)
 		local.GET("/projects/:project_id", handler.GetProjectDetail)
@@ -592,7 +798,7 @@ func (r *Router) registerLocalRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerStarRoutes(rg *gin.RouterGroup) {
 	handler := starApi.NewStarHandler(r.db, r.oceanCfg)
 
-	star := rg.Group("/star")
+	star := rg.Group("/star", middleware.OceanAccessToken(r.tokenService))
 	{
 		star.GET("/account", handler.GetAccountInfo)
 		star.GET("/agent/advertisers", handler.GetAgentAdvertisers)
@@ -638,7 +844,7 @@ func (r *Router) registerServeMarketRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerClueRoutes(rg *gin.RouterGroup) {
 	handler := clueApi.NewClueHandler(r.db, r.oceanCfg)
 
-	clue := rg.Group("/clue")
+	clue := rg.Group("/clue", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 飞鱼线索
 		clue.GET("/list", handler.GetClueList)
@@ -683,7 +889,7 @@ func (r *Router) registerClueRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerEventManagerRoutes(rg *gin.RouterGroup) {
 	handler := eventmanagerApi.NewEventManagerHandler(r.db, r.oceanCfg)
 
-	eventmanager := rg.Group("/eventmanager")
+	eventmanager := rg.Group("/eventmanager", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 资产管理
 		eventmanager.GET("/assets", handler.GetAssets)
@@ -723,7 +929,7 @@ func (r *Router) registerEventManagerRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerAdvToolsRoutes(rg *gin.RouterGroup) {
 	handler := advtoolsApi.NewAdvToolsHandler(r.db, r.oceanCfg)
 
-	advtools := rg.Group("/advtools")
+	advtools := rg.Group("/advtools", middleware.OceanAccessToken(r.tokenService))
 	{
 		// RTA策略管理
 		rta := advtools.Group("/rta")
@@ -784,7 +990,7 @@ func (r *Router) registerAdvToolsRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerSiteRoutes(rg *gin.RouterGroup) {
 	handler := siteApi.NewSiteHandler(r.db, r.oceanCfg)
 
-	site := rg.Group("/site")
+	site := rg.Group("/site", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 橙子建站
 		orange := site.Group("/orange")
@@ -846,7 +1052,7 @@ func (r *Router) registerSiteRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerV3Routes(rg *gin.RouterGroup) {
 	handler := v3Api.NewV3Handler(r.db, r.oceanCfg)
 
-	v3 := rg.Group("/v3")
+	v3 := rg.Group("/v3", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 项目管理
 		projects := v3.Group("/projects")
@@ -877,9 +1083,11 @@ func (r *Router) registerV3Routes(rg *gin.RouterGroup) {
 
 // registerDMPRoutes 注册DMP人群包路由
 func (r *Router) registerDMPRoutes(rg *gin.RouterGroup) {
-	handler := dmpApi.NewDMPHandler(r.db, r.oceanCfg)
+	// 人群包构建的原始ID文件与素材使用同一存储，由定时任务服务领取构建
+	builds := dmpService.NewBuildService(r.db, r.materials, nil, r.logger)
+	handler := dmpApi.NewDMPHandler(r.db, r.oceanCfg, builds)
 
-	dmp := rg.Group("/dmp")
+	dmp := rg.Group("/dmp", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 数据源管理
 		datasource := dmp.Group("/datasource")
@@ -899,6 +1107,8 @@ func (r *Router) registerDMPRoutes(rg *gin.RouterGroup) {
 			audience.DELETE("", handler.DeleteCustomAudience)
 			audience.POST("/publish", handler.PublishCustomAudience)
 			audience.POST("/push", handler.PushCustomAudience)
+			audience.POST("/build", handler.BuildAudience)
+			audience.GET("/build/:id", handler.GetBuildTask)
 		}
 	}
 }
@@ -907,7 +1117,7 @@ func (r *Router) registerDMPRoutes(rg *gin.RouterGroup) {
 func (r *Router) registerDPARoutes(rg *gin.RouterGroup) {
 	handler := dpaApi.NewDPAHandler(r.db, r.oceanCfg)
 
-	dpa := rg.Group("/dpa")
+	dpa := rg.Group("/dpa", middleware.OceanAccessToken(r.tokenService))
 	{
 		// 商品库管理
 		libraries := dpa.Group("/libraries")
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	base := time.Date(2024, 1, 31, 10, 30, 15, 0, time.Local) // 周三
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 * * * *", time.Date(2024, 1, 31, 11, 0, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 45, 0, 0, time.Local)},
+		{"0 2 * * *", time.Date(2024, 2, 1, 2, 0, 0, 0, time.Local)},
+		{"30 9-17/4 * * 1-5", time.Date(2024, 1, 31, 13, 30, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)},
+		{"0 0 1,15 * 0", time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2024, 1, 31, 11, 0, 0, 0, time.Local)},
+		{"@daily", time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)},
+		{"@every 5m", time.Date(2024, 1, 31, 10, 35, 15, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(base))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 0s", "@yearly"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseSchedule_Impossible(t *testing.T) {
+	s, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
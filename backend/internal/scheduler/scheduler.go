@@ -0,0 +1,390 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/admin/model"
+	"oceanengine-backend/pkg/cache"
+)
+
+// defaultTimeout 任务默认执行超时
+const defaultTimeout = time.Hour
+
+// maxCatchUpSteps 计算错过的最近一次计划时间时的最大步数
+const maxCatchUpSteps = 100000
+
+// Result 任务执行结果，计入 sys_job_run
+type Result struct {
+	Success int
+	Failed  int
+}
+
+// JobFunc 任务函数
+type JobFunc func(ctx context.Context) (Result, error)
+
+// Job 任务定义
+type Job struct {
+	Name        string
+	Description string
+	Spec        string        // 默认调度表达式，可被配置覆盖
+	Timeout     time.Duration // 单次执行超时，同时作为分布式锁的过期时间
+	CatchUp     bool          // 是否补跑停机期间错过的执行（多次错过只补跑一次）
+	Run         JobFunc
+}
+
+type entry struct {
+	job      Job
+	schedule Schedule
+	running  atomic.Bool
+}
+
+// Scheduler 分布式定时任务调度器
+//
+// 每个计划执行时间通过 sys_job.last_scheduled_at 的条件更新抢占，多实例下只会被执行一次；
+// 执行期间持有 Redis 锁，避免上一次执行未结束时其他实例重复执行。
+// 手动触发写入 PENDING 状态的 sys_job_run，由任一实例领取执行。
+type Scheduler struct {
+	db       *gorm.DB
+	cache    cache.Cache
+	log      *zap.Logger
+	cfg      *config.SchedulerConfig
+	instance string
+
+	entries map[string]*entry
+	names   []string
+	wg      sync.WaitGroup
+	now     func() time.Time
+}
+
+// New 创建调度器，c 为 nil 时仅依赖数据库抢占
+func New(db *gorm.DB, c cache.Cache, log *zap.Logger, cfg *config.SchedulerConfig) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		db:       db,
+		cache:    c,
+		log:      log,
+		cfg:      cfg,
+		instance: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		entries:  make(map[string]*entry),
+		now:      time.Now,
+	}
+}
+
+// Register 注册任务，配置文件中的同名任务配置覆盖默认值
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("scheduler: job name and run func are required")
+	}
+	if _, ok := s.entries[job.Name]; ok {
+		return fmt.Errorf("scheduler: job %s already registered", job.Name)
+	}
+
+	if override, ok := s.cfg.Jobs[job.Name]; ok {
+		if override.Disabled {
+			s.log.Info(fmt.Sprintf("[%s] 任务已在配置中禁用", job.Name))
+			return nil
+		}
+		if override.Cron != "" {
+			job.Spec = override.Cron
+		}
+		if override.Timeout > 0 {
+			job.Timeout = override.Timeout
+		}
+		if override.CatchUp != nil {
+			job.CatchUp = *override.CatchUp
+		}
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultTimeout
+	}
+
+	schedule, err := ParseSchedule(job.Spec)
+	if err != nil {
+		return fmt.Errorf("scheduler: job %s: %w", job.Name, err)
+	}
+
+	s.entries[job.Name] = &entry{job: job, schedule: schedule}
+	s.names = append(s.names, job.Name)
+	return nil
+}
+
+// Start 同步任务定义到 sys_job 并启动调度循环
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.syncJobs(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.Tick)
+		defer ticker.Stop()
+
+		s.poll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Wait 等待执行中的任务结束
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// syncJobs 同步任务定义；新任务以当前时间为起点，不补跑历史计划
+func (s *Scheduler) syncJobs(ctx context.Context) error {
+	now := s.now()
+	for _, name := range s.names {
+		e := s.entries[name]
+		job := model.Job{Name: name}
+		if err := s.db.WithContext(ctx).
+			Attrs(model.Job{LastScheduledAt: &now}).
+			FirstOrCreate(&job, model.Job{Name: name}).Error; err != nil {
+			return fmt.Errorf("同步任务 %s 失败: %w", name, err)
+		}
+
+		if job.LastScheduledAt == nil {
+			job.LastScheduledAt = &now
+		}
+		job.Description = e.job.Description
+		job.Cron = e.job.Spec
+		job.CatchUp = e.job.CatchUp
+		next := e.schedule.Next(*job.LastScheduledAt)
+		job.NextRunAt = &next
+		if err := s.db.WithContext(ctx).Model(&job).
+			Select("description", "cron", "catch_up", "last_scheduled_at", "next_run_at").
+			Updates(&job).Error; err != nil {
+			return fmt.Errorf("同步任务 %s 失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// poll 检查手动触发与到期的计划
+func (s *Scheduler) poll(ctx context.Context) {
+	now := s.now()
+	for _, name := range s.names {
+		if ctx.Err() != nil {
+			return
+		}
+		e := s.entries[name]
+		if e.running.Load() {
+			continue
+		}
+		if err := s.pollManual(ctx, e); err != nil {
+			s.log.Error(fmt.Sprintf("[%s] 检查手动触发失败: %v", name, err))
+		}
+		if e.running.Load() {
+			continue
+		}
+		if err := s.pollSchedule(ctx, e, now); err != nil {
+			s.log.Error(fmt.Sprintf("[%s] 调度失败: %v", name, err))
+		}
+	}
+}
+
+// pollManual 领取手动触发的执行
+func (s *Scheduler) pollManual(ctx context.Context, e *entry) error {
+	var run model.JobRun
+	err := s.db.WithContext(ctx).
+		Where("job_name = ? AND status = ?", e.job.Name, model.JobRunPending).
+		Order("id ASC").
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	startedAt := s.now()
+	result := s.db.WithContext(ctx).Model(&model.JobRun{}).
+		Where("id = ? AND status = ?", run.ID, model.JobRunPending).
+		Updates(map[string]interface{}{
+			"status":     model.JobRunRunning,
+			"started_at": startedAt,
+			"instance":   s.instance,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	run.Status = model.JobRunRunning
+	run.StartedAt = &startedAt
+	run.Instance = s.instance
+	s.start(ctx, e, &run)
+	return nil
+}
+
+// pollSchedule 抢占到期的计划执行
+func (s *Scheduler) pollSchedule(ctx context.Context, e *entry, now time.Time) error {
+	var job model.Job
+	if err := s.db.WithContext(ctx).Where("name = ?", e.job.Name).First(&job).Error; err != nil {
+		return err
+	}
+	if job.Paused || job.LastScheduledAt == nil {
+		return nil
+	}
+
+	slot := e.schedule.Next(*job.LastScheduledAt)
+	if slot.IsZero() || slot.After(now) {
+		return nil
+	}
+	// 多次错过的计划合并为最近一次
+	for i := 0; i < maxCatchUpSteps; i++ {
+		next := e.schedule.Next(slot)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		slot = next
+	}
+
+	trigger := model.JobTriggerSchedule
+	missed := now.Sub(slot) > s.grace()
+	if missed {
+		trigger = model.JobTriggerCatchUp
+	}
+
+	next := e.schedule.Next(slot)
+	result := s.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND last_scheduled_at < ?", job.ID, slot).
+		Updates(map[string]interface{}{
+			"last_scheduled_at": slot,
+			"next_run_at":       next,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	if missed && !e.job.CatchUp {
+		s.log.Info(fmt.Sprintf("[%s] 跳过错过的计划执行 %s", e.job.Name, slot.Format("2006-01-02 15:04:05")))
+		return nil
+	}
+
+	startedAt := s.now()
+	run := &model.JobRun{
+		JobName:     e.job.Name,
+		Trigger:     trigger,
+		Status:      model.JobRunRunning,
+		ScheduledAt: &slot,
+		StartedAt:   &startedAt,
+		Instance:    s.instance,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return err
+	}
+	s.start(ctx, e, run)
+	return nil
+}
+
+// grace 计划时间与当前时间相差超过该值视为停机期间错过的执行
+func (s *Scheduler) grace() time.Duration {
+	if g := 2 * s.cfg.Tick; g > time.Minute {
+		return g
+	}
+	return time.Minute
+}
+
+func (s *Scheduler) start(ctx context.Context, e *entry, run *model.JobRun) {
+	e.running.Store(true)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer e.running.Store(false)
+		s.execute(ctx, e, run)
+	}()
+}
+
+// execute 持锁执行任务并记录结果
+func (s *Scheduler) execute(ctx context.Context, e *entry, run *model.JobRun) {
+	name := e.job.Name
+	if s.cache != nil {
+		lockKey := "scheduler:job:" + name
+		locked, err := s.cache.Lock(ctx, lockKey, e.job.Timeout)
+		if err != nil {
+			// Redis 不可用时仍可依赖数据库抢占保证计划只执行一次
+			s.log.Warn(fmt.Sprintf("[%s] 获取任务锁失败，继续执行: %v", name, err))
+		} else if !locked {
+			s.finish(ctx, e, run, Result{}, errJobLocked)
+			return
+		} else {
+			defer s.cache.Unlock(context.WithoutCancel(ctx), lockKey)
+		}
+	}
+
+	s.log.Info(fmt.Sprintf("[%s] 开始执行 (%s)", name, run.Trigger))
+	runCtx, cancel := context.WithTimeout(ctx, e.job.Timeout)
+	defer cancel()
+	result, err := safeRun(runCtx, e.job.Run)
+	s.finish(ctx, e, run, result, err)
+}
+
+var errJobLocked = errors.New("任务正在其他实例执行")
+
+// finish 写入执行结果
+func (s *Scheduler) finish(ctx context.Context, e *entry, run *model.JobRun, result Result, runErr error) {
+	ctx = context.WithoutCancel(ctx)
+	finishedAt := s.now()
+
+	run.FinishedAt = &finishedAt
+	run.SuccessCount = result.Success
+	run.FailCount = result.Failed
+	if run.StartedAt != nil {
+		run.Duration = finishedAt.Sub(*run.StartedAt).Milliseconds()
+	}
+	switch {
+	case errors.Is(runErr, errJobLocked):
+		run.Status = model.JobRunSkipped
+		run.ErrorMsg = runErr.Error()
+		s.log.Info(fmt.Sprintf("[%s] %v，跳过", e.job.Name, runErr))
+	case runErr != nil:
+		run.Status = model.JobRunFailed
+		run.ErrorMsg = runErr.Error()
+		s.log.Error(fmt.Sprintf("[%s] 执行失败: %v", e.job.Name, runErr))
+	default:
+		run.Status = model.JobRunSuccess
+		s.log.Info(fmt.Sprintf("[%s] 执行成功，成功: %d, 失败: %d", e.job.Name, result.Success, result.Failed))
+	}
+
+	if err := s.db.WithContext(ctx).Model(run).
+		Select("status", "finished_at", "duration", "success_count", "fail_count", "error_msg").
+		Updates(run).Error; err != nil {
+		s.log.Error(fmt.Sprintf("[%s] 保存执行记录失败: %v", e.job.Name, err))
+	}
+	if run.Status == model.JobRunSkipped {
+		return
+	}
+	if err := s.db.WithContext(ctx).Model(&model.Job{}).
+		Where("name = ?", e.job.Name).
+		Updates(map[string]interface{}{
+			"last_run_at": finishedAt,
+			"last_status": run.Status,
+		}).Error; err != nil {
+		s.log.Error(fmt.Sprintf("[%s] 更新任务状态失败: %v", e.job.Name, err))
+	}
+}
+
+// safeRun 执行任务并捕获 panic
+func safeRun(ctx context.Context, fn JobFunc) (result Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(ctx)
+}
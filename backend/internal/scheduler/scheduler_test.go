@@ -0,0 +1,256 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"oceanengine-backend/config"
+	"oceanengine-backend/internal/app/admin/model"
+	"oceanengine-backend/pkg/cache"
+)
+
+// memLock 仅实现分布式锁的内存缓存
+type memLock struct {
+	cache.Cache
+	mu    sync.Mutex
+	locks map[string]bool
+}
+
+func (m *memLock) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] {
+		return false, nil
+	}
+	m.locks[key] = true
+	return true, nil
+}
+
+func (m *memLock) Unlock(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.locks, key)
+	return nil
+}
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	// 任务在独立 goroutine 中写入执行记录，内存库需共用同一连接
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Job{}, &model.JobRun{}))
+	return db
+}
+
+func newTestScheduler(db *gorm.DB, c cache.Cache, clock *testClock, jobs map[string]config.JobConfig) *Scheduler {
+	s := New(db, c, zap.NewNop(), &config.SchedulerConfig{Tick: 10 * time.Second, Jobs: jobs})
+	s.now = clock.Now
+	return s
+}
+
+func listRuns(t *testing.T, db *gorm.DB, name string) []model.JobRun {
+	var runs []model.JobRun
+	require.NoError(t, db.Where("job_name = ?", name).Order("id ASC").Find(&runs).Error)
+	return runs
+}
+
+func TestScheduler_RunsEachSlotOnce(t *testing.T) {
+	db := newTestDB(t)
+	lock := &memLock{locks: map[string]bool{}}
+	clock := &testClock{now: time.Date(2024, 1, 1, 10, 30, 0, 0, time.Local)}
+
+	var calls int32
+	job := Job{Name: "hourly", Spec: "0 * * * *", Run: func(ctx context.Context) (Result, error) {
+		atomic.AddInt32(&calls, 1)
+		return Result{Success: 3, Failed: 1}, nil
+	}}
+
+	// 两个实例共享数据库与锁
+	a := newTestScheduler(db, lock, clock, nil)
+	b := newTestScheduler(db, lock, clock, nil)
+	require.NoError(t, a.Register(job))
+	require.NoError(t, b.Register(job))
+	ctx := context.Background()
+	require.NoError(t, a.syncJobs(ctx))
+	require.NoError(t, b.syncJobs(ctx))
+
+	// 未到期
+	a.poll(ctx)
+	b.poll(ctx)
+	a.Wait()
+	b.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	clock.Set(time.Date(2024, 1, 1, 11, 0, 5, 0, time.Local))
+	a.poll(ctx)
+	b.poll(ctx)
+	a.Wait()
+	b.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	runs := listRuns(t, db, "hourly")
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.JobTriggerSchedule, runs[0].Trigger)
+	assert.Equal(t, model.JobRunSuccess, runs[0].Status)
+	assert.Equal(t, 3, runs[0].SuccessCount)
+	assert.Equal(t, 1, runs[0].FailCount)
+	assert.Equal(t, time.Date(2024, 1, 1, 11, 0, 0, 0, time.Local), runs[0].ScheduledAt.Local())
+
+	var stored model.Job
+	require.NoError(t, db.Where("name = ?", "hourly").First(&stored).Error)
+	assert.Equal(t, model.JobRunSuccess, stored.LastStatus)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local), stored.NextRunAt.Local())
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	db := newTestDB(t)
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 30, 0, 0, time.Local)}
+
+	var daily, frequent int32
+	catchUp := false
+	s := newTestScheduler(db, nil, clock, map[string]config.JobConfig{
+		"frequent": {CatchUp: &catchUp},
+	})
+	require.NoError(t, s.Register(Job{Name: "daily", Spec: "0 2 * * *", CatchUp: true, Run: func(ctx context.Context) (Result, error) {
+		atomic.AddInt32(&daily, 1)
+		return Result{}, nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "frequent", Spec: "@every 5m", CatchUp: true, Run: func(ctx context.Context) (Result, error) {
+		atomic.AddInt32(&frequent, 1)
+		return Result{}, nil
+	}}))
+	ctx := context.Background()
+	require.NoError(t, s.syncJobs(ctx))
+
+	// 停机三天后恢复：多次错过的计划只补跑一次
+	clock.Set(time.Date(2024, 1, 4, 9, 2, 0, 0, time.Local))
+	s.poll(ctx)
+	s.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&daily))
+	runs := listRuns(t, db, "daily")
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.JobTriggerCatchUp, runs[0].Trigger)
+	assert.Equal(t, time.Date(2024, 1, 4, 2, 0, 0, 0, time.Local), runs[0].ScheduledAt.Local())
+
+	// 配置关闭补跑的任务跳过错过的执行，从下一次计划继续
+	assert.Equal(t, int32(0), atomic.LoadInt32(&frequent))
+	assert.Empty(t, listRuns(t, db, "frequent"))
+	clock.Set(time.Date(2024, 1, 4, 9, 5, 0, 0, time.Local))
+	s.poll(ctx)
+	s.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&frequent))
+}
+
+func TestScheduler_ManualAndFailure(t *testing.T) {
+	db := newTestDB(t)
+	clock := &testClock{now: time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)}
+	s := newTestScheduler(db, nil, clock, nil)
+
+	var panics int32
+	require.NoError(t, s.Register(Job{Name: "broken", Spec: "@daily", Run: func(ctx context.Context) (Result, error) {
+		if atomic.AddInt32(&panics, 1) == 1 {
+			panic("boom")
+		}
+		return Result{Success: 1}, errors.New("partial failure")
+	}}))
+	ctx := context.Background()
+	require.NoError(t, s.syncJobs(ctx))
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.Create(&model.JobRun{JobName: "broken", Trigger: model.JobTriggerManual, Status: model.JobRunPending, TriggeredBy: 1}).Error)
+		s.poll(ctx)
+		s.Wait()
+	}
+
+	runs := listRuns(t, db, "broken")
+	require.Len(t, runs, 2)
+	assert.Equal(t, model.JobRunFailed, runs[0].Status)
+	assert.Contains(t, runs[0].ErrorMsg, "panic: boom")
+	assert.Equal(t, model.JobRunFailed, runs[1].Status)
+	assert.Equal(t, "partial failure", runs[1].ErrorMsg)
+	assert.Equal(t, 1, runs[1].SuccessCount)
+	assert.NotEmpty(t, runs[1].Instance)
+	assert.NotNil(t, runs[1].FinishedAt)
+}
+
+func TestScheduler_PausedAndLocked(t *testing.T) {
+	db := newTestDB(t)
+	lock := &memLock{locks: map[string]bool{}}
+	clock := &testClock{now: time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local)}
+	s := newTestScheduler(db, lock, clock, nil)
+
+	var calls int32
+	require.NoError(t, s.Register(Job{Name: "job", Spec: "*/5 * * * *", Run: func(ctx context.Context) (Result, error) {
+		atomic.AddInt32(&calls, 1)
+		return Result{}, nil
+	}}))
+	ctx := context.Background()
+	require.NoError(t, s.syncJobs(ctx))
+
+	// 暂停后不再按计划执行
+	require.NoError(t, db.Model(&model.Job{}).Where("name = ?", "job").Update("paused", true).Error)
+	clock.Set(time.Date(2024, 1, 1, 10, 5, 0, 0, time.Local))
+	s.poll(ctx)
+	s.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	// 其他实例持有锁时记录为跳过
+	require.NoError(t, db.Model(&model.Job{}).Where("name = ?", "job").Update("paused", false).Error)
+	lock.locks["scheduler:job:job"] = true
+	s.poll(ctx)
+	s.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	runs := listRuns(t, db, "job")
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.JobRunSkipped, runs[0].Status)
+}
+
+func TestScheduler_RegisterConfig(t *testing.T) {
+	db := newTestDB(t)
+	s := newTestScheduler(db, nil, &testClock{now: time.Now()}, map[string]config.JobConfig{
+		"disabled": {Disabled: true},
+		"override": {Cron: "@every 1m", Timeout: time.Minute},
+		"invalid":  {Cron: "bad"},
+	})
+	noop := func(ctx context.Context) (Result, error) { return Result{}, nil }
+
+	require.NoError(t, s.Register(Job{Name: "disabled", Spec: "@hourly", Run: noop}))
+	require.NoError(t, s.Register(Job{Name: "override", Spec: "@hourly", Run: noop}))
+	assert.Error(t, s.Register(Job{Name: "override", Spec: "@hourly", Run: noop}))
+	assert.Error(t, s.Register(Job{Name: "invalid", Spec: "@hourly", Run: noop}))
+	assert.Error(t, s.Register(Job{Name: "norun", Spec: "@hourly"}))
+
+	assert.Equal(t, []string{"override"}, s.names)
+	assert.Equal(t, "@every 1m", s.entries["override"].job.Spec)
+	assert.Equal(t, time.Minute, s.entries["override"].job.Timeout)
+}
@@ -1,24 +1,81 @@
 package utils
 
 import (
-	"github.com/gin-gonic/gin"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
 )
 
-// GetAccessToken 从请求中统一获取 access_token
-// 按优先级从以下位置获取：
-// 1. X-Access-Token header
-// 2. Access-Token header
-// 3. access_token query parameter
+// ContextKeyAccessToken 上下文中 Ocean Engine access_token 的键
+const ContextKeyAccessToken = "oe_access_token"
+
+// maxPeekBodySize 查找 advertiser_id 时最多读取的 JSON 请求体大小
+const maxPeekBodySize = 1 << 20
+
+// GetAccessToken 获取服务端解析的 Ocean Engine access_token
+// Token 由 middleware.OceanAccessToken 根据 advertiser_id 从广告主表中解析并注入，
+// 不再接受客户端通过 header 或 query 传入
 func GetAccessToken(c *gin.Context) string {
-	token := c.GetHeader("X-Access-Token")
-	if token == "" {
-		token = c.GetHeader("Access-Token")
+	return c.GetString(ContextKeyAccessToken)
+}
+
+// SetAccessToken 将解析后的 access_token 写入上下文
+func SetAccessToken(c *gin.Context, token string) {
+	c.Set(ContextKeyAccessToken, token)
+}
+
+// ErrAdvertiserIDMismatch query/path 与请求体中的 advertiser_id 不一致
+var ErrAdvertiserIDMismatch = errors.New("query 与请求体中的 advertiser_id 不一致")
+
+// LookupAdvertiserID 从请求中查找 advertiser_id
+// 依次查找 query、path parameter、表单字段及 JSON 请求体，读取请求体后会将其还原；
+// Token 按该 ID 解析而处理器通常读取请求体中的 ID，两处同时存在且不一致时返回 ErrAdvertiserIDMismatch
+func LookupAdvertiserID(c *gin.Context) (uint64, error) {
+	id := GetAdvertiserID(c)
+	bodyID := bodyAdvertiserID(c)
+	if id == 0 {
+		return bodyID, nil
 	}
-	if token == "" {
-		token = c.Query("access_token")
+	if bodyID != 0 && bodyID != id {
+		return 0, ErrAdvertiserIDMismatch
 	}
-	return token
+	return id, nil
+}
+
+// bodyAdvertiserID 从表单字段或 JSON 请求体中读取 advertiser_id
+func bodyAdvertiserID(c *gin.Context) uint64 {
+	contentType := c.ContentType()
+	if contentType == "multipart/form-data" || contentType == "application/x-www-form-urlencoded" {
+		id, _ := strconv.ParseUint(c.PostForm("advertiser_id"), 10, 64)
+		return id
+	}
+
+	if c.Request.Body == nil || !strings.Contains(contentType, "json") {
+		return 0
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPeekBodySize+1))
+	if err != nil {
+		return 0
+	}
+	// 还原请求体（超出上限时拼接剩余部分）
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), c.Request.Body))
+	if len(data) > maxPeekBodySize {
+		return 0
+	}
+
+	var body struct {
+		AdvertiserID json.Number `json:"advertiser_id"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return 0
+	}
+	id, _ := strconv.ParseUint(body.AdvertiserID.String(), 10, 64)
+	return id
 }
 
 // GetAdvertiserID 从请求中获取 advertiser_id
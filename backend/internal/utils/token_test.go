@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -15,37 +18,24 @@ func init() {
 func TestGetAccessToken(t *testing.T) {
 	tests := []struct {
 		name           string
+		contextToken   string
 		xAccessToken   string
-		accessToken    string
 		queryToken     string
 		expectedResult string
 	}{
 		{
-			name:           "X-Access-Token header takes precedence",
-			xAccessToken:   "x-token-value",
-			accessToken:    "access-token-value",
-			queryToken:     "query-token-value",
-			expectedResult: "x-token-value",
+			name:           "Token resolved by middleware",
+			contextToken:   "server-token-value",
+			expectedResult: "server-token-value",
 		},
 		{
-			name:           "Access-Token header when X-Access-Token is empty",
-			xAccessToken:   "",
-			accessToken:    "access-token-value",
-			queryToken:     "query-token-value",
-			expectedResult: "access-token-value",
+			name:           "Client supplied header is ignored",
+			xAccessToken:   "x-token-value",
+			expectedResult: "",
 		},
 		{
-			name:           "Query parameter when headers are empty",
-			xAccessToken:   "",
-			accessToken:    "",
+			name:           "Client supplied query is ignored",
 			queryToken:     "query-token-value",
-			expectedResult: "query-token-value",
-		},
-		{
-			name:           "Empty when all sources are empty",
-			xAccessToken:   "",
-			accessToken:    "",
-			queryToken:     "",
 			expectedResult: "",
 		},
 	}
@@ -59,10 +49,10 @@ func TestGetAccessToken(t *testing.T) {
 			if tt.xAccessToken != "" {
 				req.Header.Set("X-Access-Token", tt.xAccessToken)
 			}
-			if tt.accessToken != "" {
-				req.Header.Set("Access-Token", tt.accessToken)
-			}
 			c.Request = req
+			if tt.contextToken != "" {
+				SetAccessToken(c, tt.contextToken)
+			}
 
 			result := GetAccessToken(c)
 			if result != tt.expectedResult {
@@ -72,6 +62,101 @@ func TestGetAccessToken(t *testing.T) {
 	}
 }
 
+func TestLookupAdvertiserID(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		contentType    string
+		body           string
+		expectedResult uint64
+		expectedErr    error
+	}{
+		{
+			name:           "Query parameter",
+			url:            "/?advertiser_id=12345",
+			expectedResult: 12345,
+		},
+		{
+			name:           "Query and body match",
+			url:            "/?advertiser_id=12345",
+			contentType:    "application/json",
+			body:           `{"advertiser_id":12345}`,
+			expectedResult: 12345,
+		},
+		{
+			name:        "Query and body mismatch",
+			url:         "/?advertiser_id=12345",
+			contentType: "application/json",
+			body:        `{"advertiser_id":67890}`,
+			expectedErr: ErrAdvertiserIDMismatch,
+		},
+		{
+			name:        "Query and form mismatch",
+			url:         "/?advertiser_id=12345",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "advertiser_id=24680",
+			expectedErr: ErrAdvertiserIDMismatch,
+		},
+		{
+			name:           "JSON body number",
+			url:            "/",
+			contentType:    "application/json",
+			body:           `{"advertiser_id":67890,"name":"test"}`,
+			expectedResult: 67890,
+		},
+		{
+			name:           "JSON body string",
+			url:            "/",
+			contentType:    "application/json; charset=utf-8",
+			body:           `{"advertiser_id":"13579"}`,
+			expectedResult: 13579,
+		},
+		{
+			name:           "Form field",
+			url:            "/",
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "advertiser_id=24680",
+			expectedResult: 24680,
+		},
+		{
+			name:           "Missing advertiser_id",
+			url:            "/",
+			contentType:    "application/json",
+			body:           `{"name":"test"}`,
+			expectedResult: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			req, _ := http.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			c.Request = req
+
+			result, err := LookupAdvertiserID(c)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("LookupAdvertiserID() error = %v, want %v", err, tt.expectedErr)
+			}
+			if result != tt.expectedResult {
+				t.Errorf("LookupAdvertiserID() = %v, want %v", result, tt.expectedResult)
+			}
+
+			// 请求体应被还原，处理器仍可读取
+			if tt.contentType == "application/json" {
+				body, _ := io.ReadAll(c.Request.Body)
+				if string(body) != tt.body {
+					t.Errorf("request body = %s, want %s", body, tt.body)
+				}
+			}
+		})
+	}
+}
+
 func TestGetAdvertiserID(t *testing.T) {
 	tests := []struct {
 		name           string
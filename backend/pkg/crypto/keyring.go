@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"oceanengine-backend/config"
+)
+
+// cipherPrefix 密文前缀，完整格式为 enc:<版本>:<base64(nonce|ciphertext)>
+const cipherPrefix = "enc:"
+
+var (
+	ErrKeyNotConfigured = errors.New("encryption key not configured")
+	ErrUnknownKey       = errors.New("unknown encryption key version")
+	ErrMalformed        = errors.New("malformed ciphertext")
+)
+
+// Keyring 多版本 AES-256-GCM 密钥环
+// 使用 active 版本加密，按密文中的版本号解密，以支持密钥轮换
+type Keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// NewKeyring 创建密钥环
+// keys 为 版本 => base64 编码的 32 字节密钥
+func NewKeyring(active string, keys map[string]string) (*Keyring, error) {
+	if active == "" {
+		return nil, ErrKeyNotConfigured
+	}
+	k := &Keyring{active: active, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for version, encoded := range keys {
+		if version == "" || strings.Contains(version, ":") {
+			return nil, fmt.Errorf("invalid key version %q", version)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %s: %w", version, err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("key %s must be 32 bytes, got %d", version, len(raw))
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.aeads[version] = aead
+	}
+	if _, ok := k.aeads[active]; !ok {
+		return nil, fmt.Errorf("%w: active key %s", ErrUnknownKey, active)
+	}
+	return k, nil
+}
+
+// NewKeyringFromConfig 从配置创建密钥环
+func NewKeyringFromConfig(cfg *config.CryptoConfig) (*Keyring, error) {
+	return NewKeyring(cfg.ActiveKey, cfg.Keys)
+}
+
+// ActiveVersion 当前加密使用的密钥版本
+func (k *Keyring) ActiveVersion() string {
+	return k.active
+}
+
+// Encrypt 使用当前密钥加密，空字符串原样返回
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead := k.aeads[k.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return cipherPrefix + k.active + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密密文；未加密的历史明文原样返回
+func (k *Keyring) Decrypt(value string) (string, error) {
+	version, payload, ok := ParseVersion(value)
+	if !ok {
+		return value, nil
+	}
+	aead, found := k.aeads[version]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, version)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt with key %s: %w", version, err)
+	}
+	return string(plain), nil
+}
+
+// NeedsRotation 存储值是否需要用当前密钥重新加密（明文或旧版本密文）
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	version, _, ok := ParseVersion(value)
+	return !ok || version != k.active
+}
+
+// IsEncrypted 存储值是否为密文
+func IsEncrypted(value string) bool {
+	_, _, ok := ParseVersion(value)
+	return ok
+}
+
+// ParseVersion 解析密文中的密钥版本
+func ParseVersion(value string) (version, payload string, ok bool) {
+	if !strings.HasPrefix(value, cipherPrefix) {
+		return "", "", false
+	}
+	version, payload, ok = strings.Cut(value[len(cipherPrefix):], ":")
+	if !ok || version == "" {
+		return "", "", false
+	}
+	return version, payload, true
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+// Init 根据配置初始化全局密钥环
+func Init(cfg *config.CryptoConfig) (*Keyring, error) {
+	k, err := NewKeyringFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	SetDefault(k)
+	return k, nil
+}
+
+// SetDefault 设置全局密钥环
+func SetDefault(k *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = k
+}
+
+// Default 获取全局密钥环
+func Default() *Keyring {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultKeyring
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k, err := NewKeyring("v1", map[string]string{"v1": testKey('a')})
+	require.NoError(t, err)
+
+	enc, err := k.Encrypt("access-token")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:"))
+	assert.NotContains(t, enc, "access-token")
+
+	enc2, err := k.Encrypt("access-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, enc, enc2, "nonce 应随机")
+
+	plain, err := k.Decrypt(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", plain)
+
+	// 历史明文原样返回
+	plain, err = k.Decrypt("legacy-token")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-token", plain)
+
+	empty, err := k.Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, err := NewKeyring("v1", map[string]string{"v1": testKey('a')})
+	require.NoError(t, err)
+	enc, err := old.Encrypt("refresh-token")
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring("v2", map[string]string{"v1": testKey('a'), "v2": testKey('b')})
+	require.NoError(t, err)
+	assert.True(t, rotated.NeedsRotation(enc))
+	assert.True(t, rotated.NeedsRotation("legacy-token"))
+	assert.False(t, rotated.NeedsRotation(""))
+
+	plain, err := rotated.Decrypt(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-token", plain)
+
+	reenc, err := rotated.Encrypt(plain)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(reenc))
+
+	// 移除旧密钥后无法解密旧密文
+	onlyNew, err := NewKeyring("v2", map[string]string{"v2": testKey('b')})
+	require.NoError(t, err)
+	_, err = onlyNew.Decrypt(enc)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	_, err := NewKeyring("", nil)
+	assert.ErrorIs(t, err, ErrKeyNotConfigured)
+
+	_, err = NewKeyring("v1", map[string]string{"v1": ""})
+	assert.Error(t, err)
+
+	_, err = NewKeyring("v2", map[string]string{"v1": testKey('a')})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = NewKeyring("v:1", map[string]string{"v:1": testKey('a')})
+	assert.Error(t, err)
+}
+
+type secretRecord struct {
+	ID    uint64
+	Token string `gorm:"serializer:encrypted"`
+}
+
+func TestEncryptedSerializer(t *testing.T) {
+	k, err := NewKeyring("v1", map[string]string{"v1": testKey('a')})
+	require.NoError(t, err)
+	SetDefault(k)
+	defer SetDefault(nil)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&secretRecord{}))
+
+	require.NoError(t, db.Create(&secretRecord{ID: 1, Token: "secret"}).Error)
+
+	var raw string
+	require.NoError(t, db.Table("secret_records").Select("token").Where("id = 1").Scan(&raw).Error)
+	assert.True(t, IsEncrypted(raw))
+
+	var rec secretRecord
+	require.NoError(t, db.First(&rec, 1).Error)
+	assert.Equal(t, "secret", rec.Token)
+
+	// 历史明文可直接读取
+	require.NoError(t, db.Exec("INSERT INTO secret_records (id, token) VALUES (2, 'legacy')").Error)
+	var legacy secretRecord
+	require.NoError(t, db.First(&legacy, 2).Error)
+	assert.Equal(t, "legacy", legacy.Token)
+
+	// 未配置密钥时拒绝写入
+	SetDefault(nil)
+	assert.ErrorIs(t, db.Create(&secretRecord{ID: 3, Token: "secret"}).Error, ErrKeyNotConfigured)
+}
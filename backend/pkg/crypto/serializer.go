@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName GORM 加密序列化器名称，用法：`gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, EncryptedSerializer{})
+}
+
+// EncryptedSerializer 字符串字段透明加解密
+// 写入时使用全局密钥环加密，读取时按密文版本解密；历史明文可直接读取，
+// 通过 cmd/migrate -action encrypt-tokens 批量加密
+type EncryptedSerializer struct{}
+
+// Scan 从数据库读取并解密
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported encrypted column type %T for %s", dbValue, field.Name)
+	}
+
+	plain := stored
+	if IsEncrypted(stored) {
+		k := Default()
+		if k == nil {
+			return fmt.Errorf("decrypt %s: %w", field.Name, ErrKeyNotConfigured)
+		}
+		var err error
+		if plain, err = k.Decrypt(stored); err != nil {
+			return fmt.Errorf("decrypt %s: %w", field.Name, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plain)
+	return nil
+}
+
+// Value 加密后写入数据库
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, _ := fieldValue.(string)
+	if plain == "" {
+		return "", nil
+	}
+	k := Default()
+	if k == nil {
+		return nil, fmt.Errorf("encrypt %s: %w", field.Name, ErrKeyNotConfigured)
+	}
+	return k.Encrypt(plain)
+}
@@ -52,6 +52,12 @@ const (
 	ErrMenuHasChildren = 220003 // 菜单存在子菜单
 )
 
+// 定时任务错误码 (23xxxx)
+const (
+	ErrJobNotFound = 230001 // 定时任务不存在
+	ErrJobPending  = 230002 // 定时任务已在等待执行
+)
+
 // 广告主管理错误码 (30xxxx)
 const (
 	ErrAdvertiserNotFound   = 300001 // 广告主不存在
@@ -93,6 +99,15 @@ const (
 	ErrMaterialUploadFail  = 340002 // 素材上传失败
 	ErrMaterialSizeLimit   = 340003 // 素材大小超限
 	ErrMaterialTypeInvalid = 340004 // 素材类型不支持
+	ErrMaterialNotSynced   = 340005 // 素材未同步到巨量引擎
+	ErrMaterialSpecInvalid = 340006 // 素材不符合投放规格
+)
+
+// 转账错误码 (35xxxx)
+const (
+	ErrTransferNotFound = 350001 // 转账单不存在
+	ErrTransferStatus   = 350002 // 转账单状态不允许该操作
+	ErrTransferApprover = 350003 // 无权审批该转账单
 )
 
 // 报表错误码 (40xxxx)
@@ -110,6 +125,11 @@ const (
 	ErrOERateLimit        = 900004 // 请求频率限制
 	ErrOEParamInvalid     = 900005 // 参数错误
 	ErrOEResourceNotFound = 900006 // 资源不存在
+	ErrOENoPermission     = 900007 // 无权限操作该广告主
+	ErrOEBalanceLow       = 900008 // 账户余额不足
+	ErrOEAdvertiserDenied = 900009 // 广告主状态不可用
+	ErrOEQuotaExceeded    = 900010 // 调用次数超限
+	ErrOESystemBusy       = 900011 // 系统繁忙
 )
 
 // 错误消息映射
@@ -148,6 +168,9 @@ var messages = map[int]string{
 	ErrMenuExists:      "菜单已存在",
 	ErrMenuHasChildren: "菜单存在子菜单，无法删除",
 
+	ErrJobNotFound: "定时任务不存在",
+	ErrJobPending:  "定时任务已在等待或正在执行",
+
 	ErrAdvertiserNotFound:   "广告主不存在",
 	ErrAdvertiserExists:     "广告主已存在",
 	ErrAdvertiserDisabled:   "广告主已禁用",
@@ -175,6 +198,12 @@ var messages = map[int]string{
 	ErrMaterialUploadFail:  "素材上传失败",
 	ErrMaterialSizeLimit:   "素材大小超限",
 	ErrMaterialTypeInvalid: "素材类型不支持",
+	ErrMaterialNotSynced:   "素材未同步到巨量引擎",
+	ErrMaterialSpecInvalid: "素材不符合投放规格",
+
+	ErrTransferNotFound: "转账单不存在",
+	ErrTransferStatus:   "转账单当前状态不允许该操作",
+	ErrTransferApprover: "无权审批该转账单",
 
 	ErrReportQueryFail:  "报表查询失败",
 	ErrReportExportFail: "报表导出失败",
@@ -186,6 +215,11 @@ var messages = map[int]string{
 	ErrOERateLimit:        "请求过于频繁，请稍后再试",
 	ErrOEParamInvalid:     "Ocean Engine 参数错误",
 	ErrOEResourceNotFound: "Ocean Engine 资源不存在",
+	ErrOENoPermission:     "无权限操作该广告主，请检查授权范围",
+	ErrOEBalanceLow:       "广告主账户余额不足",
+	ErrOEAdvertiserDenied: "广告主账户状态异常，暂不可用",
+	ErrOEQuotaExceeded:    "今日接口调用次数已达上限，请明日再试",
+	ErrOESystemBusy:       "巨量引擎系统繁忙，请稍后重试",
 }
 
 // Message 获取错误消息
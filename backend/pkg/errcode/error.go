@@ -55,14 +55,30 @@ func (e *AppError) HTTPStatus() int {
 		return http.StatusOK
 	case e.Code >= 100100 && e.Code < 100200:
 		return http.StatusUnauthorized
-	case e.Code == ErrPermissionDeny:
+	case e.Code == ErrPermissionDeny || e.Code == ErrTransferApprover:
 		return http.StatusForbidden
-	case e.Code == ErrNotFound:
+	case e.Code == ErrNotFound || e.Code == ErrJobNotFound || e.Code == ErrTransferNotFound:
 		return http.StatusNotFound
+	case e.Code == ErrJobPending || e.Code == ErrMaterialNotSynced || e.Code == ErrTransferStatus:
+		return http.StatusConflict
 	case e.Code == ErrTooManyRequest:
 		return http.StatusTooManyRequests
-	case e.Code == ErrInvalidParam || e.Code == ErrAlreadyExists:
+	case e.Code == ErrInvalidParam || e.Code == ErrAlreadyExists ||
+		e.Code == ErrMaterialTypeInvalid || e.Code == ErrMaterialSpecInvalid:
 		return http.StatusBadRequest
+	case e.Code == ErrOETokenInvalid || e.Code == ErrOETokenExpired ||
+		e.Code == ErrOENoPermission || e.Code == ErrOEAdvertiserDenied:
+		return http.StatusForbidden
+	case e.Code == ErrOEParamInvalid || e.Code == ErrOEBalanceLow:
+		return http.StatusBadRequest
+	case e.Code == ErrOERateLimit || e.Code == ErrOEQuotaExceeded:
+		return http.StatusTooManyRequests
+	case e.Code == ErrOEResourceNotFound:
+		return http.StatusNotFound
+	case e.Code == ErrOESystemBusy:
+		return http.StatusServiceUnavailable
+	case e.Code == ErrOEAPIFailed:
+		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,72 @@
+package errcode
+
+import (
+	"errors"
+
+	sdkerrcode "github.com/bububa/oceanengine/marketing-api/model/errcode"
+)
+
+// oceanEngineCategories 巨量引擎返回码分类到应用错误码的映射
+// 返回码目录与分类见 SDK marketing-api/model/errcode，应用维度频控 40110-40119、HTTP 429/5xx 由 Lookup 归类
+var oceanEngineCategories = map[sdkerrcode.Category]int{
+	sdkerrcode.CategoryParam:      ErrOEParamInvalid,
+	sdkerrcode.CategoryPermission: ErrOENoPermission,
+	sdkerrcode.CategoryAuth:       ErrOETokenInvalid,
+	sdkerrcode.CategoryRateLimit:  ErrOERateLimit,
+	sdkerrcode.CategoryQuota:      ErrOEQuotaExceeded,
+	sdkerrcode.CategoryBalance:    ErrOEBalanceLow,
+	sdkerrcode.CategorySystem:     ErrOESystemBusy,
+}
+
+// oceanEngineOverrides 同一分类下需要区分处理的返回码
+var oceanEngineOverrides = map[int]int{
+	sdkerrcode.ErrAccessTokenExpired.Code:    ErrOETokenExpired,
+	sdkerrcode.ErrRefreshTokenExpired.Code:   ErrOETokenExpired,
+	sdkerrcode.ErrAdvertiserUnavailable.Code: ErrOEAdvertiserDenied,
+	sdkerrcode.ErrAdvertiserBlocked.Code:     ErrOEAdvertiserDenied,
+}
+
+// oceanEngineCode 巨量引擎返回码对应的应用错误码，未收录的返回码为 ErrOEAPIFailed
+func oceanEngineCode(oeCode int) int {
+	if code, ok := oceanEngineOverrides[oeCode]; ok {
+		return code
+	}
+	if e, ok := sdkerrcode.Lookup(oeCode); ok {
+		if code, ok := oceanEngineCategories[e.Category]; ok {
+			return code
+		}
+	}
+	return ErrOEAPIFailed
+}
+
+// OceanEngineDetails 巨量引擎原始错误信息
+type OceanEngineDetails struct {
+	Code      int    `json:"oe_code"`
+	Message   string `json:"oe_message,omitempty"`
+	RequestID string `json:"oe_request_id,omitempty"`
+}
+
+// FromOceanEngine 将巨量引擎 API 错误转换为 AppError
+// 错误链中没有巨量引擎返回码时返回 nil
+func FromOceanEngine(err error) *AppError {
+	var coder interface {
+		error
+		ErrorCode() int
+	}
+	if err == nil || !errors.As(err, &coder) {
+		return nil
+	}
+	oeCode := coder.ErrorCode()
+	code := oceanEngineCode(oeCode)
+
+	details := OceanEngineDetails{Code: oeCode, Message: coder.Error()}
+	if m, ok := coder.(interface{ ErrorMessage() string }); ok {
+		details.Message = m.ErrorMessage()
+	}
+	if r, ok := coder.(interface{ APIRequestID() string }); ok {
+		details.RequestID = r.APIRequestID()
+	}
+	appErr := Wrap(code, err)
+	appErr.Details = details
+	return appErr
+}
@@ -0,0 +1,50 @@
+package errcode_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+func TestFromOceanEngine(t *testing.T) {
+	tests := []struct {
+		err    *oceanengine.APIError
+		code   int
+		status int
+	}{
+		{&oceanengine.APIError{Code: 40102}, errcode.ErrOETokenExpired, http.StatusForbidden},
+		{&oceanengine.APIError{Code: 40002}, errcode.ErrOENoPermission, http.StatusForbidden},
+		{&oceanengine.APIError{Code: 40001}, errcode.ErrOEParamInvalid, http.StatusBadRequest},
+		{&oceanengine.APIError{Code: 40201}, errcode.ErrOEBalanceLow, http.StatusBadRequest},
+		{&oceanengine.APIError{Code: 40115}, errcode.ErrOERateLimit, http.StatusTooManyRequests},
+		{&oceanengine.APIError{Code: 61002}, errcode.ErrOEQuotaExceeded, http.StatusTooManyRequests},
+		{&oceanengine.APIError{Code: 51010}, errcode.ErrOESystemBusy, http.StatusServiceUnavailable},
+		{&oceanengine.APIError{HTTPStatus: http.StatusTooManyRequests}, errcode.ErrOERateLimit, http.StatusTooManyRequests},
+		{&oceanengine.APIError{HTTPStatus: http.StatusBadGateway}, errcode.ErrOESystemBusy, http.StatusServiceUnavailable},
+		{&oceanengine.APIError{Code: 40103}, errcode.ErrOETokenExpired, http.StatusForbidden},
+		{&oceanengine.APIError{Code: 40106}, errcode.ErrOETokenInvalid, http.StatusForbidden},
+		{&oceanengine.APIError{Code: 12345}, errcode.ErrOEAPIFailed, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		appErr := errcode.FromOceanEngine(fmt.Errorf("call failed: %w", tt.err))
+		require.NotNil(t, appErr, tt.err.Error())
+		assert.Equal(t, tt.code, appErr.Code, tt.err.Error())
+		assert.Equal(t, tt.status, appErr.HTTPStatus(), tt.err.Error())
+	}
+
+	appErr := errcode.FromOceanEngine(&oceanengine.APIError{Code: 40300, Message: "advertiser disabled", RequestID: "req1"})
+	assert.Equal(t, errcode.OceanEngineDetails{Code: 40300, Message: "advertiser disabled", RequestID: "req1"}, appErr.Details)
+	assert.Equal(t, errcode.Message(errcode.ErrOEAdvertiserDenied), appErr.Message)
+	_, ok := oceanengine.AsAPIError(appErr)
+	assert.True(t, ok)
+
+	assert.Nil(t, errcode.FromOceanEngine(nil))
+	assert.Nil(t, errcode.FromOceanEngine(errors.New("db error")))
+}
@@ -0,0 +1,88 @@
+package mediaprobe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// sniffImage 按文件头识别图片格式，无法识别时返回空
+func sniffImage(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xff, 0xd8, 0xff}):
+		return FormatJPG
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return FormatGIF
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		return FormatWebP
+	}
+	return ""
+}
+
+// ProbeImage 读取图片文件头解析宽高，size 为文件大小
+func ProbeImage(r io.Reader, size int64) (*Info, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(12)
+	format := sniffImage(head)
+	if format == "" {
+		return nil, ErrUnsupported
+	}
+
+	info := &Info{Format: format, Size: size}
+	if format == FormatWebP {
+		if err := probeWebP(br, info); err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(br)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	info.Width, info.Height = cfg.Width, cfg.Height
+	return info, nil
+}
+
+// probeWebP 解析 WebP 第一个 chunk：VP8 (有损)、VP8L (无损) 或 VP8X (扩展格式) 中的画布尺寸
+func probeWebP(r io.Reader, info *Info) error {
+	// RIFF 头 12 字节 + chunk 头 8 字节 + 尺寸字段最多 10 字节
+	buf := make([]byte, 30)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return ErrMalformed
+	}
+	data := buf[20:]
+	switch string(buf[12:16]) {
+	case "VP8 ":
+		if !bytes.Equal(data[3:6], []byte{0x9d, 0x01, 0x2a}) {
+			return ErrMalformed
+		}
+		info.Width = int(binary.LittleEndian.Uint16(data[6:8]) & 0x3fff)
+		info.Height = int(binary.LittleEndian.Uint16(data[8:10]) & 0x3fff)
+	case "VP8L":
+		if data[0] != 0x2f {
+			return ErrMalformed
+		}
+		bits := binary.LittleEndian.Uint32(data[1:5])
+		info.Width = int(bits&0x3fff) + 1
+		info.Height = int(bits>>14&0x3fff) + 1
+	case "VP8X":
+		info.Width = int(uint24(data[4:7])) + 1
+		info.Height = int(uint24(data[7:10])) + 1
+	default:
+		return ErrMalformed
+	}
+	return nil
+}
+
+// uint24 小端序 24 位整数
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
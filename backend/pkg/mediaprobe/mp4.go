@@ -0,0 +1,260 @@
+package mediaprobe
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// box MP4/MOV (ISO BMFF) box，offset/size 为 box 内容的位置与长度 (不含 box 头)
+type box struct {
+	typ    string
+	offset int64
+	size   int64
+}
+
+// track moov/trak 中解析出的轨道信息
+type track struct {
+	handler string // vide、soun
+	codec   string
+	width   int
+	height  int
+	rotated bool // 旋转 90/270 度，显示宽高互换
+}
+
+// ProbeVideo 解析 MP4/MOV 视频的 moov box，size 为文件大小
+// moov 位于 mdat 之后 (未做 faststart) 时跳过 mdat 读取，不会加载整个文件
+func ProbeVideo(r io.ReaderAt, size int64) (*Info, error) {
+	top, err := readBoxes(r, 0, size)
+	if err != nil {
+		return nil, err
+	}
+	if len(top) == 0 || top[0].typ != "ftyp" {
+		return nil, ErrUnsupported
+	}
+	brand, err := readBox(r, top[0], 4)
+	if err != nil || len(brand) < 4 {
+		return nil, ErrMalformed
+	}
+	info := &Info{Format: FormatMP4, Size: size}
+	if string(brand) == "qt  " {
+		info.Format = FormatMOV
+	}
+
+	moov := findBox(top, "moov")
+	if moov == nil {
+		return nil, ErrMalformed
+	}
+	children, err := readBoxes(r, moov.offset, moov.offset+moov.size)
+	if err != nil {
+		return nil, err
+	}
+	mvhd := findBox(children, "mvhd")
+	if mvhd == nil {
+		return nil, ErrMalformed
+	}
+	if info.Duration, err = parseMvhd(r, *mvhd); err != nil {
+		return nil, err
+	}
+
+	var video *track
+	for _, b := range children {
+		if b.typ != "trak" {
+			continue
+		}
+		t, err := parseTrak(r, b)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.handler == "vide" && video == nil:
+			video = t
+		case t.handler == "soun" && info.AudioCodec == "":
+			info.AudioCodec = t.codec
+		}
+	}
+	if video == nil {
+		return nil, ErrUnsupported
+	}
+
+	info.Codec, info.Width, info.Height = video.codec, video.width, video.height
+	if video.rotated {
+		info.Width, info.Height = info.Height, info.Width
+	}
+	if info.Duration > 0 {
+		info.BitRate = int(float64(size) * 8 / info.Duration)
+	}
+	return info, nil
+}
+
+// parseMvhd 解析影片时长（秒）
+func parseMvhd(r io.ReaderAt, b box) (float64, error) {
+	data, err := readBox(r, b, 32)
+	if err != nil || len(data) < 20 {
+		return 0, ErrMalformed
+	}
+	var timescale uint32
+	var duration uint64
+	if data[0] == 1 {
+		if len(data) < 32 {
+			return 0, ErrMalformed
+		}
+		timescale = binary.BigEndian.Uint32(data[20:24])
+		duration = binary.BigEndian.Uint64(data[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(data[12:16])
+		duration = uint64(binary.BigEndian.Uint32(data[16:20]))
+	}
+	if timescale == 0 {
+		return 0, ErrMalformed
+	}
+	return float64(duration) / float64(timescale), nil
+}
+
+// parseTrak 解析轨道类型、编码与尺寸：tkhd 提供显示尺寸与旋转矩阵，mdia/hdlr 提供轨道类型，mdia/minf/stbl/stsd 提供编码与编码尺寸
+func parseTrak(r io.ReaderAt, trak box) (*track, error) {
+	children, err := readBoxes(r, trak.offset, trak.offset+trak.size)
+	if err != nil {
+		return nil, err
+	}
+	t := &track{}
+
+	if tkhd := findBox(children, "tkhd"); tkhd != nil {
+		data, err := readBox(r, *tkhd, 96)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		base := 24
+		if len(data) > 0 && data[0] == 1 {
+			base = 36
+		}
+		if len(data) >= base+60 {
+			matrix := data[base+16 : base+52]
+			a := int32(binary.BigEndian.Uint32(matrix[0:4]))
+			b := int32(binary.BigEndian.Uint32(matrix[4:8]))
+			d := int32(binary.BigEndian.Uint32(matrix[16:20]))
+			t.rotated = a == 0 && d == 0 && b != 0
+			t.width = int(binary.BigEndian.Uint32(data[base+52:base+56]) >> 16)
+			t.height = int(binary.BigEndian.Uint32(data[base+56:base+60]) >> 16)
+		}
+	}
+
+	mdia := findBox(children, "mdia")
+	if mdia == nil {
+		return t, nil
+	}
+	mdiaChildren, err := readBoxes(r, mdia.offset, mdia.offset+mdia.size)
+	if err != nil {
+		return nil, err
+	}
+	if hdlr := findBox(mdiaChildren, "hdlr"); hdlr != nil {
+		data, err := readBox(r, *hdlr, 12)
+		if err != nil || len(data) < 12 {
+			return nil, ErrMalformed
+		}
+		t.handler = string(data[8:12])
+	}
+
+	stsd, err := findPath(r, mdiaChildren, "minf", "stbl", "stsd")
+	if err != nil || stsd == nil {
+		return t, err
+	}
+	// stsd: version/flags(4) entry_count(4)，第一个 sample entry: size(4) format(4) reserved(6) data_reference_index(2)
+	// 视频 sample entry 随后为 pre_defined/reserved(16) width(2) height(2)
+	data, err := readBox(r, *stsd, 44)
+	if err != nil || len(data) < 16 {
+		return nil, ErrMalformed
+	}
+	t.codec = codecName(string(data[12:16]))
+	if t.handler == "vide" && len(data) >= 44 && (t.width == 0 || t.height == 0) {
+		t.width = int(binary.BigEndian.Uint16(data[40:42]))
+		t.height = int(binary.BigEndian.Uint16(data[42:44]))
+	}
+	return t, nil
+}
+
+// codecName 将 sample entry 类型转换为常用编码名
+func codecName(fourcc string) string {
+	switch fourcc {
+	case "avc1", "avc3":
+		return "h264"
+	case "hvc1", "hev1":
+		return "hevc"
+	case "av01":
+		return "av1"
+	case "vp09":
+		return "vp9"
+	case "mp4v":
+		return "mpeg4"
+	case "mp4a":
+		return "aac"
+	}
+	return strings.TrimSpace(fourcc)
+}
+
+// readBoxes 读取 [start, end) 范围内的同级 box
+func readBoxes(r io.ReaderAt, start, end int64) ([]box, error) {
+	var boxes []box
+	header := make([]byte, 16)
+	for pos := start; pos+8 <= end; {
+		if _, err := r.ReadAt(header[:8], pos); err != nil {
+			return nil, ErrMalformed
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		headerLen := int64(8)
+		switch size {
+		case 0:
+			// 延伸到文件末尾
+			size = end - pos
+		case 1:
+			if _, err := r.ReadAt(header[8:16], pos+8); err != nil {
+				return nil, ErrMalformed
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerLen = 16
+		}
+		if size < headerLen || size > end-pos {
+			return nil, ErrMalformed
+		}
+		boxes = append(boxes, box{typ: string(header[4:8]), offset: pos + headerLen, size: size - headerLen})
+		pos += size
+	}
+	return boxes, nil
+}
+
+// readBox 读取 box 内容的前 n 个字节
+func readBox(r io.ReaderAt, b box, n int) ([]byte, error) {
+	if int64(n) > b.size {
+		n = int(b.size)
+	}
+	data := make([]byte, n)
+	if _, err := r.ReadAt(data, b.offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// findBox 查找指定类型的第一个 box
+func findBox(boxes []box, typ string) *box {
+	for i := range boxes {
+		if boxes[i].typ == typ {
+			return &boxes[i]
+		}
+	}
+	return nil
+}
+
+// findPath 按路径逐层查找 box，路径中任一层不存在时返回 nil
+func findPath(r io.ReaderAt, boxes []box, path ...string) (*box, error) {
+	for i, typ := range path {
+		b := findBox(boxes, typ)
+		if b == nil || i == len(path)-1 {
+			return b, nil
+		}
+		var err error
+		if boxes, err = readBoxes(r, b.offset, b.offset+b.size); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,63 @@
+// Package mediaprobe 本地解析素材文件的宽高、时长、编码与码率
+// 图片只读取文件头 (JPEG/PNG/GIF/WebP)，视频解析 MP4/MOV 的 moov box，不依赖 ffprobe 等外部程序
+package mediaprobe
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var (
+	ErrUnsupported = errors.New("mediaprobe: unsupported format")
+	ErrMalformed   = errors.New("mediaprobe: malformed file")
+)
+
+// 素材格式
+const (
+	FormatJPG  = "jpg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+	FormatMP4  = "mp4"
+	FormatMOV  = "mov"
+)
+
+// Info 素材信息
+type Info struct {
+	Format     string  `json:"format"`
+	Size       int64   `json:"size"`        // 文件大小（字节）
+	Width      int     `json:"width"`       // 显示宽度，视频已按旋转角度修正
+	Height     int     `json:"height"`      // 显示高度
+	Duration   float64 `json:"duration"`    // 时长（秒），图片为 0
+	Codec      string  `json:"codec"`       // 视频编码，如 h264、hevc
+	AudioCodec string  `json:"audio_codec"` // 音频编码，如 mp4a，无音轨时为空
+	BitRate    int     `json:"bit_rate"`    // 平均码率（bps），文件大小 / 时长
+}
+
+// IsVideo 是否为视频
+func (i *Info) IsVideo() bool {
+	return i.Format == FormatMP4 || i.Format == FormatMOV
+}
+
+// Probe 按文件内容识别格式并解析素材信息，不支持的格式返回 ErrUnsupported
+func Probe(r io.ReaderAt, size int64) (*Info, error) {
+	head := make([]byte, 12)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	if len(head) >= 8 && string(head[4:8]) == "ftyp" {
+		return ProbeVideo(r, size)
+	}
+	if sniffImage(head) != "" {
+		return ProbeImage(io.NewSectionReader(r, 0, size), size)
+	}
+	return nil, ErrUnsupported
+}
+
+// ProbeBytes 解析内存中的素材文件
+func ProbeBytes(data []byte) (*Info, error) {
+	return Probe(bytes.NewReader(data), int64(len(data)))
+}
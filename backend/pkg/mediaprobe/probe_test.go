@@ -0,0 +1,190 @@
+package mediaprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.Black})
+	var buf bytes.Buffer
+	switch format {
+	case FormatPNG:
+		require.NoError(t, png.Encode(&buf, img))
+	case FormatJPG:
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	case FormatGIF:
+		require.NoError(t, gif.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+// webpFile 构造只包含第一个 chunk 的 WebP 文件头
+func webpFile(chunk string, data []byte) []byte {
+	buf := []byte("RIFF\x00\x00\x00\x00WEBP" + chunk)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+func TestProbeImage(t *testing.T) {
+	vp8l := append(binary.LittleEndian.AppendUint32([]byte{0x2f}, uint32(1280-1)|uint32(720-1)<<14), 0, 0, 0, 0, 0)
+	vp8x := []byte{0, 0, 0, 0, 0xcf, 0x02, 0x00, 0xff, 0x04, 0x00}   // 720x1280
+	vp8 := []byte{0, 0, 0, 0x9d, 0x01, 0x2a, 0xc8, 0x01, 0x2c, 0x01} // 456x300
+
+	tests := []struct {
+		name          string
+		data          []byte
+		format        string
+		width, height int
+	}{
+		{"png", encodeImage(t, FormatPNG, 1280, 720), FormatPNG, 1280, 720},
+		{"jpeg", encodeImage(t, FormatJPG, 720, 1280), FormatJPG, 720, 1280},
+		{"gif", encodeImage(t, FormatGIF, 456, 300), FormatGIF, 456, 300},
+		{"webp lossless", webpFile("VP8L", vp8l), FormatWebP, 1280, 720},
+		{"webp extended", webpFile("VP8X", vp8x), FormatWebP, 720, 1280},
+		{"webp lossy", webpFile("VP8 ", vp8), FormatWebP, 456, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ProbeBytes(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, info.Format)
+			assert.Equal(t, tt.width, info.Width)
+			assert.Equal(t, tt.height, info.Height)
+			assert.Equal(t, int64(len(tt.data)), info.Size)
+			assert.False(t, info.IsVideo())
+		})
+	}
+
+	_, err := ProbeBytes([]byte("plain text"))
+	assert.ErrorIs(t, err, ErrUnsupported)
+	_, err = ProbeBytes(encodeImage(t, FormatPNG, 10, 10)[:12])
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func mp4Box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	buf := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(buf, typ...), body...)
+}
+
+// testTrack 构造 trak box，rotate 为 true 时写入 90 度旋转矩阵
+func testTrack(handler, fourcc string, width, height int, rotate bool) []byte {
+	a, b, c, d := uint32(0x10000), uint32(0), uint32(0), uint32(0x10000)
+	if rotate {
+		a, b, c, d = 0, 0x10000, 0xffff0000, 0
+	}
+	tkhd := make([]byte, 24+16)
+	for _, v := range []uint32{a, b, 0, c, d, 0, 0, 0, 0x40000000} {
+		tkhd = binary.BigEndian.AppendUint32(tkhd, v)
+	}
+	tkhd = binary.BigEndian.AppendUint32(tkhd, uint32(width)<<16)
+	tkhd = binary.BigEndian.AppendUint32(tkhd, uint32(height)<<16)
+
+	hdlr := append(make([]byte, 8), handler...)
+	hdlr = append(hdlr, make([]byte, 13)...)
+
+	entry := append(make([]byte, 32), byte(width>>8), byte(width), byte(height>>8), byte(height))
+	entry = append(entry, make([]byte, 50)...)
+	stsd := binary.BigEndian.AppendUint32(make([]byte, 4), 1)
+	stsd = append(stsd, mp4Box(fourcc, entry[8:])...)
+
+	return mp4Box("trak", mp4Box("tkhd", tkhd),
+		mp4Box("mdia", mp4Box("hdlr", hdlr), mp4Box("minf", mp4Box("stbl", mp4Box("stsd", stsd)))))
+}
+
+// testMP4 构造视频文件，mdat 填充 payload 字节；moovLast 为 true 时 moov 位于 mdat 之后
+func testMP4(brand string, duration float64, payload int, moovLast bool, tracks ...[]byte) []byte {
+	mvhd := make([]byte, 12)
+	mvhd = binary.BigEndian.AppendUint32(mvhd, 1000)
+	mvhd = binary.BigEndian.AppendUint32(mvhd, uint32(duration*1000))
+	mvhd = append(mvhd, make([]byte, 80)...)
+	moov := mp4Box("moov", append([][]byte{mp4Box("mvhd", mvhd)}, tracks...)...)
+	ftyp := mp4Box("ftyp", []byte(brand), make([]byte, 4), []byte("isom"))
+	mdat := mp4Box("mdat", make([]byte, payload))
+	if moovLast {
+		return bytes.Join([][]byte{ftyp, mdat, moov}, nil)
+	}
+	return bytes.Join([][]byte{ftyp, moov, mdat}, nil)
+}
+
+func TestProbeVideo(t *testing.T) {
+	data := testMP4("isom", 15, 2*mb, false,
+		testTrack("vide", "avc1", 1920, 1080, false),
+		testTrack("soun", "mp4a", 0, 0, false))
+	info, err := ProbeBytes(data)
+	require.NoError(t, err)
+	assert.Equal(t, FormatMP4, info.Format)
+	assert.Equal(t, 1920, info.Width)
+	assert.Equal(t, 1080, info.Height)
+	assert.Equal(t, 15.0, info.Duration)
+	assert.Equal(t, "h264", info.Codec)
+	assert.Equal(t, "aac", info.AudioCodec)
+	assert.Equal(t, int(float64(len(data))*8/15), info.BitRate)
+	assert.True(t, info.IsVideo())
+
+	// 手机竖拍视频以横向编码并写入旋转矩阵，moov 位于文件末尾
+	data = testMP4("qt  ", 30.5, 1024, true, testTrack("vide", "hvc1", 1920, 1080, true))
+	info, err = ProbeBytes(data)
+	require.NoError(t, err)
+	assert.Equal(t, FormatMOV, info.Format)
+	assert.Equal(t, 1080, info.Width)
+	assert.Equal(t, 1920, info.Height)
+	assert.Equal(t, 30.5, info.Duration)
+	assert.Equal(t, "hevc", info.Codec)
+	assert.Empty(t, info.AudioCodec)
+
+	// 只有音轨
+	_, err = ProbeBytes(testMP4("M4A ", 10, 0, false, testTrack("soun", "mp4a", 0, 0, false)))
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	// 文件不完整
+	_, err = ProbeBytes(data[:len(data)-20])
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestSpecValidate(t *testing.T) {
+	fields := func(violations []Violation) []string {
+		result := make([]string, len(violations))
+		for i, v := range violations {
+			result[i] = v.Field
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		platform string
+		info     Info
+		spec     string
+		fields   []string
+	}{
+		{"横版大图", PlatformOceanEngine, Info{Format: FormatJPG, Size: mb, Width: 1920, Height: 1080}, "巨量广告图片", []string{}},
+		{"横版小图分辨率不足", PlatformOceanEngine, Info{Format: FormatPNG, Size: mb, Width: 380, Height: 250}, "巨量广告图片", []string{"width", "height"}},
+		{"宽高比不符", PlatformOceanEngine, Info{Format: FormatWebP, Size: 6 * mb, Width: 1000, Height: 1000}, "巨量广告图片", []string{"format", "size", "aspect_ratio"}},
+		{"千川方形图片", PlatformQianchuan, Info{Format: FormatPNG, Size: mb, Width: 800, Height: 800}, "千川图片", []string{}},
+		{"竖版视频", PlatformOceanEngine, Info{Format: FormatMP4, Size: 10 * mb, Width: 1080, Height: 1920, Duration: 30, Codec: "h264", BitRate: 2000000}, "巨量广告竖版视频", []string{}},
+		{"横版视频过短且码率低", PlatformOceanEngine, Info{Format: FormatMOV, Size: mb, Width: 1280, Height: 720, Duration: 3, Codec: "prores", BitRate: 300000}, "巨量广告横版视频", []string{"duration", "bit_rate", "codec"}},
+		{"千川竖版视频", PlatformQianchuan, Info{Format: FormatMP4, Size: 10 * mb, Width: 540, Height: 960, Duration: 500, Codec: "hevc", BitRate: 1000000}, "千川竖版视频", []string{}},
+		{"巨量广告视频超长", PlatformOceanEngine, Info{Format: FormatMP4, Size: 10 * mb, Width: 540, Height: 960, Duration: 500, Codec: "hevc", BitRate: 1000000}, "巨量广告竖版视频", []string{"width", "height", "duration"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := SpecFor(tt.platform, &tt.info)
+			require.NotNil(t, spec)
+			assert.Equal(t, tt.spec, spec.Name)
+			assert.Equal(t, tt.fields, fields(spec.Validate(&tt.info)))
+		})
+	}
+
+	assert.Nil(t, SpecFor("unknown", &Info{Format: FormatJPG}))
+}
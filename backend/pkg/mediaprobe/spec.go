@@ -0,0 +1,256 @@
+package mediaprobe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// 投放平台
+const (
+	PlatformOceanEngine = "oceanengine" // 巨量广告
+	PlatformQianchuan   = "qianchuan"   // 巨量千川
+)
+
+// ratioTolerance 宽高比允许的相对误差
+const ratioTolerance = 0.03
+
+const mb = 1 << 20
+
+// Layout 素材版式，宽高比在容差内即视为该版式
+type Layout struct {
+	Name      string
+	RatioW    int
+	RatioH    int
+	MinWidth  int
+	MinHeight int
+}
+
+// Spec 素材投放规格，零值字段不校验
+type Spec struct {
+	Name        string
+	Formats     []string
+	MaxSize     int64
+	Layouts     []Layout
+	MinDuration float64 // 秒
+	MaxDuration float64
+	MinBitRate  int // bps
+	Codecs      []string
+}
+
+// Violation 素材不符合规格的项
+type Violation struct {
+	Field   string `json:"field"`   // format、size、aspect_ratio、width、height、duration、bit_rate、codec
+	Rule    string `json:"rule"`    // 规则，如 min=1280、max=300、in=16:9,9:16
+	Actual  string `json:"actual"`  // 实际值
+	Message string `json:"message"` // 说明
+}
+
+// platformSpecs 平台素材规格，视频按横版/竖版区分
+type platformSpecs struct {
+	image      *Spec
+	vertical   *Spec
+	horizontal *Spec
+}
+
+// specs 各平台默认素材规格
+var specs = map[string]platformSpecs{
+	PlatformOceanEngine: {
+		image: &Spec{
+			Name:    "巨量广告图片",
+			Formats: []string{FormatJPG, FormatPNG, FormatGIF},
+			MaxSize: 5 * mb,
+			Layouts: []Layout{
+				{Name: "横版大图", RatioW: 16, RatioH: 9, MinWidth: 1280, MinHeight: 720},
+				{Name: "竖版大图", RatioW: 9, RatioH: 16, MinWidth: 720, MinHeight: 1280},
+				{Name: "横版小图", RatioW: 38, RatioH: 25, MinWidth: 456, MinHeight: 300},
+			},
+		},
+		vertical: &Spec{
+			Name:        "巨量广告竖版视频",
+			Formats:     []string{FormatMP4, FormatMOV},
+			MaxSize:     1000 * mb,
+			Layouts:     []Layout{{Name: "竖版视频", RatioW: 9, RatioH: 16, MinWidth: 720, MinHeight: 1280}},
+			MinDuration: 4,
+			MaxDuration: 300,
+			MinBitRate:  516 * 1000,
+			Codecs:      []string{"h264", "hevc"},
+		},
+		horizontal: &Spec{
+			Name:        "巨量广告横版视频",
+			Formats:     []string{FormatMP4, FormatMOV},
+			MaxSize:     1000 * mb,
+			Layouts:     []Layout{{Name: "横版视频", RatioW: 16, RatioH: 9, MinWidth: 1280, MinHeight: 720}},
+			MinDuration: 4,
+			MaxDuration: 300,
+			MinBitRate:  516 * 1000,
+			Codecs:      []string{"h264", "hevc"},
+		},
+	},
+	PlatformQianchuan: {
+		image: &Spec{
+			Name:    "千川图片",
+			Formats: []string{FormatJPG, FormatPNG},
+			MaxSize: 5 * mb,
+			Layouts: []Layout{
+				{Name: "横版图片", RatioW: 16, RatioH: 9, MinWidth: 1280, MinHeight: 720},
+				{Name: "竖版图片", RatioW: 9, RatioH: 16, MinWidth: 720, MinHeight: 1280},
+				{Name: "方形图片", RatioW: 1, RatioH: 1, MinWidth: 800, MinHeight: 800},
+			},
+		},
+		vertical: &Spec{
+			Name:        "千川竖版视频",
+			Formats:     []string{FormatMP4, FormatMOV},
+			MaxSize:     500 * mb,
+			Layouts:     []Layout{{Name: "竖版视频", RatioW: 9, RatioH: 16, MinWidth: 540, MinHeight: 960}},
+			MinDuration: 4,
+			MaxDuration: 600,
+			MinBitRate:  516 * 1000,
+			Codecs:      []string{"h264", "hevc"},
+		},
+		horizontal: &Spec{
+			Name:        "千川横版视频",
+			Formats:     []string{FormatMP4, FormatMOV},
+			MaxSize:     500 * mb,
+			Layouts:     []Layout{{Name: "横版视频", RatioW: 16, RatioH: 9, MinWidth: 960, MinHeight: 540}},
+			MinDuration: 4,
+			MaxDuration: 600,
+			MinBitRate:  516 * 1000,
+			Codecs:      []string{"h264", "hevc"},
+		},
+	},
+}
+
+// SpecFor 按平台与素材信息选择规格：图片使用平台图片规格，视频按宽高区分竖版/横版，未知平台返回 nil
+func SpecFor(platform string, info *Info) *Spec {
+	p, ok := specs[platform]
+	if !ok {
+		return nil
+	}
+	switch {
+	case !info.IsVideo():
+		return p.image
+	case info.Height > info.Width:
+		return p.vertical
+	default:
+		return p.horizontal
+	}
+}
+
+// Validate 校验素材是否符合规格，返回全部不符合项
+func (s *Spec) Validate(info *Info) []Violation {
+	var violations []Violation
+	if len(s.Formats) > 0 && !contains(s.Formats, info.Format) {
+		violations = append(violations, Violation{
+			Field:   "format",
+			Rule:    "in=" + strings.Join(s.Formats, ","),
+			Actual:  info.Format,
+			Message: fmt.Sprintf("%s不支持 %s 格式", s.Name, info.Format),
+		})
+	}
+	if s.MaxSize > 0 && info.Size > s.MaxSize {
+		violations = append(violations, Violation{
+			Field:   "size",
+			Rule:    fmt.Sprintf("max=%d", s.MaxSize),
+			Actual:  fmt.Sprintf("%d", info.Size),
+			Message: fmt.Sprintf("文件大小超过 %dMB", s.MaxSize/mb),
+		})
+	}
+	violations = append(violations, s.validateLayout(info)...)
+
+	if !info.IsVideo() {
+		return violations
+	}
+	if s.MinDuration > 0 && info.Duration < s.MinDuration {
+		violations = append(violations, Violation{
+			Field:   "duration",
+			Rule:    fmt.Sprintf("min=%g", s.MinDuration),
+			Actual:  fmt.Sprintf("%.2f", info.Duration),
+			Message: fmt.Sprintf("视频时长 %.1f 秒，不能短于 %g 秒", info.Duration, s.MinDuration),
+		})
+	}
+	if s.MaxDuration > 0 && info.Duration > s.MaxDuration {
+		violations = append(violations, Violation{
+			Field:   "duration",
+			Rule:    fmt.Sprintf("max=%g", s.MaxDuration),
+			Actual:  fmt.Sprintf("%.2f", info.Duration),
+			Message: fmt.Sprintf("视频时长 %.1f 秒，不能超过 %g 秒", info.Duration, s.MaxDuration),
+		})
+	}
+	if s.MinBitRate > 0 && info.BitRate < s.MinBitRate {
+		violations = append(violations, Violation{
+			Field:   "bit_rate",
+			Rule:    fmt.Sprintf("min=%d", s.MinBitRate),
+			Actual:  fmt.Sprintf("%d", info.BitRate),
+			Message: fmt.Sprintf("视频码率 %dkbps，不能低于 %dkbps", info.BitRate/1000, s.MinBitRate/1000),
+		})
+	}
+	if len(s.Codecs) > 0 && !contains(s.Codecs, info.Codec) {
+		violations = append(violations, Violation{
+			Field:   "codec",
+			Rule:    "in=" + strings.Join(s.Codecs, ","),
+			Actual:  info.Codec,
+			Message: fmt.Sprintf("不支持 %s 视频编码", info.Codec),
+		})
+	}
+	return violations
+}
+
+// validateLayout 按宽高比匹配版式，再校验该版式的最小分辨率
+func (s *Spec) validateLayout(info *Info) []Violation {
+	if len(s.Layouts) == 0 {
+		return nil
+	}
+	actual := fmt.Sprintf("%dx%d", info.Width, info.Height)
+	var layout *Layout
+	if info.Width > 0 && info.Height > 0 {
+		ratio := float64(info.Width) / float64(info.Height)
+		for i := range s.Layouts {
+			want := float64(s.Layouts[i].RatioW) / float64(s.Layouts[i].RatioH)
+			if math.Abs(ratio-want)/want <= ratioTolerance {
+				layout = &s.Layouts[i]
+				break
+			}
+		}
+	}
+	if layout == nil {
+		ratios := make([]string, len(s.Layouts))
+		for i, l := range s.Layouts {
+			ratios[i] = fmt.Sprintf("%d:%d", l.RatioW, l.RatioH)
+		}
+		return []Violation{{
+			Field:   "aspect_ratio",
+			Rule:    "in=" + strings.Join(ratios, ","),
+			Actual:  actual,
+			Message: fmt.Sprintf("宽高比不符合%s要求，支持 %s", s.Name, strings.Join(ratios, "、")),
+		}}
+	}
+
+	var violations []Violation
+	if info.Width < layout.MinWidth {
+		violations = append(violations, Violation{
+			Field:   "width",
+			Rule:    fmt.Sprintf("min=%d", layout.MinWidth),
+			Actual:  fmt.Sprintf("%d", info.Width),
+			Message: fmt.Sprintf("%s宽度不能小于 %d", layout.Name, layout.MinWidth),
+		})
+	}
+	if info.Height < layout.MinHeight {
+		violations = append(violations, Violation{
+			Field:   "height",
+			Rule:    fmt.Sprintf("min=%d", layout.MinHeight),
+			Actual:  fmt.Sprintf("%d", info.Height),
+			Message: fmt.Sprintf("%s高度不能小于 %d", layout.Name, layout.MinHeight),
+		})
+	}
+	return violations
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
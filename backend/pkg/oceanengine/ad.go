@@ -75,7 +75,7 @@ func (s *AdService) Create(ctx context.Context, req *AdCreateRequest) (*AdCreate
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AdCreateResponse
@@ -114,7 +114,7 @@ func (s *AdService) Update(ctx context.Context, req *AdUpdateRequest) (*AdUpdate
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AdUpdateResponse
@@ -149,7 +149,7 @@ func (s *AdService) UpdateBudget(ctx context.Context, req *AdUpdateBudgetRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -180,7 +180,7 @@ func (s *AdService) UpdateBid(ctx context.Context, req *AdUpdateBidRequest) ([]A
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -237,7 +237,7 @@ func (s *AdService) GetDetail(ctx context.Context, req *AdDetailRequest) ([]AdDe
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -272,7 +272,7 @@ func (s *AdService) GetCostProtectStatus(ctx context.Context, req *AdCostProtect
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -305,7 +305,7 @@ func (s *AdService) GetRejectReason(ctx context.Context, req *AdRejectReasonRequ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
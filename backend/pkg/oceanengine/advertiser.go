@@ -51,7 +51,7 @@ func (s *AdvertiserService) GetInfo(ctx context.Context, advertiserIDs []int64)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AdvertiserInfoResponse
@@ -91,7 +91,7 @@ func (s *AdvertiserService) GetFund(ctx context.Context, advertiserIDs []int64)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result FundInfoResponse
@@ -145,7 +145,7 @@ func (s *AdvertiserService) GetFundTransaction(ctx context.Context, req *FundTra
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result FundTransactionResponse
@@ -68,7 +68,7 @@ func (s *AdvToolsService) GetRtaInfo(ctx context.Context, req *RtaGetInfoRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result RtaGetInfoResponse
@@ -96,7 +96,7 @@ func (s *AdvToolsService) GetAvailableRta(ctx context.Context, req *RtaGetReques
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -124,7 +124,7 @@ func (s *AdvToolsService) UpdateRtaStatus(ctx context.Context, req *RtaStatusUpd
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -146,7 +146,7 @@ func (s *AdvToolsService) SetRtaScope(ctx context.Context, req *RtaSetScopeReque
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -180,7 +180,7 @@ func (s *AdvToolsService) GetRtaScope(ctx context.Context, req *RtaScopeGetReque
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -218,7 +218,7 @@ func (s *AdvToolsService) SetAdRaise(ctx context.Context, req *AdRaiseSetRequest
 	}
 
 	if !resp.IsSuccess() {
-		return "", fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return "", resp.Err()
 	}
 
 	var result struct {
@@ -252,7 +252,7 @@ func (s *AdvToolsService) GetAdRaiseEstimate(ctx context.Context, req *AdRaiseEs
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -284,7 +284,7 @@ func (s *AdvToolsService) GetAdRaiseStatus(ctx context.Context, req *AdRaiseStat
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result map[int64]string
@@ -326,7 +326,7 @@ func (s *AdvToolsService) GetAdRaiseResult(ctx context.Context, req *AdRaiseResu
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -364,7 +364,7 @@ func (s *AdvToolsService) GetSuggestBudget(ctx context.Context, req *SuggestBudg
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -438,7 +438,7 @@ func (s *AdvToolsService) GetAudiencePackage(ctx context.Context, req *AudienceP
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AudiencePackageGetResponse
@@ -469,7 +469,7 @@ func (s *AdvToolsService) CreateAudiencePackage(ctx context.Context, req *Audien
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -501,7 +501,7 @@ func (s *AdvToolsService) UpdateAudiencePackage(ctx context.Context, req *Audien
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -528,7 +528,7 @@ func (s *AdvToolsService) DeleteAudiencePackage(ctx context.Context, req *Audien
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -556,7 +556,7 @@ func (s *AdvToolsService) BindAudiencePackage(ctx context.Context, req *AdBindRe
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -577,7 +577,7 @@ func (s *AdvToolsService) UnbindAudiencePackage(ctx context.Context, req *AdBind
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -647,7 +647,7 @@ func (s *AdvToolsService) GetNativeAnchor(ctx context.Context, req *NativeAnchor
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result NativeAnchorGetResponse
@@ -677,7 +677,7 @@ func (s *AdvToolsService) GetNativeAnchorDetail(ctx context.Context, req *Native
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result NativeAnchor
@@ -709,7 +709,7 @@ func (s *AdvToolsService) CreateNativeAnchor(ctx context.Context, req *NativeAnc
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result NativeAnchorCreateResponse
@@ -741,7 +741,7 @@ func (s *AdvToolsService) UpdateNativeAnchor(ctx context.Context, req *NativeAnc
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result NativeAnchorUpdateResponse
@@ -766,7 +766,7 @@ func (s *AdvToolsService) DeleteNativeAnchor(ctx context.Context, req *NativeAnc
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -808,7 +808,7 @@ func (s *AdvToolsService) GetDiagnosisSuggestion(ctx context.Context, req *Diagn
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result DiagnosisSuggestionGetResponse
@@ -840,7 +840,7 @@ func (s *AdvToolsService) AcceptDiagnosisSuggestion(ctx context.Context, req *Di
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result DiagnosisSuggestionAcceptResponse
@@ -876,7 +876,7 @@ func (s *AdvToolsService) GetQuota(ctx context.Context, req *QuotaGetRequest) (*
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result QuotaInfo
@@ -913,7 +913,7 @@ func (s *AdvToolsService) GetAdQuality(ctx context.Context, req *AdQualityGetReq
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -952,7 +952,7 @@ func (s *AdvToolsService) GetAdStatExtraInfo(ctx context.Context, req *AdStatExt
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -211,7 +211,7 @@ func (s *AgentService) GetAgentFundFlow(ctx context.Context, accessToken string,
 	}
 
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, 0, resp.Err()
 	}
 
 	var result struct {
@@ -340,7 +340,7 @@ func (s *AgentService) GetDailyFundStat(ctx context.Context, accessToken string,
 	}
 
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, 0, resp.Err()
 	}
 
 	var result struct {
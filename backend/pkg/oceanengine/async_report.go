@@ -1,6 +1,7 @@
 package oceanengine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -51,7 +52,7 @@ func (s *AsyncReportService) CreateTask(ctx context.Context, req *AsyncReportTas
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AsyncReportTaskCreateResponse
@@ -105,7 +106,7 @@ func (s *AsyncReportService) GetTasks(ctx context.Context, req *AsyncReportTaskG
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AsyncReportTaskGetResponse
@@ -120,6 +121,8 @@ func (s *AsyncReportService) GetTasks(ctx context.Context, req *AsyncReportTaskG
 type AsyncReportTaskDownloadRequest struct {
 	AdvertiserID int64  `json:"advertiser_id"`
 	TaskID       string `json:"task_id"`
+	Page         int    `json:"page,omitempty"`
+	PageSize     int    `json:"page_size,omitempty"`
 }
 
 // AsyncReportTaskDownloadResponse 下载异步报表任务响应
@@ -141,11 +144,14 @@ func (s *AsyncReportService) Download(ctx context.Context, req *AsyncReportTaskD
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
+	// 报表行中的广告ID等超出 float64 精度，按 json.Number 保留原始数字
 	var result AsyncReportTaskDownloadResponse
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(resp.Data))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
 		return nil, fmt.Errorf("unmarshal data failed: %w", err)
 	}
 
@@ -190,7 +196,7 @@ func (s *AsyncReportService) GetIntegratedReport(ctx context.Context, req *Integ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result IntegratedReportResponse
@@ -231,7 +237,7 @@ func (s *AsyncReportService) GetVideoAnalysisReport(ctx context.Context, req *Vi
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result VideoAnalysisReportResponse
@@ -270,7 +276,7 @@ func (s *AsyncReportService) GetAudienceAnalysisReport(ctx context.Context, req
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AudienceAnalysisReportResponse
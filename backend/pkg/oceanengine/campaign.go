@@ -63,7 +63,7 @@ func (s *CampaignService) GetList(ctx context.Context, req *CampaignListRequest)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CampaignListResponse
@@ -98,7 +98,7 @@ func (s *CampaignService) Create(ctx context.Context, req *CampaignCreateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CampaignCreateResponse
@@ -126,7 +126,7 @@ func (s *CampaignService) Update(ctx context.Context, req *CampaignUpdateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -147,7 +147,7 @@ func (s *CampaignService) UpdateStatus(ctx context.Context, req *CampaignStatusU
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -213,7 +213,7 @@ func (s *AdService) GetList(ctx context.Context, req *AdListRequest) (*AdListRes
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AdListResponse
@@ -239,7 +239,7 @@ func (s *AdService) UpdateStatus(ctx context.Context, req *AdStatusUpdateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -6,17 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 )
 
 const (
-	// BaseURL Ocean Engine API 基础地址
+	// BaseURL Ocean Engine API 默认基础地址
 	BaseURL = "https://ad.oceanengine.com/open_api"
+	// AuthURL Ocean Engine 默认授权页地址
+	AuthURL = "https://ad.oceanengine.com/openapi/audit/oauth.html"
 	// DefaultTimeout 默认超时时间
 	DefaultTimeout = 30 * time.Second
 )
@@ -25,19 +25,101 @@ const (
 type Client struct {
 	appID       string
 	secret      string
+	baseURL     string
+	authURL     string
+	sandbox     bool
 	httpClient  *http.Client
 	accessToken string
+	retry       RetryPolicy
+	limiter     *RateLimiter
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithBaseURL 设置 API 基础地址（可指向沙箱或本地模拟服务）
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithAuthURL 设置授权页地址
+func WithAuthURL(authURL string) Option {
+	return func(c *Client) {
+		if authURL != "" {
+			c.authURL = authURL
+		}
+	}
+}
+
+// WithTimeout 设置请求超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.httpClient.Timeout = timeout
+		}
+	}
+}
+
+// WithHTTPClient 使用自定义 http.Client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithSandbox 启用沙箱模式（请求携带 X-Debug-Mode 头）
+func WithSandbox(enabled bool) Option {
+	return func(c *Client) {
+		c.sandbox = enabled
+	}
+}
+
+// WithRetryCount 设置最大重试次数
+func WithRetryCount(count int) Option {
+	return func(c *Client) {
+		c.SetRetryCount(count)
+	}
+}
+
+// WithRateLimiter 设置接口限流器，每次请求（含重试）前等待对应接口的令牌
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
 }
 
 // NewClient 创建客户端
-func NewClient(appID, secret string) *Client {
-	return &Client{
-		appID:  appID,
-		secret: secret,
+func NewClient(appID, secret string, opts ...Option) *Client {
+	c := &Client{
+		appID:   appID,
+		secret:  secret,
+		baseURL: BaseURL,
+		authURL: AuthURL,
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
+		retry: DefaultRetryPolicy(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// UseSandbox 启用沙箱模式
+func (c *Client) UseSandbox() {
+	c.sandbox = true
+}
+
+// DisableSandbox 禁用沙箱模式
+func (c *Client) DisableSandbox() {
+	c.sandbox = false
 }
 
 // OAuth 返回OAuth服务
@@ -65,17 +147,40 @@ func (c *Client) SetAccessToken(token string) {
 	c.accessToken = token
 }
 
+// WithAccessToken 返回使用指定 Access Token 的客户端副本
+// 共享客户端在并发请求中应使用该方法，避免 SetAccessToken 相互覆盖
+func (c *Client) WithAccessToken(token string) *Client {
+	cp := *c
+	cp.accessToken = token
+	return &cp
+}
+
 // SetTimeout 设置超时时间
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
+// SetRetryPolicy 设置重试策略
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// SetRetryCount 设置最大重试次数（对应 OceanConfig.RetryCount）
+func (c *Client) SetRetryCount(count int) {
+	if count < 0 {
+		count = 0
+	}
+	c.retry.MaxRetries = count
+}
+
 // BaseResponse API 基础响应
 type BaseResponse struct {
 	Code      int             `json:"code"`
 	Message   string          `json:"message"`
 	RequestID string          `json:"request_id"`
 	Data      json.RawMessage `json:"data"`
+
+	httpStatus int
 }
 
 // IsSuccess 是否成功
@@ -85,7 +190,7 @@ func (r *BaseResponse) IsSuccess() bool {
 
 // Get 发送 GET 请求
 func (c *Client) Get(ctx context.Context, path string, params map[string]interface{}) (*BaseResponse, error) {
-	reqURL, err := url.Parse(BaseURL + path)
+	reqURL, err := url.Parse(c.baseURL + path)
 	if err != nil {
 		return nil, fmt.Errorf("parse url failed: %w", err)
 	}
@@ -113,7 +218,7 @@ func (c *Client) Post(ctx context.Context, path string, body interface{}) (*Base
 		return nil, fmt.Errorf("marshal body failed: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, BaseURL+path, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("create request failed: %w", err)
 	}
@@ -132,33 +237,108 @@ func (c *Client) setHeaders(req *http.Request) {
 }
 
 // doRequest 执行请求
+// 业务错误码由调用方通过 IsSuccess/Err 判断；瞬时错误按重试策略自动重试
 func (c *Client) doRequest(req *http.Request) (*BaseResponse, error) {
+	result, _, err := c.do(req)
+	return result, err
+}
+
+// do 执行请求并返回解析后的基础响应与原始响应体
+func (c *Client) do(req *http.Request) (*BaseResponse, []byte, error) {
+	ctx := req.Context()
+	// 请求体无法重放时不重试
+	replayable := req.Body == nil || req.GetBody != nil
+	var lastErr error
+	var lastResp *BaseResponse
+	var lastBody []byte
+
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, c.retry.Backoff(attempt)); err != nil {
+				return nil, nil, err
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, nil, fmt.Errorf("reset request body failed: %w", err)
+				}
+				req.Body = body
+			}
+		}
+
+		result, body, err := c.doOnce(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			lastErr, lastResp, lastBody = err, nil, nil
+			if !replayable || !IsRetryableRequest(req.Method, err) {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if apiErr, ok := AsAPIError(result.Err()); ok && IsRetryableRequest(req.Method, apiErr) {
+			lastErr, lastResp, lastBody = nil, result, body
+			if !replayable {
+				break
+			}
+			continue
+		}
+		return result, body, nil
+	}
+
+	if lastErr != nil {
+		return nil, nil, lastErr
+	}
+	return lastResp, lastBody, nil
+}
+
+// doOnce 执行一次 HTTP 请求
+func (c *Client) doOnce(req *http.Request) (*BaseResponse, []byte, error) {
+	if c.sandbox {
+		req.Header.Set("X-Debug-Mode", "1")
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context(), c.endpointOf(req.URL)); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, nil, fmt.Errorf("wait rate limiter failed: %w", err)
+		}
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("do request failed: %w", err)
+		return nil, nil, fmt.Errorf("do request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read response body failed: %w", err)
+		return nil, nil, fmt.Errorf("read response body failed: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
+		return nil, nil, &APIError{
+			Message:    string(body),
+			RequestID:  resp.Header.Get("X-Tt-Logid"),
+			HTTPStatus: resp.StatusCode,
+		}
 	}
 
 	var result BaseResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+		return nil, nil, fmt.Errorf("unmarshal response failed: %w", err)
 	}
+	result.httpStatus = resp.StatusCode
 
-	return &result, nil
+	return &result, body, nil
 }
 
 // GetWithToken 发送带 Token 的 GET 请求并直接解码响应
 func (c *Client) GetWithToken(ctx context.Context, accessToken, path string, params map[string]interface{}, result interface{}) error {
-	reqURL, err := url.Parse(BaseURL + path)
+	reqURL, err := url.Parse(c.baseURL + path)
 	if err != nil {
 		return fmt.Errorf("parse url failed: %w", err)
 	}
@@ -178,28 +358,13 @@ func (c *Client) GetWithToken(ctx context.Context, accessToken, path string, par
 		req.Header.Set("Access-Token", accessToken)
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("do request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	baseResp, body, err := c.do(req)
 	if err != nil {
-		return fmt.Errorf("read response body failed: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var baseResp BaseResponse
-	if err := json.Unmarshal(body, &baseResp); err != nil {
-		return fmt.Errorf("unmarshal response failed: %w", err)
+		return err
 	}
 
-	if !baseResp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", baseResp.Code, baseResp.Message)
+	if err := baseResp.Err(); err != nil {
+		return err
 	}
 
 	if result != nil {
@@ -218,7 +383,7 @@ func (c *Client) PostWithToken(ctx context.Context, accessToken, path string, da
 		return fmt.Errorf("marshal body failed: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, BaseURL+path, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request failed: %w", err)
 	}
@@ -228,28 +393,13 @@ func (c *Client) PostWithToken(ctx context.Context, accessToken, path string, da
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	baseResp, body, err := c.do(req)
 	if err != nil {
-		return fmt.Errorf("do request failed: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read response body failed: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var baseResp BaseResponse
-	if err := json.Unmarshal(body, &baseResp); err != nil {
-		return fmt.Errorf("unmarshal response failed: %w", err)
-	}
-
-	if !baseResp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", baseResp.Code, baseResp.Message)
+	if err := baseResp.Err(); err != nil {
+		return err
 	}
 
 	if result != nil {
@@ -262,83 +412,23 @@ func (c *Client) PostWithToken(ctx context.Context, accessToken, path string, da
 }
 
 // UploadFile 上传文件 (multipart/form-data)
+// 文件流式发送，失败时按重试策略重新打开文件重试
 func (c *Client) UploadFile(ctx context.Context, accessToken, path, fileField, filePath string, extraFields map[string]string) (*BaseResponse, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("open file failed: %w", err)
-	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	// 添加文件字段
-	part, err := writer.CreateFormFile(fileField, filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("copy file failed: %w", err)
-	}
-
-	// 添加其他字段
-	for key, val := range extraFields {
-		if err := writer.WriteField(key, val); err != nil {
-			return nil, fmt.Errorf("write field %s failed: %w", key, err)
-		}
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, BaseURL+path, body)
+	upload, err := fileUpload(path, fileField, filePath, extraFields)
 	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-
-	if accessToken != "" {
-		req.Header.Set("Access-Token", accessToken)
+		return nil, err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	return c.doRequest(req)
+	return c.Upload(ctx, accessToken, upload)
 }
 
 // UploadFileFromReader 从Reader上传文件
+// Reader 只能读取一次，失败时不重试；需要重试时使用 Upload 并设置 Source
 func (c *Client) UploadFileFromReader(ctx context.Context, accessToken, path, fileField, fileName string, fileReader io.Reader, extraFields map[string]string) (*BaseResponse, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	// 添加文件字段
-	part, err := writer.CreateFormFile(fileField, fileName)
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := io.Copy(part, fileReader); err != nil {
-		return nil, fmt.Errorf("copy file failed: %w", err)
-	}
-
-	// 添加其他字段
-	for key, val := range extraFields {
-		if err := writer.WriteField(key, val); err != nil {
-			return nil, fmt.Errorf("write field %s failed: %w", key, err)
-		}
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, BaseURL+path, body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-
-	if accessToken != "" {
-		req.Header.Set("Access-Token", accessToken)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	return c.doRequest(req)
+	return c.Upload(ctx, accessToken, &UploadRequest{
+		Path:      path,
+		FileField: fileField,
+		FileName:  fileName,
+		Fields:    extraFields,
+		Reader:    fileReader,
+	})
 }
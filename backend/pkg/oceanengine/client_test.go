@@ -0,0 +1,37 @@
+package oceanengine
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	httpClient := &http.Client{}
+	client := NewClient("app", "secret",
+		WithBaseURL("http://127.0.0.1:9000/open_api/"),
+		WithAuthURL("http://127.0.0.1:9000/oauth.html"),
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithSandbox(true),
+		WithRetryCount(1),
+	)
+
+	assert.Equal(t, "http://127.0.0.1:9000/open_api", client.baseURL)
+	assert.Equal(t, "http://127.0.0.1:9000/oauth.html", client.authURL)
+	assert.Same(t, httpClient, client.httpClient)
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	assert.True(t, client.sandbox)
+	assert.Equal(t, 1, client.retry.MaxRetries)
+}
+
+func TestNewClient_Defaults(t *testing.T) {
+	client := NewClient("app", "secret")
+
+	assert.Equal(t, BaseURL, client.baseURL)
+	assert.Equal(t, AuthURL, client.authURL)
+	assert.Equal(t, DefaultTimeout, client.httpClient.Timeout)
+	assert.False(t, client.sandbox)
+}
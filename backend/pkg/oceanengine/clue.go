@@ -96,7 +96,7 @@ func (s *ClueService) GetClueList(ctx context.Context, req *ClueListRequest) (*C
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result ClueListResponse
@@ -127,7 +127,7 @@ func (s *ClueService) ClueCallback(ctx context.Context, req *ClueCallbackRequest
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -168,7 +168,7 @@ func (s *ClueService) GetKeyAction(ctx context.Context, req *KeyActionGetRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result KeyActionGetResponse
@@ -227,7 +227,7 @@ func (s *ClueService) GetSmartPhone(ctx context.Context, req *SmartPhoneGetReque
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result SmartPhoneGetResponse
@@ -304,7 +304,7 @@ func (s *ClueService) GetFormList(ctx context.Context, req *FormGetRequest) (*Fo
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result FormGetResponse
@@ -334,7 +334,7 @@ func (s *ClueService) GetFormDetail(ctx context.Context, req *FormDetailRequest)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result Form
@@ -402,7 +402,7 @@ func (s *ClueService) GetClueStoreList(ctx context.Context, req *ClueStoreListRe
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result ClueStoreListResponse
@@ -450,7 +450,7 @@ func (s *ClueService) BatchClueCallback(ctx context.Context, req *BatchClueCallb
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result BatchClueCallbackResponse
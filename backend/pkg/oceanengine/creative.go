@@ -72,7 +72,7 @@ func (s *CreativeService) GetList(ctx context.Context, req *CreativeListRequest)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CreativeListResponse
@@ -120,7 +120,7 @@ func (s *CreativeService) Create(ctx context.Context, req *CreativeCreateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CreativeCreateResponse
@@ -158,7 +158,7 @@ func (s *CreativeService) CustomCreativeCreate(ctx context.Context, req *CustomC
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CreativeCreateResponse
@@ -194,7 +194,7 @@ func (s *CreativeService) Update(ctx context.Context, req *CreativeUpdateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CreativeUpdateResponse
@@ -230,7 +230,7 @@ func (s *CreativeService) UpdateStatus(ctx context.Context, req *CreativeStatusU
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CreativeStatusUpdateResponse
@@ -282,7 +282,7 @@ func (s *CreativeService) GetDetail(ctx context.Context, req *CreativeDetailRequ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -315,7 +315,7 @@ func (s *CreativeService) GetRejectReason(ctx context.Context, req *CreativeReje
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -353,7 +353,7 @@ func (s *CreativeService) GetMaterial(ctx context.Context, req *CreativeMaterial
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -385,7 +385,7 @@ func (s *CreativeService) ProceduralCreativeCreate(ctx context.Context, req *Pro
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -410,7 +410,7 @@ func (s *CreativeService) ProceduralCreativeUpdate(ctx context.Context, req *Pro
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -1,9 +1,11 @@
 package oceanengine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 // DMPService DMP人群包服务
@@ -59,7 +61,7 @@ func (s *DMPService) GetCustomAudienceList(ctx context.Context, req *CustomAudie
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CustomAudienceListResponse
@@ -97,7 +99,7 @@ func (s *DMPService) CreateCustomAudience(ctx context.Context, req *CustomAudien
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CustomAudienceCreateResponse
@@ -124,7 +126,7 @@ func (s *DMPService) UpdateCustomAudience(ctx context.Context, req *CustomAudien
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -144,7 +146,7 @@ func (s *DMPService) DeleteCustomAudience(ctx context.Context, req *CustomAudien
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -165,7 +167,7 @@ func (s *DMPService) PublishCustomAudience(ctx context.Context, req *CustomAudie
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -186,7 +188,7 @@ func (s *DMPService) PushCustomAudience(ctx context.Context, req *CustomAudience
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -201,24 +203,31 @@ type DataSourceFileUploadRequest struct {
 
 // DataSourceFileUploadResponse 上传数据源文件响应
 type DataSourceFileUploadResponse struct {
-	Path string `json:"path"`
+	Path string `json:"file_path"`
 }
 
 // UploadDataSourceFile 上传数据源文件
+// 数据源文件为 zip 压缩包，不超过 50M
 func (s *DMPService) UploadDataSourceFile(ctx context.Context, advertiserID int64, filename string, data []byte) (*DataSourceFileUploadResponse, error) {
-	// 使用 FileService 的上传逻辑
-	// 此处简化处理，实际需要根据API文档实现multipart上传
-	reqBody := map[string]interface{}{
-		"advertiser_id": advertiserID,
-	}
-
-	resp, err := s.client.Post(ctx, "/2/dmp/data_source/file/upload/", reqBody)
+	resp, err := s.client.Upload(ctx, s.client.accessToken, &UploadRequest{
+		Path:      "/2/dmp/data_source/file/upload/",
+		FileField: "file",
+		FileName:  filename,
+		Fields: map[string]string{
+			"advertiser_id": fmt.Sprintf("%d", advertiserID),
+		},
+		Source: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+		Size:           int64(len(data)),
+		SignatureField: "file_signature",
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result DataSourceFileUploadResponse
@@ -253,7 +262,7 @@ func (s *DMPService) CreateLookalikeAudience(ctx context.Context, req *Lookalike
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result LookalikeAudienceCreateResponse
@@ -280,7 +289,7 @@ func (s *DMPService) GetCustomAudienceDetail(ctx context.Context, req *CustomAud
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -330,7 +339,7 @@ func (s *DMPService) CreateDataSource(ctx context.Context, req *DataSourceCreate
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result DataSourceCreateResponse
@@ -357,7 +366,7 @@ func (s *DMPService) UpdateDataSource(ctx context.Context, req *DataSourceUpdate
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -377,7 +386,7 @@ func (s *DMPService) GetDataSourceDetail(ctx context.Context, req *DataSourceRea
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -410,7 +419,7 @@ func (s *DMPService) GetBrandList(ctx context.Context, advertiserID int64) ([]Br
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -438,7 +447,7 @@ func (s *DMPService) CopyCustomAudienceToBrand(ctx context.Context, req *CustomA
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -466,7 +475,7 @@ func (s *DMPService) GetInterestCategories(ctx context.Context, advertiserID int
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -501,7 +510,7 @@ func (s *DMPService) GetActionCategories(ctx context.Context, advertiserID int64
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -533,7 +542,7 @@ func (s *DMPService) SearchInterestKeywords(ctx context.Context, advertiserID in
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -570,7 +579,7 @@ func (s *DMPService) SearchAwemeAuthors(ctx context.Context, advertiserID int64,
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -602,7 +611,7 @@ func (s *DMPService) GetAwemeAuthorCategories(ctx context.Context, advertiserID
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -642,7 +651,7 @@ func (s *DMPService) EstimateAudience(ctx context.Context, req *AudienceEstimate
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AudienceEstimateResponse
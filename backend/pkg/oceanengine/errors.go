@@ -0,0 +1,147 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// 巨量引擎常见的瞬时错误码
+const (
+	// CodeRateLimit 请求过于频繁
+	CodeRateLimit = 40100
+	// CodeAppRateLimit 应用维度接口调用频率超限
+	CodeAppRateLimit = 40110
+	// CodeAppRateLimitMax 应用/接口维度频控错误码上限 (40110-40119)
+	CodeAppRateLimitMax = 40119
+	// CodeSystemError 系统错误
+	CodeSystemError = 50000
+	// CodeSystemBusy 系统繁忙，请稍后重试
+	CodeSystemBusy = 51010
+)
+
+// APIError 巨量引擎 API 错误
+type APIError struct {
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	RequestID  string `json:"request_id"`
+	HTTPStatus int    `json:"http_status"`
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	if e.Code == 0 && e.HTTPStatus != 0 && e.HTTPStatus != http.StatusOK {
+		return fmt.Sprintf("http status %d: %s", e.HTTPStatus, e.Message)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("api error: code=%d, message=%s, request_id=%s", e.Code, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("api error: code=%d, message=%s", e.Code, e.Message)
+}
+
+// ErrorCode 巨量引擎返回码，无返回码时为 HTTP 状态码，供 errcode.FromOceanEngine 映射
+func (e *APIError) ErrorCode() int {
+	if e.Code == 0 && e.HTTPStatus != 0 && e.HTTPStatus != http.StatusOK {
+		return e.HTTPStatus
+	}
+	return e.Code
+}
+
+// ErrorMessage 巨量引擎返回信息
+func (e *APIError) ErrorMessage() string {
+	return e.Message
+}
+
+// APIRequestID 巨量引擎请求ID
+func (e *APIError) APIRequestID() string {
+	return e.RequestID
+}
+
+// IsRateLimited 是否为频控错误
+func (e *APIError) IsRateLimited() bool {
+	return e.Code == CodeRateLimit ||
+		(e.Code >= CodeAppRateLimit && e.Code <= CodeAppRateLimitMax) ||
+		e.HTTPStatus == http.StatusTooManyRequests
+}
+
+// IsRetryable 是否为可重试的瞬时错误（频控、系统繁忙、5xx）
+func (e *APIError) IsRetryable() bool {
+	if e.IsRateLimited() {
+		return true
+	}
+	switch e.Code {
+	case CodeSystemError, CodeSystemBusy:
+		return true
+	}
+	return e.HTTPStatus >= http.StatusInternalServerError
+}
+
+// Err 将非成功响应转换为 *APIError，成功时返回 nil
+func (r *BaseResponse) Err() error {
+	if r.IsSuccess() {
+		return nil
+	}
+	return &APIError{
+		Code:       r.Code,
+		Message:    r.Message,
+		RequestID:  r.RequestID,
+		HTTPStatus: r.httpStatus,
+	}
+}
+
+// AsAPIError 从错误链中提取 *APIError
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsRetryable 判断错误是否可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.IsRetryable()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsRetryableRequest 判断指定方法的请求失败后是否可重试
+// GET 等幂等请求按 IsRetryable 判断；POST 等非幂等请求可能已被服务端执行，
+// 仅在频控、系统繁忙或连接未建立 (拨号失败) 时重试，避免创建、转账等操作被重复执行
+func IsRetryableRequest(method string, err error) bool {
+	if isIdempotent(method) {
+		return IsRetryable(err)
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.IsRateLimited() || apiErr.Code == CodeSystemBusy
+	}
+	return IsDialError(err)
+}
+
+// IsDialError 判断错误是否发生在建立连接阶段，此时请求尚未发送
+func IsDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isIdempotent 请求方法是否幂等
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -88,7 +88,7 @@ func (s *EventManagerService) GetAssets(ctx context.Context, req *AssetsGetReque
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AssetsGetResponse
@@ -143,7 +143,7 @@ func (s *EventManagerService) GetAllAssetsList(ctx context.Context, req *AllAsse
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AllAssetsListResponse
@@ -173,7 +173,7 @@ func (s *EventManagerService) GetAllAssetsDetail(ctx context.Context, req *AllAs
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -205,7 +205,7 @@ func (s *EventManagerService) CreateAsset(ctx context.Context, req *AssetsCreate
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -249,7 +249,7 @@ func (s *EventManagerService) GetAvailableEvents(ctx context.Context, req *Avail
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -281,7 +281,7 @@ func (s *EventManagerService) GetEventConfigs(ctx context.Context, req *EventCon
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -309,7 +309,7 @@ func (s *EventManagerService) CreateEvents(ctx context.Context, req *EventsCreat
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -358,7 +358,7 @@ func (s *EventManagerService) CreateTrackURL(ctx context.Context, req *TrackURLC
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -381,7 +381,7 @@ func (s *EventManagerService) UpdateTrackURL(ctx context.Context, req *TrackURLU
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -411,7 +411,7 @@ func (s *EventManagerService) GetTrackURL(ctx context.Context, req *TrackURLGetR
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result TrackURLGetResponse
@@ -448,7 +448,7 @@ func (s *EventManagerService) GetShare(ctx context.Context, req *ShareGetRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result ShareGetResponse
@@ -481,7 +481,7 @@ func (s *EventManagerService) Share(ctx context.Context, req *ShareRequest) ([]S
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -502,7 +502,7 @@ func (s *EventManagerService) ShareCancel(ctx context.Context, req *ShareRequest
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -553,7 +553,7 @@ func (s *EventManagerService) GetEventConvertOptimizedGoal(ctx context.Context,
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result EventConvertOptimizedGoalGetResponse
@@ -600,7 +600,7 @@ func (s *EventManagerService) GetV3OptimizedGoal(ctx context.Context, req *V3Opt
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result V3OptimizedGoalGetResponse
@@ -644,7 +644,7 @@ func (s *EventManagerService) GetV3DeepBidType(ctx context.Context, req *V3DeepB
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -675,7 +675,7 @@ func (s *EventManagerService) Conversion(ctx context.Context, req *ConversionReq
 	}
 
 	if !resp.IsSuccess() {
-		return resp.Code, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Code, resp.Err()
 	}
 
 	return 0, nil
@@ -705,7 +705,7 @@ func (s *EventManagerService) AddPublicKey(ctx context.Context, req *AddPublicKe
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result PublicKey
@@ -730,7 +730,7 @@ func (s *EventManagerService) DelPublicKey(ctx context.Context, req *DelPublicKe
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -755,7 +755,7 @@ func (s *EventManagerService) GetPublicKey(ctx context.Context, req *GetPublicKe
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result PublicKey
@@ -783,7 +783,7 @@ func (s *EventManagerService) GetAllPublicKeys(ctx context.Context, req *GetAllP
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -808,7 +808,7 @@ func (s *EventManagerService) EnableAuth(ctx context.Context, advertiserID int64
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -826,7 +826,7 @@ func (s *EventManagerService) DisableAuth(ctx context.Context, advertiserID int6
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -6,10 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
-	"net/http"
-	"os"
-	"path/filepath"
 )
 
 // FileService 文件服务
@@ -45,56 +41,21 @@ type ImageUploadRequest struct {
 
 // UploadImageByFile 上传图片文件
 func (s *FileService) UploadImageByFile(ctx context.Context, advertiserID int64, filePath string) (*ImageInfo, error) {
-	file, err := os.Open(filePath)
+	upload, err := fileUpload("/2/file/image/ad/", "image_file", filePath, map[string]string{
+		"advertiser_id": fmt.Sprintf("%d", advertiserID),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("open file failed: %w", err)
-	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	if err := writer.WriteField("advertiser_id", fmt.Sprintf("%d", advertiserID)); err != nil {
-		return nil, fmt.Errorf("write advertiser_id failed: %w", err)
-	}
-
-	part, err := writer.CreateFormFile("image_file", filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("copy file content failed: %w", err)
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", BaseURL+"/2/file/image/ad/", body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Access-Token", s.client.accessToken)
-
-	resp, err := s.client.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("do request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	upload.SignatureField = "image_signature"
 
-	respBody, err := io.ReadAll(resp.Body)
+	apiResp, err := s.client.Upload(ctx, s.client.accessToken, upload)
 	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
-	}
-
-	var apiResp BaseResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+		return nil, err
 	}
 
-	if !apiResp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", apiResp.Code, apiResp.Message)
+	if err := apiResp.Err(); err != nil {
+		return nil, err
 	}
 
 	var result ImageInfo
@@ -118,7 +79,7 @@ func (s *FileService) UploadImageByURL(ctx context.Context, advertiserID int64,
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result ImageInfo
@@ -143,56 +104,21 @@ type VideoInfo struct {
 
 // UploadVideoByFile 上传视频文件
 func (s *FileService) UploadVideoByFile(ctx context.Context, advertiserID int64, filePath string) (*VideoInfo, error) {
-	file, err := os.Open(filePath)
+	upload, err := fileUpload("/2/file/video/ad/", "video_file", filePath, map[string]string{
+		"advertiser_id": fmt.Sprintf("%d", advertiserID),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("open file failed: %w", err)
-	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	if err := writer.WriteField("advertiser_id", fmt.Sprintf("%d", advertiserID)); err != nil {
-		return nil, fmt.Errorf("write advertiser_id failed: %w", err)
-	}
-
-	part, err := writer.CreateFormFile("video_file", filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("copy file content failed: %w", err)
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", BaseURL+"/2/file/video/ad/", body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Access-Token", s.client.accessToken)
-
-	resp, err := s.client.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("do request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	upload.SignatureField = "video_signature"
 
-	respBody, err := io.ReadAll(resp.Body)
+	apiResp, err := s.client.Upload(ctx, s.client.accessToken, upload)
 	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
-	}
-
-	var apiResp BaseResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+		return nil, err
 	}
 
-	if !apiResp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", apiResp.Code, apiResp.Message)
+	if err := apiResp.Err(); err != nil {
+		return nil, err
 	}
 
 	var result VideoInfo
@@ -237,7 +163,7 @@ func (s *FileService) GetImages(ctx context.Context, req *ImageGetRequest) (*Ima
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result ImageGetResponse
@@ -282,7 +208,7 @@ func (s *FileService) GetVideos(ctx context.Context, req *VideoGetRequest) (*Vid
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result VideoGetResponse
@@ -307,7 +233,7 @@ func (s *FileService) GetAdImages(ctx context.Context, req *ImageAdGetRequest) (
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -334,7 +260,7 @@ func (s *FileService) GetAdVideos(ctx context.Context, req *VideoAdGetRequest) (
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -349,50 +275,26 @@ func (s *FileService) GetAdVideos(ctx context.Context, req *VideoAdGetRequest) (
 
 // UploadImageByBytes 通过字节数组上传图片
 func (s *FileService) UploadImageByBytes(ctx context.Context, advertiserID int64, filename string, data []byte) (*ImageInfo, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	if err := writer.WriteField("advertiser_id", fmt.Sprintf("%d", advertiserID)); err != nil {
-		return nil, fmt.Errorf("write advertiser_id failed: %w", err)
-	}
-
-	part, err := writer.CreateFormFile("image_file", filename)
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := part.Write(data); err != nil {
-		return nil, fmt.Errorf("write file content failed: %w", err)
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", BaseURL+"/2/file/image/ad/", body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Access-Token", s.client.accessToken)
-
-	resp, err := s.client.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("do request failed: %w", err)
-	}
-	defer resp.Body.Close()
+	return s.UploadImageFromSource(ctx, advertiserID, filename, bytesSource(data), int64(len(data)))
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+// UploadImageFromSource 从可重新打开的文件来源上传图片，如存储中的素材原始文件
+func (s *FileService) UploadImageFromSource(ctx context.Context, advertiserID int64, filename string, source UploadSource, size int64) (*ImageInfo, error) {
+	apiResp, err := s.client.Upload(ctx, s.client.accessToken, &UploadRequest{
+		Path:           "/2/file/image/ad/",
+		FileField:      "image_file",
+		FileName:       filename,
+		Fields:         map[string]string{"advertiser_id": fmt.Sprintf("%d", advertiserID)},
+		Source:         source,
+		Size:           size,
+		SignatureField: "image_signature",
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
-	}
-
-	var apiResp BaseResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+		return nil, err
 	}
 
-	if !apiResp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", apiResp.Code, apiResp.Message)
+	if err := apiResp.Err(); err != nil {
+		return nil, err
 	}
 
 	var result ImageInfo
@@ -405,50 +307,26 @@ func (s *FileService) UploadImageByBytes(ctx context.Context, advertiserID int64
 
 // UploadVideoByBytes 通过字节数组上传视频
 func (s *FileService) UploadVideoByBytes(ctx context.Context, advertiserID int64, filename string, data []byte) (*VideoInfo, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	if err := writer.WriteField("advertiser_id", fmt.Sprintf("%d", advertiserID)); err != nil {
-		return nil, fmt.Errorf("write advertiser_id failed: %w", err)
-	}
-
-	part, err := writer.CreateFormFile("video_file", filename)
-	if err != nil {
-		return nil, fmt.Errorf("create form file failed: %w", err)
-	}
-	if _, err := part.Write(data); err != nil {
-		return nil, fmt.Errorf("write file content failed: %w", err)
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer failed: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", BaseURL+"/2/file/video/ad/", body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Access-Token", s.client.accessToken)
-
-	resp, err := s.client.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("do request failed: %w", err)
-	}
-	defer resp.Body.Close()
+	return s.UploadVideoFromSource(ctx, advertiserID, filename, bytesSource(data), int64(len(data)))
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+// UploadVideoFromSource 从可重新打开的文件来源上传视频，如存储中的素材原始文件
+func (s *FileService) UploadVideoFromSource(ctx context.Context, advertiserID int64, filename string, source UploadSource, size int64) (*VideoInfo, error) {
+	apiResp, err := s.client.Upload(ctx, s.client.accessToken, &UploadRequest{
+		Path:           "/2/file/video/ad/",
+		FileField:      "video_file",
+		FileName:       filename,
+		Fields:         map[string]string{"advertiser_id": fmt.Sprintf("%d", advertiserID)},
+		Source:         source,
+		Size:           size,
+		SignatureField: "video_signature",
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
-	}
-
-	var apiResp BaseResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+		return nil, err
 	}
 
-	if !apiResp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", apiResp.Code, apiResp.Message)
+	if err := apiResp.Err(); err != nil {
+		return nil, err
 	}
 
 	var result VideoInfo
@@ -458,3 +336,10 @@ func (s *FileService) UploadVideoByBytes(ctx context.Context, advertiserID int64
 
 	return &result, nil
 }
+
+// bytesSource 内存数据来源
+func bytesSource(data []byte) UploadSource {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 )
 
 // LocalClient 本地推API客户端
+// 项目、广告、报表与素材上传已迁移至 pkg/oceansdk (sdk/marketing-api/api/local)，此处保留 SDK 尚未覆盖的接口
 type LocalClient struct {
 	client *Client
 }
@@ -14,207 +15,6 @@ func (c *Client) Local() *LocalClient {
 	return &LocalClient{client: c}
 }
 
-// ==================== 项目管理 ====================
-
-// LocalProject 本地推项目
-type LocalProject struct {
-	ProjectID     uint64  `json:"project_id"`
-	ProjectName   string  `json:"project_name"`
-	AdvertiserID  uint64  `json:"advertiser_id"`
-	Status        string  `json:"status"`
-	Budget        float64 `json:"budget"`
-	BudgetMode    string  `json:"budget_mode"`
-	DeliveryRange string  `json:"delivery_range"`
-	CreateTime    string  `json:"create_time"`
-	ModifyTime    string  `json:"modify_time"`
-}
-
-// ProjectListRequest 项目列表请求
-type ProjectListRequest struct {
-	AdvertiserID uint64         `json:"advertiser_id"`
-	Filtering    *ProjectFilter `json:"filtering,omitempty"`
-	Page         int            `json:"page,omitempty"`
-	PageSize     int            `json:"page_size,omitempty"`
-}
-
-type ProjectFilter struct {
-	ProjectIDs []uint64 `json:"project_ids,omitempty"`
-	Status     string   `json:"status,omitempty"`
-}
-
-// GetProjectList 获取项目列表
-func (l *LocalClient) GetProjectList(ctx context.Context, accessToken string, req *ProjectListRequest) ([]LocalProject, int, error) {
-	path := "/v1.0/local/project/list/"
-	params := map[string]interface{}{
-		"advertiser_id": req.AdvertiserID,
-	}
-	if req.Page > 0 {
-		params["page"] = req.Page
-	}
-	if req.PageSize > 0 {
-		params["page_size"] = req.PageSize
-	}
-	if req.Filtering != nil {
-		params["filtering"] = req.Filtering
-	}
-
-	var result struct {
-		Data struct {
-			List     []LocalProject `json:"list"`
-			PageInfo struct {
-				TotalNumber int `json:"total_number"`
-			} `json:"page_info"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, 0, err
-	}
-	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
-}
-
-// CreateProject 创建项目
-func (l *LocalClient) CreateProject(ctx context.Context, accessToken string, advertiserID uint64, projectData map[string]interface{}) (uint64, error) {
-	path := "/v1.0/local/project/create/"
-	projectData["advertiser_id"] = advertiserID
-
-	var result struct {
-		Data struct {
-			ProjectID uint64 `json:"project_id"`
-		} `json:"data"`
-	}
-	err := l.client.PostWithToken(ctx, accessToken, path, projectData, &result)
-	if err != nil {
-		return 0, err
-	}
-	return result.Data.ProjectID, nil
-}
-
-// GetProjectDetail 获取项目详情
-func (l *LocalClient) GetProjectDetail(ctx context.Context, accessToken string, advertiserID, projectID uint64) (*LocalProject, error) {
-	path := "/v1.0/local/project/detail/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"project_id":    projectID,
-	}
-
-	var result struct {
-		Data LocalProject `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, err
-	}
-	return &result.Data, nil
-}
-
-// ==================== 广告管理 ====================
-
-// LocalPromotion 本地推广告
-type LocalPromotion struct {
-	PromotionID   uint64  `json:"promotion_id"`
-	PromotionName string  `json:"promotion_name"`
-	ProjectID     uint64  `json:"project_id"`
-	AdvertiserID  uint64  `json:"advertiser_id"`
-	Status        string  `json:"status"`
-	OptStatus     string  `json:"opt_status"`
-	Budget        float64 `json:"budget"`
-	BudgetMode    string  `json:"budget_mode"`
-	CreateTime    string  `json:"create_time"`
-	ModifyTime    string  `json:"modify_time"`
-}
-
-// PromotionListRequest 广告列表请求
-type PromotionListRequest struct {
-	AdvertiserID uint64           `json:"advertiser_id"`
-	Filtering    *PromotionFilter `json:"filtering,omitempty"`
-	Page         int              `json:"page,omitempty"`
-	PageSize     int              `json:"page_size,omitempty"`
-}
-
-type PromotionFilter struct {
-	PromotionIDs []uint64 `json:"promotion_ids,omitempty"`
-	ProjectIDs   []uint64 `json:"project_ids,omitempty"`
-	Status       string   `json:"status,omitempty"`
-}
-
-// GetPromotionList 获取广告列表
-func (l *LocalClient) GetPromotionList(ctx context.Context, accessToken string, req *PromotionListRequest) ([]LocalPromotion, int, error) {
-	path := "/v1.0/local/promotion/list/"
-	params := map[string]interface{}{
-		"advertiser_id": req.AdvertiserID,
-	}
-	if req.Page > 0 {
-		params["page"] = req.Page
-	}
-	if req.PageSize > 0 {
-		params["page_size"] = req.PageSize
-	}
-	if req.Filtering != nil {
-		params["filtering"] = req.Filtering
-	}
-
-	var result struct {
-		Data struct {
-			List     []LocalPromotion `json:"list"`
-			PageInfo struct {
-				TotalNumber int `json:"total_number"`
-			} `json:"page_info"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, 0, err
-	}
-	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
-}
-
-// CreatePromotion 创建广告
-func (l *LocalClient) CreatePromotion(ctx context.Context, accessToken string, advertiserID uint64, promotionData map[string]interface{}) (uint64, error) {
-	path := "/v1.0/local/promotion/create/"
-	promotionData["advertiser_id"] = advertiserID
-
-	var result struct {
-		Data struct {
-			PromotionID uint64 `json:"promotion_id"`
-		} `json:"data"`
-	}
-	err := l.client.PostWithToken(ctx, accessToken, path, promotionData, &result)
-	if err != nil {
-		return 0, err
-	}
-	return result.Data.PromotionID, nil
-}
-
-// GetPromotionDetail 获取广告详情
-func (l *LocalClient) GetPromotionDetail(ctx context.Context, accessToken string, advertiserID, promotionID uint64) (*LocalPromotion, error) {
-	path := "/v1.0/local/promotion/detail/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"promotion_id":  promotionID,
-	}
-
-	var result struct {
-		Data LocalPromotion `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, err
-	}
-	return &result.Data, nil
-}
-
-// UpdatePromotionStatus 更新广告状态
-func (l *LocalClient) UpdatePromotionStatus(ctx context.Context, accessToken string, advertiserID uint64, promotionIDs []uint64, optStatus string) error {
-	path := "/v1.0/local/promotion/status/update/"
-	data := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"promotion_ids": promotionIDs,
-		"opt_status":    optStatus,
-	}
-	return l.client.PostWithToken(ctx, accessToken, path, data, nil)
-}
-
 // ==================== 线索管理 ====================
 
 // LocalClue 本地推线索信息
@@ -258,124 +58,7 @@ func (l *LocalClient) GetClueList(ctx context.Context, accessToken string, adver
 	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
 }
 
-// ==================== 数据报表 ====================
-
-// LocalReport 本地推报表
-type LocalReport struct {
-	StatDatetime string  `json:"stat_datetime"`
-	Cost         float64 `json:"cost"`
-	ShowCnt      int64   `json:"show_cnt"`
-	ClickCnt     int64   `json:"click_cnt"`
-	ConvertCnt   int64   `json:"convert_cnt"`
-	Ctr          float64 `json:"ctr"`
-	Cpm          float64 `json:"cpm"`
-	Cpc          float64 `json:"cpc"`
-	CpaConvert   float64 `json:"cpa_convert"`
-}
-
-// GetProjectReport 获取项目报表
-func (l *LocalClient) GetProjectReport(ctx context.Context, accessToken string, advertiserID uint64, startDate, endDate string, projectIDs []uint64) ([]LocalReport, error) {
-	path := "/v1.0/local/report/project/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"start_date":    startDate,
-		"end_date":      endDate,
-	}
-	if len(projectIDs) > 0 {
-		params["filtering"] = map[string]interface{}{
-			"project_ids": projectIDs,
-		}
-	}
-
-	var result struct {
-		Data struct {
-			List []LocalReport `json:"list"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, err
-	}
-	return result.Data.List, nil
-}
-
-// GetPromotionReport 获取广告报表
-func (l *LocalClient) GetPromotionReport(ctx context.Context, accessToken string, advertiserID uint64, startDate, endDate string, promotionIDs []uint64) ([]LocalReport, error) {
-	path := "/v1.0/local/report/promotion/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"start_date":    startDate,
-		"end_date":      endDate,
-	}
-	if len(promotionIDs) > 0 {
-		params["filtering"] = map[string]interface{}{
-			"promotion_ids": promotionIDs,
-		}
-	}
-
-	var result struct {
-		Data struct {
-			List []LocalReport `json:"list"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, err
-	}
-	return result.Data.List, nil
-}
-
-// GetMaterialReport 获取素材报表
-func (l *LocalClient) GetMaterialReport(ctx context.Context, accessToken string, advertiserID uint64, startDate, endDate string) ([]LocalReport, error) {
-	path := "/v1.0/local/report/material/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"start_date":    startDate,
-		"end_date":      endDate,
-	}
-
-	var result struct {
-		Data struct {
-			List []LocalReport `json:"list"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, err
-	}
-	return result.Data.List, nil
-}
-
-// ==================== 项目管理扩展 ====================
-
-// UpdateProject 更新项目
-func (l *LocalClient) UpdateProject(ctx context.Context, accessToken string, advertiserID, projectID uint64, updateData map[string]interface{}) error {
-	path := "/v1.0/local/project/update/"
-	updateData["advertiser_id"] = advertiserID
-	updateData["project_id"] = projectID
-	return l.client.PostWithToken(ctx, accessToken, path, updateData, nil)
-}
-
-// UpdateProjectStatus 更新项目状态
-func (l *LocalClient) UpdateProjectStatus(ctx context.Context, accessToken string, advertiserID uint64, projectIDs []uint64, optStatus string) ([]uint64, []uint64, error) {
-	path := "/v1.0/local/project/status/update/"
-	data := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"project_ids":   projectIDs,
-		"opt_status":    optStatus,
-	}
-	var result struct {
-		Data struct {
-			SuccessIDs []uint64 `json:"success_project_ids"`
-			FailIDs    []uint64 `json:"fail_project_ids"`
-		} `json:"data"`
-	}
-	err := l.client.PostWithToken(ctx, accessToken, path, data, &result)
-	if err != nil {
-		return nil, nil, err
-	}
-	return result.Data.SuccessIDs, result.Data.FailIDs, nil
-}
+// ==================== 项目管理 ====================
 
 // DeleteProject 删除项目
 func (l *LocalClient) DeleteProject(ctx context.Context, accessToken string, advertiserID uint64, projectIDs []uint64) error {
@@ -387,15 +70,7 @@ func (l *LocalClient) DeleteProject(ctx context.Context, accessToken string, adv
 	return l.client.PostWithToken(ctx, accessToken, path, data, nil)
 }
 
-// ==================== 广告管理扩展 ====================
-
-// UpdatePromotion 更新广告
-func (l *LocalClient) UpdatePromotion(ctx context.Context, accessToken string, advertiserID, promotionID uint64, updateData map[string]interface{}) error {
-	path := "/v1.0/local/promotion/update/"
-	updateData["advertiser_id"] = advertiserID
-	updateData["promotion_id"] = promotionID
-	return l.client.PostWithToken(ctx, accessToken, path, updateData, nil)
-}
+// ==================== 广告管理 ====================
 
 // DeletePromotion 删除广告
 func (l *LocalClient) DeletePromotion(ctx context.Context, accessToken string, advertiserID uint64, promotionIDs []uint64) error {
@@ -590,29 +265,6 @@ type LocalVideo struct {
 	CreateTime string  `json:"create_time"`
 }
 
-// GetVideoList 获取素材库视频
-func (l *LocalClient) GetVideoList(ctx context.Context, accessToken string, advertiserID uint64, page, pageSize int) ([]LocalVideo, int, error) {
-	path := "/v1.0/local/file/video/get/"
-	params := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"page":          page,
-		"page_size":     pageSize,
-	}
-	var result struct {
-		Data struct {
-			List     []LocalVideo `json:"list"`
-			PageInfo struct {
-				TotalNumber int `json:"total_number"`
-			} `json:"page_info"`
-		} `json:"data"`
-	}
-	err := l.client.GetWithToken(ctx, accessToken, path, params, &result)
-	if err != nil {
-		return nil, 0, err
-	}
-	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
-}
-
 // GetAwemeVideoList 获取抖音主页视频
 func (l *LocalClient) GetAwemeVideoList(ctx context.Context, accessToken string, advertiserID, awemeID uint64, page, pageSize int) ([]LocalVideo, int, error) {
 	path := "/v1.0/local/file/video/aweme/get/"
@@ -637,25 +289,6 @@ func (l *LocalClient) GetAwemeVideoList(ctx context.Context, accessToken string,
 	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
 }
 
-// CreateVideoUploadTask 创建异步视频上传任务
-func (l *LocalClient) CreateVideoUploadTask(ctx context.Context, accessToken string, advertiserID uint64, videoURL string) (uint64, error) {
-	path := "/v1.0/local/file/video/upload/task/create/"
-	data := map[string]interface{}{
-		"advertiser_id": advertiserID,
-		"video_url":     videoURL,
-	}
-	var result struct {
-		Data struct {
-			TaskID uint64 `json:"task_id"`
-		} `json:"data"`
-	}
-	err := l.client.PostWithToken(ctx, accessToken, path, data, &result)
-	if err != nil {
-		return 0, err
-	}
-	return result.Data.TaskID, nil
-}
-
 // GetVideoUploadTaskList 查询视频上传任务结果
 func (l *LocalClient) GetVideoUploadTaskList(ctx context.Context, accessToken string, advertiserID uint64, taskIDs []uint64) ([]map[string]interface{}, error) {
 	path := "/v1.0/local/file/video/upload/task/list/"
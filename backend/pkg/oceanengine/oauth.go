@@ -48,7 +48,7 @@ func (s *OAuthService) GetAccessToken(ctx context.Context, authCode string) (*Ac
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AccessTokenResponse
@@ -82,7 +82,7 @@ func (s *OAuthService) RefreshAccessToken(ctx context.Context, refreshToken stri
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AccessTokenResponse
@@ -96,7 +96,8 @@ func (s *OAuthService) RefreshAccessToken(ctx context.Context, refreshToken stri
 // GetAuthURL 获取授权 URL
 func (s *OAuthService) GetAuthURL(state, redirectURI string) string {
 	return fmt.Sprintf(
-		"https://ad.oceanengine.com/openapi/audit/oauth.html?app_id=%s&state=%s&redirect_uri=%s",
+		"%s?app_id=%s&state=%s&redirect_uri=%s",
+		s.client.authURL,
 		s.client.appID,
 		state,
 		redirectURI,
@@ -115,7 +116,8 @@ func (s *OAuthService) GetAuthURLWithScope(state, redirectURI string, scope []st
 		}
 	}
 	authURL := fmt.Sprintf(
-		"https://ad.oceanengine.com/openapi/audit/oauth.html?app_id=%s&state=%s&redirect_uri=%s",
+		"%s?app_id=%s&state=%s&redirect_uri=%s",
+		s.client.authURL,
 		s.client.appID,
 		state,
 		redirectURI,
@@ -151,7 +153,7 @@ func (s *OAuthService) GetUserInfo(ctx context.Context, accessToken string) (*Us
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -188,7 +190,7 @@ func (s *OAuthService) GetAdvertiserList(ctx context.Context, accessToken string
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -223,7 +225,7 @@ func (s *OAuthService) GetAppAccessToken(ctx context.Context) (*AppAccessTokenRe
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result AppAccessTokenResponse
@@ -199,23 +199,6 @@ func (q *QianchuanClient) GetAdList(ctx context.Context, accessToken string, req
 	return result.Data.List, result.Data.PageInfo.TotalNumber, nil
 }
 
-// CreateAd 创建广告计划
-func (q *QianchuanClient) CreateAd(ctx context.Context, accessToken string, advertiserID uint64, adData map[string]interface{}) (uint64, error) {
-	path := "/v1.0/qianchuan/ad/create/"
-	adData["advertiser_id"] = advertiserID
-
-	var result struct {
-		Data struct {
-			AdID uint64 `json:"ad_id"`
-		} `json:"data"`
-	}
-	err := q.client.PostWithToken(ctx, accessToken, path, adData, &result)
-	if err != nil {
-		return 0, err
-	}
-	return result.Data.AdID, nil
-}
-
 // UpdateAdStatus 更新广告状态
 func (q *QianchuanClient) UpdateAdStatus(ctx context.Context, accessToken string, advertiserID uint64, adIDs []uint64, optStatus string) error {
 	path := "/v1.0/qianchuan/ad/status/update/"
@@ -357,13 +340,18 @@ func (q *QianchuanClient) UploadVideo(ctx context.Context, accessToken string, a
 		"advertiser_id": fmt.Sprintf("%d", advertiserID),
 	}
 
-	resp, err := q.client.UploadFile(ctx, accessToken, path, "video_file", filePath, extraFields)
+	upload, err := fileUpload(path, "video_file", filePath, extraFields)
+	if err != nil {
+		return nil, err
+	}
+	upload.SignatureField = "video_signature"
+	resp, err := q.client.Upload(ctx, accessToken, upload)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -382,13 +370,20 @@ func (q *QianchuanClient) UploadVideoFromReader(ctx context.Context, accessToken
 		"advertiser_id": fmt.Sprintf("%d", advertiserID),
 	}
 
-	resp, err := q.client.UploadFileFromReader(ctx, accessToken, path, "video_file", fileName, reader, extraFields)
+	resp, err := q.client.Upload(ctx, accessToken, &UploadRequest{
+		Path:           path,
+		FileField:      "video_file",
+		FileName:       fileName,
+		Fields:         extraFields,
+		Reader:         reader,
+		SignatureField: "video_signature",
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -407,13 +402,18 @@ func (q *QianchuanClient) UploadImage(ctx context.Context, accessToken string, a
 		"advertiser_id": fmt.Sprintf("%d", advertiserID),
 	}
 
-	resp, err := q.client.UploadFile(ctx, accessToken, path, "image_file", filePath, extraFields)
+	upload, err := fileUpload(path, "image_file", filePath, extraFields)
+	if err != nil {
+		return nil, err
+	}
+	upload.SignatureField = "image_signature"
+	resp, err := q.client.Upload(ctx, accessToken, upload)
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -432,13 +432,20 @@ func (q *QianchuanClient) UploadImageFromReader(ctx context.Context, accessToken
 		"advertiser_id": fmt.Sprintf("%d", advertiserID),
 	}
 
-	resp, err := q.client.UploadFileFromReader(ctx, accessToken, path, "image_file", fileName, reader, extraFields)
+	resp, err := q.client.Upload(ctx, accessToken, &UploadRequest{
+		Path:           path,
+		FileField:      "image_file",
+		FileName:       fileName,
+		Fields:         extraFields,
+		Reader:         reader,
+		SignatureField: "image_signature",
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -83,7 +83,7 @@ func (s *QingniaoService) GetFormList(ctx context.Context, req *FormListRequest)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result FormListResponse
@@ -113,7 +113,7 @@ func (s *QingniaoService) GetFormDetail(ctx context.Context, req *FormDetailReq)
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result QingniaoForm
@@ -142,7 +142,7 @@ func (s *QingniaoService) CreateForm(ctx context.Context, req *FormCreateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -173,7 +173,7 @@ func (s *QingniaoService) UpdateForm(ctx context.Context, req *FormUpdateRequest
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -200,7 +200,7 @@ func (s *QingniaoService) DeleteForm(ctx context.Context, req *FormDeleteRequest
 	}
 
 	if !resp.IsSuccess() {
-		return false, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return false, resp.Err()
 	}
 
 	return true, nil
@@ -245,7 +245,7 @@ func (s *QingniaoService) CreateCoupon(ctx context.Context, req *CouponCreateReq
 	}
 
 	if !resp.IsSuccess() {
-		return 0, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return 0, resp.Err()
 	}
 
 	var result struct {
@@ -294,7 +294,7 @@ func (s *QingniaoService) GetCouponList(ctx context.Context, req *CouponListRequ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CouponListResponse
@@ -324,7 +324,7 @@ func (s *QingniaoService) GetCouponDetail(ctx context.Context, req *CouponDetail
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result Coupon
@@ -354,7 +354,7 @@ func (s *QingniaoService) UpdateCoupon(ctx context.Context, req *CouponUpdateReq
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -382,7 +382,7 @@ func (s *QingniaoService) UploadCouponCode(ctx context.Context, req *CouponCodeU
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CouponCodeUploadResponse
@@ -444,7 +444,7 @@ func (s *QingniaoService) GetCouponCodes(ctx context.Context, req *CouponCodeGet
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result CouponCodeGetResponse
@@ -471,7 +471,7 @@ func (s *QingniaoService) ConsumeCouponCode(ctx context.Context, req *CouponCode
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -526,7 +526,7 @@ func (s *QingniaoService) GetEmployees(ctx context.Context, req *EmployeeGetRequ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result EmployeeGetResponse
@@ -558,7 +558,7 @@ func (s *QingniaoService) CreateEmployee(ctx context.Context, req *EmployeeCreat
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result EmployeeCreateResponse
@@ -584,7 +584,7 @@ func (s *QingniaoService) DeleteEmployee(ctx context.Context, req *EmployeeDelet
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -624,7 +624,7 @@ func (s *QingniaoService) CreateSmartPhone(ctx context.Context, req *SmartPhoneC
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result SmartPhoneCreateResponse
@@ -671,7 +671,7 @@ func (s *QingniaoService) GetSmartPhoneList(ctx context.Context, req *QingniaoSm
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result QingniaoSmartPhoneListResponse
@@ -696,7 +696,7 @@ func (s *QingniaoService) DeleteSmartPhone(ctx context.Context, req *SmartPhoneD
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return resp.Err()
 	}
 
 	return nil
@@ -759,7 +759,7 @@ func (s *QingniaoService) GetSmartPhoneRecords(ctx context.Context, req *SmartPh
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result SmartPhoneRecordResponse
@@ -817,7 +817,7 @@ func (s *QingniaoService) GetWechatPoolList(ctx context.Context, req *WechatPool
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result WechatPoolListResponse
@@ -874,7 +874,7 @@ func (s *QingniaoService) GetWechatInstanceList(ctx context.Context, req *Wechat
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result WechatInstanceListResponse
@@ -904,7 +904,7 @@ func (s *QingniaoService) GetWechatInstanceDetail(ctx context.Context, req *Wech
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result WechatInstance
@@ -936,7 +936,7 @@ func (s *QingniaoService) UpdateWechatInstance(ctx context.Context, req *WechatI
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result WechatInstanceUpdateResponse
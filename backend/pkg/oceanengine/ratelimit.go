@@ -0,0 +1,106 @@
+package oceanengine
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// EndpointLimit 单个接口的限流额度
+type EndpointLimit struct {
+	QPS   float64
+	Burst int
+}
+
+// RateLimiter 应用级接口限流器
+// 开放平台按应用对每个接口单独限制 QPS，每个接口路径使用独立的令牌桶，未单独配置的接口使用默认额度
+type RateLimiter struct {
+	def       EndpointLimit
+	endpoints map[string]EndpointLimit
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter 创建限流器，QPS <= 0 的接口不限流
+func NewRateLimiter(def EndpointLimit, endpoints map[string]EndpointLimit) *RateLimiter {
+	l := &RateLimiter{
+		def:       def,
+		endpoints: make(map[string]EndpointLimit, len(endpoints)),
+		limiters:  make(map[string]*rate.Limiter),
+	}
+	for path, limit := range endpoints {
+		l.endpoints[normalizeEndpoint(path)] = limit
+	}
+	return l
+}
+
+// Wait 等待指定接口的令牌，ctx 取消时返回错误
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	limiter := l.limiter(normalizeEndpoint(endpoint))
+	if limiter == nil {
+		return ctx.Err()
+	}
+	return limiter.Wait(ctx)
+}
+
+func (l *RateLimiter) limiter(endpoint string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok := l.limiters[endpoint]; ok {
+		return limiter
+	}
+
+	limit, ok := l.endpoints[endpoint]
+	if !ok {
+		limit = l.def
+	}
+	var limiter *rate.Limiter
+	if limit.QPS > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+	}
+	l.limiters[endpoint] = limiter
+	return limiter
+}
+
+// normalizeEndpoint 统一接口路径格式为 /2/xxx/get/
+func normalizeEndpoint(path string) string {
+	path = "/" + strings.Trim(path, "/") + "/"
+	return strings.ToLower(path)
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = make(map[string]*RateLimiter)
+)
+
+// SharedRateLimiter 返回应用共享的限流器
+// 服务层按请求创建客户端，同一应用的客户端需共用令牌桶才能真正限制应用级 QPS；首次创建时的额度生效
+func SharedRateLimiter(appID string, def EndpointLimit, endpoints map[string]EndpointLimit) *RateLimiter {
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+
+	if l, ok := sharedLimiters[appID]; ok {
+		return l
+	}
+	l := NewRateLimiter(def, endpoints)
+	sharedLimiters[appID] = l
+	return l
+}
+
+// endpointOf 返回请求相对于 baseURL 的接口路径
+func (c *Client) endpointOf(u *url.URL) string {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return u.Path
+	}
+	return strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/"))
+}
@@ -0,0 +1,63 @@
+package oceanengine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_PerEndpoint(t *testing.T) {
+	l := NewRateLimiter(EndpointLimit{QPS: 1, Burst: 1}, map[string]EndpointLimit{
+		"2/report/advertiser/get": {QPS: 1000, Burst: 5},
+		"/2/unlimited/":           {QPS: 0},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// 单独配置的接口按自身额度
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Wait(ctx, "/2/report/advertiser/get/"))
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, l.Wait(ctx, "/2/unlimited/"))
+	}
+
+	// 未配置的接口各自使用默认额度，互不影响
+	require.NoError(t, l.Wait(ctx, "/2/advertiser/info/"))
+	require.NoError(t, l.Wait(ctx, "/2/campaign/get/"))
+	assert.Error(t, l.Wait(ctx, "/2/advertiser/info/"))
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"code":0,"message":"OK","data":{}}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(EndpointLimit{QPS: 1, Burst: 1}, nil)
+	client := NewClient("app", "secret", WithBaseURL(server.URL+"/open_api"), WithRateLimiter(limiter))
+
+	_, err := client.Get(context.Background(), "/2/advertiser/info/", nil)
+	require.NoError(t, err)
+
+	// 副本共享限流器
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.WithAccessToken("token").Get(ctx, "/2/advertiser/info/", nil)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"/open_api/2/advertiser/info/"}, paths)
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	def := EndpointLimit{QPS: 5, Burst: 5}
+	a := SharedRateLimiter("shared-app", def, nil)
+	assert.Same(t, a, SharedRateLimiter("shared-app", def, nil))
+	assert.NotSame(t, a, SharedRateLimiter("other-app", def, nil))
+}
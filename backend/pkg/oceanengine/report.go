@@ -97,7 +97,7 @@ func (s *ReportService) GetAdvertiserReport(ctx context.Context, req *ReportRequ
 	}
 
 	if !resp.IsSuccess() {
-		return nil, nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, nil, resp.Err()
 	}
 
 	var result ReportResponse
@@ -121,7 +121,7 @@ func (s *ReportService) GetCampaignReport(ctx context.Context, req *ReportReques
 	}
 
 	if !resp.IsSuccess() {
-		return nil, nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, nil, resp.Err()
 	}
 
 	var result ReportResponse
@@ -145,7 +145,7 @@ func (s *ReportService) GetAdReport(ctx context.Context, req *ReportRequest) ([]
 	}
 
 	if !resp.IsSuccess() {
-		return nil, nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, nil, resp.Err()
 	}
 
 	var result ReportResponse
@@ -169,7 +169,7 @@ func (s *ReportService) GetCreativeReport(ctx context.Context, req *ReportReques
 	}
 
 	if !resp.IsSuccess() {
-		return nil, nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, nil, resp.Err()
 	}
 
 	var result ReportResponse
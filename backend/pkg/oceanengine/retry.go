@@ -0,0 +1,67 @@
+package oceanengine
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries 默认最大重试次数
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay 默认首次重试等待时间
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	// DefaultRetryMaxDelay 默认单次重试最大等待时间
+	DefaultRetryMaxDelay = 10 * time.Second
+)
+
+// RetryPolicy 重试策略（指数退避 + 随机抖动）
+type RetryPolicy struct {
+	MaxRetries int           // 最大重试次数（不含首次请求），0 表示不重试
+	BaseDelay  time.Duration // 首次重试等待时间
+	MaxDelay   time.Duration // 单次等待时间上限
+}
+
+// DefaultRetryPolicy 默认重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultRetryBaseDelay,
+		MaxDelay:   DefaultRetryMaxDelay,
+	}
+}
+
+// Backoff 计算第 attempt 次重试前的等待时间（attempt 从 1 开始）
+// 采用 full jitter：在 [0, min(MaxDelay, BaseDelay*2^(attempt-1))] 内随机取值
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			delay = p.MaxDelay
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// sleepContext 等待指定时间，ctx 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
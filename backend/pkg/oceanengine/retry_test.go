@@ -0,0 +1,216 @@
+package oceanengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestClient() *Client {
+	client := NewClient("test-app-id", "test-secret")
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+	})
+	return client
+}
+
+func writeBaseResponse(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BaseResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: "test-request-id",
+		Data:      json.RawMessage(`{"ok":true}`),
+	})
+}
+
+func TestClient_RetryOnRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"advertiser_id":1}`, string(body))
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			writeBaseResponse(w, CodeRateLimit, "请求过于频繁")
+			return
+		}
+		writeBaseResponse(w, 0, "OK")
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader(`{"advertiser_id":1}`))
+	require.NoError(t, err)
+
+	resp, err := client.doRequest(req)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClient_NoRetryOnBusinessError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeBaseResponse(w, 40002, "参数错误")
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.doRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	apiErr, ok := AsAPIError(resp.Err())
+	require.True(t, ok)
+	assert.Equal(t, 40002, apiErr.Code)
+	assert.Equal(t, "test-request-id", apiErr.RequestID)
+	assert.Equal(t, http.StatusOK, apiErr.HTTPStatus)
+	assert.False(t, apiErr.IsRetryable())
+}
+
+func TestClient_RetryExhaustedOnServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("bad gateway"))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.doRequest(req)
+	require.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	apiErr, ok := AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadGateway, apiErr.HTTPStatus)
+	assert.True(t, IsRetryable(err))
+}
+
+func TestClient_RetryHonoursContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeBaseResponse(w, CodeSystemBusy, "系统繁忙")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-app-id", "test-secret")
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.doRequest(req)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_NoRetryPostAfterSent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// 服务端已收到请求，应答前断开连接
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader(`{"advertiser_id":1}`))
+	require.NoError(t, err)
+	_, err = client.doRequest(req)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// GET 请求可安全重放
+	atomic.StoreInt32(&calls, 0)
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.doRequest(req)
+	require.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestIsRetryableRequest(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://ad.oceanengine.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "https://ad.oceanengine.com", Err: io.ErrUnexpectedEOF}
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		want   bool
+	}{
+		{"get eof", http.MethodGet, readErr, true},
+		{"get http 502", http.MethodGet, &APIError{HTTPStatus: http.StatusBadGateway}, true},
+		{"post dial", http.MethodPost, dialErr, true},
+		{"post eof", http.MethodPost, readErr, false},
+		{"post rate limit", http.MethodPost, &APIError{Code: CodeRateLimit}, true},
+		{"post system busy", http.MethodPost, &APIError{Code: CodeSystemBusy}, true},
+		{"post system error", http.MethodPost, &APIError{Code: CodeSystemError}, false},
+		{"post http 502", http.MethodPost, &APIError{HTTPStatus: http.StatusBadGateway}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableRequest(tt.method, tt.err))
+		})
+	}
+}
+
+func TestAPIError_IsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want bool
+	}{
+		{"rate limit", &APIError{Code: CodeRateLimit}, true},
+		{"app rate limit", &APIError{Code: 40113}, true},
+		{"system busy", &APIError{Code: CodeSystemBusy}, true},
+		{"http 503", &APIError{HTTPStatus: http.StatusServiceUnavailable}, true},
+		{"http 429", &APIError{HTTPStatus: http.StatusTooManyRequests}, true},
+		{"invalid param", &APIError{Code: 40002}, false},
+		{"token expired", &APIError{Code: 40105}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.IsRetryable())
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := policy.Backoff(attempt)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, time.Second)
+	}
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+}
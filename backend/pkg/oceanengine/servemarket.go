@@ -3,7 +3,6 @@ package oceanengine
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 )
 
 // ServeMarketClient 服务市场API客户端
@@ -375,7 +374,7 @@ func (s *ServeMarketClient) VerifyAppAccessToken(ctx context.Context, token stri
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("api error: code=%d, message=%s", resp.Code, resp.Message)
+		return nil, resp.Err()
 	}
 
 	var result struct {
@@ -0,0 +1,208 @@
+package oceanengine
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrUploadSizeMismatch 上传文件实际大小与 UploadRequest.Size 不一致
+var ErrUploadSizeMismatch = errors.New("upload file size mismatch")
+
+// UploadSource 上传文件来源，每次调用返回从头读取的 Reader；请求重试时重新打开
+type UploadSource func() (io.ReadCloser, error)
+
+// FileSource 本地文件来源
+func FileSource(path string) UploadSource {
+	return func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+}
+
+// UploadProgress 上传进度回调，sent 为已发送的文件字节数，total 未知时为 -1
+type UploadProgress func(sent, total int64)
+
+// UploadRequest 文件上传请求 (multipart/form-data)
+// 文件内容经 io.Pipe 边读边发，不在内存中缓存
+type UploadRequest struct {
+	Path      string            // 接口路径
+	FileField string            // 文件字段名，如 video_file
+	FileName  string            // 文件名
+	Fields    map[string]string // 其他字段
+
+	// Source 文件来源，可重新打开，支持失败重试
+	Source UploadSource
+	// Reader 文件内容，Source 为空时使用；只能读取一次，不重试
+	Reader io.Reader
+	// Size 文件大小，> 0 时请求携带准确的 Content-Length，否则使用 chunked 传输
+	Size int64
+	// SignatureField 非空时上传过程中同步计算文件 MD5，并在文件之后写入该字段，如 video_signature
+	SignatureField string
+	// Progress 上传进度回调
+	Progress UploadProgress
+}
+
+// open 打开文件来源
+func (r *UploadRequest) open() (io.ReadCloser, error) {
+	if r.Source != nil {
+		return r.Source()
+	}
+	if r.Reader == nil {
+		return nil, errors.New("upload source is empty")
+	}
+	return io.NopCloser(r.Reader), nil
+}
+
+// writeTo 写入 multipart 内容，其他字段按字段名排序以保证长度计算一致
+func (r *UploadRequest) writeTo(w io.Writer, boundary string, file io.Reader, progress bool) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(r.Fields))
+	for key := range r.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := mw.WriteField(key, r.Fields[key]); err != nil {
+			return fmt.Errorf("write field %s failed: %w", key, err)
+		}
+	}
+
+	part, err := mw.CreateFormFile(r.FileField, r.FileName)
+	if err != nil {
+		return fmt.Errorf("create form file failed: %w", err)
+	}
+	hash := md5.New()
+	if r.SignatureField != "" {
+		file = io.TeeReader(file, hash)
+	}
+	if progress && r.Progress != nil {
+		total := r.Size
+		if total <= 0 {
+			total = -1
+		}
+		file = &progressReader{r: file, total: total, fn: r.Progress}
+	}
+	n, err := io.Copy(part, file)
+	if err != nil {
+		return fmt.Errorf("copy file failed: %w", err)
+	}
+	if progress && r.Size > 0 && n != r.Size {
+		return fmt.Errorf("%w: expect %d, read %d", ErrUploadSizeMismatch, r.Size, n)
+	}
+	if r.SignatureField != "" {
+		if err := mw.WriteField(r.SignatureField, hex.EncodeToString(hash.Sum(nil))); err != nil {
+			return fmt.Errorf("write field %s failed: %w", r.SignatureField, err)
+		}
+	}
+	return mw.Close()
+}
+
+// contentLength 文件大小已知时返回请求体长度，否则返回 -1
+// MD5 签名长度固定，使用空文件计算除文件内容以外的长度
+func (r *UploadRequest) contentLength(boundary string) int64 {
+	if r.Size <= 0 {
+		return -1
+	}
+	var w countWriter
+	if err := r.writeTo(&w, boundary, strings.NewReader(""), false); err != nil {
+		return -1
+	}
+	return int64(w) + r.Size
+}
+
+// body 打开文件并返回流式请求体
+func (r *UploadRequest) body(boundary string) (io.ReadCloser, error) {
+	file, err := r.open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload source failed: %w", err)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		defer file.Close()
+		pw.CloseWithError(r.writeTo(pw, boundary, file, true))
+	}()
+	return pr, nil
+}
+
+// Upload 流式上传文件 (multipart/form-data)
+// Source 可重新打开时按重试策略重试，重试时重新读取文件
+func (c *Client) Upload(ctx context.Context, accessToken string, upload *UploadRequest) (*BaseResponse, error) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	body, err := upload.body(boundary)
+	if err != nil {
+		return nil, err
+	}
+	// 请求未发出时关闭管道，结束写入 goroutine
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+upload.Path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	if l := upload.contentLength(boundary); l >= 0 {
+		req.ContentLength = l
+	}
+	if upload.Source != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return upload.body(boundary)
+		}
+	}
+	if accessToken != "" {
+		req.Header.Set("Access-Token", accessToken)
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	return c.doRequest(req)
+}
+
+type countWriter int64
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// progressReader 读取时回调上传进度
+type progressReader struct {
+	r     io.Reader
+	fn    UploadProgress
+	sent  int64
+	total int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.fn(r.sent, r.total)
+	}
+	return n, err
+}
+
+// fileUpload 根据本地文件创建上传请求
+func fileUpload(path, fileField, filePath string, fields map[string]string) (*UploadRequest, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open file failed: %w", err)
+	}
+	return &UploadRequest{
+		Path:      path,
+		FileField: fileField,
+		FileName:  filepath.Base(filePath),
+		Fields:    fields,
+		Source:    FileSource(filePath),
+		Size:      info.Size(),
+	}, nil
+}
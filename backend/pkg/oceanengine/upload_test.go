@@ -0,0 +1,105 @@
+package oceanengine
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_UploadFile_Stream(t *testing.T) {
+	content := strings.Repeat("video-content-", 10000)
+	filePath := filepath.Join(t.TempDir(), "a.mp4")
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+	sum := md5.Sum([]byte(content))
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1.0/qianchuan/file/video/ad/", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("Access-Token"))
+		assert.Greater(t, r.ContentLength, int64(len(content)))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		file, header, err := r.FormFile("video_file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, content, string(data))
+		assert.Equal(t, "a.mp4", header.Filename)
+		assert.Equal(t, "1001", r.FormValue("advertiser_id"))
+		assert.Equal(t, hex.EncodeToString(sum[:]), r.FormValue("video_signature"))
+
+		// 首次请求限流，重试时重新打开文件
+		if atomic.AddInt32(&calls, 1) == 1 {
+			writeBaseResponse(w, CodeRateLimit, "请求过于频繁")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"OK","data":{"video_id":"v1"}}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	client.baseURL = server.URL
+	resp, err := client.Qianchuan().UploadVideo(context.Background(), "token", 1001, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", resp.VideoID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Upload_Reader(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2/file/image/ad/" {
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, int64(-1), r.ContentLength)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "x", r.FormValue("filename"))
+		writeBaseResponse(w, CodeRateLimit, "请求过于频繁")
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	client.baseURL = server.URL
+
+	var sent, total int64
+	resp, err := client.Upload(context.Background(), "token", &UploadRequest{
+		Path:      "/2/file/image/ad/",
+		FileField: "image_file",
+		FileName:  "a.png",
+		Fields:    map[string]string{"filename": "x"},
+		Reader:    strings.NewReader("png"),
+		Progress: func(s, t int64) {
+			sent, total = s, t
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.IsSuccess())
+	// Reader 只能读取一次，不重试
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(3), sent)
+	assert.Equal(t, int64(-1), total)
+
+	// 文件实际大小与 Size 不一致
+	_, err = client.Upload(context.Background(), "token", &UploadRequest{
+		Path:      "/2/file/video/ad/",
+		FileField: "image_file",
+		FileName:  "a.png",
+		Size:      10,
+		Source: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("png")), nil
+		},
+	})
+	assert.ErrorIs(t, err, ErrUploadSizeMismatch)
+}
@@ -1,7 +1,10 @@
 package oceanengine
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 )
 
 // V3Client v3体验版客户端
@@ -705,10 +708,12 @@ type V3ReportRequest struct {
 	EndDate      string   `json:"end_date"`
 	Fields       []string `json:"fields,omitempty"`
 	GroupBy      []string `json:"group_by,omitempty"`
-	OrderField   string   `json:"order_field,omitempty"`
-	OrderType    string   `json:"order_type,omitempty"`
-	Page         int      `json:"page,omitempty"`
-	PageSize     int      `json:"page_size,omitempty"`
+	// TimeGranularity 时间粒度，如 STAT_TIME_GRANULARITY_DAILY
+	TimeGranularity string `json:"time_granularity,omitempty"`
+	OrderField      string `json:"order_field,omitempty"`
+	OrderType       string `json:"order_type,omitempty"`
+	Page            int    `json:"page,omitempty"`
+	PageSize        int    `json:"page_size,omitempty"`
 }
 
 // V3ReportData 报表数据
@@ -724,59 +729,64 @@ type V3ReportData struct {
 
 // GetProjectReport 项目数据报表
 func (v *V3Client) GetProjectReport(ctx context.Context, accessToken string, req *V3ReportRequest) (*V3ReportData, error) {
-	path := "/v3.0/report/project/get/"
-	var result struct {
-		Data V3ReportData `json:"data"`
-	}
-	err := v.client.GetWithToken(ctx, accessToken, path, map[string]interface{}{
-		"advertiser_id": req.AdvertiserID,
-		"start_date":    req.StartDate,
-		"end_date":      req.EndDate,
-		"page":          req.Page,
-		"page_size":     req.PageSize,
-	}, &result)
-	if err != nil {
-		return nil, err
-	}
-	return &result.Data, nil
+	return v.getReport(ctx, accessToken, "/v3.0/report/project/get/", req)
 }
 
 // GetPromotionReport 广告数据报表
 func (v *V3Client) GetPromotionReport(ctx context.Context, accessToken string, req *V3ReportRequest) (*V3ReportData, error) {
-	path := "/v3.0/report/promotion/get/"
-	var result struct {
-		Data V3ReportData `json:"data"`
-	}
-	err := v.client.GetWithToken(ctx, accessToken, path, map[string]interface{}{
-		"advertiser_id": req.AdvertiserID,
-		"start_date":    req.StartDate,
-		"end_date":      req.EndDate,
-		"page":          req.Page,
-		"page_size":     req.PageSize,
-	}, &result)
-	if err != nil {
-		return nil, err
-	}
-	return &result.Data, nil
+	return v.getReport(ctx, accessToken, "/v3.0/report/promotion/get/", req)
 }
 
 // GetMaterialReport 素材数据报表
 func (v *V3Client) GetMaterialReport(ctx context.Context, accessToken string, req *V3ReportRequest) (*V3ReportData, error) {
-	path := "/v3.0/report/material/get/"
+	return v.getReport(ctx, accessToken, "/v3.0/report/material/get/", req)
+}
+
+// getReport 获取报表数据
+// 数值以 json.Number 解码，避免 19 位 ID 转为 float64 后丢失精度
+func (v *V3Client) getReport(ctx context.Context, accessToken, path string, req *V3ReportRequest) (*V3ReportData, error) {
 	var result struct {
-		Data V3ReportData `json:"data"`
+		Data json.RawMessage `json:"data"`
 	}
-	err := v.client.GetWithToken(ctx, accessToken, path, map[string]interface{}{
+	params := map[string]interface{}{
 		"advertiser_id": req.AdvertiserID,
 		"start_date":    req.StartDate,
 		"end_date":      req.EndDate,
 		"page":          req.Page,
 		"page_size":     req.PageSize,
-	}, &result)
+	}
+	if len(req.Fields) > 0 {
+		fields, _ := json.Marshal(req.Fields)
+		params["fields"] = string(fields)
+	}
+	if len(req.GroupBy) > 0 {
+		groupBy, _ := json.Marshal(req.GroupBy)
+		params["group_by"] = string(groupBy)
+	}
+	if req.TimeGranularity != "" {
+		params["time_granularity"] = req.TimeGranularity
+	}
+	if req.OrderField != "" {
+		params["order_field"] = req.OrderField
+	}
+	if req.OrderType != "" {
+		params["order_type"] = req.OrderType
+	}
+	err := v.client.GetWithToken(ctx, accessToken, path, params, &result)
 	if err != nil {
 		return nil, err
 	}
-	return &result.Data, nil
+
+	var data V3ReportData
+	if len(result.Data) == 0 {
+		return &data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(result.Data))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("unmarshal report data failed: %w", err)
+	}
+	return &data, nil
 }
 
 // GetCustomReport 自定义报表
@@ -0,0 +1,266 @@
+package oceansdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
+	"go.uber.org/zap"
+
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+// TokenResolver 按广告主解析 access_token，advertiser/service.TokenService 实现了该接口
+type TokenResolver interface {
+	GetAccessToken(ctx context.Context, advertiserID uint64) (string, error)
+}
+
+// Client 基于 sdk/marketing-api 的巨量引擎客户端
+// 提供配置好的 core.SDKClient、access_token 解析与错误码映射，业务代码直接调用 SDK 的强类型接口
+type Client struct {
+	sdk        *core.SDKClient
+	tokens     TokenResolver
+	httpClient *http.Client
+	baseURL    string
+	limiter    *oceanengine.RateLimiter
+	retry      oceanengine.RetryPolicy
+	logger     *zap.Logger
+	sandbox    bool
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithTokenResolver 设置 access_token 解析，上下文中没有 access_token 时按广告主ID解析
+func WithTokenResolver(tokens TokenResolver) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithHTTPClient 使用自定义 http.Client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL 设置 API 基础地址，SDK 请求 ad.oceanengine.com/api.oceanengine.com 的 open_api 时改写到该地址
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithRateLimiter 设置接口限流器，与 oceanengine.Client 共用时两者合计不超过配置的 QPS
+func WithRateLimiter(limiter *oceanengine.RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryCount 设置最大重试次数
+func WithRetryCount(count int) Option {
+	return func(c *Client) {
+		if count >= 0 {
+			c.retry.MaxRetries = count
+		}
+	}
+}
+
+// WithRetryPolicy 设置重试策略
+func WithRetryPolicy(policy oceanengine.RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithSandbox 启用沙箱模式
+func WithSandbox(enabled bool) Option {
+	return func(c *Client) {
+		c.sandbox = enabled
+	}
+}
+
+// WithLogger 设置请求日志，每次调用记录接口、广告主、耗时、返回码与 request_id
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient 创建客户端，默认启用 SDK 请求参数校验
+func NewClient(appID uint64, secret string, opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: oceanengine.DefaultTimeout},
+		baseURL:    oceanengine.BaseURL,
+		retry:      oceanengine.DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.sdk = core.NewSDKClient(appID, secret)
+	c.sdk.SetValidation(true)
+	if c.sandbox {
+		c.sdk.UseSandbox()
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = newRewriteTransport(c.baseURL, httpClient.Transport)
+	c.sdk.SetHttpClient(&httpClient)
+	if c.limiter != nil {
+		c.sdk.SetRateLimiterV2(rateLimiter{limiter: c.limiter})
+	}
+	if c.logger != nil {
+		c.sdk.Use(core.Logging(zapLogger{logger: c.logger}))
+	}
+	if c.retry.MaxRetries > 0 {
+		c.sdk.Use(retryMiddleware(c.retry))
+	}
+	return c
+}
+
+// SDK 返回 SDK 客户端
+func (c *Client) SDK() *core.SDKClient {
+	return c.sdk
+}
+
+type accessTokenKey struct{}
+
+// WithAccessToken 将已解析的 access_token 写入上下文，如 middleware.OceanAccessToken 注入的 token
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
+// AccessToken 获取调用 SDK 使用的 access_token：优先使用上下文中的 token，其次按广告主ID解析
+func (c *Client) AccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	if token, _ := ctx.Value(accessTokenKey{}).(string); token != "" {
+		return token, nil
+	}
+	if c.tokens == nil || advertiserID == 0 {
+		return "", errcode.New(errcode.ErrOETokenInvalid)
+	}
+	token, err := c.tokens.GetAccessToken(ctx, advertiserID)
+	if err != nil {
+		var appErr *errcode.AppError
+		if errors.As(err, &appErr) {
+			return "", appErr
+		}
+		return "", errcode.Wrap(errcode.ErrOETokenInvalid, err)
+	}
+	return token, nil
+}
+
+// Call 解析 access_token 后调用 SDK 接口，错误转换为 *errcode.AppError
+//
+//	list, err := oceansdk.Call(ctx, client, advertiserID, project.List, req)
+func Call[Req any, Resp any](ctx context.Context, c *Client, advertiserID uint64, fn func(context.Context, *core.SDKClient, string, Req) (Resp, error), req Req) (Resp, error) {
+	var zero Resp
+	token, err := c.AccessToken(ctx, advertiserID)
+	if err != nil {
+		return zero, err
+	}
+	resp, err := fn(ctx, c.sdk, token, req)
+	if err != nil {
+		return zero, MapError(err)
+	}
+	return resp, nil
+}
+
+// Exec 同 Call，用于只返回 error 的接口
+func Exec[Req any](ctx context.Context, c *Client, advertiserID uint64, fn func(context.Context, *core.SDKClient, string, Req) error, req Req) error {
+	token, err := c.AccessToken(ctx, advertiserID)
+	if err != nil {
+		return err
+	}
+	if err := fn(ctx, c.sdk, token, req); err != nil {
+		return MapError(err)
+	}
+	return nil
+}
+
+// MapError 将 SDK 返回的错误转换为 *errcode.AppError：
+// 请求参数校验失败为 ErrInvalidParams (400)，Details 为字段错误列表；
+// 巨量引擎返回码按 errcode.FromOceanEngine 映射，其他错误 (网络、超时等) 为 ErrOEAPIFailed
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *errcode.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var fieldErrs validate.Errors
+	if errors.As(err, &fieldErrs) {
+		msgs := make([]string, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			msgs = append(msgs, fe.Error())
+		}
+		return errcode.WrapWithMessage(errcode.ErrInvalidParams, strings.Join(msgs, "; "), err).WithDetails(fieldErrs)
+	}
+	if oeErr := errcode.FromOceanEngine(err); oeErr != nil {
+		return oeErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errcode.Wrap(errcode.ErrTimeout, err)
+	}
+	return errcode.Wrap(errcode.ErrOEAPIFailed, err)
+}
+
+// rateLimiter 将 oceanengine.RateLimiter 适配为 SDK 的 RateLimiterV2
+type rateLimiter struct {
+	limiter *oceanengine.RateLimiter
+}
+
+// Wait implement core.RateLimiterV2
+func (l rateLimiter) Wait(ctx context.Context, key core.RateLimitKey) error {
+	return l.limiter.Wait(ctx, key.Path)
+}
+
+// rewriteTransport 将 SDK 默认的 open_api 地址改写为配置的 BaseURL (沙箱、本地模拟服务)
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+// sdkBaseURLs SDK 内置的 open_api 地址
+var sdkBaseURLs = []string{core.BASE_URL, core.API_BASE_URL}
+
+func newRewriteTransport(baseURL string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	target, err := url.Parse(strings.TrimRight(baseURL, "/") + "/")
+	if err != nil || target.String() == core.BASE_URL {
+		return next
+	}
+	return &rewriteTransport{target: target, next: next}
+}
+
+// RoundTrip implement http.RoundTripper
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	raw := req.URL.String()
+	for _, base := range sdkBaseURLs {
+		if !strings.HasPrefix(raw, base) {
+			continue
+		}
+		u, err := url.Parse(t.target.String() + strings.TrimPrefix(raw, base))
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.URL = u
+		req.Host = u.Host
+		break
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,152 @@
+package oceansdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/local/project"
+	projectModel "github.com/bububa/oceanengine/marketing-api/model/local/project"
+	"github.com/bububa/oceanengine/marketing-api/testing/oetest"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"oceanengine-backend/pkg/errcode"
+	"oceanengine-backend/pkg/oceanengine"
+	"oceanengine-backend/pkg/oceansdk"
+)
+
+type tokenResolver map[uint64]string
+
+func (r tokenResolver) GetAccessToken(ctx context.Context, advertiserID uint64) (string, error) {
+	if token, ok := r[advertiserID]; ok {
+		return token, nil
+	}
+	return "", errors.New("token not found")
+}
+
+func newClient(t *testing.T, opts ...oceansdk.Option) (*oetest.Server, *oceansdk.Client) {
+	srv := oetest.NewServer(t)
+	opts = append([]oceansdk.Option{
+		oceansdk.WithBaseURL(srv.URL + "/open_api"),
+		oceansdk.WithHTTPClient(srv.Client()),
+		oceansdk.WithRetryCount(0),
+	}, opts...)
+	return srv, oceansdk.NewClient(1, "secret", opts...)
+}
+
+func TestCall(t *testing.T) {
+	srv, clt := newClient(t, oceansdk.WithTokenResolver(tokenResolver{100: "resolved-token"}))
+	route := srv.Handle("v3.0/local/project/list").
+		Reply(map[string]any{"project_list": []map[string]any{{"project_id": 1}}})
+
+	req := &projectModel.ListRequest{LocalAccountID: 100}
+	result, err := oceansdk.Call(context.Background(), clt, 100, project.List, req)
+	require.NoError(t, err)
+	require.Len(t, result.ProjectList, 1)
+	assert.Equal(t, uint64(1), result.ProjectList[0].ProjectID)
+	assert.Equal(t, "resolved-token", srv.Requests()[0].AccessToken())
+
+	// 上下文中的 token 优先
+	ctx := oceansdk.WithAccessToken(context.Background(), "ctx-token")
+	_, err = oceansdk.Call(ctx, clt, 100, project.List, req)
+	require.NoError(t, err)
+	assert.Equal(t, "ctx-token", srv.Requests()[1].AccessToken())
+	assert.Equal(t, 2, route.Calls())
+
+	// 无法解析 token 时不发出请求
+	_, err = oceansdk.Call(context.Background(), clt, 200, project.List, req)
+	var appErr *errcode.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errcode.ErrOETokenInvalid, appErr.Code)
+	assert.Equal(t, 2, route.Calls())
+}
+
+func TestCallError(t *testing.T) {
+	srv, clt := newClient(t)
+	srv.Handle("v3.0/local/project/list").Fail(40102, "access token expired")
+
+	ctx := oceansdk.WithAccessToken(context.Background(), "token")
+	_, err := oceansdk.Call(ctx, clt, 100, project.List, &projectModel.ListRequest{LocalAccountID: 100})
+	var appErr *errcode.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errcode.ErrOETokenExpired, appErr.Code)
+	assert.Equal(t, errcode.OceanEngineDetails{Code: 40102, Message: "access token expired", RequestID: oetest.RequestID}, appErr.Details)
+}
+
+func TestRetry(t *testing.T) {
+	srv, clt := newClient(t, oceansdk.WithRetryPolicy(oceanengine.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}))
+	route := srv.Handle("v3.0/local/project/status/update").Fail(51010, "system busy")
+
+	ctx := oceansdk.WithAccessToken(context.Background(), "token")
+	req := &projectModel.StatusUpdateRequest{
+		LocalAccountID: 100,
+		Data:           []projectModel.StatusUpdateItem{{ProjectID: 1, OptStatus: "ENABLE"}},
+	}
+	_, err := oceansdk.Call(ctx, clt, 100, project.StatusUpdate, req)
+	var appErr *errcode.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errcode.ErrOESystemBusy, appErr.Code)
+	assert.Equal(t, 3, route.Calls())
+
+	// 重试时重放请求体
+	for _, r := range srv.Requests() {
+		var body projectModel.StatusUpdateRequest
+		require.NoError(t, r.DecodeJSON(&body))
+		assert.Equal(t, req.Data, body.Data)
+	}
+}
+
+func TestRetryPostOnlyWhenNotExecuted(t *testing.T) {
+	srv, clt := newClient(t, oceansdk.WithRetryPolicy(oceanengine.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}))
+	// 系统错误时请求可能已被执行，POST 不重试
+	route := srv.Handle("v3.0/local/project/status/update").Fail(50000, "system error")
+
+	ctx := oceansdk.WithAccessToken(context.Background(), "token")
+	req := &projectModel.StatusUpdateRequest{
+		LocalAccountID: 100,
+		Data:           []projectModel.StatusUpdateItem{{ProjectID: 1, OptStatus: "ENABLE"}},
+	}
+	_, err := oceansdk.Call(ctx, clt, 100, project.StatusUpdate, req)
+	require.Error(t, err)
+	assert.Equal(t, 1, route.Calls())
+}
+
+func TestMapError(t *testing.T) {
+	assert.Nil(t, oceansdk.MapError(nil))
+
+	appErr := errcode.New(errcode.ErrNotFound)
+	assert.Same(t, appErr, oceansdk.MapError(appErr))
+
+	var mapped *errcode.AppError
+	require.ErrorAs(t, oceansdk.MapError(context.DeadlineExceeded), &mapped)
+	assert.Equal(t, errcode.ErrTimeout, mapped.Code)
+
+	require.ErrorAs(t, oceansdk.MapError(errors.New("connection reset")), &mapped)
+	assert.Equal(t, errcode.ErrOEAPIFailed, mapped.Code)
+}
+
+func TestValidation(t *testing.T) {
+	srv, clt := newClient(t)
+	route := srv.Handle("v3.0/local/project/status/update").Reply(map[string]any{"project_ids": []uint64{1}})
+
+	ctx := oceansdk.WithAccessToken(context.Background(), "token")
+	req := &projectModel.StatusUpdateRequest{
+		LocalAccountID: 100,
+		Data:           []projectModel.StatusUpdateItem{{ProjectID: 1, OptStatus: "DISABLE"}},
+	}
+	_, err := oceansdk.Call(ctx, clt, 100, project.StatusUpdate, req)
+	var appErr *errcode.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errcode.ErrInvalidParams, appErr.Code)
+	assert.Equal(t, http.StatusBadRequest, appErr.HTTPStatus())
+	assert.Contains(t, appErr.Message, "data[0].opt_status")
+	fieldErrs, ok := appErr.Details.(validate.Errors)
+	require.True(t, ok)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "oneof", fieldErrs[0].Rule)
+	assert.Zero(t, route.Calls())
+}
@@ -0,0 +1,107 @@
+package oceansdk
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/core"
+	sdkerrcode "github.com/bububa/oceanengine/marketing-api/model/errcode"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"oceanengine-backend/pkg/oceanengine"
+)
+
+// zapLogger 将 zap.Logger 适配为 SDK 的 core.Logger
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+// LogAttrs implement core.Logger
+func (l zapLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, zapField(attr))
+	}
+	if ce := l.logger.Check(zapLevel(level), msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func zapField(attr slog.Attr) zap.Field {
+	v := attr.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return zap.String(attr.Key, v.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, v.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, v.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, v.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, v.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, v.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, v.Time())
+	default:
+		return zap.Any(attr.Key, v.Any())
+	}
+}
+
+// retryMiddleware 按 policy 退避重试，请求体无法重放时不重试
+// GET 请求在频控、系统错误及网络错误时重试；POST 请求可能已被执行，仅在频控、系统繁忙或连接未建立时重试
+func retryMiddleware(policy oceanengine.RetryPolicy) core.Middleware {
+	return func(next core.Handler) core.Handler {
+		return func(ctx context.Context, call *core.Call) error {
+			err := next(ctx, call)
+			for attempt := 1; attempt <= policy.MaxRetries && retryable(call.Request.Method, err); attempt++ {
+				if call.Request.Body != nil && call.Request.GetBody == nil {
+					return err
+				}
+				timer := time.NewTimer(policy.Backoff(attempt))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+				if call.Request.GetBody != nil {
+					body, bodyErr := call.Request.GetBody()
+					if bodyErr != nil {
+						return err
+					}
+					call.Request.Body = body
+				}
+				err = next(ctx, call)
+			}
+			return err
+		}
+	}
+}
+
+func retryable(method string, err error) bool {
+	if e, ok := sdkerrcode.From(err); ok {
+		if method == http.MethodGet {
+			return e.IsRetryable()
+		}
+		return e.Category == sdkerrcode.CategoryRateLimit || e.Code == sdkerrcode.ErrSystemBusy.Code
+	}
+	return oceanengine.IsRetryableRequest(method, err)
+}
@@ -58,19 +58,27 @@ func SuccessWithPage(c *gin.Context, list interface{}, total int64, page, pageSi
 }
 
 // Error 错误响应
+// 巨量引擎 API 错误会按返回码映射为对应的错误码，原始返回码放在 data 中
 func Error(c *gin.Context, err error) {
 	requestID := c.GetString("request_id")
 
 	if appErr, ok := err.(*errcode.AppError); ok {
+		appErr = refineOceanEngine(appErr)
 		c.JSON(appErr.HTTPStatus(), Response{
 			Code:      appErr.Code,
 			Message:   appErr.Message,
+			Data:      appErr.Details,
 			RequestID: requestID,
 			Timestamp: time.Now().UnixMilli(),
 		})
 		return
 	}
 
+	if oeErr := errcode.FromOceanEngine(err); oeErr != nil {
+		Error(c, oeErr)
+		return
+	}
+
 	// 未知错误
 	c.JSON(http.StatusInternalServerError, Response{
 		Code:      errcode.ErrInternalServer,
@@ -80,6 +88,31 @@ func Error(c *gin.Context, err error) {
 	})
 }
 
+// refineOceanEngine 将笼统的 API 调用失败细化为巨量引擎返回码对应的错误码，保留业务消息作为前缀
+func refineOceanEngine(appErr *errcode.AppError) *errcode.AppError {
+	if appErr.Code != errcode.ErrOEAPIFailed && appErr.Code != errcode.ErrInternalServer {
+		return appErr
+	}
+	oeErr := errcode.FromOceanEngine(appErr.Cause())
+	if oeErr == nil {
+		return appErr
+	}
+	if appErr.Message != errcode.Message(appErr.Code) {
+		oeErr.Message = appErr.Message + "：" + oeErr.Message
+	}
+	return oeErr
+}
+
+// UpstreamError 上游调用失败响应
+// 巨量引擎 API 错误映射为对应的错误码，其他错误按服务器内部错误处理
+func UpstreamError(c *gin.Context, err error) {
+	if oeErr := errcode.FromOceanEngine(err); oeErr != nil {
+		Error(c, oeErr)
+		return
+	}
+	InternalError(c, err.Error())
+}
+
 // ErrorWithDetails 带详情的错误响应
 func ErrorWithDetails(c *gin.Context, err *errcode.AppError, details interface{}) {
 	requestID := c.GetString("request_id")
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("storage: invalid signature")
+	ErrSignatureExpired = errors.New("storage: signature expired")
+)
+
+// Signer 下载链接签名，HMAC-SHA256(resource + "\n" + expires)
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner 创建签名器
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign 为资源生成签名，返回过期时间戳（秒）与签名
+func (s *Signer) Sign(resource string, expireAt time.Time) (int64, string) {
+	expires := expireAt.Unix()
+	return expires, s.signature(resource, expires)
+}
+
+// Verify 校验签名与有效期
+func (s *Signer) Verify(resource string, expires int64, signature string) error {
+	expected := s.signature(resource, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+	if time.Now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func (s *Signer) signature(resource string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(resource))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"oceanengine-backend/config"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// Storage 文件存储后端
+// key 为以 / 分隔的相对路径，如 exports/20240101/1.csv
+type Storage interface {
+	// Put 写入对象，返回写入字节数
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open 读取对象，调用方负责关闭
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+}
+
+// New 根据配置创建存储后端
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalPath)
+	default:
+		return nil, fmt.Errorf("storage: unsupported driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	n, err := s.Put(ctx, "exports/20240101/1.csv", strings.NewReader("a,b\n1,2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+
+	rc, err := s.Open(ctx, "exports/20240101/1.csv")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	require.NoError(t, s.Delete(ctx, "exports/20240101/1.csv"))
+	require.NoError(t, s.Delete(ctx, "exports/20240101/1.csv"))
+	_, err = s.Open(ctx, "exports/20240101/1.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", ".."} {
+		_, err := s.Put(ctx, key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestSigner(t *testing.T) {
+	s := NewSigner("secret")
+
+	expires, sig := s.Sign("export:1", time.Now().Add(time.Minute))
+	assert.NoError(t, s.Verify("export:1", expires, sig))
+	assert.ErrorIs(t, s.Verify("export:2", expires, sig), ErrSignatureInvalid)
+	assert.ErrorIs(t, s.Verify("export:1", expires+60, sig), ErrSignatureInvalid)
+	assert.ErrorIs(t, NewSigner("other").Verify("export:1", expires, sig), ErrSignatureInvalid)
+
+	expires, sig = s.Sign("export:1", time.Now().Add(-time.Second))
+	assert.ErrorIs(t, s.Verify("export:1", expires, sig), ErrSignatureExpired)
+}
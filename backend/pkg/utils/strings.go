@@ -0,0 +1,11 @@
+package utils
+
+import "unicode/utf8"
+
+// TruncateRunes 按字符数截断字符串，不会截断多字节字符（与 MySQL varchar 长度一致）
+func TruncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
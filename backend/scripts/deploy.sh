@@ -77,7 +77,7 @@ deploy_docker() {
     
     # 构建镜像
     echo -e "${YELLOW}Building Docker image...${NC}"
-    docker build -t "${IMAGE_NAME}:${IMAGE_TAG}" -f Dockerfile ../..
+    docker build -t "${IMAGE_NAME}:${IMAGE_TAG}" -f Dockerfile ../../..
     
     # 停止旧容器
     echo -e "${YELLOW}Stopping old containers...${NC}"
@@ -1,11 +1,16 @@
 package integration
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	adminModel "oceanengine-backend/internal/app/admin/model"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	campaignModel "oceanengine-backend/internal/app/campaign/model"
+	"oceanengine-backend/pkg/auth"
 )
 
 // TestAdvertiserList_Success 测试获取广告主列表
@@ -187,3 +192,71 @@ func TestAdvertiserFunds_Success(t *testing.T) {
 	err = ParseResponse(w, &resp)
 	require.NoError(t, err)
 }
+
+// TestAdvertiser_DataScope 测试仅本人数据权限只能访问分配的广告主
+func TestAdvertiser_DataScope(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	role := &adminModel.Role{Name: "投放专员", Key: "operator", Status: 1, DataScope: adminModel.DataScopeSelf}
+	require.NoError(t, ts.DB.Create(role).Error)
+	user := &adminModel.User{Username: "operator", Password: "x", RoleID: role.ID, Status: 1}
+	require.NoError(t, ts.DB.Create(user).Error)
+
+	assigned := &advModel.Advertiser{AdvertiserID: 1001, Name: "已分配"}
+	other := &advModel.Advertiser{AdvertiserID: 1002, Name: "未分配"}
+	require.NoError(t, ts.DB.Create(assigned).Error)
+	require.NoError(t, ts.DB.Create(other).Error)
+	require.NoError(t, ts.DB.Create(&adminModel.UserAdvertiser{UserID: user.ID, AdvertiserID: assigned.ID}).Error)
+
+	token, err := ts.JWTManager.GenerateToken(&auth.Claims{
+		UserID:    int64(user.ID),
+		Username:  user.Username,
+		RoleKey:   role.Key,
+		RoleID:    int64(role.ID),
+		DataScope: "5",
+	})
+	require.NoError(t, err)
+
+	w := ts.MakeRequest("GET", "/api/v1/advertisers?page=1&page_size=10", nil, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Code int `json:"code"`
+		Data struct {
+			List []struct {
+				ID uint64 `json:"id"`
+			} `json:"list"`
+			Total int64 `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, ParseResponse(w, &resp))
+	assert.Equal(t, int64(1), resp.Data.Total)
+	require.Len(t, resp.Data.List, 1)
+	assert.Equal(t, assigned.ID, resp.Data.List[0].ID)
+
+	w = ts.MakeRequest("GET", fmt.Sprintf("/api/v1/advertisers/%d", assigned.ID), nil, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = ts.MakeRequest("GET", fmt.Sprintf("/api/v1/advertisers/%d", other.ID), nil, token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// 批量操作包含权限外的记录时整体拒绝
+	mine := &campaignModel.Campaign{AdvertiserID: assigned.ID, CampaignID: 2001, Name: "已分配", Status: "ENABLE"}
+	theirs := &campaignModel.Campaign{AdvertiserID: other.ID, CampaignID: 2002, Name: "未分配", Status: "ENABLE"}
+	require.NoError(t, ts.DB.Create([]*campaignModel.Campaign{mine, theirs}).Error)
+	w = ts.MakeRequest("PUT", "/api/v1/campaigns/status", map[string]interface{}{"ids": []uint64{mine.ID, theirs.ID}, "status": "DISABLE"}, token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var count int64
+	require.NoError(t, ts.DB.Model(&campaignModel.Campaign{}).Where("status = ?", "DISABLE").Count(&count).Error)
+	assert.Zero(t, count)
+
+	w = ts.MakeRequest("PUT", "/api/v1/campaigns/status", map[string]interface{}{"ids": []uint64{mine.ID}, "status": "DISABLE"}, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 超级管理员不受限制
+	adminToken, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+	w = ts.MakeRequest("GET", fmt.Sprintf("/api/v1/advertisers/%d", other.ID), nil, adminToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
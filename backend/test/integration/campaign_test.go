@@ -1,11 +1,15 @@
 package integration
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	"oceanengine-backend/internal/app/campaign/dto"
+	"oceanengine-backend/pkg/errcode"
 )
 
 // TestCampaignList_Success 测试获取广告系列列表
@@ -191,6 +195,47 @@ func TestCampaignSync_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestCampaignSyncBatch 测试批量同步广告系列
+func TestCampaignSyncBatch(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	authorized := &advModel.Advertiser{AdvertiserID: 2001, Name: "已授权"}
+	unauthorized := &advModel.Advertiser{AdvertiserID: 2002, Name: "未授权"}
+	require.NoError(t, ts.DB.Create(authorized).Error)
+	require.NoError(t, ts.DB.Create(unauthorized).Error)
+	// 测试环境未配置加密密钥，直接写入明文 Token（读取时兼容未加密的历史数据）
+	require.NoError(t, ts.DB.Model(authorized).UpdateColumn("access_token", "token").Error)
+
+	token, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+
+	w := ts.MakeRequest("POST", "/api/v1/campaigns/sync", map[string]interface{}{
+		"advertiser_ids": []uint64{authorized.ID, unauthorized.ID, 9999},
+	}, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Code int                       `json:"code"`
+		Data dto.CampaignBatchSyncResp `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Data.Total)
+	assert.Equal(t, 1, resp.Data.Success)
+	assert.Equal(t, 2, resp.Data.Failed)
+
+	codes := map[uint64]int{}
+	for _, f := range resp.Data.Failures {
+		codes[f.AdvertiserID] = f.Code
+	}
+	assert.Equal(t, errcode.ErrOETokenInvalid, codes[unauthorized.ID])
+	assert.Equal(t, errcode.ErrAdvertiserNotFound, codes[9999])
+
+	w = ts.MakeRequest("POST", "/api/v1/campaigns/sync", map[string]interface{}{"advertiser_ids": []uint64{}}, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // --- 广告组测试 ---
 
 // TestAdList_Success 测试获取广告组列表
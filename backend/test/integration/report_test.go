@@ -1,11 +1,20 @@
 package integration
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"oceanengine-backend/config"
+	advModel "oceanengine-backend/internal/app/advertiser/model"
+	reportDto "oceanengine-backend/internal/app/report/dto"
+	reportModel "oceanengine-backend/internal/app/report/model"
+	reportService "oceanengine-backend/internal/app/report/service"
+	"oceanengine-backend/pkg/errcode"
 )
 
 // --- 数据报表测试 ---
@@ -96,6 +105,54 @@ func TestReportAd_Success(t *testing.T) {
 	assert.Equal(t, 0, resp.Code)
 }
 
+// TestReportCreative_Success 测试获取创意报表
+func TestReportCreative_Success(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	require.NoError(t, ts.DB.Create(&reportModel.CreativeReport{
+		AdvertiserID: 1, CampaignID: 10, AdID: 20, CreativeID: 30, StatDate: "2024-01-02", Cost: 12.5, Show: 100,
+	}).Error)
+
+	token, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+
+	w := ts.MakeRequest("GET", "/api/v1/reports/creative?advertiser_id=1&start_date=2024-01-01&end_date=2024-01-31", nil, token)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp Response
+	err = ParseResponse(w, &resp)
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.Code)
+	list, ok := resp.Data.([]interface{})
+	require.True(t, ok)
+	require.Len(t, list, 1)
+	assert.Equal(t, float64(30), list[0].(map[string]interface{})["creative_id"])
+}
+
+// TestReportSync_InvalidRange 测试同步报表日期范围校验
+func TestReportSync_InvalidRange(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	token, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+
+	w := ts.MakeRequest("POST", "/api/v1/reports/sync", map[string]interface{}{
+		"advertiser_id": 1,
+		"start_date":    "2024-01-01",
+		"end_date":      "2024-06-30",
+	}, token)
+
+	var resp Response
+	err = ParseResponse(w, &resp)
+	require.NoError(t, err)
+	assert.Equal(t, errcode.ErrReportDateRange, resp.Code)
+}
+
 // TestReportSync_Success 测试同步报表数据
 func TestReportSync_Success(t *testing.T) {
 	ts := NewTestServer(t)
@@ -160,6 +217,61 @@ func TestReportExportCreate_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestReportExport_Download 测试导出任务处理及签名链接下载
+func TestReportExport_Download(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	token, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+
+	adv := &advModel.Advertiser{AdvertiserID: 1001, Name: "导出测试"}
+	require.NoError(t, ts.DB.Create(adv).Error)
+	require.NoError(t, ts.DB.Create(&reportModel.AdvertiserReport{AdvertiserID: adv.ID, StatDate: "2024-01-02", Cost: 12.5, Show: 100, Click: 3}).Error)
+
+	w := ts.MakeRequest("POST", "/api/v1/reports/exports", map[string]interface{}{
+		"advertiser_id": adv.ID,
+		"task_type":     "ADVERTISER",
+		"start_date":    "2024-01-01",
+		"end_date":      "2024-01-31",
+	}, token)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var created struct {
+		Code int                      `json:"code"`
+		Data reportDto.ExportTaskResp `json:"data"`
+	}
+	require.NoError(t, ParseResponse(w, &created))
+	require.Equal(t, 0, created.Code)
+	assert.Equal(t, "PENDING", created.Data.Status)
+	assert.Empty(t, created.Data.DownloadURL)
+
+	worker := reportService.NewExportWorker(ts.DB, nil, nil, ts.ExportFiles,
+		&config.ExportConfig{BatchSize: 5, LeaseTimeout: time.Minute}, ts.Logger)
+	n, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	w = ts.MakeRequest("GET", fmt.Sprintf("/api/v1/reports/exports/%d", created.Data.ID), nil, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var detail struct {
+		Data reportDto.ExportTaskResp `json:"data"`
+	}
+	require.NoError(t, ParseResponse(w, &detail))
+	assert.Equal(t, "COMPLETED", detail.Data.Status)
+	require.NotEmpty(t, detail.Data.DownloadURL)
+
+	// 签名链接无需登录
+	w = ts.MakeRequest("GET", detail.Data.DownloadURL, nil, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Contains(t, w.Body.String(), "2024-01-02,12.5,100,3")
+
+	w = ts.MakeRequest("GET", detail.Data.DownloadURL+"0", nil, "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 // --- 千川模块测试 ---
 // 注意：千川模块需要外部API连接和有效的广告主数据
 
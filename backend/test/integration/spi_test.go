@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkspi "github.com/bububa/oceanengine/marketing-api/model/spi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spiModel "oceanengine-backend/internal/app/spi/model"
+	spiService "oceanengine-backend/internal/app/spi/service"
+)
+
+// --- 订阅推送测试 ---
+
+// postSPI 发送签名的推送消息
+func (ts *TestServer) postSPI(body []byte, signature string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/v1/spi/callback", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(spiService.SignatureHeader, signature)
+	w := httptest.NewRecorder()
+	ts.Router.ServeHTTP(w, req)
+	return w
+}
+
+// TestSPICallback_Challenge 测试回调地址校验
+func TestSPICallback_Challenge(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+
+	w := ts.MakeRequest("GET", "/api/v1/spi/callback?challenge=123&event=verify_webhook", nil, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp sdkspi.ChallengeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(123), resp.Challenge)
+	assert.Equal(t, http.StatusOK, resp.BaseResp.StatusCode)
+
+	w = ts.MakeRequest("POST", "/api/v1/spi/callback", map[string]interface{}{"challenge": 456, "event": "verify_webhook"}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(456), resp.Challenge)
+}
+
+// TestSPICallback_Message 测试接收推送消息
+func TestSPICallback_Message(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+
+	body, err := json.Marshal(sdkspi.Message{
+		MessageID:     "msg-1",
+		ServiceLabel:  "site.status",
+		AdvertiserIDs: []uint64{1001},
+		Data:          `{"content":{"site_id":1,"event_type":1,"adv_id":1001}}`,
+		Timestamp:     time.Now().UnixMilli(),
+		Nonce:         1,
+	})
+	require.NoError(t, err)
+
+	w := ts.postSPI(body, "deadbeef")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	signature := sdkspi.Sign([]byte(TestSPISecret), body)
+	w = ts.postSPI(body, signature)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 重复推送直接应答成功
+	w = ts.postSPI(body, signature)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var msg spiModel.Message
+	require.Eventually(t, func() bool {
+		return ts.DB.Where("message_id = ?", "msg-1").First(&msg).Error == nil &&
+			msg.Status != spiModel.MessageStatusPending
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, spiModel.MessageStatusProcessed, msg.Status)
+	assert.Equal(t, "1001", msg.AdvertiserIDs)
+
+	var count int64
+	ts.DB.Model(&spiModel.Message{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestSPICallback_BodyTooLarge 测试超出上限的推送消息体
+func TestSPICallback_BodyTooLarge(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+
+	body := append([]byte(`{"message_id":"big","data":"`), bytes.Repeat([]byte("x"), 1<<20)...)
+	body = append(body, `"}`...)
+	w := ts.postSPI(body, sdkspi.Sign([]byte(TestSPISecret), body))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
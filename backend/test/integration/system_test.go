@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	adminModel "oceanengine-backend/internal/app/admin/model"
 )
 
 // TestUserList_Success 测试获取用户列表
@@ -383,3 +384,49 @@ func TestPagination(t *testing.T) {
 		})
 	}
 }
+
+// TestJobs_TriggerAndPause 测试定时任务手动触发与暂停
+func TestJobs_TriggerAndPause(t *testing.T) {
+	ts := NewTestServer(t)
+	defer ts.Cleanup()
+	ts.SeedTestData(t)
+
+	job := &adminModel.Job{Name: "daily_report", Description: "日报表同步", Cron: "0 2 * * *"}
+	require.NoError(t, ts.DB.Create(job).Error)
+
+	token, err := ts.GenerateTestToken(1, "admin")
+	require.NoError(t, err)
+
+	w := ts.MakeRequest("GET", "/api/v1/system/jobs", nil, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 手动触发后在执行完成前不能重复触发
+	path := fmt.Sprintf("/api/v1/system/jobs/%d", job.ID)
+	w = ts.MakeRequest("POST", path+"/trigger", nil, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = ts.MakeRequest("POST", path+"/trigger", nil, token)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var run adminModel.JobRun
+	require.NoError(t, ts.DB.Where("job_name = ?", job.Name).First(&run).Error)
+	assert.Equal(t, adminModel.JobRunPending, run.Status)
+	assert.Equal(t, adminModel.JobTriggerManual, run.Trigger)
+	assert.Equal(t, uint64(1), run.TriggeredBy)
+
+	w = ts.MakeRequest("GET", "/api/v1/system/jobs/runs?job_name=daily_report", nil, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = ts.MakeRequest("PUT", path+"/pause", nil, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, ts.DB.First(job, job.ID).Error)
+	assert.True(t, job.Paused)
+
+	w = ts.MakeRequest("PUT", path+"/resume", nil, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, ts.DB.First(job, job.ID).Error)
+	assert.False(t, job.Paused)
+	assert.NotNil(t, job.LastScheduledAt)
+
+	w = ts.MakeRequest("POST", "/api/v1/system/jobs/9999/trigger", nil, token)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -23,16 +23,25 @@ import (
 	creativeModel "oceanengine-backend/internal/app/creative/model"
 	mediaModel "oceanengine-backend/internal/app/media/model"
 	reportModel "oceanengine-backend/internal/app/report/model"
+	reportService "oceanengine-backend/internal/app/report/service"
+	spiModel "oceanengine-backend/internal/app/spi/model"
 	"oceanengine-backend/internal/router"
 	"oceanengine-backend/pkg/auth"
 )
 
+// TestSPISecret 订阅推送签名密钥
+const TestSPISecret = "test-spi-secret"
+
 // TestServer 测试服务器
 type TestServer struct {
 	Router     *gin.Engine
 	DB         *gorm.DB
 	JWTManager *auth.JWTManager
 	Logger     *zap.Logger
+	// OceanMock 本地巨量引擎模拟服务，避免测试访问生产地址
+	OceanMock *httptest.Server
+	// ExportFiles 报表导出文件存储（测试临时目录）
+	ExportFiles *reportService.ExportFiles
 }
 
 // NewTestServer 创建测试服务器
@@ -45,6 +54,12 @@ func NewTestServer(t *testing.T) *TestServer {
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
+	// 内存数据库每个连接相互独立，并发请求（如按广告主并发同步）需共用同一连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
 
 	// 自动迁移测试表 - 系统管理表
 	err = db.AutoMigrate(
@@ -52,7 +67,11 @@ func NewTestServer(t *testing.T) *TestServer {
 		&adminModel.Role{},
 		&adminModel.Menu{},
 		&adminModel.RoleMenu{},
+		&adminModel.UserAdvertiser{},
+		&adminModel.RoleAdvertiser{},
 		&adminModel.OperationLog{},
+		&adminModel.Job{},
+		&adminModel.JobRun{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate admin tables: %v", err)
@@ -67,12 +86,21 @@ func NewTestServer(t *testing.T) *TestServer {
 		&creativeModel.Creative{},
 		&mediaModel.MaterialImage{},
 		&mediaModel.MaterialVideo{},
+		&mediaModel.LibraryFolder{},
+		&mediaModel.LibraryMaterial{},
+		&mediaModel.LibraryTag{},
+		&mediaModel.LibraryBinding{},
 		&audienceModel.AudiencePackage{},
 		&audienceModel.CustomAudience{},
 		&reportModel.AdvertiserReport{},
 		&reportModel.CampaignReport{},
 		&reportModel.AdReport{},
+		&reportModel.CreativeReport{},
+		&reportModel.ProjectReport{},
+		&reportModel.PromotionReport{},
+		&reportModel.MaterialReport{},
 		&reportModel.ExportTask{},
+		&spiModel.Message{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate business tables: %v", err)
@@ -89,27 +117,66 @@ func NewTestServer(t *testing.T) *TestServer {
 		RefreshExpire: 168 * time.Hour,
 	})
 
+	// 创建巨量引擎模拟服务（设置 OCEAN_BASE_URL 时使用指定地址）
+	oceanMock := NewOceanMockServer()
+	baseURL := os.Getenv("OCEAN_BASE_URL")
+	if baseURL == "" {
+		baseURL = oceanMock.URL + "/open_api"
+	}
+
 	// 创建Ocean配置
 	oceanCfg := &config.OceanConfig{
 		AppID:       os.Getenv("OCEAN_APP_ID"),
 		Secret:      os.Getenv("OCEAN_SECRET"),
 		RedirectURI: "http://localhost:8080/api/v1/advertisers/oauth/callback",
-		BaseURL:     "https://ad.oceanengine.com/open_api",
-		Timeout:     30 * time.Second,
+		BaseURL:     baseURL,
+		Timeout:     5 * time.Second,
+		RetryCount:  1,
+	}
+
+	// 创建报表导出文件存储
+	exportFiles, err := reportService.NewExportFiles(
+		&config.StorageConfig{Driver: "local", LocalPath: t.TempDir(), SignSecret: "test-sign-secret"},
+		&config.ExportConfig{URLExpire: time.Hour},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create export storage: %v", err)
 	}
 
 	// 创建路由
-	r := router.NewRouter(db, logger, jwtManager, oceanCfg)
+	spiCfg := &config.SPIConfig{
+		Secret:          TestSPISecret,
+		ReplayWindow:    5 * time.Minute,
+		DispatchTimeout: 5 * time.Second,
+		MaxAttempts:     3,
+	}
+	r := router.NewRouter(db, logger, jwtManager, oceanCfg).SetExportFiles(exportFiles).SetSPI(spiCfg)
 	engine := r.Setup(gin.TestMode)
 
 	return &TestServer{
-		Router:     engine,
-		DB:         db,
-		JWTManager: jwtManager,
-		Logger:     logger,
+		Router:      engine,
+		DB:          db,
+		JWTManager:  jwtManager,
+		Logger:      logger,
+		OceanMock:   oceanMock,
+		ExportFiles: exportFiles,
 	}
 }
 
+// NewOceanMockServer 创建巨量引擎 API 模拟服务
+// 所有接口均返回 code=0 的空数据，用于替代生产地址
+func NewOceanMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":       0,
+			"message":    "OK",
+			"request_id": "mock-request-id",
+			"data":       map[string]interface{}{},
+		})
+	}))
+}
+
 // SeedTestData 填充测试数据
 func (ts *TestServer) SeedTestData(t *testing.T) {
 	// 创建测试角色
@@ -208,6 +275,9 @@ type LoginResponse struct {
 
 // Cleanup 清理测试资源
 func (ts *TestServer) Cleanup() {
+	if ts.OceanMock != nil {
+		ts.OceanMock.Close()
+	}
 	sqlDB, _ := ts.DB.DB()
 	if sqlDB != nil {
 		sqlDB.Close()
@@ -1,55 +1,137 @@
 import request, { PageResponse } from './request'
 
 // ==================== 项目相关 ====================
+// 项目、广告、报表与素材接口透传巨量本地推 API 字段，金额单位为分
 
 export interface LocalProject {
   project_id: number
-  project_name: string
-  advertiser_id: number
-  landing_type: string
-  budget: number
+  name: string
+  local_account_id: number
+  ad_type: string
+  marketing_goal: string
+  delivery_goal?: string
+  external_action?: string
+  project_status_first: string
+  project_status_second?: string[]
+  project_budget_mode: string
+  project_budget: number
+  bid_type?: string
+  project_bid?: number
+  start_time?: string
+  end_time?: string
+  project_create_time: string
+  project_modify_time: string
+}
+
+export interface LocalProjectDetail {
+  project_id: number
+  name: string
+  local_account_id: number
+  aweme_id?: string
+  ad_type: string
+  local_delivery_scene: string
+  marketing_goal: string
+  delivery_goal?: string
+  external_action?: string
+  project_status_first: string
+  project_status_second?: string[]
   budget_mode: string
-  status: string
-  opt_status: string
-  created_at: string
+  budget: number
+  bid_type?: string
+  bid?: number
+  schedule_type?: string
+  start_time?: string
+  end_time?: string
+  schedule_time?: string
+  audience?: object
 }
 
 export interface LocalProjectCreateParams {
-  advertiser_id: number
-  project_name: string
-  landing_type: string
-  budget_mode: string
+  name: string
+  marketing_goal: 'LIVE' | 'VIDEO_IMAGE'
+  local_delivery_scene: string
+  ad_type?: 'GENERAL' | 'SEARCHING'
+  delivery_goal?: string
+  delivery_poi_mode?: string
+  promotion_poi_ids?: number[]
+  product_id?: number
+  aweme_id?: string
+  external_action?: string
+  audience?: object
+  schedule_type?: 'FROM_NOW_ON' | 'START_TO_END' | 'FIXED_TIME'
+  schedule_fixed_seconds?: number
+  start_time?: string
+  end_time?: string
+  schedule_time?: string
+  bid_type?: 'MANUAL' | 'SMART'
+  bid?: number
+  budget_mode?: 'BUDGET_MODE_DAY' | 'BUDGET_MODE_TOTAL'
   budget?: number
-  delivery_range?: string
+}
+
+export interface LocalProjectUpdateParams {
+  name?: string
   audience?: object
+  schedule_type?: 'FROM_NOW_ON' | 'START_TO_END' | 'FIXED_TIME'
+  schedule_fixed_seconds?: number
+  end_time?: string
+  schedule_time?: string
+  bid?: number
+  budget?: number
+}
+
+export interface LocalProjectStatusResult {
+  success_ids: number[]
+  fail_ids: number[]
+  errors?: { project_id: number; error_message: string }[]
 }
 
 // ==================== 广告相关 ====================
 
 export interface LocalPromotion {
   promotion_id: number
-  project_id: number
   promotion_name: string
-  status: string
-  opt_status: string
-  budget: number
-  bid: number
-  schedule_type: string
-  start_time?: string
-  end_time?: string
-  created_at: string
+  project_id: number
+  local_account_id: number
+  ad_type: string
+  promotion_status_first: string
+  promotion_status_second?: string[]
+  learning_phase?: string
+  aweme_id?: string
+  aweme_name?: string
+  promotion_create_time: string
+  promotion_modify_time: string
+}
+
+export interface LocalPromotionDetail {
+  promotion_id: number
+  aweme_id?: string
+  enable_graphic_delivery?: boolean
+  video_hp_visibility?: string
+  live_material_type?: string
+  customer_material_list?: object[]
 }
 
 export interface LocalPromotionCreateParams {
   project_id: number
-  promotion_name: string
-  budget: number
-  bid: number
-  schedule_type: string
-  start_time?: string
-  end_time?: string
-  audience?: object
-  creative?: object
+  name: string
+  enable_graphic_delivery?: boolean
+  aweme_id?: string
+  video_hp_visibility?: string
+  live_material_type?: string
+  customer_material_list?: object[]
+}
+
+export interface LocalPromotionUpdateParams {
+  name?: string
+  aweme_id?: string
+  video_hp_visibility?: string
+  customer_material_list?: object[]
+}
+
+export interface LocalPromotionStatusResult {
+  promotion_ids?: number[]
+  errors?: { promotion_id: number; error_message: string }[]
 }
 
 // ==================== 线索相关 ====================
@@ -68,27 +150,48 @@ export interface LocalClue {
 
 // ==================== 报表相关 ====================
 
+// LocalReportData 报表数据，返回的指标由 metrics 参数决定
 export interface LocalReportData {
-  stat_datetime: string
-  cost: number
-  show_cnt: number
-  click_cnt: number
-  ctr: number
-  clue_count: number
-  clue_cost: number
-  call_count: number
-  form_count: number
+  stat_time_day?: string
+  stat_time_hour?: string
+  stat_cost?: number
+  show_cnt?: number
+  click_cnt?: number
+  ctr?: number
+  cpc_platform?: number
+  cpm_platform?: number
+  convert_cnt?: number
+  conversion_rate?: number
+  conversion_cost?: number
+  form_cnt?: number
+  phone_confirm_cnt?: number
+  phone_connect_cnt?: number
+  message_action_cnt?: number
+}
+
+export interface LocalReportParams {
+  advertiser_id: number
+  start_date: string
+  end_date: string
+  metrics?: string
+  page: number
+  page_size: number
 }
 
 // ==================== 素材相关 ====================
 
 export interface LocalMaterial {
-  material_id: string
-  material_type: string
-  url: string
+  video_id: string
+  material_id: number
+  video_name: string
+  video_url: string
+  poster_url?: string
   width: number
   height: number
+  size: number
   duration?: number
+  image_mode?: string
+  source?: string
   create_time: string
 }
 
@@ -108,54 +211,54 @@ export interface LocalStore {
 // ==================== API 方法 ====================
 
 export const localApi = {
-  // 项目管理
-  getProjectList(params: { advertiser_id: number; page: number; page_size: number; status?: string }) {
+  // 项目管理，advertiser_id 通过 query 传递
+  getProjectList(params: { advertiser_id: number; page: number; page_size: number }) {
     return request.get<PageResponse<LocalProject>>('/local/projects', params)
   },
 
-  getProjectDetail(project_id: number) {
-    return request.get<LocalProject>(`/local/projects/${project_id}`)
+  getProjectDetail(advertiser_id: number, project_id: number) {
+    return request.get<LocalProjectDetail>(`/local/projects/${project_id}`, { advertiser_id })
   },
 
-  createProject(data: LocalProjectCreateParams) {
-    return request.post<{ project_id: number }>('/local/projects', data)
+  createProject(advertiser_id: number, data: LocalProjectCreateParams) {
+    return request.post<{ project_id: number }>('/local/projects', data, { params: { advertiser_id } })
   },
 
-  updateProject(project_id: number, data: Partial<LocalProjectCreateParams>) {
-    return request.put<void>(`/local/projects/${project_id}`, data)
+  updateProject(advertiser_id: number, project_id: number, data: LocalProjectUpdateParams) {
+    return request.put<void>(`/local/projects/${project_id}`, data, { params: { advertiser_id } })
   },
 
-  updateProjectStatus(project_ids: number[], opt_status: string) {
-    return request.post<void>('/local/projects/status', { project_ids, opt_status })
+  updateProjectStatus(advertiser_id: number, project_ids: number[], opt_status: 'ENABLE' | 'PAUSED') {
+    return request.post<LocalProjectStatusResult>('/local/projects/status', { project_ids, opt_status }, { params: { advertiser_id } })
   },
 
-  deleteProject(project_id: number) {
-    return request.delete<void>(`/local/projects/${project_id}`)
+  deleteProject(advertiser_id: number, project_id: number) {
+    return request.delete<void>(`/local/projects/${project_id}`, { advertiser_id })
   },
 
   // 广告管理
-  getPromotionList(params: { advertiser_id: number; project_id?: number; page: number; page_size: number; status?: string }) {
+  getPromotionList(params: { advertiser_id: number; page: number; page_size: number }) {
     return request.get<PageResponse<LocalPromotion>>('/local/promotions', params)
   },
 
-  getPromotionDetail(promotion_id: number) {
-    return request.get<LocalPromotion>(`/local/promotions/${promotion_id}`)
+  getPromotionDetail(advertiser_id: number, promotion_id: number) {
+    return request.get<LocalPromotionDetail>(`/local/promotions/${promotion_id}`, { advertiser_id })
   },
 
-  createPromotion(data: LocalPromotionCreateParams) {
-    return request.post<{ promotion_id: number }>('/local/promotions', data)
+  createPromotion(advertiser_id: number, data: LocalPromotionCreateParams) {
+    return request.post<{ promotion_id: number }>('/local/promotions', data, { params: { advertiser_id } })
   },
 
-  updatePromotion(promotion_id: number, data: Partial<LocalPromotionCreateParams>) {
-    return request.put<void>(`/local/promotions/${promotion_id}`, data)
+  updatePromotion(advertiser_id: number, promotion_id: number, data: LocalPromotionUpdateParams) {
+    return request.put<void>(`/local/promotions/${promotion_id}`, data, { params: { advertiser_id } })
   },
 
-  updatePromotionStatus(promotion_ids: number[], opt_status: string) {
-    return request.post<void>('/local/promotions/status', { promotion_ids, opt_status })
+  updatePromotionStatus(advertiser_id: number, promotion_ids: number[], opt_status: 'ENABLE' | 'PAUSED') {
+    return request.post<LocalPromotionStatusResult>('/local/promotions/status', { promotion_ids, opt_status }, { params: { advertiser_id } })
   },
 
-  deletePromotion(promotion_id: number) {
-    return request.delete<void>(`/local/promotions/${promotion_id}`)
+  deletePromotion(advertiser_id: number, promotion_id: number) {
+    return request.delete<void>(`/local/promotions/${promotion_id}`, { advertiser_id })
   },
 
   // 线索管理
@@ -176,30 +279,26 @@ export const localApi = {
   },
 
   // 报表
-  getProjectReport(params: { advertiser_id: number; project_ids?: number[]; start_date: string; end_date: string; page: number; page_size: number }) {
+  getProjectReport(params: LocalReportParams) {
     return request.get<PageResponse<LocalReportData & { project_id: number; project_name: string }>>('/local/reports/project', params)
   },
 
-  getPromotionReport(params: { advertiser_id: number; promotion_ids?: number[]; start_date: string; end_date: string; page: number; page_size: number }) {
+  getPromotionReport(params: LocalReportParams) {
     return request.get<PageResponse<LocalReportData & { promotion_id: number; promotion_name: string }>>('/local/reports/promotion', params)
   },
 
-  getMaterialReport(params: { advertiser_id: number; start_date: string; end_date: string; page: number; page_size: number }) {
-    return request.get<PageResponse<LocalReportData & { material_id: string }>>('/local/reports/material', params)
+  getMaterialReport(params: LocalReportParams) {
+    return request.get<PageResponse<LocalReportData & { material_id: number; material_name: string }>>('/local/reports/material', params)
   },
 
   // 素材管理
-  getMaterialList(params: { advertiser_id: number; material_type: string; page: number; page_size: number }) {
+  getMaterialList(params: { advertiser_id: number; page: number; page_size: number }) {
     return request.get<PageResponse<LocalMaterial>>('/local/materials', params)
   },
 
-  uploadVideo(advertiser_id: number, file: File) {
-    const formData = new FormData()
-    formData.append('file', file)
-    formData.append('advertiser_id', String(advertiser_id))
-    return request.post<{ video_id: string; url: string }>('/local/materials/video', formData, {
-      headers: { 'Content-Type': 'multipart/form-data' }
-    })
+  // 通过视频 URL 创建异步上传任务
+  uploadVideo(advertiser_id: number, video_url: string, filename?: string) {
+    return request.post<{ task_id: number; message: string }>('/local/materials/video', { video_url, filename }, { params: { advertiser_id } })
   },
 
   uploadImage(advertiser_id: number, file: File) {
@@ -0,0 +1,341 @@
+package audience
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/dmp/customaudience"
+	"github.com/bububa/oceanengine/marketing-api/api/dmp/datasource"
+	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	customaudienceModel "github.com/bububa/oceanengine/marketing-api/model/dmp/customaudience"
+	datasourceModel "github.com/bububa/oceanengine/marketing-api/model/dmp/datasource"
+)
+
+const (
+	// MaxFilePaths 数据源创建/更新时一次最多提交的文件数
+	MaxFilePaths = 1000
+	// MaxPushAdvertisers 人群包一次最多推送的广告主数
+	MaxPushAdvertisers = 100
+	// DefaultPollInterval 默认数据源状态轮询间隔
+	DefaultPollInterval = 10 * time.Second
+)
+
+// ErrDataSourceFailed 数据源处理失败
+var ErrDataSourceFailed = errors.New("dmp: data source failed")
+
+// Source 原始ID来源，CSV 或每行一个ID的文本
+type Source struct {
+	// Type ID类型
+	Type IDType
+	// Reader 数据
+	Reader io.Reader
+	// Column ID所在列，从0开始
+	Column int
+	// Comma 列分隔符，默认为 ','
+	Comma rune
+	// Hashed ID已按类型对应的算法哈希，仅校验格式，不再哈希
+	Hashed bool
+}
+
+// BuildRequest 构建人群包请求
+type BuildRequest struct {
+	// AdvertiserID 广告主ID
+	AdvertiserID uint64
+	// DataSourceID 已有数据源ID，为空时新建数据源
+	DataSourceID string
+	// DataSourceName 新建数据源名称, 限30个字符内
+	DataSourceName string
+	// Description 新建数据源描述, 限256个字符内
+	Description string
+	// DataSourceType 新建数据源类型，"UID"：用户ID, "DID"：设备ID ,默认值： "UID"
+	DataSourceType string
+	// OperationType 更新已有数据源的操作类型，默认添加
+	OperationType enum.DmpDatasourceOperationType
+	// Sources 原始ID来源
+	Sources []Source
+	// Tags 每个ID的业务标签
+	Tags []string
+	// Dedupe 是否去重，去重需要在内存中保存所有ID
+	Dedupe bool
+	// RowSize 每行ID数，默认 MaxRowSize
+	RowSize int
+	// FileSize 每个 zip 文件大小上限，默认 MaxFileSize
+	FileSize int
+	// NoWait 提交数据源后立即返回，Publish 或 PushAdvertiserIDs 非空时仍需等待数据源就绪
+	NoWait bool
+	// PollInterval 数据源状态轮询间隔，默认 DefaultPollInterval
+	PollInterval time.Duration
+	// Publish 数据源就绪后发布人群包
+	Publish bool
+	// PushAdvertiserIDs 数据源就绪后推送人群包的广告主ID列表
+	PushAdvertiserIDs []uint64
+}
+
+// BuildResult 构建人群包结果
+type BuildResult struct {
+	// DataSourceID 数据源ID
+	DataSourceID string
+	// CustomAudienceID 数据源对应的人群包ID
+	CustomAudienceID uint64
+	// FilePaths 上传的数据源文件路径
+	FilePaths []string
+	// IDs 上传的ID数
+	IDs int
+	// Invalid 无法解析的行数(包括表头)
+	Invalid int
+	// Duplicated 去重时重复的ID数
+	Duplicated int
+	// DataSource 就绪后的数据源信息，未等待数据源就绪时为空
+	DataSource *datasourceModel.DataSource
+	// updated 通过数据源更新提交的文件路径
+	updated []string
+}
+
+// Build 根据原始ID构建人群包
+// 规范化并哈希原始ID，打包为 zip 文件并上传，创建或更新数据源，等待数据源就绪后按需发布、推送人群包
+// 数据源处理可能需要较长时间，可通过 ctx 控制超时
+func Build(ctx context.Context, clt *core.SDKClient, accessToken string, req *BuildRequest) (*BuildResult, error) {
+	if req.AdvertiserID == 0 || len(req.Sources) == 0 {
+		return nil, errors.New("dmp: advertiser_id and sources are required")
+	}
+	if req.DataSourceID == "" && req.DataSourceName == "" {
+		return nil, errors.New("dmp: data_source_name is required to create data source")
+	}
+	ret := &BuildResult{DataSourceID: req.DataSourceID}
+	packer := NewPacker(req.RowSize, req.FileSize, req.Tags, func(f *File) error {
+		filePath, err := datasource.FileUpload(ctx, clt, accessToken, &datasourceModel.FileUploadRequest{
+			AdvertiserID:  req.AdvertiserID,
+			File:          bytes.NewReader(f.Data),
+			Filename:      f.Name,
+			FileSignature: f.Signature(),
+		})
+		if err != nil {
+			return fmt.Errorf("dmp: upload %s failed: %w", f.Name, err)
+		}
+		ret.FilePaths = append(ret.FilePaths, filePath)
+		return nil
+	})
+	if err := ret.pack(packer, req); err != nil {
+		return ret, err
+	}
+	if len(ret.FilePaths) == 0 {
+		return ret, errors.New("dmp: no valid id")
+	}
+	if err := ret.submit(ctx, clt, accessToken, req); err != nil {
+		return ret, err
+	}
+	if req.NoWait && !req.Publish && len(req.PushAdvertiserIDs) == 0 {
+		return ret, nil
+	}
+	ds, err := WaitDataSource(ctx, clt, accessToken, req.AdvertiserID, ret.DataSourceID, ret.updated, req.PollInterval)
+	if err != nil {
+		return ret, err
+	}
+	ret.DataSource = ds
+	if ds.DefaultAudience != nil {
+		ret.CustomAudienceID = ds.DefaultAudience.CustomAudienceID
+	}
+	if !req.Publish && len(req.PushAdvertiserIDs) == 0 {
+		return ret, nil
+	}
+	if ret.CustomAudienceID == 0 {
+		return ret, errors.New("dmp: custom audience of data source not found")
+	}
+	if req.Publish {
+		if err := customaudience.Publish(ctx, clt, accessToken, &customaudienceModel.PublishRequest{
+			AdvertiserID:     req.AdvertiserID,
+			CustomAudienceID: ret.CustomAudienceID,
+		}); err != nil {
+			return ret, fmt.Errorf("dmp: publish custom audience failed: %w", err)
+		}
+	}
+	for ids := range chunk(req.PushAdvertiserIDs, MaxPushAdvertisers) {
+		if err := customaudience.Push(ctx, clt, accessToken, &customaudienceModel.PushRequest{
+			AdvertiserID:        req.AdvertiserID,
+			CustomAudienceID:    ret.CustomAudienceID,
+			TargetAdvertiserIDs: ids,
+		}); err != nil {
+			return ret, fmt.Errorf("dmp: push custom audience failed: %w", err)
+		}
+	}
+	return ret, nil
+}
+
+// pack 读取所有来源并打包上传
+func (r *BuildResult) pack(packer *Packer, req *BuildRequest) error {
+	var seen map[string]struct{}
+	if req.Dedupe {
+		seen = make(map[string]struct{})
+	}
+	for _, src := range req.Sources {
+		dataType, err := src.Type.DataType()
+		if err != nil {
+			return err
+		}
+		cr := csv.NewReader(src.Reader)
+		if src.Comma != 0 {
+			cr.Comma = src.Comma
+		}
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		cr.ReuseRecord = true
+		hash := src.Type.Hash
+		if src.Hashed {
+			hash = src.Type.ParseHash
+		}
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("dmp: read source failed: %w", err)
+			}
+			if src.Column >= len(record) {
+				r.Invalid++
+				continue
+			}
+			id, err := hash(record[src.Column])
+			if err != nil {
+				r.Invalid++
+				continue
+			}
+			if seen != nil {
+				key := string(src.Type) + id
+				if _, ok := seen[key]; ok {
+					r.Duplicated++
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+			if err := packer.Add(dataType, id); err != nil {
+				return err
+			}
+			r.IDs++
+		}
+	}
+	return packer.Close()
+}
+
+// submit 使用上传的文件创建或更新数据源，文件数超过 MaxFilePaths 时分批追加
+func (r *BuildResult) submit(ctx context.Context, clt *core.SDKClient, accessToken string, req *BuildRequest) error {
+	operation := req.OperationType
+	if operation == enum.DmpDatasourceOperationType_CREATE {
+		operation = enum.DmpDatasourceOperationType_APPEND
+	}
+	for paths := range chunk(r.FilePaths, MaxFilePaths) {
+		if r.DataSourceID == "" {
+			id, err := datasource.Create(ctx, clt, accessToken, &datasourceModel.CreateRequest{
+				AdvertiserID:   req.AdvertiserID,
+				DataSourceName: req.DataSourceName,
+				Description:    req.Description,
+				DataSourceType: req.DataSourceType,
+				FilePaths:      paths,
+			})
+			if err != nil {
+				return fmt.Errorf("dmp: create data source failed: %w", err)
+			}
+			r.DataSourceID = id
+			continue
+		}
+		if err := datasource.Update(ctx, clt, accessToken, &datasourceModel.UpdateRequest{
+			AdvertiserID:  req.AdvertiserID,
+			DataSourceID:  r.DataSourceID,
+			OperationType: operation,
+			FilePaths:     paths,
+		}); err != nil {
+			return fmt.Errorf("dmp: update data source failed: %w", err)
+		}
+		r.updated = append(r.updated, paths...)
+		// 重置后剩余文件追加到数据源
+		if operation == enum.DmpDatasourceOperationType_RESET {
+			operation = enum.DmpDatasourceOperationType_APPEND
+		}
+	}
+	return nil
+}
+
+// WaitDataSource 轮询数据源详细信息，直到数据源生效且包含 filePaths 的更新均已生效
+// 数据源或相关更新失败时返回 ErrDataSourceFailed
+func WaitDataSource(ctx context.Context, clt *core.SDKClient, accessToken string, advertiserID uint64, dataSourceID string, filePaths []string, interval time.Duration) (*datasourceModel.DataSource, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		list, err := datasource.Read(ctx, clt, accessToken, &datasourceModel.ReadRequest{
+			AdvertiserID:     advertiserID,
+			DataSourceIDList: []string{dataSourceID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			if ds := &list[i]; ds.ID == dataSourceID {
+				if ready, err := dataSourceReady(ds, filePaths); ready || err != nil {
+					return ds, err
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// dataSourceReady 数据源及包含 filePaths 的更新是否已生效
+func dataSourceReady(ds *datasourceModel.DataSource, filePaths []string) (bool, error) {
+	if ds.Status == enum.DataSourceStatus_FAILED {
+		return false, fmt.Errorf("%w: %s", ErrDataSourceFailed, ds.ID)
+	}
+	if ds.Status != enum.DataSourceStatus_COMPLETED {
+		return false, nil
+	}
+	pending := make(map[string]struct{}, len(filePaths))
+	for _, p := range filePaths {
+		pending[p] = struct{}{}
+	}
+	for _, log := range ds.ChangeLogs {
+		if !slices.ContainsFunc(log.FilePaths, func(p string) bool {
+			_, ok := pending[p]
+			return ok
+		}) {
+			continue
+		}
+		switch log.Status {
+		case enum.DataSourceStatus_FAILED:
+			return false, fmt.Errorf("%w: %s change log %d", ErrDataSourceFailed, ds.ID, log.ID)
+		case enum.DataSourceStatus_COMPLETED:
+			for _, p := range log.FilePaths {
+				delete(pending, p)
+			}
+		default:
+			return false, nil
+		}
+	}
+	return len(pending) == 0, nil
+}
+
+// chunk 按 size 切分
+func chunk[T any](list []T, size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for len(list) > 0 {
+			n := min(size, len(list))
+			if !yield(list[:n]) {
+				return
+			}
+			list = list[n:]
+		}
+	}
+}
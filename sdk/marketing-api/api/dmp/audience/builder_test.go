@@ -0,0 +1,254 @@
+package audience
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	"github.com/bububa/oceanengine/marketing-api/model/dmp/datasource"
+	"github.com/bububa/oceanengine/marketing-api/testing/oetest"
+)
+
+func TestIDType_Hash(t *testing.T) {
+	sha := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	md := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	tests := []struct {
+		typ  IDType
+		raw  string
+		want string
+	}{
+		{IDType_PHONE, " +86 138-0013-8000 ", sha("13800138000")},
+		{IDType_IMEI, "86ABC", md("86abc")},
+		{IDType_IDFA, "e2c1-ab", md("E2C1-AB")},
+		{IDType_OAID, "Oaid-1", md("Oaid-1")},
+		{IDType_OAID, md("x"), md(md("x"))},
+	}
+	for _, tt := range tests {
+		got, err := tt.typ.Hash(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("%s.Hash(%q) = %q, %v, want %q", tt.typ, tt.raw, got, err, tt.want)
+		}
+	}
+	for _, raw := range []string{"", "phone", "2380013800", "12345"} {
+		if _, err := IDType_PHONE.Hash(raw); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("PHONE.Hash(%q) err = %v", raw, err)
+		}
+	}
+}
+
+func TestIDType_ParseHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("13800138000"))
+	phone := hex.EncodeToString(sum[:])
+	if got, err := IDType_PHONE.ParseHash(strings.ToUpper(phone)); err != nil || got != phone {
+		t.Errorf("PHONE.ParseHash = %q, %v", got, err)
+	}
+	md := md5.Sum([]byte("x"))
+	oaid := hex.EncodeToString(md[:])
+	if got, err := IDType_OAID.ParseHash(" " + strings.ToUpper(oaid)); err != nil || got != oaid {
+		t.Errorf("OAID.ParseHash = %q, %v", got, err)
+	}
+	for _, raw := range []string{"", "oaid-1", phone} {
+		if _, err := IDType_OAID.ParseHash(raw); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("OAID.ParseHash(%q) err = %v", raw, err)
+		}
+	}
+}
+
+// decodeUpload 解析数据源文件上传请求，检查文件大小及每行ID数，返回 zip 中的所有ID
+func decodeUpload(req *oetest.Request, rowSize int, fileSize int) ([]*datasource.IdItem, error) {
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	form, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		return nil, err
+	}
+	fh := form.File["file"][0]
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, _ := io.ReadAll(f)
+	if len(data) > fileSize {
+		return nil, fmt.Errorf("file size %d > %d", len(data), fileSize)
+	}
+	sum := md5.Sum(data)
+	if form.Value["file_signature"][0] != hex.EncodeToString(sum[:]) {
+		return nil, errors.New("file_signature mismatch")
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var items []*datasource.IdItem
+	for _, entry := range zr.File {
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(rc)
+		scanner.Buffer(nil, MaxFileSize)
+		for scanner.Scan() {
+			bs, err := base64.StdEncoding.DecodeString(scanner.Text())
+			if err != nil {
+				return nil, err
+			}
+			var row datasource.DmpData
+			if err := proto.Unmarshal(bs, &row); err != nil {
+				return nil, err
+			}
+			if len(row.IdList) > rowSize {
+				return nil, fmt.Errorf("row size %d > %d", len(row.IdList), rowSize)
+			}
+			items = append(items, row.IdList...)
+		}
+		rc.Close()
+	}
+	return items, nil
+}
+
+func TestBuild(t *testing.T) {
+	srv := oetest.NewServer(t)
+	var (
+		mu    sync.Mutex
+		items []*datasource.IdItem
+	)
+	upload := srv.Handle("2/dmp/data_source/file/upload").ReplyFunc(func(req *oetest.Request) any {
+		list, err := decodeUpload(req, 16, 8<<10)
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		items = append(items, list...)
+		return datasource.FileUploadResponseData{FilePath: fmt.Sprintf("path%d", len(items))}
+	})
+	srv.Handle("2/dmp/data_source/create").Expect(func(req *oetest.Request) error {
+		var r datasource.CreateRequest
+		if err := req.DecodeJSON(&r); err != nil {
+			return err
+		}
+		if r.DataSourceName != "老客" || len(r.FilePaths) != upload.Calls() {
+			return fmt.Errorf("create request = %+v", r)
+		}
+		return nil
+	}).Reply(datasource.CreateResponseData{DataSourceID: "ds1"})
+	read := srv.Handle("2/dmp/data_source/read")
+	read.ReplyFunc(func(req *oetest.Request) any {
+		ds := datasource.DataSource{ID: "ds1", Status: enum.DataSourceStatus_PARSING}
+		if read.Calls() > 1 {
+			ds.Status = enum.DataSourceStatus_COMPLETED
+			ds.DefaultAudience = &datasource.Audience{CustomAudienceID: 99}
+		}
+		return datasource.ReadResponseData{DataList: []datasource.DataSource{ds}}
+	})
+	publish := srv.Handle("2/dmp/custom_audience/publish").ExpectJSON(map[string]any{"advertiser_id": 1, "custom_audience_id": 99})
+	push := srv.Handle("2/dmp/custom_audience/push_v2")
+
+	var phones, oaids strings.Builder
+	phones.WriteString("name,phone\n")
+	for i := 0; i < 250; i++ {
+		fmt.Fprintf(&phones, "u%d,1380013%04d\n", i, i%200)
+	}
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&oaids, "oaid-%d\n", i)
+	}
+	targets := make([]uint64, 150)
+	for i := range targets {
+		targets[i] = uint64(i + 2)
+	}
+	ret, err := Build(context.Background(), srv.SDKClient(1, "secret"), "token", &BuildRequest{
+		AdvertiserID:   1,
+		DataSourceName: "老客",
+		Sources: []Source{
+			{Type: IDType_PHONE, Reader: strings.NewReader(phones.String()), Column: 1},
+			{Type: IDType_OAID, Reader: strings.NewReader(oaids.String())},
+		},
+		Dedupe:            true,
+		RowSize:           16,
+		FileSize:          8 << 10,
+		PollInterval:      1,
+		Publish:           true,
+		PushAdvertiserIDs: targets,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.DataSourceID != "ds1" || ret.CustomAudienceID != 99 || ret.IDs != 230 || ret.Invalid != 1 || ret.Duplicated != 50 {
+		t.Errorf("ret = %+v", ret)
+	}
+	if upload.Calls() < 2 || len(ret.FilePaths) != upload.Calls() {
+		t.Errorf("uploads = %d, file paths = %v", upload.Calls(), ret.FilePaths)
+	}
+	if len(items) != 230 || items[0].GetDataType() != datasource.IdItem_MOBILE_HASH_SHA256 || items[229].GetDataType() != datasource.IdItem_OAID_MD5 {
+		t.Errorf("uploaded %d ids", len(items))
+	}
+	if publish.Calls() != 1 || push.Calls() != 2 || read.Calls() != 2 {
+		t.Errorf("publish = %d, push = %d, read = %d", publish.Calls(), push.Calls(), read.Calls())
+	}
+}
+
+func TestBuild_NoWait(t *testing.T) {
+	srv := oetest.NewServer(t)
+	srv.Handle("2/dmp/data_source/file/upload").Reply(datasource.FileUploadResponseData{FilePath: "path1"})
+	srv.Handle("2/dmp/data_source/create").Reply(datasource.CreateResponseData{DataSourceID: "ds1"})
+	read := srv.Handle("2/dmp/data_source/read")
+
+	md := md5.Sum([]byte("oaid-1"))
+	ret, err := Build(context.Background(), srv.SDKClient(1, "secret"), "token", &BuildRequest{
+		AdvertiserID:   1,
+		DataSourceName: "老客",
+		Sources:        []Source{{Type: IDType_OAID, Reader: strings.NewReader(hex.EncodeToString(md[:]) + "\nnot-hashed\n"), Hashed: true}},
+		NoWait:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.DataSourceID != "ds1" || ret.DataSource != nil || ret.IDs != 1 || ret.Invalid != 1 || read.Calls() != 0 {
+		t.Errorf("ret = %+v, read = %d", ret, read.Calls())
+	}
+}
+
+func TestWaitDataSource_Failed(t *testing.T) {
+	srv := oetest.NewServer(t)
+	srv.Handle("2/dmp/data_source/read").Reply(datasource.ReadResponseData{DataList: []datasource.DataSource{{
+		ID:     "ds1",
+		Status: enum.DataSourceStatus_COMPLETED,
+		ChangeLogs: []datasource.ChangeLog{
+			{ID: 1, FilePaths: []string{"old"}, Status: enum.DataSourceStatus_FAILED},
+			{ID: 2, FilePaths: []string{"new"}, Status: enum.DataSourceStatus_FAILED},
+		},
+	}}})
+	clt := srv.SDKClient(1, "secret")
+
+	if _, err := WaitDataSource(context.Background(), clt, "token", 1, "ds1", nil, 1); err != nil {
+		t.Errorf("err = %v", err)
+	}
+	_, err := WaitDataSource(context.Background(), clt, "token", 1, "ds1", []string{"new"}, 1)
+	if !errors.Is(err, ErrDataSourceFailed) {
+		t.Errorf("err = %v, want ErrDataSourceFailed", err)
+	}
+}
@@ -0,0 +1,12 @@
+// Package audience DMP人群包构建
+//
+// 将手机号、IMEI、IDFA、OAID 等原始ID规范化并哈希，按【DMP上传数据格式】打包为 zip 文件上传，
+// 创建或更新数据源，等待数据源就绪后发布、推送人群包：
+//
+//	ret, err := audience.Build(ctx, clt, accessToken, &audience.BuildRequest{
+//		AdvertiserID:   advertiserID,
+//		DataSourceName: "老客手机号",
+//		Sources:        []audience.Source{{Type: audience.IDType_PHONE, Reader: file}},
+//		Publish:        true,
+//	})
+package audience
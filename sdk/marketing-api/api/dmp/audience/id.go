@@ -0,0 +1,119 @@
+package audience
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bububa/oceanengine/marketing-api/model/dmp/datasource"
+)
+
+// IDType 原始ID类型
+type IDType string
+
+const (
+	// IDType_PHONE 手机号，SHA256 后上传 (MOBILE_HASH_SHA256)
+	IDType_PHONE IDType = "PHONE"
+	// IDType_IMEI IMEI，小写后 MD5 上传 (IMEI_MD5)
+	IDType_IMEI IDType = "IMEI"
+	// IDType_IDFA IDFA，大写后 MD5 上传 (IDFA_MD5)
+	IDType_IDFA IDType = "IDFA"
+	// IDType_OAID OAID，MD5 后上传 (OAID_MD5)
+	IDType_OAID IDType = "OAID"
+)
+
+// ErrInvalidID 原始ID格式不合法
+var ErrInvalidID = errors.New("dmp: invalid id")
+
+// DataType 上传时使用的 IdItem_DataType
+func (t IDType) DataType() (datasource.IdItem_DataType, error) {
+	switch t {
+	case IDType_PHONE:
+		return datasource.IdItem_MOBILE_HASH_SHA256, nil
+	case IDType_IMEI:
+		return datasource.IdItem_IMEI_MD5, nil
+	case IDType_IDFA:
+		return datasource.IdItem_IDFA_MD5, nil
+	case IDType_OAID:
+		return datasource.IdItem_OAID_MD5, nil
+	}
+	return 0, fmt.Errorf("dmp: unsupported id type %q", t)
+}
+
+// Hash 规范化原始ID并计算对应的哈希值(小写十六进制)
+// OAID 等原始ID本身可能是十六进制串，不按格式猜测是否已哈希，已哈希的ID使用 ParseHash
+func (t IDType) Hash(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+	if id == "" {
+		return "", ErrInvalidID
+	}
+	switch t {
+	case IDType_PHONE:
+		phone, ok := normalizePhone(id)
+		if !ok {
+			return "", fmt.Errorf("%w: phone %s", ErrInvalidID, raw)
+		}
+		sum := sha256.Sum256([]byte(phone))
+		return hex.EncodeToString(sum[:]), nil
+	case IDType_IMEI, IDType_IDFA, IDType_OAID:
+		switch t {
+		case IDType_IMEI:
+			id = strings.ToLower(id)
+		case IDType_IDFA:
+			id = strings.ToUpper(id)
+		}
+		sum := md5.Sum([]byte(id))
+		return hex.EncodeToString(sum[:]), nil
+	}
+	return "", fmt.Errorf("dmp: unsupported id type %q", t)
+}
+
+// ParseHash 校验已哈希的ID并转为小写，手机号为 SHA256，其余为 MD5 的十六进制串
+func (t IDType) ParseHash(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+	size := md5.Size
+	switch t {
+	case IDType_PHONE:
+		size = sha256.Size
+	case IDType_IMEI, IDType_IDFA, IDType_OAID:
+	default:
+		return "", fmt.Errorf("dmp: unsupported id type %q", t)
+	}
+	if !isHex(id, size*2) {
+		return "", fmt.Errorf("%w: hash %s", ErrInvalidID, raw)
+	}
+	return strings.ToLower(id), nil
+}
+
+// normalizePhone 去除分隔符及 +86/86 前缀，返回 11 位手机号
+func normalizePhone(s string) (string, bool) {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == '+' || c == '-' || c == ' ' || c == '(' || c == ')':
+		default:
+			return "", false
+		}
+	}
+	phone := b.String()
+	if len(phone) == 13 && strings.HasPrefix(phone, "86") {
+		phone = phone[2:]
+	}
+	if len(phone) != 11 || phone[0] != '1' {
+		return "", false
+	}
+	return phone, true
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,160 @@
+package audience
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bububa/oceanengine/marketing-api/model/dmp/datasource"
+)
+
+const (
+	// MaxRowSize 每行 DmpData 包含的ID数上限
+	MaxRowSize = 10000
+	// MaxFileSize 数据源文件上传 zip 大小上限
+	MaxFileSize = 50 << 20
+	// zipOverhead 预留给 zip 文件头及目录的空间
+	zipOverhead = 4 << 10
+	// flateWindow deflate 尚未输出的数据上限
+	flateWindow = 64 << 10
+)
+
+// File 打包后的数据源 zip 文件
+type File struct {
+	// Name 文件名
+	Name string
+	// Data zip 文件内容
+	Data []byte
+	// Rows DmpData 行数
+	Rows int
+	// IDs ID数
+	IDs int
+}
+
+// Signature 文件MD5
+func (f *File) Signature() string {
+	sum := md5.Sum(f.Data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Packer 将ID打包为数据源文件
+// 每行为一个 base64 编码的 DmpData，每行不超过 rowSize 个ID；zip 文件超过 fileSize 前切分为新文件，生成的文件交给 emit 处理
+type Packer struct {
+	rowSize  int
+	fileSize int
+	tags     []string
+	emit     func(*File) error
+
+	row   []*datasource.IdItem
+	seq   int
+	buf   bytes.Buffer
+	zw    *zip.Writer
+	entry io.Writer
+	file  File
+	raw   int
+}
+
+// NewPacker 创建 Packer，rowSize/fileSize <= 0 或超过上限时使用上限值
+func NewPacker(rowSize int, fileSize int, tags []string, emit func(*File) error) *Packer {
+	if rowSize <= 0 || rowSize > MaxRowSize {
+		rowSize = MaxRowSize
+	}
+	if fileSize <= 0 || fileSize > MaxFileSize {
+		fileSize = MaxFileSize
+	}
+	return &Packer{
+		rowSize:  rowSize,
+		fileSize: fileSize,
+		tags:     tags,
+		emit:     emit,
+	}
+}
+
+// Add 添加已哈希的ID
+func (p *Packer) Add(dataType datasource.IdItem_DataType, id string) error {
+	p.row = append(p.row, &datasource.IdItem{
+		DataType: dataType.Enum(),
+		Id:       proto.String(id),
+		Tags:     p.tags,
+	})
+	if len(p.row) < p.rowSize {
+		return nil
+	}
+	return p.flushRow()
+}
+
+// Close 写入剩余的ID并生成最后一个文件
+func (p *Packer) Close() error {
+	if err := p.flushRow(); err != nil {
+		return err
+	}
+	return p.flushFile()
+}
+
+// flushRow 将当前行写入 zip，写入前预估超过 fileSize 时先切分文件
+func (p *Packer) flushRow() error {
+	if len(p.row) == 0 {
+		return nil
+	}
+	bs, err := proto.Marshal(&datasource.DmpData{IdList: p.row})
+	if err != nil {
+		return fmt.Errorf("dmp: marshal DmpData failed: %w", err)
+	}
+	line := make([]byte, base64.StdEncoding.EncodedLen(len(bs))+1)
+	base64.StdEncoding.Encode(line, bs)
+	line[len(line)-1] = '\n'
+
+	if p.file.Rows > 0 && p.estimate(len(line)) > p.fileSize {
+		if err := p.flushFile(); err != nil {
+			return err
+		}
+	}
+	if p.zw == nil {
+		p.seq++
+		p.file = File{Name: fmt.Sprintf("dmp_%d.zip", p.seq)}
+		p.zw = zip.NewWriter(&p.buf)
+		if p.entry, err = p.zw.Create(fmt.Sprintf("dmp_%d.txt", p.seq)); err != nil {
+			return err
+		}
+	}
+	if _, err := p.entry.Write(line); err != nil {
+		return err
+	}
+	if err := p.zw.Flush(); err != nil {
+		return err
+	}
+	p.file.Rows++
+	p.file.IDs += len(p.row)
+	p.raw += len(line)
+	p.row = p.row[:0]
+	return nil
+}
+
+// estimate 预估写入 n 字节后的 zip 大小
+// deflate 后的数据不会明显大于原文，已输出的压缩数据加上 deflate 缓冲的数据也是上限，取两者较小值
+func (p *Packer) estimate(n int) int {
+	written := min(p.raw, p.buf.Len()+flateWindow)
+	return written + n + n/100 + zipOverhead
+}
+
+// flushFile 结束当前 zip 文件并交给 emit
+func (p *Packer) flushFile() error {
+	if p.zw == nil {
+		return nil
+	}
+	if err := p.zw.Close(); err != nil {
+		return err
+	}
+	file := p.file
+	file.Data = bytes.Clone(p.buf.Bytes())
+	p.zw, p.entry = nil, nil
+	p.buf.Reset()
+	p.raw = 0
+	return p.emit(&file)
+}
@@ -2,9 +2,11 @@ package file
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
 	"github.com/bububa/oceanengine/marketing-api/model/file"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // CarouselAwemeGet 获取创编可用的抖音图文素材
@@ -18,3 +20,19 @@ func CarouselAwemeGet(ctx context.Context, clt *core.SDKClient, accessToken stri
 	}
 	return resp.Data, nil
 }
+
+// AllCarouselAwemeGet 自动翻页获取创编可用的抖音图文素材，从 req.Cursor 开始遍历全部结果
+func AllCarouselAwemeGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *file.CarouselAwemeGetRequest, opts ...pager.Option) iter.Seq2[file.AwemeCarousel, error] {
+	return pager.Cursor(ctx, req.Cursor, func(ctx context.Context, cursor string) ([]file.AwemeCarousel, string, bool, error) {
+		r := *req
+		r.Cursor = cursor
+		data, err := CarouselAwemeGet(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, "", false, err
+		}
+		if data.CursorInfo == nil {
+			return data.AwemeCarouseList, "", false, nil
+		}
+		return data.AwemeCarouseList, data.CursorInfo.Cursor, data.CursorInfo.HasMore, nil
+	}, opts...)
+}
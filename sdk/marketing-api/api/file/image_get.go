@@ -2,9 +2,12 @@ package file
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/file"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // ImageGet 获取图片素材
@@ -20,3 +23,16 @@ func ImageGet(ctx context.Context, clt *core.SDKClient, accessToken string, req
 	}
 	return resp.Data, nil
 }
+
+// AllImageGet 自动翻页获取图片素材，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllImageGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *file.ImageGetRequest, opts ...pager.Option) iter.Seq2[file.Image, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]file.Image, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		data, err := ImageGet(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, data.PageInfo, nil
+	}, opts...)
+}
@@ -2,9 +2,12 @@ package file
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/file"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // VideoGet 获取视频素材
@@ -17,3 +20,16 @@ func VideoGet(ctx context.Context, clt *core.SDKClient, accessToken string, req
 	}
 	return resp.Data, nil
 }
+
+// AllVideoGet 自动翻页获取视频素材，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllVideoGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *file.VideoGetRequest, opts ...pager.Option) iter.Seq2[file.Video, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]file.Video, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		data, err := VideoGet(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, data.PageInfo, nil
+	}, opts...)
+}
@@ -2,9 +2,12 @@ package ad
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/qianchuan/ad"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // Get 获取账户下计划列表（不含创意）
@@ -17,3 +20,16 @@ func Get(ctx context.Context, clt *core.SDKClient, accessToken string, req *ad.G
 	}
 	return resp.Data, nil
 }
+
+// AllGet 自动翻页获取账户下计划列表，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *ad.GetRequest, opts ...pager.Option) iter.Seq2[ad.Ad, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]ad.Ad, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		data, err := Get(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, &data.PageInfo, nil
+	}, opts...)
+}
@@ -2,9 +2,12 @@ package campaign
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/qianchuan/campaign"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // ListGet 获取广告组
@@ -17,3 +20,16 @@ func ListGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *
 	}
 	return resp.Data, nil
 }
+
+// AllListGet 自动翻页获取广告组，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllListGet(ctx context.Context, clt *core.SDKClient, accessToken string, req *campaign.ListGetRequest, opts ...pager.Option) iter.Seq2[campaign.Campaign, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]campaign.Campaign, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		data, err := ListGet(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, &data.PageInfo, nil
+	}, opts...)
+}
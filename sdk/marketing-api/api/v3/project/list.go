@@ -2,9 +2,12 @@ package project
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // List 获取广告项目列表
@@ -15,3 +18,16 @@ func List(ctx context.Context, clt *core.SDKClient, accessToken string, req *pro
 	}
 	return resp.Data, nil
 }
+
+// AllList 自动翻页获取广告项目列表，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllList(ctx context.Context, clt *core.SDKClient, accessToken string, req *project.ListRequest, opts ...pager.Option) iter.Seq2[project.Project, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]project.Project, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		data, err := List(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, data.PageInfo, nil
+	}, opts...)
+}
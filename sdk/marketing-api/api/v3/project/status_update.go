@@ -5,6 +5,7 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/core"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/util/batch"
 )
 
 // StatusUpdate 更新项目状态
@@ -15,3 +16,21 @@ func StatusUpdate(ctx context.Context, clt *core.SDKClient, accessToken string,
 	}
 	return resp.Data, nil
 }
+
+// StatusUpdateBatchSize 更新项目状态单次请求数据条数上限
+const StatusUpdateBatchSize = project.StatusUpdateLimit
+
+// BatchStatusUpdate 批量更新项目状态，按 StatusUpdateBatchSize 自动分批请求并合并结果
+func BatchStatusUpdate(ctx context.Context, clt *core.SDKClient, accessToken string, req *project.StatusUpdateRequest, opts ...batch.Option) *batch.Result[uint64] {
+	return batch.Run(ctx, req.Data, func(d project.StatusUpdateData) uint64 {
+		return d.ProjectID
+	}, func(ctx context.Context, chunk []project.StatusUpdateData) (map[uint64]error, error) {
+		r := *req
+		r.Data = chunk
+		data, err := StatusUpdate(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return data.Failures(), nil
+	}, StatusUpdateBatchSize, opts...)
+}
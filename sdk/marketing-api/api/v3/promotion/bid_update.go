@@ -5,6 +5,7 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/core"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/util/batch"
 )
 
 // BidUpdate 更新出价
@@ -16,3 +17,21 @@ func BidUpdate(ctx context.Context, clt *core.SDKClient, accessToken string, req
 	}
 	return resp.Data, nil
 }
+
+// BidUpdateBatchSize 更新广告出价单次请求数据条数上限
+const BidUpdateBatchSize = promotion.BidUpdateLimit
+
+// BatchBidUpdate 批量更新广告出价，按 BidUpdateBatchSize 自动分批请求并合并结果
+func BatchBidUpdate(ctx context.Context, clt *core.SDKClient, accessToken string, req *promotion.BidUpdateRequest, opts ...batch.Option) *batch.Result[uint64] {
+	return batch.Run(ctx, req.Data, func(d promotion.BidUpdateData) uint64 {
+		return d.PromotionID
+	}, func(ctx context.Context, chunk []promotion.BidUpdateData) (map[uint64]error, error) {
+		r := *req
+		r.Data = chunk
+		data, err := BidUpdate(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return data.Failures(), nil
+	}, BidUpdateBatchSize, opts...)
+}
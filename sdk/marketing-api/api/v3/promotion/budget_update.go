@@ -5,6 +5,7 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/core"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/util/batch"
 )
 
 // BudgetUpdate 更新广告预算
@@ -16,3 +17,21 @@ func BudgetUpdate(ctx context.Context, clt *core.SDKClient, accessToken string,
 	}
 	return resp.Data, nil
 }
+
+// BudgetUpdateBatchSize 更新广告预算单次请求数据条数上限
+const BudgetUpdateBatchSize = promotion.BudgetUpdateLimit
+
+// BatchBudgetUpdate 批量更新广告预算，按 BudgetUpdateBatchSize 自动分批请求并合并结果
+func BatchBudgetUpdate(ctx context.Context, clt *core.SDKClient, accessToken string, req *promotion.BudgetUpdateRequest, opts ...batch.Option) *batch.Result[uint64] {
+	return batch.Run(ctx, req.Data, func(d promotion.BudgetUpdateData) uint64 {
+		return d.PromotionID
+	}, func(ctx context.Context, chunk []promotion.BudgetUpdateData) (map[uint64]error, error) {
+		r := *req
+		r.Data = chunk
+		data, err := BudgetUpdate(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return data.Failures(), nil
+	}, BudgetUpdateBatchSize, opts...)
+}
@@ -2,9 +2,12 @@ package promotion
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
 )
 
 // List 获取广告列表
@@ -15,3 +18,17 @@ func List(ctx context.Context, clt *core.SDKClient, accessToken string, req *pro
 	}
 	return resp.Data, nil
 }
+
+// AllList 自动翻页获取广告列表，从第 1 页开始遍历全部结果，req.Page 将被忽略
+func AllList(ctx context.Context, clt *core.SDKClient, accessToken string, req *promotion.ListRequest, opts ...pager.Option) iter.Seq2[promotion.Promotion, error] {
+	return pager.Pages(ctx, func(ctx context.Context, page int) ([]promotion.Promotion, *model.PageInfo, error) {
+		r := *req
+		r.Page = page
+		r.Cursor, r.Count = 0, 0
+		data, err := List(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, nil, err
+		}
+		return data.List, data.PageInfo, nil
+	}, opts...)
+}
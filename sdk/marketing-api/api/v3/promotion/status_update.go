@@ -5,6 +5,7 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/core"
 	"github.com/bububa/oceanengine/marketing-api/model/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/util/batch"
 )
 
 // StatusUpdate 更新广告状态
@@ -16,3 +17,21 @@ func StatusUpdate(ctx context.Context, clt *core.SDKClient, accessToken string,
 	}
 	return resp.Data, nil
 }
+
+// StatusUpdateBatchSize 更新广告状态单次请求数据条数上限
+const StatusUpdateBatchSize = promotion.StatusUpdateLimit
+
+// BatchStatusUpdate 批量更新广告状态，按 StatusUpdateBatchSize 自动分批请求并合并结果
+func BatchStatusUpdate(ctx context.Context, clt *core.SDKClient, accessToken string, req *promotion.StatusUpdateRequest, opts ...batch.Option) *batch.Result[uint64] {
+	return batch.Run(ctx, req.Data, func(d promotion.StatusUpdateData) uint64 {
+		return d.PromotionID
+	}, func(ctx context.Context, chunk []promotion.StatusUpdateData) (map[uint64]error, error) {
+		r := *req
+		r.Data = chunk
+		data, err := StatusUpdate(ctx, clt, accessToken, &r)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return data.Failures(), nil
+	}, StatusUpdateBatchSize, opts...)
+}
@@ -8,14 +8,15 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"mime/multipart"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/bububa/oceanengine/marketing-api/core/internal/debug"
 	"github.com/bububa/oceanengine/marketing-api/model"
+	"github.com/bububa/oceanengine/marketing-api/model/errcode"
 	"github.com/bububa/oceanengine/marketing-api/util"
 )
 
@@ -40,15 +41,17 @@ func defaultHttpClient() *http.Client {
 
 // SDKClient sdk client
 type SDKClient struct {
-	client     *http.Client
-	tracer     *Otel
-	limiter    RateLimiter
-	Secret     string
-	operatorIP string
-	preReqs    []PreRequest
-	AppID      uint64
-	debug      bool
-	sandbox    bool
+	client      *http.Client
+	tracer      *Otel
+	limiter     RateLimiterV2
+	Secret      string
+	operatorIP  string
+	preReqs     []PreRequest
+	middlewares []Middleware
+	AppID       uint64
+	debug       bool
+	sandbox     bool
+	validation  bool
 }
 
 // NewSDKClient 创建SDKClient
@@ -80,13 +83,28 @@ func (c *SDKClient) DisableSandbox() {
 	c.sandbox = false
 }
 
+// SetValidation 设置是否校验请求参数，启用后 Post/Get 请求实现 model.Validator 时先调用 Validate，
+// 校验失败直接返回 validate.Errors，不发出请求
+func (c *SDKClient) SetValidation(enabled bool) {
+	c.validation = enabled
+}
+
 // SetOperatorIP 设置操作者IP, 支持ipv4/ipv6
 func (c *SDKClient) SetOperatorIP(ip string) {
 	c.operatorIP = ip
 }
 
-// SetRateLimiter 设置限流
+// SetRateLimiter 设置限流 (v1)
 func (c *SDKClient) SetRateLimiter(limiter RateLimiter) {
+	if limiter == nil {
+		c.limiter = nil
+		return
+	}
+	c.limiter = legacyRateLimiter{limiter: limiter}
+}
+
+// SetRateLimiterV2 设置限流 (v2)，按接口路径和广告主限流；实现 ThrottleObserver 时根据限流返回码自适应降速
+func (c *SDKClient) SetRateLimiterV2(limiter RateLimiterV2) {
 	c.limiter = limiter
 }
 
@@ -105,14 +123,17 @@ func (c *SDKClient) AddPreRequests(reqs ...PreRequest) {
 // Copy 复制SDKClient
 func (c *SDKClient) Copy() *SDKClient {
 	return &SDKClient{
-		AppID:      c.AppID,
-		Secret:     c.Secret,
-		debug:      c.debug,
-		sandbox:    c.sandbox,
-		operatorIP: c.operatorIP,
-		client:     c.client,
-		tracer:     c.tracer,
-		preReqs:    c.preReqs,
+		AppID:       c.AppID,
+		Secret:      c.Secret,
+		debug:       c.debug,
+		sandbox:     c.sandbox,
+		validation:  c.validation,
+		operatorIP:  c.operatorIP,
+		client:      c.client,
+		tracer:      c.tracer,
+		limiter:     c.limiter,
+		preReqs:     c.preReqs,
+		middlewares: c.middlewares,
 	}
 }
 
@@ -149,6 +170,9 @@ func (c *SDKClient) UploadAPI(ctx context.Context, gw string, req model.UploadRe
 }
 
 func (c *SDKClient) post(ctx context.Context, base string, gw string, req model.PostRequest, resp model.Response, accessToken string) error {
+	if err := c.validate(req); err != nil {
+		return err
+	}
 	var reqBytes []byte
 	if req != nil {
 		reqBytes = req.Encode()
@@ -168,14 +192,21 @@ func (c *SDKClient) post(ctx context.Context, base string, gw string, req model.
 	if c.sandbox {
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
-	if c.limiter != nil {
-		c.limiter.Take()
-	}
 	debug.PrintJSONRequest("POST", reqUrl, httpReq.Header, reqBytes, c.debug)
-	return c.WithSpan(ctx, httpReq, resp, reqBytes, c.fetch)
+	return c.invoke(ctx, &Call{
+		Gateway:      gw,
+		Request:      httpReq,
+		Payload:      reqBytes,
+		Response:     resp,
+		advertiserID: sync.OnceValue(func() uint64 { return advertiserIDFromJSON(reqBytes) }),
+		rateLimited:  true,
+	}, c.fetch)
 }
 
 func (c *SDKClient) get(ctx context.Context, base string, gw string, req model.GetRequest, resp model.Response, accessToken string) error {
+	if err := c.validate(req); err != nil {
+		return err
+	}
 	reqUrl := util.StringsJoin(base, gw)
 	if req != nil {
 		reqUrl = util.StringsJoin(reqUrl, "?", req.Encode())
@@ -194,10 +225,13 @@ func (c *SDKClient) get(ctx context.Context, base string, gw string, req model.G
 	if c.sandbox {
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
-	if c.limiter != nil {
-		c.limiter.Take()
-	}
-	return c.WithSpan(ctx, httpReq, resp, nil, c.fetch)
+	return c.invoke(ctx, &Call{
+		Gateway:      gw,
+		Request:      httpReq,
+		Response:     resp,
+		advertiserID: sync.OnceValue(func() uint64 { return advertiserIDFromQuery(httpReq.URL.RawQuery) }),
+		rateLimited:  true,
+	}, c.fetch)
 }
 
 // GetBytes get bytes api
@@ -220,11 +254,13 @@ func (c *SDKClient) GetBytes(ctx context.Context, gw string, req model.GetReques
 	if c.sandbox {
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
-	if c.limiter != nil {
-		c.limiter.Take()
-	}
 	var ret []byte
-	err = c.WithSpan(ctx, httpReq, nil, nil, func(httpReq *http.Request, resp model.Response) (*http.Response, error) {
+	err = c.invoke(ctx, &Call{
+		Gateway:      gw,
+		Request:      httpReq,
+		advertiserID: sync.OnceValue(func() uint64 { return advertiserIDFromQuery(httpReq.URL.RawQuery) }),
+		rateLimited:  true,
+	}, func(httpReq *http.Request, resp model.Response) (*http.Response, error) {
 		httpResp, err := c.client.Do(httpReq)
 		if err != nil {
 			return httpResp, err
@@ -237,46 +273,42 @@ func (c *SDKClient) GetBytes(ctx context.Context, gw string, req model.GetReques
 }
 
 func (c *SDKClient) upload(ctx context.Context, base string, gw string, req model.UploadRequest, resp model.Response, accessToken string) error {
-	buf := util.GetBufferPool()
-	defer util.PutBufferPool(buf)
-	mw := multipart.NewWriter(buf)
-	params := req.Encode()
-	mp := make(map[string]string, len(params))
-	for _, v := range params {
-		var (
-			fw  io.Writer
-			r   io.Reader
-			err error
-		)
-		if v.Reader != nil {
-			if fw, err = mw.CreateFormFile(v.Key, v.Value); err != nil {
-				return err
-			}
-			r = v.Reader
+	fields := req.Encode()
+	mp := make(map[string]string, len(fields))
+	for _, v := range fields {
+		if v.IsFile() {
 			builder := util.GetStringsBuilder()
 			builder.WriteString("@")
 			builder.WriteString(v.Value)
 			mp[v.Key] = builder.String()
 			util.PutStringsBuilder(builder)
-		} else {
-			if fw, err = mw.CreateFormField(v.Key); err != nil {
-				return err
+			if v.SignatureKey != "" {
+				mp[v.SignatureKey] = "(md5)"
 			}
-			r = strings.NewReader(v.Value)
+		} else {
 			mp[v.Key] = v.Value
 		}
-		if _, err = io.Copy(fw, r); err != nil {
-			return err
-		}
 	}
-	mw.Close()
 	reqUrl := util.StringsJoin(base, gw)
 	debug.PrintPostMultipartRequest(reqUrl, mp, c.debug)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, buf)
+	body := newUploadBody(fields)
+	reqBody, err := body.Open()
 	if err != nil {
 		return err
 	}
-	httpReq.Header.Add("Content-Type", mw.FormDataContentType())
+	// 请求未发出时关闭管道，结束写入 goroutine
+	defer reqBody.Close()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, reqBody)
+	if err != nil {
+		return err
+	}
+	if l := body.ContentLength(); l >= 0 {
+		httpReq.ContentLength = l
+	}
+	if body.replayable() {
+		httpReq.GetBody = body.Open
+	}
+	httpReq.Header.Add("Content-Type", body.ContentType())
 	if accessToken != "" {
 		httpReq.Header.Add("Access-Token", accessToken)
 	}
@@ -286,12 +318,19 @@ func (c *SDKClient) upload(ctx context.Context, base string, gw string, req mode
 	if c.sandbox {
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
-	if c.limiter != nil {
-		c.limiter.Take()
-	}
 
 	bs, _ := json.Marshal(mp)
-	return c.WithSpan(ctx, httpReq, resp, bs, c.fetch)
+	return c.invoke(ctx, &Call{
+		Gateway:  gw,
+		Request:  httpReq,
+		Payload:  bs,
+		Response: resp,
+		advertiserID: func() uint64 {
+			id, _ := strconv.ParseUint(mp["advertiser_id"], 10, 64)
+			return id
+		},
+		rateLimited: true,
+	}, c.fetch)
 }
 
 // TrackActive 转化回传API专用
@@ -315,10 +354,16 @@ func (c *SDKClient) TrackActive(ctx context.Context, req model.TrackRequest, res
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
 	debug.PrintJSONRequest("POST", reqUrl, httpReq.Header, reqBytes, c.debug)
+	call := &Call{
+		Gateway:  httpReq.URL.Path,
+		Request:  httpReq,
+		Payload:  reqBytes,
+		Response: resp,
+	}
 	if resp != nil {
-		return c.WithSpan(ctx, httpReq, resp, reqBytes, c.fetch)
+		return c.invoke(ctx, call, c.fetch)
 	}
-	return c.WithSpan(ctx, httpReq, nil, reqBytes, func(httpReq *http.Request, resp model.Response) (*http.Response, error) {
+	return c.invoke(ctx, call, func(httpReq *http.Request, resp model.Response) (*http.Response, error) {
 		httpResp, err := c.client.Do(httpReq)
 		if err != nil {
 			return httpResp, err
@@ -363,7 +408,13 @@ func (c *SDKClient) AnalyticsPost(ctx context.Context, gw string, req model.Conv
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
 	debug.PrintJSONRequest("POST", reqUrl, httpReq.Header, reqBytes, c.debug)
-	return c.WithSpan(ctx, httpReq, resp, reqBytes, c.fetch)
+	return c.invoke(ctx, &Call{
+		Gateway:      gw,
+		Request:      httpReq,
+		Payload:      reqBytes,
+		Response:     resp,
+		advertiserID: sync.OnceValue(func() uint64 { return advertiserIDFromJSON(reqBytes) }),
+	}, c.fetch)
 }
 
 // AnalyticsV1Post 电话转化回传API专用
@@ -387,7 +438,76 @@ func (c *SDKClient) AnalyticsV1Post(ctx context.Context, gw string, req model.Po
 		httpReq.Header.Add("X-Debug-Mode", "1")
 	}
 	debug.PrintJSONRequest("POST", reqUrl, httpReq.Header, reqBytes, c.debug)
-	return c.WithSpan(ctx, httpReq, resp, reqBytes, c.fetch)
+	return c.invoke(ctx, &Call{
+		Gateway:      gw,
+		Request:      httpReq,
+		Payload:      reqBytes,
+		Response:     resp,
+		advertiserID: sync.OnceValue(func() uint64 { return advertiserIDFromJSON(reqBytes) }),
+	}, c.fetch)
+}
+
+// rateLimit 等待限流，advertiserID 仅在设置了限流时解析
+// validate 启用校验且请求实现 model.Validator 时校验请求参数
+func (c *SDKClient) validate(req any) error {
+	if !c.validation {
+		return nil
+	}
+	if v, ok := req.(model.Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+func (c *SDKClient) rateLimit(ctx context.Context, gw string, advertiserID func() uint64) (RateLimitKey, error) {
+	if c.limiter == nil {
+		return RateLimitKey{}, nil
+	}
+	key := RateLimitKey{Path: gw, AdvertiserID: advertiserID()}
+	return key, c.limiter.Wait(ctx, key)
+}
+
+// observeThrottle 将请求结果反馈给限流
+func (c *SDKClient) observeThrottle(key RateLimitKey, err error) {
+	observer, ok := c.limiter.(ThrottleObserver)
+	if !ok {
+		return
+	}
+	if IsThrottled(err) {
+		observer.OnThrottle(key)
+		return
+	}
+	observer.OnSuccess(key)
+}
+
+// IsThrottled 是否为限流错误，即返回码属于 errcode.CategoryRateLimit 分类，见 DefaultThrottleCodes
+func IsThrottled(err error) bool {
+	return errcode.IsCategory(err, errcode.CategoryRateLimit)
+}
+
+// advertiserIDFromJSON 读取请求体中的 advertiser_id
+func advertiserIDFromJSON(body []byte) uint64 {
+	if len(body) == 0 {
+		return 0
+	}
+	var req struct {
+		AdvertiserID json.Number `json:"advertiser_id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return 0
+	}
+	id, _ := strconv.ParseUint(req.AdvertiserID.String(), 10, 64)
+	return id
+}
+
+// advertiserIDFromQuery 读取查询参数中的 advertiser_id
+func advertiserIDFromQuery(rawQuery string) uint64 {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return 0
+	}
+	id, _ := strconv.ParseUint(values.Get("advertiser_id"), 10, 64)
+	return id
 }
 
 type PreRequest func(httpReq *http.Request) error
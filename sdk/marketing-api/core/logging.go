@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Logger 结构化日志，*slog.Logger 可直接使用；zap 等日志库实现 LogAttrs 即可接入
+type Logger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// Redacted 脱敏后的占位值
+const Redacted = "******"
+
+// DefaultRedactHeaders 日志中脱敏的请求头
+var DefaultRedactHeaders = []string{"Access-Token", "App-Access-Token", "X-Signature", "X-Rs256-Token"}
+
+// DefaultRedactFields 日志中脱敏的请求参数、请求体字段
+var DefaultRedactFields = []string{"access_token", "refresh_token", "app_access_token", "secret", "auth_code"}
+
+// LoggingOption 日志中间件选项
+type LoggingOption func(*loggingOptions)
+
+type loggingOptions struct {
+	payload bool
+	headers []string
+	fields  []string
+}
+
+// LogPayload 记录脱敏后的请求体，默认不记录
+func LogPayload() LoggingOption {
+	return func(o *loggingOptions) {
+		o.payload = true
+	}
+}
+
+// RedactHeaders 追加需要脱敏的请求头
+func RedactHeaders(headers ...string) LoggingOption {
+	return func(o *loggingOptions) {
+		o.headers = append(o.headers, headers...)
+	}
+}
+
+// RedactFields 追加需要脱敏的请求参数、请求体字段
+func RedactFields(fields ...string) LoggingOption {
+	return func(o *loggingOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// Logging 结构化日志中间件，每次调用记录一条日志：
+// 成功为 Info，返回码非 0 为 Warn，请求失败为 Error。
+// 字段包括 gateway、advertiser_id、latency、code、request_id，URL、请求头、请求体中的 token 和 secret 会被脱敏
+func Logging(logger Logger, opts ...LoggingOption) Middleware {
+	o := &loggingOptions{
+		headers: slices.Clone(DefaultRedactHeaders),
+		fields:  slices.Clone(DefaultRedactFields),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	bodyRe := redactBodyRegexp(o.fields)
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+			attrs := []slog.Attr{
+				slog.String("gateway", call.Gateway),
+				slog.String("method", call.Request.Method),
+				slog.String("url", RedactURL(call.Request.URL, o.fields...)),
+				slog.Uint64("advertiser_id", call.AdvertiserID()),
+				slog.Duration("latency", call.Latency),
+				slog.Int("code", call.Code()),
+				slog.String("request_id", call.RequestID()),
+			}
+			if call.HTTPResponse != nil {
+				attrs = append(attrs, slog.Int("status", call.HTTPResponse.StatusCode))
+			}
+			if o.payload {
+				attrs = append(attrs,
+					slog.Any("header", RedactHeader(call.Request.Header, o.headers...)),
+					slog.String("payload", redactBody(bodyRe, call.Payload)),
+				)
+			}
+			level, msg := slog.LevelInfo, "oceanengine api"
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				level, msg = slog.LevelError, "oceanengine api failed"
+				if call.Code() != 0 {
+					level, msg = slog.LevelWarn, "oceanengine api error"
+				}
+			}
+			logger.LogAttrs(ctx, level, msg, attrs...)
+			return err
+		}
+	}
+}
+
+// RedactHeader 返回脱敏后的请求头，多个值以逗号连接
+func RedactHeader(header http.Header, keys ...string) map[string]string {
+	ret := make(map[string]string, len(header))
+	for k, v := range header {
+		ret[k] = strings.Join(v, ",")
+		for _, key := range keys {
+			if strings.EqualFold(k, key) {
+				ret[k] = Redacted
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// RedactURL 返回查询参数脱敏后的 URL
+func RedactURL(u *url.URL, keys ...string) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return u.String()
+	}
+	var redacted bool
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			values.Set(key, Redacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	ret := *u
+	ret.RawQuery = values.Encode()
+	return ret.String()
+}
+
+// RedactBody 返回脱敏后的 JSON 请求体
+func RedactBody(body []byte, keys ...string) string {
+	return redactBody(redactBodyRegexp(keys), body)
+}
+
+func redactBodyRegexp(keys []string) *regexp.Regexp {
+	if len(keys) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(k))
+	}
+	return regexp.MustCompile(`("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+}
+
+func redactBody(re *regexp.Regexp, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if re == nil {
+		return string(body)
+	}
+	return re.ReplaceAllString(string(body), `$1"`+Redacted+`"`)
+}
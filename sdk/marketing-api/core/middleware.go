@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+)
+
+// Call 一次 API 调用，中间件可读取请求信息，在调用完成后读取响应
+type Call struct {
+	// Gateway 接口网关路径，如 v3.0/project/list/
+	Gateway string
+	// Request http 请求
+	Request *http.Request
+	// Payload 请求体；multipart 请求为不含文件内容的字段 JSON
+	Payload []byte
+	// Response 解析后的响应，GetBytes、无响应体的回传接口为 nil
+	Response model.Response
+	// HTTPResponse http 响应，body 已读取并关闭；短路或请求未发出时为 nil
+	HTTPResponse *http.Response
+	// StartTime 开始时间
+	StartTime time.Time
+	// Latency 耗时，包含限流等待；短路的请求为 0
+	Latency time.Duration
+
+	advertiserID func() uint64
+	rateLimited  bool
+}
+
+// AdvertiserID 请求中的 advertiser_id，首次调用时解析请求体或查询参数
+func (c *Call) AdvertiserID() uint64 {
+	if c.advertiserID == nil {
+		return 0
+	}
+	return c.advertiserID()
+}
+
+// Code 返回码，HTTP 请求失败或响应无法解析时为 0
+func (c *Call) Code() int {
+	if c.Response == nil {
+		return 0
+	}
+	var coder interface{ ErrorCode() int }
+	if errors.As(c.Response, &coder) {
+		return coder.ErrorCode()
+	}
+	return 0
+}
+
+// RequestID 巨量引擎返回的 request_id，响应中没有时读取 X-Tt-Logid 响应头
+func (c *Call) RequestID() string {
+	if r, ok := c.Response.(interface{ APIRequestID() string }); ok {
+		if id := r.APIRequestID(); id != "" {
+			return id
+		}
+	}
+	if c.HTTPResponse != nil {
+		return c.HTTPResponse.Header.Get("X-Tt-Logid")
+	}
+	return ""
+}
+
+// Decode 将 JSON 响应解析到 Response，供短路的中间件 (缓存、模拟) 返回结果；返回码非 0 时返回对应错误
+func (c *Call) Decode(body []byte) error {
+	resp := c.Response
+	if resp == nil {
+		resp = &model.BaseResponse{}
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp
+	}
+	return nil
+}
+
+// Handler 执行一次 API 调用
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware API 调用中间件，不调用 next 即可短路请求
+type Middleware func(next Handler) Handler
+
+// BeforeHook 请求发出前执行，返回错误时终止请求
+type BeforeHook func(ctx context.Context, call *Call) error
+
+// AfterHook 请求完成后执行，err 为调用结果
+type AfterHook func(ctx context.Context, call *Call, err error)
+
+// Hooks 将请求前后钩子转换为中间件，before/after 均可为 nil
+func Hooks(before BeforeHook, after AfterHook) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if before != nil {
+				if err := before(ctx, call); err != nil {
+					return err
+				}
+			}
+			err := next(ctx, call)
+			if after != nil {
+				after(ctx, call, err)
+			}
+			return err
+		}
+	}
+}
+
+// Use 追加中间件，先添加的中间件在外层
+func (c *SDKClient) Use(mws ...Middleware) {
+	c.middlewares = append(c.middlewares[:len(c.middlewares):len(c.middlewares)], mws...)
+}
+
+// WithMiddlewares 替换全部中间件
+func (c *SDKClient) WithMiddlewares(mws ...Middleware) {
+	c.middlewares = mws
+}
+
+// invoke 经过中间件执行调用，限流在中间件之后进行，短路的请求不占用限流额度
+func (c *SDKClient) invoke(ctx context.Context, call *Call, fn func(*http.Request, model.Response) (*http.Response, error)) error {
+	handler := func(ctx context.Context, call *Call) error {
+		var key RateLimitKey
+		if call.rateLimited {
+			var err error
+			if key, err = c.rateLimit(ctx, call.Gateway, call.AdvertiserID); err != nil {
+				return err
+			}
+		}
+		err := c.WithSpan(ctx, call.Request, call.Response, call.Payload, func(httpReq *http.Request, resp model.Response) (*http.Response, error) {
+			httpResp, err := fn(httpReq, resp)
+			call.HTTPResponse = httpResp
+			return httpResp, err
+		})
+		if call.rateLimited {
+			c.observeThrottle(key, err)
+		}
+		call.Latency = time.Since(call.StartTime)
+		return err
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	call.StartTime = time.Now()
+	return handler(ctx, call)
+}
@@ -0,0 +1,157 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/bububa/oceanengine/marketing-api/api/oauth"
+	"github.com/bububa/oceanengine/marketing-api/api/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model/errcode"
+	projectModel "github.com/bububa/oceanengine/marketing-api/model/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/testing/oetest"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
+)
+
+func TestMiddleware(t *testing.T) {
+	srv := oetest.NewServer(t)
+	route := srv.Handle("v3.0/project/list").Fail(40002, "没有权限")
+	clt := srv.SDKClient(1, "secret")
+
+	var (
+		order []string
+		got   core.Call
+	)
+	clt.Use(
+		core.Hooks(func(ctx context.Context, call *core.Call) error {
+			order = append(order, "before1")
+			return nil
+		}, func(ctx context.Context, call *core.Call, err error) {
+			order = append(order, "after1")
+			got = *call
+		}),
+		core.Hooks(func(ctx context.Context, call *core.Call) error {
+			order = append(order, "before2")
+			return nil
+		}, func(ctx context.Context, call *core.Call, err error) {
+			order = append(order, "after2")
+		}),
+	)
+	_, err := project.List(context.Background(), clt, "token", &projectModel.ListRequest{AdvertiserID: 123})
+	if !errors.Is(err, errcode.ErrNoPermission) {
+		t.Fatalf("err = %v", err)
+	}
+	if strings.Join(order, ",") != "before1,before2,after2,after1" {
+		t.Errorf("order = %v", order)
+	}
+	if got.Gateway != "v3.0/project/list/" || got.AdvertiserID() != 123 || got.Code() != 40002 || got.RequestID() != oetest.RequestID || got.Latency <= 0 {
+		t.Errorf("call = %+v, advertiser_id = %d, code = %d, request_id = %s", got, got.AdvertiserID(), got.Code(), got.RequestID())
+	}
+
+	// 短路：不发出请求，直接返回缓存的响应
+	clt.WithMiddlewares(func(next core.Handler) core.Handler {
+		return func(ctx context.Context, call *core.Call) error {
+			return call.Decode([]byte(`{"code":0,"data":{"list":[{"project_id":1}]}}`))
+		}
+	})
+	data, err := project.List(context.Background(), clt, "token", &projectModel.ListRequest{AdvertiserID: 123})
+	if err != nil || len(data.List) != 1 || data.List[0].ProjectID != 1 {
+		t.Errorf("short circuit = %+v, %v", data, err)
+	}
+	if route.Calls() != 1 {
+		t.Errorf("calls = %d, want 1", route.Calls())
+	}
+}
+
+func TestLogging(t *testing.T) {
+	srv := oetest.NewServer(t)
+	srv.Handle("oauth2/access_token").Reply(map[string]any{"access_token": "at", "refresh_token": "rt"})
+	srv.Handle("v3.0/project/list").Fail(40002, "没有权限")
+	clt := srv.SDKClient(1, "app-secret")
+
+	var buf bytes.Buffer
+	clt.Use(core.Logging(slog.New(slog.NewJSONHandler(&buf, nil)), core.LogPayload()))
+
+	if _, err := oauth.AccessToken(context.Background(), clt, "auth-code"); err != nil {
+		t.Fatal(err)
+	}
+	project.List(context.Background(), clt, "access-token", &projectModel.ListRequest{AdvertiserID: 123})
+
+	for _, secret := range []string{"app-secret", "auth-code", "access-token"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("log contains %s: %s", secret, buf.String())
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log lines = %d", len(lines))
+	}
+	var entry struct {
+		Level        string `json:"level"`
+		Gateway      string `json:"gateway"`
+		AdvertiserID uint64 `json:"advertiser_id"`
+		Code         int    `json:"code"`
+		RequestID    string `json:"request_id"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != "WARN" || entry.Gateway != "v3.0/project/list/" || entry.AdvertiserID != 123 || entry.Code != 40002 || entry.RequestID != oetest.RequestID {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	got := core.RedactBody([]byte(`{"app_id":1,"secret":"s\"x","auth_code":"c","name":"n"}`), core.DefaultRedactFields...)
+	if want := `{"app_id":1,"secret":"******","auth_code":"******","name":"n"}`; got != want {
+		t.Errorf("RedactBody() = %s, want %s", got, want)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	srv := oetest.NewServer(t)
+	route := srv.Handle("v3.0/project/status/update").Reply(map[string]any{"project_ids": []uint64{1}})
+	clt := srv.SDKClient(1, "secret")
+
+	invalid := &projectModel.StatusUpdateRequest{
+		Data: []projectModel.StatusUpdateData{{ProjectID: 1, OptStatus: "PAUSE"}},
+	}
+	// 未启用校验时原样发出请求
+	if _, err := project.StatusUpdate(context.Background(), clt, "token", invalid); err != nil {
+		t.Fatal(err)
+	}
+
+	clt.SetValidation(true)
+	_, err := project.StatusUpdate(context.Background(), clt, "token", invalid)
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("err = %v, want validate.Errors", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "advertiser_id" || errs[1].Field != "data[0].opt_status" {
+		t.Errorf("errs = %v", errs)
+	}
+	if route.Calls() != 1 {
+		t.Errorf("calls = %d, want 1", route.Calls())
+	}
+
+	// 超出单次上限时拒绝，BatchStatusUpdate 分批后每批均通过校验
+	large := &projectModel.StatusUpdateRequest{AdvertiserID: 1}
+	for i := 1; i <= project.StatusUpdateBatchSize*2+5; i++ {
+		large.Data = append(large.Data, projectModel.StatusUpdateData{ProjectID: uint64(i), OptStatus: "ENABLE"})
+	}
+	_, err = project.StatusUpdate(context.Background(), clt, "token", large)
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "data" || errs[0].Rule != "max" {
+		t.Errorf("err = %v, want data max", err)
+	}
+	if ret := project.BatchStatusUpdate(context.Background(), clt, "token", large); ret.Err() != nil {
+		t.Errorf("BatchStatusUpdate() = %v", ret.Err())
+	}
+	if route.Calls() != 4 {
+		t.Errorf("calls = %d, want 4", route.Calls())
+	}
+}
@@ -1,9 +1,358 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/model/errcode"
 )
 
+// RateLimiter 限流 (v1)，不感知 context 和接口，建议使用 RateLimiterV2
 type RateLimiter interface {
 	Take() time.Duration
 }
+
+// RateLimitKey 限流维度
+type RateLimitKey struct {
+	// Path 接口网关路径，如 2/report/advertiser/get/
+	Path string
+	// AdvertiserID 请求参数中的广告主ID，请求未携带时为 0
+	AdvertiserID uint64
+}
+
+// RateLimiterV2 限流 (v2)
+type RateLimiterV2 interface {
+	// Wait 阻塞直到允许发起请求，ctx 取消时返回 ctx.Err()
+	Wait(ctx context.Context, key RateLimitKey) error
+}
+
+// ThrottleObserver 限流反馈，RateLimiterV2 实现该接口时 SDKClient 会在每次请求后回调，用于自适应降速
+type ThrottleObserver interface {
+	// OnThrottle 接口返回限流错误
+	OnThrottle(key RateLimitKey)
+	// OnSuccess 请求未被限流
+	OnSuccess(key RateLimitKey)
+}
+
+// DefaultThrottleCodes 视为限流的返回码，即 errcode.CategoryRateLimit 分类的返回码 (含应用维度频控 40110-40119 与 HTTP 429)
+var DefaultThrottleCodes = errcode.CategoryCodes(errcode.CategoryRateLimit)
+
+// RateLimitRule 限流规则
+type RateLimitRule struct {
+	// Path 接口网关路径，以 * 结尾表示前缀匹配，为空表示默认规则
+	Path string
+	// QPS 每秒请求数，<= 0 表示不限流
+	QPS float64
+	// Burst 突发请求数，<= 0 时取 1
+	Burst int
+	// PerAdvertiser 是否按广告主分别计数
+	PerAdvertiser bool
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	// Default 未匹配任何规则时的默认规则，每个接口单独计数
+	Default RateLimitRule
+	// Rules 按接口配置的规则，精确匹配优先，其次为最长前缀匹配
+	Rules []RateLimitRule
+	// MinFactor 自适应降速的最低比例，默认 0.1
+	MinFactor float64
+	// RecoverAfter 被限流后每隔该时间恢复一次速率，默认 10s
+	RecoverAfter time.Duration
+}
+
+// resolve 匹配规则并返回计数维度
+func (c *RateLimitConfig) resolve(key RateLimitKey) (RateLimitRule, string) {
+	path := normalizeGateway(key.Path)
+	rule, matched := c.Default, ""
+	for _, r := range c.Rules {
+		p := normalizeGateway(r.Path)
+		if p == path {
+			rule, matched = r, p
+			break
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(path, prefix) && len(p) > len(matched) {
+			rule, matched = r, p
+		}
+	}
+	// 按接口分别计数
+	bucketKey := path
+	if rule.PerAdvertiser {
+		bucketKey = bucketKey + "#" + strconv.FormatUint(key.AdvertiserID, 10)
+	}
+	return rule, bucketKey
+}
+
+func normalizeGateway(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.Trim(path, "/")
+}
+
+// adaptive 自适应速率系数
+// 被限流时速率减半（不低于 MinFactor），之后每隔 RecoverAfter 无限流则提升 25%，直至恢复配置速率
+type adaptive struct {
+	factor      float64
+	throttledAt time.Time
+}
+
+func (a *adaptive) throttle(now time.Time, minFactor float64) {
+	a.factor = max(a.factor/2, minFactor)
+	a.throttledAt = now
+}
+
+func (a *adaptive) recover(now time.Time, after time.Duration) {
+	if a.factor < 1 && now.Sub(a.throttledAt) >= after {
+		a.factor = min(a.factor*1.25, 1)
+		a.throttledAt = now
+	}
+}
+
+// limiterState 限流器共享的规则与自适应状态
+type limiterState struct {
+	cfg RateLimitConfig
+	mu  sync.Mutex
+	// adaptive 按计数维度记录
+	adaptive map[string]*adaptive
+}
+
+func newLimiterState(cfg RateLimitConfig) *limiterState {
+	if cfg.MinFactor <= 0 || cfg.MinFactor > 1 {
+		cfg.MinFactor = 0.1
+	}
+	if cfg.RecoverAfter <= 0 {
+		cfg.RecoverAfter = 10 * time.Second
+	}
+	return &limiterState{cfg: cfg, adaptive: make(map[string]*adaptive)}
+}
+
+// rate 返回当前生效的速率与突发数，qps <= 0 表示不限流
+func (s *limiterState) rate(key RateLimitKey) (bucketKey string, qps float64, burst int) {
+	rule, bucketKey := s.cfg.resolve(key)
+	burst = max(rule.Burst, 1)
+	if rule.QPS <= 0 {
+		return bucketKey, 0, burst
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.adaptive[bucketKey]; ok {
+		return bucketKey, rule.QPS * a.factor, burst
+	}
+	return bucketKey, rule.QPS, burst
+}
+
+// OnThrottle implement ThrottleObserver
+func (s *limiterState) OnThrottle(key RateLimitKey) {
+	_, bucketKey := s.cfg.resolve(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.adaptive[bucketKey]
+	if !ok {
+		a = &adaptive{factor: 1}
+		s.adaptive[bucketKey] = a
+	}
+	a.throttle(time.Now(), s.cfg.MinFactor)
+}
+
+// OnSuccess implement ThrottleObserver
+func (s *limiterState) OnSuccess(key RateLimitKey) {
+	_, bucketKey := s.cfg.resolve(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.adaptive[bucketKey]
+	if !ok {
+		return
+	}
+	a.recover(time.Now(), s.cfg.RecoverAfter)
+	if a.factor >= 1 {
+		delete(s.adaptive, bucketKey)
+	}
+}
+
+// sleep 等待 d 或 ctx 取消
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// reserve 预占一个令牌，返回需要等待的时间
+func (b *tokenBucket) reserve(now time.Time, qps float64, burst int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else {
+		b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*qps, float64(burst))
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / qps * float64(time.Second))
+}
+
+// cancel 归还预占的令牌
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
+
+// LocalRateLimiter 进程内限流，按接口/广告主分别计数，并根据限流返回码自适应降速
+type LocalRateLimiter struct {
+	*limiterState
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+var (
+	_ RateLimiterV2    = (*LocalRateLimiter)(nil)
+	_ ThrottleObserver = (*LocalRateLimiter)(nil)
+)
+
+// NewLocalRateLimiter 创建进程内限流
+func NewLocalRateLimiter(cfg RateLimitConfig) *LocalRateLimiter {
+	return &LocalRateLimiter{
+		limiterState: newLimiterState(cfg),
+		buckets:      make(map[string]*tokenBucket),
+	}
+}
+
+// Wait implement RateLimiterV2
+func (l *LocalRateLimiter) Wait(ctx context.Context, key RateLimitKey) error {
+	bucketKey, qps, burst := l.rate(key)
+	if qps <= 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	b, ok := l.buckets[bucketKey]
+	if !ok {
+		b = &tokenBucket{}
+		l.buckets[bucketKey] = b
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, b.reserve(time.Now(), qps, burst)); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
+
+// RedisEvaler 执行 Lua 脚本，可由 go-redis 等客户端适配：
+//
+//	func (a adapter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return a.rdb.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisEvaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// redisGCRAScript GCRA 限流，使用 Redis 服务器时间；允许时返回 0，否则返回需要等待的微秒数
+const redisGCRAScript = `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+local new_tat = tat + interval
+local allow_at = new_tat - burst * interval
+if now < allow_at then return allow_at - now end
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000) + 1000)
+return 0
+`
+
+// RedisRateLimiter 基于 Redis 的限流，多进程共享同一配额；自适应系数为进程内状态
+type RedisRateLimiter struct {
+	*limiterState
+	client RedisEvaler
+	prefix string
+}
+
+var (
+	_ RateLimiterV2    = (*RedisRateLimiter)(nil)
+	_ ThrottleObserver = (*RedisRateLimiter)(nil)
+)
+
+// NewRedisRateLimiter 创建基于 Redis 的限流，prefix 为计数 key 前缀
+func NewRedisRateLimiter(client RedisEvaler, prefix string, cfg RateLimitConfig) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		limiterState: newLimiterState(cfg),
+		client:       client,
+		prefix:       prefix,
+	}
+}
+
+// Wait implement RateLimiterV2
+func (l *RedisRateLimiter) Wait(ctx context.Context, key RateLimitKey) error {
+	bucketKey, qps, burst := l.rate(key)
+	if qps <= 0 {
+		return ctx.Err()
+	}
+	interval := int64(float64(time.Second/time.Microsecond) / qps)
+	redisKey := l.prefix + bucketKey
+	for {
+		ret, err := l.client.Eval(ctx, redisGCRAScript, []string{redisKey}, interval, burst)
+		if err != nil {
+			return err
+		}
+		wait, err := toInt64(ret)
+		if err != nil {
+			return err
+		}
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, time.Duration(wait)*time.Microsecond); err != nil {
+			return err
+		}
+	}
+}
+
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	}
+	return 0, errors.New("ratelimiter: unexpected redis result")
+}
+
+// legacyRateLimiter 适配 v1 RateLimiter
+type legacyRateLimiter struct {
+	limiter RateLimiter
+}
+
+// Wait implement RateLimiterV2
+func (l legacyRateLimiter) Wait(ctx context.Context, _ RateLimitKey) error {
+	l.limiter.Take()
+	return ctx.Err()
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+)
+
+func TestRateLimitConfig_Resolve(t *testing.T) {
+	cfg := RateLimitConfig{
+		Default: RateLimitRule{QPS: 10},
+		Rules: []RateLimitRule{
+			{Path: "2/report/*", QPS: 5},
+			{Path: "/2/report/advertiser/get/", QPS: 2, PerAdvertiser: true},
+		},
+	}
+
+	tests := []struct {
+		key    RateLimitKey
+		qps    float64
+		bucket string
+	}{
+		{RateLimitKey{Path: "2/ad/get/"}, 10, "2/ad/get"},
+		{RateLimitKey{Path: "2/report/campaign/get/"}, 5, "2/report/campaign/get"},
+		{RateLimitKey{Path: "2/report/advertiser/get/?page=1", AdvertiserID: 7}, 2, "2/report/advertiser/get#7"},
+	}
+	for _, tt := range tests {
+		rule, bucket := cfg.resolve(tt.key)
+		if rule.QPS != tt.qps || bucket != tt.bucket {
+			t.Errorf("resolve(%+v) = %v %q, want %v %q", tt.key, rule.QPS, bucket, tt.qps, tt.bucket)
+		}
+	}
+}
+
+func TestLocalRateLimiter_Wait(t *testing.T) {
+	limiter := NewLocalRateLimiter(RateLimitConfig{
+		Default: RateLimitRule{QPS: 20, Burst: 1},
+		Rules:   []RateLimitRule{{Path: "2/unlimited/", QPS: 0}},
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, RateLimitKey{Path: "2/ad/get/"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20 QPS took %v, want >= 100ms", elapsed)
+	}
+
+	// 不同接口、不限流接口互不影响
+	start = time.Now()
+	if err := limiter.Wait(ctx, RateLimitKey{Path: "2/campaign/get/"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(ctx, RateLimitKey{Path: "2/unlimited/"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("independent buckets took %v", elapsed)
+	}
+
+	// ctx 取消时立即返回
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	limiter.Wait(cctx, RateLimitKey{Path: "2/ad/get/"})
+	if err := limiter.Wait(cctx, RateLimitKey{Path: "2/ad/get/"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestLocalRateLimiter_Adaptive(t *testing.T) {
+	limiter := NewLocalRateLimiter(RateLimitConfig{
+		Default:      RateLimitRule{QPS: 100},
+		MinFactor:    0.2,
+		RecoverAfter: time.Millisecond,
+	})
+	key := RateLimitKey{Path: "2/ad/get/"}
+
+	limiter.OnThrottle(key)
+	if _, qps, _ := limiter.rate(key); qps != 50 {
+		t.Errorf("qps after throttle = %v, want 50", qps)
+	}
+	limiter.OnThrottle(key)
+	limiter.OnThrottle(key)
+	if _, qps, _ := limiter.rate(key); qps != 20 {
+		t.Errorf("qps floor = %v, want 20", qps)
+	}
+
+	for i := 0; i < 20; i++ {
+		time.Sleep(2 * time.Millisecond)
+		limiter.OnSuccess(key)
+	}
+	if _, qps, _ := limiter.rate(key); qps != 100 {
+		t.Errorf("qps after recover = %v, want 100", qps)
+	}
+}
+
+type fakeEvaler struct {
+	mu    sync.Mutex
+	waits []int64
+	keys  []string
+}
+
+func (f *fakeEvaler) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, keys...)
+	if len(f.waits) == 0 {
+		return int64(0), nil
+	}
+	w := f.waits[0]
+	f.waits = f.waits[1:]
+	return w, nil
+}
+
+func TestRedisRateLimiter_Wait(t *testing.T) {
+	evaler := &fakeEvaler{waits: []int64{2000, 0}}
+	limiter := NewRedisRateLimiter(evaler, "oe:rl:", RateLimitConfig{
+		Default: RateLimitRule{QPS: 10, PerAdvertiser: true},
+	})
+
+	if err := limiter.Wait(context.Background(), RateLimitKey{Path: "2/ad/get/", AdvertiserID: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if len(evaler.keys) != 2 || evaler.keys[0] != "oe:rl:2/ad/get#9" {
+		t.Errorf("redis keys = %v", evaler.keys)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type recordingLimiter struct {
+	waited    []RateLimitKey
+	throttled int
+	succeeded int
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context, key RateLimitKey) error {
+	l.waited = append(l.waited, key)
+	return nil
+}
+
+func (l *recordingLimiter) OnThrottle(RateLimitKey) { l.throttled++ }
+func (l *recordingLimiter) OnSuccess(RateLimitKey)  { l.succeeded++ }
+
+type testGetRequest struct{}
+
+func (testGetRequest) Encode() string { return "advertiser_id=123&page=1" }
+
+func TestSDKClient_RateLimiterV2(t *testing.T) {
+	code := "40100"
+	client := NewSDKClient(1, "secret")
+	client.SetHttpClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"code":` + code + `,"message":"too many requests"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})})
+	limiter := &recordingLimiter{}
+	client.SetRateLimiterV2(limiter)
+
+	copied := client.Copy()
+	if copied.limiter != client.limiter {
+		t.Fatal("Copy() dropped rate limiter")
+	}
+
+	var resp model.BaseResponse
+	err := copied.Get(context.Background(), "2/ad/get/", testGetRequest{}, &resp, "token")
+	if !IsThrottled(err) {
+		t.Fatalf("IsThrottled(%v) = false", err)
+	}
+	if !IsThrottled(model.BaseResponse{Code: 40115}) || IsThrottled(model.BaseResponse{Code: 61002}) {
+		t.Error("IsThrottled() mismatch for 40115/61002")
+	}
+	code = "0"
+	if err := copied.Get(context.Background(), "2/ad/get/", testGetRequest{}, &resp, "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(limiter.waited) != 2 || limiter.waited[0] != (RateLimitKey{Path: "2/ad/get/", AdvertiserID: 123}) {
+		t.Errorf("waited = %+v", limiter.waited)
+	}
+	if limiter.throttled != 1 || limiter.succeeded != 1 {
+		t.Errorf("throttled = %d, succeeded = %d", limiter.throttled, limiter.succeeded)
+	}
+}
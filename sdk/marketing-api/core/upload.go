@@ -0,0 +1,171 @@
+package core
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+)
+
+// uploadBody 流式 multipart/form-data 请求体，文件内容经 io.Pipe 边读边发，不在内存中缓存
+type uploadBody struct {
+	fields   []model.UploadField
+	boundary string
+}
+
+func newUploadBody(fields []model.UploadField) *uploadBody {
+	return &uploadBody{
+		fields:   fields,
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+// ContentType 请求 Content-Type
+func (b *uploadBody) ContentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+// replayable 文件均可重新打开时请求体可重放
+func (b *uploadBody) replayable() bool {
+	for _, f := range b.fields {
+		if f.IsFile() && f.Open == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentLength 文件大小均已知时返回请求体长度，否则返回 -1
+func (b *uploadBody) ContentLength() int64 {
+	var size int64
+	readers := make([]io.Reader, len(b.fields))
+	for i, f := range b.fields {
+		if !f.IsFile() {
+			continue
+		}
+		if f.Size <= 0 {
+			return -1
+		}
+		size += f.Size
+		readers[i] = strings.NewReader("")
+	}
+	// MD5 签名长度固定，使用空文件计算除文件内容以外的长度
+	var w countWriter
+	if err := b.write(&w, readers, false); err != nil {
+		return -1
+	}
+	return int64(w) + size
+}
+
+// Open 打开文件并返回请求体
+func (b *uploadBody) Open() (io.ReadCloser, error) {
+	readers := make([]io.Reader, len(b.fields))
+	closers := make([]io.Closer, 0, len(b.fields))
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+	for i, f := range b.fields {
+		if !f.IsFile() {
+			continue
+		}
+		if f.Open == nil {
+			readers[i] = f.Reader
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		readers[i] = rc
+		closers = append(closers, rc)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		defer closeAll()
+		pw.CloseWithError(b.write(pw, readers, true))
+	}()
+	return pr, nil
+}
+
+// write 写入 multipart 内容；签名字段写在对应文件之后
+func (b *uploadBody) write(w io.Writer, readers []io.Reader, progress bool) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+	for i, f := range b.fields {
+		if !f.IsFile() {
+			if err := mw.WriteField(f.Key, f.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		fw, err := mw.CreateFormFile(f.Key, f.Value)
+		if err != nil {
+			return err
+		}
+		r := readers[i]
+		if r == nil {
+			return fmt.Errorf("upload: field %s has no reader", f.Key)
+		}
+		hash := md5.New()
+		if f.SignatureKey != "" {
+			r = io.TeeReader(r, hash)
+		}
+		if progress && f.Progress != nil {
+			total := f.Size
+			if total <= 0 {
+				total = -1
+			}
+			r = &progressReader{r: r, total: total, fn: f.Progress}
+		}
+		n, err := io.Copy(fw, r)
+		if err != nil {
+			return err
+		}
+		if progress && f.Size > 0 && n != f.Size {
+			return errors.Join(ErrUploadSizeMismatch, fmt.Errorf("field %s: size %d, read %d", f.Key, f.Size, n))
+		}
+		if f.SignatureKey != "" {
+			if err := mw.WriteField(f.SignatureKey, hex.EncodeToString(hash.Sum(nil))); err != nil {
+				return err
+			}
+		}
+	}
+	return mw.Close()
+}
+
+// ErrUploadSizeMismatch 上传文件实际大小与 UploadField.Size 不一致
+var ErrUploadSizeMismatch = errors.New("upload: file size mismatch")
+
+type countWriter int64
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// progressReader 读取时回调上传进度
+type progressReader struct {
+	r     io.Reader
+	fn    func(sent int64, total int64)
+	sent  int64
+	total int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.fn(r.sent, r.total)
+	}
+	return n, err
+}
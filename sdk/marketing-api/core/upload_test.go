@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+	"github.com/bububa/oceanengine/marketing-api/model/file"
+)
+
+// parseMultipart 解析 multipart 请求体，文件字段返回内容
+func parseMultipart(t *testing.T, contentType string, body []byte) map[string]string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := make(map[string]string)
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return ret
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _ := io.ReadAll(part)
+		ret[part.FormName()] = string(value)
+	}
+}
+
+func TestSDKClient_UploadStream(t *testing.T) {
+	content := strings.Repeat("video-content-", 10000)
+	path := filepath.Join(t.TempDir(), "a.mp4")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	videoFile, err := model.NewUploadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent, total int64
+	videoFile.Progress = func(s int64, tt int64) {
+		sent, total = s, tt
+	}
+
+	var (
+		contentLength int64
+		received      []byte
+		replayed      []byte
+		contentType   string
+	)
+	client := NewSDKClient(1, "secret")
+	client.SetHttpClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		contentLength, contentType = req.ContentLength, req.Header.Get("Content-Type")
+		received, _ = io.ReadAll(req.Body)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			replayed, _ = io.ReadAll(body)
+			body.Close()
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"code":0,"message":"OK"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})})
+
+	var resp file.VideoAdResponse
+	req := &file.VideoAdRequest{AdvertiserID: 123, VideoFile: videoFile}
+	if err := client.Upload(context.Background(), "2/file/video/ad/", req, &resp, "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if contentLength != int64(len(received)) {
+		t.Errorf("ContentLength = %d, body length = %d", contentLength, len(received))
+	}
+	if !bytes.Equal(received, replayed) {
+		t.Error("GetBody() returned a different body")
+	}
+	form := parseMultipart(t, contentType, received)
+	sum := md5.Sum([]byte(content))
+	if form["video_file"] != content || form["advertiser_id"] != "123" || form["video_signature"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("form advertiser_id = %q, video_signature = %q", form["advertiser_id"], form["video_signature"])
+	}
+	if sent != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("progress = %d/%d", sent, total)
+	}
+}
+
+func TestSDKClient_UploadReader(t *testing.T) {
+	var (
+		contentLength int64
+		replayable    bool
+		form          map[string]string
+	)
+	client := NewSDKClient(1, "secret")
+	client.SetHttpClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		contentLength, replayable = req.ContentLength, req.GetBody != nil
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		form = parseMultipart(t, req.Header.Get("Content-Type"), body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"code":0,"message":"OK"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})})
+
+	req := &file.ImageAdRequest{
+		AdvertiserID:   1,
+		ImageSignature: "abc",
+		ImageFile:      &model.UploadField{Value: "a.png", Reader: strings.NewReader("png")},
+	}
+	if err := client.Upload(context.Background(), "2/file/image/ad/", req, nil, "token"); err != nil {
+		t.Fatal(err)
+	}
+	if contentLength > 0 || replayable {
+		t.Errorf("ContentLength = %d, replayable = %v", contentLength, replayable)
+	}
+	if form["image_file"] != "png" || form["image_signature"] != "abc" {
+		t.Errorf("form = %v", form)
+	}
+
+	// 文件实际大小与 Size 不一致
+	req.ImageFile = &model.UploadField{Value: "a.png", Size: 10, Open: func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("png")), nil
+	}}
+	err := client.Upload(context.Background(), "2/file/image/ad/", req, nil, "token")
+	if !errors.Is(err, ErrUploadSizeMismatch) {
+		t.Errorf("err = %v, want ErrUploadSizeMismatch", err)
+	}
+}
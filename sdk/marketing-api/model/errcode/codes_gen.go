@@ -0,0 +1,82 @@
+// Code generated by gen.go from codes.csv; DO NOT EDIT.
+
+package errcode
+
+var (
+	// ErrInvalidParam 40001 参数错误
+	ErrInvalidParam = &Error{Code: 40001, Name: "InvalidParam", Category: CategoryParam, Retryable: false, Message: "参数错误", MessageEN: "invalid parameter"}
+	// ErrNoPermission 40002 没有权限进行相关操作
+	ErrNoPermission = &Error{Code: 40002, Name: "NoPermission", Category: CategoryPermission, Retryable: false, Message: "没有权限进行相关操作", MessageEN: "no permission for this operation"}
+	// ErrInvalidFilterField 40003 过滤条件的字段错误
+	ErrInvalidFilterField = &Error{Code: 40003, Name: "InvalidFilterField", Category: CategoryParam, Retryable: false, Message: "过滤条件的字段错误", MessageEN: "invalid filtering field"}
+	// ErrStatusConflict 40004 操作状态与实际状态不符
+	ErrStatusConflict = &Error{Code: 40004, Name: "StatusConflict", Category: CategoryParam, Retryable: false, Message: "操作状态与实际状态不符", MessageEN: "operation conflicts with current status"}
+	// ErrRateLimited 40100 请求过于频繁
+	ErrRateLimited = &Error{Code: 40100, Name: "RateLimited", Category: CategoryRateLimit, Retryable: true, Message: "请求过于频繁", MessageEN: "too many requests"}
+	// ErrInvalidUser 40101 不合法的接入用户
+	ErrInvalidUser = &Error{Code: 40101, Name: "InvalidUser", Category: CategoryAuth, Retryable: false, Message: "不合法的接入用户", MessageEN: "invalid user"}
+	// ErrAccessTokenExpired 40102 access_token 已过期
+	ErrAccessTokenExpired = &Error{Code: 40102, Name: "AccessTokenExpired", Category: CategoryAuth, Retryable: false, Message: "access_token 已过期", MessageEN: "access token expired"}
+	// ErrRefreshTokenExpired 40103 refresh_token 已过期
+	ErrRefreshTokenExpired = &Error{Code: 40103, Name: "RefreshTokenExpired", Category: CategoryAuth, Retryable: false, Message: "refresh_token 已过期", MessageEN: "refresh token expired"}
+	// ErrAccessTokenEmpty 40104 access_token 为空
+	ErrAccessTokenEmpty = &Error{Code: 40104, Name: "AccessTokenEmpty", Category: CategoryAuth, Retryable: false, Message: "access_token 为空", MessageEN: "access token is empty"}
+	// ErrAccessTokenInvalid 40105 access_token 错误
+	ErrAccessTokenInvalid = &Error{Code: 40105, Name: "AccessTokenInvalid", Category: CategoryAuth, Retryable: false, Message: "access_token 错误", MessageEN: "invalid access token"}
+	// ErrAccountLoginAbnormal 40106 账户登录异常
+	ErrAccountLoginAbnormal = &Error{Code: 40106, Name: "AccountLoginAbnormal", Category: CategoryAuth, Retryable: false, Message: "账户登录异常", MessageEN: "account login abnormal"}
+	// ErrRefreshTokenInvalid 40107 refresh_token 错误
+	ErrRefreshTokenInvalid = &Error{Code: 40107, Name: "RefreshTokenInvalid", Category: CategoryAuth, Retryable: false, Message: "refresh_token 错误", MessageEN: "invalid refresh token"}
+	// ErrInvalidGrantType 40108 授权类型错误
+	ErrInvalidGrantType = &Error{Code: 40108, Name: "InvalidGrantType", Category: CategoryAuth, Retryable: false, Message: "授权类型错误", MessageEN: "invalid grant type"}
+	// ErrAppRateLimited 40110 应用维度接口调用频率超限
+	ErrAppRateLimited = &Error{Code: 40110, Name: "AppRateLimited", Category: CategoryRateLimit, Retryable: true, Message: "应用维度接口调用频率超限", MessageEN: "app request rate limit exceeded"}
+	// ErrRechargeTooSmall 40200 充值金额太少
+	ErrRechargeTooSmall = &Error{Code: 40200, Name: "RechargeTooSmall", Category: CategoryBalance, Retryable: false, Message: "充值金额太少", MessageEN: "recharge amount too small"}
+	// ErrBalanceInsufficient 40201 账户余额不足
+	ErrBalanceInsufficient = &Error{Code: 40201, Name: "BalanceInsufficient", Category: CategoryBalance, Retryable: false, Message: "账户余额不足", MessageEN: "insufficient account balance"}
+	// ErrAdvertiserUnavailable 40300 广告主状态不可用
+	ErrAdvertiserUnavailable = &Error{Code: 40300, Name: "AdvertiserUnavailable", Category: CategoryPermission, Retryable: false, Message: "广告主状态不可用", MessageEN: "advertiser is unavailable"}
+	// ErrAdvertiserBlocked 40301 广告主在黑名单中
+	ErrAdvertiserBlocked = &Error{Code: 40301, Name: "AdvertiserBlocked", Category: CategoryPermission, Retryable: false, Message: "广告主在黑名单中", MessageEN: "advertiser is blocked"}
+	// ErrFileSignatureMismatch 40900 文件签名错误
+	ErrFileSignatureMismatch = &Error{Code: 40900, Name: "FileSignatureMismatch", Category: CategoryParam, Retryable: false, Message: "文件签名错误", MessageEN: "file signature mismatch"}
+	// ErrSystemError 50000 系统错误
+	ErrSystemError = &Error{Code: 50000, Name: "SystemError", Category: CategorySystem, Retryable: true, Message: "系统错误", MessageEN: "system error"}
+	// ErrSystemBusy 51010 系统繁忙，请稍后重试
+	ErrSystemBusy = &Error{Code: 51010, Name: "SystemBusy", Category: CategorySystem, Retryable: true, Message: "系统繁忙，请稍后重试", MessageEN: "system busy"}
+	// ErrDeveloperQuotaExceeded 61002 当前开发者账号日累计调用接口次数超限
+	ErrDeveloperQuotaExceeded = &Error{Code: 61002, Name: "DeveloperQuotaExceeded", Category: CategoryQuota, Retryable: false, Message: "当前开发者账号日累计调用接口次数超限", MessageEN: "developer daily request quota exceeded"}
+	// ErrAdvertiserQuotaExceeded 61003 广告主账户日累计调用接口次数超限
+	ErrAdvertiserQuotaExceeded = &Error{Code: 61003, Name: "AdvertiserQuotaExceeded", Category: CategoryQuota, Retryable: false, Message: "广告主账户日累计调用接口次数超限", MessageEN: "advertiser daily request quota exceeded"}
+)
+
+// catalogue 已收录的返回码
+var catalogue = map[int]*Error{
+	40001: ErrInvalidParam,
+	40002: ErrNoPermission,
+	40003: ErrInvalidFilterField,
+	40004: ErrStatusConflict,
+	40100: ErrRateLimited,
+	40101: ErrInvalidUser,
+	40102: ErrAccessTokenExpired,
+	40103: ErrRefreshTokenExpired,
+	40104: ErrAccessTokenEmpty,
+	40105: ErrAccessTokenInvalid,
+	40106: ErrAccountLoginAbnormal,
+	40107: ErrRefreshTokenInvalid,
+	40108: ErrInvalidGrantType,
+	40110: ErrAppRateLimited,
+	40200: ErrRechargeTooSmall,
+	40201: ErrBalanceInsufficient,
+	40300: ErrAdvertiserUnavailable,
+	40301: ErrAdvertiserBlocked,
+	40900: ErrFileSignatureMismatch,
+	50000: ErrSystemError,
+	51010: ErrSystemBusy,
+	61002: ErrDeveloperQuotaExceeded,
+	61003: ErrAdvertiserQuotaExceeded,
+}
+
+// codes 返回码，按 codes.csv 顺序
+var codes = []int{40001, 40002, 40003, 40004, 40100, 40101, 40102, 40103, 40104, 40105, 40106, 40107, 40108, 40110, 40200, 40201, 40300, 40301, 40900, 50000, 51010, 61002, 61003}
@@ -0,0 +1,11 @@
+// Package errcode 巨量引擎开放平台返回码
+//
+// 返回码目录由 codes.csv 生成 (go generate)，每个返回码对应一个 *Error 哨兵值，可通过 errors.Is 判断接口错误：
+//
+//	if errors.Is(err, errcode.ErrAccessTokenExpired) {
+//		// 刷新 access_token
+//	}
+//	if errcode.IsRetryable(err) {
+//		// 稍后重试
+//	}
+package errcode
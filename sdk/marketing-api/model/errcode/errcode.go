@@ -0,0 +1,173 @@
+package errcode
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+//go:generate go run gen.go
+
+// Category 返回码分类
+type Category string
+
+const (
+	// CategoryParam 参数错误
+	CategoryParam Category = "param"
+	// CategoryPermission 无权限或广告主不可用
+	CategoryPermission Category = "permission"
+	// CategoryAuth 授权失效，需要刷新或重新授权
+	CategoryAuth Category = "auth"
+	// CategoryRateLimit 调用频率超限
+	CategoryRateLimit Category = "rate_limit"
+	// CategoryQuota 日累计调用次数超限
+	CategoryQuota Category = "quota"
+	// CategoryBalance 账户余额相关
+	CategoryBalance Category = "balance"
+	// CategorySystem 开放平台系统错误
+	CategorySystem Category = "system"
+)
+
+// Lang 返回信息语言
+type Lang string
+
+const (
+	// LangZH 中文
+	LangZH Lang = "zh"
+	// LangEN 英文
+	LangEN Lang = "en"
+)
+
+// Error 巨量引擎返回码
+// 可作为 errors.Is 的目标与 model.BaseResponse 比较：errors.Is(err, errcode.ErrAccessTokenExpired)
+type Error struct {
+	// Code 返回码
+	Code int
+	// Name 名称
+	Name string
+	// Category 分类
+	Category Category
+	// Retryable 是否可重试
+	Retryable bool
+	// Message 中文说明
+	Message string
+	// MessageEN 英文说明
+	MessageEN string
+}
+
+// Error implement error interface
+func (e *Error) Error() string {
+	return strconv.Itoa(e.Code) + ":" + e.Message
+}
+
+// ErrorCode 返回码
+func (e *Error) ErrorCode() int {
+	return e.Code
+}
+
+// Is 返回码相同即视为同一错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// IsRetryable 是否为可重试的瞬时错误
+func (e *Error) IsRetryable() bool {
+	return e.Retryable
+}
+
+// IsAuthError 是否为授权失效错误
+func (e *Error) IsAuthError() bool {
+	return e.Category == CategoryAuth
+}
+
+// Localize 返回指定语言的说明
+func (e *Error) Localize(lang Lang) string {
+	if lang == LangEN && e.MessageEN != "" {
+		return e.MessageEN
+	}
+	return e.Message
+}
+
+// 应用维度频控返回码范围，目录中仅收录 40110
+const (
+	appRateLimitFirst = 40110
+	appRateLimitLast  = 40119
+)
+
+// Lookup 查找返回码，应用维度频控 40110-40119 均返回 ErrAppRateLimited；
+// 非业务返回码的 HTTP 429/5xx 状态视为可重试
+func Lookup(code int) (*Error, bool) {
+	if e, ok := catalogue[code]; ok {
+		return e, true
+	}
+	switch {
+	case code > appRateLimitFirst && code <= appRateLimitLast:
+		return ErrAppRateLimited, true
+	case code == http.StatusTooManyRequests:
+		return &Error{Code: code, Name: "HTTPTooManyRequests", Category: CategoryRateLimit, Retryable: true, Message: http.StatusText(code), MessageEN: http.StatusText(code)}, true
+	case code >= http.StatusInternalServerError && code < 600:
+		return &Error{Code: code, Name: "HTTPServerError", Category: CategorySystem, Retryable: true, Message: http.StatusText(code), MessageEN: http.StatusText(code)}, true
+	}
+	return nil, false
+}
+
+// Codes 返回所有已收录的返回码
+func Codes() []*Error {
+	ret := make([]*Error, 0, len(catalogue))
+	for _, code := range codes {
+		ret = append(ret, catalogue[code])
+	}
+	return ret
+}
+
+// CategoryCodes 返回 Lookup 归入指定分类的所有返回码，包括目录外的应用维度频控返回码与 HTTP 状态码
+func CategoryCodes(category Category) []int {
+	candidates := slices.Clone(codes)
+	for code := appRateLimitFirst + 1; code <= appRateLimitLast; code++ {
+		candidates = append(candidates, code)
+	}
+	candidates = append(candidates, http.StatusTooManyRequests)
+	for code := http.StatusInternalServerError; code < 600; code++ {
+		candidates = append(candidates, code)
+	}
+
+	var ret []int
+	for _, code := range candidates {
+		if e, ok := Lookup(code); ok && e.Category == category {
+			ret = append(ret, code)
+		}
+	}
+	return ret
+}
+
+// From 从错误链中提取返回码并查找对应的 Error
+func From(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var coder interface{ ErrorCode() int }
+	if !errors.As(err, &coder) {
+		return nil, false
+	}
+	return Lookup(coder.ErrorCode())
+}
+
+// IsRetryable 错误是否为可重试的瞬时错误 (频控、系统繁忙等)
+func IsRetryable(err error) bool {
+	e, ok := From(err)
+	return ok && e.IsRetryable()
+}
+
+// IsAuthError 错误是否为授权失效 (access_token/refresh_token 过期或错误)
+func IsAuthError(err error) bool {
+	e, ok := From(err)
+	return ok && e.IsAuthError()
+}
+
+// IsCategory 错误是否属于指定分类
+func IsCategory(err error, category Category) bool {
+	e, ok := From(err)
+	return ok && e.Category == category
+}
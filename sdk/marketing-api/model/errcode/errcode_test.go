@@ -0,0 +1,98 @@
+package errcode_test
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/bububa/oceanengine/marketing-api/api/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/model"
+	"github.com/bububa/oceanengine/marketing-api/model/errcode"
+	projectModel "github.com/bububa/oceanengine/marketing-api/model/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/testing/oetest"
+)
+
+func TestErrorsIs(t *testing.T) {
+	err := fmt.Errorf("list failed: %w", model.BaseResponse{Code: 40102, Message: "access token expired"})
+	if !errors.Is(err, errcode.ErrAccessTokenExpired) || errors.Is(err, errcode.ErrAccessTokenInvalid) {
+		t.Errorf("errors.Is(%v) mismatch", err)
+	}
+	if !errcode.IsAuthError(err) || errcode.IsRetryable(err) {
+		t.Errorf("IsAuthError/IsRetryable(%v) mismatch", err)
+	}
+	e, ok := errcode.From(err)
+	if !ok || e != errcode.ErrAccessTokenExpired || e.Localize(errcode.LangEN) != "access token expired" {
+		t.Errorf("From(%v) = %+v", err, e)
+	}
+
+	tests := []struct {
+		code      int
+		retryable bool
+		known     bool
+	}{
+		{40100, true, true},
+		{40115, true, true},
+		{51010, true, true},
+		{503, true, true},
+		{40201, false, true},
+		{61002, false, true},
+		{12345, false, false},
+	}
+	for _, tt := range tests {
+		resp := model.BaseResponse{Code: tt.code}
+		_, known := errcode.Lookup(tt.code)
+		if resp.IsRetryable() != tt.retryable || known != tt.known {
+			t.Errorf("code %d: retryable = %v, known = %v", tt.code, resp.IsRetryable(), known)
+		}
+	}
+	if errcode.IsRetryable(nil) || errcode.IsRetryable(errors.New("x")) {
+		t.Error("IsRetryable() on non api error")
+	}
+}
+
+func TestCategoryCodes(t *testing.T) {
+	want := []int{40100, 40110, 40111, 40112, 40113, 40114, 40115, 40116, 40117, 40118, 40119, http.StatusTooManyRequests}
+	if got := errcode.CategoryCodes(errcode.CategoryRateLimit); !slices.Equal(got, want) {
+		t.Errorf("CategoryCodes(rate_limit) = %v", got)
+	}
+	if got := errcode.CategoryCodes(errcode.CategoryQuota); !slices.Equal(got, []int{61002, 61003}) {
+		t.Errorf("CategoryCodes(quota) = %v", got)
+	}
+}
+
+func TestCodes(t *testing.T) {
+	f, err := os.Open("codes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// codes_gen.go 需要与 codes.csv 保持一致，修改 codes.csv 后执行 go generate
+	codes := errcode.Codes()
+	if len(codes) != len(records)-1 {
+		t.Fatalf("Codes() = %d entries, codes.csv = %d, run go generate", len(codes), len(records)-1)
+	}
+	for i, e := range codes {
+		if r := records[i+1]; fmt.Sprint(e.Code) != r[0] || e.Name != r[1] || e.Message != r[4] {
+			t.Errorf("Codes()[%d] = %+v, codes.csv = %v", i, e, r)
+		}
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := oetest.NewServer(t)
+	srv.Handle("v3.0/project/list").Fail(40002, "没有权限")
+
+	_, err := project.List(context.Background(), srv.SDKClient(1, "secret"), "token", &projectModel.ListRequest{AdvertiserID: 1})
+	if !errors.Is(err, errcode.ErrNoPermission) || !errcode.IsCategory(err, errcode.CategoryPermission) {
+		t.Errorf("err = %v", err)
+	}
+}
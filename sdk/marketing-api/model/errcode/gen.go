@@ -0,0 +1,83 @@
+//go:build ignore
+
+// gen 根据 codes.csv 生成 codes_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	f, err := os.Open("codes.csv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	categories := map[string]string{
+		"param":      "CategoryParam",
+		"permission": "CategoryPermission",
+		"auth":       "CategoryAuth",
+		"rate_limit": "CategoryRateLimit",
+		"quota":      "CategoryQuota",
+		"balance":    "CategoryBalance",
+		"system":     "CategorySystem",
+	}
+
+	var vars, entries, codes bytes.Buffer
+	for i, r := range records[1:] {
+		if len(r) != 6 {
+			log.Fatalf("codes.csv line %d: expect 6 fields, got %d", i+2, len(r))
+		}
+		code, err := strconv.Atoi(r[0])
+		if err != nil {
+			log.Fatalf("codes.csv line %d: %v", i+2, err)
+		}
+		category, ok := categories[r[2]]
+		if !ok {
+			log.Fatalf("codes.csv line %d: unknown category %s", i+2, r[2])
+		}
+		retryable, err := strconv.ParseBool(r[3])
+		if err != nil {
+			log.Fatalf("codes.csv line %d: %v", i+2, err)
+		}
+		name := r[1]
+		fmt.Fprintf(&vars, "\t// Err%s %d %s\n", name, code, r[4])
+		fmt.Fprintf(&vars, "\tErr%s = &Error{Code: %d, Name: %q, Category: %s, Retryable: %t, Message: %q, MessageEN: %q}\n",
+			name, code, name, category, retryable, r[4], r[5])
+		fmt.Fprintf(&entries, "\t%d: Err%s,\n", code, name)
+		fmt.Fprintf(&codes, "%d, ", code)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go from codes.csv; DO NOT EDIT.\n\n")
+	buf.WriteString("package errcode\n\n")
+	buf.WriteString("var (\n")
+	buf.Write(vars.Bytes())
+	buf.WriteString(")\n\n")
+	buf.WriteString("// catalogue 已收录的返回码\n")
+	buf.WriteString("var catalogue = map[int]*Error{\n")
+	buf.Write(entries.Bytes())
+	buf.WriteString("}\n\n")
+	buf.WriteString("// codes 返回码，按 codes.csv 顺序\n")
+	fmt.Fprintf(&buf, "var codes = []int{%s}\n", strings.TrimSuffix(codes.String(), ", "))
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("codes_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
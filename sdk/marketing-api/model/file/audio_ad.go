@@ -17,6 +17,7 @@ type AudioAdRequest struct {
 	UploadType enum.UploadType `json:"upload_type,omitempty"`
 	// AudioSignature 音频的md5值(用于服务端校验)
 	// upload_type为UPLOAD_BY_FILE时必填
+	// 为空时上传过程中同步计算
 	AudioSignature string `json:"audio_signature,omitempty"`
 	// AudioFile 音频文件，upload_type为UPLOAD_BY_FILE时必填
 	// 允许格式：m4a、mp3
@@ -45,14 +46,7 @@ func (r AudioAdRequest) Encode() []model.UploadField {
 		if filename == "" {
 			filename = "file"
 		}
-		fields = append(fields, model.UploadField{
-			Key:   "audio_signature",
-			Value: r.AudioSignature,
-		}, model.UploadField{
-			Key:    "audio_file",
-			Value:  filename,
-			Reader: r.AudioFile.Reader,
-		})
+		fields = append(fields, model.FileField(r.AudioFile, "audio_file", filename, "audio_signature", r.AudioSignature)...)
 	}
 	if r.AudioURL != "" {
 		fields = append(fields, model.UploadField{
@@ -13,7 +13,7 @@ type ImageAdRequest struct {
 	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
 	// UploadType 图片上传方式; 默认值：UPLOAD_BY_FILE; 允许值：UPLOAD_BY_FILE、UPLOAD_BY_URL
 	UploadType enum.UploadType `json:"upload_type,omitempty"`
-	// ImageSignature 图片的md5值(用于服务端校验)，upload_type为UPLOAD_BY_FILE必填
+	// ImageSignature 图片的md5值(用于服务端校验)，upload_type为UPLOAD_BY_FILE必填；为空时上传过程中同步计算
 	ImageSignature string `json:"image_signature,omitempty"`
 	// ImageFile 图片文件,upload_type为UPLOAD_BY_FILE必填; 格式: jpg、jpeg、png、bmp、gif, 大小1.5M内
 	ImageFile *model.UploadField `json:"image_file,omitempty"`
@@ -46,14 +46,7 @@ func (r ImageAdRequest) Encode() []model.UploadField {
 		if filename == "" {
 			filename = "file"
 		}
-		fields = append(fields, model.UploadField{
-			Key:   "image_signature",
-			Value: r.ImageSignature,
-		}, model.UploadField{
-			Key:    "image_file",
-			Value:  filename,
-			Reader: r.ImageFile.Reader,
-		})
+		fields = append(fields, model.FileField(r.ImageFile, "image_file", filename, "image_signature", r.ImageSignature)...)
 	}
 	if r.Filename != "" {
 		fields = append(fields, model.UploadField{
@@ -13,7 +13,7 @@ type VideoAdRequest struct {
 	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
 	// UploadType 图片上传方式; 默认值：UPLOAD_BY_FILE; 允许值：UPLOAD_BY_FILE、UPLOAD_BY_URL
 	UploadType enum.UploadType `json:"upload_type,omitempty"`
-	// VideoSignature 视频的md5值(用于服务端校验)
+	// VideoSignature 视频的md5值(用于服务端校验)；为空时上传过程中同步计算
 	VideoSignature string `json:"video_signature,omitempty"`
 	// VideoFile 视频文件; 允许格式：mp4、mpeg、3gp、avi（10s超时限制）
 	VideoFile *model.UploadField `json:"video_file,omitempty"`
@@ -50,14 +50,7 @@ func (r VideoAdRequest) Encode() []model.UploadField {
 		if filename == "" {
 			filename = "file"
 		}
-		fields = append(fields, model.UploadField{
-			Key:   "video_signature",
-			Value: r.VideoSignature,
-		}, model.UploadField{
-			Key:    "video_file",
-			Value:  filename,
-			Reader: r.VideoFile.Reader,
-		})
+		fields = append(fields, model.FileField(r.VideoFile, "video_file", filename, "video_signature", r.VideoSignature)...)
 	}
 	if r.Filename != "" {
 		fields = append(fields, model.UploadField{
@@ -5,29 +5,30 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum/local"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // CreateRequest 创建项目 API Request
 type CreateRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// Name 项目名称，长度是1-50个字（两个英文字符占1个字）
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" validate:"required,max=50"`
 	// MarketingGoal 营销场景，允许值：
 	// LIVE 直播
 	// VIDEO_IMAGE 短视频/图文
-	MarketingGoal local.MarketingGoal `json:"marketing_goal,omitempty"`
+	MarketingGoal local.MarketingGoal `json:"marketing_goal,omitempty" validate:"required,oneof=LIVE VIDEO_IMAGE"`
 	// LocalDeliveryScene 推广目的，允许值：
 	// CONTENT_HEAT 内容加热
 	// POI_RECOMMEND 门店引流
 	// 当marketing_goal =LIVE 直播时，不支持传入POI_RECOMMEND 门店引流
 	// PRODUCT_PAY 团购成交
-	LocalDeliveryScene local.LocalDeliveryScene `json:"local_delivery_scene,omitempty"`
+	LocalDeliveryScene local.LocalDeliveryScene `json:"local_delivery_scene,omitempty" validate:"required"`
 	// AdType 广告类型，允许值：
 	// GENERAL 通投广告
 	// SEARCHING 搜索广告
 	// 当marketing_goal=VIDEO_IMAGE 短视频/图文 && local_delivery_scene=CONTENT_HEAT 内容加热 时，不支持传入SEARCHING
-	AdType local.AdType `json:"ad_type,omitempty"`
+	AdType local.AdType `json:"ad_type,omitempty" validate:"oneof=GENERAL SEARCHING"`
 	// DeliveryGoal 投放内容，允许值：
 	// POI 门店
 	// PRODUCT 商品
@@ -85,50 +86,50 @@ type CreateRequest struct {
 	// START_TO_END 设置开始结束时间
 	// FIXED_TIME 固定时长
 	// 仅营销场景=LIVE直播时，该枚举值有效，否则传入报错
-	ScheduleType local.ScheduleType `json:"schedule_type,omitempty"`
+	ScheduleType local.ScheduleType `json:"schedule_type,omitempty" validate:"oneof=FROM_NOW_ON START_TO_END FIXED_TIME"`
 	// ScheduleFixedSeconds 直播固定投放时长（单位：秒）
 	// 填写说明：
 	// 仅当营销场景=LIVE && schedule_type=FIXED_TIME 固定时长时有效且必填，其他情况传入报错
 	// 输入值需不小于1800，且为1800的整数倍（即半个小时为最小粒度）
-	ScheduleFixedSeconds int64 `json:"schedule_fixed_seconds,omitempty"`
+	ScheduleFixedSeconds int64 `json:"schedule_fixed_seconds,omitempty" validate:"min=1800"`
 	// StartTime 开始投放时间，精确到天，例如：2017-01-01
 	// 当schedule_type=START_TO_END时，有效且必填
 	// 广告投放起始时间不允许修改
 	// 开始时间不得大于结束时间，且开始时间内不得小于今天
-	StartTime string `json:"start_time,omitempty"`
+	StartTime string `json:"start_time,omitempty" validate:"datetime=2006-01-02"`
 	// EndTime 结束投放时间，精确到天，例如：2017-01-01
 	// 当schedule_type=START_TO_END时，有效且必填
 	// 结束时间不得小于开始时间
-	EndTime string `json:"end_time,omitempty"`
+	EndTime string `json:"end_time,omitempty" validate:"datetime=2006-01-02"`
 	// ScheduleTime 投放时段，默认全时段投放。格式是48*7位字符串，且都是0或1，也就是以半个小时为最小粒度，周一至周日每天分为48个区段，0为不投放，1为投放，不传、全传0、全传1均代表全时段投放。
 	// 当schedule_type=FIXED_TIME固定时长时，该字段无效，传入会报错
 	// 例如：000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000000000000000000000000001111000000000000000000000，则投放时段为周一到周日的11:30~13:30
-	ScheduleTime string `json:"schedule_time,omitempty"`
+	ScheduleTime string `json:"schedule_time,omitempty" validate:"len=336"`
 	// BidType 出价方式，允许值：
 	// MANUAL 手动出价
 	// SMART 智能出价
 	// 填写说明：
 	// 优化目标= SHOW 展示量 时，仅支持MANUAL 手动出价
 	// 营销目标=LIVE && 推广目的= CONTENT_HEAT内容加热 && 优化目标=LIVE_ENGAGEMENT时，仅支持SMART 智能出价
-	BidType local.BidType `json:"bid_type,omitempty"`
+	BidType local.BidType `json:"bid_type,omitempty" validate:"oneof=MANUAL SMART"`
 	// Bid 出价，当出价方式=MANUAL 手动出价时有效
 	// 出价单位：分
 	// 展示量取值范围为[400,10000]，其他场景取值范围为[1, 1000000]
 	// 【展示量】：分/千次曝光
-	Bid int64 `json:"bid,omitempty"`
+	Bid int64 `json:"bid,omitempty" validate:"min=1,max=1000000"`
 	// BudgetMode 预算模式设置，允许值：
 	// BUDGET_MODE_DAY 日预算
 	// BUDGET_MODE_TOTAL 总预算
 	// 填写说明：
 	// 当sceduale_type=FIXED_TIME固定时长时，仅支持BUDGET_MODE_TOTAL总预算
 	// 当sceduale_type=FROM_NOW_ON或START_TO_END时，仅支持BUDGET_MODE_DAY日预算
-	BudgetMode enum.BudgetMode `json:"budget_mode,omitempty"`
+	BudgetMode enum.BudgetMode `json:"budget_mode,omitempty" validate:"oneof=BUDGET_MODE_DAY BUDGET_MODE_TOTAL"`
 	// Budget 项目预算，单位为：分
 	// 填写限制：
 	// 当budget_mode=BUDGET_MODE_DAY 日预算 && bid_type=SMART 智能出价时，取值范围为[10000, 999999999]
 	// 当budget_mode=BUDGET_MODE_DAY 日预算 && bid_type=MANUAL 手动出价时，取值范围为[30000, 999999999]
 	// 当budget_mode=BUDGET_MODE_TOTAL 总预算时，取值范围为[30000, 999999999]
-	Budget int64 `json:"budget,omitempty"`
+	Budget int64 `json:"budget,omitempty" validate:"min=10000,max=999999999"`
 	// IsSetPeakBudget 高峰日预算设置，仅当营销场景为短视频，且推广目的=团购成交或门店引流时，该字段有效且必填，允许值：
 	// TURE 开启
 	// FALSE 关闭
@@ -161,7 +162,7 @@ type CreateRequest struct {
 	// HighBudgetRate 高峰日预算上调比例，单位为百分比，例如：传“40”表示高峰日时预算上调“40%”
 	// 当is_set_peak_budget = TURE开启高峰日预算时有效且必填
 	// 区间限制： 20～200
-	HighBudgetRate int `json:"high_budget_rate,omitempty"`
+	HighBudgetRate int `json:"high_budget_rate,omitempty" validate:"min=20,max=200"`
 }
 
 // Encode implements PostRequest interface
@@ -169,6 +170,41 @@ func (r CreateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
 
+// Validate implement model.Validator interface
+func (r CreateRequest) Validate() error {
+	errs := validate.Check(r)
+	if r.MarketingGoal == local.MarketingGoal_LIVE && r.AwemeID == "" {
+		errs = errs.Add("aweme_id", "required", "marketing_goal=LIVE 时不能为空")
+	}
+	if r.ScheduleType == local.ScheduleType_START_TO_END {
+		if r.StartTime == "" {
+			errs = errs.Add("start_time", "required", "schedule_type=START_TO_END 时不能为空")
+		}
+		if r.EndTime == "" {
+			errs = errs.Add("end_time", "required", "schedule_type=START_TO_END 时不能为空")
+		}
+	}
+	if r.StartTime != "" && r.EndTime != "" && r.EndTime < r.StartTime {
+		errs = errs.Add("end_time", "gtefield", "不能早于 start_time")
+	}
+	errs = checkSchedule(errs, r.ScheduleType, r.ScheduleFixedSeconds)
+	if r.Budget > 0 && r.Budget < 30000 && (r.BudgetMode == enum.BUDGET_MODE_TOTAL || r.BidType == local.BidType_MANUAL) {
+		errs = errs.Add("budget", "min", "总预算或手动出价时不能小于 30000")
+	}
+	return errs.Err()
+}
+
+// checkSchedule 校验固定投放时长
+func checkSchedule(errs validate.Errors, scheduleType local.ScheduleType, fixedSeconds int64) validate.Errors {
+	if scheduleType == local.ScheduleType_FIXED_TIME && fixedSeconds == 0 {
+		return errs.Add("schedule_fixed_seconds", "required", "schedule_type=FIXED_TIME 时不能为空")
+	}
+	if fixedSeconds%1800 != 0 {
+		return errs.Add("schedule_fixed_seconds", "multiple", "必须为 1800 的整数倍")
+	}
+	return errs
+}
+
 // CreateResponse 创建项目 API Response
 type CreateResponse struct {
 	model.BaseResponse
@@ -6,23 +6,24 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum/local"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // ListRequest 获取项目列表 API Request
 type ListRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// Filtering 过滤字段
 	Filtering *ListRequestFilter `json:"filtering,omitempty"`
 	// Page 页码，默认值1
 	Page int `json:"page,omitempty"`
 	// PageSize 页面大小，最大值100，默认值20
-	PageSize int `json:"page_size,omitempty"`
+	PageSize int `json:"page_size,omitempty" validate:"max=100"`
 }
 
 type ListRequestFilter struct {
 	// ProjectIDs 项目IDs筛选，最多100个
-	ProjectIDs []uint64 `json:"project_ids,omitempty"`
+	ProjectIDs []uint64 `json:"project_ids,omitempty" validate:"max=100"`
 	// ProjectStatusFirst 项目一级状态筛选，允许值：
 	// PROJECT_STATUS_ALL 不限（包含已删除）
 	// PROJECT_STATUS_DELETE 已删除
@@ -40,9 +41,9 @@ type ListRequestFilter struct {
 	// 仅当status_first = PROJECT_STATUS_DISABLE 未投放时传入有效
 	ProjectStatusSecond local.ProjectStatus `json:"project_status_second,omitempty"`
 	// ShopIDs 按门店IDs筛选，单次限制最多10个
-	ShopIDs []uint64 `json:"shop_ids,omitempty"`
+	ShopIDs []uint64 `json:"shop_ids,omitempty" validate:"max=10"`
 	// ProductIDs 按商品IDs筛选，单次限制最多10个
-	ProductIDs []uint64 `json:"product_ids,omitempty"`
+	ProductIDs []uint64 `json:"product_ids,omitempty" validate:"max=10"`
 	// LocalDeliveryScene 推广目的筛选，默认不限，允许值：
 	// ALL 不限
 	// CONTENT_HEAT 内容加热
@@ -98,6 +99,11 @@ func (r ListRequest) Encode() string {
 	return ret
 }
 
+// Validate implement model.Validator interface
+func (r ListRequest) Validate() error {
+	return validate.Struct(r)
+}
+
 // ListResponse 获取项目列表 API Response
 type ListResponse struct {
 	Data *ListResult `json:"data,omitempty"`
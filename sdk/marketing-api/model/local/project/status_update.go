@@ -5,26 +5,27 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // StatusUpdateRequest 批量更新项目状态 API Request
 type StatusUpdateRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// Data 批量更新项目状态，包含项目ID和目标操作，list长度限制1～50
-	Data []StatusUpdateItem `json:"data,omitempty"`
+	Data []StatusUpdateItem `json:"data,omitempty" validate:"required,max=50"`
 }
 
 // StatusUpdateItem 批量更新项目
 type StatusUpdateItem struct {
 	// ProjectID 项目id
-	ProjectID uint64 `json:"project_id,omitempty"`
+	ProjectID uint64 `json:"project_id,omitempty" validate:"required"`
 	// OptStatus 目标操作
 	// 目标操作，可选值:
 	// ENABLE 启用项目
 	// PAUSED 暂停项目
 	// 对于删除的广告项目不可进行任何操作，否则会报错
-	OptStatus string `json:"opt_status,omitempty"`
+	OptStatus string `json:"opt_status,omitempty" validate:"required,oneof=ENABLE PAUSED"`
 }
 
 // Encode implements PostRequest interface
@@ -32,6 +33,11 @@ func (r StatusUpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
 
+// Validate implement model.Validator interface
+func (r StatusUpdateRequest) Validate() error {
+	return validate.Struct(r)
+}
+
 // StatusUpdateResponse 批量更新项目状态 API Response
 type StatusUpdateResponse struct {
 	model.BaseResponse
@@ -4,16 +4,17 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // CreateRequest 创建广告 API Request
 type CreateRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// ProjectID 项目ID
-	ProjectID uint64 `json:"project_id,omitempty"`
+	ProjectID uint64 `json:"project_id,omitempty" validate:"required"`
 	// Name 广告名称，长度是1-50个字（两个英文字符占1个字）
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" validate:"required,max=50"`
 	// EnableGraphicDelivery 是否开启团购卡
 	// 仅当项目marketing_goal = VIDEO_IMAGE 短视频/图文 && external_action = OTO_PAY 团购购买/POI_RECOMMEND 门店引流时 ，有效且必传
 	EnableGraphicDelivery bool `json:"enable_graphic_delivery,omitempty"`
@@ -24,12 +25,12 @@ type CreateRequest struct {
 	// ALWAYS_VISIBLE 主页始终可见
 	// HIDE_VIDEO_ON_HP 仅单次展示可见（默认值）
 	// 仅针对素材库和上传视频生效
-	VideoHpVisibility enum.VideoHpVisibility `json:"video_hp_visibility,omitempty"`
+	VideoHpVisibility enum.VideoHpVisibility `json:"video_hp_visibility,omitempty" validate:"oneof=ALWAYS_VISIBLE HIDE_VIDEO_ON_HP"`
 	// LiveMaterialType 直播素材类型设置 可选值:
 	// LIVE 直播间画面
 	// VIDEO 短视频
 	// 注意：当项目营销场景为直播间时，有效且必传。直播场景下仅支持设置其一，当选择直播间画面时，不支持传入customer_material_list
-	LiveMaterialType enum.MarketingGoal `json:"live_material_type,omitempty"`
+	LiveMaterialType enum.MarketingGoal `json:"live_material_type,omitempty" validate:"oneof=LIVE VIDEO"`
 	// CustomerMaterialList 视频素材列表
 	// 推直播：live_material_type=VIDEO短视频时必填，live_material_type=LIVE时不支持传入，传入会报错；
 	// 推短视频：未开启团购卡时，视频素材必传；开启团购卡时，可根据是否需要推广短视频素材选择传入
@@ -41,6 +42,22 @@ func (r CreateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
 
+// Validate implement model.Validator interface
+func (r CreateRequest) Validate() error {
+	errs := validate.Check(r)
+	switch r.LiveMaterialType {
+	case enum.MarketingGoal_LIVE:
+		if len(r.CustomerMaterialList) > 0 {
+			errs = errs.Add("customer_material_list", "excluded", "live_material_type=LIVE 时不支持传入")
+		}
+	case enum.MarketingGoal_VIDEO:
+		if len(r.CustomerMaterialList) == 0 {
+			errs = errs.Add("customer_material_list", "required", "live_material_type=VIDEO 时不能为空")
+		}
+	}
+	return errs.Err()
+}
+
 // CreateResponse 创建广告 API Response
 type CreateResponse struct {
 	model.BaseResponse
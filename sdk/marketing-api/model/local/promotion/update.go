@@ -3,16 +3,17 @@ package promotion
 import (
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // UpdateRequest 更新广告 API Request
 type UpdateRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// PromotionID 广告ID
-	PromotionID uint64 `json:"promotion_id,omitempty"`
+	PromotionID uint64 `json:"promotion_id,omitempty" validate:"required"`
 	// Name 广告名称，长度是1-50个字（两个英文字符占1个字）
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" validate:"max=50"`
 	// AwemeID 用于推广的抖音号id
 	// 当营销场景为短视频，且选择素材库和上传视频投广时，该字段必填
 	AwemeID string `json:"aweme_id,omitempty"`
@@ -20,7 +21,7 @@ type UpdateRequest struct {
 	// ALWAYS_VISIBLE 主页始终可见
 	// HIDE_VIDEO_ON_HP 仅单次展示可见（默认值）
 	// 仅针对素材库和上传视频生效
-	VideoHpVisibility enum.VideoHpVisibility `json:"video_hp_visibility,omitempty"`
+	VideoHpVisibility enum.VideoHpVisibility `json:"video_hp_visibility,omitempty" validate:"oneof=ALWAYS_VISIBLE HIDE_VIDEO_ON_HP"`
 	// CustomerMaterialList 视频素材列表
 	// 推直播：live_material_type=VIDEO短视频时必填，live_material_type=LIVE时不支持传入，传入会报错；
 	// 推短视频：未开启团购卡时，视频素材必传；开启团购卡时，可根据是否需要推广短视频素材选择传入
@@ -31,3 +32,8 @@ type UpdateRequest struct {
 func (r UpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
+
+// Validate implement model.Validator interface
+func (r UpdateRequest) Validate() error {
+	return validate.Struct(r)
+}
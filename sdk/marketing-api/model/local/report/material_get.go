@@ -7,27 +7,28 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum/local"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // MaterialGetRequest 获取素材数据 API Request
 type MaterialGetRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// TimeGranularity 时间粒度，允许值：
 	// TIME_GRANULARITY_DAILY 天维度（默认值）
 	// TIME_GRANULARITY_HOURLY 小时维度
 	// TIME_GRANULARITY_TOTAL 汇总
 	TimeGranularity enum.TimeGranularity `json:"time_granularity,omitempty"`
 	// StartDate 查询起始日期，格式：yyyy-mm-dd
-	StartDate string `json:"start_date,omitempty"`
+	StartDate string `json:"start_date,omitempty" validate:"required,datetime=2006-01-02"`
 	// EndDate 查询结束日期，格式：yyyy-mm-dd
 	// 当time_granularity = TIME_GRANULARITY_DAILY/TIME_GRANULARITY_TOTAL时，时间跨度不能超过365天
 	// 当time_granularity = TIME_GRANULARITY_HOURLY时，时间跨度不能超过7天
-	EndDate string `json:"end_date,omitempty"`
+	EndDate string `json:"end_date,omitempty" validate:"required,datetime=2006-01-02"`
 	// OrderType 排序方式，允许值：
 	// ASC 升序（默认值）
 	// DESC 降序
-	OrderType enum.OrderType `json:"order_type,omitempty"`
+	OrderType enum.OrderType `json:"order_type,omitempty" validate:"oneof=ASC DESC"`
 	// OrderField 排序字段，允许值可参考应答返回数据指标
 	OrderField string `json:"order_field,omitempty"`
 	// Metrics 指标集，允许值可参考应答返回数据指标
@@ -37,16 +38,16 @@ type MaterialGetRequest struct {
 	// Page 页码，默认值：1
 	Page int `json:"page,omitempty"`
 	// PageSize 页面大小，允许值：10（默认值）、20、50、100
-	PageSize int `json:"page_size,omitempty"`
+	PageSize int `json:"page_size,omitempty" validate:"max=100"`
 }
 
 type MaterialGetFilter struct {
-  // MaterialIDs 素材ID
-  MaterialIDs []uint64 `json:"material_ids,omitempty"`
-  // MaterialType 素材类型，允许值：
-// CASURAL 图文
-// VIDEO 视频
-  MaterialType enum.MaterialMode `json:"material_type,omitempty"`
+	// MaterialIDs 素材ID
+	MaterialIDs []uint64 `json:"material_ids,omitempty"`
+	// MaterialType 素材类型，允许值：
+	// CASURAL 图文
+	// VIDEO 视频
+	MaterialType enum.MaterialMode `json:"material_type,omitempty"`
 	// CampaignType 广告类型，允许值：
 	// GENERAL 通投广告
 	// SEARCHING 线索广告
@@ -83,6 +84,11 @@ func (r MaterialGetRequest) Encode() string {
 	return ret
 }
 
+// Validate implement model.Validator interface
+func (r MaterialGetRequest) Validate() error {
+	return checkDateRange(validate.Check(r), r.TimeGranularity, r.StartDate, r.EndDate).Err()
+}
+
 // MaterialGetResponse 获取素材数据 API Response
 type MaterialGetResponse struct {
 	model.BaseResponse
@@ -92,6 +98,6 @@ type MaterialGetResponse struct {
 type MaterialGetResult struct {
 	// PageInfo 分页信息
 	PageInfo *model.PageInfo `json:"page_info,omitempty"`
-	// MaterialList 
+	// MaterialList
 	MaterialList []Report `json:"material_list,omitempty"`
 }
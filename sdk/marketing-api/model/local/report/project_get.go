@@ -7,27 +7,28 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum/local"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // ProjectGetRequest 获取项目数据 API Request
 type ProjectGetRequest struct {
 	// LocalAccountID 本地推广告账户ID
-	LocalAccountID uint64 `json:"local_account_id,omitempty"`
+	LocalAccountID uint64 `json:"local_account_id,omitempty" validate:"required"`
 	// TimeGranularity 时间粒度，允许值：
 	// TIME_GRANULARITY_DAILY 天维度（默认值）
 	// TIME_GRANULARITY_HOURLY 小时维度
 	// TIME_GRANULARITY_TOTAL 汇总
 	TimeGranularity enum.TimeGranularity `json:"time_granularity,omitempty"`
 	// StartDate 查询起始日期，格式：yyyy-mm-dd
-	StartDate string `json:"start_date,omitempty"`
+	StartDate string `json:"start_date,omitempty" validate:"required,datetime=2006-01-02"`
 	// EndDate 查询结束日期，格式：yyyy-mm-dd
 	// 当time_granularity = TIME_GRANULARITY_DAILY/TIME_GRANULARITY_TOTAL时，时间跨度不能超过365天
 	// 当time_granularity = TIME_GRANULARITY_HOURLY时，时间跨度不能超过7天
-	EndDate string `json:"end_date,omitempty"`
+	EndDate string `json:"end_date,omitempty" validate:"required,datetime=2006-01-02"`
 	// OrderType 排序方式，允许值：
 	// ASC 升序（默认值）
 	// DESC 降序
-	OrderType enum.OrderType `json:"order_type,omitempty"`
+	OrderType enum.OrderType `json:"order_type,omitempty" validate:"oneof=ASC DESC"`
 	// OrderField 排序字段，允许值可参考应答返回数据指标
 	OrderField string `json:"order_field,omitempty"`
 	// Metrics 指标集，允许值可参考应答返回数据指标
@@ -37,7 +38,7 @@ type ProjectGetRequest struct {
 	// Page 页码，默认值：1
 	Page int `json:"page,omitempty"`
 	// PageSize 页面大小，允许值：10（默认值）、20、50、100
-	PageSize int `json:"page_size,omitempty"`
+	PageSize int `json:"page_size,omitempty" validate:"max=100"`
 }
 
 type ProjectGetFilter struct {
@@ -91,6 +92,11 @@ func (r ProjectGetRequest) Encode() string {
 	return ret
 }
 
+// Validate implement model.Validator interface
+func (r ProjectGetRequest) Validate() error {
+	return checkDateRange(validate.Check(r), r.TimeGranularity, r.StartDate, r.EndDate).Err()
+}
+
 // ProjectGetResponse 获取项目数据 API Response
 type ProjectGetResponse struct {
 	model.BaseResponse
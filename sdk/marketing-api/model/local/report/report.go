@@ -1,5 +1,12 @@
 package report
 
+import (
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/enum"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
+)
+
 // Report 报表
 type Report struct {
 	// ProjectID 项目ID
@@ -117,3 +124,20 @@ type Report struct {
 	// LubanLiveShareCnt 直播间分享次数
 	LubanLiveShareCnt int64 `json:"luban_live_share_cnt,omitempty"`
 }
+
+// checkDateRange 校验查询日期跨度：小时维度不超过 7 天，其他不超过 365 天
+func checkDateRange(errs validate.Errors, granularity enum.TimeGranularity, startDate string, endDate string) validate.Errors {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return errs
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return errs
+	}
+	maxDays := 365
+	if granularity == enum.TIME_GRANULARITY_HOURLY {
+		maxDays = 7
+	}
+	return errs.DateRange("start_date", start, "end_date", end, maxDays)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // GetRequest 获取数据报表API Request
@@ -16,9 +17,9 @@ type GetRequest struct {
 	// AdvertiserID 广告主ID
 	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
 	// StartDate 起始日期,格式YYYY-MM-DD,只支持查询2016-10-26及以后的日期
-	StartDate time.Time `json:"start_date,omitempty"`
+	StartDate time.Time `json:"start_date,omitempty" validate:"required"`
 	// EndDate 结束日期,格式YYYY-MM-DD,只支持查询2016-10-26及以后的日期，时间跨度不能超过30天
-	EndDate time.Time `json:"end_date,omitempty"`
+	EndDate time.Time `json:"end_date,omitempty" validate:"required"`
 	// Fields 指定需要的指标名称
 	Fields []string `json:"fields,omitempty"`
 	// GroupBy 分组条件默认为 STAT_GROUP_BY_FIELD_STAT_TIME
@@ -32,7 +33,7 @@ type GetRequest struct {
 	// Page 页码；默认值: 1
 	Page int `json:"page,omitempty"`
 	// PageSize 页面大小，即每页展示的数据量；默认值: 20；取值范围: 1-1000
-	PageSize int `json:"page_size,omitempty"`
+	PageSize int `json:"page_size,omitempty" validate:"max=1000"`
 	// Filtering 过滤字段，json格式，支持字段如下
 	Filtering *StatFiltering `json:"filtering,omitempty"`
 }
@@ -128,3 +129,12 @@ func (r GetRequest) Encode() string {
 	util.PutUrlValues(values)
 	return ret
 }
+
+// Validate implement model.Validator interface
+func (r GetRequest) Validate() error {
+	errs := validate.Check(r)
+	if r.AdvertiserID == 0 && r.AgentID == 0 {
+		errs = errs.Add("advertiser_id", "required", "advertiser_id 与 agent_id 不能同时为空")
+	}
+	return errs.DateRange("start_date", r.StartDate, "end_date", r.EndDate, 30).Err()
+}
@@ -8,28 +8,29 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // CustomGetRequest 自定义报表 API Request
 type CustomGetRequest struct {
 	// AdvertiserID 广告主ID
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// DateTopic 数据主题
-	DataTopic string `json:"data_topic"`
+	DataTopic string `json:"data_topic" validate:"required"`
 	// Dimensions 维度列表。获取方式：巨量引擎体验版—>报表—>新建/编辑自定义报表—>API参数生成。该字段从前端自定义报表中获取，建议不要修改。
 	Dimensions []string `json:"dimensions,omitempty"`
 	// Metrics 指标列表 。获取方式：巨量引擎体验版—>报表—>新建/编辑自定义报表—>API参数生成。该字段从前端自定义报表中获取，建议不要修改。
-	Metrics []string `json:"metrics,omitempty"`
+	Metrics []string `json:"metrics,omitempty" validate:"required"`
 	// StartTime 起始日期,格式YYYY-MM-DD,只支持查询2016-10-26及以后的日期
-	StartTime time.Time `json:"start_time,omitempty"`
+	StartTime time.Time `json:"start_time,omitempty" validate:"required"`
 	// EndTime 结束日期,格式YYYY-MM-DD,只支持查询2016-10-26及以后的日期，时间跨度不能超过30天
-	EndTime time.Time `json:"end_time,omitempty"`
+	EndTime time.Time `json:"end_time,omitempty" validate:"required"`
 	// OrderBy 排序
 	OrderBy []OrderBy `json:"order_by,omitempty"`
 	// Page 页码；默认值: 1
 	Page int `json:"page,omitempty"`
 	// PageSize 页面大小，即每页展示的数据量；默认值: 20；取值范围: 1-1000
-	PageSize int `json:"page_size,omitempty"`
+	PageSize int `json:"page_size,omitempty" validate:"max=1000"`
 	// Filters 过滤字段，json格式，支持字段如下
 	Filters []CustomGetFilter `json:"filters,omitempty"`
 }
@@ -110,6 +111,11 @@ func (r CustomGetRequest) Encode() string {
 	return ret
 }
 
+// Validate implement model.Validator interface
+func (r CustomGetRequest) Validate() error {
+	return validate.Check(r).DateRange("start_time", r.StartTime, "end_time", r.EndTime, 30).Err()
+}
+
 // CustomGetResponse 自定义数据报表 API Response
 type CustomGetResponse struct {
 	model.BaseResponse
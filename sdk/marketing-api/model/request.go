@@ -6,6 +6,8 @@ import (
 	"encoding/base64"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -26,14 +28,64 @@ type GetRequest interface {
 	Encode() string
 }
 
+// Validator 请求参数校验，SDKClient 启用校验 (SetValidation) 后在发送请求前调用，
+// 校验失败时不发出请求，直接返回错误 (通常为 validate.Errors)
+type Validator interface {
+	Validate() error
+}
+
 // UploadField multipart/form-data post request field struct
 type UploadField struct {
 	// Reader upload file reader
 	Reader io.Reader
+	// Open 打开上传文件，设置后优先于 Reader；每次调用需返回从头读取的 Reader，请求重发时会重新打开
+	Open func() (io.ReadCloser, error)
+	// Progress 上传进度回调，sent 为已发送的文件字节数，total 未知时为 -1
+	Progress func(sent int64, total int64)
 	// Key field key
 	Key string
-	// Value field value
+	// Value field value，文件字段为文件名
 	Value string
+	// SignatureKey 上传时同步计算文件 MD5 并在文件之后写入该字段，如 video_signature
+	SignatureKey string
+	// Size 文件大小；全部文件字段均设置时请求携带准确的 Content-Length，否则使用 chunked 传输
+	Size int64
+}
+
+// IsFile 是否为文件字段
+func (f UploadField) IsFile() bool {
+	return f.Reader != nil || f.Open != nil
+}
+
+// NewUploadFile 从本地文件创建上传字段，文件在上传时才打开，支持重发
+func NewUploadFile(path string) (*UploadField, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadField{
+		Value: filepath.Base(path),
+		Size:  info.Size(),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}, nil
+}
+
+// FileField 复制文件字段并设置字段名与文件名；signatureKey 非空且 signature 为空时上传时同步计算 MD5
+// 返回的字段列表中签名字段（如有）位于文件字段之前
+func FileField(f *UploadField, key string, filename string, signatureKey string, signature string) []UploadField {
+	file := *f
+	file.Key, file.Value = key, filename
+	if signatureKey == "" {
+		return []UploadField{file}
+	}
+	if signature != "" {
+		file.SignatureKey = ""
+		return []UploadField{{Key: signatureKey, Value: signature}, file}
+	}
+	file.SignatureKey = signatureKey
+	return []UploadField{file}
 }
 
 // UploadRequest multipart/form-data reqeust interface
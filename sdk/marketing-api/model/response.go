@@ -3,6 +3,7 @@ package model
 import (
 	"strconv"
 
+	"github.com/bububa/oceanengine/marketing-api/model/errcode"
 	"github.com/bububa/oceanengine/marketing-api/util"
 )
 
@@ -36,6 +37,34 @@ func (r BaseResponse) Error() string {
 	return util.StringsJoin(strconv.Itoa(r.Code), ":", r.Message)
 }
 
+// ErrorCode 返回码
+func (r BaseResponse) ErrorCode() int {
+	return r.Code
+}
+
+// ErrorMessage 返回信息
+func (r BaseResponse) ErrorMessage() string {
+	return r.Message
+}
+
+// Is 与 errcode 中的返回码比较，支持 errors.Is(err, errcode.ErrAccessTokenExpired)
+func (r BaseResponse) Is(target error) bool {
+	e, ok := target.(*errcode.Error)
+	return ok && e.Code == r.Code
+}
+
+// IsRetryable 是否为可重试的瞬时错误 (频控、系统繁忙等)
+func (r BaseResponse) IsRetryable() bool {
+	e, ok := errcode.Lookup(r.Code)
+	return ok && e.IsRetryable()
+}
+
+// IsAuthError 是否为授权失效错误
+func (r BaseResponse) IsAuthError() bool {
+	e, ok := errcode.Lookup(r.Code)
+	return ok && e.IsAuthError()
+}
+
 // APIRequestID implement Response interface
 func (r BaseResponse) APIRequestID() string {
 	return r.RequestID
@@ -15,6 +15,8 @@ type TransferCreateRequest struct {
 	// AGENT 代理
 	// LOCAL 本地推广告主
 	AccountType string `json:"account_type,omitempty"`
+	// BizRequestNo 发起转账的幂等id，相同幂等id只会转账一次，可用于查询转账单
+	BizRequestNo string `json:"biz_request_no,omitempty"`
 	// MainWalletID 大钱包id
 	MainWalletID uint64 `json:"main_wallet_id,omitempty"`
 	// TransferDirection 转账方向，以小钱包视角确定 可选值:
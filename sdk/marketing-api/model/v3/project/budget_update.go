@@ -3,24 +3,25 @@ package project
 import (
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // BudgetUpdateRequest 更新项目预算 API Request
 type BudgetUpdateRequest struct {
 	// AdvertiserID 广告主ID
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// Data 批量修改，包含广告id和出价
-	Data []BudgetUpdateData `json:"data,omitempty"`
+	Data []BudgetUpdateData `json:"data,omitempty" validate:"required,max=10"`
 }
 
 // BudgetUpdateData 修改信息
 type BudgetUpdateData struct {
 	// ProjectID 项目ID
-	ProjectID uint64 `json:"project_id,omitempty"`
+	ProjectID uint64 `json:"project_id,omitempty" validate:"required"`
 	// BudgetMode 允许值:
 	// BUDGET_MODE_DAY日预算
 	// BUDGET_MODE_INFINITE不限
-	BudgetMode enum.BudgetMode `json:"budget_mode,omitempty"`
+	BudgetMode enum.BudgetMode `json:"budget_mode,omitempty" validate:"oneof=BUDGET_MODE_DAY BUDGET_MODE_INFINITE"`
 	// Budget 预算，单位“元”，精度：两位小数。
 	Budget float64 `json:"budget,omitempty"`
 }
@@ -29,3 +30,8 @@ type BudgetUpdateData struct {
 func (r BudgetUpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
+
+// Validate implement model.Validator interface
+func (r BudgetUpdateRequest) Validate() error {
+	return validate.Struct(r)
+}
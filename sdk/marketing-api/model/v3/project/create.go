@@ -4,14 +4,15 @@ import (
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/model"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
 // CreateRequest 创建项目 API Request
 type CreateRequest struct {
 	// AdvertiserID 广告账户id
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// Operation 计划状态，允许值: ENABLE开启（默认值）,DISABLE关闭
-	Operation enum.OptStatus `json:"operation,omitempty"`
+	Operation enum.OptStatus `json:"operation,omitempty" validate:"oneof=ENABLE DISABLE"`
 	// DeliveryMode 投放模式，允许值：
 	// MANUAL手动投放(默认值）、PROCEDURAL自动投放
 	// 自动投放仅支持landing_type=APP或MICRO_GAME或LINK
@@ -19,7 +20,7 @@ type CreateRequest struct {
 	// 当marketing_goal= LIVE时，仅支持MANUAL手动投放
 	DeliveryMode enum.DeliveryMode `json:"delivery_mode,omitempty"`
 	// LandingType 推广目的，允许值：APP 应用推广、LINK 销售线索推广、MICRO_GAME 小程序、SHOP 电商店铺推广、QUICK_APP快应用、NATIVE_ACTION 原生互动、DPA商品目录
-	LandingType enum.LandingType `json:"landing_type,omitempty"`
+	LandingType enum.LandingType `json:"landing_type,omitempty" validate:"required"`
 	// AppPromotionType 子目标，当 landing_type = APP 有效且必填
 	// 允许值：DOWNLOAD 应用下载、LAUNCH 应用调用、RESERVE 预约下载
 	// 当delivery_mode = PROCEDURAL 时仅支持DOWNLOAD应用下载；
@@ -28,7 +29,7 @@ type CreateRequest struct {
 	// MarketingGoal 营销场景，允许值：VIDEO_AND_IMAGE 短视频/图片，LIVE直播,
 	// LIVE仅支持已在广告平台签署直播推广协议的账户，支持的landing_type有应用/小程序/线索/原生互动
 	// 当delivery_mode选择PROCEDURAL且landing_type选择LINK时，仅支持VIDEO_AND_IMAGE
-	MarketingGoal enum.MarketingGoal `json:"marketing_goal,omitempty"`
+	MarketingGoal enum.MarketingGoal `json:"marketing_goal,omitempty" validate:"required"`
 	// 广告类型，允许值：ALL 通投广告 SEARCH 搜索广告
 	// 当 marketing_goal= LIVE时，仅支持ALL
 	// 仅当landing_type=APP/LINk&&delivery_mode=MANUAL时支持搜索广告，否则报错
@@ -40,12 +41,12 @@ type CreateRequest struct {
 	// 当landing_type = APP 应用推广、LINK 销售线索推广、MICRO_GAME 小程序时，允许创建周期稳投搜索广告
 	DeliveryType enum.DeliveryType `json:"delivery_type,omitempty"`
 	// Name 项目名称
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" validate:"required,max=50"`
 	// SearchBidRatio 出价系数，默认系数为1，出价系数可通过【获取快投推荐出价系数】查询，小数点后最多两位,取值范围 [1,2]
 	// 当符合以下所有条件时填写有效
 	// 1. bid_type != NO_BID && pricing = PRICING_OCPM
 	// 2. deep_bid_type = DEEP_BID_DEFAULT 无深度优化方式 /BID_PER_ACTION 每次付费
-	SearchBidRatio float64 `json:"search_bid_ratio,omitempty"`
+	SearchBidRatio float64 `json:"search_bid_ratio,omitempty" validate:"min=1,max=2"`
 	// AudienceExtend 定向拓展, 允许值：ON:开启（默认值）， OFF:关闭
 	AudienceExtend enum.OnOff `json:"audience_extend,omitempty"`
 	// Keywords 搜索关键词列表
@@ -126,6 +127,11 @@ func (r CreateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
 
+// Validate implement model.Validator interface
+func (r CreateRequest) Validate() error {
+	return validate.Struct(r)
+}
+
 // CreateResponse 创建项目 API Response
 type CreateResponse struct {
 	model.BaseResponse
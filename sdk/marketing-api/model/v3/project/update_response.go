@@ -28,6 +28,17 @@ type UpdateResponseData struct {
 	ErrorKeywordList []ErrorKeyword `json:"error_keywords_list,omitempty"`
 }
 
+// Failures 按广告项目ID汇总更新失败原因，无广告项目ID的失败原因使用 0 作为 key
+func (r UpdateResponseData) Failures() map[uint64]error {
+	ret := make(map[uint64]error, len(r.ErrorList)+len(r.Errors))
+	for _, list := range [][]UpdateError{r.ErrorList, r.Errors} {
+		for _, e := range list {
+			ret[e.ProjectID] = e
+		}
+	}
+	return ret
+}
+
 // UpdateError 更新失败的广告项目
 type UpdateError struct {
 	// ProjectID 广告项目ID
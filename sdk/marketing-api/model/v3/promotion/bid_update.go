@@ -1,26 +1,41 @@
 package promotion
 
 import (
+	"fmt"
+
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
+// BidUpdateLimit 更新广告出价单次请求数据条数上限
+const BidUpdateLimit = 10
+
 // BidUpdateRequest 更新出价 API Request
 type BidUpdateRequest struct {
 	// AdvertiserID 广告主ID
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// Data 批量修改，包含广告id和出价
-	Data []BidUpdateData `json:"data,omitempty"`
+	Data []BidUpdateData `json:"data,omitempty" validate:"required"`
 }
 
 // BidUpdateData 修改信息
 type BidUpdateData struct {
 	// PromotionID 广告ID，广告ID需要属于广告主
-	PromotionID uint64 `json:"promotion_id,omitempty"`
+	PromotionID uint64 `json:"promotion_id,omitempty" validate:"required"`
 	// Bid 出价，单位“元”，精度：两位小数。
-	Bid float64 `json:"bid,omitempty"`
+	Bid float64 `json:"bid,omitempty" validate:"required"`
 }
 
 // Encode implement PostRequest interface
 func (r BidUpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
+
+// Validate implement model.Validator interface
+func (r BidUpdateRequest) Validate() error {
+	errs := validate.Check(r)
+	if len(r.Data) > BidUpdateLimit {
+		errs = errs.Add("data", "max", fmt.Sprintf("单次最多 %d 条，超出时使用 BatchBidUpdate 自动分批", BidUpdateLimit))
+	}
+	return errs.Err()
+}
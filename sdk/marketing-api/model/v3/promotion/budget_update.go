@@ -1,29 +1,44 @@
 package promotion
 
 import (
+	"fmt"
+
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
+// BudgetUpdateLimit 更新广告预算单次请求数据条数上限
+const BudgetUpdateLimit = 10
+
 // BudgetUpdateRequest 更新广告预算 API Request
 type BudgetUpdateRequest struct {
 	// AdvertiserID 广告主ID
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// Data 批量修改，包含广告id和出价
-	Data []BudgetUpdateData `json:"data,omitempty"`
+	Data []BudgetUpdateData `json:"data,omitempty" validate:"required"`
 }
 
 // BudgetUpdateData 修改信息
 type BudgetUpdateData struct {
 	// PromotionID 广告ID，广告ID需要属于广告主
-	PromotionID uint64 `json:"promotion_id,omitempty"`
+	PromotionID uint64 `json:"promotion_id,omitempty" validate:"required"`
 	// BudgetMode 该广告所属的预算类型, （预算类型不支持修改，且需要上传）。允许值: BUDGET_DAY日预算（默认值）, BUDGET_TOTAL总预算
 	BudgetMode enum.BudgetMode `json:"budget_mode,omitempty"`
 	// Budget 预算，单位“元”，精度：两位小数。
-	Budget float64 `json:"budget,omitempty"`
+	Budget float64 `json:"budget,omitempty" validate:"required"`
 }
 
 // Encode implement PostRequest interface
 func (r BudgetUpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
+
+// Validate implement model.Validator interface
+func (r BudgetUpdateRequest) Validate() error {
+	errs := validate.Check(r)
+	if len(r.Data) > BudgetUpdateLimit {
+		errs = errs.Add("data", "max", fmt.Sprintf("单次最多 %d 条，超出时使用 BatchBudgetUpdate 自动分批", BudgetUpdateLimit))
+	}
+	return errs.Err()
+}
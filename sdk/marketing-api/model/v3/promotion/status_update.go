@@ -1,26 +1,41 @@
 package promotion
 
 import (
+	"fmt"
+
 	"github.com/bububa/oceanengine/marketing-api/enum"
 	"github.com/bububa/oceanengine/marketing-api/util"
+	"github.com/bububa/oceanengine/marketing-api/util/validate"
 )
 
+// StatusUpdateLimit 更新广告状态单次请求数据条数上限
+const StatusUpdateLimit = 10
+
 // StatusUpdateRequest 更新广告状态 API Request
 type StatusUpdateRequest struct {
 	// AdvertiserID 广告主ID
-	AdvertiserID uint64 `json:"advertiser_id,omitempty"`
+	AdvertiserID uint64 `json:"advertiser_id,omitempty" validate:"required"`
 	// Data
-	Data []StatusUpdateData `json:"data,omitempty"`
+	Data []StatusUpdateData `json:"data,omitempty" validate:"required"`
 }
 
 type StatusUpdateData struct {
 	// PromotionID 广告ID
-	PromotionID uint64 `json:"promotion_id,omitempty"`
+	PromotionID uint64 `json:"promotion_id,omitempty" validate:"required"`
 	// OptStatus  操作ENABLE启 用广告、DISABLE 暂停广告
-	OptStatus enum.OptStatus `json:"opt_status,omitempty"`
+	OptStatus enum.OptStatus `json:"opt_status,omitempty" validate:"required,oneof=ENABLE DISABLE"`
 }
 
 // Encode implement PostRequest interface
 func (r StatusUpdateRequest) Encode() []byte {
 	return util.JSONMarshal(r)
 }
+
+// Validate implement model.Validator interface
+func (r StatusUpdateRequest) Validate() error {
+	errs := validate.Check(r)
+	if len(r.Data) > StatusUpdateLimit {
+		errs = errs.Add("data", "max", fmt.Sprintf("单次最多 %d 条，超出时使用 BatchStatusUpdate 自动分批", StatusUpdateLimit))
+	}
+	return errs.Err()
+}
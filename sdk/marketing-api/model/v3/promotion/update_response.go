@@ -26,6 +26,17 @@ type UpdateResponseData struct {
 	Errors []UpdateError `json:"errors,omitempty"`
 }
 
+// Failures 按广告计划ID汇总更新失败原因，无广告计划ID的失败原因使用 0 作为 key
+func (r UpdateResponseData) Failures() map[uint64]error {
+	ret := make(map[uint64]error, len(r.ErrorList)+len(r.Errors))
+	for _, list := range [][]UpdateError{r.ErrorList, r.Errors} {
+		for _, e := range list {
+			ret[e.PromotionID] = e
+		}
+	}
+	return ret
+}
+
 // UpdateError 更新失败的广告计划
 type UpdateError struct {
 	// PromotionID 广告计划ID
@@ -0,0 +1,12 @@
+// Package oetest 离线测试工具
+//
+// Server 是基于 httptest 的巨量引擎开放平台模拟服务，按接口网关路径路由，
+// 可返回预设数据或录制的 JSON 响应，校验请求头与请求体，并注入错误码与延迟：
+//
+//	srv := oetest.NewServer(t)
+//	srv.Handle("v3.0/promotion/list/").ExpectAccessToken("token").Reply(data)
+//	clt := srv.SDKClient(appID, secret)
+//	promotion.List(ctx, clt, "token", req)
+//
+// Recorder 包装真实请求并将响应保存为 Server 可加载的 JSON 文件，保存前会清除 token 等敏感字段
+package oetest
@@ -0,0 +1,104 @@
+package oetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Scrubbed 录制时敏感字段替换后的值
+const Scrubbed = "SCRUBBED"
+
+// DefaultScrubKeys 录制时默认清除的响应字段
+var DefaultScrubKeys = []string{"access_token", "refresh_token", "app_access_token", "secret"}
+
+// Recorder 录制真实请求的响应，保存为 <Dir>/<gateway>.json，可由 Server.LoadFixtures 加载
+type Recorder struct {
+	// Dir 保存目录
+	Dir string
+	// Transport 实际发送请求的 RoundTripper，为空时使用 http.DefaultTransport
+	Transport http.RoundTripper
+	// ScrubKeys 需要清除的响应字段，为空时使用 DefaultScrubKeys
+	ScrubKeys []string
+}
+
+// NewRecorder 创建录制器
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{Dir: dir}
+}
+
+// HTTPClient 返回经过录制器发送请求的 http.Client，可通过 SDKClient.SetHttpClient 设置
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implement http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := r.save(Gateway(req.URL.Path), body); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(gateway string, body []byte) error {
+	name := filepath.Join(r.Dir, filepath.FromSlash(gateway)+".json")
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, r.scrub(body), 0o644)
+}
+
+// scrub 清除敏感字段，非 JSON 响应原样返回
+func (r *Recorder) scrub(body []byte) []byte {
+	keys := r.ScrubKeys
+	if len(keys) == 0 {
+		keys = DefaultScrubKeys
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	ret, err := json.MarshalIndent(scrubValue(v, keys), "", "  ")
+	if err != nil {
+		return body
+	}
+	return append(ret, '\n')
+}
+
+func scrubValue(v any, keys []string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if slices.Contains(keys, k) {
+				val[k] = Scrubbed
+			} else {
+				val[k] = scrubValue(item, keys)
+			}
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = scrubValue(item, keys)
+		}
+	}
+	return v
+}
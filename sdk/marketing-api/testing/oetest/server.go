@@ -0,0 +1,368 @@
+package oetest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/core"
+)
+
+// RequestID 模拟服务返回的 request_id
+const RequestID = "oetest"
+
+// Request 模拟服务收到的请求
+type Request struct {
+	// Method 请求方法
+	Method string
+	// Gateway 接口网关路径，如 v3.0/promotion/list
+	Gateway string
+	// Header 请求头
+	Header http.Header
+	// Query 查询参数
+	Query url.Values
+	// Body 请求体
+	Body []byte
+}
+
+// AccessToken 请求头中的 Access-Token
+func (r *Request) AccessToken() string {
+	return r.Header.Get("Access-Token")
+}
+
+// Sandbox 是否为沙箱请求 (X-Debug-Mode)
+func (r *Request) Sandbox() bool {
+	return r.Header.Get("X-Debug-Mode") == "1"
+}
+
+// Signature 请求头中的 x-signature
+func (r *Request) Signature() string {
+	return r.Header.Get("x-signature")
+}
+
+// DecodeJSON 解析 JSON 请求体
+func (r *Request) DecodeJSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server 巨量引擎开放平台模拟服务
+type Server struct {
+	*httptest.Server
+	t        testing.TB
+	mu       sync.Mutex
+	routes   map[string]*Route
+	fixtures fs.FS
+	requests []*Request
+}
+
+// NewServer 创建并启动模拟服务，测试结束时自动关闭
+func NewServer(t testing.TB) *Server {
+	s := &Server{t: t, routes: make(map[string]*Route)}
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// LoadFixtures 加载录制的响应，未注册路由的请求返回 fsys 中 <gateway>.json 的内容
+func (s *Server) LoadFixtures(fsys fs.FS) *Server {
+	s.mu.Lock()
+	s.fixtures = fsys
+	s.mu.Unlock()
+	return s
+}
+
+// Handle 注册接口路由，gateway 为接口网关路径，首尾的 / 可省略
+func (s *Server) Handle(gateway string) *Route {
+	route := &Route{gateway: Gateway(gateway)}
+	s.mu.Lock()
+	s.routes[route.gateway] = route
+	s.mu.Unlock()
+	return route
+}
+
+// Requests 返回收到的全部请求
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// HTTPClient 返回将所有请求转发到模拟服务的 http.Client
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{target: target, next: s.Client().Transport}}
+}
+
+// SDKClient 创建请求模拟服务的 SDKClient
+func (s *Server) SDKClient(appID uint64, secret string) *core.SDKClient {
+	clt := core.NewSDKClient(appID, secret)
+	clt.SetHttpClient(s.HTTPClient())
+	return clt
+}
+
+// ServeHTTP implement http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := &Request{
+		Method:  r.Method,
+		Gateway: Gateway(r.URL.Path),
+		Header:  r.Header.Clone(),
+		Query:   r.URL.Query(),
+		Body:    body,
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	route := s.routes[req.Gateway]
+	fixtures := s.fixtures
+	s.mu.Unlock()
+
+	if route != nil {
+		route.serve(s.t, w, r, req)
+		return
+	}
+	if fixtures != nil {
+		if data, err := fs.ReadFile(fixtures, req.Gateway+".json"); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+			return
+		}
+	}
+	s.t.Errorf("oetest: unexpected request %s %s", req.Method, req.Gateway)
+	http.NotFound(w, r)
+}
+
+// Gateway 将请求路径转换为接口网关路径，去掉首尾的 / 及 open_api/ 前缀
+func Gateway(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.Trim(path, "/")
+	return strings.TrimPrefix(path, "open_api/")
+}
+
+// rewriteTransport 将请求的 scheme/host 替换为模拟服务地址
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+// RoundTrip implement http.RoundTripper
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = ""
+	return t.next.RoundTrip(r)
+}
+
+// Route 接口路由
+type Route struct {
+	mu      sync.Mutex
+	gateway string
+	method  string
+	expects []func(*Request) error
+	delay   time.Duration
+	status  int
+	code    int
+	message string
+	reply   func(*Request) any
+	raw     []byte
+	calls   int
+}
+
+// Method 校验请求方法
+func (r *Route) Method(method string) *Route {
+	r.mu.Lock()
+	r.method = method
+	r.mu.Unlock()
+	return r
+}
+
+// Expect 添加请求校验，返回 error 时测试失败
+func (r *Route) Expect(fn func(req *Request) error) *Route {
+	r.mu.Lock()
+	r.expects = append(r.expects, fn)
+	r.mu.Unlock()
+	return r
+}
+
+// ExpectHeader 校验请求头
+func (r *Route) ExpectHeader(key string, value string) *Route {
+	return r.Expect(func(req *Request) error {
+		if got := req.Header.Get(key); got != value {
+			return fmt.Errorf("header %s = %q, want %q", key, got, value)
+		}
+		return nil
+	})
+}
+
+// ExpectAccessToken 校验 Access-Token
+func (r *Route) ExpectAccessToken(token string) *Route {
+	return r.ExpectHeader("Access-Token", token)
+}
+
+// ExpectSandbox 校验沙箱请求头 X-Debug-Mode
+func (r *Route) ExpectSandbox() *Route {
+	return r.ExpectHeader("X-Debug-Mode", "1")
+}
+
+// ExpectSignature 校验 x-signature，签名为 hex(sha256(body + secret))
+func (r *Route) ExpectSignature(secret string) *Route {
+	return r.Expect(func(req *Request) error {
+		sum := sha256.Sum256(append(append([]byte(nil), req.Body...), secret...))
+		if want := hex.EncodeToString(sum[:]); req.Signature() != want {
+			return fmt.Errorf("x-signature = %q, want %q", req.Signature(), want)
+		}
+		return nil
+	})
+}
+
+// ExpectQuery 校验查询参数
+func (r *Route) ExpectQuery(key string, value string) *Route {
+	return r.Expect(func(req *Request) error {
+		if got := req.Query.Get(key); got != value {
+			return fmt.Errorf("query %s = %q, want %q", key, got, value)
+		}
+		return nil
+	})
+}
+
+// ExpectJSON 校验 JSON 请求体，与 want 序列化后的 JSON 语义相等
+func (r *Route) ExpectJSON(want any) *Route {
+	return r.Expect(func(req *Request) error {
+		wantBytes, err := json.Marshal(want)
+		if err != nil {
+			return err
+		}
+		var got, expected any
+		if err := json.Unmarshal(req.Body, &got); err != nil {
+			return fmt.Errorf("decode body: %w", err)
+		}
+		json.Unmarshal(wantBytes, &expected)
+		if !reflect.DeepEqual(got, expected) {
+			return fmt.Errorf("body = %s, want %s", req.Body, wantBytes)
+		}
+		return nil
+	})
+}
+
+// Delay 注入响应延迟
+func (r *Route) Delay(d time.Duration) *Route {
+	r.mu.Lock()
+	r.delay = d
+	r.mu.Unlock()
+	return r
+}
+
+// Reply 返回 code 为 0、data 为 data 的响应
+func (r *Route) Reply(data any) *Route {
+	return r.ReplyFunc(func(*Request) any { return data })
+}
+
+// ReplyFunc 按请求返回 data，可用于模拟翻页等
+func (r *Route) ReplyFunc(fn func(req *Request) any) *Route {
+	r.mu.Lock()
+	r.reply, r.raw = fn, nil
+	r.mu.Unlock()
+	return r
+}
+
+// ReplyRaw 原样返回响应体
+func (r *Route) ReplyRaw(body []byte) *Route {
+	r.mu.Lock()
+	r.raw, r.reply = body, nil
+	r.mu.Unlock()
+	return r
+}
+
+// ReplyFixture 返回 fsys 中 name 文件的内容
+func (r *Route) ReplyFixture(fsys fs.FS, name string) *Route {
+	body, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		panic(fmt.Sprintf("oetest: read fixture %s: %v", name, err))
+	}
+	return r.ReplyRaw(body)
+}
+
+// Fail 注入业务错误码，HTTP 状态码仍为 200
+func (r *Route) Fail(code int, message string) *Route {
+	r.mu.Lock()
+	r.code, r.message = code, message
+	r.mu.Unlock()
+	return r
+}
+
+// Status 注入 HTTP 状态码
+func (r *Route) Status(status int) *Route {
+	r.mu.Lock()
+	r.status = status
+	r.mu.Unlock()
+	return r
+}
+
+// Calls 返回路由被调用次数
+func (r *Route) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func (r *Route) serve(t testing.TB, w http.ResponseWriter, httpReq *http.Request, req *Request) {
+	r.mu.Lock()
+	r.calls++
+	method, expects, delay := r.method, r.expects, r.delay
+	status, code, message, reply, raw := r.status, r.code, r.message, r.reply, r.raw
+	r.mu.Unlock()
+
+	if method != "" && method != req.Method {
+		t.Errorf("oetest: %s method = %s, want %s", r.gateway, req.Method, method)
+	}
+	for _, fn := range expects {
+		if err := fn(req); err != nil {
+			t.Errorf("oetest: %s %v", r.gateway, err)
+		}
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-httpReq.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if status > 0 {
+		w.WriteHeader(status)
+	}
+	if raw != nil && code == 0 {
+		w.Write(raw)
+		return
+	}
+	resp := struct {
+		Code      int    `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+		Data      any    `json:"data,omitempty"`
+	}{Code: code, Message: message, RequestID: RequestID}
+	if code == 0 {
+		resp.Message = "OK"
+		if reply != nil {
+			resp.Data = reply(req)
+		}
+	}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(resp)
+	w.Write(buf.Bytes())
+}
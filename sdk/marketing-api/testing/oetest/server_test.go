@@ -0,0 +1,138 @@
+package oetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/api/conversion"
+	"github.com/bububa/oceanengine/marketing-api/api/v3/project"
+	"github.com/bububa/oceanengine/marketing-api/api/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/core"
+	"github.com/bububa/oceanengine/marketing-api/model"
+	conversionModel "github.com/bububa/oceanengine/marketing-api/model/conversion"
+	projectModel "github.com/bububa/oceanengine/marketing-api/model/v3/project"
+	promotionModel "github.com/bububa/oceanengine/marketing-api/model/v3/promotion"
+	"github.com/bububa/oceanengine/marketing-api/util/pager"
+)
+
+func TestServer_Fixtures(t *testing.T) {
+	srv := NewServer(t).LoadFixtures(os.DirFS("testdata"))
+	clt := srv.SDKClient(1, "secret")
+
+	data, err := promotion.List(context.Background(), clt, "token", &promotionModel.ListRequest{AdvertiserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.List) != 2 || data.List[0].PromotionName != "测试广告1" {
+		t.Errorf("list = %+v", data.List)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Gateway != "v3.0/promotion/list" || reqs[0].AccessToken() != "token" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	srv := NewServer(t)
+	route := srv.Handle("v3.0/project/list/").
+		Method(http.MethodGet).
+		ExpectAccessToken("token").
+		ExpectSandbox().
+		ExpectQuery("advertiser_id", "42").
+		ReplyFunc(func(req *Request) any {
+			page, _ := strconv.Atoi(req.Query.Get("page"))
+			return projectModel.ListResponseData{
+				List:     []projectModel.Project{{ProjectID: uint64(page)}},
+				PageInfo: &model.PageInfo{Page: page, TotalPage: 3},
+			}
+		})
+	clt := srv.SDKClient(1, "secret")
+	clt.UseSandbox()
+
+	list, err := pager.Collect(project.AllList(context.Background(), clt, "token", &projectModel.ListRequest{AdvertiserID: 42}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint64
+	for _, p := range list {
+		ids = append(ids, p.ProjectID)
+	}
+	if !slices.Equal(ids, []uint64{1, 2, 3}) || route.Calls() != 3 {
+		t.Errorf("ids = %v, calls = %d", ids, route.Calls())
+	}
+}
+
+func TestServer_Fail(t *testing.T) {
+	srv := NewServer(t)
+	srv.Handle("v3.0/promotion/list").Fail(40100, "请求过于频繁")
+	srv.Handle("v3.0/project/list").Delay(500 * time.Millisecond)
+	clt := srv.SDKClient(1, "secret")
+
+	_, err := promotion.List(context.Background(), clt, "token", &promotionModel.ListRequest{AdvertiserID: 1})
+	if !core.IsThrottled(err) || !strings.Contains(err.Error(), "请求过于频繁") {
+		t.Errorf("err = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = project.List(ctx, clt, "token", &projectModel.ListRequest{AdvertiserID: 1})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestServer_Signature(t *testing.T) {
+	srv := NewServer(t)
+	srv.Handle("api/v1/attribution").
+		ExpectSignature("secret").
+		ExpectJSON(map[string]any{"event_type": "active", "timestamp": 1})
+	clt := srv.SDKClient(1, "secret")
+
+	if err := conversion.Attribution(context.Background(), clt, &conversionModel.Request{EventType: "active", Timestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"message":"OK","data":{"access_token":"real-token","list":[{"refresh_token":"r","id":1}]}}`))
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	rec := NewRecorder(dir)
+	client := rec.HTTPClient()
+	resp, err := client.Get(upstream.URL + "/open_api/2/oauth2/refresh_token/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	saved, err := os.ReadFile(filepath.Join(dir, "2", "oauth2", "refresh_token.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(saved), "real-token") || strings.Count(string(saved), Scrubbed) != 2 {
+		t.Errorf("saved = %s", saved)
+	}
+
+	// 录制的响应可直接回放
+	srv := NewServer(t).LoadFixtures(os.DirFS(dir))
+	resp, err = srv.HTTPClient().Get("https://ad.oceanengine.com/open_api/2/oauth2/refresh_token/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("replay status = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,130 @@
+// Package batch 批量接口自动分批
+//
+// 批量更新类接口单次请求的数据条数有上限，且以列表形式返回每条数据的失败原因。
+// Run 将任意数量的数据按上限拆分后并发请求，并将各批次结果合并为按ID索引的 Result
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ChunkFunc 请求一批数据，返回该批次中失败的ID及原因；
+// 返回 error 表示整批请求失败，该批次所有ID均记为失败
+// 无法对应到ID的失败原因使用零值ID作为 key
+type ChunkFunc[T any, ID comparable] func(ctx context.Context, chunk []T) (failed map[ID]error, err error)
+
+// Options 分批选项
+type Options struct {
+	// Size 每批数据条数，<= 0 时使用接口默认上限
+	Size int
+	// Concurrency 并发请求数，<= 0 时为 1；请求仍受 SDKClient 限流控制
+	Concurrency int
+}
+
+// Option 分批选项
+type Option func(*Options)
+
+// WithSize 设置每批数据条数，不应超过接口上限
+func WithSize(n int) Option {
+	return func(o *Options) {
+		o.Size = n
+	}
+}
+
+// WithConcurrency 设置并发请求数
+func WithConcurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// Result 批量操作结果
+type Result[ID comparable] struct {
+	// Success 成功的ID，按输入顺序
+	Success []ID
+	// Failed 失败的ID及原因
+	Failed map[ID]error
+	// Errors 无法对应到ID的失败原因
+	Errors []error
+}
+
+// Err 合并全部失败原因，全部成功时返回 nil
+func (r *Result[ID]) Err() error {
+	errs := make([]error, 0, len(r.Failed)+len(r.Errors))
+	for _, err := range r.Failed {
+		errs = append(errs, err)
+	}
+	errs = append(errs, r.Errors...)
+	return errors.Join(errs...)
+}
+
+// Run 按 Size 拆分 items 并以 Concurrency 并发调用 fn，合并各批次结果
+// id 返回数据对应的ID；ctx 取消后未开始的批次记为失败
+func Run[T any, ID comparable](ctx context.Context, items []T, id func(T) ID, fn ChunkFunc[T, ID], defaultSize int, opts ...Option) *Result[ID] {
+	o := Options{Size: defaultSize, Concurrency: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Size <= 0 {
+		o.Size = defaultSize
+	}
+	if o.Size <= 0 {
+		o.Size = len(items)
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+
+	var zero ID
+	ret := &Result[ID]{Failed: make(map[ID]error)}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, o.Concurrency)
+	)
+	collect := func(chunk []T, failed map[ID]error, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, item := range chunk {
+			itemID := id(item)
+			if err != nil {
+				ret.Failed[itemID] = err
+			} else if e, ok := failed[itemID]; ok {
+				ret.Failed[itemID] = e
+			}
+		}
+		if e, ok := failed[zero]; ok && err == nil {
+			ret.Errors = append(ret.Errors, e)
+		}
+	}
+	for start := 0; start < len(items); start += o.Size {
+		chunk := items[start:min(start+o.Size, len(items))]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			collect(chunk, nil, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			failed, err := fn(ctx, chunk)
+			collect(chunk, failed, err)
+		}()
+	}
+	wg.Wait()
+
+	for _, item := range items {
+		if itemID := id(item); itemID != zero {
+			if _, ok := ret.Failed[itemID]; !ok {
+				ret.Success = append(ret.Success, itemID)
+			}
+		}
+	}
+	return ret
+}
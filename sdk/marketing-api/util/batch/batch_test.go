@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	items := make([]uint64, 0, 95)
+	for i := uint64(1); i <= 95; i++ {
+		items = append(items, i)
+	}
+	boom := errors.New("boom")
+	rejected := errors.New("rejected")
+
+	var (
+		mu       sync.Mutex
+		sizes    []int
+		inflight atomic.Int32
+		peak     atomic.Int32
+	)
+	ret := Run(context.Background(), items, func(id uint64) uint64 { return id }, func(ctx context.Context, chunk []uint64) (map[uint64]error, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		mu.Lock()
+		sizes = append(sizes, len(chunk))
+		mu.Unlock()
+		if chunk[0] == 41 {
+			return nil, boom
+		}
+		failed := make(map[uint64]error)
+		for _, id := range chunk {
+			if id%10 == 0 {
+				failed[id] = rejected
+			}
+		}
+		if chunk[0] == 1 {
+			failed[0] = errors.New("unknown")
+		}
+		return failed, nil
+	}, 20, WithConcurrency(3))
+
+	slices.Sort(sizes)
+	if !slices.Equal(sizes, []int{15, 20, 20, 20, 20}) {
+		t.Errorf("chunk sizes = %v", sizes)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("concurrency = %d, want <= 3", peak.Load())
+	}
+	// 41-60 整批失败，另有 10/20/30/40/70/80/90 单条失败
+	if len(ret.Failed) != 27 || len(ret.Success) != 68 {
+		t.Errorf("failed = %d, success = %d", len(ret.Failed), len(ret.Success))
+	}
+	if !errors.Is(ret.Failed[45], boom) || !errors.Is(ret.Failed[70], rejected) || !errors.Is(ret.Failed[50], boom) {
+		t.Errorf("failed reasons = %v %v %v", ret.Failed[45], ret.Failed[70], ret.Failed[50])
+	}
+	if len(ret.Errors) != 1 {
+		t.Errorf("errors = %v", ret.Errors)
+	}
+	if !slices.IsSorted(ret.Success) || ret.Success[0] != 1 {
+		t.Errorf("success not in input order: %v", ret.Success)
+	}
+	if err := ret.Err(); !errors.Is(err, boom) || !errors.Is(err, rejected) {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ret := Run(ctx, []int{1, 2, 3}, func(v int) int { return v }, func(ctx context.Context, chunk []int) (map[int]error, error) {
+		return nil, ctx.Err()
+	}, 1)
+	if len(ret.Success) != 0 || len(ret.Failed) != 3 {
+		t.Errorf("success = %v, failed = %v", ret.Success, ret.Failed)
+	}
+	if err := ret.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err() = %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+// Package pager 列表接口自动翻页
+//
+// 将按页码 (page/page_size) 或游标 (cursor/count) 分页的列表接口包装为 iter.Seq2 迭代器：
+//
+//	for promotion, err := range promotion.AllList(ctx, clt, accessToken, req) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+package pager
@@ -0,0 +1,212 @@
+package pager
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+)
+
+// PageFunc 按页码拉取一页数据，返回当页数据与分页信息
+type PageFunc[T any] func(ctx context.Context, page int) ([]T, *model.PageInfo, error)
+
+// CursorFunc 按游标拉取一页数据，返回当页数据、下一页游标及是否有下一页
+type CursorFunc[T any, C any] func(ctx context.Context, cursor C) (list []T, next C, hasMore bool, err error)
+
+// Options 翻页选项
+type Options struct {
+	// Concurrency 按页码翻页时的并发数，<= 1 时顺序拉取
+	Concurrency int
+	// MaxPages 最多拉取页数，<= 0 表示不限制
+	MaxPages int
+}
+
+// Option 翻页选项
+type Option func(*Options)
+
+// WithConcurrency 设置并发数，仅对按页码分页的接口生效
+// 首页拉取后根据 TotalPage 并发拉取剩余页，结果仍按页码顺序返回
+func WithConcurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// WithMaxPages 设置最多拉取页数
+func WithMaxPages(n int) Option {
+	return func(o *Options) {
+		o.MaxPages = n
+	}
+}
+
+func newOptions(opts []Option) *Options {
+	o := new(Options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// reachMax 是否已达到最多拉取页数
+func (o *Options) reachMax(fetched int) bool {
+	return o.MaxPages > 0 && fetched >= o.MaxPages
+}
+
+// TotalPage 根据分页信息计算总页数，无法确定时返回 0
+func TotalPage(info *model.PageInfo) int {
+	if info == nil {
+		return 0
+	}
+	if info.TotalPage > 0 {
+		return info.TotalPage
+	}
+	if info.TotalNumber > 0 && info.PageSize > 0 {
+		return int((int64(info.TotalNumber) + int64(info.PageSize) - 1) / int64(info.PageSize))
+	}
+	return 0
+}
+
+// HasNext 当前为第 page 页时是否还有下一页
+// 优先使用总页数判断，其次使用 HasMore
+func HasNext(info *model.PageInfo, page int) bool {
+	if info == nil {
+		return false
+	}
+	if total := TotalPage(info); total > 0 {
+		return page < total
+	}
+	return info.HasMore == 1
+}
+
+// Pages 按页码从第 1 页开始遍历，直至 TotalPage/HasMore 表示没有更多数据
+// 拉取失败时返回一次错误后结束
+func Pages[T any](ctx context.Context, fn PageFunc[T], opts ...Option) iter.Seq2[T, error] {
+	o := newOptions(opts)
+	return func(yield func(T, error) bool) {
+		list, info, err := fn(ctx, 1)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		if !yieldAll(list, yield) || o.reachMax(1) || !HasNext(info, 1) {
+			return
+		}
+		last := TotalPage(info)
+		if o.MaxPages > 0 && last > o.MaxPages {
+			last = o.MaxPages
+		}
+		if o.Concurrency > 1 && last > 1 {
+			concurrentPages(ctx, fn, 2, last, o.Concurrency, yield)
+			return
+		}
+		for page := 2; ; page++ {
+			list, info, err := fn(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yieldAll(list, yield) || o.reachMax(page) || !HasNext(info, page) {
+				return
+			}
+		}
+	}
+}
+
+// concurrentPages 并发拉取 [first, last] 页，按页码顺序返回
+// 最多预取 concurrency 页，调用方停止遍历时取消未完成的请求
+func concurrentPages[T any](ctx context.Context, fn PageFunc[T], first int, last int, concurrency int, yield func(T, error) bool) {
+	type result struct {
+		list []T
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	results := make([]chan result, last-first+1)
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	sem := make(chan struct{}, concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range results {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				list, _, err := fn(ctx, first+i)
+				results[i] <- result{list: list, err: err}
+			}(i)
+		}
+	}()
+
+	for i := range results {
+		var ret result
+		select {
+		case ret = <-results[i]:
+			<-sem
+		case <-ctx.Done():
+			ret.err = ctx.Err()
+		}
+		if ret.err != nil {
+			var zero T
+			yield(zero, ret.err)
+			return
+		}
+		if !yieldAll(ret.list, yield) {
+			return
+		}
+	}
+}
+
+// Cursor 按游标从 start 开始遍历，直至 hasMore 为 false
+// 拉取失败时返回一次错误后结束
+func Cursor[T any, C any](ctx context.Context, start C, fn CursorFunc[T, C], opts ...Option) iter.Seq2[T, error] {
+	o := newOptions(opts)
+	return func(yield func(T, error) bool) {
+		cursor := start
+		for fetched := 1; ; fetched++ {
+			list, next, hasMore, err := fn(ctx, cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yieldAll(list, yield) || !hasMore || o.reachMax(fetched) {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// Collect 遍历并收集全部数据，遇到错误时返回已收集的数据与错误
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var ret []T
+	for v, err := range seq {
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+func yieldAll[T any](list []T, yield func(T, error) bool) bool {
+	for _, v := range list {
+		if !yield(v, nil) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package pager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bububa/oceanengine/marketing-api/model"
+)
+
+// fakePages 模拟共 total 页、每页 size 条的列表接口
+func fakePages(total int, size int, calls *atomic.Int32) PageFunc[int] {
+	return func(ctx context.Context, page int) ([]int, *model.PageInfo, error) {
+		calls.Add(1)
+		// 后面的页先返回，验证结果仍按页码顺序
+		time.Sleep(time.Duration(total-page) * time.Millisecond)
+		list := make([]int, 0, size)
+		for i := 0; i < size; i++ {
+			list = append(list, (page-1)*size+i)
+		}
+		return list, &model.PageInfo{Page: page, PageSize: size, TotalPage: total}, nil
+	}
+}
+
+func TestPages(t *testing.T) {
+	want := make([]int, 0, 25)
+	for i := 0; i < 25; i++ {
+		want = append(want, i)
+	}
+	for _, concurrency := range []int{0, 3} {
+		var calls atomic.Int32
+		got, err := Collect(Pages(context.Background(), fakePages(5, 5, &calls), WithConcurrency(concurrency)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("concurrency %d: got %v", concurrency, got)
+		}
+		if calls.Load() != 5 {
+			t.Errorf("concurrency %d: calls = %d, want 5", concurrency, calls.Load())
+		}
+	}
+}
+
+func TestPages_HasMore(t *testing.T) {
+	fn := func(ctx context.Context, page int) ([]string, *model.PageInfo, error) {
+		hasMore := 0
+		if page < 3 {
+			hasMore = 1
+		}
+		return []string{strconv.Itoa(page)}, &model.PageInfo{HasMore: hasMore}, nil
+	}
+	got, err := Collect(Pages(context.Background(), fn, WithConcurrency(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("got %v", got)
+	}
+
+	got, _ = Collect(Pages(context.Background(), fn, WithMaxPages(2)))
+	if !slices.Equal(got, []string{"1", "2"}) {
+		t.Errorf("max pages: got %v", got)
+	}
+}
+
+func TestPages_Break(t *testing.T) {
+	var calls atomic.Int32
+	n := 0
+	for v, err := range Pages(context.Background(), fakePages(100, 10, &calls), WithConcurrency(4)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != n {
+			t.Fatalf("got %d, want %d", v, n)
+		}
+		if n++; n == 15 {
+			break
+		}
+	}
+	// 提前结束时最多预取 concurrency 页
+	if c := calls.Load(); c > 6 {
+		t.Errorf("calls = %d after break", c)
+	}
+}
+
+func TestPages_Error(t *testing.T) {
+	boom := errors.New("boom")
+	fn := func(ctx context.Context, page int) ([]int, *model.PageInfo, error) {
+		if page == 3 {
+			return nil, nil, boom
+		}
+		return []int{page}, &model.PageInfo{TotalPage: 5}, nil
+	}
+	for _, concurrency := range []int{0, 2} {
+		got, err := Collect(Pages(context.Background(), fn, WithConcurrency(concurrency)))
+		if !errors.Is(err, boom) {
+			t.Errorf("concurrency %d: err = %v", concurrency, err)
+		}
+		if !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("concurrency %d: got %v", concurrency, got)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	var cursors []string
+	fn := func(ctx context.Context, cursor string) ([]int, string, bool, error) {
+		cursors = append(cursors, cursor)
+		n, _ := strconv.Atoi(cursor)
+		return []int{n, n + 1}, strconv.Itoa(n + 2), n+2 < 6, nil
+	}
+	got, err := Collect(Cursor(context.Background(), "0", fn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("got %v", got)
+	}
+	if !slices.Equal(cursors, []string{"0", "2", "4"}) {
+		t.Errorf("cursors = %v", cursors)
+	}
+}
+
+func TestTotalPage(t *testing.T) {
+	tests := []struct {
+		info *model.PageInfo
+		want int
+	}{
+		{nil, 0},
+		{&model.PageInfo{TotalPage: 3}, 3},
+		{&model.PageInfo{TotalNumber: 21, PageSize: 10}, 3},
+		{&model.PageInfo{HasMore: 1}, 0},
+	}
+	for _, tt := range tests {
+		if got := TotalPage(tt.info); got != tt.want {
+			t.Errorf("TotalPage(%+v) = %d, want %d", tt.info, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package validate 基于 struct tag 的请求参数校验
+//
+// 校验规则写在 validate tag 中，多个规则以逗号分隔，字段名取自 json tag：
+//
+//	type StatusUpdateRequest struct {
+//		AdvertiserID uint64   `json:"advertiser_id" validate:"required"`
+//		IDs          []uint64 `json:"ids" validate:"required,max=50"`
+//		OptStatus    string   `json:"opt_status" validate:"required,oneof=ENABLE DISABLE"`
+//	}
+//
+// 支持的规则：
+//
+//	required     不能为零值，slice/map 不能为空
+//	min=N        数值不小于 N；字符串字符数、slice/map 元素个数不小于 N
+//	max=N        数值不大于 N；字符串字符数、slice/map 元素个数不大于 N
+//	len=N        字符串字符数、slice/map 元素个数等于 N
+//	oneof=A B C  取值为列出的值之一，slice 校验每个元素
+//	datetime=L   字符串按 time.Parse 的 layout L 解析，如 datetime=2006-01-02
+//
+// 除 required 外，其他规则对零值字段不生效，可选字段无需额外标注。
+// 嵌套的结构体、结构体指针及结构体 slice 会递归校验，错误字段路径形如 data[0].opt_status。
+// 无法用 tag 表达的字段间约束在请求的 Validate 方法中通过 Errors.Add 追加
+package validate
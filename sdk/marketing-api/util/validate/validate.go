@@ -0,0 +1,302 @@
+package validate
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError 字段校验错误
+type FieldError struct {
+	// Field 字段路径，如 data[0].opt_status
+	Field string `json:"field"`
+	// Rule 未通过的规则，如 required、max
+	Rule string `json:"rule"`
+	// Param 规则参数，如 max=50 中的 50
+	Param string `json:"param,omitempty"`
+	// Message 错误说明
+	Message string `json:"message"`
+}
+
+// Error implement error interface
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Errors 请求参数校验错误，包含全部未通过校验的字段
+type Errors []FieldError
+
+// Error implement error interface
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Add 追加字段错误
+func (e Errors) Add(field string, rule string, message string) Errors {
+	return append(e, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// Err 没有错误时返回 nil，避免返回非 nil 的空 Errors
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// DateRange 校验起止日期：结束日期不早于开始日期，maxDays > 0 时时间跨度不超过 maxDays 天 (含首尾)；任一日期为零值时不校验
+func (e Errors) DateRange(startField string, start time.Time, endField string, end time.Time, maxDays int) Errors {
+	if start.IsZero() || end.IsZero() {
+		return e
+	}
+	if end.Before(start) {
+		return e.Add(endField, "gtefield", "不能早于 "+startField)
+	}
+	if maxDays > 0 && end.Sub(start) >= time.Duration(maxDays)*24*time.Hour {
+		return e.Add(endField, "range", "与 "+startField+" 的时间跨度不能超过 "+strconv.Itoa(maxDays)+" 天")
+	}
+	return e
+}
+
+// Struct 按 validate tag 校验结构体，返回 Errors 或 nil
+func Struct(v any) error {
+	return Check(v).Err()
+}
+
+// Check 按 validate tag 校验结构体，返回全部字段错误，供 Validate 方法追加自定义约束
+func Check(v any) Errors {
+	var errs Errors
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	checkStruct(&errs, "", val)
+	return errs
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+type field struct {
+	index    int
+	name     string
+	rules    []rule
+	embedded bool
+}
+
+var fieldsCache sync.Map // map[reflect.Type][]field
+
+func cachedFields(typ reflect.Type) []field {
+	if f, ok := fieldsCache.Load(typ); ok {
+		return f.([]field)
+	}
+	fields := make([]field, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		f := field{index: i, name: name}
+		if name == "" {
+			f.name = sf.Name
+			f.embedded = sf.Anonymous
+		}
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			for _, r := range strings.Split(tag, ",") {
+				k, p, _ := strings.Cut(r, "=")
+				f.rules = append(f.rules, rule{name: k, param: p})
+			}
+		}
+		fields = append(fields, f)
+	}
+	f, _ := fieldsCache.LoadOrStore(typ, fields)
+	return f.([]field)
+}
+
+func checkStruct(errs *Errors, prefix string, val reflect.Value) {
+	for _, f := range cachedFields(val.Type()) {
+		fv := val.Field(f.index)
+		path := prefix
+		if !f.embedded {
+			path = join(prefix, f.name)
+		}
+		for _, r := range f.rules {
+			if msg, ok := checkRule(r, fv); !ok {
+				*errs = append(*errs, FieldError{Field: path, Rule: r.name, Param: r.param, Message: msg})
+				break
+			}
+		}
+		dive(errs, path, fv)
+	}
+}
+
+// dive 递归校验嵌套结构体
+func dive(errs *Errors, path string, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		checkStruct(errs, path, v)
+	case reflect.Slice, reflect.Array:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			dive(errs, path+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+	}
+}
+
+func join(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// checkRule 返回错误说明及是否通过
+func checkRule(r rule, v reflect.Value) (string, bool) {
+	if r.name == "required" {
+		if isZero(v) {
+			return "不能为空", false
+		}
+		return "", true
+	}
+	if isZero(v) {
+		return "", true
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch r.name {
+	case "min", "max", "len":
+		return checkRange(r, v)
+	case "oneof":
+		return checkOneOf(r, v)
+	case "datetime":
+		if v.Kind() == reflect.String {
+			if _, err := time.Parse(r.param, v.String()); err != nil {
+				return "格式必须为 " + r.param, false
+			}
+		}
+	}
+	return "", true
+}
+
+func checkRange(r rule, v reflect.Value) (string, bool) {
+	limit, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return "", true
+	}
+	var (
+		n      float64
+		prefix string
+	)
+	switch v.Kind() {
+	case reflect.String:
+		n, prefix = float64(utf8.RuneCountInString(v.String())), "长度"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, prefix = float64(v.Len()), "数量"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return "", true
+	}
+	switch r.name {
+	case "min":
+		if n < limit {
+			return prefix + "不能小于 " + r.param, false
+		}
+	case "max":
+		if n > limit {
+			return prefix + "不能大于 " + r.param, false
+		}
+	case "len":
+		if n != limit {
+			return prefix + "必须为 " + r.param, false
+		}
+	}
+	return "", true
+}
+
+func checkOneOf(r rule, v reflect.Value) (string, bool) {
+	allowed := strings.Fields(r.param)
+	var values []reflect.Value
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i))
+		}
+	} else {
+		values = append(values, v)
+	}
+	for _, item := range values {
+		s, ok := scalarString(item)
+		if !ok {
+			return "", true
+		}
+		var found bool
+		for _, a := range allowed {
+			if a == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "取值 " + s + " 无效，可选值：" + strings.Join(allowed, "/"), false
+		}
+	}
+	return "", true
+}
+
+func scalarString(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+	return "", false
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Invalid:
+		return true
+	}
+	return v.IsZero()
+}
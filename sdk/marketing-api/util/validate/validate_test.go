@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type item struct {
+	ID        uint64 `json:"id" validate:"required"`
+	OptStatus string `json:"opt_status,omitempty" validate:"required,oneof=ENABLE DISABLE"`
+}
+
+type request struct {
+	AdvertiserID uint64   `json:"advertiser_id,omitempty" validate:"required"`
+	Name         string   `json:"name,omitempty" validate:"max=5"`
+	Budget       float64  `json:"budget,omitempty" validate:"min=300"`
+	Date         string   `json:"date,omitempty" validate:"datetime=2006-01-02"`
+	Tags         []string `json:"tags,omitempty" validate:"oneof=A B"`
+	Data         []item   `json:"data,omitempty" validate:"required,max=2"`
+	Detail       *item    `json:"detail,omitempty"`
+	Ignored      string   `json:"-" validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	valid := request{AdvertiserID: 1, Name: "名称", Data: []item{{ID: 1, OptStatus: "ENABLE"}}}
+	if err := Struct(valid); err != nil {
+		t.Fatalf("Struct(valid) = %v", err)
+	}
+	if err := Struct(&valid); err != nil {
+		t.Fatalf("Struct(&valid) = %v", err)
+	}
+
+	err := Struct(request{
+		Name:   "超过五个字的名称",
+		Budget: 100,
+		Date:   "2024/01/01",
+		Tags:   []string{"A", "C"},
+		Data:   []item{{ID: 1, OptStatus: "ENABLE"}, {OptStatus: "PAUSED"}, {ID: 3, OptStatus: "ENABLE"}},
+		Detail: &item{ID: 1},
+	})
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct() = %v, want Errors", err)
+	}
+	want := []FieldError{
+		{Field: "advertiser_id", Rule: "required"},
+		{Field: "name", Rule: "max", Param: "5"},
+		{Field: "budget", Rule: "min", Param: "300"},
+		{Field: "date", Rule: "datetime", Param: "2006-01-02"},
+		{Field: "tags", Rule: "oneof", Param: "A B"},
+		{Field: "data", Rule: "max", Param: "2"},
+		{Field: "data[1].id", Rule: "required"},
+		{Field: "data[1].opt_status", Rule: "oneof", Param: "ENABLE DISABLE"},
+		{Field: "detail.opt_status", Rule: "required"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errs = %v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Field != w.Field || errs[i].Rule != w.Rule || errs[i].Param != w.Param || errs[i].Message == "" {
+			t.Errorf("errs[%d] = %+v, want %+v", i, errs[i], w)
+		}
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		start, end time.Time
+		rule       string
+	}{
+		{day(1), day(30), ""},
+		{day(1), day(31), "range"},
+		{day(2), day(1), "gtefield"},
+		{time.Time{}, day(1), ""},
+	}
+	for _, tt := range tests {
+		errs := Errors(nil).DateRange("start_date", tt.start, "end_date", tt.end, 30)
+		if tt.rule == "" {
+			if errs.Err() != nil {
+				t.Errorf("DateRange(%s, %s) = %v", tt.start, tt.end, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || errs[0].Rule != tt.rule || errs[0].Field != "end_date" {
+			t.Errorf("DateRange(%s, %s) = %v, want %s", tt.start, tt.end, errs, tt.rule)
+		}
+	}
+}